@@ -8,62 +8,225 @@ import (
 
 	"github.com/go-redis/redis/v9"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 	"go.uber.org/zap"
 )
 
 type redisReportCache struct {
-	keyPrefix string
-	rdb       redis.Cmdable
-	expenser  model.Expenser
-	reporter  model.Reporter
-	logger    *zap.Logger
+	keyPrefix   string
+	rdb         redis.Cmdable
+	expenser    model.Expenser
+	categorizer model.Categorizer
+	incomer     model.Incomer
+	reporter    model.Reporter
+	grouper     model.Grouper
+	unitOfWork  storage.UnitOfWork
+	logger      *zap.Logger
 }
 
-func NewReportCache(e model.Expenser, r model.Reporter, dsn string, l *zap.Logger) (*redisReportCache, error) {
+func NewReportCache(e model.Expenser, cat model.Categorizer, i model.Incomer, r model.Reporter, g model.Grouper, uow storage.UnitOfWork, dsn string, l *zap.Logger) (*redisReportCache, error) {
 	opts, err := redis.ParseURL(dsn)
 	if err != nil {
 		return nil, err
 	}
 
 	return &redisReportCache{
-		keyPrefix: "report",
-		rdb:       redis.NewClient(opts),
-		expenser:  e,
-		reporter:  r,
-		logger:    l,
+		keyPrefix:   "report",
+		rdb:         redis.NewClient(opts),
+		expenser:    e,
+		categorizer: cat,
+		incomer:     i,
+		reporter:    r,
+		grouper:     g,
+		unitOfWork:  uow,
+		logger:      l,
 	}, nil
 }
 
-func (c *redisReportCache) AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string) (err error) {
+func (c *redisReportCache) AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64, note string, tags []string) (id int64, err error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.AddExpense")
 	defer span.Finish()
 
 	defer func() {
 		if err == nil {
-			keyPattern := c.cacheKeyPattern(user)
-			if keys, err := c.rdb.Keys(ctx, keyPattern).Result(); err != nil {
-				c.logger.Warn("cannot get cache keys", zap.Error(err), zap.String("pattern", keyPattern))
-			} else if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
-				c.logger.Warn("cannot delete cache keys", zap.Error(err), zap.Strings("keys", keys))
-			}
+			c.invalidate(ctx, user)
 		}
 	}()
 
-	err = c.expenser.AddExpense(ctx, user, date, amount, currency, category)
+	id, err = c.expenser.AddExpense(ctx, user, date, amount, currency, category, account, note, tags)
 	return
 }
 
-func (c *redisReportCache) GetReport(ctx context.Context, user *types.User, from time.Time, currency string) (map[string]int64, error) {
+func (c *redisReportCache) AddIncome(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64) (err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.AddIncome")
+	defer span.Finish()
+
+	defer func() {
+		if err == nil {
+			c.invalidate(ctx, user)
+		}
+	}()
+
+	err = c.incomer.AddIncome(ctx, user, date, amount, currency, category, account)
+	return
+}
+
+func (c *redisReportCache) ListLastIncomes(ctx context.Context, user *types.User, count int) ([]types.Income, error) {
+	return c.incomer.ListLastIncomes(ctx, user, count)
+}
+
+func (c *redisReportCache) ListLastExpenses(ctx context.Context, user *types.User, count int) ([]types.Expense, error) {
+	return c.expenser.ListLastExpenses(ctx, user, count)
+}
+
+func (c *redisReportCache) FindExpenses(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, bool, error) {
+	return c.expenser.FindExpenses(ctx, user, filter, offset, limit)
+}
+
+func (c *redisReportCache) GetExpense(ctx context.Context, user *types.User, id int64) (types.Expense, error) {
+	return c.expenser.GetExpense(ctx, user, id)
+}
+
+func (c *redisReportCache) UpdateExpense(ctx context.Context, user *types.User, id int64, date time.Time, amount int64, currency, category string) (err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.UpdateExpense")
+	defer span.Finish()
+
+	defer func() {
+		if err == nil {
+			c.invalidate(ctx, user)
+		}
+	}()
+
+	err = c.expenser.UpdateExpense(ctx, user, id, date, amount, currency, category)
+	return
+}
+
+func (c *redisReportCache) DeleteExpense(ctx context.Context, user *types.User, id int64) (err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.DeleteExpense")
+	defer span.Finish()
+
+	defer func() {
+		if err == nil {
+			c.invalidate(ctx, user)
+		}
+	}()
+
+	err = c.expenser.DeleteExpense(ctx, user, id)
+	return
+}
+
+func (c *redisReportCache) ImportExpenses(ctx context.Context, user *types.User, items []types.ImportedExpense) (list []types.Expense, err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.ImportExpenses")
+	defer span.Finish()
+
+	defer func() {
+		if err == nil && len(list) > 0 {
+			c.invalidate(ctx, user)
+		}
+	}()
+
+	list, err = c.expenser.ImportExpenses(ctx, user, items)
+	return
+}
+
+func (c *redisReportCache) ResolveCategory(ctx context.Context, user *types.User, name string) (string, error) {
+	return c.categorizer.ResolveCategory(ctx, user, name)
+}
+
+func (c *redisReportCache) ListCategories(ctx context.Context, user *types.User) ([]types.Category, error) {
+	return c.categorizer.ListCategories(ctx, user)
+}
+
+func (c *redisReportCache) SetAlias(ctx context.Context, user *types.User, alias, category string) error {
+	return c.categorizer.SetAlias(ctx, user, alias, category)
+}
+
+func (c *redisReportCache) DeleteAlias(ctx context.Context, user *types.User, alias string) error {
+	return c.categorizer.DeleteAlias(ctx, user, alias)
+}
+
+func (c *redisReportCache) RenameCategory(ctx context.Context, user *types.User, from, to string, merge bool) (err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.RenameCategory")
+	defer span.Finish()
+
+	defer func() {
+		if err == nil {
+			c.invalidate(ctx, user)
+		}
+	}()
+
+	err = c.categorizer.RenameCategory(ctx, user, from, to, merge)
+	return
+}
+
+func (c *redisReportCache) CreateGroup(ctx context.Context, user *types.User, name string) (types.Group, error) {
+	return c.grouper.CreateGroup(ctx, user, name)
+}
+
+// JoinGroup drops the cached reports of the whole group the user has joined, as they cover the user's expenses now.
+func (c *redisReportCache) JoinGroup(ctx context.Context, user *types.User, code, name string) (group types.Group, err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.JoinGroup")
+	defer span.Finish()
+
+	before := c.members(ctx, user)
+	defer func() {
+		if err == nil {
+			c.invalidateUsers(ctx, append(before, group.Users()...))
+		}
+	}()
+
+	group, err = c.grouper.JoinGroup(ctx, user, code, name)
+	return
+}
+
+// LeaveGroup drops the cached reports of the members of the group the user has left.
+func (c *redisReportCache) LeaveGroup(ctx context.Context, user *types.User) (err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.LeaveGroup")
+	defer span.Finish()
+
+	before := c.members(ctx, user)
+	defer func() {
+		if err == nil {
+			c.invalidateUsers(ctx, append(before, c.members(ctx, user)...))
+		}
+	}()
+
+	err = c.grouper.LeaveGroup(ctx, user)
+	return
+}
+
+// RemoveMember drops the cached reports of the members of the group the member is removed from, including the member.
+func (c *redisReportCache) RemoveMember(ctx context.Context, owner *types.User, name string) (err error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.RemoveMember")
+	defer span.Finish()
+
+	before := c.members(ctx, owner)
+	defer func() {
+		if err == nil {
+			c.invalidateUsers(ctx, append(before, c.members(ctx, owner)...))
+		}
+	}()
+
+	err = c.grouper.RemoveMember(ctx, owner, name)
+	return
+}
+
+func (c *redisReportCache) GetGroup(ctx context.Context, user *types.User) (types.Group, error) {
+	return c.grouper.GetGroup(ctx, user)
+}
+
+func (c *redisReportCache) GetReport(ctx context.Context, user *types.User, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange, currency string) (types.ReportData, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "redisReportCache.GetReport")
 	defer span.Finish()
 
-	key := c.cacheKey(user, currency, from)
+	key := c.cacheKey(user, currency, from, to, grouping, compare)
 
 	cache, err := c.rdb.Get(ctx, key).Result()
 	if err == nil {
-		var data map[string]int64
+		var data types.ReportData
 		if err = json.Unmarshal([]byte(cache), &data); err != nil {
 			c.logger.Error("cannot unmarshal cached report", zap.Error(err), zap.String("key", key))
 		} else {
@@ -73,7 +236,7 @@ func (c *redisReportCache) GetReport(ctx context.Context, user *types.User, from
 		c.logger.Warn("cannot get report from cache", zap.Error(err), zap.String("key", key))
 	}
 
-	data, err := c.reporter.GetReport(ctx, user, from, currency)
+	data, err := c.reporter.GetReport(ctx, user, from, to, grouping, compare, currency)
 	if err == nil {
 		if bytes, err := json.Marshal(data); err != nil {
 			c.logger.Error("cannot marshal report", zap.Error(err))
@@ -85,8 +248,61 @@ func (c *redisReportCache) GetReport(ctx context.Context, user *types.User, from
 	return data, err
 }
 
-func (c *redisReportCache) cacheKey(user *types.User, currency string, date time.Time) string {
-	return fmt.Sprintf("%s_%s_%s_%s", c.keyPrefix, user, currency, date)
+// invalidate drops the cached reports of the user, and of the other members of the user's group
+// as their reports cover the user's expenses too.
+func (c *redisReportCache) invalidate(ctx context.Context, user *types.User) {
+	c.invalidateUsers(ctx, c.members(ctx, user))
+}
+
+// members returns the members of the user's group, or the user alone when not in a group.
+func (c *redisReportCache) members(ctx context.Context, user *types.User) []*types.User {
+	group, err := c.grouper.GetGroup(ctx, user)
+	if err == nil {
+		return group.Users()
+	}
+
+	if !errors.Is(err, model.ErrNotFound) {
+		c.logger.Warn("cannot get user group", zap.Error(err), zap.Stringer("user", user))
+	}
+
+	return []*types.User{user}
+}
+
+// invalidateUsers drops the cached reports of the users once the changes are committed, so that reports
+// made in the meantime from the data being changed don't stay cached. The same user may be given several times.
+func (c *redisReportCache) invalidateUsers(ctx context.Context, users []*types.User) {
+	c.unitOfWork.AfterCommit(ctx, func(ctx context.Context) {
+		c.deleteReports(ctx, users)
+	})
+}
+
+// deleteReports drops the cached reports of the users.
+func (c *redisReportCache) deleteReports(ctx context.Context, users []*types.User) {
+	seen := make(map[types.User]bool, len(users))
+	for _, user := range users {
+		if seen[*user] {
+			continue
+		}
+		seen[*user] = true
+
+		keyPattern := c.cacheKeyPattern(user)
+		if keys, err := c.rdb.Keys(ctx, keyPattern).Result(); err != nil {
+			c.logger.Warn("cannot get cache keys", zap.Error(err), zap.String("pattern", keyPattern))
+		} else if len(keys) == 0 {
+			continue
+		} else if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+			c.logger.Warn("cannot delete cache keys", zap.Error(err), zap.Strings("keys", keys))
+		}
+	}
+}
+
+func (c *redisReportCache) cacheKey(user *types.User, currency string, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange) string {
+	key := fmt.Sprintf("%s_%s_%s_%s_%s_%s", c.keyPrefix, user, currency, from.Format("2006-01-02"), to.Format("2006-01-02"), grouping)
+	if !compare.IsZero() {
+		key += fmt.Sprintf("_%s_%s", compare.From.Format("2006-01-02"), compare.To.Format("2006-01-02"))
+	}
+
+	return key
 }
 
 func (c *redisReportCache) cacheKeyPattern(user *types.User) string {
@@ -0,0 +1,87 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	_barsWidth   = 720
+	_barsHeight  = 400
+	_barsPadding = 12
+	_barsAxis    = 64 // width of the value axis labels
+	_barsLegend  = 24 // height of the bar labels
+	_barsGrid    = 4  // number of horizontal grid lines
+)
+
+// Bars renders values as a bar chart. Values are divided by scale for the axis labels, and bar
+// labels are shown as far as they fit (they must be ASCII, as the chart uses a basic font).
+func Bars(w io.Writer, values []Value, scale int64) error {
+	var max int64
+	for _, value := range values {
+		if value.Value > max {
+			max = value.Value
+		}
+	}
+
+	if len(values) == 0 || max <= 0 {
+		return ErrNoData
+	}
+
+	img := newCanvas(_barsWidth, _barsHeight)
+	plot := image.Rect(_barsAxis, _barsPadding, _barsWidth-_barsPadding, _barsHeight-_barsLegend)
+
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{Dst: img, Src: image.NewUniform(_foreground), Face: face}
+
+	for i := 0; i <= _barsGrid; i++ {
+		y := plot.Max.Y - plot.Dy()*i/_barsGrid
+		draw.Draw(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), image.NewUniform(_grid), image.Point{}, draw.Src)
+
+		label := fmt.Sprintf("%.0f", float64(max)*float64(i)/_barsGrid/float64(scale))
+		drawer.Dot = fixed.P(plot.Min.X-8-font.MeasureString(face, label).Round(), y+face.Ascent/2)
+		drawer.DrawString(label)
+	}
+
+	step := float64(plot.Dx()) / float64(len(values))
+	labelEvery := 1
+	for widest := maxLabelWidth(face, values) + 8; float64(labelEvery)*step < float64(widest); {
+		labelEvery++
+	}
+
+	for i, value := range values {
+		left := plot.Min.X + int(float64(i)*step+step*0.15)
+		right := plot.Min.X + int(float64(i+1)*step-step*0.15)
+		if right <= left {
+			right = left + 1
+		}
+
+		top := plot.Max.Y - int(float64(plot.Dy())*float64(value.Value)/float64(max))
+		draw.Draw(img, image.Rect(left, top, right, plot.Max.Y), image.NewUniform(Palette[4]), image.Point{}, draw.Src)
+
+		if i%labelEvery == 0 {
+			center := plot.Min.X + int(float64(i)*step+step/2)
+			drawer.Dot = fixed.P(center-font.MeasureString(face, value.Label).Round()/2, plot.Max.Y+face.Ascent+6)
+			drawer.DrawString(value.Label)
+		}
+	}
+
+	return encode(w, img)
+}
+
+func maxLabelWidth(face font.Face, values []Value) int {
+	var max int
+	for _, value := range values {
+		if width := font.MeasureString(face, value.Label).Round(); width > max {
+			max = width
+		}
+	}
+
+	return max
+}
@@ -0,0 +1,82 @@
+// Package chart renders report data as PNG images without any external services.
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Palette holds colors of pie slices in order. The last color is used for the slice
+// aggregating the rest of the values (see Shares).
+var Palette = []color.RGBA{
+	{R: 0xe5, G: 0x39, B: 0x35, A: 0xff}, // red
+	{R: 0xfb, G: 0x8c, B: 0x00, A: 0xff}, // orange
+	{R: 0xfd, G: 0xd8, B: 0x35, A: 0xff}, // yellow
+	{R: 0x43, G: 0xa0, B: 0x47, A: 0xff}, // green
+	{R: 0x1e, G: 0x88, B: 0xe5, A: 0xff}, // blue
+	{R: 0x8e, G: 0x24, B: 0xaa, A: 0xff}, // purple
+	{R: 0x6d, G: 0x4c, B: 0x41, A: 0xff}, // brown
+	{R: 0x42, G: 0x42, B: 0x42, A: 0xff}, // black
+}
+
+var (
+	_background = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	_foreground = color.RGBA{R: 0x21, G: 0x21, B: 0x21, A: 0xff}
+	_grid       = color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}
+
+	ErrNoData = errors.New("no data to render")
+)
+
+// Value is a labeled value shown as a pie slice or a bar.
+type Value struct {
+	Label string
+	Value int64
+}
+
+// Shares converts data into values sorted in descending order. When there are more values
+// than colors in the Palette, the smallest of them are summed up into the last value labeled
+// with restLabel.
+func Shares(data map[string]int64, restLabel string) []Value {
+	values := make([]Value, 0, len(data))
+	for label, value := range data {
+		if value > 0 {
+			values = append(values, Value{Label: label, Value: value})
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Value == values[j].Value {
+			return values[i].Label < values[j].Label
+		}
+
+		return values[i].Value > values[j].Value
+	})
+
+	if len(values) <= len(Palette) {
+		return values
+	}
+
+	rest := Value{Label: restLabel}
+	for _, value := range values[len(Palette)-1:] {
+		rest.Value += value.Value
+	}
+
+	return append(values[:len(Palette)-1], rest)
+}
+
+func newCanvas(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: _background}, image.Point{}, draw.Src)
+
+	return img
+}
+
+func encode(w io.Writer, img image.Image) error {
+	return errors.Wrap(png.Encode(w, img), "encode png")
+}
@@ -0,0 +1,98 @@
+//go:build unit
+
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Shares(t *testing.T) {
+	t.Run("sorted", func(t *testing.T) {
+		// ACT
+		shares := Shares(map[string]int64{
+			"coffee": 100,
+			"taxi":   300,
+			"books":  100,
+			"gifts":  0,
+		}, "rest")
+
+		// ASSERT
+		assert.Equal(t, []Value{
+			{Label: "taxi", Value: 300},
+			{Label: "books", Value: 100},
+			{Label: "coffee", Value: 100},
+		}, shares)
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		// ACT
+		shares := Shares(map[string]int64{
+			"a": 900, "b": 800, "c": 700, "d": 600, "e": 500, "f": 400, "g": 300, "h": 200, "i": 100,
+		}, "rest")
+
+		// ASSERT
+		assert.Len(t, shares, len(Palette))
+		assert.Equal(t, Value{Label: "rest", Value: 300}, shares[len(shares)-1])
+	})
+}
+
+func Test_Pie(t *testing.T) {
+	t.Run("no data", func(t *testing.T) {
+		// ACT
+		err := Pie(new(bytes.Buffer), []Value{{Label: "taxi"}})
+
+		// ASSERT
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		// ARRANGE
+		buf := new(bytes.Buffer)
+
+		// ACT
+		err := Pie(buf, []Value{{Label: "taxi", Value: 3}, {Label: "coffee", Value: 1}})
+		img, decodeErr := png.Decode(buf)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, decodeErr)
+		assert.Equal(t, _pieSize, img.Bounds().Dx())
+		assert.Equal(t, Palette[0], img.At(_pieSize*3/4, _pieSize/2), "right half is the first slice")
+		assert.Equal(t, Palette[1], img.At(_pieSize/4, _pieSize/4), "top left quarter is the second slice")
+		assert.Equal(t, _background, img.At(0, 0))
+	})
+}
+
+func Test_Bars(t *testing.T) {
+	t.Run("no data", func(t *testing.T) {
+		// ACT
+		err := Bars(new(bytes.Buffer), nil, 1)
+
+		// ASSERT
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+
+	t.Run("bars", func(t *testing.T) {
+		// ARRANGE
+		buf := new(bytes.Buffer)
+
+		// ACT
+		err := Bars(buf, []Value{{Label: "09.2022", Value: 10}, {Label: "10.2022", Value: 0}}, 1)
+		img, decodeErr := png.Decode(buf)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, decodeErr)
+		assert.Equal(t, _barsWidth, img.Bounds().Dx())
+		assert.Equal(t, _barsHeight, img.Bounds().Dy())
+
+		plotWidth := _barsWidth - _barsPadding - _barsAxis
+		middle := (_barsHeight-_barsLegend+_barsPadding)/2 + 5 // off the grid line
+		assert.Equal(t, Palette[4], img.At(_barsAxis+plotWidth/4, middle), "the first bar is full height")
+		assert.Equal(t, _background, img.At(_barsAxis+plotWidth*3/4, middle), "the second bar is empty")
+	})
+}
@@ -0,0 +1,61 @@
+package chart
+
+import (
+	"image/color"
+	"io"
+	"math"
+)
+
+const _pieSize = 480
+
+// Pie renders values as a pie chart colored with the Palette, starting from 12 o'clock clockwise.
+func Pie(w io.Writer, values []Value) error {
+	var total int64
+	for _, value := range values {
+		total += value.Value
+	}
+
+	if total <= 0 {
+		return ErrNoData
+	}
+
+	// bounds of every slice as a fraction of the full turn
+	bounds := make([]float64, len(values))
+	var sum int64
+	for i, value := range values {
+		sum += value.Value
+		bounds[i] = float64(sum) / float64(total)
+	}
+
+	img := newCanvas(_pieSize, _pieSize)
+	center := float64(_pieSize) / 2
+	radius := center - 16
+
+	for y := 0; y < _pieSize; y++ {
+		for x := 0; x < _pieSize; x++ {
+			dx, dy := float64(x)+0.5-center, float64(y)+0.5-center
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+
+			turn := math.Atan2(dx, -dy) / (2 * math.Pi)
+			if turn < 0 {
+				turn++
+			}
+
+			img.Set(x, y, sliceColor(bounds, turn))
+		}
+	}
+
+	return encode(w, img)
+}
+
+func sliceColor(bounds []float64, turn float64) color.Color {
+	for i, bound := range bounds {
+		if turn < bound {
+			return Palette[i%len(Palette)]
+		}
+	}
+
+	return Palette[(len(bounds)-1)%len(Palette)]
+}
@@ -0,0 +1,186 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+)
+
+var (
+	// _accountSelectorRx matches the #account selector of /income, /add takes accounts among its tags.
+	_accountSelectorRx = regexp.MustCompile(`(?:^|\s)#(\S+)`)
+	_accountAddRx      = regexp.MustCompile(`^(?:add|добавить)\s+#?(\S+)(?:\s+([A-Z]{3}))?(?:\s+(-?\d+(?:[.,]\d+)?))?$`)
+	_transferRx        = regexp.MustCompile(`^#?(\S+)\s+#?(\S+)\s+(\d+(?:[.,]\d+)?)(?:\s+(\d+(?:[.,]\d+)?))?$`)
+)
+
+// cutAccountSelector cuts the first #account selector out of the command arguments.
+func cutAccountSelector(args string) (string, string) {
+	m := _accountSelectorRx.FindStringSubmatchIndex(args)
+	if m == nil {
+		return args, ""
+	}
+
+	return strings.TrimSpace(args[:m[0]] + args[m[1]:]), args[m[2]:m[3]]
+}
+
+// handleAccount lists the accounts or adds a new one.
+func (c *client) handleAccount(ctx context.Context, user *types.User, args string) string {
+	if args != "" {
+		return c.handleAddAccount(ctx, user, args)
+	}
+
+	resp := c.controller.ListAccounts(ctx, request.ListAccounts{
+		User: user,
+	})
+
+	switch {
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+
+	case len(resp.List) == 0:
+		return tr(ctx, accountEmptyMessage) + "\n\n" + tr(ctx, accountHelpMessage)
+	}
+
+	text := tr(ctx, "Счета:")
+	for _, account := range resp.List {
+		text += fmt.Sprintf("\n#%s — %s", html.EscapeString(account.Name), account.Currency)
+	}
+
+	return text
+}
+
+func (c *client) handleAddAccount(ctx context.Context, user *types.User, args string) string {
+	m := _accountAddRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось добавить счёт.", accountHelpMessage)
+	}
+
+	var opening int64
+	if m[3] != "" {
+		var err error
+		if opening, err = parseAmount(m[3]); err != nil {
+			return errorMessage(ctx, err, "Не удалось добавить счёт.", accountHelpMessage)
+		}
+	}
+
+	resp := c.controller.AddAccount(ctx, request.AddAccount{
+		User:     user,
+		Name:     m[1],
+		Currency: m[2],
+		Opening:  opening,
+	})
+
+	switch {
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось добавить счёт.", accountHelpMessage)
+
+	case resp.Duplicate:
+		return tr(ctx, accountDuplicateMessage)
+
+	case resp.Invalid != nil:
+		return errorMessage(ctx, explainInputError(resp.Invalid), "Не удалось добавить счёт.", accountHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+// handleBalance shows balances of the accounts in their currencies and converted into the current one.
+func (c *client) handleBalance(ctx context.Context, user *types.User, _ string) string {
+	resp := c.controller.GetBalance(ctx, request.GetBalance{
+		User: user,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+
+	case len(resp.List) == 0:
+		return tr(ctx, accountEmptyMessage) + "\n\n" + tr(ctx, accountHelpMessage)
+	}
+
+	var total int64
+	text := trf(ctx, "Балансы счетов (валюта — %s):", resp.Currency)
+	for _, balance := range resp.List {
+		text += fmt.Sprintf("\n#%s: %.2f %s", html.EscapeString(balance.Name), float64(balance.Balance)/10000, balance.Currency)
+		if balance.Currency != resp.Currency {
+			text += fmt.Sprintf(" ≈ %.2f %s", float64(balance.Converted)/10000, resp.Currency)
+		}
+
+		total += balance.Converted
+	}
+	text += "\n\n" + trf(ctx, "Всего: %.2f %s", float64(total)/10000, resp.Currency)
+
+	return text
+}
+
+// handleTransfer moves money between two accounts, the received amount is exchanged at today's rate
+// unless it's given explicitly.
+func (c *client) handleTransfer(ctx context.Context, user *types.User, args string) string {
+	m := _transferRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось выполнить перевод.", transferHelpMessage)
+	}
+
+	amount, err := parseAmount(m[3])
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось выполнить перевод.", transferHelpMessage)
+	}
+
+	var received int64
+	if m[4] != "" {
+		if received, err = parseAmount(m[4]); err != nil {
+			return errorMessage(ctx, err, "Не удалось выполнить перевод.", transferHelpMessage)
+		}
+	}
+
+	if strings.EqualFold(m[1], m[2]) || amount == 0 {
+		return errorMessage(ctx, errWrongTransfer, "Не удалось выполнить перевод.", transferHelpMessage)
+	}
+
+	resp := c.controller.Transfer(ctx, request.Transfer{
+		User:     user,
+		Date:     utils.TruncateToDate(time.Now()),
+		From:     m[1],
+		To:       m[2],
+		Amount:   amount,
+		Received: received,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage)
+
+	case resp.NotFound:
+		return errorMessage(ctx, errUnknownAccount, "Не удалось выполнить перевод.", accountHelpMessage)
+
+	case resp.Invalid != nil:
+		return errorMessage(ctx, explainInputError(resp.Invalid), "Не удалось выполнить перевод.", transferHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return trf(
+		ctx,
+		"Готово! С #%s списано %.2f %s, на #%s зачислено %.2f %s.",
+		html.EscapeString(resp.From.Name),
+		float64(amount)/10000,
+		resp.From.Currency,
+		html.EscapeString(resp.To.Name),
+		float64(resp.Received)/10000,
+		resp.To.Currency,
+	)
+}
@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+// confirmation is the message confirming the added expense, photos sent in reply to it are attached to the expense.
+type confirmation struct {
+	MessageID int   `json:"message_id"`
+	ExpenseID int64 `json:"expense_id"`
+}
+
+// handleAttachment handles photos captioned with /add and photos sent in reply to the last expense confirmation.
+// It returns the expense the further photos are attached to, or the keyboard to confirm the expense guessed
+// from the caption, and reports false when the message is neither.
+func (c *client) handleAttachment(ctx context.Context, user *types.User, message *tgbotapi.Message) (string, [][][]string, added, bool) {
+	if len(message.Photo) == 0 {
+		return "", nil, added{}, false
+	}
+
+	if args, ok := addCaptionArgs(message.Caption); ok {
+		text, keyboard, a := c.handleAdd(ctx, user, args, message.Photo)
+		return text, keyboard, a, true
+	}
+
+	if message.ReplyToMessage == nil {
+		return "", nil, added{}, false
+	}
+
+	var last confirmation
+	if !c.loadState(ctx, user, _stateConfirmation, &last) || last.MessageID != message.ReplyToMessage.MessageID {
+		return "", nil, added{}, false
+	}
+
+	// the attachment itself can't be undone
+	return c.attachPhoto(ctx, user, last.ExpenseID, message.Photo), nil, added{expenseID: last.ExpenseID}, true
+}
+
+// attachPhoto attaches the largest size of the photo to the expense.
+func (c *client) attachPhoto(ctx context.Context, user *types.User, expenseID int64, sizes []tgbotapi.PhotoSize) string {
+	largest := sizes[len(sizes)-1]
+	if largest.FileSize > c.maxAttachmentSize {
+		return trf(ctx, attachmentTooLargeMessage, c.maxAttachmentSize>>10)
+	}
+
+	data, err := c.downloadFile(ctx, largest.FileID, c.maxAttachmentSize)
+	if err != nil {
+		c.logger.Error("cannot download attachment", zap.Error(err))
+		return tr(ctx, attachmentRetry)
+	}
+
+	resp := c.controller.AttachToExpense(ctx, request.AttachToExpense{
+		User:      user,
+		ExpenseID: expenseID,
+		Data:      data,
+	})
+
+	switch {
+	case resp.NotFound:
+		return tr(ctx, expenseNotFoundMessage)
+
+	case resp.TooLarge:
+		return trf(ctx, attachmentTooLargeMessage, c.maxAttachmentSize>>10)
+
+	case !resp.Success:
+		return tr(ctx, attachmentRetry)
+	}
+
+	return tr(ctx, attachmentDoneMessage)
+}
+
+func (c *client) handleAttachmentCallback(ctx context.Context, user *types.User, args string) (string, []photo) {
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		return errorMessage(ctx, errWrongExpenseID, "Не удалось получить фото.", editHelpMessage), nil
+	}
+
+	resp := c.controller.GetAttachment(ctx, request.GetAttachment{
+		User:      user,
+		ExpenseID: id,
+	})
+
+	switch {
+	case resp.NotFound:
+		return tr(ctx, attachmentNotFoundMessage), nil
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), nil
+	}
+
+	return "", []photo{{
+		name: fmt.Sprintf("expense-%d.jpg", id),
+		data: resp.Data,
+	}}
+}
+
+// sendConfirmation sends the result of adding the expense with the button to undo it and remembers the message,
+// so that a photo sent in reply to it is attached to the expense.
+func (c *client) sendConfirmation(ctx context.Context, chatID int64, user *types.User, text string, a added) {
+	message := tgbotapi.NewMessage(chatID, text)
+	message.ParseMode = tgbotapi.ModeHTML
+	if keyboard := undoKeyboard(ctx, text, a.operation); len(keyboard) != 0 {
+		message.ReplyMarkup = c.inlineKeyboard(keyboard)
+	}
+
+	sent, err := c.api.Send(message)
+	if err != nil {
+		c.logger.Error("cannot send telegram message", zap.Error(err))
+		return
+	}
+
+	if a.expenseID != 0 {
+		c.saveState(ctx, user, _stateConfirmation, confirmation{
+			MessageID: sent.MessageID,
+			ExpenseID: a.expenseID,
+		})
+	}
+}
+
+// addCaptionArgs returns the arguments of the /add command given as a caption of the photo, Telegram doesn't
+// parse commands in captions. It reports false when the caption is not the command.
+func addCaptionArgs(caption string) (string, bool) {
+	command, args, _ := strings.Cut(strings.TrimSpace(caption), " ")
+
+	command, _, _ = strings.Cut(command, "@")
+	if command != "/add" {
+		return "", false
+	}
+
+	return strings.TrimSpace(args), true
+}
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+var _categoriesRx = regexp.MustCompile(`^(alias|синоним|rename|переименовать|merge|объединить)\s+(.+?)\s*=\s*(.*)$`)
+
+// handleCategories lists the categories or changes them: sets an alias, renames or merges a category.
+func (c *client) handleCategories(ctx context.Context, user *types.User, args string) string {
+	if args == "" {
+		resp := c.controller.ListCategories(ctx, request.ListCategories{
+			User: user,
+		})
+
+		switch {
+		case !resp.Success:
+			return tr(ctx, emergencyMessage)
+
+		case len(resp.List) == 0:
+			return tr(ctx, categoriesEmptyMessage) + "\n\n" + tr(ctx, categoriesHelpMessage)
+		}
+
+		return tr(ctx, "Категории:") + "\n" + renderCategories(resp.List)
+	}
+
+	m := _categoriesRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось изменить категории.", categoriesHelpMessage)
+	}
+
+	switch m[1] {
+	case "alias", "синоним":
+		return c.handleCategoryAlias(ctx, user, m[2], m[3])
+
+	default:
+		return c.handleRenameCategory(ctx, user, m[2], m[3], m[1] == "merge" || m[1] == "объединить")
+	}
+}
+
+func (c *client) handleCategoryAlias(ctx context.Context, user *types.User, alias, category string) string {
+	resp := c.controller.SetCategoryAlias(ctx, request.SetCategoryAlias{
+		User:     user,
+		Alias:    alias,
+		Category: category,
+	})
+
+	switch {
+	case resp.Invalid:
+		return errorMessage(ctx, errWrongAlias, "Не удалось задать синоним.", categoriesHelpMessage)
+
+	case resp.NotFound:
+		return errorMessage(ctx, errUnknownAlias, "Не удалось удалить синоним.", categoriesHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+func (c *client) handleRenameCategory(ctx context.Context, user *types.User, from, to string, merge bool) string {
+	resp := c.controller.RenameCategory(ctx, request.RenameCategory{
+		User:  user,
+		From:  from,
+		To:    to,
+		Merge: merge,
+	})
+
+	switch {
+	case resp.Invalid:
+		return errorMessage(ctx, errWrongCategoryRename, "Не удалось переименовать категорию.", categoriesHelpMessage)
+
+	case resp.NotFound:
+		return errorMessage(ctx, errUnknownCategory, "Не удалось переименовать категорию.", categoriesHelpMessage)
+
+	case resp.AlreadyExists:
+		return errorMessage(ctx, errCategoryExists, "Не удалось переименовать категорию.", categoriesHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+// renderCategories renders the sorted categories as a tree indenting subcategories under their parents,
+// the parents having no expenses of their own are shown as well.
+func renderCategories(list []types.Category) string {
+	var (
+		lines   []string
+		printed = make(map[string]bool)
+	)
+	for _, category := range list {
+		segments := strings.Split(category.Name, "/")
+		for depth := range segments {
+			path := strings.Join(segments[:depth+1], "/")
+			if printed[path] {
+				continue
+			}
+			printed[path] = true
+
+			line := strings.Repeat("    ", depth) + html.EscapeString(segments[depth])
+			if path == category.Name && len(category.Aliases) > 0 {
+				line += " <i>(" + html.EscapeString(strings.Join(category.Aliases, ", ")) + ")</i>"
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,151 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/i18n"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+var (
+	// _splitRx matches the list of group members closing the arguments of /add.
+	_splitRx  = regexp.MustCompile(`(?:^|\s+)(?:split|разделить)((?:\s+\S+)+)$`)
+	_settleRx = regexp.MustCompile(`^(\S+)(?:\s+([A-Z]{3}))?$`)
+)
+
+// cutSplit cuts the names of the group members to split the expense with out of the command arguments.
+func cutSplit(args string) (string, []string) {
+	m := _splitRx.FindStringSubmatchIndex(args)
+	if m == nil {
+		return args, nil
+	}
+
+	return strings.TrimSpace(args[:m[0]]), strings.Fields(args[m[2]:m[3]])
+}
+
+// handleDebts shows the net debts between the user and other group members.
+func (c *client) handleDebts(ctx context.Context, user *types.User, _ string) string {
+	resp := c.controller.ListDebts(ctx, request.ListDebts{
+		User: user,
+	})
+
+	switch {
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+
+	case len(resp.List) == 0:
+		return tr(ctx, debtsEmptyMessage) + "\n\n" + tr(ctx, debtsHelpMessage)
+	}
+
+	var owed, owing []string
+	for _, balance := range resp.List {
+		if balance.Amount > 0 {
+			owed = append(owed, fmt.Sprintf("%s: %.2f %s", html.EscapeString(balance.Name), float64(balance.Amount)/10000, balance.Currency))
+		} else {
+			owing = append(owing, fmt.Sprintf("%s: %.2f %s", html.EscapeString(balance.Name), float64(-balance.Amount)/10000, balance.Currency))
+		}
+	}
+
+	var sections []string
+	if len(owed) > 0 {
+		sections = append(sections, tr(ctx, "Тебе должны:")+"\n"+strings.Join(owed, "\n"))
+	}
+	if len(owing) > 0 {
+		sections = append(sections, tr(ctx, "Ты должен:")+"\n"+strings.Join(owing, "\n"))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// handleSettle closes the debts between the user and the counterparty, and lets the counterparty know.
+// The name identifies the user in the notification.
+func (c *client) handleSettle(ctx context.Context, user *types.User, name, args string) string {
+	m := _settleRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось закрыть долг.", debtsHelpMessage)
+	}
+
+	resp := c.controller.SettleDebts(ctx, request.SettleDebts{
+		User:     user,
+		Name:     m[1],
+		Currency: m[2],
+	})
+
+	switch {
+	case resp.NotFound:
+		return errorMessage(ctx, errUnknownDebt, "Не удалось закрыть долг.", debtsHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	text := tr(ctx, "Готово! Закрыты долги:")
+	for _, balance := range resp.Settled {
+		balance := balance
+		text += "\n" + renderDebtBalance(ctx, balance, balance.Name)
+		c.notify(ctx, balance.Counterparty, func(ctx context.Context) string {
+			return trf(
+				ctx,
+				"%s отметил закрытым долг между вами: %s",
+				html.EscapeString(name),
+				renderDebtBalance(ctx, types.DebtBalance{Amount: -balance.Amount, Currency: balance.Currency}, name),
+			)
+		})
+	}
+
+	return text
+}
+
+// notifyDebtors lets the group members know about their shares of the split expense.
+func (c *client) notifyDebtors(ctx context.Context, debts []types.Debt) {
+	for _, debt := range debts {
+		debt := debt
+		c.notify(ctx, debt.Debtor, func(ctx context.Context) string {
+			return trf(
+				ctx,
+				"%s разделил с тобой расход «%s»: твоя доля — %.2f %s.\n\nДолги можно посмотреть командой <code>/debts</code>.",
+				html.EscapeString(debt.CreditorName),
+				html.EscapeString(debt.Category),
+				float64(debt.Amount)/10000,
+				debt.Currency,
+			)
+		})
+	}
+}
+
+// notify sends the message to another user of the bot, the message is rendered in the language of that user.
+func (c *client) notify(ctx context.Context, user *types.User, render func(ctx context.Context) string) {
+	chatID, err := c.storage.FetchTelegramID(ctx, user)
+	if err != nil {
+		c.logger.Error("cannot get telegram user to notify", zap.Error(err))
+		return
+	}
+
+	// the language of the Telegram client is known only while the user writes to the bot
+	c.sendMessage(chatID, render(i18n.WithLanguage(ctx, c.language(ctx, chatID, ""))))
+}
+
+// renderSplit lists the shares of the split expense owed to the user.
+func renderSplit(ctx context.Context, debts []types.Debt) string {
+	text := tr(ctx, "Доли участников:")
+	for _, debt := range debts {
+		text += fmt.Sprintf("\n%s: %.2f %s", html.EscapeString(debt.DebtorName), float64(debt.Amount)/10000, debt.Currency)
+	}
+
+	return text
+}
+
+// renderDebtBalance describes who owes whom from the point of view of the user.
+func renderDebtBalance(ctx context.Context, balance types.DebtBalance, counterparty string) string {
+	if balance.Amount > 0 {
+		return trf(ctx, "%s должен тебе %.2f %s", html.EscapeString(counterparty), float64(balance.Amount)/10000, balance.Currency)
+	}
+
+	return trf(ctx, "ты должен %s %.2f %s", html.EscapeString(counterparty), float64(-balance.Amount)/10000, balance.Currency)
+}
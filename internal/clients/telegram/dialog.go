@@ -0,0 +1,201 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/phrase"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const (
+	_callbackDialog = "dialog"
+
+	_dialogAdd = "add"
+)
+
+// dialog is the command asking the user for its arguments one by one, the state is kept between the answers.
+type dialog struct {
+	Name    string               `json:"name"`
+	Step    string               `json:"step"`
+	Expense request.AddExpense   `json:"expense"`
+	Photo   []tgbotapi.PhotoSize `json:"photo,omitempty"` // attached along with the expense
+}
+
+// dialogStep asks for the value and takes the answer into the dialog, the rejected answer is asked again.
+type dialogStep struct {
+	name   string
+	ask    func(c *client, ctx context.Context, user *types.User) (string, [][][]string)
+	answer func(c *client, d *dialog, answer string) error
+}
+
+// dialogFlow is the sequence of the steps followed by the action taking the answers.
+type dialogFlow struct {
+	steps  []dialogStep
+	finish func(c *client, ctx context.Context, user *types.User, d dialog) (string, added)
+}
+
+var _dialogFlows = map[string]dialogFlow{
+	_dialogAdd: {
+		steps: []dialogStep{
+			{name: "amount", ask: askExpenseAmount, answer: (*client).answerExpenseAmount},
+			{name: "category", ask: (*client).askExpenseCategory, answer: answerExpenseCategory},
+			{name: "date", ask: askExpenseDate, answer: answerExpenseDate},
+		},
+		finish: (*client).finishAddDialog,
+	},
+}
+
+// startDialog starts the dialog over, the unfinished dialog of the user is dropped.
+func (c *client) startDialog(ctx context.Context, user *types.User, d dialog) (string, [][][]string) {
+	step := _dialogFlows[d.Name].steps[0]
+	d.Step = step.name
+
+	c.saveState(ctx, user, _stateDialog, d)
+
+	return step.ask(c, ctx, user)
+}
+
+// continueDialog takes the answer to the current step of the dialog and asks for the next one, the last answer
+// finishes the dialog with the added expense if any. It reports false when there's no dialog going on.
+func (c *client) continueDialog(ctx context.Context, user *types.User, answer string) (string, [][][]string, added, bool) {
+	var d dialog
+	if !c.loadState(ctx, user, _stateDialog, &d) {
+		return "", nil, added{}, false
+	}
+
+	flow, ok := _dialogFlows[d.Name]
+	if !ok {
+		c.deleteState(ctx, user, _stateDialog)
+		return "", nil, added{}, false
+	}
+
+	current := -1
+	for i, step := range flow.steps {
+		if step.name == d.Step {
+			current = i
+			break
+		}
+	}
+	if current < 0 {
+		// the dialog was started by another version of the bot, it's easier to start over
+		text, keyboard := c.startDialog(ctx, user, d)
+		return text, keyboard, added{}, true
+	}
+
+	step := flow.steps[current]
+	if err := step.answer(c, &d, strings.TrimSpace(answer)); err != nil {
+		text, keyboard := step.ask(c, ctx, user)
+		return trf(ctx, dialogWrongAnswer, tr(ctx, err.Error()), text), keyboard, added{}, true
+	}
+
+	if current == len(flow.steps)-1 {
+		c.deleteState(ctx, user, _stateDialog)
+
+		text, a := flow.finish(c, ctx, user, d)
+		return text, nil, a, true
+	}
+
+	next := flow.steps[current+1]
+	d.Step = next.name
+
+	c.saveState(ctx, user, _stateDialog, d)
+
+	text, keyboard := next.ask(c, ctx, user)
+	return text, keyboard, added{}, true
+}
+
+// handleDialogCallback takes the button pressed as the answer to the dialog.
+func (c *client) handleDialogCallback(ctx context.Context, user *types.User, args string) reply {
+	text, keyboard, a, ok := c.continueDialog(ctx, user, args)
+	if !ok {
+		return reply{text: tr(ctx, dialogExpiredMessage), answered: true}
+	}
+
+	if a.expenseID != 0 {
+		keyboard = undoKeyboard(ctx, text, a.operation)
+	}
+
+	return reply{text: text, keyboard: keyboard, answered: true}
+}
+
+// handleCancel drops the unfinished dialog along with the receipt and the proposal waiting for the user.
+func (c *client) handleCancel(ctx context.Context, user *types.User, _ string) string {
+	pending := false
+	for _, key := range []string{_stateDialog, _stateReceipt, _stateProposal} {
+		var state json.RawMessage
+		if c.loadState(ctx, user, key, &state) {
+			c.deleteState(ctx, user, key)
+			pending = true
+		}
+	}
+
+	if !pending {
+		return tr(ctx, cancelNothingMessage)
+	}
+
+	return tr(ctx, cancelDoneMessage)
+}
+
+func askExpenseAmount(_ *client, ctx context.Context, _ *types.User) (string, [][][]string) {
+	return tr(ctx, dialogAmountPrompt), nil
+}
+
+func (c *client) answerExpenseAmount(d *dialog, answer string) error {
+	amount, currency, err := phrase.ParseAmount(answer, c.controller.IsCurrencyAvailable)
+	if err != nil {
+		return errWrongExpenseAmount
+	}
+
+	d.Expense.Amount, d.Expense.Currency = amount, currency
+
+	return nil
+}
+
+func (c *client) askExpenseCategory(ctx context.Context, user *types.User) (string, [][][]string) {
+	return tr(ctx, dialogCategoryPrompt), c.prepareCategoriesKeyboard(ctx, user, _callbackDialog)
+}
+
+func answerExpenseCategory(_ *client, d *dialog, answer string) error {
+	if d.Expense.Category = types.NormalizeCategory(answer); d.Expense.Category == "" {
+		return errNoExpenseCategory
+	}
+
+	return nil
+}
+
+// askExpenseDate offers the recent days, the answers are the Russian words whatever the language, as they are parsed
+// as phrases.
+func askExpenseDate(_ *client, ctx context.Context, _ *types.User) (string, [][][]string) {
+	return tr(ctx, dialogDatePrompt), [][][]string{{
+		{tr(ctx, "Сегодня"), _callbackDialog + _callbackSeparator + "сегодня"},
+		{tr(ctx, "Вчера"), _callbackDialog + _callbackSeparator + "вчера"},
+		{tr(ctx, "Позавчера"), _callbackDialog + _callbackSeparator + "позавчера"},
+	}}
+}
+
+func answerExpenseDate(_ *client, d *dialog, answer string) error {
+	date, err := phrase.ParseDate(answer, time.Now())
+	if err != nil {
+		return errInvalidExpenseDate
+	}
+
+	d.Expense.Date = date
+
+	return nil
+}
+
+func (c *client) finishAddDialog(ctx context.Context, user *types.User, d dialog) (string, added) {
+	d.Expense.User = user
+
+	text, a := c.addExpense(ctx, d.Expense)
+	if a.expenseID != 0 && len(d.Photo) > 0 {
+		text += "\n\n" + c.attachPhoto(ctx, user, a.expenseID, d.Photo)
+	}
+
+	return text, a
+}
@@ -0,0 +1,213 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+)
+
+const _progressBarWidth = 10
+
+var (
+	_goalAddRx        = regexp.MustCompile(`^(\S+)\s+(\d+(?:[.,]\d+)?)(?:\s+([A-Z]{3}))?(?:\s+(?:by|до)\s+(\d{2}\.\d{2}\.\d{4}))?$`)
+	_goalContributeRx = regexp.MustCompile(`^(\S+)\s+(-?\d+(?:[.,]\d+)?)(?:\s+([A-Z]{3}))?$`)
+)
+
+// handleGoals shows progress of the savings goals or manages them: adds a goal,
+// puts money aside for it or deletes it.
+func (c *client) handleGoals(ctx context.Context, user *types.User, args string) string {
+	subcommand, rest, _ := strings.Cut(args, " ")
+	rest = strings.TrimSpace(rest)
+
+	switch strings.ToLower(subcommand) {
+	case "":
+		return c.handleListGoals(ctx, user)
+
+	case "add", "добавить":
+		return c.handleAddGoal(ctx, user, rest)
+
+	case "put", "внести":
+		return c.handleContribute(ctx, user, rest)
+
+	case "delete", "удалить":
+		if rest == "" {
+			return errorMessage(ctx, nil, "Не удалось удалить цель.", goalsHelpMessage)
+		}
+
+		resp := c.controller.DeleteGoal(ctx, request.DeleteGoal{
+			User: user,
+			Name: rest,
+		})
+
+		switch {
+		case resp.NotFound:
+			return errorMessage(ctx, errUnknownGoal, "Не удалось удалить цель.", goalsHelpMessage)
+
+		case !resp.Success:
+			return tr(ctx, emergencyMessage)
+		}
+
+		return tr(ctx, doneMessage)
+	}
+
+	return errorMessage(ctx, nil, "Неизвестная команда.", goalsHelpMessage)
+}
+
+func (c *client) handleAddGoal(ctx context.Context, user *types.User, args string) string {
+	m := _goalAddRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось добавить цель.", goalsHelpMessage)
+	}
+
+	target, err := parseAmount(m[2])
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось добавить цель.", goalsHelpMessage)
+	}
+
+	var deadline time.Time
+	if m[4] != "" {
+		if deadline, err = time.Parse("02.01.2006", m[4]); err != nil {
+			return errorMessage(ctx, errWrongGoalDeadline, "Не удалось добавить цель.", goalsHelpMessage)
+		}
+	}
+
+	resp := c.controller.AddGoal(ctx, request.AddGoal{
+		User:     user,
+		Name:     m[1],
+		Target:   target,
+		Currency: m[3],
+		Deadline: deadline,
+	})
+
+	switch {
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось добавить цель.", goalsHelpMessage)
+
+	case resp.Duplicate:
+		return tr(ctx, goalDuplicateMessage)
+
+	case resp.Invalid != nil:
+		return errorMessage(ctx, explainInputError(resp.Invalid), "Не удалось добавить цель.", goalsHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+func (c *client) handleContribute(ctx context.Context, user *types.User, args string) string {
+	m := _goalContributeRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось пополнить цель.", goalsHelpMessage)
+	}
+
+	amount, err := parseAmount(m[2])
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось пополнить цель.", goalsHelpMessage)
+	}
+
+	resp := c.controller.Contribute(ctx, request.Contribute{
+		User:     user,
+		Name:     m[1],
+		Date:     utils.TruncateToDate(time.Now()),
+		Amount:   amount,
+		Currency: m[3],
+	})
+
+	switch {
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось пополнить цель.", goalsHelpMessage)
+
+	case resp.NotFound:
+		return errorMessage(ctx, errUnknownGoal, "Не удалось пополнить цель.", goalsHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+func (c *client) handleListGoals(ctx context.Context, user *types.User) string {
+	resp := c.controller.ListGoals(ctx, request.ListGoals{
+		User: user,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+
+	case len(resp.List) == 0:
+		return tr(ctx, goalsEmptyMessage) + "\n\n" + tr(ctx, goalsHelpMessage)
+	}
+
+	today := utils.TruncateToDate(time.Now())
+
+	items := make([]string, 0, len(resp.List))
+	for _, goal := range resp.List {
+		items = append(items, renderGoal(ctx, goal, resp.Currency, today))
+	}
+
+	return tr(ctx, "Цели:") + "\n\n" + strings.Join(items, "\n\n")
+}
+
+func renderGoal(ctx context.Context, goal types.GoalProgress, currency string, today time.Time) string {
+	text := fmt.Sprintf(
+		"<b>%s</b>: %.2f/%.2f %s",
+		html.EscapeString(goal.Name),
+		float64(goal.Saved)/10000,
+		float64(goal.Target)/10000,
+		goal.Currency,
+	)
+	if goal.Currency != currency {
+		text += fmt.Sprintf(" (≈ %.2f %s)", float64(goal.Converted)/10000, currency)
+	}
+
+	text += "\n" + renderProgressBar(goal.Saved, goal.Target)
+
+	switch {
+	case goal.Saved >= goal.Target:
+		text += "\n" + tr(ctx, "Цель достигнута! 🎉")
+
+	case goal.Deadline.IsZero():
+
+	case goal.Deadline.Before(today):
+		text += "\n" + trf(ctx, "Срок (%s) истёк, осталось накопить %.2f %s.", goal.Deadline.Format("02.01.2006"), float64(goal.Monthly)/10000, goal.Currency)
+
+	default:
+		text += "\n" + trf(ctx, "Чтобы успеть к %s, откладывай по %.2f %s в месяц.", goal.Deadline.Format("02.01.2006"), float64(goal.Monthly)/10000, goal.Currency)
+	}
+
+	return text
+}
+
+// renderProgressBar draws the saved share of the target as a bar of _progressBarWidth cells.
+func renderProgressBar(saved, target int64) string {
+	percent := int64(0)
+	if target > 0 && saved > 0 {
+		percent = saved * 100 / target
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	filled := int(percent) * _progressBarWidth / 100
+
+	return fmt.Sprintf(
+		"<code>%s%s</code> %d%%",
+		strings.Repeat("▓", filled),
+		strings.Repeat("░", _progressBarWidth-filled),
+		percent,
+	)
+}
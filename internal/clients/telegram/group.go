@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+var _groupRoleTitles = map[types.GroupRole]string{
+	types.RoleOwner:  "владелец",
+	types.RoleMember: "участник",
+}
+
+// handleGroup shows the user's group or manages it: creates a group, joins one by the code,
+// leaves it or removes a member. The name identifies the user among the members.
+func (c *client) handleGroup(ctx context.Context, user *types.User, name, args string) string {
+	subcommand, rest, _ := strings.Cut(args, " ")
+	rest = strings.TrimSpace(rest)
+
+	switch strings.ToLower(subcommand) {
+	case "":
+		return c.handleShowGroup(ctx, user)
+
+	case "create", "создать":
+		resp := c.controller.CreateGroup(ctx, request.CreateGroup{
+			User: user,
+			Name: name,
+		})
+
+		switch {
+		case resp.AlreadyMember:
+			return tr(ctx, groupAlreadyMemberMessage)
+
+		case !resp.Success:
+			return tr(ctx, emergencyMessage)
+		}
+
+		return trf(ctx, "Группа создана! Чтобы присоединиться к ней, отправь команду <code>/group join %s</code>.", resp.Group.Code)
+
+	case "join", "вступить":
+		if rest == "" {
+			return errorMessage(ctx, nil, "Не удалось вступить в группу.", groupHelpMessage)
+		}
+
+		resp := c.controller.JoinGroup(ctx, request.JoinGroup{
+			User: user,
+			Code: rest,
+			Name: name,
+		})
+
+		switch {
+		case resp.NotFound:
+			return errorMessage(ctx, errUnknownGroup, "Не удалось вступить в группу.", groupHelpMessage)
+
+		case resp.AlreadyMember:
+			return tr(ctx, groupAlreadyMemberMessage)
+
+		case !resp.Success:
+			return tr(ctx, emergencyMessage)
+		}
+
+		return tr(ctx, doneMessage) + "\n\n" + renderGroup(ctx, resp.Group)
+
+	case "leave", "выйти":
+		resp := c.controller.LeaveGroup(ctx, request.LeaveGroup{
+			User: user,
+		})
+
+		switch {
+		case resp.NotFound:
+			return tr(ctx, groupEmptyMessage)
+
+		case !resp.Success:
+			return tr(ctx, emergencyMessage)
+		}
+
+		return tr(ctx, doneMessage)
+
+	case "remove", "исключить":
+		if rest == "" {
+			return errorMessage(ctx, nil, "Не удалось исключить участника.", groupHelpMessage)
+		}
+
+		resp := c.controller.RemoveGroupMember(ctx, request.RemoveGroupMember{
+			User: user,
+			Name: rest,
+		})
+
+		switch {
+		case resp.Forbidden:
+			return errorMessage(ctx, errGroupOwnerRemoves, "Не удалось исключить участника.", groupHelpMessage)
+
+		case resp.NotFound:
+			return errorMessage(ctx, errUnknownGroupMember, "Не удалось исключить участника.", groupHelpMessage)
+
+		case !resp.Success:
+			return tr(ctx, emergencyMessage)
+		}
+
+		return tr(ctx, doneMessage)
+	}
+
+	return errorMessage(ctx, nil, "Неизвестная команда.", groupHelpMessage)
+}
+
+func (c *client) handleShowGroup(ctx context.Context, user *types.User) string {
+	resp := c.controller.GetGroup(ctx, request.GetGroup{
+		User: user,
+	})
+
+	switch {
+	case resp.NotFound:
+		return tr(ctx, groupEmptyMessage) + "\n\n" + tr(ctx, groupHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return renderGroup(ctx, resp.Group)
+}
+
+func renderGroup(ctx context.Context, group types.Group) string {
+	text := trf(ctx, "Группа (код для вступления — <code>%s</code>):", group.Code)
+	for _, member := range group.Members {
+		text += fmt.Sprintf("\n%s — %s", html.EscapeString(member.Name), tr(ctx, _groupRoleTitles[member.Role]))
+	}
+
+	return text
+}
+
+// memberName returns the name of the Telegram user shown to the other members of the group.
+func memberName(from *tgbotapi.User) string {
+	if from.UserName != "" {
+		return "@" + from.UserName
+	}
+
+	if from.FirstName != "" {
+		return from.FirstName
+	}
+
+	return fmt.Sprintf("id%d", from.ID)
+}
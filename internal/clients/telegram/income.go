@@ -0,0 +1,70 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+// handleIncome adds an income in the same format as /add does, or lists the last incomes.
+func (c *client) handleIncome(ctx context.Context, user *types.User, args string) string {
+	if args == "" {
+		return c.handleListIncomes(ctx, user)
+	}
+
+	args, account := cutAccountSelector(args)
+
+	e, err := parseExactPhrase(args, time.Now(), c.controller.IsCurrencyAvailable)
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось добавить доход.", incomeHelpMessage)
+	}
+
+	resp := c.controller.AddIncome(ctx, request.AddIncome{
+		User:     user,
+		Date:     e.Date,
+		Amount:   e.Amount,
+		Currency: e.Currency,
+		Category: e.Category,
+		Account:  account,
+	})
+
+	switch {
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось добавить доход.", incomeHelpMessage)
+
+	case resp.UnknownAccount:
+		return errorMessage(ctx, errUnknownAccount, "Не удалось добавить доход.", accountHelpMessage)
+
+	case resp.Invalid != nil:
+		return errorMessage(ctx, explainInputError(resp.Invalid), "Не удалось добавить доход.", incomeHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+func (c *client) handleListIncomes(ctx context.Context, user *types.User) string {
+	resp := c.controller.ListIncomes(ctx, request.ListIncomes{
+		User: user,
+	})
+
+	switch {
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+
+	case len(resp.List) == 0:
+		return tr(ctx, incomeEmptyMessage) + "\n\n" + tr(ctx, incomeHelpMessage)
+	}
+
+	text := tr(ctx, "Последние доходы:")
+	for i, item := range resp.List {
+		text += fmt.Sprintf("\n%d. %s — %.2f %s — %s", i+1, item.Date.Format("02.01.2006"), float64(item.Amount)/10000, item.Currency, item.Category)
+	}
+
+	return text
+}
@@ -0,0 +1,69 @@
+package telegram
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/i18n"
+	"go.uber.org/zap"
+)
+
+const _callbackLang = "lang"
+
+// _languageNames are the names of the languages in themselves, as the user may not read the current one.
+var _languageNames = map[i18n.Language]string{
+	i18n.Russian: "🇷🇺 Русский",
+	i18n.English: "🇬🇧 English",
+}
+
+// language returns the language chosen by the user with /lang, otherwise the one closest to the language of
+// the Telegram client given by the tag.
+func (c *client) language(ctx context.Context, tgUserID int64, tag string) i18n.Language {
+	chosen, err := c.storage.FetchLanguage(ctx, tgUserID)
+	if err != nil {
+		c.logger.Error("cannot get user language", zap.Error(err))
+	}
+
+	if lang, ok := i18n.Parse(chosen); ok {
+		return lang
+	}
+
+	return i18n.Match(tag)
+}
+
+// handleLang switches the language to the one given by the arguments, or offers the languages to choose from.
+func (c *client) handleLang(ctx context.Context, tgUserID int64, args string) (string, [][][]string) {
+	if args == "" {
+		buttons := make([][]string, 0, len(i18n.Languages))
+		for _, lang := range i18n.Languages {
+			buttons = append(buttons, []string{_languageNames[lang], _callbackLang + _callbackSeparator + string(lang)})
+		}
+
+		return tr(ctx, langChooseMessage), [][][]string{buttons}
+	}
+
+	lang, ok := i18n.Parse(args)
+	if !ok {
+		return errorMessage(ctx, errUnknownLanguage, "Не удалось сменить язык.", langHelpMessage), nil
+	}
+
+	return c.setLanguage(ctx, tgUserID, lang), nil
+}
+
+func (c *client) handleLangCallback(ctx context.Context, tgUserID int64, args string) (string, bool) {
+	lang, ok := i18n.Parse(args)
+	if !ok {
+		return errorMessage(ctx, errUnknownLanguage, "Не удалось сменить язык.", langHelpMessage), false
+	}
+
+	return c.setLanguage(ctx, tgUserID, lang), true
+}
+
+// setLanguage keeps the language chosen by the user, the reply is in that language already.
+func (c *client) setLanguage(ctx context.Context, tgUserID int64, lang i18n.Language) string {
+	if err := c.storage.SetLanguage(ctx, tgUserID, string(lang)); err != nil {
+		c.logger.Error("cannot set user language", zap.Error(err))
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(i18n.WithLanguage(ctx, lang), langDoneMessage)
+}
@@ -1,38 +1,121 @@
 package telegram
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/i18n"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+)
+
 const (
-	helloMessage = "Привет! 👋\n\n" + currencyHelpMessage + "\n\n\n" + addHelpMessage + "\n\n\n" + reportHelpMessage
+	helloMessage = "Привет! 👋\n\n" + currencyHelpMessage + "\n" + langHelpMessage + "\n\n\n" + addHelpMessage + "\n\n\n" + editHelpMessage + "\n\n\n" + reportHelpMessage
 
 	currencyHelpMessage    = `Для смены текущей валюты используй команду /currency.`
+	langHelpMessage        = `Чтобы сменить язык, используй команду /lang (например, <code>/lang en</code>).`
+	langChooseMessage      = `Выбери язык:`
+	langDoneMessage        = `Готово! Теперь я говорю по-русски.`
 	currencyCurrentMessage = `Текущая валюта: `
 	currencyChooseMessage  = `Выбери валюту:`
 	currencyLaterMessage   = "🚧 Выполняется обновление курсов валют. 🚧\n\nПовтори попытку чуть позже."
 
 	limitsHelpMessage = `Чтобы задать лимит, отправь команду:
 <pre>
-/limit &lt;сумма&gt;
+/limit &lt;сумма&gt; [период]
 </pre>
 
 Можно задавать лимит для отдельных категорий. Для этого можно использовать команду:
 <pre>
-/limit &lt;сумма&gt; &lt;категория&gt;
+/limit &lt;сумма&gt; [период] &lt;категория&gt;
 </pre>
 
 Сумма указывается <b>целым числом</b>.
+Период может быть одним из: <b>day</b> (день), <b>week</b> (неделя), <b>month</b> (месяц) или <b>Nd</b> (N дней). По умолчанию лимит задаётся на месяц.
+Период указывается сразу после суммы. Если название категории начинается со слова периода, укажи период явно: <code>/limit 100 month day care</code>.
+С началом нового периода лимит восстанавливается. Если добавить к периоду знак <b>+</b> (например, <b>month+</b>), неизрасходованный остаток перенесётся на следующий период.
 Для удаления лимита, укажите в качестве суммы <b>0</b>. А команда <code>/limit</code> (без дополнительных параметров) покажет текущие лимиты.
+
+Когда расход достигнет заданной доли лимита, я пришлю предупреждение (один раз за период). По умолчанию это 50%, 80% и 100%. Изменить пороги можно командой:
+<pre>
+/limit alerts 50,80,100
+</pre>
 `
 	limitsEmptyMessage = "Лимиты ещё не заданы."
 
 	addHelpMessage = `Чтобы добавить запись о расходах, отправь команду:
 <pre>
-/add [дата] &lt;сумма&gt; &lt;категория&gt;
+/add [дата] &lt;сумма&gt; [валюта] &lt;категория&gt;
+</pre>
+Порядок не важен: <code>/add вчера такси 350</code>, <code>/add 350р кофе 12 окт</code> и <code>/add coffee 4.5$ yesterday</code> тоже подойдут.
+
+Дата может быть указана в формате <b>dd.mm.yyyy</b> (день.месяц.год) или днём и месяцем (<b>12 окт</b>, <b>oct 12</b>) — тогда это ближайшая прошедшая такая дата.
+Без даты расход записывается на сегодня, а ещё подойдут слова <b>сегодня</b>, <b>вчера</b>, <b>позавчера</b>, <b>today</b>, <b>yesterday</b>, знак <b>@</b> и "дни назад": <b>3 дня назад</b>, <b>2 days ago</b> или <b>-2d</b>.
+
+Сумма указывается в формате <b>XX[.yy]</b>: целого или дробного числа с одним или двумя знаками после запятой (вместо которой можно использовать точку).
+Рядом с суммой можно указать валюту расхода: код (например, <b>/add 12.50 EUR кофе</b>), символ (<b>/add 12,5€ кофе</b>) или название (<b>/add 350 руб кофе</b>). Без указания валюты расход будет записан в текущей валюте.
+
+Если отправить <code>/add</code> без аргументов, я спрошу сумму, категорию и дату по очереди, а <code>/cancel</code> прервёт этот диалог.
+
+Можно обойтись и без команды: просто отправь сообщение вроде <code>такси 350</code>, и я предложу добавить расход. Если в сообщении несколько чисел, я тоже уточню, какое из них сумма.
+
+Категории можно вкладывать друг в друга через <b>/</b>, например <b>transport/taxi</b>. Регистр букв и лишние пробелы в названии категории не важны, а синонимы категорий задаются командой <code>/categories</code>.
+К расходу можно добавить заметку в кавычках и теги через <b>#</b>: <code>/add 450 кофе #work "встреча с клиентом"</code>. Найти расходы по ним поможет команда <code>/find</code>.
+
+Расход можно добавить и по QR-коду кассового чека: просто отправь фото кода или строку, которую он содержит.
+
+Чтобы сохранить фото чека, отправь его в ответ на подтверждение добавленного расхода или с подписью-командой <code>/add</code>. Посмотреть фото можно кнопкой 📎 под списком <code>/list</code>.`
+
+	editHelpMessage = `Чтобы посмотреть последние расходы, отправь команду <code>/list</code>. Под списком появятся кнопки для изменения (✏️) и удаления (🗑) записей.
+
+Чтобы изменить запись о расходах, отправь команду:
+<pre>
+/edit &lt;номер&gt; [дата] &lt;сумма&gt; [валюта] &lt;категория&gt;
 </pre>
-Дата может быть указана в формате <b>dd.mm.yyyy</b> (день.месяц.год).
-Чтобы задать сегодняшнее число, можно использовать знак <b>@</b> в качестве даты, или не указывать дату совсем.
-Кроме того, в качестве даты можно использовать строку вида <b>-Nd</b>, где N — количество "дней назад" (1 можно не указывать).
-Например, <b>-2d</b> значит "2 дня назад".
+Номер записи подскажет кнопка ✏️, а дата, сумма и валюта указываются так же, как в команде <code>/add</code>.
 
-Сумма указывается в формате <b>XX[.yy]</b>: целого или дробного числа с одним или двумя знаками после запятой (вместо которой можно использовать точку).`
+Только что добавленный расход, изменённый лимит или сменённую валюту можно вернуть как было командой <code>/undo</code> или кнопкой ↩️ Отменить.`
+	proposalPrompt          = "Добавить расход?\n%s"
+	proposalRejectedMessage = "Хорошо, не добавляю. 👌"
+	proposalExpiredMessage  = "Расход уже добавлен или отменён. 🤷"
+
+	dialogAmountPrompt   = "Сколько потрачено? Отправь сумму, например <b>350</b> или <b>12.50 EUR</b>.\nПередумал — отправь /cancel."
+	dialogCategoryPrompt = "На что? Выбери категорию или отправь её сообщением:"
+	dialogDatePrompt     = "Когда? Выбери день или отправь дату, например <b>12 окт</b> или <b>12.10.2022</b>:"
+	dialogWrongAnswer    = "Ошибка: %s. 🙁\n\n%s"
+	dialogExpiredMessage = "Этот вопрос уже неактуален, начни заново командой /add. 🤷"
+
+	cancelDoneMessage    = "Хорошо, отменяю. 👌"
+	cancelNothingMessage = "Нечего отменять. 🤷"
+
+	undoExpenseMessage  = "Отменено: добавление расхода. ↩️"
+	undoLimitMessage    = "Отменено: изменение лимита. ↩️"
+	undoCurrencyMessage = "Отменено: смена валюты. ↩️"
+	undoNothingMessage  = "Нечего отменять: отменить можно только последнее действие и лишь в течение нескольких минут. 🤷"
+
+	editPromptMessage      = "Чтобы изменить расход, отправь исправленную команду:"
+	expenseNotFoundMessage = "Расход не найден. 🤷"
+
+	findHelpMessage = `Чтобы найти расходы по категории, заметке или тегу, отправь команду:
+<pre>
+/find &lt;что искать&gt; [from dd.mm.yyyy] [to dd.mm.yyyy] [&gt;сумма] [&lt;сумма]
+</pre>
+Например, <code>/find кофе #work from 01.10.2022 &gt;300</code>. Вместо <b>from</b> и <b>to</b> можно писать <b>с</b> и <b>по</b>, а суммы сравниваются в валюте расхода.
+Найденные расходы выводятся постранично, страницы листаются кнопками ◀️ и ▶️.`
+	findEmptyMessage   = "Ничего не нашлось. 🤷"
+	findExpiredMessage = "Результаты поиска устарели, повтори команду /find."
+
+	categoriesHelpMessage = `Команда <code>/categories</code> (без дополнительных параметров) покажет твои категории вместе с их синонимами.
+Чтобы расходы с одной категорией попадали в другую, задай синоним:
+<pre>
+/categories alias такси = transport/taxi
+</pre>
+Для удаления синонима не указывай категорию. Переименовать категорию (вместе с подкатегориями, лимитами, регулярными расходами и правилами) во всей истории расходов можно командой:
+<pre>
+/categories rename &lt;категория&gt; = &lt;новое название&gt;
+</pre>
+А чтобы объединить две категории, используй <b>merge</b> вместо <b>rename</b>: старое название станет синонимом новой категории.`
+	categoriesEmptyMessage = "Категорий пока нет."
 
 	reportHelpMessage = `Для просмотра расходов по категориям выполни одну из команд (w — расходы за неделю, m — за месяц, y — за год):
 <pre>
@@ -42,23 +125,191 @@ const (
 </pre>
 Если задать положительное число N, будут выведены расходы за N последних недель/месяцев/лет.
 
+Можно указать и произвольный период (даты включительно):
+<pre>
+/report 01.09.2022-30.09.2022
+</pre>
+Или один из именованных периодов: <b>this week</b>, <b>last week</b>, <b>this month</b>, <b>last month</b>, <b>ytd</b>, <b>last year</b> (или <b>эта неделя</b>, <b>прошлая неделя</b>, <b>этот месяц</b>, <b>прошлый месяц</b>, <b>с начала года</b>, <b>прошлый год</b>).
+
+Чтобы увидеть, как менялись расходы, добавь в конце команды разбивку по времени: <b>by day</b>, <b>by week</b> или <b>by month</b> (<b>по дням</b>, <b>по неделям</b>, <b>по месяцам</b>). Например:
+<pre>
+/report 6m by month
+</pre>
+
+Чтобы сравнить расходы текущей недели, месяца или года с предыдущими, выполни <code>/report compare w</code>, <code>/report compare m</code> или <code>/report compare y</code> (или <b>сравнить</b> вместо <b>compare</b>). Прошедшие дни текущего периода сравниваются с тем же числом дней от начала предыдущего. Сравнить можно и два произвольных периода, второй с первым:
+<pre>
+/report compare 01.09.2022-30.09.2022 01.10.2022-31.10.2022
+</pre>
+
+Чтобы получить отчёт в виде диаграмм, добавь в конце команды <b>chart</b> (или <b>график</b>), например: <code>/report 3m chart</code>.
+
+Чтобы свести подкатегории (например, <b>transport/taxi</b>) в их верхние категории, добавь в самом конце команды <b>rollup</b> (или <b>свернуть</b>): <code>/report m by week rollup</code>.
+
 Команда <code>/report</code> (без дополнительных параметров) вернёт расходы за последнюю неделю. 
 `
 	reportRetry      = "Не удалось сформировать отчёт.🙁\nПопробуй ещё раз чуть позже."
 	reportNoExpenses = "Ты ещё не добавил ни одного расхода."
 
-	doneMessage  = `Готово!`
-	limitReached = `❗ Ты исчерпал заданный лимит.`
+	exportHelpMessage = `Чтобы выгрузить расходы в файл, выполни команду:
+<pre>
+/export [период] [csv|xlsx]
+</pre>
+Период задаётся так же, как в команде <code>/report</code>, например: <code>/export last month xlsx</code>. Без периода будут выгружены все расходы, по умолчанию — в формате CSV.`
+	exportCaption    = "Расходы %s: %d шт. (сумма в валюте — %s)"
+	exportRetry      = "Не удалось выгрузить расходы.🙁\nПопробуй ещё раз чуть позже."
+	exportNoExpenses = "За этот период нет расходов."
+
+	importHelpMessage = `Чтобы загрузить расходы из банковской выписки, отправь файл выписки в формате CSV или OFX.
+Формат CSV-выписки я постараюсь определить сам. Если не получится, укажи название банка в подписи к файлу.
+Поступления на счёт не загружаются, а расходы, загруженные ранее, повторно не добавляются.
 
-	unknownCommandMessage = "Извини, я не знаю такой команды. 🙁\n\n" + currencyHelpMessage + "\n\n\n" + addHelpMessage + "\n\n\n" + reportHelpMessage
+` + ruleHelpMessage
+	importDoneMessage     = "Расходов в выписке: %d.\nДобавлено: %d, загружено ранее: %d."
+	importNoExpenses      = "В выписке нет расходов."
+	importTooLargeMessage = "Файл выписки должен быть не больше %d КБ."
+	importRetry           = "Не удалось загрузить выписку.🙁\nПопробуй ещё раз чуть позже."
+
+	attachmentDoneMessage     = "Фото прикреплено к расходу. 📎"
+	attachmentTooLargeMessage = "Фото должно быть не больше %d КБ."
+	attachmentNotFoundMessage = "К расходу не прикреплено фото. 🤷"
+	attachmentRetry           = "Не удалось сохранить фото.🙁\nПопробуй ещё раз чуть позже."
+
+	ruleHelpMessage = `Категории загруженных расходов определяются по описанию операции. Чтобы расходы, в описании которых встречается заданный текст, попадали в нужную категорию, отправь команду:
+<pre>
+/rule &lt;текст&gt; = &lt;категория&gt;
+</pre>
+Если подходят несколько правил, выбирается то, у которого текст длиннее. Для удаления правила не указывай категорию, а команда <code>/rule</code> (без дополнительных параметров) покажет текущие правила.`
+	ruleEmptyMessage    = "Правила категорий ещё не заданы."
+	ruleNotFoundMessage = "Правило не найдено. 🤷"
+
+	receiptHelpMessage = `Чтобы добавить расход по кассовому чеку, отправь фото QR-кода с чека или строку, которую он содержит (её показывают приложения для сканирования QR-кодов):
+<pre>
+t=20221021T1530&amp;s=1234.50&amp;fn=...&amp;i=...&amp;fp=...&amp;n=1
+</pre>
+Строку можно отправить и командой <code>/add</code>. Категорию расхода можно указать после строки (или в подписи к фото), иначе я предложу её выбрать.
+Один и тот же чек дважды не добавляется.`
+	receiptCategoryPrompt    = "Чек от %s на %.2f %s.\nВыбери категорию расхода или отправь её ответом на это сообщение:"
+	receiptCategoryMissing   = "Отправь категорию расхода по чеку ответом на сообщение с чеком."
+	receiptDuplicateMessage  = "Этот чек уже добавлен. 🧾"
+	receiptNotExpenseMessage = "Это чек возврата, расходы по нему не добавляются."
+	receiptExpiredMessage    = "Чек не найден, отправь его ещё раз. 🤷"
+	receiptRetry             = "Не удалось получить фото чека.🙁\nПопробуй ещё раз чуть позже."
+
+	recurringHelpMessage = `Чтобы добавить регулярный расход (например, аренду или подписку), отправь команду:
+<pre>
+/recurring add &lt;расписание&gt; [дата начала] &lt;сумма&gt; [валюта] &lt;категория&gt;
+</pre>
+Расписание может быть одним из: <b>day</b> (день), <b>week</b> (неделя), <b>month</b> (месяц), <b>year</b> (год), <b>Nd</b>, <b>Nw</b>, <b>Nm</b>, <b>Ny</b> (каждые N дней, недель, месяцев или лет) или правилом вида <b>FREQ=MONTHLY;INTERVAL=2</b>.
+Дата начала, сумма и валюта указываются так же, как в команде <code>/add</code>. Например, <code>/recurring add month 05.11.2022 30000 аренда</code>.
+
+Расходы будут добавляться сами в дни по расписанию. Команда <code>/recurring</code> (без дополнительных параметров) покажет регулярные расходы, под списком появятся кнопки для их приостановки (⏸) и возобновления (▶️).`
+	recurringEmptyMessage    = "Регулярных расходов пока нет."
+	recurringPausedMessage   = "Регулярный расход «%s» приостановлен."
+	recurringResumedMessage  = "Регулярный расход «%s» возобновлён, следующий будет добавлен %s."
+	recurringNotFoundMessage = "Регулярный расход не найден. 🤷"
+
+	incomeHelpMessage = `Чтобы добавить запись о доходах, отправь команду:
+<pre>
+/income [дата] &lt;сумма&gt; [валюта] &lt;категория&gt;
+</pre>
+Дата, сумма и валюта указываются так же, как в команде <code>/add</code>. Например, <code>/income 150000 зарплата</code>.
+Доходы не расходуют лимиты, а в отчётах появятся вместе с расходами и сбережениями за период.
+Команда <code>/income</code> (без дополнительных параметров) покажет последние доходы.`
+	incomeEmptyMessage = "Ты ещё не добавил ни одного дохода."
+
+	accountHelpMessage = `Чтобы добавить счёт (например, карту, наличные или валютный вклад), отправь команду:
+<pre>
+/account add &lt;название&gt; [валюта] [остаток]
+</pre>
+Например, <code>/account add savings USD 1000</code>. Без указания валюты счёт будет в текущей валюте.
+Чтобы списать расход или зачислить доход на счёт, добавь к команде <code>/add</code> или <code>/income</code> название счёта через <b>#</b>: <code>/add 450 кофе #card</code>.
+Команда <code>/account</code> (без дополнительных параметров) покажет счета, а <code>/balance</code> — их балансы.`
+	accountEmptyMessage     = "Счетов пока нет."
+	accountDuplicateMessage = "Счёт с таким названием уже есть."
+
+	goalsHelpMessage = `Чтобы поставить цель накоплений, отправь команду:
+<pre>
+/goals add &lt;название&gt; &lt;сумма&gt; [валюта] [by dd.mm.yyyy]
+</pre>
+Например, <code>/goals add vacation 200000 RUB by 01.06.2023</code>. Без указания валюты цель будет в текущей валюте.
+Чтобы отложить деньги на цель, отправь <code>/goals put &lt;название&gt; &lt;сумма&gt; [валюта]</code> (отрицательная сумма вернёт их обратно), а чтобы удалить цель — <code>/goals delete &lt;название&gt;</code>.
+Команда <code>/goals</code> (без дополнительных параметров) покажет прогресс целей и сколько нужно откладывать в месяц, чтобы успеть к сроку.`
+	goalsEmptyMessage    = "Целей пока нет."
+	goalDuplicateMessage = "Цель с таким названием уже есть."
+
+	groupHelpMessage = `Группа объединяет расходы нескольких человек: у участников общие лимиты и общий отчёт, а расходы каждый добавляет сам.
+<pre>
+/group create
+/group join &lt;код&gt;
+/group leave
+/group remove &lt;участник&gt;
+</pre>
+Создатель группы становится её владельцем: он задаёт лимиты и может исключать участников. Если владелец выйдет, группа будет распущена.
+Команда <code>/group</code> (без дополнительных параметров) покажет участников и код для вступления.`
+	groupEmptyMessage         = "Ты не состоишь в группе."
+	groupAlreadyMemberMessage = "Ты уже состоишь в группе. Чтобы перейти в другую, сначала выйди из текущей: <code>/group leave</code>."
+
+	debtsHelpMessage = `Чтобы разделить расход поровну с участниками группы, добавь к команде <code>/add</code> слово <b>split</b> и их имена:
+<pre>
+/add 3000 ресторан split @alice @bob
+</pre>
+Расходом станет только твоя доля, а доли остальных — их долгами тебе. Участники получат уведомление.
+Разделить расход можно только с участниками своей группы (<code>/group</code>): Telegram не сообщает боту, кто скрывается за @именем, а вступая в группу, участник соглашается принимать долги.
+Команда <code>/debts</code> покажет, кто кому должен (долги в одной валюте взаимно зачитываются), а <code>/settle &lt;участник&gt; [валюта]</code> закроет долги с участником.`
+	debtsEmptyMessage = "Открытых долгов нет."
+
+	transferHelpMessage = `Чтобы перевести деньги между счетами, отправь команду:
+<pre>
+/transfer &lt;откуда&gt; &lt;куда&gt; &lt;сумма&gt; [сумма зачисления]
+</pre>
+Сумма указывается в валюте счёта списания. Если валюты счетов различаются, сумма зачисления рассчитается по сегодняшнему курсу, но её можно указать и явно: <code>/transfer card savings 6150 100</code>.`
+
+	doneMessage       = `Готово!`
+	limitReached      = `❗ Ты исчерпал заданный лимит.`
+	limitAlertMessage = `⚠️ Ты израсходовал %d%% заданного лимита.`
+
+	unknownCommandMessage = "Извини, я не знаю такой команды. 🙁\n\n" + currencyHelpMessage + "\n" + langHelpMessage + "\n\n\n" + addHelpMessage + "\n\n\n" + editHelpMessage + "\n\n\n" + reportHelpMessage
 
 	emergencyMessage = "Извини, бот временно неисправен. 🙁\nМы уже работаем над его починкой, возвращайтесь чуть позже."
 )
 
-func errorMessage(err error, fail, help string) string {
+// _catalogue holds translations of the messages, which are written in Russian, see messages_en.go.
+var _catalogue = i18n.Catalogue{
+	i18n.English: _english,
+}
+
+// tr translates the message into the language of the user the context belongs to.
+func tr(ctx context.Context, message string) string {
+	return _catalogue.Translate(i18n.FromContext(ctx), message)
+}
+
+// trf translates the format and formats it with the arguments.
+func trf(ctx context.Context, format string, args ...interface{}) string {
+	return _catalogue.Sprintf(i18n.FromContext(ctx), format, args...)
+}
+
+// trn chooses the plural form of the translated format for the number, e.g. "%d день|%d дня|%d дней",
+// and formats it with the arguments.
+func trn(ctx context.Context, forms string, n int64, args ...interface{}) string {
+	return fmt.Sprintf(_catalogue.Plural(i18n.FromContext(ctx), forms, n), args...)
+}
+
+func errorMessage(ctx context.Context, err error, fail, help string) string {
 	if err == nil {
-		return fail + "\n\nДля справки:\n" + help
+		return trf(ctx, "%s\n\nДля справки:\n%s", tr(ctx, fail), tr(ctx, help))
+	}
+
+	return trf(ctx, "%s\nОшибка: %s.\n\n\nДля справки:\n%s", tr(ctx, fail), tr(ctx, err.Error()), tr(ctx, help))
+}
+
+// explainInputError returns the explanation of the input rejected by the model.
+func explainInputError(err error) error {
+	var e model.InputError
+	if errors.As(err, &e) {
+		if explained, ok := _inputErrors[e]; ok {
+			return explained
+		}
 	}
 
-	return fail + "\nОшибка: " + err.Error() + ".\n\n\nДля справки:\n" + help
+	return err
 }
@@ -0,0 +1,444 @@
+package telegram
+
+// English parts of the composite messages.
+const (
+	enCurrencyHelpMessage = `To change the current currency, use the /currency command.`
+	enLangHelpMessage     = `To change the language, use the /lang command (e.g. <code>/lang ru</code>).`
+
+	enAddHelpMessage = `To add an expense, send the command:
+<pre>
+/add [date] &lt;amount&gt; [currency] &lt;category&gt;
+</pre>
+The order doesn't matter: <code>/add yesterday taxi 350</code>, <code>/add 350r coffee oct 12</code> and <code>/add coffee 4.5$ yesterday</code> will do too.
+
+The date may be given as <b>dd.mm.yyyy</b> (day.month.year) or as a day and a month (<b>oct 12</b>, <b>12 окт</b>), which is the closest such date in the past.
+Without a date the expense is added for today, and the words <b>today</b>, <b>yesterday</b>, <b>сегодня</b>, <b>вчера</b>, <b>позавчера</b>, the <b>@</b> sign and "days ago" will do too: <b>2 days ago</b>, <b>3 дня назад</b> or <b>-2d</b>.
+
+The amount is given as <b>XX[.yy]</b>: an integer or a decimal number with one or two digits after the point (a comma will do too).
+Next to the amount you may give the currency of the expense: its code (e.g. <b>/add 12.50 EUR coffee</b>), its sign (<b>/add 12,5€ coffee</b>) or its name (<b>/add 350 руб coffee</b>). Without a currency the expense is added in the current one.
+
+If you send <code>/add</code> without arguments, I'll ask for the amount, the category and the date one by one, and <code>/cancel</code> stops this dialog.
+
+You may do without the command too: just send a message like <code>taxi 350</code>, and I'll offer to add the expense. If there are several numbers in the message, I'll ask which of them is the amount.
+
+Categories may be nested with <b>/</b>, e.g. <b>transport/taxi</b>. The case of letters and extra spaces in category names don't matter, and category aliases are set with the <code>/categories</code> command.
+An expense may have a note in quotes and tags with <b>#</b>: <code>/add 450 coffee #work "meeting a client"</code>. The <code>/find</code> command will find expenses by them.
+
+An expense may be added by the QR code of a receipt as well: just send a photo of the code or the text it contains.
+
+To keep a photo of the receipt, send it in reply to the confirmation of the added expense or with the <code>/add</code> command as its caption. The 📎 button under the <code>/list</code> shows the photo.`
+
+	enEditHelpMessage = `To see the latest expenses, send the <code>/list</code> command. Under the list there will be buttons to edit (✏️) and delete (🗑) the expenses.
+
+To edit an expense, send the command:
+<pre>
+/edit &lt;number&gt; [date] &lt;amount&gt; [currency] &lt;category&gt;
+</pre>
+The ✏️ button shows the number of the expense, and the date, the amount and the currency are given the same way as for the <code>/add</code> command.
+
+The expense just added, the limit just changed or the currency just switched may be reverted with the <code>/undo</code> command or the ↩️ Undo button.`
+
+	enReportHelpMessage = `To see expenses by category, run one of the commands (w — expenses for a week, m — for a month, y — for a year):
+<pre>
+/report [N]w
+/report [N]m
+/report [N]y
+</pre>
+With a positive number N, the expenses for the last N weeks/months/years are shown.
+
+An arbitrary period may be given too (the dates are inclusive):
+<pre>
+/report 01.09.2022-30.09.2022
+</pre>
+Or one of the named periods: <b>this week</b>, <b>last week</b>, <b>this month</b>, <b>last month</b>, <b>ytd</b>, <b>last year</b>.
+
+To see how the expenses changed, add a breakdown by time at the end of the command: <b>by day</b>, <b>by week</b> or <b>by month</b>. For example:
+<pre>
+/report 6m by month
+</pre>
+
+To compare the expenses of the current week, month or year with the previous ones, run <code>/report compare w</code>, <code>/report compare m</code> or <code>/report compare y</code>. The days passed in the current period are compared with the same number of days from the start of the previous one. Two arbitrary periods may be compared as well, the second one with the first:
+<pre>
+/report compare 01.09.2022-30.09.2022 01.10.2022-31.10.2022
+</pre>
+
+To get the report as charts, add <b>chart</b> at the end of the command, e.g. <code>/report 3m chart</code>.
+
+To roll subcategories (e.g. <b>transport/taxi</b>) up into their top categories, add <b>rollup</b> at the very end of the command: <code>/report m by week rollup</code>.
+
+The <code>/report</code> command (without parameters) shows the expenses for the last week.
+`
+
+	enRuleHelpMessage = `Categories of imported expenses are found by the description of the transaction. To put the expenses, whose description contains the text, into the category, send the command:
+<pre>
+/rule &lt;text&gt; = &lt;category&gt;
+</pre>
+If several rules match, the one with the longer text is chosen. To delete a rule, don't give the category, and the <code>/rule</code> command (without parameters) shows the current rules.`
+)
+
+// _english holds English translations of the messages.
+var _english = map[string]string{
+	helloMessage: "Hi! 👋\n\n" + enCurrencyHelpMessage + "\n" + enLangHelpMessage + "\n\n\n" + enAddHelpMessage + "\n\n\n" + enEditHelpMessage + "\n\n\n" + enReportHelpMessage,
+
+	currencyHelpMessage:    enCurrencyHelpMessage,
+	langHelpMessage:        enLangHelpMessage,
+	langChooseMessage:      `Choose the language:`,
+	langDoneMessage:        `Done! I speak English now.`,
+	currencyCurrentMessage: `Current currency: `,
+	currencyChooseMessage:  `Choose the currency:`,
+	currencyLaterMessage:   "🚧 Exchange rates are being updated. 🚧\n\nPlease try again a bit later.",
+
+	limitsHelpMessage: `To set a limit, send the command:
+<pre>
+/limit &lt;amount&gt; [period]
+</pre>
+
+Limits may be set for separate categories as well. To do that, use the command:
+<pre>
+/limit &lt;amount&gt; [period] &lt;category&gt;
+</pre>
+
+The amount is given as <b>an integer</b>.
+The period may be one of: <b>day</b>, <b>week</b>, <b>month</b> or <b>Nd</b> (N days). By default the limit is set for a month.
+The period goes right after the amount. If the name of the category starts with a period word, give the period explicitly: <code>/limit 100 month day care</code>.
+The limit is restored when a new period starts. If you add the <b>+</b> sign to the period (e.g. <b>month+</b>), the unspent rest is carried over to the next period.
+To delete a limit, give <b>0</b> as the amount. And the <code>/limit</code> command (without parameters) shows the current limits.
+
+When the expenses reach the given share of the limit, I'll send a warning (once per period). By default these are 50%, 80% and 100%. The thresholds are changed with the command:
+<pre>
+/limit alerts 50,80,100
+</pre>
+`,
+	limitsEmptyMessage: "No limits are set yet.",
+
+	addHelpMessage:  enAddHelpMessage,
+	editHelpMessage: enEditHelpMessage,
+
+	proposalPrompt:          "Add the expense?\n%s",
+	proposalRejectedMessage: "OK, not adding it. 👌",
+	proposalExpiredMessage:  "The expense is already added or cancelled. 🤷",
+
+	dialogAmountPrompt:   "How much was spent? Send the amount, e.g. <b>350</b> or <b>12.50 EUR</b>.\nChanged your mind? Send /cancel.",
+	dialogCategoryPrompt: "What for? Choose the category or send it as a message:",
+	dialogDatePrompt:     "When? Choose the day or send the date, e.g. <b>oct 12</b> or <b>12.10.2022</b>:",
+	dialogWrongAnswer:    "Error: %s. 🙁\n\n%s",
+	dialogExpiredMessage: "This question is no longer relevant, start over with the /add command. 🤷",
+
+	cancelDoneMessage:    "OK, cancelling. 👌",
+	cancelNothingMessage: "Nothing to cancel. 🤷",
+
+	undoExpenseMessage:  "Undone: adding the expense. ↩️",
+	undoLimitMessage:    "Undone: changing the limit. ↩️",
+	undoCurrencyMessage: "Undone: switching the currency. ↩️",
+	undoNothingMessage:  "Nothing to undo: only the last action may be undone and only within a few minutes. 🤷",
+
+	editPromptMessage:      "To edit the expense, send the corrected command:",
+	expenseNotFoundMessage: "The expense is not found. 🤷",
+
+	findHelpMessage: `To find expenses by category, note or tag, send the command:
+<pre>
+/find &lt;what to find&gt; [from dd.mm.yyyy] [to dd.mm.yyyy] [&gt;amount] [&lt;amount]
+</pre>
+For example, <code>/find coffee #work from 01.10.2022 &gt;300</code>. The amounts are compared in the currency of the expense.
+The expenses found are shown page by page, the pages are turned with the ◀️ and ▶️ buttons.`,
+	findEmptyMessage:   "Nothing is found. 🤷",
+	findExpiredMessage: "The search results are out of date, repeat the /find command.",
+
+	categoriesHelpMessage: `The <code>/categories</code> command (without parameters) shows your categories along with their aliases.
+To put the expenses of one category into another, set an alias:
+<pre>
+/categories alias taxi = transport/taxi
+</pre>
+To delete an alias, don't give the category. A category (along with its subcategories, limits, recurring expenses and rules) is renamed in the whole history of expenses with the command:
+<pre>
+/categories rename &lt;category&gt; = &lt;new name&gt;
+</pre>
+And to merge two categories, use <b>merge</b> instead of <b>rename</b>: the old name becomes an alias of the new category.`,
+	categoriesEmptyMessage: "No categories yet.",
+
+	reportHelpMessage: enReportHelpMessage,
+	reportRetry:       "Cannot make the report. 🙁\nPlease try again a bit later.",
+	reportNoExpenses:  "You haven't added any expenses yet.",
+
+	exportHelpMessage: `To export expenses into a file, run the command:
+<pre>
+/export [period] [csv|xlsx]
+</pre>
+The period is given the same way as for the <code>/report</code> command, e.g. <code>/export last month xlsx</code>. Without a period all the expenses are exported, as CSV by default.`,
+	exportCaption:    "Expenses %s: %d item (amounts in %s)|Expenses %s: %d items (amounts in %s)",
+	exportRetry:      "Cannot export the expenses. 🙁\nPlease try again a bit later.",
+	exportNoExpenses: "There are no expenses for this period.",
+
+	importHelpMessage: `To import expenses from a bank statement, send the statement file as CSV or OFX.
+I'll try to recognize the format of a CSV statement myself. If I can't, give the name of the bank in the caption of the file.
+Incoming payments are not imported, and the expenses imported before are not added again.
+
+` + enRuleHelpMessage,
+	importDoneMessage:     "Expenses in the statement: %d.\nAdded: %d, imported before: %d.",
+	importNoExpenses:      "There are no expenses in the statement.",
+	importTooLargeMessage: "The statement file must be at most %d KB.",
+	importRetry:           "Cannot import the statement. 🙁\nPlease try again a bit later.",
+
+	attachmentDoneMessage:     "The photo is attached to the expense. 📎",
+	attachmentTooLargeMessage: "The photo must be at most %d KB.",
+	attachmentNotFoundMessage: "No photo is attached to the expense. 🤷",
+	attachmentRetry:           "Cannot save the photo. 🙁\nPlease try again a bit later.",
+
+	ruleHelpMessage:     enRuleHelpMessage,
+	ruleEmptyMessage:    "No category rules are set yet.",
+	ruleNotFoundMessage: "The rule is not found. 🤷",
+
+	receiptHelpMessage: `To add an expense by a receipt, send a photo of the QR code on the receipt or the text it contains (QR scanner apps show it):
+<pre>
+t=20221021T1530&amp;s=1234.50&amp;fn=...&amp;i=...&amp;fp=...&amp;n=1
+</pre>
+The text may be sent with the <code>/add</code> command as well. The category of the expense may be given after the text (or in the caption of the photo), otherwise I'll offer to choose it.
+The same receipt is never added twice.`,
+	receiptCategoryPrompt:    "Receipt of %s for %.2f %s.\nChoose the category of the expense or send it in reply to this message:",
+	receiptCategoryMissing:   "Send the category of the expense on the receipt in reply to the message with the receipt.",
+	receiptDuplicateMessage:  "This receipt is already added. 🧾",
+	receiptNotExpenseMessage: "This is a refund receipt, no expenses are added by it.",
+	receiptExpiredMessage:    "The receipt is not found, send it once again. 🤷",
+	receiptRetry:             "Cannot get the photo of the receipt. 🙁\nPlease try again a bit later.",
+
+	recurringHelpMessage: `To add a recurring expense (e.g. a rent or a subscription), send the command:
+<pre>
+/recurring add &lt;schedule&gt; [start date] &lt;amount&gt; [currency] &lt;category&gt;
+</pre>
+The schedule may be one of: <b>day</b>, <b>week</b>, <b>month</b>, <b>year</b>, <b>Nd</b>, <b>Nw</b>, <b>Nm</b>, <b>Ny</b> (every N days, weeks, months or years) or a rule like <b>FREQ=MONTHLY;INTERVAL=2</b>.
+The start date, the amount and the currency are given the same way as for the <code>/add</code> command. For example, <code>/recurring add month 05.11.2022 30000 rent</code>.
+
+The expenses are added by themselves on the scheduled days. The <code>/recurring</code> command (without parameters) shows the recurring expenses, under the list there will be buttons to pause (⏸) and resume (▶️) them.`,
+	recurringEmptyMessage:    "No recurring expenses yet.",
+	recurringPausedMessage:   "The recurring expense “%s” is paused.",
+	recurringResumedMessage:  "The recurring expense “%s” is resumed, the next one will be added on %s.",
+	recurringNotFoundMessage: "The recurring expense is not found. 🤷",
+
+	incomeHelpMessage: `To add an income, send the command:
+<pre>
+/income [date] &lt;amount&gt; [currency] &lt;category&gt;
+</pre>
+The date, the amount and the currency are given the same way as for the <code>/add</code> command. For example, <code>/income 150000 salary</code>.
+Incomes don't spend limits, and reports show them along with the expenses and the savings for the period.
+The <code>/income</code> command (without parameters) shows the latest incomes.`,
+	incomeEmptyMessage: "You haven't added any incomes yet.",
+
+	accountHelpMessage: `To add an account (e.g. a card, cash or a foreign currency deposit), send the command:
+<pre>
+/account add &lt;name&gt; [currency] [balance]
+</pre>
+For example, <code>/account add savings USD 1000</code>. Without a currency the account is in the current one.
+To charge an expense or credit an income to an account, add the name of the account with <b>#</b> to the <code>/add</code> or <code>/income</code> command: <code>/add 450 coffee #card</code>.
+The <code>/account</code> command (without parameters) shows the accounts, and <code>/balance</code> shows their balances.`,
+	accountEmptyMessage:     "No accounts yet.",
+	accountDuplicateMessage: "There is an account with this name already.",
+
+	goalsHelpMessage: `To set a savings goal, send the command:
+<pre>
+/goals add &lt;name&gt; &lt;amount&gt; [currency] [by dd.mm.yyyy]
+</pre>
+For example, <code>/goals add vacation 200000 RUB by 01.06.2023</code>. Without a currency the goal is in the current one.
+To put money aside for a goal, send <code>/goals put &lt;name&gt; &lt;amount&gt; [currency]</code> (a negative amount takes it back), and to delete a goal, send <code>/goals delete &lt;name&gt;</code>.
+The <code>/goals</code> command (without parameters) shows the progress of the goals and how much to put aside per month to make it by the deadline.`,
+	goalsEmptyMessage:    "No goals yet.",
+	goalDuplicateMessage: "There is a goal with this name already.",
+
+	groupHelpMessage: `A group brings together the expenses of several people: the members share limits and the report, and everyone adds their expenses themselves.
+<pre>
+/group create
+/group join &lt;code&gt;
+/group leave
+/group remove &lt;member&gt;
+</pre>
+The creator of the group becomes its owner: the owner sets the limits and may remove members. If the owner leaves, the group is disbanded.
+The <code>/group</code> command (without parameters) shows the members and the code to join.`,
+	groupEmptyMessage:         "You are not in a group.",
+	groupAlreadyMemberMessage: "You are in a group already. To switch to another one, leave the current one first: <code>/group leave</code>.",
+
+	debtsHelpMessage: `To split an expense equally with members of the group, add the word <b>split</b> and their names to the <code>/add</code> command:
+<pre>
+/add 3000 restaurant split @alice @bob
+</pre>
+Only your share becomes the expense, and the shares of the others become their debts to you. The members will be notified.
+An expense may be split only with members of your group (<code>/group</code>): Telegram doesn't tell the bot who is behind a @username, and joining the group is the member's consent to take on debts.
+The <code>/debts</code> command shows who owes whom (debts in the same currency are offset), and <code>/settle &lt;member&gt; [currency]</code> settles the debts with the member.`,
+	debtsEmptyMessage: "There are no open debts.",
+
+	transferHelpMessage: `To transfer money between accounts, send the command:
+<pre>
+/transfer &lt;from&gt; &lt;to&gt; &lt;amount&gt; [credited amount]
+</pre>
+The amount is given in the currency of the debited account. If the currencies of the accounts differ, the credited amount is calculated at today's rate, but it may be given explicitly too: <code>/transfer card savings 6150 100</code>.`,
+
+	doneMessage:       `Done!`,
+	limitReached:      `❗ You've run out of the limit.`,
+	limitAlertMessage: `⚠️ You've spent %d%% of the limit.`,
+
+	unknownCommandMessage: "Sorry, I don't know this command. 🙁\n\n" + enCurrencyHelpMessage + "\n" + enLangHelpMessage + "\n\n\n" + enAddHelpMessage + "\n\n\n" + enEditHelpMessage + "\n\n\n" + enReportHelpMessage,
+
+	emergencyMessage: "Sorry, the bot is temporarily out of order. 🙁\nWe are already fixing it, please come back a bit later.",
+
+	// errors
+	"%s\n\nДля справки:\n%s":                "%s\n\nFor reference:\n%s",
+	"%s\nОшибка: %s.\n\n\nДля справки:\n%s": "%s\nError: %s.\n\n\nFor reference:\n%s",
+
+	errWrongExpenseDate.Error():    "cannot recognize the date",
+	errWrongExpenseAmount.Error():  "cannot recognize the amount",
+	errAmbiguousPhrase.Error():     "several amounts or dates are given",
+	errInvalidExpenseDate.Error():  "the date is invalid",
+	errNoExpenseCategory.Error():   "the category of the expense is missing",
+	errWrongReportDuration.Error(): "cannot recognize the period of the report",
+	errWrongReportRange.Error():    "the period must start no later than it ends",
+	errWrongExpenseID.Error():      "cannot recognize the number of the expense",
+	errUnknownCurrency.Error():     "this currency is not supported",
+	errUnknownAccount.Error():      "there is no such account",
+	errUnknownCategory.Error():     "there is no such category",
+	errUnknownAlias.Error():        "there is no such alias",
+	errWrongAlias.Error():          "the alias must differ from the category",
+	errWrongCategoryRename.Error(): "a category cannot be renamed into itself or its subcategory",
+	errCategoryExists.Error():      "this category exists already, use merge to merge them",
+	errEmptySearch.Error():         "what to find is missing",
+	errGroupOwnerOnly.Error():      "only the owner of the group sets its limits",
+	errGroupOwnerRemoves.Error():   "only the owner of the group may remove members",
+	errUnknownGroup.Error():        "there is no group with this code",
+	errUnknownGroupMember.Error():  "there is no such member in the group",
+	errUnknownSplitMember.Error():  "an expense may be split only with members of your group",
+	errUnknownDebt.Error():         "there are no open debts with this member",
+	errUnknownGoal.Error():         "there is no such goal",
+	errWrongGoalDeadline.Error():   "cannot recognize the deadline of the goal",
+	errWrongTransfer.Error():       "money may be transferred only between different accounts and in a non-zero amount",
+	errWrongLimitAmount.Error():    "cannot recognize the amount of the limit",
+	errWrongLimitPeriod.Error():    "cannot recognize the period of the limit",
+	errWrongLimitAlerts.Error():    "the alert thresholds must be numbers from 1 to 100",
+	errNonPositiveAmount.Error():   "the amount must be a positive number",
+	errFutureDate.Error():          "dates in the future are not supported",
+	errEmptyName.Error():           "the name must not be empty",
+	errNoExchangeRate.Error():      "cannot convert the currency",
+	errUnknownLanguage.Error():     "this language is not supported",
+	errWrongRecurrence.Error():     "cannot recognize the schedule",
+	errWrongRecurringID.Error():    "cannot recognize the number of the recurring expense",
+	errUnknownSubcommand.Error():   "unknown command",
+
+	"Выписка содержит ошибки.":               "The statement contains errors.",
+	"Не удалось вступить в группу.":          "Cannot join the group.",
+	"Не удалось выгрузить расходы.":          "Cannot export the expenses.",
+	"Не удалось выполнить перевод.":          "Cannot make the transfer.",
+	"Не удалось добавить доход.":             "Cannot add the income.",
+	"Не удалось добавить расход.":            "Cannot add the expense.",
+	"Не удалось добавить регулярный расход.": "Cannot add the recurring expense.",
+	"Не удалось добавить счёт.":              "Cannot add the account.",
+	"Не удалось добавить цель.":              "Cannot add the goal.",
+	"Не удалось задать лимит.":               "Cannot set the limit.",
+	"Не удалось задать правило.":             "Cannot set the rule.",
+	"Не удалось задать синоним.":             "Cannot set the alias.",
+	"Не удалось закрыть долг.":               "Cannot settle the debt.",
+	"Не удалось изменить категории.":         "Cannot change the categories.",
+	"Не удалось изменить расход.":            "Cannot edit the expense.",
+	"Не удалось изменить регулярный расход.": "Cannot change the recurring expense.",
+	"Не удалось исключить участника.":        "Cannot remove the member.",
+	"Не удалось найти QR-код на фото.":       "Cannot find a QR code in the photo.",
+	"Не удалось найти расходы.":              "Cannot find expenses.",
+	"Не удалось настроить уведомления.":      "Cannot set up the alerts.",
+	"Не удалось отменить действие.":          "Cannot undo the action.",
+	"Не удалось переименовать категорию.":    "Cannot rename the category.",
+	"Не удалось получить фото.":              "Cannot get the photo.",
+	"Не удалось пополнить цель.":             "Cannot put money aside for the goal.",
+	"Не удалось распознать выписку.":         "Cannot recognize the statement.",
+	"Не удалось распознать чек.":             "Cannot recognize the receipt.",
+	"Не удалось сменить текущую валюту.":     "Cannot change the current currency.",
+	"Не удалось сменить язык.":               "Cannot change the language.",
+	"Не удалось сформировать отчёт.":         "Cannot make the report.",
+	"Не удалось удалить расход.":             "Cannot delete the expense.",
+	"Не удалось удалить синоним.":            "Cannot delete the alias.",
+	"Не удалось удалить цель.":               "Cannot delete the goal.",
+	"Неизвестная команда.":                   "Unknown command.",
+
+	// buttons
+	"Добавить":  "Add",
+	"Отмена":    "Cancel",
+	"Отменить":  "Undo",
+	"Сегодня":   "Today",
+	"Вчера":     "Yesterday",
+	"Позавчера": "The day before yesterday",
+
+	// lists
+	"Категории:":                       "Categories:",
+	"Последние расходы:":               "Latest expenses:",
+	"Последние доходы:":                "Latest incomes:",
+	"Правила категорий:":               "Category rules:",
+	"Регулярные расходы:":              "Recurring expenses:",
+	"Найденные расходы (страница %d):": "Expenses found (page %d):",
+	"Счета:": "Accounts:",
+	"Балансы счетов (валюта — %s):": "Account balances (currency — %s):",
+	"Всего: %.2f %s":                "Total: %.2f %s",
+	"Готово! С #%s списано %.2f %s, на #%s зачислено %.2f %s.": "Done! %[2].2f %[3]s debited from #%[1]s, %[5].2f %[6]s credited to #%[4]s.",
+	"Цели:":              "Goals:",
+	"Цель достигнута! 🎉": "The goal is reached! 🎉",
+	"Срок (%s) истёк, осталось накопить %.2f %s.":       "The deadline (%s) has passed, %.2f %s is left to save.",
+	"Чтобы успеть к %s, откладывай по %.2f %s в месяц.": "To make it by %s, put aside %.2f %s per month.",
+	"%.2f %s — %s — %s, следующий %s":                   "%.2f %s — %s — %s, next on %s",
+	"приостановлен":                                     "paused",
+
+	// limits
+	"Твои лимиты (осталось/всего):": "Your limits (left/total):",
+	"Общий лимит (осталось/всего):": "Group limit (left/total):",
+	"Уведомления о расходе лимита:": "Limit alerts:",
+	"на день":   "per day",
+	"на неделю": "per week",
+	"на месяц":  "per month",
+	"на %d день|на %d дня|на %d дней": "per %d day|per %d days",
+	"с %s":                "since %s",
+	"с переносом остатка": "with rollover",
+
+	// groups and debts
+	"владелец": "owner",
+	"участник": "member",
+	"Группа (код для вступления — <code>%s</code>):":                                           "Group (the code to join is <code>%s</code>):",
+	"Группа создана! Чтобы присоединиться к ней, отправь команду <code>/group join %s</code>.": "The group is created! To join it, send the command <code>/group join %s</code>.",
+	"Доли участников:":                        "Shares of the members:",
+	"Тебе должны:":                            "You are owed:",
+	"Ты должен:":                              "You owe:",
+	"%s должен тебе %.2f %s":                  "%s owes you %.2f %s",
+	"ты должен %s %.2f %s":                    "you owe %s %.2f %s",
+	"Готово! Закрыты долги:":                  "Done! The debts are settled:",
+	"%s отметил закрытым долг между вами: %s": "%s marked the debt between you as settled: %s",
+	"%s разделил с тобой расход «%s»: твоя доля — %.2f %s.\n\nДолги можно посмотреть командой <code>/debts</code>.": "%s split the expense “%s” with you: your share is %.2f %s.\n\nSee the debts with the <code>/debts</code> command.",
+
+	// recurrences
+	"каждый день":   "every day",
+	"каждую неделю": "every week",
+	"каждый месяц":  "every month",
+	"каждый год":    "every year",
+	"каждый %d день|каждые %d дня|каждые %d дней":        "every %d day|every %d days",
+	"каждую %d неделю|каждые %d недели|каждые %d недель": "every %d week|every %d weeks",
+	"каждый %d месяц|каждые %d месяца|каждые %d месяцев": "every %d month|every %d months",
+	"каждый %d год|каждые %d года|каждые %d лет":         "every %d year|every %d years",
+
+	// reports
+	"за всё время":  "for all time",
+	"с %s по %s":    "from %s to %s",
+	"по дням":       "by day",
+	"по неделям":    "by week",
+	"по месяцам":    "by month",
+	"Расходы %s:":   "Expenses %s:",
+	"Расходы %s %s": "Expenses %s %s",
+	"Расходы с %s по %s (валюта — %s):\n":                        "Expenses from %s to %s (currency — %s):\n",
+	"Расходы %s с %s по %s (валюта — %s):\n":                     "Expenses %s from %s to %s (currency — %s):\n",
+	"Расходы с %s по %s в сравнении с %s по %s (валюта — %s):\n": "Expenses from %s to %s compared with %s to %s (currency — %s):\n",
+	"с %s по %s (валюта — %s)":                                   "from %s to %s (currency — %s)",
+	"(показана последняя %d строка)|(показаны последние %d строки)|(показаны последние %d строк)": "(the last %d row is shown)|(the last %d rows are shown)",
+	"\nВсего доходов: %.2f\nВсего расходов: %.2f\nСбережения: %.2f\n":                             "\nTotal incomes: %.2f\nTotal expenses: %.2f\nSavings: %.2f\n",
+	"Расходы участников группы:":                                                                  "Expenses of the group members:",
+	"Новые категории:":     "New categories:",
+	"Пропавшие категории:": "Missing categories:",
+	"Доходы:":              "Incomes:",
+	"прочее":               "other",
+	"остальные расходы":    "other expenses",
+	"итого":                "total",
+	"доход":                "income",
+	"доходы":               "incomes",
+	"сбер.":                "saved",
+	"сбережения":           "savings",
+	"было":                 "before",
+	"стало":                "after",
+}
@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const (
+	_proposalAccept = "✅"
+	_proposalReject = "❌"
+)
+
+// proposal is the expense guessed from the message, it's added once the user confirms it.
+type proposal struct {
+	Request request.AddExpense   `json:"request"`
+	Photo   []tgbotapi.PhotoSize `json:"photo,omitempty"` // attached along with the expense
+}
+
+// handleText proposes the expense described by a message without a command, as the message may not be meant
+// as an expense at all. It reports false when the message describes no expense.
+func (c *client) handleText(ctx context.Context, user *types.User, text string) (string, [][][]string, bool) {
+	req, _, err := parseAddRequest(text, time.Now(), c.controller.IsCurrencyAvailable)
+	if err != nil {
+		return "", nil, false
+	}
+
+	req.User = user
+
+	proposalText, keyboard := c.proposeExpense(ctx, user, proposal{
+		Request: req,
+	})
+
+	return proposalText, keyboard, true
+}
+
+// proposeExpense keeps the proposal until it's confirmed, the next proposal of the user replaces it.
+func (c *client) proposeExpense(ctx context.Context, user *types.User, p proposal) (string, [][][]string) {
+	c.saveState(ctx, user, _stateProposal, p)
+
+	amount := fmt.Sprintf("%.2f", float64(p.Request.Amount)/10000)
+	if p.Request.Currency != "" {
+		amount += " " + p.Request.Currency
+	}
+
+	text := fmt.Sprintf("%s — %s — %s", p.Request.Date.Format("02.01.2006"), amount, html.EscapeString(p.Request.Category))
+	if p.Request.Note != "" {
+		text += fmt.Sprintf(" — «%s»", html.EscapeString(p.Request.Note))
+	}
+	for _, tag := range p.Request.Tags {
+		text += " #" + html.EscapeString(tag)
+	}
+
+	return trf(ctx, proposalPrompt, text), [][][]string{{
+		{_proposalAccept + " " + tr(ctx, "Добавить"), _callbackConfirmExpense + _callbackSeparator + _proposalAccept},
+		{_proposalReject + " " + tr(ctx, "Отмена"), _callbackConfirmExpense + _callbackSeparator + _proposalReject},
+	}}
+}
+
+func (c *client) handleConfirmExpenseCallback(ctx context.Context, user *types.User, args string) (string, int64, bool) {
+	var p proposal
+	if !c.loadState(ctx, user, _stateProposal, &p) {
+		return tr(ctx, proposalExpiredMessage), 0, false
+	}
+
+	c.deleteState(ctx, user, _stateProposal)
+
+	if args != _proposalAccept {
+		return tr(ctx, proposalRejectedMessage), 0, true
+	}
+
+	p.Request.User = user
+
+	text, a := c.addExpense(ctx, p.Request)
+	if a.expenseID != 0 && len(p.Photo) > 0 {
+		text += "\n\n" + c.attachPhoto(ctx, user, a.expenseID, p.Photo)
+	}
+
+	return text, a.operation, a.expenseID != 0
+}
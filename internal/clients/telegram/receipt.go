@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/receipt"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	_callbackReceiptCategory = "receipt"
+	_callbackDataLimit       = 64 // bytes of callback data Telegram accepts
+
+	_receiptCurrency = "RUB" // fiscal receipts are issued in roubles only
+)
+
+// pendingReceipt is the receipt waiting for the category, which comes with the button or in reply to the prompt.
+type pendingReceipt struct {
+	Receipt   receipt.Receipt `json:"receipt"`
+	MessageID int             `json:"message_id"` // the prompt to choose the category
+}
+
+// handleReceipt handles receipt QR payloads sent as text or with /add, photos of receipt QR codes and
+// categories of receipts sent in reply to the prompt. It returns the receipt to prompt for the category if any,
+// and reports false when the message is none of these. Any other command drops the receipt waiting for the category.
+func (c *client) handleReceipt(ctx context.Context, user *types.User, message *tgbotapi.Message) (string, [][][]string, *receipt.Receipt, bool) {
+	var payload, category string
+
+	switch command := message.Command(); {
+	case len(message.Photo) > 0:
+		// photo sizes are sorted, the largest one is the most likely to be decoded
+		data, err := c.downloadFile(ctx, message.Photo[len(message.Photo)-1].FileID, c.maxImportSize)
+		if err != nil {
+			c.logger.Error("cannot download receipt photo", zap.Error(err))
+			return tr(ctx, receiptRetry), nil, nil, true
+		}
+
+		if payload, err = receipt.DecodeQR(data); err != nil {
+			return errorMessage(ctx, nil, "Не удалось найти QR-код на фото.", receiptHelpMessage), nil, nil, true
+		}
+
+		category = strings.TrimSpace(message.Caption)
+
+	case command == "add" && receipt.IsReceipt(message.CommandArguments()):
+		payload, category, _ = strings.Cut(strings.TrimSpace(message.CommandArguments()), " ")
+
+	case command == "" && receipt.IsReceipt(message.Text):
+		payload, category, _ = strings.Cut(strings.TrimSpace(message.Text), " ")
+
+	case command == "":
+		if message.ReplyToMessage == nil {
+			return "", nil, nil, false
+		}
+
+		var pending pendingReceipt
+		if !c.loadState(ctx, user, _stateReceipt, &pending) || pending.MessageID != message.ReplyToMessage.MessageID {
+			return "", nil, nil, false
+		}
+
+		text, operation := c.addReceiptExpense(ctx, user, pending.Receipt, strings.TrimSpace(message.Text))
+		return text, undoKeyboard(ctx, text, operation), nil, true
+
+	default:
+		c.deleteState(ctx, user, _stateReceipt)
+		return "", nil, nil, false
+	}
+
+	r, err := receipt.Parse(payload)
+	if err != nil {
+		if errors.Is(err, receipt.ErrNotExpense) {
+			return tr(ctx, receiptNotExpenseMessage), nil, nil, true
+		}
+
+		return errorMessage(ctx, nil, "Не удалось распознать чек.", receiptHelpMessage), nil, nil, true
+	}
+
+	if category = strings.TrimSpace(category); category != "" {
+		text, operation := c.addReceiptExpense(ctx, user, r, category)
+		return text, undoKeyboard(ctx, text, operation), nil, true
+	}
+
+	return trf(ctx, receiptCategoryPrompt, r.Date.Format("02.01.2006"), float64(r.Amount)/10000, _receiptCurrency), c.prepareCategoriesKeyboard(ctx, user, _callbackReceiptCategory), &r, true
+}
+
+// sendReceiptPrompt sends the prompt to choose the category of the receipt and keeps the receipt waiting for it.
+func (c *client) sendReceiptPrompt(ctx context.Context, chatID int64, user *types.User, text string, keyboard [][][]string, r receipt.Receipt) {
+	message := tgbotapi.NewMessage(chatID, text)
+	message.ParseMode = tgbotapi.ModeHTML
+	if len(keyboard) != 0 {
+		message.ReplyMarkup = c.inlineKeyboard(keyboard)
+	}
+
+	sent, err := c.api.Send(message)
+	if err != nil {
+		c.logger.Error("cannot send telegram message (with inline keyboard)", zap.Error(err))
+		return
+	}
+
+	c.saveState(ctx, user, _stateReceipt, pendingReceipt{
+		Receipt:   r,
+		MessageID: sent.MessageID,
+	})
+}
+
+func (c *client) handleReceiptCallback(ctx context.Context, user *types.User, category string) (string, int64, bool) {
+	var pending pendingReceipt
+	if !c.loadState(ctx, user, _stateReceipt, &pending) {
+		return tr(ctx, receiptExpiredMessage), 0, false
+	}
+
+	text, operation := c.addReceiptExpense(ctx, user, pending.Receipt, category)
+	return text, operation, true
+}
+
+// addReceiptExpense adds the expense of the receipt and returns the journal entry to undo it, the receipt stops
+// waiting for the category unless it has to be retried.
+func (c *client) addReceiptExpense(ctx context.Context, user *types.User, r receipt.Receipt, category string) (string, int64) {
+	if category == "" {
+		return tr(ctx, receiptCategoryMissing), 0
+	}
+
+	resp := c.controller.AddExpense(ctx, request.AddExpense{
+		User:     user,
+		Date:     r.Date,
+		Amount:   r.Amount,
+		Currency: _receiptCurrency,
+		Category: category,
+		Receipt:  &r.ID,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage), 0
+
+	case !resp.Success && !resp.Duplicate && !resp.UnknownCurrency:
+		return tr(ctx, emergencyMessage), 0
+	}
+
+	c.deleteState(ctx, user, _stateReceipt)
+
+	switch {
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось добавить расход.", receiptHelpMessage), 0
+
+	case resp.Duplicate:
+		return tr(ctx, receiptDuplicateMessage), 0
+
+	case resp.LimitAlert > 0:
+		return tr(ctx, doneMessage) + "\n\n" + renderLimitAlert(ctx, resp.LimitAlert), resp.Operation
+	}
+
+	return tr(ctx, doneMessage), resp.Operation
+}
+
+// prepareCategoriesKeyboard offers categories of the latest expenses, the buttons send the category with the callback.
+func (c *client) prepareCategoriesKeyboard(ctx context.Context, user *types.User, callback string) [][][]string {
+	resp := c.controller.ListExpenses(ctx, request.ListExpenses{
+		User: user,
+	})
+
+	var (
+		buttons [][]string
+		seen    = make(map[string]struct{})
+	)
+	for _, item := range resp.List {
+		data := callback + _callbackSeparator + item.Category
+		if _, ok := seen[item.Category]; ok || len(data) > _callbackDataLimit {
+			continue
+		}
+		seen[item.Category] = struct{}{}
+
+		buttons = append(buttons, []string{item.Category, data})
+	}
+
+	if len(buttons) == 0 {
+		return nil
+	}
+
+	return splitKeyboard(buttons)
+}
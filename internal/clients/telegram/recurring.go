@@ -0,0 +1,217 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const (
+	_callbackPauseRecurring  = "recurring-pause"
+	_callbackResumeRecurring = "recurring-resume"
+)
+
+var (
+	_recurringAddRx = regexp.MustCompile(`^(?:add|добавить)\s+(\S+)\s+(.+)$`)
+	// _recurrenceRx matches a named period, N periods (Nd, Nw, Nm, Ny) or an RRULE with the FREQ and INTERVAL parts.
+	_recurrenceRx = regexp.MustCompile(`^(?:(day|week|month|year|день|неделя|месяц|год)|(\d+)([dwmy])|(?i:(?:RRULE:)?FREQ=(DAILY|WEEKLY|MONTHLY|YEARLY)(?:;INTERVAL=(\d+))?))$`)
+
+	_recurrenceFrequencies = map[string]types.RecurrenceFrequency{
+		"day":     types.FrequencyDaily,
+		"день":    types.FrequencyDaily,
+		"d":       types.FrequencyDaily,
+		"DAILY":   types.FrequencyDaily,
+		"week":    types.FrequencyWeekly,
+		"неделя":  types.FrequencyWeekly,
+		"w":       types.FrequencyWeekly,
+		"WEEKLY":  types.FrequencyWeekly,
+		"month":   types.FrequencyMonthly,
+		"месяц":   types.FrequencyMonthly,
+		"m":       types.FrequencyMonthly,
+		"MONTHLY": types.FrequencyMonthly,
+		"year":    types.FrequencyYearly,
+		"год":     types.FrequencyYearly,
+		"y":       types.FrequencyYearly,
+		"YEARLY":  types.FrequencyYearly,
+	}
+
+	// _recurrenceNames holds the names of a single period and the plural forms of multiple periods by frequency
+	_recurrenceNames = map[types.RecurrenceFrequency][2]string{
+		types.FrequencyDaily:   {"каждый день", "каждый %d день|каждые %d дня|каждые %d дней"},
+		types.FrequencyWeekly:  {"каждую неделю", "каждую %d неделю|каждые %d недели|каждые %d недель"},
+		types.FrequencyMonthly: {"каждый месяц", "каждый %d месяц|каждые %d месяца|каждые %d месяцев"},
+		types.FrequencyYearly:  {"каждый год", "каждый %d год|каждые %d года|каждые %d лет"},
+	}
+
+	errWrongRecurrence   = errors.New("не удалось определить расписание")
+	errWrongRecurringID  = errors.New("не удалось определить номер регулярного расхода")
+	errUnknownSubcommand = errors.New("неизвестная команда")
+)
+
+// handleRecurring lists the recurring expenses with the buttons pausing and resuming them,
+// or adds a new one.
+func (c *client) handleRecurring(ctx context.Context, user *types.User, args string) (string, [][][]string) {
+	if args != "" {
+		return c.handleAddRecurring(ctx, user, args), nil
+	}
+
+	resp := c.controller.ListRecurring(ctx, request.ListRecurring{
+		User: user,
+	})
+
+	switch {
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), nil
+
+	case len(resp.List) == 0:
+		return tr(ctx, recurringEmptyMessage) + "\n\n" + tr(ctx, recurringHelpMessage), nil
+	}
+
+	text := tr(ctx, "Регулярные расходы:")
+	buttons := make([][]string, 0, len(resp.List))
+	for i, item := range resp.List {
+		text += fmt.Sprintf("\n%d. %s", i+1, renderRecurringExpense(ctx, item))
+
+		id := strconv.FormatInt(item.ID, 10)
+		if item.Paused {
+			text += " — ⏸ " + tr(ctx, "приостановлен")
+			buttons = append(buttons, []string{fmt.Sprintf("▶️ %d", i+1), _callbackResumeRecurring + _callbackSeparator + id})
+		} else {
+			buttons = append(buttons, []string{fmt.Sprintf("⏸ %d", i+1), _callbackPauseRecurring + _callbackSeparator + id})
+		}
+	}
+
+	return text, splitKeyboard(buttons)
+}
+
+func (c *client) handleAddRecurring(ctx context.Context, user *types.User, args string) string {
+	m := _recurringAddRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, errUnknownSubcommand, "Не удалось добавить регулярный расход.", recurringHelpMessage)
+	}
+
+	recurrence, err := parseRecurrence(m[1])
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось добавить регулярный расход.", recurringHelpMessage)
+	}
+
+	e, err := parseExactPhrase(m[2], time.Now(), c.controller.IsCurrencyAvailable)
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось добавить регулярный расход.", recurringHelpMessage)
+	}
+
+	recurrence.Start = e.Date
+
+	resp := c.controller.AddRecurring(ctx, request.AddRecurring{
+		User:       user,
+		Recurrence: recurrence,
+		Amount:     e.Amount,
+		Currency:   e.Currency,
+		Category:   e.Category,
+	})
+
+	switch {
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось добавить регулярный расход.", recurringHelpMessage)
+
+	case resp.Invalid != nil:
+		return errorMessage(ctx, explainInputError(resp.Invalid), "Не удалось добавить регулярный расход.", recurringHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage) + "\n" + renderRecurringExpense(ctx, resp.Item)
+}
+
+func (c *client) handlePauseRecurringCallback(ctx context.Context, user *types.User, args string) (string, bool) {
+	return c.setRecurringPaused(ctx, user, args, true)
+}
+
+func (c *client) handleResumeRecurringCallback(ctx context.Context, user *types.User, args string) (string, bool) {
+	return c.setRecurringPaused(ctx, user, args, false)
+}
+
+func (c *client) setRecurringPaused(ctx context.Context, user *types.User, args string, paused bool) (string, bool) {
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		return errorMessage(ctx, errWrongRecurringID, "Не удалось изменить регулярный расход.", recurringHelpMessage), false
+	}
+
+	resp := c.controller.SetRecurringPaused(ctx, request.SetRecurringPaused{
+		User:   user,
+		ID:     id,
+		Paused: paused,
+	})
+
+	switch {
+	case resp.NotFound:
+		return tr(ctx, recurringNotFoundMessage), false
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), false
+
+	case paused:
+		return trf(ctx, recurringPausedMessage, resp.Item.Category), true
+	}
+
+	return trf(ctx, recurringResumedMessage, resp.Item.Category, resp.Item.Next.Format("02.01.2006")), true
+}
+
+// parseRecurrence parses the schedule of a recurring expense, its start is left unset.
+func parseRecurrence(input string) (types.Recurrence, error) {
+	m := _recurrenceRx.FindStringSubmatch(input)
+	if len(m) == 0 {
+		return types.Recurrence{}, errWrongRecurrence
+	}
+
+	name, interval := m[1], "1"
+	switch {
+	case m[2] != "":
+		name, interval = m[3], m[2]
+
+	case m[4] != "":
+		name = strings.ToUpper(m[4])
+		if m[5] != "" {
+			interval = m[5]
+		}
+	}
+
+	n, err := strconv.Atoi(interval)
+	if err != nil || n <= 0 {
+		return types.Recurrence{}, errWrongRecurrence
+	}
+
+	return types.Recurrence{
+		Frequency: _recurrenceFrequencies[name],
+		Interval:  n,
+	}, nil
+}
+
+func renderRecurringExpense(ctx context.Context, item types.RecurringExpense) string {
+	return trf(
+		ctx,
+		"%.2f %s — %s — %s, следующий %s",
+		float64(item.Amount)/10000,
+		item.Currency,
+		item.Category,
+		renderRecurrence(ctx, item.Recurrence),
+		item.Next.Format("02.01.2006"),
+	)
+}
+
+func renderRecurrence(ctx context.Context, r types.Recurrence) string {
+	names := _recurrenceNames[r.Frequency]
+	if r.Interval == 1 {
+		return tr(ctx, names[0])
+	}
+
+	return trn(ctx, names[1], int64(r.Interval), r.Interval)
+}
@@ -0,0 +1,409 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/chart"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/response"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const (
+	_reportTableColumns     = 3  // the most expensive categories shown in separate columns
+	_reportTableRows        = 31 // the latest buckets shown in the table
+	_reportTableColumnWidth = 8  // the longest category name shown in the table header
+)
+
+var (
+	_reportGroupingTitles = map[types.ReportGrouping]string{
+		types.GroupingDay:   "по дням",
+		types.GroupingWeek:  "по неделям",
+		types.GroupingMonth: "по месяцам",
+	}
+
+	// _chartLegendMarks match colors of chart.Palette.
+	_chartLegendMarks = []string{"🟥", "🟧", "🟨", "🟩", "🟦", "🟪", "🟫", "⬛"}
+)
+
+type photo struct {
+	name    string
+	data    []byte
+	caption string
+}
+
+// renderReportTable renders the report grouped by time buckets as a monospaced table. Every row
+// is a bucket with amounts of the most expensive categories, the rest of the categories, the total
+// and its change against the previous bucket. Incomes and savings of buckets are added when the
+// report has any income.
+func renderReportTable(ctx context.Context, resp response.GetReport) string {
+	categories := make([]string, 0, len(resp.Data))
+	for category := range resp.Data {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if resp.Data[categories[i]] == resp.Data[categories[j]] {
+			return categories[i] < categories[j]
+		}
+
+		return resp.Data[categories[i]] > resp.Data[categories[j]]
+	})
+
+	shown, others := categories, false
+	if len(categories) > _reportTableColumns {
+		shown, others = categories[:_reportTableColumns], true
+	}
+
+	header := []string{""}
+	for _, category := range shown {
+		header = append(header, truncateRunes(category, _reportTableColumnWidth))
+	}
+	if others {
+		header = append(header, tr(ctx, "прочее"))
+	}
+	header = append(header, tr(ctx, "итого"), "Δ")
+
+	withIncome := len(resp.Income) != 0
+	if withIncome {
+		header = append(header, tr(ctx, "доход"), tr(ctx, "сбер."))
+	}
+
+	rows := make([][]string, 0, len(resp.Buckets))
+	var previous int64
+	for i, bucket := range resp.Buckets {
+		row := []string{renderBucketStart(resp.Grouping, bucket)}
+
+		var total, rest int64
+		for _, amount := range bucket.Data {
+			total += amount
+		}
+
+		rest = total
+		for _, category := range shown {
+			row = append(row, renderAmount(bucket.Data[category]))
+			rest -= bucket.Data[category]
+		}
+		if others {
+			row = append(row, renderAmount(rest))
+		}
+
+		row = append(row, renderAmount(total), renderTrend(previous, total, i > 0))
+		if withIncome {
+			income := sumAmounts(bucket.Income)
+			row = append(row, renderAmount(income), renderAmount(income-total))
+		}
+
+		rows = append(rows, row)
+		previous = total
+	}
+
+	text := trf(
+		ctx,
+		"Расходы %s с %s по %s (валюта — %s):\n",
+		tr(ctx, _reportGroupingTitles[resp.Grouping]),
+		resp.From.Local().Format("02.01.2006"),
+		resp.To.AddDate(0, 0, -1).Local().Format("02.01.2006"),
+		resp.Currency,
+	)
+
+	if len(rows) > _reportTableRows {
+		rows = rows[len(rows)-_reportTableRows:]
+		text += trn(ctx, "(показана последняя %d строка)|(показаны последние %d строки)|(показаны последние %d строк)", _reportTableRows, _reportTableRows) + "\n"
+	}
+
+	return text + "<pre>" + html.EscapeString(renderTable(header, rows)) + "</pre>"
+}
+
+// renderReportComparison renders amounts per category spent within the compared and the current
+// ranges as a monospaced table with absolute and relative changes, and lists categories which
+// appeared or disappeared in the current range. Incomes and savings follow the expenses total
+// when any of the ranges has income.
+func renderReportComparison(ctx context.Context, resp response.GetReport) string {
+	categories := make([]string, 0, len(resp.Data)+len(resp.Previous))
+	for category := range resp.Data {
+		categories = append(categories, category)
+	}
+	for category := range resp.Previous {
+		if _, ok := resp.Data[category]; !ok {
+			categories = append(categories, category)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		switch {
+		case resp.Data[a] != resp.Data[b]:
+			return resp.Data[a] > resp.Data[b]
+		case resp.Previous[a] != resp.Previous[b]:
+			return resp.Previous[a] > resp.Previous[b]
+		}
+
+		return a < b
+	})
+
+	header := []string{"", tr(ctx, "было"), tr(ctx, "стало"), "Δ", "Δ%"}
+	rows := make([][]string, 0, len(categories)+1)
+
+	var appeared, disappeared []string
+	var previousTotal, currentTotal int64
+	for _, category := range categories {
+		previous, current := resp.Previous[category], resp.Data[category]
+		rows = append(rows, []string{
+			truncateRunes(category, _reportTableColumnWidth*2),
+			renderAmount(previous),
+			renderAmount(current),
+			renderDelta(previous, current),
+			renderTrend(previous, current, true),
+		})
+
+		if _, ok := resp.Previous[category]; !ok {
+			appeared = append(appeared, html.EscapeString(category))
+		} else if _, ok := resp.Data[category]; !ok {
+			disappeared = append(disappeared, html.EscapeString(category))
+		}
+
+		previousTotal += previous
+		currentTotal += current
+	}
+
+	rows = append(rows, []string{
+		tr(ctx, "итого"),
+		renderAmount(previousTotal),
+		renderAmount(currentTotal),
+		renderDelta(previousTotal, currentTotal),
+		renderTrend(previousTotal, currentTotal, true),
+	})
+
+	if len(resp.Income) != 0 || len(resp.PreviousIncome) != 0 {
+		previousIncome, currentIncome := sumAmounts(resp.PreviousIncome), sumAmounts(resp.Income)
+		previousSavings, currentSavings := previousIncome-previousTotal, currentIncome-currentTotal
+		rows = append(rows, []string{
+			tr(ctx, "доходы"),
+			renderAmount(previousIncome),
+			renderAmount(currentIncome),
+			renderDelta(previousIncome, currentIncome),
+			renderTrend(previousIncome, currentIncome, true),
+		}, []string{
+			tr(ctx, "сбережения"),
+			renderAmount(previousSavings),
+			renderAmount(currentSavings),
+			renderDelta(previousSavings, currentSavings),
+			"",
+		})
+	}
+
+	text := trf(
+		ctx,
+		"Расходы с %s по %s в сравнении с %s по %s (валюта — %s):\n",
+		resp.From.Local().Format("02.01.2006"),
+		resp.To.AddDate(0, 0, -1).Local().Format("02.01.2006"),
+		resp.Compare.From.Local().Format("02.01.2006"),
+		resp.Compare.To.AddDate(0, 0, -1).Local().Format("02.01.2006"),
+		resp.Currency,
+	)
+	text += "<pre>" + html.EscapeString(renderTable(header, rows)) + "</pre>"
+
+	if len(appeared) != 0 {
+		text += "\n" + tr(ctx, "Новые категории:") + " " + strings.Join(appeared, ", ")
+	}
+	if len(disappeared) != 0 {
+		text += "\n" + tr(ctx, "Пропавшие категории:") + " " + strings.Join(disappeared, ", ")
+	}
+
+	return text
+}
+
+// renderReportCharts renders a pie chart of category shares with a legend in its caption
+// and a bar chart of totals per time bucket.
+func renderReportCharts(ctx context.Context, resp response.GetReport) ([]photo, error) {
+	title := trf(
+		ctx,
+		"с %s по %s (валюта — %s)",
+		resp.From.Local().Format("02.01.2006"),
+		resp.To.AddDate(0, 0, -1).Local().Format("02.01.2006"),
+		resp.Currency,
+	)
+
+	shares := chart.Shares(resp.Data, tr(ctx, "прочее"))
+
+	var total int64
+	for _, share := range shares {
+		total += share.Value
+	}
+
+	legend := trf(ctx, "Расходы %s:", title) + "\n"
+	for i, share := range shares {
+		legend += fmt.Sprintf(
+			"%s %s: %.2f (%.0f%%)\n",
+			_chartLegendMarks[i%len(_chartLegendMarks)],
+			html.EscapeString(share.Label),
+			float64(share.Value)/10000,
+			float64(share.Value)/float64(total)*100,
+		)
+	}
+
+	pie := new(bytes.Buffer)
+	if err := chart.Pie(pie, shares); err != nil {
+		return nil, errors.Wrap(err, "chart.Pie")
+	}
+
+	totals := make([]chart.Value, 0, len(resp.Buckets))
+	for _, bucket := range resp.Buckets {
+		value := chart.Value{Label: renderBucketStart(resp.Grouping, bucket)}
+		for _, amount := range bucket.Data {
+			value.Value += amount
+		}
+		totals = append(totals, value)
+	}
+
+	bars := new(bytes.Buffer)
+	if err := chart.Bars(bars, totals, 10000); err != nil {
+		return nil, errors.Wrap(err, "chart.Bars")
+	}
+
+	return []photo{
+		{name: "shares.png", data: pie.Bytes(), caption: legend},
+		{name: "totals.png", data: bars.Bytes(), caption: trf(ctx, "Расходы %s %s", tr(ctx, _reportGroupingTitles[resp.Grouping]), title)},
+	}, nil
+}
+
+// renderReportBalance renders incomes per category followed by the totals of incomes, expenses
+// and savings, i.e. the difference between them. Nothing is rendered without incomes in the period.
+func renderReportBalance(ctx context.Context, resp response.GetReport) string {
+	if len(resp.Income) == 0 {
+		return ""
+	}
+
+	categories := make([]string, 0, len(resp.Income))
+	for category := range resp.Income {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	text := "\n" + tr(ctx, "Доходы:") + "\n"
+	for _, category := range categories {
+		text += fmt.Sprintf("%s: %.2f\n", html.EscapeString(category), float64(resp.Income[category])/10000)
+	}
+
+	income, expenses := sumAmounts(resp.Income), sumAmounts(resp.Data)
+	text += trf(
+		ctx,
+		"\nВсего доходов: %.2f\nВсего расходов: %.2f\nСбережения: %.2f\n",
+		float64(income)/10000,
+		float64(expenses)/10000,
+		float64(income-expenses)/10000,
+	)
+
+	return text
+}
+
+// renderReportMembers renders the amounts spent by each member of the group, nothing outside of groups.
+func renderReportMembers(ctx context.Context, resp response.GetReport) string {
+	if len(resp.Members) == 0 {
+		return ""
+	}
+
+	text := "\n" + tr(ctx, "Расходы участников группы:") + "\n"
+	for _, member := range resp.Members {
+		text += fmt.Sprintf("%s: %.2f\n", html.EscapeString(member.Name), float64(member.Amount)/10000)
+	}
+
+	return text
+}
+
+// chartGrouping picks buckets size so that the bar chart has a readable number of bars.
+func chartGrouping(from, to time.Time) types.ReportGrouping {
+	switch days := to.Sub(from).Hours() / 24; {
+	case days <= 31:
+		return types.GroupingDay
+	case days <= 26*7:
+		return types.GroupingWeek
+	}
+
+	return types.GroupingMonth
+}
+
+func renderBucketStart(grouping types.ReportGrouping, bucket types.ReportBucket) string {
+	if grouping == types.GroupingMonth {
+		return bucket.Start.Format("01.2006")
+	}
+
+	return bucket.Start.Format("02.01")
+}
+
+func sumAmounts(amounts map[string]int64) (total int64) {
+	for _, amount := range amounts {
+		total += amount
+	}
+
+	return total
+}
+
+func renderAmount(amount int64) string {
+	return fmt.Sprintf("%.0f", float64(amount)/10000)
+}
+
+func renderDelta(previous, current int64) string {
+	delta := math.Round(float64(current-previous) / 10000)
+	if delta == 0 {
+		return "0"
+	}
+
+	return fmt.Sprintf("%+.0f", delta)
+}
+
+// renderTrend renders the change of the current total against the previous one in percent.
+func renderTrend(previous, current int64, hasPrevious bool) string {
+	switch {
+	case !hasPrevious:
+		return ""
+	case previous == 0 && current == 0:
+		return "0%"
+	case previous == 0:
+		return "new"
+	}
+
+	return fmt.Sprintf("%+.0f%%", float64(current-previous)/float64(previous)*100)
+}
+
+// renderTable aligns the first column to the left and the rest of them to the right.
+func renderTable(header []string, rows [][]string) string {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if i == 0 {
+				b.WriteString(cell + padding)
+			} else {
+				b.WriteString(" " + padding + cell)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,250 @@
+//go:build unit
+
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/response"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_renderReportTable(t *testing.T) {
+	t.Run("months with others", func(t *testing.T) {
+		// ARRANGE
+		resp := response.GetReport{
+			From:     time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+			Grouping: types.GroupingMonth,
+			Currency: "RUB",
+			Data: map[string]int64{
+				"taxi":     30000000,
+				"coffee":   5000000,
+				"products": 20000000,
+				"<cinema>": 1000000,
+			},
+			Buckets: []types.ReportBucket{
+				{
+					Start: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+					Data: map[string]int64{
+						"taxi":     10000000,
+						"products": 10000000,
+					},
+				},
+				{
+					Start: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+					Data: map[string]int64{
+						"taxi":     20000000,
+						"coffee":   5000000,
+						"products": 10000000,
+						"<cinema>": 1000000,
+					},
+				},
+			},
+		}
+
+		// ACT
+		text := renderReportTable(context.Background(), resp)
+
+		// ASSERT
+		assert.Equal(t, "Расходы по месяцам с 01.08.2022 по 31.10.2022 (валюта — RUB):\n"+
+			"<pre>"+
+			"        taxi products coffee прочее итого     Δ\n"+
+			"08.2022 1000     1000      0      0  2000      \n"+
+			"09.2022    0        0      0      0     0 -100%\n"+
+			"10.2022 2000     1000    500    100  3600   new"+
+			"</pre>", text)
+	})
+
+	t.Run("with incomes", func(t *testing.T) {
+		// ARRANGE
+		resp := response.GetReport{
+			From:     time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+			Grouping: types.GroupingMonth,
+			Currency: "RUB",
+			Data:     map[string]int64{"rent": 600000000},
+			Income:   map[string]int64{"salary": 1500000000},
+			Buckets: []types.ReportBucket{
+				{
+					Start:  time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+					Data:   map[string]int64{"rent": 300000000},
+					Income: map[string]int64{"salary": 1500000000},
+				},
+				{
+					Start: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+					Data:  map[string]int64{"rent": 300000000},
+				},
+			},
+		}
+
+		// ACT
+		text := renderReportTable(context.Background(), resp)
+
+		// ASSERT
+		assert.Equal(t, "Расходы по месяцам с 01.09.2022 по 31.10.2022 (валюта — RUB):\n"+
+			"<pre>"+
+			"         rent итого   Δ  доход  сбер.\n"+
+			"09.2022 30000 30000     150000 120000\n"+
+			"10.2022 30000 30000 +0%      0 -30000"+
+			"</pre>", text)
+	})
+
+	t.Run("escaped and truncated categories", func(t *testing.T) {
+		// ARRANGE
+		resp := response.GetReport{
+			From:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC),
+			Grouping: types.GroupingDay,
+			Currency: "USD",
+			Data: map[string]int64{
+				"<restaurants>": 300000,
+			},
+			Buckets: []types.ReportBucket{
+				{
+					Start: time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+					Data:  map[string]int64{"<restaurants>": 200000},
+				},
+				{
+					Start: time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+					Data:  map[string]int64{"<restaurants>": 100000},
+				},
+			},
+		}
+
+		// ACT
+		text := renderReportTable(context.Background(), resp)
+
+		// ASSERT
+		assert.Equal(t, "Расходы по дням с 20.10.2022 по 21.10.2022 (валюта — USD):\n"+
+			"<pre>"+
+			"      &lt;restau… итого    Δ\n"+
+			"20.10       20    20     \n"+
+			"21.10       10    10 -50%"+
+			"</pre>", text)
+	})
+}
+
+func Test_renderReportComparison(t *testing.T) {
+	// ARRANGE
+	resp := response.GetReport{
+		From:     time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+		Compare:  types.DateRange{From: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)},
+		Currency: "RUB",
+		Data: map[string]int64{
+			"taxi":     15000000,
+			"coffee":   5000000,
+			"<cinema>": 1000000,
+		},
+		Previous: map[string]int64{
+			"taxi":     10000000,
+			"coffee":   5000000,
+			"products": 20000000,
+		},
+	}
+
+	// ACT
+	text := renderReportComparison(context.Background(), resp)
+
+	// ASSERT
+	assert.Equal(t, "Расходы с 01.10.2022 по 31.10.2022 в сравнении с 01.09.2022 по 30.09.2022 (валюта — RUB):\n"+
+		"<pre>"+
+		"         было стало     Δ    Δ%\n"+
+		"taxi     1000  1500  +500  +50%\n"+
+		"coffee    500   500     0   +0%\n"+
+		"&lt;cinema&gt;    0   100  +100   new\n"+
+		"products 2000     0 -2000 -100%\n"+
+		"итого    3500  2100 -1400  -40%"+
+		"</pre>\n"+
+		"Новые категории: &lt;cinema&gt;\n"+
+		"Пропавшие категории: products", text)
+}
+
+func Test_renderReportComparison_incomes(t *testing.T) {
+	// ARRANGE
+	resp := response.GetReport{
+		From:           time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+		Compare:        types.DateRange{From: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)},
+		Currency:       "RUB",
+		Data:           map[string]int64{"rent": 300000000},
+		Previous:       map[string]int64{"rent": 300000000},
+		Income:         map[string]int64{"salary": 1500000000},
+		PreviousIncome: map[string]int64{"salary": 1000000000},
+	}
+
+	// ACT
+	text := renderReportComparison(context.Background(), resp)
+
+	// ASSERT
+	assert.Equal(t, "Расходы с 01.10.2022 по 31.10.2022 в сравнении с 01.09.2022 по 30.09.2022 (валюта — RUB):\n"+
+		"<pre>"+
+		"             было  стало      Δ   Δ%\n"+
+		"rent        30000  30000      0  +0%\n"+
+		"итого       30000  30000      0  +0%\n"+
+		"доходы     100000 150000 +50000 +50%\n"+
+		"сбережения  70000 120000 +50000     "+
+		"</pre>", text)
+}
+
+func Test_reportComparedRanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		period       string
+		today        time.Time
+		wantCurrent  types.DateRange
+		wantPrevious types.DateRange
+	}{
+		{
+			name:         "middle of the week",
+			period:       "w",
+			today:        date(2022, 10, 19),
+			wantCurrent:  types.DateRange{From: date(2022, 10, 17), To: date(2022, 10, 20)},
+			wantPrevious: types.DateRange{From: date(2022, 10, 10), To: date(2022, 10, 13)},
+		},
+		{
+			name:         "middle of the month",
+			period:       "m",
+			today:        date(2022, 10, 21),
+			wantCurrent:  types.DateRange{From: date(2022, 10, 1), To: date(2022, 10, 22)},
+			wantPrevious: types.DateRange{From: date(2022, 9, 1), To: date(2022, 9, 22)},
+		},
+		{
+			name:         "previous month is shorter",
+			period:       "m",
+			today:        date(2022, 3, 31),
+			wantCurrent:  types.DateRange{From: date(2022, 3, 1), To: date(2022, 4, 1)},
+			wantPrevious: types.DateRange{From: date(2022, 2, 1), To: date(2022, 3, 1)},
+		},
+		{
+			name:         "year to date",
+			period:       "y",
+			today:        date(2022, 2, 10),
+			wantCurrent:  types.DateRange{From: date(2022, 1, 1), To: date(2022, 2, 11)},
+			wantPrevious: types.DateRange{From: date(2021, 1, 1), To: date(2021, 2, 11)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			current, previous := _reportComparedRanges[tt.period](tt.today)
+
+			// ASSERT
+			assert.Equal(t, tt.wantCurrent, current)
+			assert.Equal(t, tt.wantPrevious, previous)
+		})
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
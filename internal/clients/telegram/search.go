@@ -0,0 +1,194 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+var (
+	// _noteRx matches the quoted note of /add, typographic quotes are accepted as well.
+	_noteRx = regexp.MustCompile(`(?:^|\s)(?:"([^"]*)"|«([^»]*)»|“([^”]*)”)`)
+	// _tagRx matches #tags of /add and /find.
+	_tagRx = regexp.MustCompile(`(?:^|\s)#(\S+)`)
+)
+
+// cutNote cuts the first quoted note out of the command arguments.
+func cutNote(args string) (string, string) {
+	m := _noteRx.FindStringSubmatchIndex(args)
+	if m == nil {
+		return args, ""
+	}
+
+	var note string
+	for i := 2; i < len(m); i += 2 {
+		if m[i] >= 0 {
+			note = args[m[i]:m[i+1]]
+		}
+	}
+
+	return strings.TrimSpace(args[:m[0]] + " " + args[m[1]:]), strings.TrimSpace(note)
+}
+
+// cutTags cuts all #tags out of the command arguments and returns them lowercased without repeats.
+func cutTags(args string) (string, []string) {
+	var tags []string
+	for _, m := range _tagRx.FindAllStringSubmatch(args, -1) {
+		tag := strings.ToLower(m[1])
+		if !hasString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) == 0 {
+		return args, nil
+	}
+
+	return strings.Join(strings.Fields(_tagRx.ReplaceAllString(args, " ")), " "), tags
+}
+
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleFind searches the expenses and shows the first page of the found ones,
+// the filter is kept to switch the pages by the keyboard.
+func (c *client) handleFind(ctx context.Context, user *types.User, args string) (string, [][][]string) {
+	if args == "" {
+		return tr(ctx, findHelpMessage), nil
+	}
+
+	filter, err := parseFindArgs(args)
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось найти расходы.", findHelpMessage), nil
+	}
+
+	c.saveState(ctx, user, _stateSearch, filter)
+
+	return c.findExpenses(ctx, user, filter, 0)
+}
+
+func (c *client) handleFindCallback(ctx context.Context, user *types.User, args string) (string, [][][]string) {
+	page, err := strconv.Atoi(args)
+	if err != nil || page < 0 {
+		return errorMessage(ctx, nil, "Не удалось найти расходы.", findHelpMessage), nil
+	}
+
+	var filter types.ExpenseFilter
+	if !c.loadState(ctx, user, _stateSearch, &filter) {
+		return tr(ctx, findExpiredMessage), nil
+	}
+
+	return c.findExpenses(ctx, user, filter, page)
+}
+
+func (c *client) findExpenses(ctx context.Context, user *types.User, filter types.ExpenseFilter, page int) (string, [][][]string) {
+	resp := c.controller.FindExpenses(ctx, request.FindExpenses{
+		User:   user,
+		Filter: filter,
+		Page:   page,
+	})
+
+	switch {
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), nil
+
+	case len(resp.List) == 0 && resp.Page == 0:
+		return tr(ctx, findEmptyMessage), nil
+	}
+
+	text := trf(ctx, "Найденные расходы (страница %d):", resp.Page+1)
+	for _, item := range resp.List {
+		text += "\n" + renderExpense(item)
+	}
+
+	var buttons [][]string
+	if resp.Page > 0 {
+		buttons = append(buttons, []string{"◀️", _callbackFindExpenses + _callbackSeparator + strconv.Itoa(resp.Page-1)})
+	}
+	if resp.More {
+		buttons = append(buttons, []string{"▶️", _callbackFindExpenses + _callbackSeparator + strconv.Itoa(resp.Page+1)})
+	}
+
+	if len(buttons) == 0 {
+		return text, nil
+	}
+
+	return text, [][][]string{buttons}
+}
+
+// parseFindArgs parses the words to look for along with the optional filters:
+// from/с and to/по followed by dates, and >N or <N bounding amounts.
+func parseFindArgs(args string) (filter types.ExpenseFilter, err error) {
+	var words []string
+
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		field := strings.ToLower(fields[i])
+
+		switch {
+		case (field == "from" || field == "с") && i+1 < len(fields):
+			i++
+			if filter.From, err = time.Parse("02.01.2006", fields[i]); err != nil {
+				return types.ExpenseFilter{}, errWrongExpenseDate
+			}
+
+		case (field == "to" || field == "по") && i+1 < len(fields):
+			i++
+			if filter.To, err = time.Parse("02.01.2006", fields[i]); err != nil {
+				return types.ExpenseFilter{}, errWrongExpenseDate
+			}
+			filter.To = filter.To.AddDate(0, 0, 1)
+
+		case len(field) > 1 && field[0] == '>':
+			if filter.Min, err = parseAmount(field[1:]); err != nil {
+				return types.ExpenseFilter{}, err
+			}
+
+		case len(field) > 1 && field[0] == '<':
+			if filter.Max, err = parseAmount(field[1:]); err != nil {
+				return types.ExpenseFilter{}, err
+			}
+
+		default:
+			words = append(words, strings.TrimPrefix(field, "#"))
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return types.ExpenseFilter{}, errWrongReportRange
+	}
+
+	filter.Query = strings.Join(words, " ")
+	if filter == (types.ExpenseFilter{}) {
+		return types.ExpenseFilter{}, errEmptySearch
+	}
+
+	return filter, nil
+}
+
+// renderExpense renders the expense as a line of a list along with its note and tags.
+func renderExpense(item types.Expense) string {
+	text := fmt.Sprintf("%s — %.2f %s — %s", item.Date.Format("02.01.2006"), float64(item.Amount)/10000, item.Currency, html.EscapeString(item.Category))
+	if item.Note != "" {
+		text += fmt.Sprintf(" — «%s»", html.EscapeString(item.Note))
+	}
+	for _, tag := range item.Tags {
+		text += " #" + html.EscapeString(tag)
+	}
+
+	return text
+}
@@ -0,0 +1,65 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+// Keys of the state kept between messages of the user.
+const (
+	// _stateReceipt is the receipt waiting for the category
+	_stateReceipt = "receipt"
+	// _stateSearch is the last search filter, so that pages of the found expenses are switched by callbacks
+	// which data is too short for the filter itself
+	_stateSearch = "search"
+	// _stateConfirmation is the last confirmation of an added expense, so that a photo sent in reply to it
+	// is attached to the expense
+	_stateConfirmation = "confirmation"
+	// _stateProposal is the expense guessed from a free-form message waiting for the confirmation
+	_stateProposal = "proposal"
+	// _stateDialog is the unfinished dialog
+	_stateDialog = "dialog"
+)
+
+// loadState decodes the state kept under the key into v and reports whether there's one. Failures are logged
+// and taken as no state, so that the user just starts over.
+func (c *client) loadState(ctx context.Context, user *types.User, key string, v interface{}) bool {
+	data, ok, err := c.dialogs.Get(ctx, user, key)
+	if err != nil {
+		c.logger.Error("cannot get dialog state", zap.Error(err), zap.String("key", key))
+		return false
+	}
+
+	if !ok {
+		return false
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		c.logger.Error("cannot decode dialog state", zap.Error(err), zap.String("key", key))
+		return false
+	}
+
+	return true
+}
+
+// saveState keeps the state under the key for the TTL since now.
+func (c *client) saveState(ctx context.Context, user *types.User, key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.logger.Error("cannot encode dialog state", zap.Error(err), zap.String("key", key))
+		return
+	}
+
+	if err = c.dialogs.Set(ctx, user, key, data, c.dialogTTL); err != nil {
+		c.logger.Error("cannot set dialog state", zap.Error(err), zap.String("key", key))
+	}
+}
+
+func (c *client) deleteState(ctx context.Context, user *types.User, key string) {
+	if err := c.dialogs.Delete(ctx, user, key); err != nil {
+		c.logger.Error("cannot delete dialog state", zap.Error(err), zap.String("key", key))
+	}
+}
@@ -1,8 +1,13 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"html"
+	"io"
+	"math"
+	"net/http"
 	"regexp"
 	"sort"
 	"strconv"
@@ -12,9 +17,12 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/chart"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/response"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/i18n"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/phrase"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
@@ -24,40 +32,174 @@ import (
 const (
 	_updateTimeout = 60
 	_buttonsPerRow = 4
+
+	_callbackSeparator      = ":"
+	_callbackEditExpense    = "expense-edit"
+	_callbackDeleteExpense  = "expense-delete"
+	_callbackFindExpenses   = "expense-find"
+	_callbackAttachment     = "expense-attachment"
+	_callbackConfirmExpense = "expense-confirm"
+
+	_limitAlertsSubcommand = "alerts"
+
+	_defaultMaxImportSize     = 5 << 20
+	_defaultMaxAttachmentSize = 5 << 20
+	_defaultDialogTTL         = 24 * time.Hour
 )
 
 var (
-	_addRx    = regexp.MustCompile(`^(|@|-\d+d|\d{2}\.\d{2}\.\d{4})\s*(\d+(?:[.,]\d+)?) (.+)$`)
-	_reportRx = regexp.MustCompile(`^(?:(\d+)([wmy]))?$`)
+	_editRx = regexp.MustCompile(`^(\d+)\s+(.+)$`)
+
+	_limitPeriodRx = regexp.MustCompile(`^(day|week|month|день|неделя|месяц|(\d+)d)(\+)?$`)
+	_reportRx      = regexp.MustCompile(`^(?:(\d+)([wmy]))?$`)
+	_reportRangeRx = regexp.MustCompile(`^(\d{2}\.\d{2}\.\d{4})\s*-\s*(\d{2}\.\d{2}\.\d{4})$`)
+
+	_reportCompareRx = regexp.MustCompile(`^(?:compare|сравнить)\s+(?:([wmy])|(\d{2}\.\d{2}\.\d{4})\s*-\s*(\d{2}\.\d{2}\.\d{4})\s+(\d{2}\.\d{2}\.\d{4})\s*-\s*(\d{2}\.\d{2}\.\d{4}))$`)
+
+	_exportFormatRx = regexp.MustCompile(`(?:^|\s+)(csv|xlsx)$`)
+
+	_ruleRx = regexp.MustCompile(`^(.+?)\s*=\s*(.*)$`)
+
+	_reportRollUpRx   = regexp.MustCompile(`(?:^|\s+)(?:rollup|свернуть)$`)
+	_reportChartRx    = regexp.MustCompile(`(?:^|\s+)(?:chart|график)$`)
+	_reportGroupingRx = regexp.MustCompile(`(?:^|\s+)(?:by\s+(day|week|month)|по\s+(дням|неделям|месяцам))$`)
+	_reportGroupings  = map[string]types.ReportGrouping{
+		"day":     types.GroupingDay,
+		"дням":    types.GroupingDay,
+		"week":    types.GroupingWeek,
+		"неделям": types.GroupingWeek,
+		"month":   types.GroupingMonth,
+		"месяцам": types.GroupingMonth,
+	}
+
+	// _reportComparedRanges maps periods of the comparison report to functions returning
+	// the current period so far and the same number of days of the previous one for the given day.
+	_reportComparedRanges = map[string]func(today time.Time) (types.DateRange, types.DateRange){
+		"w": comparedRanges(thisWeekRange, lastWeekRange),
+		"m": comparedRanges(thisMonthRange, lastMonthRange),
+		"y": comparedRanges(ytdRange, lastYearRange),
+	}
+
+	// _reportNamedRanges maps named report ranges to functions returning [from, to) for the given day.
+	_reportNamedRanges = map[string]func(today time.Time) (time.Time, time.Time){
+		"this week":      thisWeekRange,
+		"эта неделя":     thisWeekRange,
+		"last week":      lastWeekRange,
+		"прошлая неделя": lastWeekRange,
+		"this month":     thisMonthRange,
+		"этот месяц":     thisMonthRange,
+		"last month":     lastMonthRange,
+		"прошлый месяц":  lastMonthRange,
+		"this year":      ytdRange,
+		"этот год":       ytdRange,
+		"ytd":            ytdRange,
+		"с начала года":  ytdRange,
+		"last year":      lastYearRange,
+		"прошлый год":    lastYearRange,
+	}
 
 	errWrongExpenseDate    = errors.New("не удалось определить дату")
 	errWrongExpenseAmount  = errors.New("не удалось определить сумму")
+	errInvalidExpenseDate  = errors.New("дата указана неверно")
+	errNoExpenseCategory   = errors.New("не указана категория расхода")
+	errAmbiguousPhrase     = errors.New("указано несколько сумм или дат")
 	errWrongReportDuration = errors.New("не удалось определить срок формирования отчёта")
+	errWrongReportRange    = errors.New("начало периода должно быть не позже его окончания")
+	errUnknownReportPeriod = errors.New("unknown report period")
+	errWrongExpenseID      = errors.New("не удалось определить номер расхода")
+	errUnknownCurrency     = errors.New("эта валюта не поддерживается")
+	errUnknownAccount      = errors.New("такого счёта нет")
+	errUnknownCategory     = errors.New("такой категории нет")
+	errUnknownAlias        = errors.New("такого синонима нет")
+	errWrongAlias          = errors.New("синоним должен отличаться от категории")
+	errWrongCategoryRename = errors.New("категорию нельзя переименовать в саму себя или в свою подкатегорию")
+	errCategoryExists      = errors.New("такая категория уже есть, чтобы объединить их, используй merge")
+	errEmptySearch         = errors.New("не указано, что искать")
+	errGroupOwnerOnly      = errors.New("лимиты группы задаёт только её владелец")
+	errGroupOwnerRemoves   = errors.New("исключать участников может только владелец группы")
+	errUnknownGroup        = errors.New("группы с таким кодом нет")
+	errUnknownGroupMember  = errors.New("такого участника в группе нет")
+	errUnknownSplitMember  = errors.New("разделить расход можно только с участниками своей группы")
+	errUnknownDebt         = errors.New("открытых долгов с этим участником нет")
+	errUnknownGoal         = errors.New("такой цели нет")
+	errWrongGoalDeadline   = errors.New("не удалось определить срок цели")
+	errWrongTransfer       = errors.New("перевод возможен только между разными счетами на ненулевую сумму")
+	errWrongLimitAmount    = errors.New("не удалось определить сумму лимита")
+	errWrongLimitPeriod    = errors.New("не удалось определить период лимита")
+	errWrongLimitAlerts    = errors.New("пороги уведомлений должны быть числами от 1 до 100")
+	errNonPositiveAmount   = errors.New("сумма должна быть положительным числом")
+	errFutureDate          = errors.New("даты из будущего не поддерживаются")
+	errEmptyName           = errors.New("название не должно быть пустым")
+	errNoExchangeRate      = errors.New("не удалось выполнить конвертацию валюты")
+	errUnknownLanguage     = errors.New("этот язык не поддерживается")
+
+	// _inputErrors explain the input rejected by the model
+	_inputErrors = map[model.InputError]error{
+		model.ErrNonPositiveAmount: errNonPositiveAmount,
+		model.ErrFutureDate:        errFutureDate,
+		model.ErrEmptyName:         errEmptyName,
+		model.ErrSameAccount:       errWrongTransfer,
+		model.ErrUnknownCurrency:   errUnknownCurrency,
+		model.ErrNoExchangeRate:    errNoExchangeRate,
+		model.ErrWrongRecurrence:   errWrongRecurrence,
+	}
 )
 
+// document is a file sent to the user along with the caption.
+type document struct {
+	name    string
+	data    []byte
+	caption string
+}
+
 type api interface {
 	GetUpdatesChan(tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
 	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
 	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	GetFileDirectURL(fileID string) (string, error)
 }
 
 type client struct {
-	api        api
-	storage    storage.TelegramUserStorage
-	controller model.Controller
-	logger     *zap.Logger
+	api               api
+	storage           storage.TelegramUserStorage
+	controller        model.Controller
+	maxImportSize     int
+	maxAttachmentSize int
+	// dialogs keeps the state of conversations between messages, see state.go
+	dialogs   storage.DialogStorage
+	dialogTTL time.Duration
+	logger    *zap.Logger
 }
 
-func NewClient(token string, s storage.TelegramUserStorage, l *zap.Logger) (*client, error) {
+// NewClient creates the client which accepts statements up to maxImportSize bytes and attached photos
+// up to maxAttachmentSize bytes (5 MiB when zero). The state of conversations is kept for dialogTTL
+// since the last message (a day when zero).
+func NewClient(token string, maxImportSize, maxAttachmentSize int, dialogTTL time.Duration, s storage.TelegramUserStorage, d storage.DialogStorage, l *zap.Logger) (*client, error) {
 	api, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, errors.Wrap(err, "NewBotAPI")
 	}
 
+	if maxImportSize <= 0 {
+		maxImportSize = _defaultMaxImportSize
+	}
+
+	if maxAttachmentSize <= 0 {
+		maxAttachmentSize = _defaultMaxAttachmentSize
+	}
+
+	if dialogTTL <= 0 {
+		dialogTTL = _defaultDialogTTL
+	}
+
 	return &client{
-		api:     api,
-		storage: s,
-		logger:  l,
+		api:               api,
+		storage:           s,
+		maxImportSize:     maxImportSize,
+		maxAttachmentSize: maxAttachmentSize,
+		dialogs:           d,
+		dialogTTL:         dialogTTL,
+		logger:            l,
 	}, nil
 }
 
@@ -98,15 +240,17 @@ func (c *client) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 
 	c.logger.Debug("tg message", zap.String("username", message.From.UserName), zap.String("text", message.Text))
 
+	ctx = i18n.WithLanguage(ctx, c.language(ctx, message.From.ID, message.From.LanguageCode))
+
 	user, err := c.resolveUser(ctx, message.From)
 	if err != nil {
 		c.logger.Error("cannot resolve user", zap.Error(err))
-		c.sendMessage(message.From.ID, emergencyMessage)
+		c.sendMessage(message.From.ID, tr(ctx, emergencyMessage))
 		return
 	}
 
 	command := message.Command()
-	text := unknownCommandMessage
+	text := tr(ctx, unknownCommandMessage)
 
 	defer func() {
 		span.SetTag("command", command)
@@ -116,19 +260,157 @@ func (c *client) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 		_commandCount.WithLabelValues(command).Inc()
 	}()
 
-	if command == "currency" {
-		currText, keyboard := c.handleCurrency(ctx, user)
-		c.sendMessageWithInlineKeyboard(message.From.ID, currText, keyboard)
+	if message.Document != nil {
+		command = "import"
+		span.SetTag("args", message.Caption)
+
+		c.sendMessage(message.From.ID, c.handleImport(ctx, user, message.Document, strings.TrimSpace(message.Caption)))
 		return
 	}
 
-	handler, ok := map[string]func(context.Context, *types.User, string) string{
-		"start":  func(context.Context, *types.User, string) string { return helloMessage },
-		"limit":  c.handleLimit,
-		"add":    c.handleAdd,
+	// photos attached to expenses go before receipts, as any other photo is taken for a receipt
+	if attachmentText, keyboard, a, ok := c.handleAttachment(ctx, user, message); ok {
+		command = "attach"
+		span.SetTag("args", message.Caption)
+
+		if len(keyboard) == 0 {
+			c.sendConfirmation(ctx, message.From.ID, user, attachmentText, a)
+		} else {
+			c.sendMessageWithInlineKeyboard(message.From.ID, attachmentText, keyboard)
+		}
+		return
+	}
+
+	// answers to the dialog go before receipts and free-form expenses, as any text may be the answer
+	if command == "" && message.Text != "" {
+		if dialogText, keyboard, a, ok := c.continueDialog(ctx, user, message.Text); ok {
+			command = "dialog"
+
+			if len(keyboard) == 0 {
+				c.sendConfirmation(ctx, message.From.ID, user, dialogText, a)
+			} else {
+				c.sendMessageWithInlineKeyboard(message.From.ID, dialogText, keyboard)
+			}
+			return
+		}
+	}
+
+	if receiptText, keyboard, pending, ok := c.handleReceipt(ctx, user, message); ok {
+		command = "receipt"
+
+		if pending == nil {
+			c.sendMessageWithInlineKeyboard(message.From.ID, receiptText, keyboard)
+		} else {
+			c.sendReceiptPrompt(ctx, message.From.ID, user, receiptText, keyboard, *pending)
+		}
+		return
+	}
+
+	if command == "add" {
+		args := strings.TrimSpace(message.CommandArguments())
+		span.SetTag("args", args)
+
+		addText, keyboard, a := c.handleAdd(ctx, user, args, nil)
+		if len(keyboard) == 0 {
+			c.sendConfirmation(ctx, message.From.ID, user, addText, a)
+		} else {
+			c.sendMessageWithInlineKeyboard(message.From.ID, addText, keyboard)
+		}
+		return
+	}
+
+	if command == "" {
+		if proposalText, keyboard, ok := c.handleText(ctx, user, message.Text); ok {
+			command = "add"
+			span.SetTag("args", message.Text)
+
+			c.sendMessageWithInlineKeyboard(message.From.ID, proposalText, keyboard)
+			return
+		}
+	}
+
+	keyboardHandler, ok := map[string]func(context.Context, *types.User, string) (string, [][][]string){
+		"currency":  c.handleCurrency,
+		"limit":     c.handleLimit,
+		"list":      c.handleList,
+		"find":      c.handleFind,
+		"recurring": c.handleRecurring,
+		"lang": func(ctx context.Context, _ *types.User, args string) (string, [][][]string) {
+			return c.handleLang(ctx, message.From.ID, args)
+		},
+	}[command]
+
+	if ok {
+		args := strings.TrimSpace(message.CommandArguments())
+		span.SetTag("args", args)
+
+		keyboardText, keyboard := keyboardHandler(ctx, user, args)
+		if len(keyboard) == 0 {
+			c.sendMessage(message.From.ID, keyboardText)
+		} else {
+			c.sendMessageWithInlineKeyboard(message.From.ID, keyboardText, keyboard)
+		}
+		return
+	}
+
+	photoHandler, ok := map[string]func(context.Context, *types.User, string) (string, []photo){
 		"report": c.handleReport,
 	}[command]
 
+	if ok {
+		args := strings.TrimSpace(message.CommandArguments())
+		span.SetTag("args", args)
+
+		photoText, photos := photoHandler(ctx, user, args)
+		if len(photos) == 0 {
+			c.sendMessage(message.From.ID, photoText)
+		} else {
+			c.sendPhotos(message.From.ID, photos)
+		}
+		return
+	}
+
+	documentHandler, ok := map[string]func(context.Context, *types.User, string) (string, *document){
+		"export": c.handleExport,
+	}[command]
+
+	if ok {
+		args := strings.TrimSpace(message.CommandArguments())
+		span.SetTag("args", args)
+
+		documentText, doc := documentHandler(ctx, user, args)
+		if doc == nil {
+			c.sendMessage(message.From.ID, documentText)
+		} else {
+			c.sendDocument(message.From.ID, *doc)
+		}
+		return
+	}
+
+	handler, ok := map[string]func(context.Context, *types.User, string) string{
+		"start":    func(ctx context.Context, _ *types.User, _ string) string { return tr(ctx, helloMessage) },
+		"cancel":   c.handleCancel,
+		"undo":     c.handleUndo,
+		"income":   c.handleIncome,
+		"account":  c.handleAccount,
+		"balance":  c.handleBalance,
+		"transfer": c.handleTransfer,
+		"goals":    c.handleGoals,
+		"group": func(ctx context.Context, user *types.User, args string) string {
+			return c.handleGroup(ctx, user, memberName(message.From), args)
+		},
+		"debts": c.handleDebts,
+		"settle": func(ctx context.Context, user *types.User, args string) string {
+			return c.handleSettle(ctx, user, memberName(message.From), args)
+		},
+		"edit":       c.handleEdit,
+		"categories": c.handleCategories,
+		"rule":       c.handleRule,
+		"import": func(ctx context.Context, _ *types.User, _ string) string {
+			return tr(ctx, importHelpMessage)
+		},
+	}[command]
+
 	if ok {
 		args := strings.TrimSpace(message.CommandArguments())
 		span.SetTag("args", args)
@@ -141,93 +423,266 @@ func (c *client) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 }
 
 func (c *client) handleCallback(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "callback", opentracing.Tags{"command": "set-currency"})
+	command, args, found := strings.Cut(callbackQuery.Data, _callbackSeparator)
+	if !found {
+		command, args = "set-currency", callbackQuery.Data
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "callback", opentracing.Tags{"command": command})
 	defer span.Finish()
 
 	c.logger.Debug("tg callback", zap.String("username", callbackQuery.From.UserName), zap.String("data", callbackQuery.Data))
 
+	ctx = i18n.WithLanguage(ctx, c.language(ctx, callbackQuery.From.ID, callbackQuery.From.LanguageCode))
+
 	user, err := c.resolveUser(ctx, callbackQuery.From)
 	if err != nil {
 		c.logger.Error("cannot resolve user", zap.Error(err))
-		c.sendMessage(callbackQuery.From.ID, emergencyMessage)
+		c.sendMessage(callbackQuery.From.ID, tr(ctx, emergencyMessage))
+		return
+	}
+
+	handler, ok := map[string]callbackHandler{
+		"set-currency":           undoableCallback(c.handleCurrencyCallback),
+		_callbackEditExpense:     textCallback(c.handleEditCallback),
+		_callbackDeleteExpense:   textCallback(c.handleDeleteCallback),
+		_callbackReceiptCategory: undoableCallback(c.handleReceiptCallback),
+		_callbackConfirmExpense:  undoableCallback(c.handleConfirmExpenseCallback),
+		_callbackPauseRecurring:  textCallback(c.handlePauseRecurringCallback),
+		_callbackResumeRecurring: textCallback(c.handleResumeRecurringCallback),
+		_callbackFindExpenses:    keyboardCallback(c.handleFindCallback),
+		_callbackAttachment:      photoCallback(c.handleAttachmentCallback),
+		_callbackDialog:          c.handleDialogCallback,
+		_callbackUndo:            textCallback(c.handleUndoCallback),
+		_callbackLang: textCallback(func(ctx context.Context, _ *types.User, args string) (string, bool) {
+			return c.handleLangCallback(ctx, callbackQuery.From.ID, args)
+		}),
+	}[command]
+	if !ok {
+		c.logger.Warn("unknown callback", zap.String("data", callbackQuery.Data))
 		return
 	}
 
 	start := time.Now()
 	defer func() {
-		_commandResponseTime.WithLabelValues("set-currency").Observe(time.Since(start).Seconds())
-		_commandCount.WithLabelValues("set-currency").Inc()
+		_commandResponseTime.WithLabelValues(command).Observe(time.Since(start).Seconds())
+		_commandCount.WithLabelValues(command).Inc()
 	}()
 
-	text, ok := c.handleCurrencyCallback(ctx, user, callbackQuery.Data)
-	if ok {
-		callback := tgbotapi.NewCallback(callbackQuery.ID, callbackQuery.Data)
-		if _, err := c.api.Request(callback); err != nil {
+	r := handler(ctx, user, args)
+	if r.answered {
+		if _, err := c.api.Request(tgbotapi.NewCallback(callbackQuery.ID, r.notice)); err != nil {
 			c.logger.Error("callback processing failed", zap.Error(err))
 		}
 	}
 
-	c.sendMessage(callbackQuery.From.ID, text)
+	switch {
+	case len(r.photos) > 0:
+		c.sendPhotos(callbackQuery.From.ID, r.photos)
+
+	case r.edit && callbackQuery.Message != nil:
+		c.editMessageWithInlineKeyboard(callbackQuery.Message.Chat.ID, callbackQuery.Message.MessageID, r.text, r.keyboard)
+
+	case len(r.keyboard) > 0:
+		c.sendMessageWithInlineKeyboard(callbackQuery.From.ID, r.text, r.keyboard)
+
+	default:
+		c.sendMessage(callbackQuery.From.ID, r.text)
+	}
 }
 
-func (c *client) handleCurrency(ctx context.Context, user *types.User) (string, [][][]string) {
+// reply is the response to a callback: the text along with the keyboard, or the photos instead.
+type reply struct {
+	text     string
+	keyboard [][][]string
+	photos   []photo
+	// edit replaces the message the callback comes from, e.g. to switch pages of a list
+	edit bool
+	// answered callbacks stop showing the progress, the notice is shown to the user if any
+	answered bool
+	notice   string
+}
+
+type callbackHandler func(ctx context.Context, user *types.User, args string) reply
+
+// textCallback adapts the handler replying with the text, the callback is answered with its arguments once handled.
+func textCallback(handler func(context.Context, *types.User, string) (string, bool)) callbackHandler {
+	return func(ctx context.Context, user *types.User, args string) reply {
+		text, ok := handler(ctx, user, args)
+		return reply{text: text, answered: ok, notice: args}
+	}
+}
+
+// keyboardCallback adapts the handler replacing the message with the text and the keyboard.
+func keyboardCallback(handler func(context.Context, *types.User, string) (string, [][][]string)) callbackHandler {
+	return func(ctx context.Context, user *types.User, args string) reply {
+		text, keyboard := handler(ctx, user, args)
+		return reply{text: text, keyboard: keyboard, edit: true, answered: true}
+	}
+}
+
+// photoCallback adapts the handler replying with the photos, or with the text when there are none.
+func photoCallback(handler func(context.Context, *types.User, string) (string, []photo)) callbackHandler {
+	return func(ctx context.Context, user *types.User, args string) reply {
+		text, photos := handler(ctx, user, args)
+		return reply{text: text, photos: photos, answered: true}
+	}
+}
+
+func (c *client) handleCurrency(ctx context.Context, user *types.User, _ string) (string, [][][]string) {
 	resp := c.controller.ListCurrencies(ctx, request.ListCurrencies{
 		User: user,
 	})
 
-	return currencyCurrentMessage + resp.Current + "\n\n" + currencyChooseMessage, prepareCurrenciesKeyboard(resp.List)
+	return tr(ctx, currencyCurrentMessage) + resp.Current + "\n\n" + tr(ctx, currencyChooseMessage), prepareCurrenciesKeyboard(resp.List)
 }
 
-func (c *client) handleCurrencyCallback(ctx context.Context, user *types.User, currency string) (string, bool) {
-	if c.controller.SetCurrency(ctx, request.SetCurrency{
+func (c *client) handleCurrencyCallback(ctx context.Context, user *types.User, currency string) (string, int64, bool) {
+	resp := c.controller.SetCurrency(ctx, request.SetCurrency{
 		User: user,
 		Code: currency,
-	}) {
-		return doneMessage, true
+	})
+	if !resp.Success {
+		return errorMessage(ctx, nil, "Не удалось сменить текущую валюту.", currencyHelpMessage), 0, false
 	}
 
-	return errorMessage(nil, "Не удалось сменить текущую валюту.", currencyHelpMessage), false
+	return tr(ctx, doneMessage), resp.Operation, true
 }
 
-func (c *client) handleLimit(ctx context.Context, user *types.User, args string) string {
+func (c *client) handleLimit(ctx context.Context, user *types.User, args string) (string, [][][]string) {
 	if args == "" {
-		return renderLimits(c.controller.ListLimits(ctx, request.ListLimits{
+		return renderLimits(ctx, c.controller.ListLimits(ctx, request.ListLimits{
 			User: user,
-		}))
+		})), nil
 	}
 
-	limitStr, category, ok := strings.Cut(args, " ")
-	if !ok {
-		limitStr, category = args, ""
+	if subcommand, thresholds, _ := strings.Cut(args, " "); subcommand == _limitAlertsSubcommand {
+		return c.handleLimitAlerts(ctx, user, thresholds), nil
 	}
 
-	limit, err := strconv.ParseInt(limitStr, 10, 64)
-	if err == nil && c.controller.SetLimit(ctx, request.SetLimit{
+	limit, period, category, err := parseLimitArgs(args)
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось задать лимит.", limitsHelpMessage), nil
+	}
+
+	resp := c.controller.SetLimit(ctx, request.SetLimit{
 		User:     user,
-		Value:    limit * 10000,
-		Category: strings.TrimSpace(category),
-	}) {
-		return doneMessage
+		Value:    limit,
+		Period:   period,
+		Category: category,
+	})
+
+	switch {
+	case resp.Forbidden:
+		return errorMessage(ctx, errGroupOwnerOnly, "Не удалось задать лимит.", groupHelpMessage), nil
+
+	case !resp.Success:
+		return errorMessage(ctx, nil, "Не удалось задать лимит.", limitsHelpMessage), nil
 	}
 
-	return errorMessage(nil, "Не удалось задать лимит.", limitsHelpMessage)
+	text := tr(ctx, doneMessage)
+
+	return text, undoKeyboard(ctx, text, resp.Operation)
 }
 
-func renderLimits(resp response.ListLimits) string {
+func (c *client) handleLimitAlerts(ctx context.Context, user *types.User, args string) string {
+	thresholds, err := parseLimitAlertsArgs(args)
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось настроить уведомления.", limitsHelpMessage)
+	}
+
+	resp := c.controller.SetLimitAlerts(ctx, request.SetLimitAlerts{
+		User:       user,
+		Thresholds: thresholds,
+	})
+
+	switch {
+	case resp.Forbidden:
+		return errorMessage(ctx, errGroupOwnerOnly, "Не удалось настроить уведомления.", groupHelpMessage)
+
+	case !resp.Success:
+		return errorMessage(ctx, nil, "Не удалось настроить уведомления.", limitsHelpMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+func parseLimitAlertsArgs(args string) ([]int, error) {
+	fields := strings.FieldsFunc(args, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '%'
+	})
+	if len(fields) == 0 {
+		return nil, errWrongLimitAlerts
+	}
+
+	thresholds := make([]int, 0, len(fields))
+	for _, field := range fields {
+		threshold, err := strconv.Atoi(field)
+		if err != nil || threshold <= 0 || threshold > 100 {
+			return nil, errWrongLimitAlerts
+		}
+
+		thresholds = append(thresholds, threshold)
+	}
+
+	return thresholds, nil
+}
+
+// parseLimitArgs parses the arguments of /limit: the amount, the period and the category. The period may only
+// go right after the amount, so a category starting with a period word is given along with the period
+// (/limit 100 month day care), otherwise the word is taken for the period.
+func parseLimitArgs(args string) (limit int64, period types.LimitPeriod, category string, err error) {
+	limitStr, rest, _ := strings.Cut(args, " ")
+
+	if limit, err = strconv.ParseInt(limitStr, 10, 64); err != nil {
+		return 0, types.LimitPeriod{}, "", errWrongLimitAmount
+	}
+
+	limit *= 10000
+	period = types.LimitPeriod{Kind: types.PeriodMonth}
+
+	rest = strings.TrimSpace(rest)
+	periodStr, category, _ := strings.Cut(rest, " ")
+
+	m := _limitPeriodRx.FindStringSubmatch(periodStr)
+	if len(m) == 0 {
+		return limit, period, rest, nil
+	}
+
+	switch m[1] {
+	case "day", "день":
+		period.Kind = types.PeriodDay
+	case "week", "неделя":
+		period.Kind = types.PeriodWeek
+	case "month", "месяц":
+		period.Kind = types.PeriodMonth
+	default:
+		period.Kind = types.PeriodCustom
+		if period.Days, err = strconv.Atoi(m[2]); err != nil || period.Days == 0 {
+			return 0, types.LimitPeriod{}, "", errWrongLimitPeriod
+		}
+	}
+
+	period.CarryOver = m[3] == "+"
+
+	return limit, period, strings.TrimSpace(category), nil
+}
+
+func renderLimits(ctx context.Context, resp response.ListLimits) string {
 	switch {
 	case !resp.Ready:
-		return currencyLaterMessage
+		return tr(ctx, currencyLaterMessage)
 
 	case !resp.Success:
-		return emergencyMessage
+		return tr(ctx, emergencyMessage)
 
 	case len(resp.List) == 0:
-		return limitsEmptyMessage
+		return tr(ctx, limitsEmptyMessage)
 	}
 
 	baseItem, baseOk := resp.List[""]
 	if baseOk && len(resp.List) == 1 {
-		return "Общий лимит (осталось/всего):\n• " + renderLimitRow(baseItem, resp.CurrentCurrency)
+		return tr(ctx, "Общий лимит (осталось/всего):") + "\n• " + renderLimitRow(ctx, baseItem, resp.CurrentCurrency) + renderLimitAlerts(ctx, resp.Alerts)
 	}
 
 	categories := make([]string, 0, len(resp.List))
@@ -236,22 +691,43 @@ func renderLimits(resp response.ListLimits) string {
 	}
 	sort.Strings(categories)
 
-	text := "Твои лимиты (осталось/всего):"
+	text := tr(ctx, "Твои лимиты (осталось/всего):")
 	for category, item := range resp.List {
 		if category == "" {
 			continue
 		}
-		text += "\n• " + category + ": " + renderLimitRow(item, resp.CurrentCurrency)
+		text += "\n• " + category + ": " + renderLimitRow(ctx, item, resp.CurrentCurrency)
 	}
 
 	if baseOk {
-		text += "\n• остальные расходы: " + renderLimitRow(baseItem, resp.CurrentCurrency)
+		text += "\n• " + tr(ctx, "остальные расходы") + ": " + renderLimitRow(ctx, baseItem, resp.CurrentCurrency)
 	}
 
-	return text
+	return text + renderLimitAlerts(ctx, resp.Alerts)
 }
 
-func renderLimitRow(item response.LimitItem, currency string) (row string) {
+func renderLimitAlerts(ctx context.Context, thresholds []int) string {
+	if len(thresholds) == 0 {
+		return ""
+	}
+
+	list := make([]string, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		list = append(list, strconv.Itoa(threshold)+"%")
+	}
+
+	return "\n\n" + tr(ctx, "Уведомления о расходе лимита:") + " " + strings.Join(list, ", ")
+}
+
+func renderLimitAlert(ctx context.Context, threshold int) string {
+	if threshold >= 100 {
+		return tr(ctx, limitReached)
+	}
+
+	return trf(ctx, limitAlertMessage, threshold)
+}
+
+func renderLimitRow(ctx context.Context, item response.LimitItem, currency string) (row string) {
 	if item.Remains == 0 {
 		row = fmt.Sprintf("<b>%.2f</b>/%.2f %s", float64(item.Remains)/10000, float64(item.Total)/10000, currency)
 	} else {
@@ -262,111 +738,388 @@ func renderLimitRow(item response.LimitItem, currency string) (row string) {
 		row += fmt.Sprintf(" (%.2f/%.2f %s)", float64(item.Origin.Remains)/10000, float64(item.Origin.Total)/10000, item.Origin.Currency)
 	}
 
+	row += renderLimitPeriod(ctx, item.Origin)
+
 	return
 }
 
-func (c *client) handleAdd(ctx context.Context, user *types.User, args string) string {
-	m := _addRx.FindStringSubmatch(args)
-	if len(m) == 0 {
-		return errorMessage(nil, "Не удалось добавить расход.", addHelpMessage)
+func renderLimitPeriod(ctx context.Context, item types.LimitItem) (text string) {
+	switch item.Period.Kind {
+	case types.PeriodDay:
+		text = " — " + tr(ctx, "на день")
+	case types.PeriodWeek:
+		text = " — " + tr(ctx, "на неделю")
+	case types.PeriodMonth:
+		text = " — " + tr(ctx, "на месяц")
+	case types.PeriodCustom:
+		text = " — " + trn(ctx, "на %d день|на %d дня|на %d дней", int64(item.Period.Days), item.Period.Days)
+	default:
+		return ""
+	}
+
+	if !item.Anchor.IsZero() {
+		text += " " + trf(ctx, "с %s", item.Anchor.Format("02.01.2006"))
+	}
+
+	if item.Period.CarryOver {
+		text += ", " + tr(ctx, "с переносом остатка")
+	}
+
+	return
+}
+
+// handleAdd adds the expense described by the arguments, the photo is attached to it if any. When the arguments
+// are ambiguous, the expense is proposed instead: handleAdd returns the keyboard to confirm it and the zero ID.
+// Without the arguments, the dialog asking for them one by one is started.
+func (c *client) handleAdd(ctx context.Context, user *types.User, args string, photo []tgbotapi.PhotoSize) (string, [][][]string, added) {
+	if args == "" {
+		text, keyboard := c.startDialog(ctx, user, dialog{
+			Name:  _dialogAdd,
+			Photo: photo,
+		})
+
+		return text, keyboard, added{}
+	}
+
+	req, guessed, err := parseAddRequest(args, time.Now(), c.controller.IsCurrencyAvailable)
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось добавить расход.", addHelpMessage), nil, added{}
 	}
 
-	date, amount, category, err := parseAddArgs(m[1:])
-	if err == nil {
-		resp := c.controller.AddExpense(ctx, request.AddExpense{
-			User:     user,
-			Date:     date,
-			Amount:   amount,
-			Category: category,
+	req.User = user
+
+	if guessed {
+		text, keyboard := c.proposeExpense(ctx, user, proposal{
+			Request: req,
+			Photo:   photo,
 		})
 
-		switch {
-		case !resp.Ready:
-			return currencyLaterMessage
+		return text, keyboard, added{}
+	}
 
-		case !resp.Success:
-			return emergencyMessage
+	text, a := c.addExpense(ctx, req)
+	if a.expenseID != 0 && len(photo) > 0 {
+		text += "\n\n" + c.attachPhoto(ctx, user, a.expenseID, photo)
+	}
 
-		case resp.LimitReached:
-			return doneMessage + "\n\n" + limitReached
+	return text, nil, a
+}
 
-		default:
-			return doneMessage
-		}
+// added is the expense just added along with the journal entry to undo it, the zero one stands for no expense.
+type added struct {
+	expenseID int64
+	operation int64
+}
+
+// addExpense adds the expense and returns the confirmation along with the added expense.
+func (c *client) addExpense(ctx context.Context, req request.AddExpense) (string, added) {
+	resp := c.controller.AddExpense(ctx, req)
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage), added{}
+
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось добавить расход.", addHelpMessage), added{}
+
+	case resp.UnknownMember:
+		return errorMessage(ctx, errUnknownSplitMember, "Не удалось добавить расход.", debtsHelpMessage), added{}
+
+	case resp.Invalid != nil:
+		return errorMessage(ctx, explainInputError(resp.Invalid), "Не удалось добавить расход.", addHelpMessage), added{}
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), added{}
 	}
 
-	return errorMessage(err, "Не удалось добавить расход.", addHelpMessage)
+	text := tr(ctx, doneMessage)
+	if len(resp.Debts) > 0 {
+		c.notifyDebtors(ctx, resp.Debts)
+		text += "\n\n" + renderSplit(ctx, resp.Debts)
+	}
+	if resp.LimitAlert > 0 {
+		text += "\n\n" + renderLimitAlert(ctx, resp.LimitAlert)
+	}
+
+	return text, added{expenseID: resp.ID, operation: resp.Operation}
 }
 
-func parseAddArgs(args []string) (date time.Time, amount int64, category string, err error) {
-	if date, err = parseDate(args[0]); err != nil {
-		return time.Time{}, 0, "", errors.New("дата указана неверно")
+// parseAddRequest parses the arguments of /add: the note, the tags and the members to split the expense with
+// go along with the phrase describing the expense. It reports whether the phrase is ambiguous.
+func parseAddRequest(args string, now time.Time, available func(code string) bool) (request.AddExpense, bool, error) {
+	// the note goes first, as it may contain anything
+	args, note := cutNote(args)
+	args, tags := cutTags(args)
+	args, split := cutSplit(args)
+
+	e, err := parsePhrase(args, now, available)
+	if err != nil {
+		return request.AddExpense{}, false, err
 	}
 
-	date = utils.TruncateToDate(date)
+	return request.AddExpense{
+		Date:     e.Date,
+		Amount:   e.Amount,
+		Currency: e.Currency,
+		Category: e.Category,
+		Note:     note,
+		Tags:     tags,
+		Split:    split,
+	}, e.Guessed, nil
+}
+
+// parsePhrase parses the phrase describing the expense, see phrase.Parse.
+func parsePhrase(text string, now time.Time, available func(code string) bool) (phrase.Expense, error) {
+	e, err := phrase.Parse(text, now, available)
+	switch {
+	case errors.Is(err, phrase.ErrWrongDate):
+		return phrase.Expense{}, errInvalidExpenseDate
+
+	case errors.Is(err, phrase.ErrNoCategory):
+		return phrase.Expense{}, errNoExpenseCategory
 
-	floatAmount, err := strconv.ParseFloat(strings.ReplaceAll(args[1], ",", "."), 64)
+	case err != nil:
+		return phrase.Expense{}, errWrongExpenseAmount
+	}
+
+	return e, nil
+}
+
+// parseExactPhrase parses the phrase the same way as parsePhrase for the commands which don't confirm guessed
+// expenses, so ambiguous phrases are rejected.
+func parseExactPhrase(text string, now time.Time, available func(code string) bool) (phrase.Expense, error) {
+	e, err := parsePhrase(text, now, available)
 	if err != nil {
-		err = errWrongExpenseAmount
+		return phrase.Expense{}, err
 	}
 
-	amount = int64(floatAmount * 10000)
+	if e.Guessed {
+		return phrase.Expense{}, errAmbiguousPhrase
+	}
 
-	category = strings.TrimSpace(args[2])
+	return e, nil
+}
 
-	return
+// parseAmount parses an amount with a dot or a comma as the decimal separator.
+func parseAmount(input string) (int64, error) {
+	floatAmount, err := strconv.ParseFloat(strings.ReplaceAll(input, ",", "."), 64)
+	if err != nil {
+		return 0, errWrongExpenseAmount
+	}
+
+	return int64(math.Round(floatAmount * 10000)), nil
 }
 
-func parseDate(input string) (time.Time, error) {
-	if input == "" || input == "@" {
-		return time.Now(), nil
+func (c *client) handleList(ctx context.Context, user *types.User, _ string) (string, [][][]string) {
+	resp := c.controller.ListExpenses(ctx, request.ListExpenses{
+		User: user,
+	})
+
+	switch {
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), nil
+
+	case len(resp.List) == 0:
+		return tr(ctx, reportNoExpenses), nil
 	}
 
-	if input[0] == '-' {
-		rate, err := strconv.ParseUint(input[1:len(input)-1], 10, 64)
-		if err != nil {
-			return time.Time{}, errWrongExpenseDate
+	text := tr(ctx, "Последние расходы:")
+	keyboard := make([][][]string, 0, len(resp.List))
+	for i, item := range resp.List {
+		text += fmt.Sprintf("\n%d. %s", i+1, renderExpense(item))
+		row := [][]string{
+			{fmt.Sprintf("✏️ %d", i+1), _callbackEditExpense + _callbackSeparator + strconv.FormatInt(item.ID, 10)},
+			{fmt.Sprintf("🗑 %d", i+1), _callbackDeleteExpense + _callbackSeparator + strconv.FormatInt(item.ID, 10)},
 		}
+		if resp.Attached[item.ID] {
+			row = append(row, []string{fmt.Sprintf("📎 %d", i+1), _callbackAttachment + _callbackSeparator + strconv.FormatInt(item.ID, 10)})
+		}
+
+		keyboard = append(keyboard, row)
+	}
+
+	return text, keyboard
+}
+
+func (c *client) handleEditCallback(ctx context.Context, user *types.User, args string) (string, bool) {
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		return errorMessage(ctx, errWrongExpenseID, "Не удалось изменить расход.", editHelpMessage), false
+	}
+
+	resp := c.controller.GetExpense(ctx, request.GetExpense{
+		User: user,
+		ID:   id,
+	})
+
+	switch {
+	case resp.NotFound:
+		return tr(ctx, expenseNotFoundMessage), false
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), false
+	}
+
+	return fmt.Sprintf(
+		"%s\n<code>/edit %d %s %.2f %s %s</code>",
+		tr(ctx, editPromptMessage),
+		resp.Item.ID,
+		resp.Item.Date.Format("02.01.2006"),
+		float64(resp.Item.Amount)/10000,
+		resp.Item.Currency,
+		resp.Item.Category,
+	), true
+}
+
+func (c *client) handleDeleteCallback(ctx context.Context, user *types.User, args string) (string, bool) {
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		return errorMessage(ctx, errWrongExpenseID, "Не удалось удалить расход.", editHelpMessage), false
+	}
+
+	resp := c.controller.DeleteExpense(ctx, request.DeleteExpense{
+		User: user,
+		ID:   id,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage), false
 
-		return time.Now().Add(-time.Duration(rate) * 24 * time.Hour), nil
+	case resp.NotFound:
+		return tr(ctx, expenseNotFoundMessage), false
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage), false
+	}
+
+	return tr(ctx, doneMessage), true
+}
+
+func (c *client) handleEdit(ctx context.Context, user *types.User, args string) string {
+	m := _editRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось изменить расход.", editHelpMessage)
 	}
 
-	date, err := time.Parse("02.01.2006", input)
+	id, err := strconv.ParseInt(m[1], 10, 64)
 	if err != nil {
-		return time.Time{}, errWrongExpenseDate
+		return errorMessage(ctx, errWrongExpenseID, "Не удалось изменить расход.", editHelpMessage)
 	}
 
-	return date, nil
+	e, err := parseExactPhrase(m[2], time.Now(), c.controller.IsCurrencyAvailable)
+	if err != nil {
+		return errorMessage(ctx, err, "Не удалось изменить расход.", editHelpMessage)
+	}
+
+	resp := c.controller.UpdateExpense(ctx, request.UpdateExpense{
+		User:     user,
+		ID:       id,
+		Date:     e.Date,
+		Amount:   e.Amount,
+		Currency: e.Currency,
+		Category: e.Category,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage)
+
+	case resp.UnknownCurrency:
+		return errorMessage(ctx, errUnknownCurrency, "Не удалось изменить расход.", editHelpMessage)
+
+	case resp.NotFound:
+		return tr(ctx, expenseNotFoundMessage)
+
+	case resp.Invalid != nil:
+		return errorMessage(ctx, explainInputError(resp.Invalid), "Не удалось изменить расход.", editHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+
+	case resp.LimitAlert > 0:
+		return tr(ctx, doneMessage) + "\n\n" + renderLimitAlert(ctx, resp.LimitAlert)
+
+	default:
+		return tr(ctx, doneMessage)
+	}
 }
 
-func (c *client) handleReport(ctx context.Context, user *types.User, args string) string {
+func (c *client) handleReport(ctx context.Context, user *types.User, args string) (string, []photo) {
 	var (
-		from time.Time
-		err  error
+		today    = utils.TruncateToDate(time.Now())
+		from, to time.Time
+		grouping types.ReportGrouping
+		charts   bool
+		rollUp   bool
+		err      error
 	)
 
+	args = strings.ToLower(strings.TrimSpace(args))
+
+	if m := _reportRollUpRx.FindStringSubmatch(args); len(m) != 0 {
+		rollUp = true
+		args = strings.TrimSuffix(args, m[0])
+	}
+
+	if m := _reportCompareRx.FindStringSubmatch(args); len(m) != 0 {
+		return c.handleReportComparison(ctx, user, today, m[1:], rollUp), nil
+	}
+
+	if m := _reportChartRx.FindStringSubmatch(args); len(m) != 0 {
+		charts = true
+		args = strings.TrimSuffix(args, m[0])
+	}
+
+	if m := _reportGroupingRx.FindStringSubmatch(args); len(m) != 0 {
+		grouping = _reportGroupings[m[1]+m[2]]
+		args = strings.TrimSuffix(args, m[0])
+	}
+
 	if args == "" {
-		from = utils.TruncateToDate(time.Now()).Add(-7 * 24 * time.Hour)
-	} else if m := _reportRx.FindStringSubmatch(args); len(m) == 0 {
-		return reportHelpMessage
-	} else if from, err = parseReportArgs(m[1:]); err != nil {
-		return errorMessage(err, "Не удалось сформировать отчёт.", reportHelpMessage)
+		from, to = today.Add(-7*24*time.Hour), today.AddDate(0, 0, 1)
+	} else if from, to, err = parseReportPeriod(args, today); err == errUnknownReportPeriod {
+		return tr(ctx, reportHelpMessage), nil
+	} else if err != nil {
+		return errorMessage(ctx, err, "Не удалось сформировать отчёт.", reportHelpMessage), nil
+	}
+
+	if charts && grouping == types.GroupingNone {
+		grouping = chartGrouping(from, to)
 	}
 
 	resp := c.controller.GetReport(ctx, request.GetReport{
-		User: user,
-		From: from,
+		User:     user,
+		From:     from,
+		To:       to,
+		Grouping: grouping,
+		RollUp:   rollUp,
 	})
 
 	switch {
 	case !resp.Ready:
-		return currencyLaterMessage
+		return tr(ctx, currencyLaterMessage), nil
 
 	case !resp.Success:
-		return reportRetry
+		return tr(ctx, reportRetry), nil
+
+	case len(resp.Data) == 0 && len(resp.Income) == 0:
+		return tr(ctx, reportNoExpenses), nil
+
+	case charts && len(resp.Data) != 0:
+		photos, err := renderReportCharts(ctx, resp)
+		if errors.Is(err, chart.ErrNoData) {
+			// the expenses of the period sum up to zero
+			return tr(ctx, reportNoExpenses), nil
+		} else if err != nil {
+			c.logger.Error("cannot render report charts", zap.Error(err))
+			return tr(ctx, reportRetry), nil
+		}
 
-	case len(resp.Data) == 0:
-		return reportNoExpenses
+		return "", photos
+
+	case resp.Grouping != types.GroupingNone:
+		return renderReportTable(ctx, resp) + renderReportMembers(ctx, resp), nil
 	}
 
 	categories := make([]string, 0, len(resp.Data))
@@ -375,14 +1128,235 @@ func (c *client) handleReport(ctx context.Context, user *types.User, args string
 	}
 	sort.Strings(categories)
 
-	text := fmt.Sprintf("Расходы с %s (валюта — %s):\n", resp.From.Local().Format("02.01.2006"), resp.Currency)
+	text := trf(
+		ctx,
+		"Расходы с %s по %s (валюта — %s):\n",
+		resp.From.Local().Format("02.01.2006"),
+		resp.To.AddDate(0, 0, -1).Local().Format("02.01.2006"),
+		resp.Currency,
+	)
 	for _, category := range categories {
 		text += fmt.Sprintf("%s: %.2f\n", category, float64(resp.Data[category])/10000)
 	}
 
+	return text + renderReportBalance(ctx, resp) + renderReportMembers(ctx, resp), nil
+}
+
+func (c *client) handleExport(ctx context.Context, user *types.User, args string) (string, *document) {
+	var (
+		today    = utils.TruncateToDate(time.Now())
+		from, to time.Time
+		format   = types.ExportCSV
+		err      error
+	)
+
+	args = strings.ToLower(strings.TrimSpace(args))
+
+	if m := _exportFormatRx.FindStringSubmatch(args); len(m) != 0 {
+		format = types.ExportFormat(m[1])
+		args = strings.TrimSuffix(args, m[0])
+	}
+
+	if args != "" {
+		if from, to, err = parseReportPeriod(args, today); err == errUnknownReportPeriod {
+			return tr(ctx, exportHelpMessage), nil
+		} else if err != nil {
+			return errorMessage(ctx, err, "Не удалось выгрузить расходы.", exportHelpMessage), nil
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	resp := c.controller.ExportExpenses(ctx, request.ExportExpenses{
+		User:   user,
+		From:   from,
+		To:     to,
+		Format: format,
+		Writer: buf,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage), nil
+
+	case !resp.Success:
+		return tr(ctx, exportRetry), nil
+
+	case resp.Count == 0:
+		return tr(ctx, exportNoExpenses), nil
+	}
+
+	period := tr(ctx, "за всё время")
+	if !from.IsZero() {
+		period = trf(ctx, "с %s по %s", from.Format("02.01.2006"), to.AddDate(0, 0, -1).Format("02.01.2006"))
+	}
+
+	return "", &document{
+		name:    "expenses." + string(format),
+		data:    buf.Bytes(),
+		caption: trn(ctx, exportCaption, int64(resp.Count), period, resp.Count, resp.Currency),
+	}
+}
+
+// handleImport imports expenses from the bank statement sent as a document, the caption names the bank
+// whose layout the CSV statement has, it's detected when the caption is empty.
+func (c *client) handleImport(ctx context.Context, user *types.User, doc *tgbotapi.Document, bank string) string {
+	if doc.FileSize > c.maxImportSize {
+		return errorMessage(ctx, nil, trf(ctx, importTooLargeMessage, c.maxImportSize>>10), importHelpMessage)
+	}
+
+	data, err := c.downloadFile(ctx, doc.FileID, c.maxImportSize)
+	if err != nil {
+		c.logger.Error("cannot download statement", zap.Error(err), zap.String("file", doc.FileName))
+		return tr(ctx, importRetry)
+	}
+
+	resp := c.controller.ImportExpenses(ctx, request.ImportExpenses{
+		User: user,
+		Name: doc.FileName,
+		Bank: bank,
+		Data: data,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage)
+
+	case resp.UnknownStatement:
+		return errorMessage(ctx, nil, "Не удалось распознать выписку.", importHelpMessage)
+
+	case resp.InvalidStatement:
+		return errorMessage(ctx, nil, "Выписка содержит ошибки.", importHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, importRetry)
+
+	case resp.Parsed == 0:
+		return tr(ctx, importNoExpenses)
+	}
+
+	text := trf(ctx, importDoneMessage, resp.Parsed, resp.Imported, resp.Parsed-resp.Imported)
+
+	categories := make([]string, 0, len(resp.LimitAlerts))
+	for category := range resp.LimitAlerts {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		text += "\n\n" + html.EscapeString(category) + ": " + renderLimitAlert(ctx, resp.LimitAlerts[category])
+	}
+
 	return text
 }
 
+func (c *client) handleRule(ctx context.Context, user *types.User, args string) string {
+	if args == "" {
+		resp := c.controller.ListMerchantRules(ctx, request.ListMerchantRules{
+			User: user,
+		})
+		if !resp.Success {
+			return tr(ctx, emergencyMessage)
+		}
+
+		return renderMerchantRules(ctx, resp.List)
+	}
+
+	m := _ruleRx.FindStringSubmatch(args)
+	if len(m) == 0 {
+		return errorMessage(ctx, nil, "Не удалось задать правило.", ruleHelpMessage)
+	}
+
+	resp := c.controller.SetMerchantRule(ctx, request.SetMerchantRule{
+		User:     user,
+		Pattern:  m[1],
+		Category: strings.TrimSpace(m[2]),
+	})
+
+	switch {
+	case resp.NotFound:
+		return tr(ctx, ruleNotFoundMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+func renderMerchantRules(ctx context.Context, list []types.MerchantRule) string {
+	if len(list) == 0 {
+		return tr(ctx, ruleEmptyMessage) + "\n\n" + tr(ctx, ruleHelpMessage)
+	}
+
+	text := tr(ctx, "Правила категорий:")
+	for _, rule := range list {
+		text += fmt.Sprintf("\n<code>%s</code> → %s", html.EscapeString(rule.Pattern), html.EscapeString(rule.Category))
+	}
+
+	return text
+}
+
+// handleReportComparison compares expenses of the current period with the previous one,
+// or of the second range of dates with the first one.
+func (c *client) handleReportComparison(ctx context.Context, user *types.User, today time.Time, args []string, rollUp bool) string {
+	var current, compare types.DateRange
+
+	if ranges, ok := _reportComparedRanges[args[0]]; ok {
+		current, compare = ranges(today)
+	} else {
+		var err error
+		if compare.From, compare.To, err = parseReportRangeArgs(args[1:3]); err != nil {
+			return errorMessage(ctx, err, "Не удалось сформировать отчёт.", reportHelpMessage)
+		}
+
+		if current.From, current.To, err = parseReportRangeArgs(args[3:5]); err != nil {
+			return errorMessage(ctx, err, "Не удалось сформировать отчёт.", reportHelpMessage)
+		}
+	}
+
+	resp := c.controller.GetReport(ctx, request.GetReport{
+		User:    user,
+		From:    current.From,
+		To:      current.To,
+		Compare: compare,
+		RollUp:  rollUp,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage)
+
+	case !resp.Success:
+		return tr(ctx, reportRetry)
+
+	case len(resp.Data) == 0 && len(resp.Previous) == 0 && len(resp.Income) == 0 && len(resp.PreviousIncome) == 0:
+		return tr(ctx, reportNoExpenses)
+	}
+
+	return renderReportComparison(ctx, resp)
+}
+
+// parseReportPeriod parses a named range, a closed range of dates or a number of the last
+// weeks/months/years and returns it as [from, to).
+func parseReportPeriod(args string, today time.Time) (from, to time.Time, err error) {
+	if namedRange, ok := _reportNamedRanges[strings.Join(strings.Fields(args), " ")]; ok {
+		from, to = namedRange(today)
+		return from, to, nil
+	}
+
+	if m := _reportRangeRx.FindStringSubmatch(args); len(m) != 0 {
+		return parseReportRangeArgs(m[1:])
+	}
+
+	if m := _reportRx.FindStringSubmatch(args); len(m) == 0 || m[1] == "" {
+		return time.Time{}, time.Time{}, errUnknownReportPeriod
+	} else if from, err = parseReportArgs(m[1:]); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return from, today.AddDate(0, 0, 1), nil
+}
+
 func parseReportArgs(args []string) (time.Time, error) {
 	hours, err := strconv.ParseUint(args[0], 10, 64)
 	if err != nil {
@@ -401,6 +1375,68 @@ func parseReportArgs(args []string) (time.Time, error) {
 	return utils.TruncateToDate(time.Now()).Add(-(time.Duration(hours) * time.Hour)), nil
 }
 
+// parseReportRangeArgs parses a closed range of dates and returns it as [from, to).
+func parseReportRangeArgs(args []string) (from, to time.Time, err error) {
+	if from, err = time.Parse("02.01.2006", args[0]); err != nil {
+		return time.Time{}, time.Time{}, errWrongReportDuration
+	}
+
+	if to, err = time.Parse("02.01.2006", args[1]); err != nil {
+		return time.Time{}, time.Time{}, errWrongReportDuration
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errWrongReportRange
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func thisWeekRange(today time.Time) (time.Time, time.Time) {
+	from := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	return from, today.AddDate(0, 0, 1)
+}
+
+func lastWeekRange(today time.Time) (time.Time, time.Time) {
+	to, _ := thisWeekRange(today)
+	return to.AddDate(0, 0, -7), to
+}
+
+func thisMonthRange(today time.Time) (time.Time, time.Time) {
+	return today.AddDate(0, 0, 1-today.Day()), today.AddDate(0, 0, 1)
+}
+
+func lastMonthRange(today time.Time) (time.Time, time.Time) {
+	to, _ := thisMonthRange(today)
+	return to.AddDate(0, -1, 0), to
+}
+
+func ytdRange(today time.Time) (time.Time, time.Time) {
+	return time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC), today.AddDate(0, 0, 1)
+}
+
+func lastYearRange(today time.Time) (time.Time, time.Time) {
+	to, _ := ytdRange(today)
+	return to.AddDate(-1, 0, 0), to
+}
+
+// comparedRanges returns the function making the current period so far and the beginning of the previous
+// period of the same number of days, so that the periods are compared evenly. The previous period is cut
+// at its end when it's shorter, e.g. February compared with March 31.
+func comparedRanges(current, previous func(today time.Time) (time.Time, time.Time)) func(today time.Time) (types.DateRange, types.DateRange) {
+	return func(today time.Time) (types.DateRange, types.DateRange) {
+		from, to := current(today)
+		previousFrom, previousTo := previous(today)
+
+		days := int(to.Sub(from) / (24 * time.Hour))
+		if sameDays := previousFrom.AddDate(0, 0, days); sameDays.Before(previousTo) {
+			previousTo = sameDays
+		}
+
+		return types.DateRange{From: from, To: to}, types.DateRange{From: previousFrom, To: previousTo}
+	}
+}
+
 func (c *client) sendMessage(chatID int64, text string) {
 	message := tgbotapi.NewMessage(chatID, text)
 	message.ParseMode = tgbotapi.ModeHTML
@@ -411,10 +1447,99 @@ func (c *client) sendMessage(chatID int64, text string) {
 	}
 }
 
+func (c *client) sendPhotos(chatID int64, photos []photo) {
+	for _, p := range photos {
+		message := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+			Name:  p.name,
+			Bytes: p.data,
+		})
+		message.Caption = p.caption
+		message.ParseMode = tgbotapi.ModeHTML
+
+		if _, err := c.api.Send(message); err != nil {
+			c.logger.Error("cannot send telegram photo", zap.Error(err))
+		}
+	}
+}
+
+func (c *client) sendDocument(chatID int64, doc document) {
+	message := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  doc.name,
+		Bytes: doc.data,
+	})
+	message.Caption = doc.caption
+	message.ParseMode = tgbotapi.ModeHTML
+
+	if _, err := c.api.Send(message); err != nil {
+		c.logger.Error("cannot send telegram document", zap.Error(err))
+	}
+}
+
+// downloadFile fetches the file sent by the user, it fails on files larger than the limit.
+func (c *client) downloadFile(ctx context.Context, fileID string, limit int) ([]byte, error) {
+	url, err := c.api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetFileDirectURL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "create request")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// the error contains the URL with the bot token
+		return nil, errors.New("file request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "read file")
+	}
+
+	if len(data) > limit {
+		return nil, errors.New("file is too large")
+	}
+
+	return data, nil
+}
+
 func (c *client) sendMessageWithInlineKeyboard(chatID int64, text string, rowsData [][][]string) {
 	message := tgbotapi.NewMessage(chatID, text)
 	message.ParseMode = tgbotapi.ModeHTML
+	if len(rowsData) != 0 {
+		message.ReplyMarkup = c.inlineKeyboard(rowsData)
+	}
 
+	_, err := c.api.Send(message)
+	if err != nil {
+		c.logger.Error("cannot send telegram message (with inline keyboard)", zap.Error(err))
+	}
+}
+
+// editMessageWithInlineKeyboard replaces the text and the keyboard of the message sent before,
+// the keyboard is removed when there are no buttons.
+func (c *client) editMessageWithInlineKeyboard(chatID int64, messageID int, text string, rowsData [][][]string) {
+	message := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	message.ParseMode = tgbotapi.ModeHTML
+	if len(rowsData) != 0 {
+		markup := c.inlineKeyboard(rowsData)
+		message.ReplyMarkup = &markup
+	}
+
+	_, err := c.api.Send(message)
+	if err != nil {
+		c.logger.Error("cannot edit telegram message (with inline keyboard)", zap.Error(err))
+	}
+}
+
+func (c *client) inlineKeyboard(rowsData [][][]string) tgbotapi.InlineKeyboardMarkup {
 	var rows [][]tgbotapi.InlineKeyboardButton
 	for i, rowData := range rowsData {
 		var row []tgbotapi.InlineKeyboardButton
@@ -429,12 +1554,7 @@ func (c *client) sendMessageWithInlineKeyboard(chatID int64, text string, rowsDa
 
 	}
 
-	message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
-
-	_, err := c.api.Send(message)
-	if err != nil {
-		c.logger.Error("cannot send telegram message (with inline keyboard)", zap.Error(err))
-	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
 func prepareCurrenciesKeyboard(currencies []string) [][][]string {
@@ -444,6 +1564,11 @@ func prepareCurrenciesKeyboard(currencies []string) [][][]string {
 		buttons = append(buttons, []string{flag + " " + code, code})
 	}
 
+	return splitKeyboard(buttons)
+}
+
+// splitKeyboard puts the buttons into rows of _buttonsPerRow.
+func splitKeyboard(buttons [][]string) [][][]string {
 	var keyboard [][][]string
 	for _buttonsPerRow < len(buttons) {
 		buttons, keyboard = buttons[_buttonsPerRow:], append(keyboard, buttons[:_buttonsPerRow:_buttonsPerRow])
@@ -4,6 +4,8 @@ package telegram
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
@@ -17,6 +19,8 @@ import (
 	tgmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/clients/telegram"
 	mmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model"
 	smocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage/inmemory"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
@@ -59,16 +63,26 @@ func setupClient(t *testing.T, i clientMocksInitializer) (*client, context.Conte
 	if i.storage != nil {
 		i.storage(storageMock)
 	}
+	// users who haven't chosen a language get the one of their Telegram client
+	storageMock.EXPECT().FetchLanguage(gomock.Any(), gomock.Any()).Return("", nil).AnyTimes()
 
 	c := &client{
-		api:     apiMock,
-		storage: storageMock,
-		logger:  zap.NewNop(),
+		api:               apiMock,
+		storage:           storageMock,
+		maxImportSize:     _defaultMaxImportSize,
+		maxAttachmentSize: _defaultMaxAttachmentSize,
+		dialogs:           inmemory.NewDialogStorage(),
+		dialogTTL:         _defaultDialogTTL,
+		logger:            zap.NewNop(),
 	}
 
 	if i.controller != nil {
 		controllerMock := mmocks.NewMockController(ctrl)
 		i.controller(controllerMock)
+		// the phrases of the tests are written in the currencies of the default config
+		controllerMock.EXPECT().IsCurrencyAvailable(gomock.Any()).DoAndReturn(func(code string) bool {
+			return code == "RUB" || code == "USD" || code == "EUR" || code == "CNY"
+		}).AnyTimes()
 		c.RegisterController(controllerMock)
 	}
 
@@ -292,7 +306,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 				m.EXPECT().SetCurrency(gomock.AssignableToTypeOf(test.CtxInterface), request.SetCurrency{
 					User: test.User,
 					Code: "EUR",
-				}).Return(response.SetCurrency(false))
+				}).Return(response.SetCurrency{})
 			},
 		})
 		defer cancel()
@@ -335,7 +349,10 @@ func Test_client_ListenUpdates(t *testing.T) {
 				m.EXPECT().SetCurrency(gomock.AssignableToTypeOf(test.CtxInterface), request.SetCurrency{
 					User: test.User,
 					Code: "EUR",
-				}).Return(response.SetCurrency(true))
+				}).Return(response.SetCurrency{
+					Operation: 5,
+					Success:   true,
+				})
 			},
 		})
 		defer cancel()
@@ -368,7 +385,10 @@ func Test_client_ListenUpdates(t *testing.T) {
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
 				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
 				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
-				m.EXPECT().Send(test.MessageTextContains("Готово"))
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Готово"),
+					test.MessageKeyboardData(_callbackUndo+_callbackSeparator+"5"),
+				))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
@@ -377,7 +397,10 @@ func Test_client_ListenUpdates(t *testing.T) {
 				m.EXPECT().SetCurrency(gomock.AssignableToTypeOf(test.CtxInterface), request.SetCurrency{
 					User: test.User,
 					Code: "RUB",
-				}).Return(response.SetCurrency(true))
+				}).Return(response.SetCurrency{
+					Operation: 5,
+					Success:   true,
+				})
 			},
 		})
 		defer cancel()
@@ -402,7 +425,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 					}
 				}()
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
-				m.EXPECT().Send(test.MessageTextContains("Не удалось задать лимит"))
+				m.EXPECT().Send(test.MessageTextContains("не удалось определить сумму лимита"))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
@@ -441,8 +464,9 @@ func Test_client_ListenUpdates(t *testing.T) {
 				m.EXPECT().SetLimit(gomock.AssignableToTypeOf(test.CtxInterface), request.SetLimit{
 					User:     test.User,
 					Value:    2000000,
+					Period:   types.LimitPeriod{Kind: types.PeriodMonth},
 					Category: "",
-				}).Return(response.SetLimit(false))
+				}).Return(response.SetLimit{})
 			},
 		})
 		defer cancel()
@@ -477,8 +501,146 @@ func Test_client_ListenUpdates(t *testing.T) {
 				m.EXPECT().SetLimit(gomock.AssignableToTypeOf(test.CtxInterface), request.SetLimit{
 					User:     test.User,
 					Value:    2500000,
+					Period:   types.LimitPeriod{Kind: types.PeriodMonth},
 					Category: "taxi & coffee",
-				}).Return(response.SetLimit(true))
+				}).Return(response.SetLimit{Success: true})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("limit set with period", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/limit 300 10d+ taxi"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(nil, test.SimpleError)
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().SetLimit(gomock.AssignableToTypeOf(test.CtxInterface), request.SetLimit{
+					User:     test.User,
+					Value:    3000000,
+					Period:   types.LimitPeriod{Kind: types.PeriodCustom, Days: 10, CarryOver: true},
+					Category: "taxi",
+				}).Return(response.SetLimit{Success: true})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("limit set for category named as period", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/limit 300 month day care"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(nil, test.SimpleError)
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().SetLimit(gomock.AssignableToTypeOf(test.CtxInterface), request.SetLimit{
+					User:     test.User,
+					Value:    3000000,
+					Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+					Category: "day care",
+				}).Return(response.SetLimit{Success: true})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("limit alerts invalid args error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/limit alerts 50,120"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Не удалось настроить уведомления"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("limit alerts set success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/limit alerts 50, 80,100"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().SetLimitAlerts(gomock.AssignableToTypeOf(test.CtxInterface), request.SetLimitAlerts{
+					User:       test.User,
+					Thresholds: []int{50, 80, 100},
+				}).Return(response.SetLimitAlerts{Success: true})
 			},
 		})
 		defer cancel()
@@ -869,7 +1031,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 					}
 				}()
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
-				m.EXPECT().Send(test.MessageTextContains("Готово"))
+				m.EXPECT().Send(gomock.All(test.MessageTextContains("Готово"), test.MessageKeyboardData(_callbackUndo+_callbackSeparator+"12")))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
@@ -881,8 +1043,10 @@ func Test_client_ListenUpdates(t *testing.T) {
 					Amount:   20200,
 					Category: "coffee",
 				}).Return(response.AddExpense{
-					Ready:   true,
-					Success: true,
+					ID:        7,
+					Ready:     true,
+					Operation: 12,
+					Success:   true,
 				})
 			},
 		})
@@ -923,9 +1087,9 @@ func Test_client_ListenUpdates(t *testing.T) {
 					Amount:   25000,
 					Category: "coffee",
 				}).Return(response.AddExpense{
-					Ready:        true,
-					Success:      true,
-					LimitReached: true,
+					Ready:      true,
+					Success:    true,
+					LimitAlert: 100,
 				})
 			},
 		})
@@ -938,7 +1102,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("report invalid syntax", func(t *testing.T) {
+	t.Run("add with limit alert", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
@@ -947,16 +1111,30 @@ func Test_client_ListenUpdates(t *testing.T) {
 				updates := make(chan tgbotapi.Update)
 				go func() {
 					updates <- tgbotapi.Update{
-						Message: newTestCommandMessage("/report taxi"),
+						Message: newTestCommandMessage("/add @ 2.5 coffee"),
 					}
 				}()
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
-				m.EXPECT().Send(test.MessageTextContains("Для просмотра расходов"))
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Готово"),
+					test.MessageTextContains("Ты израсходовал 80% заданного лимита"),
+				))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
 			},
-			controller: func(*mmocks.MockController) {},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()),
+					Amount:   25000,
+					Category: "coffee",
+				}).Return(response.AddExpense{
+					Ready:      true,
+					Success:    true,
+					LimitAlert: 80,
+				})
+			},
 		})
 		defer cancel()
 
@@ -967,7 +1145,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("report last week not ready", func(t *testing.T) {
+	t.Run("add with currency code", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
@@ -976,23 +1154,25 @@ func Test_client_ListenUpdates(t *testing.T) {
 				updates := make(chan tgbotapi.Update)
 				go func() {
 					updates <- tgbotapi.Update{
-						Message: newTestCommandMessage("/report"),
+						Message: newTestCommandMessage("/add 12.50 EUR coffee"),
 					}
 				}()
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
-				m.EXPECT().Send(test.MessageTextContains("Выполняется обновление курсов валют"))
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
 			},
 			controller: func(m *mmocks.MockController) {
-				from := utils.TruncateToDate(time.Now()).Add(-7 * 24 * time.Hour)
-				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
-					User: test.User,
-					From: from,
-				}).Return(response.GetReport{
-					From:  from,
-					Ready: false,
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()),
+					Amount:   125000,
+					Currency: "EUR",
+					Category: "coffee",
+				}).Return(response.AddExpense{
+					Ready:   true,
+					Success: true,
 				})
 			},
 		})
@@ -1005,7 +1185,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("report last week explicit emergency", func(t *testing.T) {
+	t.Run("add with currency symbol", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
@@ -1014,24 +1194,25 @@ func Test_client_ListenUpdates(t *testing.T) {
 				updates := make(chan tgbotapi.Update)
 				go func() {
 					updates <- tgbotapi.Update{
-						Message: newTestCommandMessage("/report 1w"),
+						Message: newTestCommandMessage("/add 12,5€ coffee"),
 					}
 				}()
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
-				m.EXPECT().Send(test.MessageTextContains("Не удалось сформировать отчёт"))
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
 			},
 			controller: func(m *mmocks.MockController) {
-				from := utils.TruncateToDate(time.Now()).Add(-7 * 24 * time.Hour)
-				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
-					User: test.User,
-					From: from,
-				}).Return(response.GetReport{
-					From:    from,
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()),
+					Amount:   125000,
+					Currency: "EUR",
+					Category: "coffee",
+				}).Return(response.AddExpense{
 					Ready:   true,
-					Success: false,
+					Success: true,
 				})
 			},
 		})
@@ -1044,7 +1225,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("report last 2 months no expenses", func(t *testing.T) {
+	t.Run("add with unknown currency", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
@@ -1053,25 +1234,25 @@ func Test_client_ListenUpdates(t *testing.T) {
 				updates := make(chan tgbotapi.Update)
 				go func() {
 					updates <- tgbotapi.Update{
-						Message: newTestCommandMessage("/report 2m"),
+						Message: newTestCommandMessage("/add 12.50£ coffee"),
 					}
 				}()
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
-				m.EXPECT().Send(test.MessageTextContains("Ты ещё не добавил ни одного расхода"))
+				m.EXPECT().Send(test.MessageTextContains("эта валюта не поддерживается"))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
 			},
 			controller: func(m *mmocks.MockController) {
-				from := utils.TruncateToDate(time.Now()).Add(-60 * 24 * time.Hour)
-				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
-					User: test.User,
-					From: from,
-				}).Return(response.GetReport{
-					From:    from,
-					Ready:   true,
-					Data:    make(map[string]int64),
-					Success: true,
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()),
+					Amount:   125000,
+					Currency: "GBP",
+					Category: "coffee",
+				}).Return(response.AddExpense{
+					Ready:           true,
+					UnknownCurrency: true,
 				})
 			},
 		})
@@ -1084,7 +1265,7 @@ func Test_client_ListenUpdates(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("report last 3 years complete", func(t *testing.T) {
+	t.Run("add guessed", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
@@ -1093,38 +1274,3536 @@ func Test_client_ListenUpdates(t *testing.T) {
 				updates := make(chan tgbotapi.Update)
 				go func() {
 					updates <- tgbotapi.Update{
-						Message: newTestCommandMessage("/report 3y"),
+						Message: newTestCommandMessage("/add такси 200 300"),
 					}
 				}()
 				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
-				from := utils.TruncateToDate(time.Now()).Add(-3 * 365 * 24 * time.Hour)
 				m.EXPECT().Send(gomock.All(
-					test.MessageTextContains("Расходы с "+from.Format("02.01.2006")+" (валюта — RUB)"),
-					test.MessageTextContains("hotel: 5000.00"),
-					test.MessageTextContains("кофе: 120.50"),
-					test.MessageTextContains("такси: 500.00"),
+					test.MessageTextContains("Добавить расход?"),
+					test.MessageTextContains("200.00 — такси 300"),
+					test.MessageKeyboardContains("✅ Добавить"),
+					test.MessageKeyboardContains("❌ Отмена"),
 				))
 			},
 			storage: func(m *smocks.MockTelegramUserStorage) {
 				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
 			},
-			controller: func(m *mmocks.MockController) {
-				from := utils.TruncateToDate(time.Now()).Add(-3 * 365 * 24 * time.Hour)
-				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
-					User: test.User,
-					From: from,
-				}).Return(response.GetReport{
-					From:     from,
-					Ready:    true,
-					Currency: "RUB",
-					Data: map[string]int64{
-						"такси": 5000000,
-						"кофе":  1205000,
-						"hotel": 50000000,
-					},
-					Success: true,
-				})
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("text without expense", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: &tgbotapi.Message{
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Text: "привет",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Извини, я не знаю такой команды."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
 			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("text proposal confirmed", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		proposed, done := make(chan struct{}), make(chan struct{})
+		c, _, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: &tgbotapi.Message{
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Text: "вчера такси 350",
+						},
+					}
+
+					// the expense is confirmed after it's proposed
+					<-proposed
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID:   "some-id",
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Data: "expense-confirm:✅",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("350.00 — такси"),
+					test.MessageKeyboardContains("✅ Добавить"),
+				)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(proposed)
+					return tgbotapi.Message{}, nil
+				})
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains(doneMessage)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(done)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil).Times(2)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()).Add(-24 * time.Hour),
+					Amount:   3500000,
+					Category: "такси",
+				}).Return(response.AddExpense{
+					ID:      12,
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// both updates have to be handled before the client stops listening for them
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Error("proposed expense has not been added")
+			}
+			stop()
+		}()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("confirm callback expired", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID:   "some-id",
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Data: "expense-confirm:✅",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(proposalExpiredMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("add dialog", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		amountAsked, categoryAsked, dateAsked, done := make(chan struct{}), make(chan struct{}), make(chan struct{}), make(chan struct{})
+		c, _, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/add"),
+					}
+
+					// every answer goes after the question
+					<-amountAsked
+					updates <- tgbotapi.Update{
+						Message: &tgbotapi.Message{
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Text: "12.50 EUR",
+						},
+					}
+
+					<-categoryAsked
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID:   "some-id",
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Data: "dialog:coffee",
+						},
+					}
+
+					<-dateAsked
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID:   "some-id",
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Data: "dialog:вчера",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(dialogAmountPrompt)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(amountAsked)
+					return tgbotapi.Message{}, nil
+				})
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains(dialogCategoryPrompt),
+					test.MessageKeyboardContains("coffee"),
+				)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(categoryAsked)
+					return tgbotapi.Message{}, nil
+				})
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil).Times(2)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains(dialogDatePrompt),
+					test.MessageKeyboardContains("Вчера"),
+				)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(dateAsked)
+					return tgbotapi.Message{}, nil
+				})
+				m.EXPECT().Send(test.MessageTextContains(doneMessage)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(done)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil).Times(4)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListExpenses(gomock.AssignableToTypeOf(test.CtxInterface), request.ListExpenses{
+					User: test.User,
+				}).Return(response.ListExpenses{
+					List: []types.Expense{
+						{Category: "coffee"},
+					},
+					Success: true,
+				})
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()).Add(-24 * time.Hour),
+					Amount:   125000,
+					Currency: "EUR",
+					Category: "coffee",
+				}).Return(response.AddExpense{
+					ID:      13,
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// all the updates have to be handled before the client stops listening for them
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Error("dialog has not been finished")
+			}
+			stop()
+		}()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("add dialog wrong amount", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: &tgbotapi.Message{
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Text: "12 кофе",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains(errWrongExpenseAmount.Error()),
+					test.MessageTextContains(dialogAmountPrompt),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		c.saveState(context.Background(), test.User, _stateDialog, dialog{Name: _dialogAdd, Step: "amount"})
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("cancel dialog", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/cancel"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(cancelDoneMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		c.saveState(context.Background(), test.User, _stateDialog, dialog{Name: _dialogAdd, Step: "category"})
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+
+		var d dialog
+		assert.False(t, c.loadState(context.Background(), test.User, _stateDialog, &d))
+	})
+
+	t.Run("undo added expense", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/undo"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(undoExpenseMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().Undo(gomock.AssignableToTypeOf(test.CtxInterface), request.Undo{
+					User: test.User,
+				}).Return(response.Undo{
+					Ready:   true,
+					Success: true,
+					Kind:    types.OperationAddExpense,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("undo nothing", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/undo"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(undoNothingMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().Undo(gomock.AssignableToTypeOf(test.CtxInterface), request.Undo{
+					User: test.User,
+				}).Return(response.Undo{
+					Ready:    true,
+					NotFound: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("undo callback", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							From: &tgbotapi.User{
+								ID:       test.TgUserID,
+								UserName: "tester",
+							},
+							Data: _callbackUndo + _callbackSeparator + "5",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains(undoCurrencyMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().Undo(gomock.AssignableToTypeOf(test.CtxInterface), request.Undo{
+					User:      test.User,
+					Operation: 5,
+				}).Return(response.Undo{
+					Ready:   true,
+					Success: true,
+					Kind:    types.OperationSetCurrency,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("undo callback without operation", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							From: &tgbotapi.User{
+								ID:       test.TgUserID,
+								UserName: "tester",
+							},
+							Data: _callbackUndo + _callbackSeparator,
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains(undoNothingMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("income with currency code", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/income 1500 USD freelance"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddIncome(gomock.AssignableToTypeOf(test.CtxInterface), request.AddIncome{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()),
+					Amount:   15000000,
+					Currency: "USD",
+					Category: "freelance",
+				}).Return(response.AddIncome{
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("income ambiguous phrase", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/income 1500 freelance 300"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(errAmbiguousPhrase.Error()))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("income in the future", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/income 1500 salary"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Ошибка: даты из будущего не поддерживаются."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddIncome(gomock.AssignableToTypeOf(test.CtxInterface), gomock.Any()).Return(response.AddIncome{
+					Invalid: model.ErrFutureDate,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("income syntax error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/income salary"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Не удалось добавить доход."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("income list", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/income"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Последние доходы:\n1. 05.11.2022 — 150000.00 RUB — зарплата"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListIncomes(gomock.AssignableToTypeOf(test.CtxInterface), request.ListIncomes{
+					User: test.User,
+				}).Return(response.ListIncomes{
+					List: []types.Income{
+						{
+							ExpenseItem: types.ExpenseItem{ID: 1, Date: time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC), Amount: 1500000000, Currency: "RUB"},
+							Category:    "зарплата",
+						},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("add with note and tags", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage(`/add 20.10.2022 450 #Card Food / Coffee "with client" #work #card`),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+					Amount:   4500000,
+					Category: "food/coffee",
+					Note:     "with client",
+					Tags:     []string{"card", "work"},
+				}).Return(response.AddExpense{
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("add with split", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		alice := types.User(101)
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/add 20.10.2022 3000 restaurant #card split @alice"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("@tester разделил с тобой расход «restaurant»: твоя доля — 1500.00 RUB."))
+				m.EXPECT().Send(test.MessageTextContains("Доли участников:\n@alice: 1500.00 RUB"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+				m.EXPECT().FetchTelegramID(gomock.AssignableToTypeOf(test.CtxInterface), &alice).Return(int64(1001), nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+					Amount:   30000000,
+					Category: "restaurant",
+					Tags:     []string{"card"},
+					Split:    []string{"@alice"},
+				}).Return(response.AddExpense{
+					Ready: true,
+					Debts: []types.Debt{{
+						Creditor:     test.User,
+						CreditorName: "@tester",
+						Debtor:       &alice,
+						DebtorName:   "@alice",
+						Amount:       15000000,
+						Currency:     "RUB",
+						Category:     "restaurant",
+					}},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("debts", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		alice, bob := types.User(101), types.User(102)
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/debts"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Тебе должны:\n@alice: 1500.00 RUB\n\nТы должен:\nBob: 20.00 USD"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListDebts(gomock.AssignableToTypeOf(test.CtxInterface), request.ListDebts{
+					User: test.User,
+				}).Return(response.ListDebts{
+					List: []types.DebtBalance{
+						{Counterparty: &alice, Name: "@alice", Currency: "RUB", Amount: 15000000},
+						{Counterparty: &bob, Name: "Bob", Currency: "USD", Amount: -200000},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("settle", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		alice := types.User(101)
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/settle @alice RUB"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("@tester отметил закрытым долг между вами: ты должен @tester 1500.00 RUB"))
+				m.EXPECT().Send(test.MessageTextContains("Закрыты долги:\n@alice должен тебе 1500.00 RUB"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+				m.EXPECT().FetchTelegramID(gomock.AssignableToTypeOf(test.CtxInterface), &alice).Return(int64(1001), nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().SettleDebts(gomock.AssignableToTypeOf(test.CtxInterface), request.SettleDebts{
+					User:     test.User,
+					Name:     "@alice",
+					Currency: "RUB",
+				}).Return(response.SettleDebts{
+					Settled: []types.DebtBalance{{Counterparty: &alice, Name: "@alice", Currency: "RUB", Amount: 15000000}},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("goals add", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/goals add vacation 200000 RUB by 01.06.2023"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(doneMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddGoal(gomock.AssignableToTypeOf(test.CtxInterface), request.AddGoal{
+					User:     test.User,
+					Name:     "vacation",
+					Target:   2000000000,
+					Currency: "RUB",
+					Deadline: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+				}).Return(response.AddGoal{
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("goals", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/goals"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(
+					"<b>vacation</b>: 56000.00/200000.00 RUB\n<code>▓▓░░░░░░░░</code> 28%\nЧтобы успеть к 01.06.2100, откладывай по 20571.43 RUB в месяц." +
+						"\n\n<b>laptop</b>: 1500.00/1500.00 USD (≈ 90000.00 RUB)\n<code>▓▓▓▓▓▓▓▓▓▓</code> 100%\nЦель достигнута! 🎉",
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListGoals(gomock.AssignableToTypeOf(test.CtxInterface), request.ListGoals{
+					User: test.User,
+				}).Return(response.ListGoals{
+					Currency: "RUB",
+					Ready:    true,
+					List: []types.GoalProgress{
+						{
+							Goal:      types.Goal{ID: 3, Name: "vacation", Target: 2000000000, Currency: "RUB", Deadline: time.Date(2100, 6, 1, 0, 0, 0, 0, time.UTC)},
+							Saved:     560000000,
+							Converted: 560000000,
+							Monthly:   205714286,
+						},
+						{
+							Goal:      types.Goal{ID: 4, Name: "laptop", Target: 15000000, Currency: "USD"},
+							Saved:     15000000,
+							Converted: 900000000,
+						},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("account add", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/account add savings USD 1000"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddAccount(gomock.AssignableToTypeOf(test.CtxInterface), request.AddAccount{
+					User:     test.User,
+					Name:     "savings",
+					Currency: "USD",
+					Opening:  10000000,
+				}).Return(response.AddAccount{
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("account list", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/account"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Счета:\n#card — RUB\n#savings — USD"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListAccounts(gomock.AssignableToTypeOf(test.CtxInterface), request.ListAccounts{
+					User: test.User,
+				}).Return(response.ListAccounts{
+					List: []types.Account{
+						{ID: 1, Name: "card", Currency: "RUB"},
+						{ID: 2, Name: "savings", Currency: "USD"},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("balance", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/balance"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("#card: 500.00 RUB\n#savings: 100.00 USD ≈ 6150.00 RUB\n\nВсего: 6650.00 RUB"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().GetBalance(gomock.AssignableToTypeOf(test.CtxInterface), request.GetBalance{
+					User: test.User,
+				}).Return(response.GetBalance{
+					Currency: "RUB",
+					Ready:    true,
+					List: []types.AccountBalance{
+						{Account: types.Account{ID: 1, Name: "card", Currency: "RUB"}, Balance: 5000000, Converted: 5000000},
+						{Account: types.Account{ID: 2, Name: "savings", Currency: "USD"}, Balance: 1000000, Converted: 61500000},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/transfer card savings 6150"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("С #card списано 6150.00 RUB, на #savings зачислено 100.00 USD"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().Transfer(gomock.AssignableToTypeOf(test.CtxInterface), request.Transfer{
+					User:   test.User,
+					Date:   utils.TruncateToDate(time.Now()),
+					From:   "card",
+					To:     "savings",
+					Amount: 61500000,
+				}).Return(response.Transfer{
+					Ready:    true,
+					From:     types.Account{ID: 1, Name: "card", Currency: "RUB"},
+					To:       types.Account{ID: 2, Name: "savings", Currency: "USD"},
+					Received: 1000000,
+					Success:  true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("group create", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/group create"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("/group join ABCD2345"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().CreateGroup(gomock.AssignableToTypeOf(test.CtxInterface), request.CreateGroup{
+					User: test.User,
+					Name: "@tester",
+				}).Return(response.CreateGroup{
+					Group:   types.Group{ID: 1, Code: "ABCD2345"},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("group join", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		owner := types.User(101)
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/group join abcd2345"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("@alice — владелец\n@tester — участник"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().JoinGroup(gomock.AssignableToTypeOf(test.CtxInterface), request.JoinGroup{
+					User: test.User,
+					Code: "abcd2345",
+					Name: "@tester",
+				}).Return(response.JoinGroup{
+					Group: types.Group{
+						ID:   1,
+						Code: "ABCD2345",
+						Members: []types.GroupMember{
+							{User: &owner, Name: "@alice", Role: types.RoleOwner},
+							{User: test.User, Name: "@tester", Role: types.RoleMember},
+						},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("group limit by member", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/limit 1000 taxi"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("лимиты группы задаёт только её владелец"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().SetLimit(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(request.SetLimit{})).Return(response.SetLimit{
+					Forbidden: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report with group members", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Расходы участников группы:\n@alice: 150.00\n@tester: 50.00\n"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from := utils.TruncateToDate(time.Now()).Add(-7 * 24 * time.Hour)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   test.Tomorrow,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       test.Tomorrow,
+					Currency: "RUB",
+					Ready:    true,
+					Data:     map[string]int64{"coffee": 2000000},
+					Members: []types.MemberTotal{
+						{Name: "@alice", Amount: 1500000},
+						{Name: "@tester", Amount: 500000},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("list empty", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/list"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Ты ещё не добавил ни одного расхода"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListExpenses(gomock.AssignableToTypeOf(test.CtxInterface), request.ListExpenses{
+					User: test.User,
+				}).Return(response.ListExpenses{
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("list complete", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/list"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("1. 21.10.2022 — 150.00 RUB — taxi"),
+					test.MessageTextContains("2. 20.10.2022 — 2.50 USD — coffee"),
+					test.MessageKeyboardContains("✏️ 2"),
+					test.MessageKeyboardContains("🗑 1"),
+					test.MessageKeyboardContains("📎 1"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListExpenses(gomock.AssignableToTypeOf(test.CtxInterface), request.ListExpenses{
+					User: test.User,
+				}).Return(response.ListExpenses{
+					List: []types.Expense{
+						{
+							ExpenseItem: types.ExpenseItem{
+								ID:       12,
+								Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+								Amount:   1500000,
+								Currency: "RUB",
+							},
+							Category: "taxi",
+						},
+						{
+							ExpenseItem: types.ExpenseItem{
+								ID:       11,
+								Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+								Amount:   25000,
+								Currency: "USD",
+							},
+							Category: "coffee",
+						},
+					},
+					Attached: map[int64]bool{12: true},
+					Success:  true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("edit callback prompt", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID: "some-id",
+							From: &tgbotapi.User{
+								ID:       test.TgUserID,
+								UserName: "tester",
+							},
+							Data: "expense-edit:12",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains("/edit 12 21.10.2022 150.00 RUB taxi"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.GetExpense{
+					User: test.User,
+					ID:   12,
+				}).Return(response.GetExpense{
+					Item: types.Expense{
+						ExpenseItem: types.ExpenseItem{
+							ID:       12,
+							Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+							Amount:   1500000,
+							Currency: "RUB",
+						},
+						Category: "taxi",
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("edit not found", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/edit 13 -1d 3.5 tea"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Расход не найден"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().UpdateExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.UpdateExpense{
+					User:     test.User,
+					ID:       13,
+					Date:     utils.TruncateToDate(time.Now()).Add(-24 * time.Hour),
+					Amount:   35000,
+					Category: "tea",
+				}).Return(response.UpdateExpense{
+					Ready:    true,
+					NotFound: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("edit success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/edit 12 150 taxi"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().UpdateExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.UpdateExpense{
+					User:     test.User,
+					ID:       12,
+					Date:     utils.TruncateToDate(time.Now()),
+					Amount:   1500000,
+					Category: "taxi",
+				}).Return(response.UpdateExpense{
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete callback success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID: "some-id",
+							From: &tgbotapi.User{
+								ID:       test.TgUserID,
+								UserName: "tester",
+							},
+							Data: "expense-delete:12",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().DeleteExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.DeleteExpense{
+					User: test.User,
+					ID:   12,
+				}).Return(response.DeleteExpense{
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report invalid syntax", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report taxi"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Для просмотра расходов"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report last week not ready", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Выполняется обновление курсов валют"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from := utils.TruncateToDate(time.Now()).Add(-7 * 24 * time.Hour)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   test.Tomorrow,
+				}).Return(response.GetReport{
+					From:  from,
+					To:    test.Tomorrow,
+					Ready: false,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report last week explicit emergency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 1w"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Не удалось сформировать отчёт"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from := utils.TruncateToDate(time.Now()).Add(-7 * 24 * time.Hour)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   test.Tomorrow,
+				}).Return(response.GetReport{
+					From:    from,
+					To:      test.Tomorrow,
+					Ready:   true,
+					Success: false,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report last 2 months no expenses", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 2m"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Ты ещё не добавил ни одного расхода"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from := utils.TruncateToDate(time.Now()).Add(-60 * 24 * time.Hour)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   test.Tomorrow,
+				}).Return(response.GetReport{
+					From:    from,
+					To:      test.Tomorrow,
+					Ready:   true,
+					Data:    make(map[string]int64),
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report last 3 years complete", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 3y"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				from := utils.TruncateToDate(time.Now()).Add(-3 * 365 * 24 * time.Hour)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Расходы с "+from.Format("02.01.2006")+" по "+test.Today.Format("02.01.2006")+" (валюта — RUB)"),
+					test.MessageTextContains("hotel: 5000.00"),
+					test.MessageTextContains("кофе: 120.50"),
+					test.MessageTextContains("такси: 500.00"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from := utils.TruncateToDate(time.Now()).Add(-3 * 365 * 24 * time.Hour)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   test.Tomorrow,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       test.Tomorrow,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"такси": 5000000,
+						"кофе":  1205000,
+						"hotel": 50000000,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report with incomes", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 01.10.2022-31.10.2022"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("такси: 500.00"),
+					test.MessageTextContains("Доходы:\nзарплата: 150000.00"),
+					test.MessageTextContains("Всего доходов: 150000.00\nВсего расходов: 500.00\nСбережения: 149500.00"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   to,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"такси": 5000000,
+					},
+					Income: map[string]int64{
+						"зарплата": 1500000000,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report without incomes", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 01.10.2022-31.10.2022"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("такси: 500.00"),
+					gomock.Not(test.MessageTextContains("Всего доходов")),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   to,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"такси": 5000000,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report date range", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 01.09.2022-30.09.2022"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Расходы с 01.09.2022 по 30.09.2022 (валюта — RUB)"),
+					test.MessageTextContains("кофе: 120.50"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from, to := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   to,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"кофе": 1205000,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report last month", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report last month"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Расходы с "),
+					test.MessageTextContains("кофе: 120.50"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from, to := test.Today.AddDate(0, -1, 1-test.Today.Day()), test.Today.AddDate(0, 0, 1-test.Today.Day())
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   to,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"кофе": 1205000,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report year to date", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report YTD"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Расходы с 01.01."),
+					test.MessageTextContains("кофе: 120.50"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from, to := time.Date(test.Today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC), test.Tomorrow
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User: test.User,
+					From: from,
+					To:   to,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"кофе": 1205000,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report reversed date range error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 30.09.2022-01.09.2022"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("начало периода должно быть не позже его окончания"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report grouped by month", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 01.09.2022-31.10.2022 by month"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Расходы по месяцам с 01.09.2022 по 31.10.2022 (валюта — RUB)"),
+					test.MessageTextContains("09.2022   500   500"),
+					test.MessageTextContains("10.2022   750   750 +50%"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from, to := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User:     test.User,
+					From:     from,
+					To:       to,
+					Grouping: types.GroupingMonth,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Grouping: types.GroupingMonth,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"такси": 12500000,
+					},
+					Buckets: []types.ReportBucket{
+						{Start: from, Data: map[string]int64{"такси": 5000000}},
+						{Start: from.AddDate(0, 1, 0), Data: map[string]int64{"такси": 7500000}},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report compare ranges", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report compare 01.09.2022-30.09.2022 01.10.2022-31.10.2022"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Расходы с 01.10.2022 по 31.10.2022 в сравнении с 01.09.2022 по 30.09.2022 (валюта — RUB)"),
+					test.MessageTextContains("такси  500   750 +250 +50%"),
+					test.MessageTextContains("Новые категории: кофе"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				compare := types.DateRange{From: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)}
+				from, to := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User:    test.User,
+					From:    from,
+					To:      to,
+					Compare: compare,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Compare:  compare,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"такси": 7500000,
+						"кофе":  1000000,
+					},
+					Previous: map[string]int64{
+						"такси": 5000000,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("export range xlsx", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/export 01.10.2022-31.10.2022 xlsx"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(tgbotapi.DocumentConfig{})).DoAndReturn(func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+					doc := c.(tgbotapi.DocumentConfig)
+					assert.Equal(t, "Расходы с 01.10.2022 по 31.10.2022: 2 шт. (сумма в валюте — RUB)", doc.Caption)
+					assert.Equal(t, tgbotapi.FileBytes{Name: "expenses.xlsx", Bytes: []byte("content")}, doc.File)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ExportExpenses(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(request.ExportExpenses{})).DoAndReturn(func(_ context.Context, req request.ExportExpenses) response.ExportExpenses {
+					assert.Equal(t, time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), req.From)
+					assert.Equal(t, time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), req.To)
+					assert.Equal(t, types.ExportXLSX, req.Format)
+
+					_, _ = req.Writer.Write([]byte("content"))
+
+					return response.ExportExpenses{
+						Currency: "RUB",
+						Ready:    true,
+						Count:    2,
+						Success:  true,
+					}
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("export no expenses", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/export"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(exportNoExpenses))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ExportExpenses(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(request.ExportExpenses{})).DoAndReturn(func(_ context.Context, req request.ExportExpenses) response.ExportExpenses {
+					assert.True(t, req.From.IsZero())
+					assert.True(t, req.To.IsZero())
+					assert.Equal(t, types.ExportCSV, req.Format)
+
+					return response.ExportExpenses{
+						Currency: "RUB",
+						Ready:    true,
+						Success:  true,
+					}
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("report charts", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		sent := make(chan struct{})
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 20.10.2022-21.10.2022 chart"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(tgbotapi.PhotoConfig{})).DoAndReturn(func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+					assert.Contains(t, c.(tgbotapi.PhotoConfig).Caption, "🟥 такси: 500.00 (80%)")
+					assert.Contains(t, c.(tgbotapi.PhotoConfig).Caption, "🟧 кофе: 125.00 (20%)")
+					return tgbotapi.Message{}, nil
+				})
+				m.EXPECT().Send(gomock.AssignableToTypeOf(tgbotapi.PhotoConfig{})).DoAndReturn(func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+					assert.Contains(t, c.(tgbotapi.PhotoConfig).Caption, "Расходы по дням с 20.10.2022 по 21.10.2022")
+					close(sent)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from, to := time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User:     test.User,
+					From:     from,
+					To:       to,
+					Grouping: types.GroupingDay,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Grouping: types.GroupingDay,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"такси": 5000000,
+						"кофе":  1250000,
+					},
+					Buckets: []types.ReportBucket{
+						{Start: from, Data: map[string]int64{"такси": 5000000}},
+						{Start: from.AddDate(0, 0, 1), Data: map[string]int64{"кофе": 1250000}},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+
+		// rendering takes longer than the client is listening for updates
+		select {
+		case <-sent:
+		case <-time.After(5 * time.Second):
+			t.Error("charts have not been sent")
+		}
+	})
+
+	t.Run("report charts of zero amounts", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		sent := make(chan struct{})
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/report 20.10.2022-21.10.2022 chart"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Ты ещё не добавил ни одного расхода")).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(sent)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				from, to := time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), request.GetReport{
+					User:     test.User,
+					From:     from,
+					To:       to,
+					Grouping: types.GroupingDay,
+				}).Return(response.GetReport{
+					From:     from,
+					To:       to,
+					Grouping: types.GroupingDay,
+					Ready:    true,
+					Currency: "RUB",
+					Data: map[string]int64{
+						"такси": 0,
+					},
+					Buckets: []types.ReportBucket{
+						{Start: from, Data: map[string]int64{"такси": 0}},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+
+		// rendering takes longer than the client is listening for updates
+		select {
+		case <-sent:
+		case <-time.After(5 * time.Second):
+			t.Error("charts have not been sent")
+		}
+	})
+
+	t.Run("import statement", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		statement := []byte("date,amount,merchant\n2022-10-15,-100,Starbucks\n")
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(statement)
+		}))
+		defer server.Close()
+
+		sent := make(chan struct{})
+		c, _, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				message := &tgbotapi.Message{
+					From:     &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+					Caption:  "tinkoff",
+					Document: &tgbotapi.Document{FileID: "file-1", FileName: "statement.csv", FileSize: len(statement)},
+				}
+
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{Message: message}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().GetFileDirectURL("file-1").Return(server.URL+"/statement.csv", nil)
+				m.EXPECT().Send(test.MessageTextContains("Добавлено: 2, загружено ранее: 1.\n\nкофе: " + limitReached)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(sent)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ImportExpenses(gomock.AssignableToTypeOf(test.CtxInterface), request.ImportExpenses{
+					User: test.User,
+					Name: "statement.csv",
+					Bank: "tinkoff",
+					Data: statement,
+				}).Return(response.ImportExpenses{
+					Ready:       true,
+					Parsed:      3,
+					Imported:    2,
+					LimitAlerts: map[string]int{"кофе": 100},
+					Success:     true,
+				})
+			},
+		})
+		defer cancel()
+
+		// the download would be cancelled along with the short context of the client
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-sent:
+			case <-time.After(5 * time.Second):
+				t.Error("import result has not been sent")
+			}
+			stop()
+		}()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("add with photo caption", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("photo"))
+		}))
+		defer server.Close()
+
+		sent := make(chan struct{})
+		c, _, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				message := &tgbotapi.Message{
+					From:    &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+					Caption: "/add 2,02 coffee",
+					Photo: []tgbotapi.PhotoSize{
+						{FileID: "photo-small", FileSize: 1},
+						{FileID: "photo-large", FileSize: 5},
+					},
+				}
+
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{Message: message}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().GetFileDirectURL("photo-large").Return(server.URL+"/photo.jpg", nil)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Готово"),
+					test.MessageTextContains("Фото прикреплено к расходу."),
+				)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(sent)
+					return tgbotapi.Message{MessageID: 77}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     utils.TruncateToDate(time.Now()),
+					Amount:   20200,
+					Category: "coffee",
+				}).Return(response.AddExpense{
+					ID:      12,
+					Ready:   true,
+					Success: true,
+				})
+				m.EXPECT().AttachToExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AttachToExpense{
+					User:      test.User,
+					ExpenseID: 12,
+					Data:      []byte("photo"),
+				}).Return(response.AttachToExpense{
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// the download would be cancelled along with the short context of the client
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-sent:
+			case <-time.After(5 * time.Second):
+				t.Error("confirmation has not been sent")
+			}
+			stop()
+		}()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+
+		var last confirmation
+		assert.True(t, c.loadState(context.Background(), test.User, _stateConfirmation, &last))
+		assert.Equal(t, confirmation{MessageID: 77, ExpenseID: 12}, last)
+	})
+
+	t.Run("attach too large photo in reply", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				message := &tgbotapi.Message{
+					From:           &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+					ReplyToMessage: &tgbotapi.Message{MessageID: 77},
+					Photo: []tgbotapi.PhotoSize{
+						{FileID: "photo-large", FileSize: _defaultMaxAttachmentSize + 1},
+					},
+				}
+
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{Message: message}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Фото должно быть не больше 5120 КБ."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		c.saveState(context.Background(), test.User, _stateConfirmation, confirmation{MessageID: 77, ExpenseID: 12})
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("attachment callback", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID: "some-id",
+							From: &tgbotapi.User{
+								ID:       test.TgUserID,
+								UserName: "tester",
+							},
+							Data: "expense-attachment:12",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(tgbotapi.PhotoConfig{})).DoAndReturn(func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+					assert.Equal(t, []byte("photo"), c.(tgbotapi.PhotoConfig).File.(tgbotapi.FileBytes).Bytes)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().GetAttachment(gomock.AssignableToTypeOf(test.CtxInterface), request.GetAttachment{
+					User:      test.User,
+					ExpenseID: 12,
+				}).Return(response.GetAttachment{
+					Data:    []byte("photo"),
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("import too large statement", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				message := &tgbotapi.Message{
+					From:     &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+					Document: &tgbotapi.Document{FileID: "file-1", FileName: "statement.csv", FileSize: _defaultMaxImportSize + 1},
+				}
+
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{Message: message}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Файл выписки должен быть не больше 5120 КБ."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("set merchant rule", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/rule Yandex Taxi = такси"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(doneMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().SetMerchantRule(gomock.AssignableToTypeOf(test.CtxInterface), request.SetMerchantRule{
+					User:     test.User,
+					Pattern:  "Yandex Taxi",
+					Category: "такси",
+				}).Return(response.SetMerchantRule{Success: true})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("list merchant rules", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/rule"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("<code>a&amp;b</code> → магазин\n<code>starbucks</code> → кофе"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListMerchantRules(gomock.AssignableToTypeOf(test.CtxInterface), request.ListMerchantRules{User: test.User}).Return(response.ListMerchantRules{
+					List: []types.MerchantRule{
+						{Pattern: "a&b", Category: "магазин"},
+						{Pattern: "starbucks", Category: "кофе"},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("receipt with category", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/add t=20221021T1530&s=1234.50&fn=9960440300000001&i=12345&fp=1234567890&n=1 продукты"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(receiptDuplicateMessage))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+					Amount:   12345000,
+					Currency: "RUB",
+					Category: "продукты",
+					Receipt:  &types.Receipt{Drive: "9960440300000001", Document: "12345", Sign: "1234567890"},
+				}).Return(response.AddExpense{
+					Ready:     true,
+					Duplicate: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("receipt category callback", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		prompted, done := make(chan struct{}), make(chan struct{})
+		c, _, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: &tgbotapi.Message{
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Text: "t=20221021T1530&s=99.90&fn=1&i=2&fp=3&n=1",
+						},
+					}
+
+					// the category is chosen after the prompt is shown
+					<-prompted
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID:   "some-id",
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Data: "receipt:кофе",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("Чек от 21.10.2022 на 99.90 RUB."),
+					test.MessageKeyboardContains("кофе"),
+					test.MessageKeyboardContains("такси"),
+				)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(prompted)
+					return tgbotapi.Message{}, nil
+				})
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains(doneMessage)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(done)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil).Times(2)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListExpenses(gomock.AssignableToTypeOf(test.CtxInterface), request.ListExpenses{User: test.User}).Return(response.ListExpenses{
+					List: []types.Expense{
+						{Category: "кофе"},
+						{Category: "такси"},
+						{Category: "кофе"},
+					},
+					Success: true,
+				})
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+					Amount:   999000,
+					Currency: "RUB",
+					Category: "кофе",
+					Receipt:  &types.Receipt{Drive: "1", Document: "2", Sign: "3"},
+				}).Return(response.AddExpense{
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// both updates have to be handled before the client stops listening for them
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Error("receipt expense has not been added")
+			}
+			stop()
+		}()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+
+		var pending pendingReceipt
+		assert.False(t, c.loadState(context.Background(), test.User, _stateReceipt, &pending))
+	})
+
+	t.Run("receipt category in reply", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		prompted, done := make(chan struct{}), make(chan struct{})
+		c, _, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: &tgbotapi.Message{
+							From: &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Text: "t=20221021T1530&s=99.90&fn=1&i=2&fp=3&n=1",
+						},
+					}
+
+					<-prompted
+					updates <- tgbotapi.Update{
+						Message: &tgbotapi.Message{
+							From:           &tgbotapi.User{ID: test.TgUserID, UserName: "tester"},
+							Text:           "кофе",
+							ReplyToMessage: &tgbotapi.Message{MessageID: 42},
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Чек от 21.10.2022 на 99.90 RUB.")).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					defer close(prompted)
+					return tgbotapi.Message{MessageID: 42}, nil
+				})
+				m.EXPECT().Send(test.MessageTextContains(doneMessage)).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(done)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil).Times(2)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListExpenses(gomock.AssignableToTypeOf(test.CtxInterface), request.ListExpenses{User: test.User}).Return(response.ListExpenses{
+					Success: true,
+				})
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), request.AddExpense{
+					User:     test.User,
+					Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+					Amount:   999000,
+					Currency: "RUB",
+					Category: "кофе",
+					Receipt:  &types.Receipt{Drive: "1", Document: "2", Sign: "3"},
+				}).Return(response.AddExpense{
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// both updates have to be handled before the client stops listening for them
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Error("receipt expense has not been added")
+			}
+			stop()
+		}()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("command drops pending receipt", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		done := make(chan struct{})
+		c, _, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/start"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Привет")).DoAndReturn(func(tgbotapi.Chattable) (tgbotapi.Message, error) {
+					close(done)
+					return tgbotapi.Message{}, nil
+				})
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		c.saveState(context.Background(), test.User, _stateReceipt, pendingReceipt{MessageID: 42})
+
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Error("command has not been handled")
+			}
+			stop()
+		}()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+
+		var pending pendingReceipt
+		assert.False(t, c.loadState(context.Background(), test.User, _stateReceipt, &pending))
+	})
+
+	t.Run("recurring add", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		start := time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)
+		recurrence := types.Recurrence{Frequency: types.FrequencyMonthly, Interval: 2, Start: start}
+
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/recurring add FREQ=MONTHLY;INTERVAL=2 05.11.2022 300 USD hosting"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains(doneMessage),
+					test.MessageTextContains("300.00 USD — hosting — каждые 2 месяца, следующий 05.11.2022"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().AddRecurring(gomock.AssignableToTypeOf(test.CtxInterface), request.AddRecurring{
+					User:       test.User,
+					Recurrence: recurrence,
+					Amount:     3000000,
+					Currency:   "USD",
+					Category:   "hosting",
+				}).Return(response.AddRecurring{
+					Item: types.RecurringExpense{
+						ID:         1,
+						Recurrence: recurrence,
+						Amount:     3000000,
+						Currency:   "USD",
+						Category:   "hosting",
+						Next:       start,
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("recurring add wrong schedule", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/recurring add 0m 300 hosting"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains(errWrongRecurrence.Error()))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("recurring list", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/recurring"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("1. 30000.00 RUB — аренда — каждый месяц, следующий 05.12.2022"),
+					test.MessageTextContains("2. 150.00 RUB — обед — каждые 3 дня, следующий 12.11.2022 — ⏸ приостановлен"),
+					test.MessageKeyboardContains("⏸ 1"),
+					test.MessageKeyboardContains("▶️ 2"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListRecurring(gomock.AssignableToTypeOf(test.CtxInterface), request.ListRecurring{
+					User: test.User,
+				}).Return(response.ListRecurring{
+					List: []types.RecurringExpense{
+						{
+							ID:         1,
+							Recurrence: types.Recurrence{Frequency: types.FrequencyMonthly, Interval: 1},
+							Amount:     300000000,
+							Currency:   "RUB",
+							Category:   "аренда",
+							Next:       time.Date(2022, 12, 5, 0, 0, 0, 0, time.UTC),
+						},
+						{
+							ID:         2,
+							Recurrence: types.Recurrence{Frequency: types.FrequencyDaily, Interval: 3},
+							Amount:     1500000,
+							Currency:   "RUB",
+							Category:   "обед",
+							Next:       time.Date(2022, 11, 12, 0, 0, 0, 0, time.UTC),
+							Paused:     true,
+						},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("recurring pause callback", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID: "some-id",
+							From: &tgbotapi.User{
+								ID:       test.TgUserID,
+								UserName: "tester",
+							},
+							Data: "recurring-pause:1",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains("Регулярный расход «аренда» приостановлен."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().SetRecurringPaused(gomock.AssignableToTypeOf(test.CtxInterface), request.SetRecurringPaused{
+					User:   test.User,
+					ID:     1,
+					Paused: true,
+				}).Return(response.SetRecurringPaused{
+					Item:    types.RecurringExpense{ID: 1, Category: "аренда", Paused: true},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("find with next page", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/find coffee #work from 01.10.2022 >300"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(gomock.All(
+					test.MessageTextContains("20.10.2022 — 450.00 RUB — food/coffee — «with client» #work"),
+					test.MessageKeyboardContains("▶️"),
+				))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().FindExpenses(gomock.AssignableToTypeOf(test.CtxInterface), request.FindExpenses{
+					User: test.User,
+					Filter: types.ExpenseFilter{
+						Query: "coffee work",
+						From:  time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+						Min:   3000000,
+					},
+				}).Return(response.FindExpenses{
+					List: []types.Expense{{
+						ExpenseItem: types.ExpenseItem{
+							Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+							Amount:   4500000,
+							Currency: "RUB",
+							Note:     "with client",
+							Tags:     []string{"work"},
+						},
+						Category: "food/coffee",
+					}},
+					More:    true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("find empty filter error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/find #"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Не удалось найти расходы."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("find callback expired", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID: "some-id",
+							From: &tgbotapi.User{
+								ID:       test.TgUserID,
+								UserName: "tester",
+							},
+							Data: "expense-find:1",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains("Результаты поиска устарели"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("categories tree", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/categories"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("food\n    coffee <i>(кофе)</i>\n    restaurant\ntaxi"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListCategories(gomock.AssignableToTypeOf(test.CtxInterface), request.ListCategories{
+					User: test.User,
+				}).Return(response.ListCategories{
+					List: []types.Category{
+						{Name: "food/coffee", Aliases: []string{"кофе"}},
+						{Name: "food/restaurant"},
+						{Name: "taxi"},
+					},
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("categories rename to existing", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/categories rename taxi = transport/taxi"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Не удалось переименовать категорию."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().RenameCategory(gomock.AssignableToTypeOf(test.CtxInterface), request.RenameCategory{
+					User: test.User,
+					From: "taxi",
+					To:   "transport/taxi",
+				}).Return(response.RenameCategory{
+					AlreadyExists: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("categories merge", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/categories merge такси = transport/taxi"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Готово"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().RenameCategory(gomock.AssignableToTypeOf(test.CtxInterface), request.RenameCategory{
+					User:  test.User,
+					From:  "такси",
+					To:    "transport/taxi",
+					Merge: true,
+				}).Return(response.RenameCategory{
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("start in english", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					message := newTestCommandMessage("/start")
+					message.From.LanguageCode = "en"
+					updates <- tgbotapi.Update{
+						Message: message,
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Hi!"))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("chosen language overrides the client one", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					message := newTestCommandMessage("/limit")
+					message.From.LanguageCode = "ru"
+					updates <- tgbotapi.Update{
+						Message: message,
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("No limits are set yet."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchLanguage(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return("en", nil)
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(m *mmocks.MockController) {
+				m.EXPECT().ListLimits(gomock.AssignableToTypeOf(test.CtxInterface), gomock.Any()).Return(response.ListLimits{
+					Ready:   true,
+					Success: true,
+				})
+			},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("lang", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/lang en"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Done! I speak English now."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+				m.EXPECT().SetLanguage(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID, "en").Return(nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("lang unknown", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						Message: newTestCommandMessage("/lang klingon"),
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				m.EXPECT().Send(test.MessageTextContains("Ошибка: этот язык не поддерживается."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+			},
+			controller: func(*mmocks.MockController) {},
+		})
+		defer cancel()
+
+		// ACT
+		err := c.ListenUpdates(ctx)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("lang callback", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		c, ctx, cancel := setupClient(t, clientMocksInitializer{
+			api: func(m *tgmocks.Mockapi) {
+				updates := make(chan tgbotapi.Update)
+				go func() {
+					updates <- tgbotapi.Update{
+						CallbackQuery: &tgbotapi.CallbackQuery{
+							ID: "some-id",
+							From: &tgbotapi.User{
+								ID:           test.TgUserID,
+								UserName:     "tester",
+								LanguageCode: "en",
+							},
+							Data: "lang:ru",
+						},
+					}
+				}()
+				m.EXPECT().GetUpdatesChan(gomock.AssignableToTypeOf(test.UpdateConfigInterface)).Return(updates)
+				var callback = reflect.TypeOf((*tgbotapi.CallbackConfig)(nil)).Elem()
+				m.EXPECT().Request(gomock.AssignableToTypeOf(callback)).Return(nil, nil)
+				m.EXPECT().Send(test.MessageTextContains("Готово! Теперь я говорю по-русски."))
+			},
+			storage: func(m *smocks.MockTelegramUserStorage) {
+				m.EXPECT().FetchByID(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID).Return(test.User, nil)
+				m.EXPECT().SetLanguage(gomock.AssignableToTypeOf(test.CtxInterface), test.TgUserID, "ru").Return(nil)
+			},
+			controller: func(*mmocks.MockController) {},
 		})
 		defer cancel()
 
@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const _callbackUndo = "undo"
+
+var _undoDoneMessages = map[types.OperationKind]string{
+	types.OperationAddExpense:  undoExpenseMessage,
+	types.OperationSetLimit:    undoLimitMessage,
+	types.OperationSetCurrency: undoCurrencyMessage,
+}
+
+func (c *client) handleUndo(ctx context.Context, user *types.User, _ string) string {
+	return c.undo(ctx, user, 0)
+}
+
+// handleUndoCallback undoes the operation the button is attached to, provided that it's still the last one.
+func (c *client) handleUndoCallback(ctx context.Context, user *types.User, args string) (string, bool) {
+	operation, err := strconv.ParseInt(args, 10, 64)
+	if err != nil || operation == 0 {
+		return tr(ctx, undoNothingMessage), true
+	}
+
+	return c.undo(ctx, user, operation), true
+}
+
+// undo reverts the operation of the user, the last one when the operation is 0.
+func (c *client) undo(ctx context.Context, user *types.User, operation int64) string {
+	resp := c.controller.Undo(ctx, request.Undo{
+		User:      user,
+		Operation: operation,
+	})
+
+	switch {
+	case !resp.Ready:
+		return tr(ctx, currencyLaterMessage)
+
+	case resp.NotFound:
+		return tr(ctx, undoNothingMessage)
+
+	case resp.Forbidden:
+		return errorMessage(ctx, errGroupOwnerOnly, "Не удалось отменить действие.", groupHelpMessage)
+
+	case !resp.Success:
+		return tr(ctx, emergencyMessage)
+	}
+
+	if text, ok := _undoDoneMessages[resp.Kind]; ok {
+		return tr(ctx, text)
+	}
+
+	return tr(ctx, doneMessage)
+}
+
+// undoKeyboard offers to undo the journal operation reported by the text, there's no button unless the operation
+// is done and journaled.
+func undoKeyboard(ctx context.Context, text string, operation int64) [][][]string {
+	if operation == 0 || !strings.HasPrefix(text, tr(ctx, doneMessage)) {
+		return nil
+	}
+
+	return [][][]string{{{"↩️ " + tr(ctx, "Отменить"), _callbackUndo + _callbackSeparator + strconv.FormatInt(operation, 10)}}}
+}
+
+// undoableCallback adapts the handler the same way as textCallback, the reply offers to undo the operation done.
+func undoableCallback(handler func(context.Context, *types.User, string) (string, int64, bool)) callbackHandler {
+	return func(ctx context.Context, user *types.User, args string) reply {
+		text, operation, ok := handler(ctx, user, args)
+
+		var keyboard [][][]string
+		if ok {
+			keyboard = undoKeyboard(ctx, text, operation)
+		}
+
+		return reply{text: text, keyboard: keyboard, answered: ok, notice: args}
+	}
+}
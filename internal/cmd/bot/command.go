@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -19,7 +20,10 @@ import (
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model/expense/reports"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage/inmemory"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage/localfs"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage/postgresql"
+	redisstorage "gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage/redis"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -27,22 +31,48 @@ import (
 )
 
 const (
-	_defaultMetricsPort = 8084
+	_defaultMetricsPort     = 8084
+	_defaultAttachmentsPath = "attachments"
 )
 
 type (
 	storageFactory interface {
 		CreateTelegramUserStorage() storage.TelegramUserStorage
 		CreateExpenseStorage() storage.ExpenseStorage
+		CreateCategoryStorage() storage.CategoryStorage
+		CreateAttachmentStorage() storage.AttachmentStorage
+		CreateIncomeStorage() storage.IncomeStorage
+		CreateAccountStorage() storage.AccountStorage
 		CreateExpenseLimitStorage() storage.ExpenseLimitStorage
+		CreateGoalStorage() storage.GoalStorage
+		CreateGroupStorage() storage.GroupStorage
+		CreateDebtStorage() storage.DebtStorage
 		CreateCurrencyStorage() storage.CurrencyStorage
 		CreateCurrencyRatesStorage() storage.CurrencyRatesStorage
+		CreateMerchantRuleStorage() storage.MerchantRuleStorage
+		CreateRecurringExpenseStorage() storage.RecurringExpenseStorage
+		CreateOutboxStorage() storage.OutboxStorage
+		CreateJournalStorage() storage.JournalStorage
+		CreateUnitOfWork() storage.UnitOfWork
 	}
 
 	client interface {
 		RegisterController(model.Controller)
 		ListenUpdates(ctx context.Context) error
 	}
+
+	limiter interface {
+		Get(ctx context.Context, user *types.User, category string) (types.LimitItem, error)
+		Set(ctx context.Context, user *types.User, limit int64, currency string, period types.LimitPeriod, category string) error
+		Decrease(ctx context.Context, user *types.User, value int64, category string) (bool, error)
+		Increase(ctx context.Context, user *types.User, value int64, category string) error
+		Unset(ctx context.Context, user *types.User, category string) error
+		Restore(ctx context.Context, user *types.User, item types.LimitItem, category string) error
+		List(ctx context.Context, user *types.User) (map[string]types.LimitItem, error)
+		Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error)
+		GetAlerts(ctx context.Context, user *types.User) ([]int, error)
+		SetAlerts(ctx context.Context, user *types.User, thresholds []int) error
+	}
 )
 
 func NewCommand(name, version string) *cobra.Command {
@@ -130,24 +160,64 @@ func NewCommand(name, version string) *cobra.Command {
 			}
 			defer reportsProducer.Close()
 
+			unitOfWork := factory.CreateUnitOfWork()
+
 			var (
-				expenser model.Expenser = expense.NewExpenser(factory.CreateExpenseStorage())
-				reporter model.Reporter = expense.NewReporter(cfg.Reports.Kafka.Timeout, reportsProducer, reportsListener, logger)
+				grouper     model.Grouper     = expense.NewGrouper(factory.CreateGroupStorage())
+				expenser    model.Expenser    = expense.NewExpenser(factory.CreateExpenseStorage())
+				categorizer model.Categorizer = expense.NewCategorizer(factory.CreateCategoryStorage())
+				incomer     model.Incomer     = expense.NewIncomer(factory.CreateIncomeStorage())
+				reporter    model.Reporter    = expense.NewReporter(cfg.Reports.Kafka.Timeout, reportsProducer, reportsListener, logger)
 			)
 			if cfg.Cache.Reporter.Driver != "" {
-				if expenser, reporter, err = newReportCache(expenser, reporter, cfg.Cache.Reporter, logger); err != nil {
+				if expenser, categorizer, incomer, reporter, grouper, err = newReportCache(expenser, categorizer, incomer, reporter, grouper, unitOfWork, cfg.Cache.Reporter, logger); err != nil {
 					logger.Error("reports cache init failed", zap.Error(err))
 				}
 			}
 
-			tgClient, err := newTelegramClient(cfg.Client.Telegram.Token, factory.CreateTelegramUserStorage(), logger)
+			blobs, err := newBlobStorage(cfg.Attachments)
+			if err != nil {
+				return errors.Wrap(err, "blob storage init failed")
+			}
+
+			attacher := expense.NewAttacher(cfg.Attachments, factory.CreateAttachmentStorage(), blobs, logger)
+			g.Go(func() error {
+				return attacher.Run(ctx)
+			})
+
+			dialogs, err := newDialogStorage(cfg.Client.Telegram.Dialogs)
+			if err != nil {
+				return errors.Wrap(err, "dialog storage init failed")
+			}
+
+			tgClient, err := newTelegramClient(cfg.Client.Telegram.Token, cfg.Import.MaxFileSize, cfg.Attachments.MaxFileSize, cfg.Client.Telegram.Dialogs.TTL, factory.CreateTelegramUserStorage(), dialogs, logger)
 			if err != nil {
 				return errors.Wrap(err, "telegram client init failed")
 			}
 
-			limiter := expense.NewLimiter(factory.CreateExpenseLimitStorage())
+			limiter, err := newLimiter(cfg.Limits, factory, rater)
+			if err != nil {
+				return errors.Wrap(err, "limiter init failed")
+			}
+
 			currencyManager := currency.NewCurrencyManager(cfg.Currency, factory.CreateCurrencyStorage())
-			finAssist := model.NewController(expenser, reporter, limiter, currencyManager, rater, logger)
+			exporter := expense.NewExporter(factory.CreateExpenseStorage(), rater)
+			accounter := expense.NewAccounter(factory.CreateAccountStorage(), rater)
+			saver := expense.NewSaver(factory.CreateGoalStorage(), rater)
+			debtor := expense.NewDebtor(factory.CreateDebtStorage())
+			importer := expense.NewImporter(cfg.Import, factory.CreateMerchantRuleStorage())
+			// the recurrer adds expenses through the controller within its own transactions
+			recurrer := expense.NewRecurrer(cfg.Recurring, factory.CreateRecurringExpenseStorage(), unitOfWork, logger)
+			journaler := expense.NewJournaler(cfg.Undo, factory.CreateJournalStorage())
+			finAssist := model.NewController(expenser, categorizer, attacher, incomer, accounter, saver, grouper, debtor, reporter, exporter, importer, recurrer, journaler, limiter, currencyManager, rater, unitOfWork, factory.CreateOutboxStorage(), logger)
+			g.Go(func() error {
+				return recurrer.Run(ctx, finAssist)
+			})
+
+			relay := expense.NewRelay(cfg.Outbox, factory.CreateOutboxStorage(), unitOfWork, reportsProducer, logger)
+			g.Go(func() error {
+				return relay.Run(ctx)
+			})
 
 			tgClient.RegisterController(finAssist)
 			g.Go(func() error {
@@ -170,6 +240,8 @@ func NewCommand(name, version string) *cobra.Command {
 
 	_ = c.MarkFlagRequired("config")
 
+	c.AddCommand(newExportCommand(&configPath))
+
 	return c
 }
 
@@ -185,6 +257,44 @@ func newStorageFactory(ctx context.Context, cfg config.StorageConfig, logger *za
 	return nil, errors.New("unknown storage driver")
 }
 
+func newBlobStorage(cfg config.AttachmentsConfig) (storage.BlobStorage, error) {
+	switch cfg.Driver {
+	case "", config.LocalBlobDriver:
+		path := cfg.Path
+		if path == "" {
+			path = _defaultAttachmentsPath
+		}
+
+		return localfs.NewBlobStorage(path)
+	}
+
+	return nil, errors.New("unknown blob storage driver")
+}
+
+func newDialogStorage(cfg config.DialogsConfig) (storage.DialogStorage, error) {
+	switch cfg.Driver {
+	case "", config.InMemoryDialogDriver:
+		return inmemory.NewDialogStorage(), nil
+
+	case config.RedisDialogDriver:
+		return redisstorage.NewDialogStorage(cfg.Dsn)
+	}
+
+	return nil, errors.New("unknown dialog storage driver")
+}
+
+func newLimiter(cfg config.LimitsConfig, factory storageFactory, rater model.Rater) (limiter, error) {
+	switch cfg.Mode {
+	case "", config.CounterLimitsMode:
+		return expense.NewLimiter(factory.CreateExpenseLimitStorage()), nil
+
+	case config.LedgerLimitsMode:
+		return expense.NewLedgerLimiter(factory.CreateExpenseLimitStorage(), factory.CreateExpenseStorage(), factory.CreateGroupStorage(), rater), nil
+	}
+
+	return nil, errors.New("unknown limits mode")
+}
+
 func newCurrencyRatesCache(storage storage.CurrencyRatesStorage, cfg config.CacheSectionConfig, logger *zap.Logger) (storage.CurrencyRatesStorage, error) {
 	switch cfg.Driver {
 	case config.RedisDriver:
@@ -194,16 +304,19 @@ func newCurrencyRatesCache(storage storage.CurrencyRatesStorage, cfg config.Cach
 	return storage, errors.New("unknown rates cache driver")
 }
 
-func newReportCache(expenser model.Expenser, reporter model.Reporter, cfg config.CacheSectionConfig, logger *zap.Logger) (model.Expenser, model.Reporter, error) {
+func newReportCache(expenser model.Expenser, categorizer model.Categorizer, incomer model.Incomer, reporter model.Reporter, grouper model.Grouper, uow storage.UnitOfWork, cfg config.CacheSectionConfig, logger *zap.Logger) (model.Expenser, model.Categorizer, model.Incomer, model.Reporter, model.Grouper, error) {
 	switch cfg.Driver {
 	case config.RedisDriver:
-		cache, err := redis.NewReportCache(expenser, reporter, cfg.Dsn, logger)
-		return cache, cache, err
+		cache, err := redis.NewReportCache(expenser, categorizer, incomer, reporter, grouper, uow, cfg.Dsn, logger)
+		if err != nil {
+			return expenser, categorizer, incomer, reporter, grouper, err
+		}
+		return cache, cache, cache, cache, cache, nil
 	}
 
-	return expenser, reporter, errors.New("unknown report cache driver")
+	return expenser, categorizer, incomer, reporter, grouper, errors.New("unknown report cache driver")
 }
 
-func newTelegramClient(token string, s storage.TelegramUserStorage, l *zap.Logger) (client, error) {
-	return tgclient.NewClient(token, s, l)
+func newTelegramClient(token string, maxImportSize, maxAttachmentSize int, dialogTTL time.Duration, s storage.TelegramUserStorage, d storage.DialogStorage, l *zap.Logger) (client, error) {
+	return tgclient.NewClient(token, maxImportSize, maxAttachmentSize, dialogTTL, s, d, l)
 }
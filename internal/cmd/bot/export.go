@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/ctxkey"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model/currency"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model/currency/cbr"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model/expense"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+func newExportCommand(configPath *string) *cobra.Command {
+	var (
+		userID       int64
+		from, to     string
+		format       string
+		currencyCode string
+		output       string
+	)
+
+	c := &cobra.Command{
+		Use:     "export",
+		Short:   "Export expenses of the user",
+		Example: "export --config=.data/config.yaml --user=1 --from=2022-10-01 --to=2022-10-31 --format=xlsx --output=expenses.xlsx",
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			logger := ctx.Value(ctxkey.Logger).(*zap.Logger)
+
+			fromDate, err := time.Parse("2006-01-02", from)
+			if err != nil {
+				return errors.Wrap(err, "invalid from date")
+			}
+
+			// the upper bound is inclusive for operators, but exclusive for the exporter
+			var toDate time.Time
+			if to != "" {
+				if toDate, err = time.Parse("2006-01-02", to); err != nil {
+					return errors.Wrap(err, "invalid to date")
+				}
+				toDate = toDate.AddDate(0, 0, 1)
+			}
+
+			cfg, err := config.NewConfig(*configPath)
+			if err != nil {
+				return errors.Wrap(err, "config init failed")
+			}
+
+			factory, err := newStorageFactory(ctx, cfg.Storage, logger)
+			if err != nil {
+				return errors.Wrap(err, "storage factory init failed")
+			}
+
+			user := types.User(userID)
+			if currencyCode == "" {
+				currencyManager := currency.NewCurrencyManager(cfg.Currency, factory.CreateCurrencyStorage())
+				if currencyCode, err = currencyManager.Get(ctx, &user); err != nil {
+					return errors.Wrap(err, "cannot get user currency")
+				}
+			}
+
+			// rates are not refreshed, the stored ones are enough to convert the past expenses
+			rater := currency.NewRater(cfg.Currency, factory.CreateCurrencyRatesStorage(), cbr.NewCbrGateway(http.DefaultClient), logger)
+			exporter := expense.NewExporter(factory.CreateExpenseStorage(), rater)
+
+			w := cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return errors.Wrap(err, "cannot create output file")
+				}
+				defer func(c io.Closer, l *zap.Logger) {
+					if err := c.Close(); err != nil {
+						l.Error("cannot close output file", zap.Error(err))
+					}
+				}(f, logger)
+
+				w = f
+			}
+
+			count, err := exporter.Export(ctx, &user, fromDate, toDate, types.ExportFormat(format), currencyCode, w)
+			if err != nil {
+				return errors.Wrap(err, "export failed")
+			}
+
+			logger.Info("expenses exported", zap.Int64("user", userID), zap.Int("count", count), zap.String("currency", currencyCode))
+
+			return nil
+		},
+	}
+
+	c.Flags().Int64Var(&userID, "user", 0, "internal user ID")
+	c.Flags().StringVar(&from, "from", "", "first date of the expenses, YYYY-MM-DD")
+	c.Flags().StringVar(&to, "to", "", "last date of the expenses, YYYY-MM-DD (all the expenses since the first date by default)")
+	c.Flags().StringVar(&format, "format", string(types.ExportCSV), "csv | xlsx")
+	c.Flags().StringVar(&currencyCode, "currency", "", "currency of the converted amounts (the user's current currency by default)")
+	c.Flags().StringVarP(&output, "output", "o", "", "output file path (stdout by default)")
+
+	_ = c.MarkFlagRequired("user")
+	_ = c.MarkFlagRequired("from")
+
+	return c
+}
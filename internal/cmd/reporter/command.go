@@ -28,6 +28,8 @@ const (
 type (
 	storageFactory interface {
 		CreateExpenseStorage() storage.ExpenseStorage
+		CreateIncomeStorage() storage.IncomeStorage
+		CreateGroupStorage() storage.GroupStorage
 		CreateCurrencyRatesStorage() storage.CurrencyRatesStorage
 	}
 )
@@ -98,7 +100,7 @@ func NewCommand(name, version string) *cobra.Command {
 
 			expenseStorage := factory.CreateExpenseStorage()
 			rater := currency.NewRater(cfg.Currency, ratesStorage, nil, logger)
-			consumer, err := reports.NewConsumer(cfg.Reports.Kafka, cfg.Reports.Grpc, expenseStorage, rater, logger)
+			consumer, err := reports.NewConsumer(cfg.Reports.Kafka, cfg.Reports.Grpc, expenseStorage, factory.CreateIncomeStorage(), factory.CreateGroupStorage(), rater, logger)
 			if err != nil {
 				return errors.Wrap(err, "reports consumer init failed")
 			}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"time"
+)
+
+type blobDriver string
+
+const (
+	LocalBlobDriver blobDriver = "local"
+)
+
+type AttachmentsConfig struct {
+	// Driver is the blob storage of attached files, the local one is used by default.
+	Driver blobDriver `yaml:"driver"`
+	// Path is the directory of the local blob storage ("attachments" by default).
+	Path string `yaml:"path"`
+	// MaxFileSize limits the size of attached files in bytes (5 MiB by default).
+	MaxFileSize int `yaml:"max_file_size"`
+	// Retention is how long attached files are kept, they are kept forever when zero.
+	Retention time.Duration `yaml:"retention"`
+	// CleanupInterval is the period of removing expired attachments (1 hour by default).
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+}
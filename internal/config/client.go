@@ -6,6 +6,7 @@ type (
 	}
 
 	telegram struct {
-		Token string `yaml:"token"`
+		Token   string        `yaml:"token"`
+		Dialogs DialogsConfig `yaml:"dialogs"`
 	}
 )
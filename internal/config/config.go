@@ -8,11 +8,17 @@ import (
 )
 
 type config struct {
-	Client   clientConfig   `yaml:"client"`
-	Storage  StorageConfig  `yaml:"storage"`
-	Cache    cacheConfig    `yaml:"cache"`
-	Currency CurrencyConfig `yaml:"currency"`
-	Reports  ReportsConfig  `yaml:"reports"`
+	Client      clientConfig      `yaml:"client"`
+	Storage     StorageConfig     `yaml:"storage"`
+	Cache       cacheConfig       `yaml:"cache"`
+	Currency    CurrencyConfig    `yaml:"currency"`
+	Reports     ReportsConfig     `yaml:"reports"`
+	Limits      LimitsConfig      `yaml:"limits"`
+	Import      ImportConfig      `yaml:"import"`
+	Recurring   RecurringConfig   `yaml:"recurring"`
+	Attachments AttachmentsConfig `yaml:"attachments"`
+	Undo        UndoConfig        `yaml:"undo"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
 }
 
 func NewConfig(configPath string) (*config, error) {
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"time"
+)
+
+type dialogDriver string
+
+const (
+	InMemoryDialogDriver dialogDriver = "in_memory"
+	RedisDialogDriver    dialogDriver = "redis"
+)
+
+type DialogsConfig struct {
+	// Driver is in memory by default, unfinished dialogs are lost on restart then.
+	Driver dialogDriver `yaml:"driver"`
+	Dsn    string       `yaml:"dsn"`
+	// TTL is the time the state of a dialog is kept since the last answer, a day by default.
+	TTL time.Duration `yaml:"ttl"`
+}
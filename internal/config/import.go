@@ -0,0 +1,28 @@
+package config
+
+type (
+	ImportConfig struct {
+		// DefaultCategory is assigned to imported expenses matching no merchant rule.
+		DefaultCategory string `yaml:"default_category"`
+		// MaxFileSize limits the size of uploaded statements in bytes.
+		MaxFileSize int          `yaml:"max_file_size"`
+		Banks       []BankConfig `yaml:"banks"`
+	}
+
+	// BankConfig describes the layout of CSV statements of a bank, columns are referred to by their header titles.
+	BankConfig struct {
+		Name      string `yaml:"name"`
+		Delimiter string `yaml:"delimiter"`
+		// Encoding is either empty (UTF-8) or "windows-1251".
+		Encoding          string `yaml:"encoding"`
+		DateColumn        string `yaml:"date_column"`
+		DateFormat        string `yaml:"date_format"`
+		AmountColumn      string `yaml:"amount_column"`
+		CurrencyColumn    string `yaml:"currency_column"`
+		Currency          string `yaml:"currency"` // used when there is no currency column
+		DescriptionColumn string `yaml:"description_column"`
+		IDColumn          string `yaml:"id_column"`
+		// ExpensesNegative tells that expenses have negative amounts and incomes have positive ones.
+		ExpensesNegative bool `yaml:"expenses_negative"`
+	}
+)
@@ -0,0 +1,14 @@
+package config
+
+type limitsMode string
+
+const (
+	CounterLimitsMode limitsMode = "counter"
+	LedgerLimitsMode  limitsMode = "ledger"
+)
+
+type LimitsConfig struct {
+	// Mode defines how limit remains are tracked: "counter" (default) decreases
+	// the stored remains on every expense, "ledger" computes them from expenses.
+	Mode limitsMode `yaml:"mode"`
+}
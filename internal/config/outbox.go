@@ -0,0 +1,12 @@
+package config
+
+import (
+	"time"
+)
+
+type OutboxConfig struct {
+	// Interval is the period of publishing the outbox events to Kafka (5 seconds by default).
+	Interval time.Duration `yaml:"interval"`
+	// BatchSize is the number of events published within a transaction (100 by default).
+	BatchSize int `yaml:"batch_size"`
+}
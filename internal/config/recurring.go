@@ -0,0 +1,10 @@
+package config
+
+import (
+	"time"
+)
+
+type RecurringConfig struct {
+	// Interval is the period of checks for due occurrences of recurring expenses (1 minute by default).
+	Interval time.Duration `yaml:"interval"`
+}
@@ -21,5 +21,7 @@ type (
 		Timeout       time.Duration `yaml:"timeout"`
 		Topic         string        `yaml:"topic"`
 		ConsumerGroup string        `yaml:"cg"`
+		// EventsTopic receives the domain events relayed from the outbox ("events" by default).
+		EventsTopic string `yaml:"events_topic"`
 	}
 )
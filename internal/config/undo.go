@@ -0,0 +1,10 @@
+package config
+
+import (
+	"time"
+)
+
+type UndoConfig struct {
+	// Window is the time an operation can be undone within since it's made (10 minutes by default).
+	Window time.Duration `yaml:"window"`
+}
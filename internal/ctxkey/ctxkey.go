@@ -5,7 +5,10 @@ import (
 )
 
 var (
-	Logger = New("Logger")
+	Logger      = New("Logger")
+	Transaction = New("Transaction")
+	CommitHooks = New("CommitHooks")
+	Language    = New("Language")
 )
 
 type Key interface {
@@ -2,9 +2,14 @@ package message
 
 import (
 	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 )
 
 type Report struct {
 	From     time.Time
+	To       time.Time
+	Grouping types.ReportGrouping
+	Compare  types.DateRange
 	Currency string
 }
@@ -0,0 +1,65 @@
+package request
+
+import (
+	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type AddAccount struct {
+	User     *types.User
+	Name     string
+	Currency string // the user's current currency is used when empty
+	Opening  int64
+}
+
+func (r AddAccount) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+	enc.AddString("currency", r.Currency)
+	enc.AddInt64("opening", r.Opening)
+
+	return nil
+}
+
+type ListAccounts struct {
+	User *types.User
+}
+
+func (r ListAccounts) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type GetBalance struct {
+	User *types.User
+}
+
+func (r GetBalance) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type Transfer struct {
+	User   *types.User
+	Date   time.Time
+	From   string
+	To     string
+	Amount int64
+	// Received is the amount credited to the target account, it's exchanged at the rate of the date when zero.
+	Received int64
+}
+
+func (r Transfer) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddTime("date", r.Date)
+	enc.AddString("from", r.From)
+	enc.AddString("to", r.To)
+	enc.AddInt64("amount", r.Amount)
+	enc.AddInt64("received", r.Received)
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package request
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type AttachToExpense struct {
+	User      *types.User
+	ExpenseID int64
+	Data      []byte
+}
+
+func (r AttachToExpense) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddInt64("expense_id", r.ExpenseID)
+	enc.AddInt("size", len(r.Data))
+
+	return nil
+}
+
+type GetAttachment struct {
+	User      *types.User
+	ExpenseID int64
+}
+
+func (r GetAttachment) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddInt64("expense_id", r.ExpenseID)
+
+	return nil
+}
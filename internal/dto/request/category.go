@@ -0,0 +1,47 @@
+package request
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type ListCategories struct {
+	User *types.User
+}
+
+func (r ListCategories) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type SetCategoryAlias struct {
+	User     *types.User
+	Alias    string
+	Category string // the alias is deleted when empty
+}
+
+func (r SetCategoryAlias) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("alias", r.Alias)
+	enc.AddString("category", r.Category)
+
+	return nil
+}
+
+type RenameCategory struct {
+	User *types.User
+	From string
+	To   string
+	// Merge allows renaming into a category in use and keeps the old name as an alias of the new one.
+	Merge bool
+}
+
+func (r RenameCategory) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("from", r.From)
+	enc.AddString("to", r.To)
+	enc.AddBool("merge", r.Merge)
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package request
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type ListDebts struct {
+	User *types.User
+}
+
+func (r ListDebts) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type SettleDebts struct {
+	User     *types.User
+	Name     string // the name of the counterparty
+	Currency string // debts in all currencies are settled when empty
+}
+
+func (r SettleDebts) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+	enc.AddString("currency", r.Currency)
+
+	return nil
+}
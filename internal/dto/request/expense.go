@@ -1,6 +1,7 @@
 package request
 
 import (
+	"io"
 	"time"
 
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
@@ -11,14 +12,130 @@ type AddExpense struct {
 	User     *types.User
 	Date     time.Time
 	Amount   int64
+	Currency string // the user's current currency is used when empty
 	Category string
+	Account  string // name of the account the expense is paid from, optional
+	Note     string
+	// Tags annotate the expense, the first tag naming an account selects it when Account is empty.
+	Tags []string
+	// Receipt identifies the fiscal receipt the expense is made from, an expense is added once per receipt.
+	Receipt *types.Receipt
+	// Split lists names of the group members sharing the expense equally with the user,
+	// only the user's share is added as the expense and the others' ones become their debts.
+	// Only members of the user's group can be named, other users are unknown by their names.
+	Split []string
+	// Scheduled expenses are added on behalf of the user by recurring expenses, they're not undone by the user.
+	Scheduled bool
 }
 
 func (r AddExpense) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddInt64("user", int64(*r.User))
 	enc.AddTime("date", r.Date)
 	enc.AddInt64("amount", r.Amount)
+	enc.AddString("currency", r.Currency)
 	enc.AddString("category", r.Category)
+	enc.AddString("account", r.Account)
+	enc.AddString("note", r.Note)
+	if len(r.Tags) > 0 {
+		if err := enc.AddArray("tags", stringArray(r.Tags)); err != nil {
+			return err
+		}
+	}
+	if r.Receipt != nil {
+		enc.AddString("receipt", r.Receipt.Key())
+	}
+	if r.Scheduled {
+		enc.AddBool("scheduled", r.Scheduled)
+	}
+	if len(r.Split) > 0 {
+		return enc.AddArray("split", stringArray(r.Split))
+	}
+
+	return nil
+}
+
+func stringArray(list []string) zapcore.ArrayMarshaler {
+	return zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, item := range list {
+			enc.AppendString(item)
+		}
+
+		return nil
+	})
+}
+
+type ListExpenses struct {
+	User *types.User
+}
+
+func (r ListExpenses) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type FindExpenses struct {
+	User   *types.User
+	Filter types.ExpenseFilter
+	Page   int // zero-based
+}
+
+func (r FindExpenses) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("query", r.Filter.Query)
+	if !r.Filter.From.IsZero() {
+		enc.AddTime("from", r.Filter.From)
+	}
+	if !r.Filter.To.IsZero() {
+		enc.AddTime("to", r.Filter.To)
+	}
+	enc.AddInt64("min", r.Filter.Min)
+	enc.AddInt64("max", r.Filter.Max)
+	enc.AddInt("page", r.Page)
+
+	return nil
+}
+
+type GetExpense struct {
+	User *types.User
+	ID   int64
+}
+
+func (r GetExpense) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddInt64("id", r.ID)
+
+	return nil
+}
+
+type UpdateExpense struct {
+	User     *types.User
+	ID       int64
+	Date     time.Time
+	Amount   int64
+	Currency string // the expense currency is kept when empty
+	Category string
+}
+
+func (r UpdateExpense) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddInt64("id", r.ID)
+	enc.AddTime("date", r.Date)
+	enc.AddInt64("amount", r.Amount)
+	enc.AddString("currency", r.Currency)
+	enc.AddString("category", r.Category)
+
+	return nil
+}
+
+type DeleteExpense struct {
+	User *types.User
+	ID   int64
+}
+
+func (r DeleteExpense) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddInt64("id", r.ID)
 
 	return nil
 }
@@ -26,11 +143,43 @@ func (r AddExpense) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 type GetReport struct {
 	User *types.User
 	From time.Time
+	// To is the exclusive upper bound of the report range.
+	To       time.Time
+	Grouping types.ReportGrouping
+	// Compare is the range the report is compared with, the report is not compared when it is zero.
+	Compare types.DateRange
+	// RollUp sums up the subcategories under their top-level categories.
+	RollUp bool
 }
 
 func (r GetReport) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddInt64("user", int64(*r.User))
 	enc.AddTime("from", r.From)
+	enc.AddTime("to", r.To)
+	enc.AddString("grouping", string(r.Grouping))
+	if !r.Compare.IsZero() {
+		enc.AddTime("compare_from", r.Compare.From)
+		enc.AddTime("compare_to", r.Compare.To)
+	}
+	enc.AddBool("roll_up", r.RollUp)
+
+	return nil
+}
+
+type ExportExpenses struct {
+	User *types.User
+	From time.Time
+	// To is the exclusive upper bound of the export range, zero means no upper bound.
+	To     time.Time
+	Format types.ExportFormat
+	Writer io.Writer
+}
+
+func (r ExportExpenses) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddTime("from", r.From)
+	enc.AddTime("to", r.To)
+	enc.AddString("format", string(r.Format))
 
 	return nil
 }
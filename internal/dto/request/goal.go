@@ -0,0 +1,66 @@
+package request
+
+import (
+	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type AddGoal struct {
+	User     *types.User
+	Name     string
+	Target   int64
+	Currency string    // the user's current currency is used when empty
+	Deadline time.Time // optional
+}
+
+func (r AddGoal) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+	enc.AddInt64("target", r.Target)
+	enc.AddString("currency", r.Currency)
+	enc.AddTime("deadline", r.Deadline)
+
+	return nil
+}
+
+type Contribute struct {
+	User     *types.User
+	Name     string
+	Date     time.Time
+	Amount   int64
+	Currency string // the goal currency is used when empty
+}
+
+func (r Contribute) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+	enc.AddTime("date", r.Date)
+	enc.AddInt64("amount", r.Amount)
+	enc.AddString("currency", r.Currency)
+
+	return nil
+}
+
+type DeleteGoal struct {
+	User *types.User
+	Name string
+}
+
+func (r DeleteGoal) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+
+	return nil
+}
+
+type ListGoals struct {
+	User *types.User
+}
+
+func (r ListGoals) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
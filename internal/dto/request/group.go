@@ -0,0 +1,64 @@
+package request
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type CreateGroup struct {
+	User *types.User
+	Name string // the name of the user shown to the other members
+}
+
+func (r CreateGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+
+	return nil
+}
+
+type JoinGroup struct {
+	User *types.User
+	Code string
+	Name string // the name of the user shown to the other members
+}
+
+func (r JoinGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("code", r.Code)
+	enc.AddString("name", r.Name)
+
+	return nil
+}
+
+type LeaveGroup struct {
+	User *types.User
+}
+
+func (r LeaveGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type RemoveGroupMember struct {
+	User *types.User
+	Name string
+}
+
+func (r RemoveGroupMember) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+
+	return nil
+}
+
+type GetGroup struct {
+	User *types.User
+}
+
+func (r GetGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
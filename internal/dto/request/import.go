@@ -0,0 +1,46 @@
+package request
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type ImportExpenses struct {
+	User *types.User
+	Name string // file name of the statement
+	Bank string // the layout of CSV statements is detected by the header when empty
+	Data []byte
+}
+
+func (r ImportExpenses) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("name", r.Name)
+	enc.AddString("bank", r.Bank)
+	enc.AddInt("size", len(r.Data))
+
+	return nil
+}
+
+type ListMerchantRules struct {
+	User *types.User
+}
+
+func (r ListMerchantRules) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type SetMerchantRule struct {
+	User     *types.User
+	Pattern  string
+	Category string // the rule is deleted when empty
+}
+
+func (r SetMerchantRule) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("pattern", r.Pattern)
+	enc.AddString("category", r.Category)
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package request
+
+import (
+	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type AddIncome struct {
+	User     *types.User
+	Date     time.Time
+	Amount   int64
+	Currency string // the user's current currency is used when empty
+	Category string
+	Account  string // name of the account the income is put into, optional
+}
+
+func (r AddIncome) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddTime("date", r.Date)
+	enc.AddInt64("amount", r.Amount)
+	enc.AddString("currency", r.Currency)
+	enc.AddString("category", r.Category)
+	enc.AddString("account", r.Account)
+
+	return nil
+}
+
+type ListIncomes struct {
+	User *types.User
+}
+
+func (r ListIncomes) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
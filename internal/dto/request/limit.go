@@ -8,12 +8,16 @@ import (
 type SetLimit struct {
 	User     *types.User
 	Value    int64
+	Period   types.LimitPeriod
 	Category string
 }
 
 func (r SetLimit) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddInt64("user", int64(*r.User))
 	enc.AddInt64("value", r.Value)
+	enc.AddString("period", string(r.Period.Kind))
+	enc.AddInt("period_days", r.Period.Days)
+	enc.AddBool("carry_over", r.Period.CarryOver)
 	enc.AddString("category", r.Category)
 
 	return nil
@@ -28,3 +32,19 @@ func (r ListLimits) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 
 	return nil
 }
+
+type SetLimitAlerts struct {
+	User       *types.User
+	Thresholds []int
+}
+
+func (r SetLimitAlerts) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	return enc.AddArray("thresholds", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, threshold := range r.Thresholds {
+			enc.AppendInt(threshold)
+		}
+
+		return nil
+	}))
+}
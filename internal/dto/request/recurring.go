@@ -0,0 +1,50 @@
+package request
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type AddRecurring struct {
+	User       *types.User
+	Recurrence types.Recurrence
+	Amount     int64
+	Currency   string // the user's current currency is fixed when empty
+	Category   string
+}
+
+func (r AddRecurring) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddString("frequency", string(r.Recurrence.Frequency))
+	enc.AddInt("interval", r.Recurrence.Interval)
+	enc.AddTime("start", r.Recurrence.Start)
+	enc.AddInt64("amount", r.Amount)
+	enc.AddString("currency", r.Currency)
+	enc.AddString("category", r.Category)
+
+	return nil
+}
+
+type ListRecurring struct {
+	User *types.User
+}
+
+func (r ListRecurring) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+
+	return nil
+}
+
+type SetRecurringPaused struct {
+	User   *types.User
+	ID     int64
+	Paused bool
+}
+
+func (r SetRecurringPaused) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddInt64("id", r.ID)
+	enc.AddBool("paused", r.Paused)
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package request
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type Undo struct {
+	User      *types.User
+	Operation int64 // the journal entry to revert, 0 for the last one
+}
+
+func (r Undo) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("user", int64(*r.User))
+	enc.AddInt64("operation", r.Operation)
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package response
+
+import "gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+
+type AddAccount struct {
+	UnknownCurrency bool
+	Duplicate       bool
+	Invalid         error // the input rejected by the model, see model.InputError
+	Success         bool
+}
+
+type ListAccounts struct {
+	List    []types.Account
+	Success bool
+}
+
+type GetBalance struct {
+	Currency string
+	Ready    bool
+	List     []types.AccountBalance
+	Success  bool
+}
+
+type Transfer struct {
+	Ready    bool
+	NotFound bool // one of the accounts doesn't exist
+	From     types.Account
+	To       types.Account
+	Received int64
+	Invalid  error // the input rejected by the model, see model.InputError
+	Success  bool
+}
@@ -0,0 +1,13 @@
+package response
+
+type AttachToExpense struct {
+	NotFound bool
+	TooLarge bool
+	Success  bool
+}
+
+type GetAttachment struct {
+	Data     []byte
+	NotFound bool
+	Success  bool
+}
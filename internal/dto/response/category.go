@@ -0,0 +1,21 @@
+package response
+
+import "gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+
+type ListCategories struct {
+	List    []types.Category
+	Success bool
+}
+
+type SetCategoryAlias struct {
+	Invalid  bool
+	NotFound bool
+	Success  bool
+}
+
+type RenameCategory struct {
+	Invalid       bool
+	NotFound      bool
+	AlreadyExists bool
+	Success       bool
+}
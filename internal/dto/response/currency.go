@@ -1,6 +1,9 @@
 package response
 
-type SetCurrency bool
+type SetCurrency struct {
+	Operation int64 // the journal entry to undo the change
+	Success   bool
+}
 
 type ListCurrencies struct {
 	Current string
@@ -0,0 +1,14 @@
+package response
+
+import "gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+
+type ListDebts struct {
+	List    []types.DebtBalance
+	Success bool
+}
+
+type SettleDebts struct {
+	NotFound bool
+	Settled  []types.DebtBalance
+	Success  bool
+}
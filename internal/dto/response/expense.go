@@ -3,19 +3,80 @@ package response
 import (
 	"encoding/json"
 	"time"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 )
 
 type AddExpense struct {
-	Ready        bool
-	LimitReached bool
-	Success      bool
+	ID              int64 // the added expense
+	Ready           bool
+	UnknownCurrency bool
+	UnknownAccount  bool
+	Duplicate       bool // the expense of the receipt has been added before
+	UnknownMember   bool // some of the names to split the expense with are not members of the user's group
+	LimitAlert      int  // usage threshold (percent) of the limit crossed by the expense, 0 if none
+	Debts           []types.Debt
+	Invalid         error // the input rejected by the model, see model.InputError
+	Operation       int64 // the journal entry to undo the expense, 0 for scheduled ones
+	Success         bool
+}
+
+type ListExpenses struct {
+	List     []types.Expense
+	Attached map[int64]bool // IDs of the listed expenses having attachments
+	Success  bool
+}
+
+type FindExpenses struct {
+	List    []types.Expense
+	Page    int
+	More    bool // whether there are further pages
+	Success bool
+}
+
+type GetExpense struct {
+	Item     types.Expense
+	NotFound bool
+	Success  bool
+}
+
+type UpdateExpense struct {
+	Ready           bool
+	NotFound        bool
+	UnknownCurrency bool
+	LimitAlert      int   // usage threshold (percent) of the limit crossed by the expense, 0 if none
+	Invalid         error // the input rejected by the model, see model.InputError
+	Success         bool
+}
+
+type DeleteExpense struct {
+	Ready    bool
+	NotFound bool
+	Success  bool
 }
 
 type GetReport struct {
 	From     time.Time
+	To       time.Time
+	Grouping types.ReportGrouping
+	Compare  types.DateRange
 	Currency string
 	Ready    bool
 	Data     map[string]int64
+	Income   map[string]int64
+	Buckets  []types.ReportBucket
+	Previous map[string]int64
+	// PreviousIncome holds incomes per category of the compared range.
+	PreviousIncome map[string]int64
+	// Members holds the amounts spent by each member of the user's group.
+	Members []types.MemberTotal
+	Success bool
+}
+
+type ExportExpenses struct {
+	Currency string
+	Ready    bool
+	Count    int
 	Success  bool
 }
 
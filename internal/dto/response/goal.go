@@ -0,0 +1,29 @@
+package response
+
+import "gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+
+type AddGoal struct {
+	UnknownCurrency bool
+	Duplicate       bool
+	Invalid         error // the input rejected by the model, see model.InputError
+	Success         bool
+}
+
+type Contribute struct {
+	UnknownCurrency bool
+	NotFound        bool
+	Goal            types.Goal
+	Success         bool
+}
+
+type DeleteGoal struct {
+	NotFound bool
+	Success  bool
+}
+
+type ListGoals struct {
+	Currency string
+	Ready    bool
+	List     []types.GoalProgress
+	Success  bool
+}
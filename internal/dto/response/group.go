@@ -0,0 +1,33 @@
+package response
+
+import "gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+
+type CreateGroup struct {
+	AlreadyMember bool // the user is a member of another group
+	Group         types.Group
+	Success       bool
+}
+
+type JoinGroup struct {
+	NotFound      bool
+	AlreadyMember bool // the user is a member of another group
+	Group         types.Group
+	Success       bool
+}
+
+type LeaveGroup struct {
+	NotFound bool
+	Success  bool
+}
+
+type RemoveGroupMember struct {
+	NotFound  bool
+	Forbidden bool // only the owner removes members
+	Success   bool
+}
+
+type GetGroup struct {
+	NotFound bool
+	Group    types.Group
+	Success  bool
+}
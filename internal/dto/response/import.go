@@ -0,0 +1,26 @@
+package response
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type ImportExpenses struct {
+	Ready            bool
+	UnknownStatement bool
+	InvalidStatement bool
+	Parsed           int // expenses found in the statement
+	Imported         int // expenses added, the rest have been imported before
+	// LimitAlerts holds usage thresholds (percent) of the limits crossed by the imported expenses by category.
+	LimitAlerts map[string]int
+	Success     bool
+}
+
+type ListMerchantRules struct {
+	List    []types.MerchantRule
+	Success bool
+}
+
+type SetMerchantRule struct {
+	NotFound bool
+	Success  bool
+}
@@ -0,0 +1,15 @@
+package response
+
+import "gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+
+type AddIncome struct {
+	UnknownCurrency bool
+	UnknownAccount  bool
+	Invalid         error // the input rejected by the model, see model.InputError
+	Success         bool
+}
+
+type ListIncomes struct {
+	List    []types.Income
+	Success bool
+}
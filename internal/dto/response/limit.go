@@ -4,12 +4,22 @@ import (
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 )
 
-type SetLimit bool
+type SetLimit struct {
+	Forbidden bool  // limits of a group are set by its owner only
+	Operation int64 // the journal entry to undo the change
+	Success   bool
+}
+
+type SetLimitAlerts struct {
+	Forbidden bool // limits of a group are set by its owner only
+	Success   bool
+}
 
 type ListLimits struct {
 	Ready           bool
 	CurrentCurrency string
 	List            map[string]LimitItem
+	Alerts          []int
 	Success         bool
 }
 
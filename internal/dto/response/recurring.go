@@ -0,0 +1,23 @@
+package response
+
+import (
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type AddRecurring struct {
+	UnknownCurrency bool
+	Invalid         error // the input rejected by the model, see model.InputError
+	Item            types.RecurringExpense
+	Success         bool
+}
+
+type ListRecurring struct {
+	List    []types.RecurringExpense
+	Success bool
+}
+
+type SetRecurringPaused struct {
+	NotFound bool
+	Item     types.RecurringExpense
+	Success  bool
+}
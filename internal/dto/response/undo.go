@@ -0,0 +1,11 @@
+package response
+
+import "gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+
+type Undo struct {
+	Ready     bool
+	NotFound  bool // there's nothing to undo within the window
+	Forbidden bool // the limits of the group are changed by its owner only
+	Success   bool
+	Kind      types.OperationKind // the undone operation
+}
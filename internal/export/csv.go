@@ -0,0 +1,42 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+type csvWriter struct {
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, header []string) (*csvWriter, error) {
+	c := &csvWriter{csv: csv.NewWriter(w)}
+	if err := c.csv.Write(header); err != nil {
+		return nil, errors.Wrap(err, "write csv header")
+	}
+
+	return c, nil
+}
+
+func (c *csvWriter) Write(row Row) error {
+	return errors.Wrap(c.csv.Write([]string{
+		row.Date.Format("2006-01-02"),
+		formatAmount(row.Amount),
+		row.Currency,
+		formatAmount(row.Converted),
+		row.Category,
+	}), "write csv row")
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+
+	return errors.Wrap(c.csv.Error(), "flush csv")
+}
+
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%.2f", float64(amount)/10000)
+}
@@ -0,0 +1,44 @@
+// Package export writes expenses into files which can be opened with spreadsheet applications.
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Row is an expense along with its amount converted into the currency of the export.
+type Row struct {
+	Date      time.Time
+	Amount    int64
+	Currency  string
+	Converted int64
+	Category  string
+}
+
+// Writer writes rows one by one, so expenses are streamed without collecting them in memory.
+// Close must be called to complete the file, it does not close the underlying writer.
+type Writer interface {
+	Write(row Row) error
+	Close() error
+}
+
+// NewWriter creates a writer of the format and writes the header, where the converted
+// amount column is named after the currency.
+func NewWriter(w io.Writer, format types.ExportFormat, currency string) (Writer, error) {
+	header := []string{"date", "amount", "currency", "amount_" + currency, "category"}
+
+	switch format {
+	case types.ExportCSV:
+		return newCSVWriter(w, header)
+
+	case types.ExportXLSX:
+		return newXLSXWriter(w, header)
+	}
+
+	return nil, ErrUnknownFormat
+}
@@ -0,0 +1,95 @@
+//go:build unit
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+var _rows = []Row{
+	{Date: time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), Amount: 1575000, Currency: "RUB", Converted: 1575000, Category: "coffee"},
+	{Date: time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC), Amount: 30000, Currency: "USD", Converted: 1845000, Category: "<cinema>, \"evening\""},
+}
+
+func Test_NewWriter(t *testing.T) {
+	t.Run("unknown format", func(t *testing.T) {
+		// ACT
+		_, err := NewWriter(io.Discard, "pdf", "RUB")
+
+		// ASSERT
+		assert.ErrorIs(t, err, ErrUnknownFormat)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		// ACT
+		content := writeRows(t, types.ExportCSV)
+
+		// ASSERT
+		assert.Equal(t, "date,amount,currency,amount_RUB,category\n"+
+			"2022-10-20,157.50,RUB,157.50,coffee\n"+
+			"2022-10-21,3.00,USD,184.50,\"<cinema>, \"\"evening\"\"\"\n", string(content))
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		// ACT
+		content := writeRows(t, types.ExportXLSX)
+
+		// ASSERT
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			t.Fatalf("cannot open archive: %s", err.Error())
+		}
+
+		parts := make(map[string]string)
+		for _, f := range archive.File {
+			r, err := f.Open()
+			if err != nil {
+				t.Fatalf("cannot open %s: %s", f.Name, err.Error())
+			}
+
+			part, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("cannot read %s: %s", f.Name, err.Error())
+			}
+
+			parts[f.Name] = string(part)
+		}
+
+		assert.Contains(t, parts, "[Content_Types].xml")
+		assert.Contains(t, parts, "xl/workbook.xml")
+		assert.Contains(t, parts, "xl/styles.xml")
+		assert.Contains(t, parts["xl/worksheets/sheet1.xml"], `<row r="1"><c t="inlineStr"><is><t>date</t></is></c>`)
+		assert.Contains(t, parts["xl/worksheets/sheet1.xml"], `<row r="3"><c s="1"><v>44855</v></c><c s="2"><v>3.00</v></c>`+
+			`<c t="inlineStr"><is><t>USD</t></is></c><c s="2"><v>184.50</v></c>`+
+			`<c t="inlineStr"><is><t>&lt;cinema&gt;, &#34;evening&#34;</t></is></c></row>`)
+		assert.Contains(t, parts["xl/worksheets/sheet1.xml"], `</sheetData></worksheet>`)
+	})
+}
+
+func writeRows(t *testing.T, format types.ExportFormat) []byte {
+	buf := new(bytes.Buffer)
+
+	w, err := NewWriter(buf, format, "RUB")
+	if err != nil {
+		t.Fatalf("cannot create writer: %s", err.Error())
+	}
+
+	for _, row := range _rows {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("cannot write row: %s", err.Error())
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("cannot close writer: %s", err.Error())
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,150 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// the minimal set of parts of a workbook with a single sheet; the sheet itself is streamed
+// as the last part of the archive
+var _xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="expenses" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		// cell formats: 0 — general, 1 — date (built-in format 14), 2 — amount (built-in format 2, "0.00")
+		name: "xl/styles.xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="3">` +
+			`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+			`<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+			`</cellXfs>` +
+			`</styleSheet>`,
+	},
+}
+
+// _xlsxEpoch is the day the serial numbers of dates are counted from.
+var _xlsxEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+func newXLSXWriter(w io.Writer, header []string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	for _, part := range _xlsxParts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "create %s", part.name)
+		}
+
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, errors.Wrapf(err, "write %s", part.name)
+		}
+	}
+
+	f, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, errors.Wrap(err, "create sheet")
+	}
+
+	x := &xlsxWriter{
+		archive: archive,
+		sheet:   bufio.NewWriter(f),
+	}
+
+	fmt.Fprint(x.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n")
+	fmt.Fprint(x.sheet, `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	x.startRow()
+	for _, title := range header {
+		x.writeString(title)
+	}
+	fmt.Fprint(x.sheet, `</row>`)
+
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(row Row) error {
+	x.startRow()
+	x.writeNumber(1, fmt.Sprintf("%d", int(row.Date.Sub(_xlsxEpoch).Hours()/24)))
+	x.writeNumber(2, formatAmount(row.Amount))
+	x.writeString(row.Currency)
+	x.writeNumber(2, formatAmount(row.Converted))
+	x.writeString(row.Category)
+	_, err := fmt.Fprint(x.sheet, `</row>`)
+
+	return errors.Wrap(err, "write sheet row")
+}
+
+func (x *xlsxWriter) Close() error {
+	fmt.Fprint(x.sheet, `</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return errors.Wrap(err, "flush sheet")
+	}
+
+	return errors.Wrap(x.archive.Close(), "close archive")
+}
+
+func (x *xlsxWriter) startRow() {
+	x.rows++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.rows)
+}
+
+func (x *xlsxWriter) writeNumber(style int, value string) {
+	fmt.Fprintf(x.sheet, `<c s="%d"><v>%s</v></c>`, style, value)
+}
+
+func (x *xlsxWriter) writeString(value string) {
+	fmt.Fprint(x.sheet, `<c t="inlineStr"><is><t>`)
+	_ = xml.EscapeText(x.sheet, []byte(value))
+	fmt.Fprint(x.sheet, `</t></is></c>`)
+}
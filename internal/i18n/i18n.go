@@ -0,0 +1,128 @@
+// Package i18n translates texts shown to users, the texts are written in Russian and looked up in catalogues
+// of other languages by the Russian text itself, so that a missing translation falls back to the original.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/ctxkey"
+)
+
+// Language is the lowercase ISO 639-1 code of the language.
+type Language string
+
+const (
+	Russian Language = "ru"
+	English Language = "en"
+
+	// Default is the language the texts are written in.
+	Default = Russian
+)
+
+// Languages are the supported languages in the order they are offered to users.
+var Languages = []Language{Russian, English}
+
+// _related are the languages of users who are more likely to read Russian than English.
+var _related = map[string]bool{"be": true, "kk": true, "ky": true, "uk": true, "uz": true}
+
+// Parse returns the supported language given by the code or the IETF tag, e.g. "en" or "en-US".
+func Parse(tag string) (Language, bool) {
+	code, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	for _, lang := range Languages {
+		if code == string(lang) {
+			return lang, true
+		}
+	}
+
+	return "", false
+}
+
+// Match returns the supported language closest to the one given by the tag. Users whose language is unknown
+// get the default one, users of other languages get English.
+func Match(tag string) Language {
+	if tag == "" {
+		return Default
+	}
+
+	if lang, ok := Parse(tag); ok {
+		return lang
+	}
+
+	code, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	if _related[code] {
+		return Russian
+	}
+
+	return English
+}
+
+// Catalogue holds translations of the texts written in the default language.
+type Catalogue map[Language]map[string]string
+
+// Translate returns the text in the language, the text itself when there's no translation.
+func (c Catalogue) Translate(lang Language, text string) string {
+	if translated, ok := c[lang][text]; ok {
+		return translated
+	}
+
+	return text
+}
+
+// Plural translates the plural forms separated by "|" and chooses the form for the number, e.g.
+// "%d день|%d дня|%d дней". Russian has three forms (one, few, many), English has two (one, other).
+// The last form is used when there are fewer forms than the language needs.
+func (c Catalogue) Plural(lang Language, forms string, n int64) string {
+	variants := strings.Split(c.Translate(lang, forms), "|")
+
+	i := pluralForm(lang, n)
+	if i >= len(variants) {
+		i = len(variants) - 1
+	}
+
+	return variants[i]
+}
+
+// Sprintf translates the format and formats it with the arguments.
+func (c Catalogue) Sprintf(lang Language, format string, args ...interface{}) string {
+	return fmt.Sprintf(c.Translate(lang, format), args...)
+}
+
+// pluralForm returns the index of the plural form of the number in the language.
+func pluralForm(lang Language, n int64) int {
+	if n < 0 {
+		n = -n
+	}
+
+	if lang != Russian {
+		if n == 1 {
+			return 0
+		}
+
+		return 1
+	}
+
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// WithLanguage returns the context the user's texts are translated within.
+func WithLanguage(ctx context.Context, lang Language) context.Context {
+	return context.WithValue(ctx, ctxkey.Language, lang)
+}
+
+// FromContext returns the language of the context, the default one when it's not set.
+func FromContext(ctx context.Context) Language {
+	if lang, ok := ctx.Value(ctxkey.Language).(Language); ok {
+		return lang
+	}
+
+	return Default
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _catalogue = Catalogue{
+	English: {
+		"Привет, %s!":            "Hello, %s!",
+		"%d день|%d дня|%d дней": "%d day|%d days",
+	},
+}
+
+func Test_Match(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Language
+	}{
+		{tag: "", want: Russian},
+		{tag: "ru", want: Russian},
+		{tag: "en-US", want: English},
+		{tag: "EN", want: English},
+		{tag: "uk", want: Russian},
+		{tag: "de", want: English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			// ACT
+			lang := Match(tt.tag)
+
+			// ASSERT
+			assert.Equal(t, tt.want, lang)
+		})
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	// ACT
+	lang, ok := Parse("en-GB")
+	_, unknown := Parse("de")
+
+	// ASSERT
+	assert.True(t, ok)
+	assert.Equal(t, English, lang)
+	assert.False(t, unknown)
+}
+
+func Test_Catalogue_Sprintf(t *testing.T) {
+	// ACT
+	english := _catalogue.Sprintf(English, "Привет, %s!", "Bob")
+	russian := _catalogue.Sprintf(Russian, "Привет, %s!", "Боб")
+	missing := _catalogue.Sprintf(English, "Пока, %s!", "Bob")
+
+	// ASSERT
+	assert.Equal(t, "Hello, Bob!", english)
+	assert.Equal(t, "Привет, Боб!", russian)
+	assert.Equal(t, "Пока, Bob!", missing)
+}
+
+func Test_Catalogue_Plural(t *testing.T) {
+	tests := []struct {
+		lang Language
+		n    int64
+		want string
+	}{
+		{lang: Russian, n: 1, want: "%d день"},
+		{lang: Russian, n: 3, want: "%d дня"},
+		{lang: Russian, n: 5, want: "%d дней"},
+		{lang: Russian, n: 11, want: "%d дней"},
+		{lang: Russian, n: 21, want: "%d день"},
+		{lang: Russian, n: 22, want: "%d дня"},
+		{lang: Russian, n: 112, want: "%d дней"},
+		{lang: English, n: 1, want: "%d day"},
+		{lang: English, n: 0, want: "%d days"},
+		{lang: English, n: 21, want: "%d days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang)+tt.want, func(t *testing.T) {
+			// ACT
+			form := _catalogue.Plural(tt.lang, "%d день|%d дня|%d дней", tt.n)
+
+			// ASSERT
+			assert.Equal(t, tt.want, form)
+		})
+	}
+}
+
+func Test_FromContext(t *testing.T) {
+	// ACT
+	lang := FromContext(WithLanguage(context.Background(), English))
+	def := FromContext(context.Background())
+
+	// ASSERT
+	assert.Equal(t, English, lang)
+	assert.Equal(t, Default, def)
+}
@@ -34,6 +34,21 @@ func (m *Mockapi) EXPECT() *MockapiMockRecorder {
 	return m.recorder
 }
 
+// GetFileDirectURL mocks base method.
+func (m *Mockapi) GetFileDirectURL(fileID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileDirectURL", fileID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileDirectURL indicates an expected call of GetFileDirectURL.
+func (mr *MockapiMockRecorder) GetFileDirectURL(fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileDirectURL", reflect.TypeOf((*Mockapi)(nil).GetFileDirectURL), fileID)
+}
+
 // GetUpdatesChan mocks base method.
 func (m *Mockapi) GetUpdatesChan(arg0 tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
 	m.ctrl.T.Helper()
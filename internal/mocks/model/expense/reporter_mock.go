@@ -37,17 +37,17 @@ func (m *Mockproducer) EXPECT() *MockproducerMockRecorder {
 }
 
 // Send mocks base method.
-func (m *Mockproducer) Send(ctx context.Context, user *types.User, from time.Time, currency string) error {
+func (m *Mockproducer) Send(ctx context.Context, user *types.User, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange, currency string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Send", ctx, user, from, currency)
+	ret := m.ctrl.Call(m, "Send", ctx, user, from, to, grouping, compare, currency)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Send indicates an expected call of Send.
-func (mr *MockproducerMockRecorder) Send(ctx, user, from, currency interface{}) *gomock.Call {
+func (mr *MockproducerMockRecorder) Send(ctx, user, from, to, grouping, compare, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*Mockproducer)(nil).Send), ctx, user, from, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*Mockproducer)(nil).Send), ctx, user, from, to, grouping, compare, currency)
 }
 
 // Mocklistener is a mock of listener interface.
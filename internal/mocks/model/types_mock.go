@@ -6,6 +6,7 @@ package mock_model
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 	time "time"
 
@@ -38,163 +39,1565 @@ func (m *MockController) EXPECT() *MockControllerMockRecorder {
 	return m.recorder
 }
 
+// AddAccount mocks base method.
+func (m *MockController) AddAccount(ctx context.Context, req request.AddAccount) response.AddAccount {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccount", ctx, req)
+	ret0, _ := ret[0].(response.AddAccount)
+	return ret0
+}
+
+// AddAccount indicates an expected call of AddAccount.
+func (mr *MockControllerMockRecorder) AddAccount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccount", reflect.TypeOf((*MockController)(nil).AddAccount), ctx, req)
+}
+
 // AddExpense mocks base method.
 func (m *MockController) AddExpense(ctx context.Context, req request.AddExpense) response.AddExpense {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddExpense", ctx, req)
-	ret0, _ := ret[0].(response.AddExpense)
-	return ret0
+	ret := m.ctrl.Call(m, "AddExpense", ctx, req)
+	ret0, _ := ret[0].(response.AddExpense)
+	return ret0
+}
+
+// AddExpense indicates an expected call of AddExpense.
+func (mr *MockControllerMockRecorder) AddExpense(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExpense", reflect.TypeOf((*MockController)(nil).AddExpense), ctx, req)
+}
+
+// AddGoal mocks base method.
+func (m *MockController) AddGoal(ctx context.Context, req request.AddGoal) response.AddGoal {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGoal", ctx, req)
+	ret0, _ := ret[0].(response.AddGoal)
+	return ret0
+}
+
+// AddGoal indicates an expected call of AddGoal.
+func (mr *MockControllerMockRecorder) AddGoal(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGoal", reflect.TypeOf((*MockController)(nil).AddGoal), ctx, req)
+}
+
+// AddIncome mocks base method.
+func (m *MockController) AddIncome(ctx context.Context, req request.AddIncome) response.AddIncome {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIncome", ctx, req)
+	ret0, _ := ret[0].(response.AddIncome)
+	return ret0
+}
+
+// AddIncome indicates an expected call of AddIncome.
+func (mr *MockControllerMockRecorder) AddIncome(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIncome", reflect.TypeOf((*MockController)(nil).AddIncome), ctx, req)
+}
+
+// AddRecurring mocks base method.
+func (m *MockController) AddRecurring(ctx context.Context, req request.AddRecurring) response.AddRecurring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecurring", ctx, req)
+	ret0, _ := ret[0].(response.AddRecurring)
+	return ret0
+}
+
+// AddRecurring indicates an expected call of AddRecurring.
+func (mr *MockControllerMockRecorder) AddRecurring(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecurring", reflect.TypeOf((*MockController)(nil).AddRecurring), ctx, req)
+}
+
+// AttachToExpense mocks base method.
+func (m *MockController) AttachToExpense(ctx context.Context, req request.AttachToExpense) response.AttachToExpense {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachToExpense", ctx, req)
+	ret0, _ := ret[0].(response.AttachToExpense)
+	return ret0
+}
+
+// AttachToExpense indicates an expected call of AttachToExpense.
+func (mr *MockControllerMockRecorder) AttachToExpense(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachToExpense", reflect.TypeOf((*MockController)(nil).AttachToExpense), ctx, req)
+}
+
+// Contribute mocks base method.
+func (m *MockController) Contribute(ctx context.Context, req request.Contribute) response.Contribute {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Contribute", ctx, req)
+	ret0, _ := ret[0].(response.Contribute)
+	return ret0
+}
+
+// Contribute indicates an expected call of Contribute.
+func (mr *MockControllerMockRecorder) Contribute(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contribute", reflect.TypeOf((*MockController)(nil).Contribute), ctx, req)
+}
+
+// CreateGroup mocks base method.
+func (m *MockController) CreateGroup(ctx context.Context, req request.CreateGroup) response.CreateGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, req)
+	ret0, _ := ret[0].(response.CreateGroup)
+	return ret0
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockControllerMockRecorder) CreateGroup(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockController)(nil).CreateGroup), ctx, req)
+}
+
+// DeleteExpense mocks base method.
+func (m *MockController) DeleteExpense(ctx context.Context, req request.DeleteExpense) response.DeleteExpense {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpense", ctx, req)
+	ret0, _ := ret[0].(response.DeleteExpense)
+	return ret0
+}
+
+// DeleteExpense indicates an expected call of DeleteExpense.
+func (mr *MockControllerMockRecorder) DeleteExpense(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpense", reflect.TypeOf((*MockController)(nil).DeleteExpense), ctx, req)
+}
+
+// DeleteGoal mocks base method.
+func (m *MockController) DeleteGoal(ctx context.Context, req request.DeleteGoal) response.DeleteGoal {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGoal", ctx, req)
+	ret0, _ := ret[0].(response.DeleteGoal)
+	return ret0
+}
+
+// DeleteGoal indicates an expected call of DeleteGoal.
+func (mr *MockControllerMockRecorder) DeleteGoal(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGoal", reflect.TypeOf((*MockController)(nil).DeleteGoal), ctx, req)
+}
+
+// ExportExpenses mocks base method.
+func (m *MockController) ExportExpenses(ctx context.Context, req request.ExportExpenses) response.ExportExpenses {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportExpenses", ctx, req)
+	ret0, _ := ret[0].(response.ExportExpenses)
+	return ret0
+}
+
+// ExportExpenses indicates an expected call of ExportExpenses.
+func (mr *MockControllerMockRecorder) ExportExpenses(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportExpenses", reflect.TypeOf((*MockController)(nil).ExportExpenses), ctx, req)
+}
+
+// FindExpenses mocks base method.
+func (m *MockController) FindExpenses(ctx context.Context, req request.FindExpenses) response.FindExpenses {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpenses", ctx, req)
+	ret0, _ := ret[0].(response.FindExpenses)
+	return ret0
+}
+
+// FindExpenses indicates an expected call of FindExpenses.
+func (mr *MockControllerMockRecorder) FindExpenses(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpenses", reflect.TypeOf((*MockController)(nil).FindExpenses), ctx, req)
+}
+
+// GetAttachment mocks base method.
+func (m *MockController) GetAttachment(ctx context.Context, req request.GetAttachment) response.GetAttachment {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, req)
+	ret0, _ := ret[0].(response.GetAttachment)
+	return ret0
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockControllerMockRecorder) GetAttachment(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockController)(nil).GetAttachment), ctx, req)
+}
+
+// GetBalance mocks base method.
+func (m *MockController) GetBalance(ctx context.Context, req request.GetBalance) response.GetBalance {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", ctx, req)
+	ret0, _ := ret[0].(response.GetBalance)
+	return ret0
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockControllerMockRecorder) GetBalance(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockController)(nil).GetBalance), ctx, req)
+}
+
+// GetExpense mocks base method.
+func (m *MockController) GetExpense(ctx context.Context, req request.GetExpense) response.GetExpense {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpense", ctx, req)
+	ret0, _ := ret[0].(response.GetExpense)
+	return ret0
+}
+
+// GetExpense indicates an expected call of GetExpense.
+func (mr *MockControllerMockRecorder) GetExpense(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpense", reflect.TypeOf((*MockController)(nil).GetExpense), ctx, req)
+}
+
+// GetGroup mocks base method.
+func (m *MockController) GetGroup(ctx context.Context, req request.GetGroup) response.GetGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroup", ctx, req)
+	ret0, _ := ret[0].(response.GetGroup)
+	return ret0
+}
+
+// GetGroup indicates an expected call of GetGroup.
+func (mr *MockControllerMockRecorder) GetGroup(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockController)(nil).GetGroup), ctx, req)
+}
+
+// GetReport mocks base method.
+func (m *MockController) GetReport(ctx context.Context, req request.GetReport) response.GetReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", ctx, req)
+	ret0, _ := ret[0].(response.GetReport)
+	return ret0
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockControllerMockRecorder) GetReport(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockController)(nil).GetReport), ctx, req)
+}
+
+// ImportExpenses mocks base method.
+func (m *MockController) ImportExpenses(ctx context.Context, req request.ImportExpenses) response.ImportExpenses {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportExpenses", ctx, req)
+	ret0, _ := ret[0].(response.ImportExpenses)
+	return ret0
+}
+
+// ImportExpenses indicates an expected call of ImportExpenses.
+func (mr *MockControllerMockRecorder) ImportExpenses(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportExpenses", reflect.TypeOf((*MockController)(nil).ImportExpenses), ctx, req)
+}
+
+// IsCurrencyAvailable mocks base method.
+func (m *MockController) IsCurrencyAvailable(code string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsCurrencyAvailable", code)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsCurrencyAvailable indicates an expected call of IsCurrencyAvailable.
+func (mr *MockControllerMockRecorder) IsCurrencyAvailable(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsCurrencyAvailable", reflect.TypeOf((*MockController)(nil).IsCurrencyAvailable), code)
+}
+
+// JoinGroup mocks base method.
+func (m *MockController) JoinGroup(ctx context.Context, req request.JoinGroup) response.JoinGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinGroup", ctx, req)
+	ret0, _ := ret[0].(response.JoinGroup)
+	return ret0
+}
+
+// JoinGroup indicates an expected call of JoinGroup.
+func (mr *MockControllerMockRecorder) JoinGroup(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinGroup", reflect.TypeOf((*MockController)(nil).JoinGroup), ctx, req)
+}
+
+// LeaveGroup mocks base method.
+func (m *MockController) LeaveGroup(ctx context.Context, req request.LeaveGroup) response.LeaveGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveGroup", ctx, req)
+	ret0, _ := ret[0].(response.LeaveGroup)
+	return ret0
+}
+
+// LeaveGroup indicates an expected call of LeaveGroup.
+func (mr *MockControllerMockRecorder) LeaveGroup(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveGroup", reflect.TypeOf((*MockController)(nil).LeaveGroup), ctx, req)
+}
+
+// ListAccounts mocks base method.
+func (m *MockController) ListAccounts(ctx context.Context, req request.ListAccounts) response.ListAccounts {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", ctx, req)
+	ret0, _ := ret[0].(response.ListAccounts)
+	return ret0
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockControllerMockRecorder) ListAccounts(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockController)(nil).ListAccounts), ctx, req)
+}
+
+// ListCategories mocks base method.
+func (m *MockController) ListCategories(ctx context.Context, req request.ListCategories) response.ListCategories {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx, req)
+	ret0, _ := ret[0].(response.ListCategories)
+	return ret0
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockControllerMockRecorder) ListCategories(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockController)(nil).ListCategories), ctx, req)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockController) ListCurrencies(ctx context.Context, req request.ListCurrencies) response.ListCurrencies {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", ctx, req)
+	ret0, _ := ret[0].(response.ListCurrencies)
+	return ret0
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockControllerMockRecorder) ListCurrencies(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockController)(nil).ListCurrencies), ctx, req)
+}
+
+// ListDebts mocks base method.
+func (m *MockController) ListDebts(ctx context.Context, req request.ListDebts) response.ListDebts {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDebts", ctx, req)
+	ret0, _ := ret[0].(response.ListDebts)
+	return ret0
+}
+
+// ListDebts indicates an expected call of ListDebts.
+func (mr *MockControllerMockRecorder) ListDebts(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDebts", reflect.TypeOf((*MockController)(nil).ListDebts), ctx, req)
+}
+
+// ListExpenses mocks base method.
+func (m *MockController) ListExpenses(ctx context.Context, req request.ListExpenses) response.ListExpenses {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpenses", ctx, req)
+	ret0, _ := ret[0].(response.ListExpenses)
+	return ret0
+}
+
+// ListExpenses indicates an expected call of ListExpenses.
+func (mr *MockControllerMockRecorder) ListExpenses(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpenses", reflect.TypeOf((*MockController)(nil).ListExpenses), ctx, req)
+}
+
+// ListGoals mocks base method.
+func (m *MockController) ListGoals(ctx context.Context, req request.ListGoals) response.ListGoals {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoals", ctx, req)
+	ret0, _ := ret[0].(response.ListGoals)
+	return ret0
+}
+
+// ListGoals indicates an expected call of ListGoals.
+func (mr *MockControllerMockRecorder) ListGoals(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoals", reflect.TypeOf((*MockController)(nil).ListGoals), ctx, req)
+}
+
+// ListIncomes mocks base method.
+func (m *MockController) ListIncomes(ctx context.Context, req request.ListIncomes) response.ListIncomes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomes", ctx, req)
+	ret0, _ := ret[0].(response.ListIncomes)
+	return ret0
+}
+
+// ListIncomes indicates an expected call of ListIncomes.
+func (mr *MockControllerMockRecorder) ListIncomes(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomes", reflect.TypeOf((*MockController)(nil).ListIncomes), ctx, req)
+}
+
+// ListLimits mocks base method.
+func (m *MockController) ListLimits(ctx context.Context, req request.ListLimits) response.ListLimits {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLimits", ctx, req)
+	ret0, _ := ret[0].(response.ListLimits)
+	return ret0
+}
+
+// ListLimits indicates an expected call of ListLimits.
+func (mr *MockControllerMockRecorder) ListLimits(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLimits", reflect.TypeOf((*MockController)(nil).ListLimits), ctx, req)
+}
+
+// ListMerchantRules mocks base method.
+func (m *MockController) ListMerchantRules(ctx context.Context, req request.ListMerchantRules) response.ListMerchantRules {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMerchantRules", ctx, req)
+	ret0, _ := ret[0].(response.ListMerchantRules)
+	return ret0
+}
+
+// ListMerchantRules indicates an expected call of ListMerchantRules.
+func (mr *MockControllerMockRecorder) ListMerchantRules(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMerchantRules", reflect.TypeOf((*MockController)(nil).ListMerchantRules), ctx, req)
+}
+
+// ListRecurring mocks base method.
+func (m *MockController) ListRecurring(ctx context.Context, req request.ListRecurring) response.ListRecurring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecurring", ctx, req)
+	ret0, _ := ret[0].(response.ListRecurring)
+	return ret0
+}
+
+// ListRecurring indicates an expected call of ListRecurring.
+func (mr *MockControllerMockRecorder) ListRecurring(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecurring", reflect.TypeOf((*MockController)(nil).ListRecurring), ctx, req)
+}
+
+// RemoveGroupMember mocks base method.
+func (m *MockController) RemoveGroupMember(ctx context.Context, req request.RemoveGroupMember) response.RemoveGroupMember {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveGroupMember", ctx, req)
+	ret0, _ := ret[0].(response.RemoveGroupMember)
+	return ret0
+}
+
+// RemoveGroupMember indicates an expected call of RemoveGroupMember.
+func (mr *MockControllerMockRecorder) RemoveGroupMember(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGroupMember", reflect.TypeOf((*MockController)(nil).RemoveGroupMember), ctx, req)
+}
+
+// RenameCategory mocks base method.
+func (m *MockController) RenameCategory(ctx context.Context, req request.RenameCategory) response.RenameCategory {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCategory", ctx, req)
+	ret0, _ := ret[0].(response.RenameCategory)
+	return ret0
+}
+
+// RenameCategory indicates an expected call of RenameCategory.
+func (mr *MockControllerMockRecorder) RenameCategory(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCategory", reflect.TypeOf((*MockController)(nil).RenameCategory), ctx, req)
+}
+
+// SetCategoryAlias mocks base method.
+func (m *MockController) SetCategoryAlias(ctx context.Context, req request.SetCategoryAlias) response.SetCategoryAlias {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCategoryAlias", ctx, req)
+	ret0, _ := ret[0].(response.SetCategoryAlias)
+	return ret0
+}
+
+// SetCategoryAlias indicates an expected call of SetCategoryAlias.
+func (mr *MockControllerMockRecorder) SetCategoryAlias(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCategoryAlias", reflect.TypeOf((*MockController)(nil).SetCategoryAlias), ctx, req)
+}
+
+// SetCurrency mocks base method.
+func (m *MockController) SetCurrency(ctx context.Context, req request.SetCurrency) response.SetCurrency {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCurrency", ctx, req)
+	ret0, _ := ret[0].(response.SetCurrency)
+	return ret0
+}
+
+// SetCurrency indicates an expected call of SetCurrency.
+func (mr *MockControllerMockRecorder) SetCurrency(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrency", reflect.TypeOf((*MockController)(nil).SetCurrency), ctx, req)
+}
+
+// SetLimit mocks base method.
+func (m *MockController) SetLimit(ctx context.Context, req request.SetLimit) response.SetLimit {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimit", ctx, req)
+	ret0, _ := ret[0].(response.SetLimit)
+	return ret0
+}
+
+// SetLimit indicates an expected call of SetLimit.
+func (mr *MockControllerMockRecorder) SetLimit(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockController)(nil).SetLimit), ctx, req)
+}
+
+// SetLimitAlerts mocks base method.
+func (m *MockController) SetLimitAlerts(ctx context.Context, req request.SetLimitAlerts) response.SetLimitAlerts {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimitAlerts", ctx, req)
+	ret0, _ := ret[0].(response.SetLimitAlerts)
+	return ret0
+}
+
+// SetLimitAlerts indicates an expected call of SetLimitAlerts.
+func (mr *MockControllerMockRecorder) SetLimitAlerts(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimitAlerts", reflect.TypeOf((*MockController)(nil).SetLimitAlerts), ctx, req)
+}
+
+// SetMerchantRule mocks base method.
+func (m *MockController) SetMerchantRule(ctx context.Context, req request.SetMerchantRule) response.SetMerchantRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMerchantRule", ctx, req)
+	ret0, _ := ret[0].(response.SetMerchantRule)
+	return ret0
+}
+
+// SetMerchantRule indicates an expected call of SetMerchantRule.
+func (mr *MockControllerMockRecorder) SetMerchantRule(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMerchantRule", reflect.TypeOf((*MockController)(nil).SetMerchantRule), ctx, req)
+}
+
+// SetRecurringPaused mocks base method.
+func (m *MockController) SetRecurringPaused(ctx context.Context, req request.SetRecurringPaused) response.SetRecurringPaused {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRecurringPaused", ctx, req)
+	ret0, _ := ret[0].(response.SetRecurringPaused)
+	return ret0
+}
+
+// SetRecurringPaused indicates an expected call of SetRecurringPaused.
+func (mr *MockControllerMockRecorder) SetRecurringPaused(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecurringPaused", reflect.TypeOf((*MockController)(nil).SetRecurringPaused), ctx, req)
+}
+
+// SettleDebts mocks base method.
+func (m *MockController) SettleDebts(ctx context.Context, req request.SettleDebts) response.SettleDebts {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleDebts", ctx, req)
+	ret0, _ := ret[0].(response.SettleDebts)
+	return ret0
+}
+
+// SettleDebts indicates an expected call of SettleDebts.
+func (mr *MockControllerMockRecorder) SettleDebts(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleDebts", reflect.TypeOf((*MockController)(nil).SettleDebts), ctx, req)
+}
+
+// Transfer mocks base method.
+func (m *MockController) Transfer(ctx context.Context, req request.Transfer) response.Transfer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, req)
+	ret0, _ := ret[0].(response.Transfer)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockControllerMockRecorder) Transfer(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockController)(nil).Transfer), ctx, req)
+}
+
+// Undo mocks base method.
+func (m *MockController) Undo(ctx context.Context, req request.Undo) response.Undo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undo", ctx, req)
+	ret0, _ := ret[0].(response.Undo)
+	return ret0
+}
+
+// Undo indicates an expected call of Undo.
+func (mr *MockControllerMockRecorder) Undo(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undo", reflect.TypeOf((*MockController)(nil).Undo), ctx, req)
+}
+
+// UpdateExpense mocks base method.
+func (m *MockController) UpdateExpense(ctx context.Context, req request.UpdateExpense) response.UpdateExpense {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExpense", ctx, req)
+	ret0, _ := ret[0].(response.UpdateExpense)
+	return ret0
+}
+
+// UpdateExpense indicates an expected call of UpdateExpense.
+func (mr *MockControllerMockRecorder) UpdateExpense(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExpense", reflect.TypeOf((*MockController)(nil).UpdateExpense), ctx, req)
+}
+
+// MockExpenser is a mock of Expenser interface.
+type MockExpenser struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpenserMockRecorder
+}
+
+// MockExpenserMockRecorder is the mock recorder for MockExpenser.
+type MockExpenserMockRecorder struct {
+	mock *MockExpenser
+}
+
+// NewMockExpenser creates a new mock instance.
+func NewMockExpenser(ctrl *gomock.Controller) *MockExpenser {
+	mock := &MockExpenser{ctrl: ctrl}
+	mock.recorder = &MockExpenserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenser) EXPECT() *MockExpenserMockRecorder {
+	return m.recorder
+}
+
+// AddExpense mocks base method.
+func (m *MockExpenser) AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64, note string, tags []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddExpense", ctx, user, date, amount, currency, category, account, note, tags)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddExpense indicates an expected call of AddExpense.
+func (mr *MockExpenserMockRecorder) AddExpense(ctx, user, date, amount, currency, category, account, note, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExpense", reflect.TypeOf((*MockExpenser)(nil).AddExpense), ctx, user, date, amount, currency, category, account, note, tags)
+}
+
+// DeleteExpense mocks base method.
+func (m *MockExpenser) DeleteExpense(ctx context.Context, user *types.User, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpense", ctx, user, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpense indicates an expected call of DeleteExpense.
+func (mr *MockExpenserMockRecorder) DeleteExpense(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpense", reflect.TypeOf((*MockExpenser)(nil).DeleteExpense), ctx, user, id)
+}
+
+// FindExpenses mocks base method.
+func (m *MockExpenser) FindExpenses(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpenses", ctx, user, filter, offset, limit)
+	ret0, _ := ret[0].([]types.Expense)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindExpenses indicates an expected call of FindExpenses.
+func (mr *MockExpenserMockRecorder) FindExpenses(ctx, user, filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpenses", reflect.TypeOf((*MockExpenser)(nil).FindExpenses), ctx, user, filter, offset, limit)
+}
+
+// GetExpense mocks base method.
+func (m *MockExpenser) GetExpense(ctx context.Context, user *types.User, id int64) (types.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpense", ctx, user, id)
+	ret0, _ := ret[0].(types.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpense indicates an expected call of GetExpense.
+func (mr *MockExpenserMockRecorder) GetExpense(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpense", reflect.TypeOf((*MockExpenser)(nil).GetExpense), ctx, user, id)
+}
+
+// ImportExpenses mocks base method.
+func (m *MockExpenser) ImportExpenses(ctx context.Context, user *types.User, items []types.ImportedExpense) ([]types.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportExpenses", ctx, user, items)
+	ret0, _ := ret[0].([]types.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportExpenses indicates an expected call of ImportExpenses.
+func (mr *MockExpenserMockRecorder) ImportExpenses(ctx, user, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportExpenses", reflect.TypeOf((*MockExpenser)(nil).ImportExpenses), ctx, user, items)
+}
+
+// ListLastExpenses mocks base method.
+func (m *MockExpenser) ListLastExpenses(ctx context.Context, user *types.User, count int) ([]types.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLastExpenses", ctx, user, count)
+	ret0, _ := ret[0].([]types.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLastExpenses indicates an expected call of ListLastExpenses.
+func (mr *MockExpenserMockRecorder) ListLastExpenses(ctx, user, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLastExpenses", reflect.TypeOf((*MockExpenser)(nil).ListLastExpenses), ctx, user, count)
+}
+
+// UpdateExpense mocks base method.
+func (m *MockExpenser) UpdateExpense(ctx context.Context, user *types.User, id int64, date time.Time, amount int64, currency, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExpense", ctx, user, id, date, amount, currency, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExpense indicates an expected call of UpdateExpense.
+func (mr *MockExpenserMockRecorder) UpdateExpense(ctx, user, id, date, amount, currency, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExpense", reflect.TypeOf((*MockExpenser)(nil).UpdateExpense), ctx, user, id, date, amount, currency, category)
+}
+
+// MockCategorizer is a mock of Categorizer interface.
+type MockCategorizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategorizerMockRecorder
+}
+
+// MockCategorizerMockRecorder is the mock recorder for MockCategorizer.
+type MockCategorizerMockRecorder struct {
+	mock *MockCategorizer
+}
+
+// NewMockCategorizer creates a new mock instance.
+func NewMockCategorizer(ctrl *gomock.Controller) *MockCategorizer {
+	mock := &MockCategorizer{ctrl: ctrl}
+	mock.recorder = &MockCategorizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategorizer) EXPECT() *MockCategorizerMockRecorder {
+	return m.recorder
+}
+
+// DeleteAlias mocks base method.
+func (m *MockCategorizer) DeleteAlias(ctx context.Context, user *types.User, alias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", ctx, user, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockCategorizerMockRecorder) DeleteAlias(ctx, user, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockCategorizer)(nil).DeleteAlias), ctx, user, alias)
+}
+
+// ListCategories mocks base method.
+func (m *MockCategorizer) ListCategories(ctx context.Context, user *types.User) ([]types.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx, user)
+	ret0, _ := ret[0].([]types.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockCategorizerMockRecorder) ListCategories(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCategorizer)(nil).ListCategories), ctx, user)
+}
+
+// RenameCategory mocks base method.
+func (m *MockCategorizer) RenameCategory(ctx context.Context, user *types.User, from, to string, merge bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCategory", ctx, user, from, to, merge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameCategory indicates an expected call of RenameCategory.
+func (mr *MockCategorizerMockRecorder) RenameCategory(ctx, user, from, to, merge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCategory", reflect.TypeOf((*MockCategorizer)(nil).RenameCategory), ctx, user, from, to, merge)
+}
+
+// ResolveCategory mocks base method.
+func (m *MockCategorizer) ResolveCategory(ctx context.Context, user *types.User, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveCategory", ctx, user, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveCategory indicates an expected call of ResolveCategory.
+func (mr *MockCategorizerMockRecorder) ResolveCategory(ctx, user, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveCategory", reflect.TypeOf((*MockCategorizer)(nil).ResolveCategory), ctx, user, name)
+}
+
+// SetAlias mocks base method.
+func (m *MockCategorizer) SetAlias(ctx context.Context, user *types.User, alias, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlias", ctx, user, alias, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAlias indicates an expected call of SetAlias.
+func (mr *MockCategorizerMockRecorder) SetAlias(ctx, user, alias, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlias", reflect.TypeOf((*MockCategorizer)(nil).SetAlias), ctx, user, alias, category)
+}
+
+// MockAttacher is a mock of Attacher interface.
+type MockAttacher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttacherMockRecorder
+}
+
+// MockAttacherMockRecorder is the mock recorder for MockAttacher.
+type MockAttacherMockRecorder struct {
+	mock *MockAttacher
+}
+
+// NewMockAttacher creates a new mock instance.
+func NewMockAttacher(ctrl *gomock.Controller) *MockAttacher {
+	mock := &MockAttacher{ctrl: ctrl}
+	mock.recorder = &MockAttacherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttacher) EXPECT() *MockAttacherMockRecorder {
+	return m.recorder
+}
+
+// Attach mocks base method.
+func (m *MockAttacher) Attach(ctx context.Context, user *types.User, expenseID int64, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attach", ctx, user, expenseID, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attach indicates an expected call of Attach.
+func (mr *MockAttacherMockRecorder) Attach(ctx, user, expenseID, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockAttacher)(nil).Attach), ctx, user, expenseID, data)
+}
+
+// GetAttachment mocks base method.
+func (m *MockAttacher) GetAttachment(ctx context.Context, user *types.User, expenseID int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, user, expenseID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockAttacherMockRecorder) GetAttachment(ctx, user, expenseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockAttacher)(nil).GetAttachment), ctx, user, expenseID)
+}
+
+// ListAttached mocks base method.
+func (m *MockAttacher) ListAttached(ctx context.Context, user *types.User, ids []int64) (map[int64]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttached", ctx, user, ids)
+	ret0, _ := ret[0].(map[int64]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttached indicates an expected call of ListAttached.
+func (mr *MockAttacherMockRecorder) ListAttached(ctx, user, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttached", reflect.TypeOf((*MockAttacher)(nil).ListAttached), ctx, user, ids)
+}
+
+// MockIncomer is a mock of Incomer interface.
+type MockIncomer struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncomerMockRecorder
+}
+
+// MockIncomerMockRecorder is the mock recorder for MockIncomer.
+type MockIncomerMockRecorder struct {
+	mock *MockIncomer
+}
+
+// NewMockIncomer creates a new mock instance.
+func NewMockIncomer(ctrl *gomock.Controller) *MockIncomer {
+	mock := &MockIncomer{ctrl: ctrl}
+	mock.recorder = &MockIncomerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncomer) EXPECT() *MockIncomerMockRecorder {
+	return m.recorder
+}
+
+// AddIncome mocks base method.
+func (m *MockIncomer) AddIncome(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIncome", ctx, user, date, amount, currency, category, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddIncome indicates an expected call of AddIncome.
+func (mr *MockIncomerMockRecorder) AddIncome(ctx, user, date, amount, currency, category, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIncome", reflect.TypeOf((*MockIncomer)(nil).AddIncome), ctx, user, date, amount, currency, category, account)
+}
+
+// ListLastIncomes mocks base method.
+func (m *MockIncomer) ListLastIncomes(ctx context.Context, user *types.User, count int) ([]types.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLastIncomes", ctx, user, count)
+	ret0, _ := ret[0].([]types.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLastIncomes indicates an expected call of ListLastIncomes.
+func (mr *MockIncomerMockRecorder) ListLastIncomes(ctx, user, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLastIncomes", reflect.TypeOf((*MockIncomer)(nil).ListLastIncomes), ctx, user, count)
+}
+
+// MockAccounter is a mock of Accounter interface.
+type MockAccounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccounterMockRecorder
+}
+
+// MockAccounterMockRecorder is the mock recorder for MockAccounter.
+type MockAccounterMockRecorder struct {
+	mock *MockAccounter
+}
+
+// NewMockAccounter creates a new mock instance.
+func NewMockAccounter(ctrl *gomock.Controller) *MockAccounter {
+	mock := &MockAccounter{ctrl: ctrl}
+	mock.recorder = &MockAccounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccounter) EXPECT() *MockAccounterMockRecorder {
+	return m.recorder
+}
+
+// AddAccount mocks base method.
+func (m *MockAccounter) AddAccount(ctx context.Context, user *types.User, name, currency string, opening int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccount", ctx, user, name, currency, opening)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAccount indicates an expected call of AddAccount.
+func (mr *MockAccounterMockRecorder) AddAccount(ctx, user, name, currency, opening interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccount", reflect.TypeOf((*MockAccounter)(nil).AddAccount), ctx, user, name, currency, opening)
+}
+
+// GetAccount mocks base method.
+func (m *MockAccounter) GetAccount(ctx context.Context, user *types.User, name string) (types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", ctx, user, name)
+	ret0, _ := ret[0].(types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccounterMockRecorder) GetAccount(ctx, user, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccounter)(nil).GetAccount), ctx, user, name)
+}
+
+// ListAccounts mocks base method.
+func (m *MockAccounter) ListAccounts(ctx context.Context, user *types.User) ([]types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", ctx, user)
+	ret0, _ := ret[0].([]types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockAccounterMockRecorder) ListAccounts(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccounter)(nil).ListAccounts), ctx, user)
+}
+
+// ListBalances mocks base method.
+func (m *MockAccounter) ListBalances(ctx context.Context, user *types.User, currency string, date time.Time) ([]types.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalances", ctx, user, currency, date)
+	ret0, _ := ret[0].([]types.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalances indicates an expected call of ListBalances.
+func (mr *MockAccounterMockRecorder) ListBalances(ctx, user, currency, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalances", reflect.TypeOf((*MockAccounter)(nil).ListBalances), ctx, user, currency, date)
+}
+
+// Transfer mocks base method.
+func (m *MockAccounter) Transfer(ctx context.Context, user *types.User, transfer types.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, user, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockAccounterMockRecorder) Transfer(ctx, user, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockAccounter)(nil).Transfer), ctx, user, transfer)
+}
+
+// MockSaver is a mock of Saver interface.
+type MockSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockSaverMockRecorder
+}
+
+// MockSaverMockRecorder is the mock recorder for MockSaver.
+type MockSaverMockRecorder struct {
+	mock *MockSaver
+}
+
+// NewMockSaver creates a new mock instance.
+func NewMockSaver(ctrl *gomock.Controller) *MockSaver {
+	mock := &MockSaver{ctrl: ctrl}
+	mock.recorder = &MockSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSaver) EXPECT() *MockSaverMockRecorder {
+	return m.recorder
+}
+
+// AddGoal mocks base method.
+func (m *MockSaver) AddGoal(ctx context.Context, user *types.User, goal types.Goal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGoal", ctx, user, goal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddGoal indicates an expected call of AddGoal.
+func (mr *MockSaverMockRecorder) AddGoal(ctx, user, goal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGoal", reflect.TypeOf((*MockSaver)(nil).AddGoal), ctx, user, goal)
+}
+
+// Contribute mocks base method.
+func (m *MockSaver) Contribute(ctx context.Context, user *types.User, name string, date time.Time, amount int64, currency string) (types.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Contribute", ctx, user, name, date, amount, currency)
+	ret0, _ := ret[0].(types.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Contribute indicates an expected call of Contribute.
+func (mr *MockSaverMockRecorder) Contribute(ctx, user, name, date, amount, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contribute", reflect.TypeOf((*MockSaver)(nil).Contribute), ctx, user, name, date, amount, currency)
+}
+
+// DeleteGoal mocks base method.
+func (m *MockSaver) DeleteGoal(ctx context.Context, user *types.User, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGoal", ctx, user, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGoal indicates an expected call of DeleteGoal.
+func (mr *MockSaverMockRecorder) DeleteGoal(ctx, user, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGoal", reflect.TypeOf((*MockSaver)(nil).DeleteGoal), ctx, user, name)
+}
+
+// ListGoals mocks base method.
+func (m *MockSaver) ListGoals(ctx context.Context, user *types.User, currency string, date time.Time) ([]types.GoalProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoals", ctx, user, currency, date)
+	ret0, _ := ret[0].([]types.GoalProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGoals indicates an expected call of ListGoals.
+func (mr *MockSaverMockRecorder) ListGoals(ctx, user, currency, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoals", reflect.TypeOf((*MockSaver)(nil).ListGoals), ctx, user, currency, date)
+}
+
+// MockGrouper is a mock of Grouper interface.
+type MockGrouper struct {
+	ctrl     *gomock.Controller
+	recorder *MockGrouperMockRecorder
+}
+
+// MockGrouperMockRecorder is the mock recorder for MockGrouper.
+type MockGrouperMockRecorder struct {
+	mock *MockGrouper
+}
+
+// NewMockGrouper creates a new mock instance.
+func NewMockGrouper(ctrl *gomock.Controller) *MockGrouper {
+	mock := &MockGrouper{ctrl: ctrl}
+	mock.recorder = &MockGrouperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGrouper) EXPECT() *MockGrouperMockRecorder {
+	return m.recorder
+}
+
+// CreateGroup mocks base method.
+func (m *MockGrouper) CreateGroup(ctx context.Context, user *types.User, name string) (types.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, user, name)
+	ret0, _ := ret[0].(types.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockGrouperMockRecorder) CreateGroup(ctx, user, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockGrouper)(nil).CreateGroup), ctx, user, name)
+}
+
+// GetGroup mocks base method.
+func (m *MockGrouper) GetGroup(ctx context.Context, user *types.User) (types.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroup", ctx, user)
+	ret0, _ := ret[0].(types.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroup indicates an expected call of GetGroup.
+func (mr *MockGrouperMockRecorder) GetGroup(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockGrouper)(nil).GetGroup), ctx, user)
+}
+
+// JoinGroup mocks base method.
+func (m *MockGrouper) JoinGroup(ctx context.Context, user *types.User, code, name string) (types.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinGroup", ctx, user, code, name)
+	ret0, _ := ret[0].(types.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinGroup indicates an expected call of JoinGroup.
+func (mr *MockGrouperMockRecorder) JoinGroup(ctx, user, code, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinGroup", reflect.TypeOf((*MockGrouper)(nil).JoinGroup), ctx, user, code, name)
+}
+
+// LeaveGroup mocks base method.
+func (m *MockGrouper) LeaveGroup(ctx context.Context, user *types.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveGroup", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaveGroup indicates an expected call of LeaveGroup.
+func (mr *MockGrouperMockRecorder) LeaveGroup(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveGroup", reflect.TypeOf((*MockGrouper)(nil).LeaveGroup), ctx, user)
+}
+
+// RemoveMember mocks base method.
+func (m *MockGrouper) RemoveMember(ctx context.Context, owner *types.User, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, owner, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockGrouperMockRecorder) RemoveMember(ctx, owner, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockGrouper)(nil).RemoveMember), ctx, owner, name)
+}
+
+// MockDebtor is a mock of Debtor interface.
+type MockDebtor struct {
+	ctrl     *gomock.Controller
+	recorder *MockDebtorMockRecorder
+}
+
+// MockDebtorMockRecorder is the mock recorder for MockDebtor.
+type MockDebtorMockRecorder struct {
+	mock *MockDebtor
+}
+
+// NewMockDebtor creates a new mock instance.
+func NewMockDebtor(ctrl *gomock.Controller) *MockDebtor {
+	mock := &MockDebtor{ctrl: ctrl}
+	mock.recorder = &MockDebtorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDebtor) EXPECT() *MockDebtorMockRecorder {
+	return m.recorder
+}
+
+// AddDebt mocks base method.
+func (m *MockDebtor) AddDebt(ctx context.Context, debt types.Debt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDebt", ctx, debt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDebt indicates an expected call of AddDebt.
+func (mr *MockDebtorMockRecorder) AddDebt(ctx, debt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDebt", reflect.TypeOf((*MockDebtor)(nil).AddDebt), ctx, debt)
+}
+
+// ListDebts mocks base method.
+func (m *MockDebtor) ListDebts(ctx context.Context, user *types.User) ([]types.DebtBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDebts", ctx, user)
+	ret0, _ := ret[0].([]types.DebtBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDebts indicates an expected call of ListDebts.
+func (mr *MockDebtorMockRecorder) ListDebts(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDebts", reflect.TypeOf((*MockDebtor)(nil).ListDebts), ctx, user)
+}
+
+// SettleDebts mocks base method.
+func (m *MockDebtor) SettleDebts(ctx context.Context, user *types.User, name, currency string) ([]types.DebtBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleDebts", ctx, user, name, currency)
+	ret0, _ := ret[0].([]types.DebtBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AddExpense indicates an expected call of AddExpense.
-func (mr *MockControllerMockRecorder) AddExpense(ctx, req interface{}) *gomock.Call {
+// SettleDebts indicates an expected call of SettleDebts.
+func (mr *MockDebtorMockRecorder) SettleDebts(ctx, user, name, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExpense", reflect.TypeOf((*MockController)(nil).AddExpense), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleDebts", reflect.TypeOf((*MockDebtor)(nil).SettleDebts), ctx, user, name, currency)
+}
+
+// MockReporter is a mock of Reporter interface.
+type MockReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockReporterMockRecorder
+}
+
+// MockReporterMockRecorder is the mock recorder for MockReporter.
+type MockReporterMockRecorder struct {
+	mock *MockReporter
+}
+
+// NewMockReporter creates a new mock instance.
+func NewMockReporter(ctrl *gomock.Controller) *MockReporter {
+	mock := &MockReporter{ctrl: ctrl}
+	mock.recorder = &MockReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReporter) EXPECT() *MockReporterMockRecorder {
+	return m.recorder
 }
 
 // GetReport mocks base method.
-func (m *MockController) GetReport(ctx context.Context, req request.GetReport) response.GetReport {
+func (m *MockReporter) GetReport(ctx context.Context, user *types.User, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange, currency string) (types.ReportData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReport", ctx, req)
-	ret0, _ := ret[0].(response.GetReport)
-	return ret0
+	ret := m.ctrl.Call(m, "GetReport", ctx, user, from, to, grouping, compare, currency)
+	ret0, _ := ret[0].(types.ReportData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetReport indicates an expected call of GetReport.
-func (mr *MockControllerMockRecorder) GetReport(ctx, req interface{}) *gomock.Call {
+func (mr *MockReporterMockRecorder) GetReport(ctx, user, from, to, grouping, compare, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockController)(nil).GetReport), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockReporter)(nil).GetReport), ctx, user, from, to, grouping, compare, currency)
 }
 
-// ListCurrencies mocks base method.
-func (m *MockController) ListCurrencies(ctx context.Context, req request.ListCurrencies) response.ListCurrencies {
+// MockExporter is a mock of Exporter interface.
+type MockExporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExporterMockRecorder
+}
+
+// MockExporterMockRecorder is the mock recorder for MockExporter.
+type MockExporterMockRecorder struct {
+	mock *MockExporter
+}
+
+// NewMockExporter creates a new mock instance.
+func NewMockExporter(ctrl *gomock.Controller) *MockExporter {
+	mock := &MockExporter{ctrl: ctrl}
+	mock.recorder = &MockExporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExporter) EXPECT() *MockExporterMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExporter) Export(ctx context.Context, user *types.User, from, to time.Time, format types.ExportFormat, currency string, w io.Writer) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCurrencies", ctx, req)
-	ret0, _ := ret[0].(response.ListCurrencies)
-	return ret0
+	ret := m.ctrl.Call(m, "Export", ctx, user, from, to, format, currency, w)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ListCurrencies indicates an expected call of ListCurrencies.
-func (mr *MockControllerMockRecorder) ListCurrencies(ctx, req interface{}) *gomock.Call {
+// Export indicates an expected call of Export.
+func (mr *MockExporterMockRecorder) Export(ctx, user, from, to, format, currency, w interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockController)(nil).ListCurrencies), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExporter)(nil).Export), ctx, user, from, to, format, currency, w)
 }
 
-// ListLimits mocks base method.
-func (m *MockController) ListLimits(ctx context.Context, req request.ListLimits) response.ListLimits {
+// MockImporter is a mock of Importer interface.
+type MockImporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockImporterMockRecorder
+}
+
+// MockImporterMockRecorder is the mock recorder for MockImporter.
+type MockImporterMockRecorder struct {
+	mock *MockImporter
+}
+
+// NewMockImporter creates a new mock instance.
+func NewMockImporter(ctrl *gomock.Controller) *MockImporter {
+	mock := &MockImporter{ctrl: ctrl}
+	mock.recorder = &MockImporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImporter) EXPECT() *MockImporterMockRecorder {
+	return m.recorder
+}
+
+// DeleteRule mocks base method.
+func (m *MockImporter) DeleteRule(ctx context.Context, user *types.User, pattern string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListLimits", ctx, req)
-	ret0, _ := ret[0].(response.ListLimits)
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, user, pattern)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ListLimits indicates an expected call of ListLimits.
-func (mr *MockControllerMockRecorder) ListLimits(ctx, req interface{}) *gomock.Call {
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockImporterMockRecorder) DeleteRule(ctx, user, pattern interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLimits", reflect.TypeOf((*MockController)(nil).ListLimits), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockImporter)(nil).DeleteRule), ctx, user, pattern)
 }
 
-// SetCurrency mocks base method.
-func (m *MockController) SetCurrency(ctx context.Context, req request.SetCurrency) response.SetCurrency {
+// ListRules mocks base method.
+func (m *MockImporter) ListRules(ctx context.Context, user *types.User) ([]types.MerchantRule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetCurrency", ctx, req)
-	ret0, _ := ret[0].(response.SetCurrency)
-	return ret0
+	ret := m.ctrl.Call(m, "ListRules", ctx, user)
+	ret0, _ := ret[0].([]types.MerchantRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// SetCurrency indicates an expected call of SetCurrency.
-func (mr *MockControllerMockRecorder) SetCurrency(ctx, req interface{}) *gomock.Call {
+// ListRules indicates an expected call of ListRules.
+func (mr *MockImporterMockRecorder) ListRules(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrency", reflect.TypeOf((*MockController)(nil).SetCurrency), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockImporter)(nil).ListRules), ctx, user)
 }
 
-// SetLimit mocks base method.
-func (m *MockController) SetLimit(ctx context.Context, req request.SetLimit) response.SetLimit {
+// Parse mocks base method.
+func (m *MockImporter) Parse(ctx context.Context, user *types.User, name, bank string, data []byte) ([]types.ImportedExpense, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetLimit", ctx, req)
-	ret0, _ := ret[0].(response.SetLimit)
+	ret := m.ctrl.Call(m, "Parse", ctx, user, name, bank, data)
+	ret0, _ := ret[0].([]types.ImportedExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Parse indicates an expected call of Parse.
+func (mr *MockImporterMockRecorder) Parse(ctx, user, name, bank, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockImporter)(nil).Parse), ctx, user, name, bank, data)
+}
+
+// SetRule mocks base method.
+func (m *MockImporter) SetRule(ctx context.Context, user *types.User, pattern, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRule", ctx, user, pattern, category)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetLimit indicates an expected call of SetLimit.
-func (mr *MockControllerMockRecorder) SetLimit(ctx, req interface{}) *gomock.Call {
+// SetRule indicates an expected call of SetRule.
+func (mr *MockImporterMockRecorder) SetRule(ctx, user, pattern, category interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockController)(nil).SetLimit), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRule", reflect.TypeOf((*MockImporter)(nil).SetRule), ctx, user, pattern, category)
 }
 
-// MockExpenser is a mock of Expenser interface.
-type MockExpenser struct {
+// MockRecurrer is a mock of Recurrer interface.
+type MockRecurrer struct {
 	ctrl     *gomock.Controller
-	recorder *MockExpenserMockRecorder
+	recorder *MockRecurrerMockRecorder
 }
 
-// MockExpenserMockRecorder is the mock recorder for MockExpenser.
-type MockExpenserMockRecorder struct {
-	mock *MockExpenser
+// MockRecurrerMockRecorder is the mock recorder for MockRecurrer.
+type MockRecurrerMockRecorder struct {
+	mock *MockRecurrer
 }
 
-// NewMockExpenser creates a new mock instance.
-func NewMockExpenser(ctrl *gomock.Controller) *MockExpenser {
-	mock := &MockExpenser{ctrl: ctrl}
-	mock.recorder = &MockExpenserMockRecorder{mock}
+// NewMockRecurrer creates a new mock instance.
+func NewMockRecurrer(ctrl *gomock.Controller) *MockRecurrer {
+	mock := &MockRecurrer{ctrl: ctrl}
+	mock.recorder = &MockRecurrerMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockExpenser) EXPECT() *MockExpenserMockRecorder {
+func (m *MockRecurrer) EXPECT() *MockRecurrerMockRecorder {
 	return m.recorder
 }
 
-// AddExpense mocks base method.
-func (m *MockExpenser) AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string) error {
+// Add mocks base method.
+func (m *MockRecurrer) Add(ctx context.Context, user *types.User, item types.RecurringExpense) (types.RecurringExpense, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddExpense", ctx, user, date, amount, currency, category)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Add", ctx, user, item)
+	ret0, _ := ret[0].(types.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AddExpense indicates an expected call of AddExpense.
-func (mr *MockExpenserMockRecorder) AddExpense(ctx, user, date, amount, currency, category interface{}) *gomock.Call {
+// Add indicates an expected call of Add.
+func (mr *MockRecurrerMockRecorder) Add(ctx, user, item interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExpense", reflect.TypeOf((*MockExpenser)(nil).AddExpense), ctx, user, date, amount, currency, category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRecurrer)(nil).Add), ctx, user, item)
 }
 
-// MockReporter is a mock of Reporter interface.
-type MockReporter struct {
+// List mocks base method.
+func (m *MockRecurrer) List(ctx context.Context, user *types.User) ([]types.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user)
+	ret0, _ := ret[0].([]types.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRecurrerMockRecorder) List(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRecurrer)(nil).List), ctx, user)
+}
+
+// SetPaused mocks base method.
+func (m *MockRecurrer) SetPaused(ctx context.Context, user *types.User, id int64, paused bool) (types.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPaused", ctx, user, id, paused)
+	ret0, _ := ret[0].(types.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPaused indicates an expected call of SetPaused.
+func (mr *MockRecurrerMockRecorder) SetPaused(ctx, user, id, paused interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPaused", reflect.TypeOf((*MockRecurrer)(nil).SetPaused), ctx, user, id, paused)
+}
+
+// MockJournaler is a mock of Journaler interface.
+type MockJournaler struct {
 	ctrl     *gomock.Controller
-	recorder *MockReporterMockRecorder
+	recorder *MockJournalerMockRecorder
 }
 
-// MockReporterMockRecorder is the mock recorder for MockReporter.
-type MockReporterMockRecorder struct {
-	mock *MockReporter
+// MockJournalerMockRecorder is the mock recorder for MockJournaler.
+type MockJournalerMockRecorder struct {
+	mock *MockJournaler
 }
 
-// NewMockReporter creates a new mock instance.
-func NewMockReporter(ctrl *gomock.Controller) *MockReporter {
-	mock := &MockReporter{ctrl: ctrl}
-	mock.recorder = &MockReporterMockRecorder{mock}
+// NewMockJournaler creates a new mock instance.
+func NewMockJournaler(ctrl *gomock.Controller) *MockJournaler {
+	mock := &MockJournaler{ctrl: ctrl}
+	mock.recorder = &MockJournalerMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockReporter) EXPECT() *MockReporterMockRecorder {
+func (m *MockJournaler) EXPECT() *MockJournalerMockRecorder {
 	return m.recorder
 }
 
-// GetReport mocks base method.
-func (m *MockReporter) GetReport(ctx context.Context, user *types.User, from time.Time, currency string) (map[string]int64, error) {
+// Pop mocks base method.
+func (m *MockJournaler) Pop(ctx context.Context, user *types.User, id int64) (types.Operation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReport", ctx, user, from, currency)
-	ret0, _ := ret[0].(map[string]int64)
+	ret := m.ctrl.Call(m, "Pop", ctx, user, id)
+	ret0, _ := ret[0].(types.Operation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetReport indicates an expected call of GetReport.
-func (mr *MockReporterMockRecorder) GetReport(ctx, user, from, currency interface{}) *gomock.Call {
+// Pop indicates an expected call of Pop.
+func (mr *MockJournalerMockRecorder) Pop(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pop", reflect.TypeOf((*MockJournaler)(nil).Pop), ctx, user, id)
+}
+
+// Record mocks base method.
+func (m *MockJournaler) Record(ctx context.Context, user *types.User, op types.Operation) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, user, op)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockJournalerMockRecorder) Record(ctx, user, op interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockReporter)(nil).GetReport), ctx, user, from, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockJournaler)(nil).Record), ctx, user, op)
 }
 
 // MockRater is a mock of Rater interface.
@@ -328,6 +1731,35 @@ func (mr *MocklimiterMockRecorder) Get(ctx, user, category interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*Mocklimiter)(nil).Get), ctx, user, category)
 }
 
+// GetAlerts mocks base method.
+func (m *Mocklimiter) GetAlerts(ctx context.Context, user *types.User) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlerts", ctx, user)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlerts indicates an expected call of GetAlerts.
+func (mr *MocklimiterMockRecorder) GetAlerts(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlerts", reflect.TypeOf((*Mocklimiter)(nil).GetAlerts), ctx, user)
+}
+
+// Increase mocks base method.
+func (m *Mocklimiter) Increase(ctx context.Context, user *types.User, value int64, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increase", ctx, user, value, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Increase indicates an expected call of Increase.
+func (mr *MocklimiterMockRecorder) Increase(ctx, user, value, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increase", reflect.TypeOf((*Mocklimiter)(nil).Increase), ctx, user, value, category)
+}
+
 // List mocks base method.
 func (m *Mocklimiter) List(ctx context.Context, user *types.User) (map[string]types.LimitItem, error) {
 	m.ctrl.T.Helper()
@@ -343,18 +1775,61 @@ func (mr *MocklimiterMockRecorder) List(ctx, user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Mocklimiter)(nil).List), ctx, user)
 }
 
+// Notify mocks base method.
+func (m *Mocklimiter) Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, user, threshold, category)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MocklimiterMockRecorder) Notify(ctx, user, threshold, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*Mocklimiter)(nil).Notify), ctx, user, threshold, category)
+}
+
+// Restore mocks base method.
+func (m *Mocklimiter) Restore(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, user, item, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MocklimiterMockRecorder) Restore(ctx, user, item, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*Mocklimiter)(nil).Restore), ctx, user, item, category)
+}
+
 // Set mocks base method.
-func (m *Mocklimiter) Set(ctx context.Context, user *types.User, limit int64, currency, category string) error {
+func (m *Mocklimiter) Set(ctx context.Context, user *types.User, limit int64, currency string, period types.LimitPeriod, category string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", ctx, user, limit, currency, category)
+	ret := m.ctrl.Call(m, "Set", ctx, user, limit, currency, period, category)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MocklimiterMockRecorder) Set(ctx, user, limit, currency, category interface{}) *gomock.Call {
+func (mr *MocklimiterMockRecorder) Set(ctx, user, limit, currency, period, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*Mocklimiter)(nil).Set), ctx, user, limit, currency, period, category)
+}
+
+// SetAlerts mocks base method.
+func (m *Mocklimiter) SetAlerts(ctx context.Context, user *types.User, thresholds []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlerts", ctx, user, thresholds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAlerts indicates an expected call of SetAlerts.
+func (mr *MocklimiterMockRecorder) SetAlerts(ctx, user, thresholds interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*Mocklimiter)(nil).Set), ctx, user, limit, currency, category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlerts", reflect.TypeOf((*Mocklimiter)(nil).SetAlerts), ctx, user, thresholds)
 }
 
 // Unset mocks base method.
@@ -409,6 +1884,20 @@ func (mr *MockcurrencyManagerMockRecorder) Get(ctx, user interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockcurrencyManager)(nil).Get), ctx, user)
 }
 
+// IsAvailable mocks base method.
+func (m *MockcurrencyManager) IsAvailable(currency string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAvailable", currency)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAvailable indicates an expected call of IsAvailable.
+func (mr *MockcurrencyManagerMockRecorder) IsAvailable(currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAvailable", reflect.TypeOf((*MockcurrencyManager)(nil).IsAvailable), currency)
+}
+
 // ListCurrenciesCodesWithFlags mocks base method.
 func (m *MockcurrencyManager) ListCurrenciesCodesWithFlags() []string {
 	m.ctrl.T.Helper()
@@ -436,3 +1925,77 @@ func (mr *MockcurrencyManagerMockRecorder) Set(ctx, user, currency interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockcurrencyManager)(nil).Set), ctx, user, currency)
 }
+
+// MockunitOfWork is a mock of unitOfWork interface.
+type MockunitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockunitOfWorkMockRecorder
+}
+
+// MockunitOfWorkMockRecorder is the mock recorder for MockunitOfWork.
+type MockunitOfWorkMockRecorder struct {
+	mock *MockunitOfWork
+}
+
+// NewMockunitOfWork creates a new mock instance.
+func NewMockunitOfWork(ctrl *gomock.Controller) *MockunitOfWork {
+	mock := &MockunitOfWork{ctrl: ctrl}
+	mock.recorder = &MockunitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockunitOfWork) EXPECT() *MockunitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockunitOfWork) Do(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockunitOfWorkMockRecorder) Do(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockunitOfWork)(nil).Do), ctx, fn)
+}
+
+// Mockoutbox is a mock of outbox interface.
+type Mockoutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockoutboxMockRecorder
+}
+
+// MockoutboxMockRecorder is the mock recorder for Mockoutbox.
+type MockoutboxMockRecorder struct {
+	mock *Mockoutbox
+}
+
+// NewMockoutbox creates a new mock instance.
+func NewMockoutbox(ctrl *gomock.Controller) *Mockoutbox {
+	mock := &Mockoutbox{ctrl: ctrl}
+	mock.recorder = &MockoutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockoutbox) EXPECT() *MockoutboxMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *Mockoutbox) Add(ctx context.Context, event types.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockoutboxMockRecorder) Add(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*Mockoutbox)(nil).Add), ctx, event)
+}
@@ -66,56 +66,1058 @@ func (mr *MockTelegramUserStorageMockRecorder) FetchByID(ctx, tgUserID interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByID", reflect.TypeOf((*MockTelegramUserStorage)(nil).FetchByID), ctx, tgUserID)
 }
 
+// FetchLanguage mocks base method.
+func (m *MockTelegramUserStorage) FetchLanguage(ctx context.Context, tgUserID int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchLanguage", ctx, tgUserID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchLanguage indicates an expected call of FetchLanguage.
+func (mr *MockTelegramUserStorageMockRecorder) FetchLanguage(ctx, tgUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchLanguage", reflect.TypeOf((*MockTelegramUserStorage)(nil).FetchLanguage), ctx, tgUserID)
+}
+
+// FetchTelegramID mocks base method.
+func (m *MockTelegramUserStorage) FetchTelegramID(ctx context.Context, user *types.User) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTelegramID", ctx, user)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTelegramID indicates an expected call of FetchTelegramID.
+func (mr *MockTelegramUserStorageMockRecorder) FetchTelegramID(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTelegramID", reflect.TypeOf((*MockTelegramUserStorage)(nil).FetchTelegramID), ctx, user)
+}
+
+// SetLanguage mocks base method.
+func (m *MockTelegramUserStorage) SetLanguage(ctx context.Context, tgUserID int64, lang string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLanguage", ctx, tgUserID, lang)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLanguage indicates an expected call of SetLanguage.
+func (mr *MockTelegramUserStorageMockRecorder) SetLanguage(ctx, tgUserID, lang interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLanguage", reflect.TypeOf((*MockTelegramUserStorage)(nil).SetLanguage), ctx, tgUserID, lang)
+}
+
 // MockExpenseStorage is a mock of ExpenseStorage interface.
 type MockExpenseStorage struct {
 	ctrl     *gomock.Controller
-	recorder *MockExpenseStorageMockRecorder
+	recorder *MockExpenseStorageMockRecorder
+}
+
+// MockExpenseStorageMockRecorder is the mock recorder for MockExpenseStorage.
+type MockExpenseStorageMockRecorder struct {
+	mock *MockExpenseStorage
+}
+
+// NewMockExpenseStorage creates a new mock instance.
+func NewMockExpenseStorage(ctrl *gomock.Controller) *MockExpenseStorage {
+	mock := &MockExpenseStorage{ctrl: ctrl}
+	mock.recorder = &MockExpenseStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenseStorage) EXPECT() *MockExpenseStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockExpenseStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, user, item, category)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockExpenseStorageMockRecorder) Add(ctx, user, item, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockExpenseStorage)(nil).Add), ctx, user, item, category)
+}
+
+// AddImported mocks base method.
+func (m *MockExpenseStorage) AddImported(ctx context.Context, user *types.User, items []types.ImportedExpense) ([]types.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddImported", ctx, user, items)
+	ret0, _ := ret[0].([]types.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddImported indicates an expected call of AddImported.
+func (mr *MockExpenseStorageMockRecorder) AddImported(ctx, user, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddImported", reflect.TypeOf((*MockExpenseStorage)(nil).AddImported), ctx, user, items)
+}
+
+// Delete mocks base method.
+func (m *MockExpenseStorage) Delete(ctx context.Context, user *types.User, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, user, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockExpenseStorageMockRecorder) Delete(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockExpenseStorage)(nil).Delete), ctx, user, id)
+}
+
+// Find mocks base method.
+func (m *MockExpenseStorage) Find(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, user, filter, offset, limit)
+	ret0, _ := ret[0].([]types.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockExpenseStorageMockRecorder) Find(ctx, user, filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockExpenseStorage)(nil).Find), ctx, user, filter, offset, limit)
+}
+
+// Get mocks base method.
+func (m *MockExpenseStorage) Get(ctx context.Context, user *types.User, id int64) (types.Expense, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, user, id)
+	ret0, _ := ret[0].(types.Expense)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExpenseStorageMockRecorder) Get(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExpenseStorage)(nil).Get), ctx, user, id)
+}
+
+// List mocks base method.
+func (m *MockExpenseStorage) List(ctx context.Context, user *types.User, from, to time.Time) (map[string][]types.ExpenseItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user, from, to)
+	ret0, _ := ret[0].(map[string][]types.ExpenseItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockExpenseStorageMockRecorder) List(ctx, user, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockExpenseStorage)(nil).List), ctx, user, from, to)
+}
+
+// ListLast mocks base method.
+func (m *MockExpenseStorage) ListLast(ctx context.Context, user *types.User, count int) ([]types.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLast", ctx, user, count)
+	ret0, _ := ret[0].([]types.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLast indicates an expected call of ListLast.
+func (mr *MockExpenseStorageMockRecorder) ListLast(ctx, user, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLast", reflect.TypeOf((*MockExpenseStorage)(nil).ListLast), ctx, user, count)
+}
+
+// SumByDate mocks base method.
+func (m *MockExpenseStorage) SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumByDate", ctx, user, from, to)
+	ret0, _ := ret[0].([]types.ExpenseTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumByDate indicates an expected call of SumByDate.
+func (mr *MockExpenseStorageMockRecorder) SumByDate(ctx, user, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByDate", reflect.TypeOf((*MockExpenseStorage)(nil).SumByDate), ctx, user, from, to)
+}
+
+// Update mocks base method.
+func (m *MockExpenseStorage) Update(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, user, item, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockExpenseStorageMockRecorder) Update(ctx, user, item, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockExpenseStorage)(nil).Update), ctx, user, item, category)
+}
+
+// Walk mocks base method.
+func (m *MockExpenseStorage) Walk(ctx context.Context, user *types.User, from, to time.Time, fn func(types.Expense) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Walk", ctx, user, from, to, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Walk indicates an expected call of Walk.
+func (mr *MockExpenseStorageMockRecorder) Walk(ctx, user, from, to, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Walk", reflect.TypeOf((*MockExpenseStorage)(nil).Walk), ctx, user, from, to, fn)
+}
+
+// MockCategoryStorage is a mock of CategoryStorage interface.
+type MockCategoryStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryStorageMockRecorder
+}
+
+// MockCategoryStorageMockRecorder is the mock recorder for MockCategoryStorage.
+type MockCategoryStorageMockRecorder struct {
+	mock *MockCategoryStorage
+}
+
+// NewMockCategoryStorage creates a new mock instance.
+func NewMockCategoryStorage(ctrl *gomock.Controller) *MockCategoryStorage {
+	mock := &MockCategoryStorage{ctrl: ctrl}
+	mock.recorder = &MockCategoryStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryStorage) EXPECT() *MockCategoryStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteAlias mocks base method.
+func (m *MockCategoryStorage) DeleteAlias(ctx context.Context, user *types.User, alias string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", ctx, user, alias)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockCategoryStorageMockRecorder) DeleteAlias(ctx, user, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockCategoryStorage)(nil).DeleteAlias), ctx, user, alias)
+}
+
+// List mocks base method.
+func (m *MockCategoryStorage) List(ctx context.Context, user *types.User) ([]types.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user)
+	ret0, _ := ret[0].([]types.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCategoryStorageMockRecorder) List(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCategoryStorage)(nil).List), ctx, user)
+}
+
+// Rename mocks base method.
+func (m *MockCategoryStorage) Rename(ctx context.Context, user *types.User, from, to string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, user, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockCategoryStorageMockRecorder) Rename(ctx, user, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockCategoryStorage)(nil).Rename), ctx, user, from, to)
+}
+
+// Resolve mocks base method.
+func (m *MockCategoryStorage) Resolve(ctx context.Context, user *types.User, alias string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, user, alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockCategoryStorageMockRecorder) Resolve(ctx, user, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockCategoryStorage)(nil).Resolve), ctx, user, alias)
+}
+
+// SetAlias mocks base method.
+func (m *MockCategoryStorage) SetAlias(ctx context.Context, user *types.User, alias, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlias", ctx, user, alias, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAlias indicates an expected call of SetAlias.
+func (mr *MockCategoryStorageMockRecorder) SetAlias(ctx, user, alias, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlias", reflect.TypeOf((*MockCategoryStorage)(nil).SetAlias), ctx, user, alias, category)
+}
+
+// MockAttachmentStorage is a mock of AttachmentStorage interface.
+type MockAttachmentStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentStorageMockRecorder
+}
+
+// MockAttachmentStorageMockRecorder is the mock recorder for MockAttachmentStorage.
+type MockAttachmentStorageMockRecorder struct {
+	mock *MockAttachmentStorage
+}
+
+// NewMockAttachmentStorage creates a new mock instance.
+func NewMockAttachmentStorage(ctrl *gomock.Controller) *MockAttachmentStorage {
+	mock := &MockAttachmentStorage{ctrl: ctrl}
+	mock.recorder = &MockAttachmentStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentStorage) EXPECT() *MockAttachmentStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockAttachmentStorage) DeleteExpired(ctx context.Context, before time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockAttachmentStorageMockRecorder) DeleteExpired(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockAttachmentStorage)(nil).DeleteExpired), ctx, before)
+}
+
+// Get mocks base method.
+func (m *MockAttachmentStorage) Get(ctx context.Context, user *types.User, expenseID int64) (types.Attachment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, user, expenseID)
+	ret0, _ := ret[0].(types.Attachment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAttachmentStorageMockRecorder) Get(ctx, user, expenseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAttachmentStorage)(nil).Get), ctx, user, expenseID)
+}
+
+// ListAttached mocks base method.
+func (m *MockAttachmentStorage) ListAttached(ctx context.Context, user *types.User, ids []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttached", ctx, user, ids)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttached indicates an expected call of ListAttached.
+func (mr *MockAttachmentStorageMockRecorder) ListAttached(ctx, user, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttached", reflect.TypeOf((*MockAttachmentStorage)(nil).ListAttached), ctx, user, ids)
+}
+
+// Set mocks base method.
+func (m *MockAttachmentStorage) Set(ctx context.Context, user *types.User, item types.Attachment) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, user, item)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockAttachmentStorageMockRecorder) Set(ctx, user, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockAttachmentStorage)(nil).Set), ctx, user, item)
+}
+
+// MockJournalStorage is a mock of JournalStorage interface.
+type MockJournalStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockJournalStorageMockRecorder
+}
+
+// MockJournalStorageMockRecorder is the mock recorder for MockJournalStorage.
+type MockJournalStorageMockRecorder struct {
+	mock *MockJournalStorage
+}
+
+// NewMockJournalStorage creates a new mock instance.
+func NewMockJournalStorage(ctrl *gomock.Controller) *MockJournalStorage {
+	mock := &MockJournalStorage{ctrl: ctrl}
+	mock.recorder = &MockJournalStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJournalStorage) EXPECT() *MockJournalStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockJournalStorage) Add(ctx context.Context, user *types.User, op types.Operation) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, user, op)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockJournalStorageMockRecorder) Add(ctx, user, op interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockJournalStorage)(nil).Add), ctx, user, op)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockJournalStorage) DeleteBefore(ctx context.Context, user *types.User, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, user, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockJournalStorageMockRecorder) DeleteBefore(ctx, user, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockJournalStorage)(nil).DeleteBefore), ctx, user, before)
+}
+
+// Pop mocks base method.
+func (m *MockJournalStorage) Pop(ctx context.Context, user *types.User, id int64, since time.Time) (types.Operation, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pop", ctx, user, id, since)
+	ret0, _ := ret[0].(types.Operation)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Pop indicates an expected call of Pop.
+func (mr *MockJournalStorageMockRecorder) Pop(ctx, user, id, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pop", reflect.TypeOf((*MockJournalStorage)(nil).Pop), ctx, user, id, since)
+}
+
+// MockBlobStorage is a mock of BlobStorage interface.
+type MockBlobStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStorageMockRecorder
+}
+
+// MockBlobStorageMockRecorder is the mock recorder for MockBlobStorage.
+type MockBlobStorageMockRecorder struct {
+	mock *MockBlobStorage
+}
+
+// NewMockBlobStorage creates a new mock instance.
+func NewMockBlobStorage(ctrl *gomock.Controller) *MockBlobStorage {
+	mock := &MockBlobStorage{ctrl: ctrl}
+	mock.recorder = &MockBlobStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStorage) EXPECT() *MockBlobStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobStorage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobStorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobStorage)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockBlobStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBlobStorageMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBlobStorage)(nil).Get), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockBlobStorage) Put(ctx context.Context, key string, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobStorageMockRecorder) Put(ctx, key, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlobStorage)(nil).Put), ctx, key, data)
+}
+
+// MockDialogStorage is a mock of DialogStorage interface.
+type MockDialogStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockDialogStorageMockRecorder
+}
+
+// MockDialogStorageMockRecorder is the mock recorder for MockDialogStorage.
+type MockDialogStorageMockRecorder struct {
+	mock *MockDialogStorage
+}
+
+// NewMockDialogStorage creates a new mock instance.
+func NewMockDialogStorage(ctrl *gomock.Controller) *MockDialogStorage {
+	mock := &MockDialogStorage{ctrl: ctrl}
+	mock.recorder = &MockDialogStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDialogStorage) EXPECT() *MockDialogStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockDialogStorage) Delete(ctx context.Context, user *types.User, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, user, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDialogStorageMockRecorder) Delete(ctx, user, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDialogStorage)(nil).Delete), ctx, user, key)
+}
+
+// Get mocks base method.
+func (m *MockDialogStorage) Get(ctx context.Context, user *types.User, key string) ([]byte, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, user, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDialogStorageMockRecorder) Get(ctx, user, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDialogStorage)(nil).Get), ctx, user, key)
+}
+
+// Set mocks base method.
+func (m *MockDialogStorage) Set(ctx context.Context, user *types.User, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, user, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockDialogStorageMockRecorder) Set(ctx, user, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockDialogStorage)(nil).Set), ctx, user, key, value, ttl)
+}
+
+// MockAccountStorage is a mock of AccountStorage interface.
+type MockAccountStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountStorageMockRecorder
+}
+
+// MockAccountStorageMockRecorder is the mock recorder for MockAccountStorage.
+type MockAccountStorageMockRecorder struct {
+	mock *MockAccountStorage
+}
+
+// NewMockAccountStorage creates a new mock instance.
+func NewMockAccountStorage(ctrl *gomock.Controller) *MockAccountStorage {
+	mock := &MockAccountStorage{ctrl: ctrl}
+	mock.recorder = &MockAccountStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountStorage) EXPECT() *MockAccountStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockAccountStorage) Add(ctx context.Context, user *types.User, account types.Account) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, user, account)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockAccountStorageMockRecorder) Add(ctx, user, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAccountStorage)(nil).Add), ctx, user, account)
+}
+
+// AddTransfer mocks base method.
+func (m *MockAccountStorage) AddTransfer(ctx context.Context, user *types.User, transfer types.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransfer", ctx, user, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTransfer indicates an expected call of AddTransfer.
+func (mr *MockAccountStorageMockRecorder) AddTransfer(ctx, user, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransfer", reflect.TypeOf((*MockAccountStorage)(nil).AddTransfer), ctx, user, transfer)
+}
+
+// GetByName mocks base method.
+func (m *MockAccountStorage) GetByName(ctx context.Context, user *types.User, name string) (types.Account, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", ctx, user, name)
+	ret0, _ := ret[0].(types.Account)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockAccountStorageMockRecorder) GetByName(ctx, user, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockAccountStorage)(nil).GetByName), ctx, user, name)
+}
+
+// List mocks base method.
+func (m *MockAccountStorage) List(ctx context.Context, user *types.User) ([]types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user)
+	ret0, _ := ret[0].([]types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccountStorageMockRecorder) List(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccountStorage)(nil).List), ctx, user)
+}
+
+// Turnover mocks base method.
+func (m *MockAccountStorage) Turnover(ctx context.Context, user *types.User) ([]types.AccountTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Turnover", ctx, user)
+	ret0, _ := ret[0].([]types.AccountTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Turnover indicates an expected call of Turnover.
+func (mr *MockAccountStorageMockRecorder) Turnover(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Turnover", reflect.TypeOf((*MockAccountStorage)(nil).Turnover), ctx, user)
+}
+
+// MockIncomeStorage is a mock of IncomeStorage interface.
+type MockIncomeStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncomeStorageMockRecorder
+}
+
+// MockIncomeStorageMockRecorder is the mock recorder for MockIncomeStorage.
+type MockIncomeStorageMockRecorder struct {
+	mock *MockIncomeStorage
+}
+
+// NewMockIncomeStorage creates a new mock instance.
+func NewMockIncomeStorage(ctrl *gomock.Controller) *MockIncomeStorage {
+	mock := &MockIncomeStorage{ctrl: ctrl}
+	mock.recorder = &MockIncomeStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncomeStorage) EXPECT() *MockIncomeStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockIncomeStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, user, item, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockIncomeStorageMockRecorder) Add(ctx, user, item, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockIncomeStorage)(nil).Add), ctx, user, item, category)
+}
+
+// ListLast mocks base method.
+func (m *MockIncomeStorage) ListLast(ctx context.Context, user *types.User, count int) ([]types.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLast", ctx, user, count)
+	ret0, _ := ret[0].([]types.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLast indicates an expected call of ListLast.
+func (mr *MockIncomeStorageMockRecorder) ListLast(ctx, user, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLast", reflect.TypeOf((*MockIncomeStorage)(nil).ListLast), ctx, user, count)
+}
+
+// SumByDate mocks base method.
+func (m *MockIncomeStorage) SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumByDate", ctx, user, from, to)
+	ret0, _ := ret[0].([]types.ExpenseTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumByDate indicates an expected call of SumByDate.
+func (mr *MockIncomeStorageMockRecorder) SumByDate(ctx, user, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByDate", reflect.TypeOf((*MockIncomeStorage)(nil).SumByDate), ctx, user, from, to)
+}
+
+// MockGoalStorage is a mock of GoalStorage interface.
+type MockGoalStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockGoalStorageMockRecorder
+}
+
+// MockGoalStorageMockRecorder is the mock recorder for MockGoalStorage.
+type MockGoalStorageMockRecorder struct {
+	mock *MockGoalStorage
+}
+
+// NewMockGoalStorage creates a new mock instance.
+func NewMockGoalStorage(ctrl *gomock.Controller) *MockGoalStorage {
+	mock := &MockGoalStorage{ctrl: ctrl}
+	mock.recorder = &MockGoalStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGoalStorage) EXPECT() *MockGoalStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockGoalStorage) Add(ctx context.Context, user *types.User, goal types.Goal) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, user, goal)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockGoalStorageMockRecorder) Add(ctx, user, goal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockGoalStorage)(nil).Add), ctx, user, goal)
+}
+
+// AddContribution mocks base method.
+func (m *MockGoalStorage) AddContribution(ctx context.Context, user *types.User, contribution types.GoalContribution) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddContribution", ctx, user, contribution)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddContribution indicates an expected call of AddContribution.
+func (mr *MockGoalStorageMockRecorder) AddContribution(ctx, user, contribution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddContribution", reflect.TypeOf((*MockGoalStorage)(nil).AddContribution), ctx, user, contribution)
+}
+
+// Delete mocks base method.
+func (m *MockGoalStorage) Delete(ctx context.Context, user *types.User, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, user, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockGoalStorageMockRecorder) Delete(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGoalStorage)(nil).Delete), ctx, user, id)
+}
+
+// GetByName mocks base method.
+func (m *MockGoalStorage) GetByName(ctx context.Context, user *types.User, name string) (types.Goal, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", ctx, user, name)
+	ret0, _ := ret[0].(types.Goal)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockGoalStorageMockRecorder) GetByName(ctx, user, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockGoalStorage)(nil).GetByName), ctx, user, name)
+}
+
+// List mocks base method.
+func (m *MockGoalStorage) List(ctx context.Context, user *types.User) ([]types.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user)
+	ret0, _ := ret[0].([]types.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockGoalStorageMockRecorder) List(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockGoalStorage)(nil).List), ctx, user)
+}
+
+// Totals mocks base method.
+func (m *MockGoalStorage) Totals(ctx context.Context, user *types.User) ([]types.GoalTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Totals", ctx, user)
+	ret0, _ := ret[0].([]types.GoalTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Totals indicates an expected call of Totals.
+func (mr *MockGoalStorageMockRecorder) Totals(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Totals", reflect.TypeOf((*MockGoalStorage)(nil).Totals), ctx, user)
+}
+
+// MockGroupStorage is a mock of GroupStorage interface.
+type MockGroupStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockGroupStorageMockRecorder
+}
+
+// MockGroupStorageMockRecorder is the mock recorder for MockGroupStorage.
+type MockGroupStorageMockRecorder struct {
+	mock *MockGroupStorage
+}
+
+// NewMockGroupStorage creates a new mock instance.
+func NewMockGroupStorage(ctrl *gomock.Controller) *MockGroupStorage {
+	mock := &MockGroupStorage{ctrl: ctrl}
+	mock.recorder = &MockGroupStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGroupStorage) EXPECT() *MockGroupStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockGroupStorage) Add(ctx context.Context, code string, owner types.GroupMember) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, code, owner)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockGroupStorageMockRecorder) Add(ctx, code, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockGroupStorage)(nil).Add), ctx, code, owner)
+}
+
+// AddMember mocks base method.
+func (m *MockGroupStorage) AddMember(ctx context.Context, group int64, member types.GroupMember) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, group, member)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockGroupStorageMockRecorder) AddMember(ctx, group, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockGroupStorage)(nil).AddMember), ctx, group, member)
+}
+
+// Delete mocks base method.
+func (m *MockGroupStorage) Delete(ctx context.Context, group int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockGroupStorageMockRecorder) Delete(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGroupStorage)(nil).Delete), ctx, group)
+}
+
+// GetByCode mocks base method.
+func (m *MockGroupStorage) GetByCode(ctx context.Context, code string) (types.Group, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCode", ctx, code)
+	ret0, _ := ret[0].(types.Group)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByCode indicates an expected call of GetByCode.
+func (mr *MockGroupStorageMockRecorder) GetByCode(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCode", reflect.TypeOf((*MockGroupStorage)(nil).GetByCode), ctx, code)
+}
+
+// GetByUser mocks base method.
+func (m *MockGroupStorage) GetByUser(ctx context.Context, user *types.User) (types.Group, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, user)
+	ret0, _ := ret[0].(types.Group)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockGroupStorageMockRecorder) GetByUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockGroupStorage)(nil).GetByUser), ctx, user)
+}
+
+// RemoveMember mocks base method.
+func (m *MockGroupStorage) RemoveMember(ctx context.Context, user *types.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockGroupStorageMockRecorder) RemoveMember(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockGroupStorage)(nil).RemoveMember), ctx, user)
+}
+
+// MockDebtStorage is a mock of DebtStorage interface.
+type MockDebtStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockDebtStorageMockRecorder
 }
 
-// MockExpenseStorageMockRecorder is the mock recorder for MockExpenseStorage.
-type MockExpenseStorageMockRecorder struct {
-	mock *MockExpenseStorage
+// MockDebtStorageMockRecorder is the mock recorder for MockDebtStorage.
+type MockDebtStorageMockRecorder struct {
+	mock *MockDebtStorage
 }
 
-// NewMockExpenseStorage creates a new mock instance.
-func NewMockExpenseStorage(ctrl *gomock.Controller) *MockExpenseStorage {
-	mock := &MockExpenseStorage{ctrl: ctrl}
-	mock.recorder = &MockExpenseStorageMockRecorder{mock}
+// NewMockDebtStorage creates a new mock instance.
+func NewMockDebtStorage(ctrl *gomock.Controller) *MockDebtStorage {
+	mock := &MockDebtStorage{ctrl: ctrl}
+	mock.recorder = &MockDebtStorageMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockExpenseStorage) EXPECT() *MockExpenseStorageMockRecorder {
+func (m *MockDebtStorage) EXPECT() *MockDebtStorageMockRecorder {
 	return m.recorder
 }
 
 // Add mocks base method.
-func (m *MockExpenseStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+func (m *MockDebtStorage) Add(ctx context.Context, debt types.Debt) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", ctx, user, item, category)
+	ret := m.ctrl.Call(m, "Add", ctx, debt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockExpenseStorageMockRecorder) Add(ctx, user, item, category interface{}) *gomock.Call {
+func (mr *MockDebtStorageMockRecorder) Add(ctx, debt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockExpenseStorage)(nil).Add), ctx, user, item, category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockDebtStorage)(nil).Add), ctx, debt)
 }
 
-// List mocks base method.
-func (m *MockExpenseStorage) List(ctx context.Context, user *types.User, from time.Time) (map[string][]types.ExpenseItem, error) {
+// Balances mocks base method.
+func (m *MockDebtStorage) Balances(ctx context.Context, user *types.User) ([]types.DebtBalance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, user, from)
-	ret0, _ := ret[0].(map[string][]types.ExpenseItem)
+	ret := m.ctrl.Call(m, "Balances", ctx, user)
+	ret0, _ := ret[0].([]types.DebtBalance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List.
-func (mr *MockExpenseStorageMockRecorder) List(ctx, user, from interface{}) *gomock.Call {
+// Balances indicates an expected call of Balances.
+func (mr *MockDebtStorageMockRecorder) Balances(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockExpenseStorage)(nil).List), ctx, user, from)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balances", reflect.TypeOf((*MockDebtStorage)(nil).Balances), ctx, user)
+}
+
+// Settle mocks base method.
+func (m *MockDebtStorage) Settle(ctx context.Context, user, counterparty *types.User, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Settle", ctx, user, counterparty, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Settle indicates an expected call of Settle.
+func (mr *MockDebtStorageMockRecorder) Settle(ctx, user, counterparty, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Settle", reflect.TypeOf((*MockDebtStorage)(nil).Settle), ctx, user, counterparty, currency)
 }
 
 // MockExpenseLimitStorage is a mock of ExpenseLimitStorage interface.
@@ -172,6 +1174,36 @@ func (mr *MockExpenseLimitStorageMockRecorder) Get(ctx, user, category interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Get), ctx, user, category)
 }
 
+// GetAlerts mocks base method.
+func (m *MockExpenseLimitStorage) GetAlerts(ctx context.Context, user *types.User) ([]int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlerts", ctx, user)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAlerts indicates an expected call of GetAlerts.
+func (mr *MockExpenseLimitStorageMockRecorder) GetAlerts(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlerts", reflect.TypeOf((*MockExpenseLimitStorage)(nil).GetAlerts), ctx, user)
+}
+
+// Increase mocks base method.
+func (m *MockExpenseLimitStorage) Increase(ctx context.Context, user *types.User, value int64, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increase", ctx, user, value, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Increase indicates an expected call of Increase.
+func (mr *MockExpenseLimitStorageMockRecorder) Increase(ctx, user, value, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increase", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Increase), ctx, user, value, category)
+}
+
 // List mocks base method.
 func (m *MockExpenseLimitStorage) List(ctx context.Context, user *types.User) (map[string]types.LimitItem, bool, error) {
 	m.ctrl.T.Helper()
@@ -188,18 +1220,75 @@ func (mr *MockExpenseLimitStorageMockRecorder) List(ctx, user interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockExpenseLimitStorage)(nil).List), ctx, user)
 }
 
+// Notify mocks base method.
+func (m *MockExpenseLimitStorage) Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, user, threshold, category)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockExpenseLimitStorageMockRecorder) Notify(ctx, user, threshold, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Notify), ctx, user, threshold, category)
+}
+
+// Refresh mocks base method.
+func (m *MockExpenseLimitStorage) Refresh(ctx context.Context, user *types.User, remains int64, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, user, remains, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockExpenseLimitStorageMockRecorder) Refresh(ctx, user, remains, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Refresh), ctx, user, remains, category)
+}
+
+// Renew mocks base method.
+func (m *MockExpenseLimitStorage) Renew(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, user, item, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockExpenseLimitStorageMockRecorder) Renew(ctx, user, item, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Renew), ctx, user, item, category)
+}
+
 // Set mocks base method.
-func (m *MockExpenseLimitStorage) Set(ctx context.Context, user *types.User, total int64, currency, category string) error {
+func (m *MockExpenseLimitStorage) Set(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", ctx, user, total, currency, category)
+	ret := m.ctrl.Call(m, "Set", ctx, user, item, category)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockExpenseLimitStorageMockRecorder) Set(ctx, user, total, currency, category interface{}) *gomock.Call {
+func (mr *MockExpenseLimitStorageMockRecorder) Set(ctx, user, item, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Set), ctx, user, item, category)
+}
+
+// SetAlerts mocks base method.
+func (m *MockExpenseLimitStorage) SetAlerts(ctx context.Context, user *types.User, thresholds []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlerts", ctx, user, thresholds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAlerts indicates an expected call of SetAlerts.
+func (mr *MockExpenseLimitStorageMockRecorder) SetAlerts(ctx, user, thresholds interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Set), ctx, user, total, currency, category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlerts", reflect.TypeOf((*MockExpenseLimitStorage)(nil).SetAlerts), ctx, user, thresholds)
 }
 
 // Unset mocks base method.
@@ -216,6 +1305,288 @@ func (mr *MockExpenseLimitStorageMockRecorder) Unset(ctx, user, category interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unset", reflect.TypeOf((*MockExpenseLimitStorage)(nil).Unset), ctx, user, category)
 }
 
+// MockMerchantRuleStorage is a mock of MerchantRuleStorage interface.
+type MockMerchantRuleStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchantRuleStorageMockRecorder
+}
+
+// MockMerchantRuleStorageMockRecorder is the mock recorder for MockMerchantRuleStorage.
+type MockMerchantRuleStorageMockRecorder struct {
+	mock *MockMerchantRuleStorage
+}
+
+// NewMockMerchantRuleStorage creates a new mock instance.
+func NewMockMerchantRuleStorage(ctrl *gomock.Controller) *MockMerchantRuleStorage {
+	mock := &MockMerchantRuleStorage{ctrl: ctrl}
+	mock.recorder = &MockMerchantRuleStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchantRuleStorage) EXPECT() *MockMerchantRuleStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockMerchantRuleStorage) Delete(ctx context.Context, user *types.User, pattern string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, user, pattern)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMerchantRuleStorageMockRecorder) Delete(ctx, user, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMerchantRuleStorage)(nil).Delete), ctx, user, pattern)
+}
+
+// List mocks base method.
+func (m *MockMerchantRuleStorage) List(ctx context.Context, user *types.User) ([]types.MerchantRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user)
+	ret0, _ := ret[0].([]types.MerchantRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockMerchantRuleStorageMockRecorder) List(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockMerchantRuleStorage)(nil).List), ctx, user)
+}
+
+// Set mocks base method.
+func (m *MockMerchantRuleStorage) Set(ctx context.Context, user *types.User, rule types.MerchantRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, user, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockMerchantRuleStorageMockRecorder) Set(ctx, user, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockMerchantRuleStorage)(nil).Set), ctx, user, rule)
+}
+
+// MockRecurringExpenseStorage is a mock of RecurringExpenseStorage interface.
+type MockRecurringExpenseStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringExpenseStorageMockRecorder
+}
+
+// MockRecurringExpenseStorageMockRecorder is the mock recorder for MockRecurringExpenseStorage.
+type MockRecurringExpenseStorageMockRecorder struct {
+	mock *MockRecurringExpenseStorage
+}
+
+// NewMockRecurringExpenseStorage creates a new mock instance.
+func NewMockRecurringExpenseStorage(ctrl *gomock.Controller) *MockRecurringExpenseStorage {
+	mock := &MockRecurringExpenseStorage{ctrl: ctrl}
+	mock.recorder = &MockRecurringExpenseStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringExpenseStorage) EXPECT() *MockRecurringExpenseStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRecurringExpenseStorage) Add(ctx context.Context, user *types.User, item types.RecurringExpense) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, user, item)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRecurringExpenseStorageMockRecorder) Add(ctx, user, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRecurringExpenseStorage)(nil).Add), ctx, user, item)
+}
+
+// Get mocks base method.
+func (m *MockRecurringExpenseStorage) Get(ctx context.Context, user *types.User, id int64) (types.RecurringExpense, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, user, id)
+	ret0, _ := ret[0].(types.RecurringExpense)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRecurringExpenseStorageMockRecorder) Get(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRecurringExpenseStorage)(nil).Get), ctx, user, id)
+}
+
+// List mocks base method.
+func (m *MockRecurringExpenseStorage) List(ctx context.Context, user *types.User) ([]types.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user)
+	ret0, _ := ret[0].([]types.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRecurringExpenseStorageMockRecorder) List(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRecurringExpenseStorage)(nil).List), ctx, user)
+}
+
+// LockDue mocks base method.
+func (m *MockRecurringExpenseStorage) LockDue(ctx context.Context, date time.Time, exclude []int64) (*types.User, types.RecurringExpense, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDue", ctx, date, exclude)
+	ret0, _ := ret[0].(*types.User)
+	ret1, _ := ret[1].(types.RecurringExpense)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// LockDue indicates an expected call of LockDue.
+func (mr *MockRecurringExpenseStorageMockRecorder) LockDue(ctx, date, exclude interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDue", reflect.TypeOf((*MockRecurringExpenseStorage)(nil).LockDue), ctx, date, exclude)
+}
+
+// Update mocks base method.
+func (m *MockRecurringExpenseStorage) Update(ctx context.Context, user *types.User, item types.RecurringExpense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, user, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecurringExpenseStorageMockRecorder) Update(ctx, user, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecurringExpenseStorage)(nil).Update), ctx, user, item)
+}
+
+// MockOutboxStorage is a mock of OutboxStorage interface.
+type MockOutboxStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxStorageMockRecorder
+}
+
+// MockOutboxStorageMockRecorder is the mock recorder for MockOutboxStorage.
+type MockOutboxStorageMockRecorder struct {
+	mock *MockOutboxStorage
+}
+
+// NewMockOutboxStorage creates a new mock instance.
+func NewMockOutboxStorage(ctrl *gomock.Controller) *MockOutboxStorage {
+	mock := &MockOutboxStorage{ctrl: ctrl}
+	mock.recorder = &MockOutboxStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxStorage) EXPECT() *MockOutboxStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockOutboxStorage) Add(ctx context.Context, event types.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockOutboxStorageMockRecorder) Add(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOutboxStorage)(nil).Add), ctx, event)
+}
+
+// Delete mocks base method.
+func (m *MockOutboxStorage) Delete(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOutboxStorageMockRecorder) Delete(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOutboxStorage)(nil).Delete), ctx, ids)
+}
+
+// LockPending mocks base method.
+func (m *MockOutboxStorage) LockPending(ctx context.Context, limit int) ([]types.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPending", ctx, limit)
+	ret0, _ := ret[0].([]types.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPending indicates an expected call of LockPending.
+func (mr *MockOutboxStorageMockRecorder) LockPending(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPending", reflect.TypeOf((*MockOutboxStorage)(nil).LockPending), ctx, limit)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// AfterCommit mocks base method.
+func (m *MockUnitOfWork) AfterCommit(ctx context.Context, fn func(context.Context)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterCommit", ctx, fn)
+}
+
+// AfterCommit indicates an expected call of AfterCommit.
+func (mr *MockUnitOfWorkMockRecorder) AfterCommit(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterCommit", reflect.TypeOf((*MockUnitOfWork)(nil).AfterCommit), ctx, fn)
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkMockRecorder) Do(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWork)(nil).Do), ctx, fn)
+}
+
 // MockCurrencyStorage is a mock of CurrencyStorage interface.
 type MockCurrencyStorage struct {
 	ctrl     *gomock.Controller
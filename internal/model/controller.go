@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -13,26 +14,99 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	_lastExpensesCount     = 10
+	_foundExpensesPageSize = 5
+
+	_eventExpenseAdded   = "expense.added"
+	_eventExpenseUpdated = "expense.updated"
+	_eventExpenseDeleted = "expense.deleted"
+)
+
 var (
-	ErrNotReady = errors.New("not ready")
+	ErrNotReady      = errors.New("not ready")
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrForbidden     = errors.New("forbidden")
+
+	ErrInvalidCategory = errors.New("invalid category")
+	ErrTooLarge        = errors.New("file is too large")
+
+	ErrUnknownStatement = errors.New("unknown statement format")
+	ErrInvalidStatement = errors.New("invalid statement")
+
+	errDuplicateReceipt = errors.New("duplicate receipt")
+)
+
+// InputError is the error of the data given by the user. Its text is meant for logs,
+// frontends explain the error to the user in their language.
+type InputError string
+
+func (e InputError) Error() string {
+	return string(e)
+}
+
+const (
+	ErrNonPositiveAmount InputError = "amount must be positive"
+	ErrFutureDate        InputError = "date is in the future"
+	ErrEmptyName         InputError = "name is empty"
+	ErrSameAccount       InputError = "transfer within the same account"
+	ErrUnknownCurrency   InputError = "unknown currency"
+	ErrNoExchangeRate    InputError = "no exchange rate for the date"
+	ErrWrongRecurrence   InputError = "unknown frequency or non-positive interval"
 )
 
 type controller struct {
 	expenser        Expenser
+	categorizer     Categorizer
+	attacher        Attacher
+	incomer         Incomer
+	accounter       Accounter
+	saver           Saver
+	grouper         Grouper
+	debtor          Debtor
 	reporter        Reporter
+	exporter        Exporter
+	importer        Importer
+	recurrer        Recurrer
+	journaler       Journaler
 	limiter         limiter
 	currencyManager currencyManager
 	rater           Rater
+	unitOfWork      unitOfWork
+	outbox          outbox
 	logger          *zap.Logger
 }
 
-func NewController(e Expenser, rep Reporter, lm limiter, cm currencyManager, rater Rater, l *zap.Logger) *controller {
+type expenseEvent struct {
+	User     int64     `json:"user_id"`
+	ID       int64     `json:"id,omitempty"`
+	Date     time.Time `json:"date"`
+	Amount   int64     `json:"amount"`
+	Currency string    `json:"currency"`
+	Category string    `json:"category"`
+}
+
+func NewController(e Expenser, cat Categorizer, att Attacher, inc Incomer, acc Accounter, sav Saver, grp Grouper, debt Debtor, rep Reporter, exp Exporter, imp Importer, rec Recurrer, jr Journaler, lm limiter, cm currencyManager, rater Rater, uow unitOfWork, ob outbox, l *zap.Logger) *controller {
 	return &controller{
 		expenser:        e,
+		categorizer:     cat,
+		attacher:        att,
+		incomer:         inc,
+		accounter:       acc,
+		saver:           sav,
+		grouper:         grp,
+		debtor:          debt,
 		reporter:        rep,
+		exporter:        exp,
+		importer:        imp,
+		recurrer:        rec,
+		journaler:       jr,
 		limiter:         lm,
 		currencyManager: cm,
 		rater:           rater,
+		unitOfWork:      uow,
+		outbox:          ob,
 		logger:          l,
 	}
 }
@@ -54,15 +128,39 @@ func (c *controller) SetCurrency(ctx context.Context, req request.SetCurrency) (
 	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.SetCurrency")
 	defer span.Finish()
 
-	if err := c.currencyManager.Set(ctx, req.User, req.Code); err != nil {
+	previous, err := c.currencyManager.Get(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot get user currency", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := c.currencyManager.Set(ctx, req.User, req.Code); err != nil {
+			return errors.Wrap(err, "currencyManager.Set")
+		}
+
+		resp.Operation, err = c.journaler.Record(ctx, req.User, types.Operation{
+			Kind:     types.OperationSetCurrency,
+			Currency: previous,
+		})
+		return errors.Wrap(err, "Journaler.Record")
+	})
+	if err != nil {
 		c.logger.Error("cannot set user currency", zap.Error(err), zap.Object("request", req))
+		resp.Operation = 0
 		return
 	}
 
-	resp = true
+	resp.Success = true
 	return
 }
 
+// IsCurrencyAvailable reports whether the currency may be used in expenses, so that parsers can tell codes
+// from words.
+func (c *controller) IsCurrencyAvailable(code string) bool {
+	return c.currencyManager.IsAvailable(code)
+}
+
 func (c *controller) ListLimits(ctx context.Context, req request.ListLimits) (resp response.ListLimits) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListLimits")
 	defer span.Finish()
@@ -80,7 +178,13 @@ func (c *controller) ListLimits(ctx context.Context, req request.ListLimits) (re
 
 	resp.CurrentCurrency = currency
 
-	limits, err := c.limiter.List(ctx, req.User)
+	budget, _, err := c.budget(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot get user budget", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	limits, err := c.limiter.List(ctx, budget)
 	if err != nil {
 		c.logger.Error("cannot get user limits", zap.Error(err), zap.Object("request", req))
 		return
@@ -106,36 +210,94 @@ func (c *controller) ListLimits(ctx context.Context, req request.ListLimits) (re
 		list[category] = item
 	}
 
+	if resp.Alerts, err = c.limiter.GetAlerts(ctx, budget); err != nil {
+		c.logger.Error("cannot get user limit alerts", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
 	resp.List = list
 	resp.Success = true
 
 	return
 }
 
-func (c *controller) SetLimit(ctx context.Context, req request.SetLimit) response.SetLimit {
+func (c *controller) SetLimit(ctx context.Context, req request.SetLimit) (resp response.SetLimit) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.SetLimit")
 	defer span.Finish()
 
 	currency, ok := c.resolveUserCurrency(ctx, req.User)
 	if !ok {
-		return false
+		return
 	}
 
-	if req.Value == 0 {
-		err := c.limiter.Unset(ctx, req.User, req.Category)
-		if err != nil {
-			c.logger.Error("cannot unset user limit", zap.Error(err), zap.Object("request", req))
-			return false
-		}
-	} else {
-		err := c.limiter.Set(ctx, req.User, req.Value, currency, req.Category)
-		if err != nil {
-			c.logger.Error("cannot set user limit", zap.Error(err), zap.Object("request", req))
-			return false
+	budget, owner, err := c.budget(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot get user budget", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	if !owner {
+		resp.Forbidden = true
+		return
+	}
+
+	// the limit is kept as it was along with its remains, so that the change can be undone
+	limits, err := c.limiter.List(ctx, budget)
+	if err != nil {
+		c.logger.Error("cannot get user limits", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) (err error) {
+		if req.Value == 0 {
+			if err = c.limiter.Unset(ctx, budget, req.Category); err != nil {
+				return errors.Wrap(err, "limiter.Unset")
+			}
+		} else {
+			if err = c.limiter.Set(ctx, budget, req.Value, currency, req.Period, req.Category); err != nil {
+				return errors.Wrap(err, "limiter.Set")
+			}
 		}
+
+		resp.Operation, err = c.journaler.Record(ctx, req.User, types.Operation{
+			Kind:     types.OperationSetLimit,
+			Category: req.Category,
+			Limit:    limits[req.Category],
+		})
+		return errors.Wrap(err, "Journaler.Record")
+	})
+	if err != nil {
+		c.logger.Error("cannot set user limit", zap.Error(err), zap.Object("request", req))
+		resp.Operation = 0
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) SetLimitAlerts(ctx context.Context, req request.SetLimitAlerts) (resp response.SetLimitAlerts) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.SetLimitAlerts")
+	defer span.Finish()
+
+	budget, owner, err := c.budget(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot get user budget", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	if !owner {
+		resp.Forbidden = true
+		return
+	}
+
+	if err := c.limiter.SetAlerts(ctx, budget, req.Thresholds); err != nil {
+		c.logger.Error("cannot set user limit alerts", zap.Error(err), zap.Object("request", req))
+		return
 	}
 
-	return true
+	resp.Success = true
+	return
 }
 
 func (c *controller) AddExpense(ctx context.Context, req request.AddExpense) (resp response.AddExpense) {
@@ -148,63 +310,414 @@ func (c *controller) AddExpense(ctx context.Context, req request.AddExpense) (re
 	}
 	defer c.rater.ReleaseExchange()
 
-	currency, ok := c.resolveUserCurrency(ctx, req.User)
-	if !ok {
+	category, err := c.categorizer.ResolveCategory(ctx, req.User, req.Category)
+	if err != nil {
+		c.logger.Error("cannot resolve category", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	req.Category = category
+
+	account, tags, err := c.pickAccount(ctx, req.User, req.Account, req.Tags)
+	if err != nil {
+		resp.UnknownAccount = errors.Is(err, ErrNotFound)
+		if !resp.UnknownAccount {
+			c.logger.Error("cannot get account", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	req.Tags = tags
+
+	// the expense paid from an account is in the account currency unless another one is given
+	currency := req.Currency
+	switch {
+	case currency != "":
+		if !c.currencyManager.IsAvailable(currency) {
+			resp.UnknownCurrency = true
+			return
+		}
+	case account.ID != 0:
+		currency = account.Currency
+	default:
+		var ok bool
+		if currency, ok = c.resolveUserCurrency(ctx, req.User); !ok {
+			return
+		}
+	}
+
+	budget, _, err := c.budget(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot get user budget", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	var debts []types.Debt
+	if len(req.Split) > 0 {
+		if req.Amount, debts, err = c.split(ctx, req, currency); err != nil {
+			resp.UnknownMember = errors.Is(err, ErrNotFound)
+			if !resp.UnknownMember {
+				c.logger.Error("cannot split expense", zap.Error(err), zap.Object("request", req))
+			}
+			return
+		}
+	}
+
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) (err error) {
+		if resp.ID, err = c.addExpense(ctx, req, currency, account.ID); err != nil {
+			return err
+		}
+
+		for _, debt := range debts {
+			if err = c.debtor.AddDebt(ctx, debt); err != nil {
+				return errors.Wrap(err, "Debtor.AddDebt")
+			}
+		}
+
+		if err = c.chargeLimit(ctx, budget, req.Amount, currency, req.Date, req.Category); err != nil {
+			return err
+		}
+
+		if resp.LimitAlert, err = c.alertLimit(ctx, budget, req.Category); err != nil {
+			return err
+		}
+
+		err = c.publishExpenseEvent(ctx, _eventExpenseAdded, req.User, types.Expense{
+			ExpenseItem: types.ExpenseItem{
+				ID:       resp.ID,
+				Date:     req.Date,
+				Amount:   req.Amount,
+				Currency: currency,
+			},
+			Category: req.Category,
+		})
+		if err != nil || req.Scheduled {
+			return err
+		}
+
+		resp.Operation, err = c.journaler.Record(ctx, req.User, types.Operation{
+			Kind:      types.OperationAddExpense,
+			ExpenseID: resp.ID,
+			Debts:     debts,
+		})
+		return errors.Wrap(err, "Journaler.Record")
+	})
+	if err != nil {
+		resp.Duplicate = errors.Is(err, errDuplicateReceipt)
+		resp.Invalid = asInputError(err)
+		if !resp.Duplicate && resp.Invalid == nil {
+			c.logger.Error("cannot add expense", zap.Error(err), zap.Object("request", req))
+		}
+		resp.ID = 0
+		resp.LimitAlert = 0
+		resp.Operation = 0
+		return
+	}
+
+	resp.Debts = debts
+	resp.Success = true
+	return
+}
+
+// split divides the expense equally between the user and the group members named in the request,
+// and returns the user's share, which becomes the expense charged to the limit, along with the debts
+// of the others. The user pays the remainder of the division, so that the shares sum up to the expense amount.
+//
+// The names are looked up among the members of the user's group only. The Bot API can't resolve a @username
+// into a Telegram user, and tg_users keeps nothing but the IDs of those who have written to the bot, so the names
+// given on joining the group are the only ones known. Joining the group by its code is also the member's consent
+// to owe shares and to be notified of them.
+func (c *controller) split(ctx context.Context, req request.AddExpense, currency string) (int64, []types.Debt, error) {
+	group, err := c.grouper.GetGroup(ctx, req.User)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "Grouper.GetGroup")
+	}
+
+	creditor, found := group.Member(req.User)
+	if !found {
+		return 0, nil, ErrNotFound
+	}
+
+	var debtors []types.GroupMember
+	seen := map[types.User]bool{*req.User: true}
+	for _, name := range req.Split {
+		member, found := group.MemberByName(name)
+		if !found {
+			return 0, nil, ErrNotFound
+		}
+
+		if !seen[*member.User] {
+			seen[*member.User] = true
+			debtors = append(debtors, member)
+		}
+	}
+
+	share := req.Amount / int64(len(debtors)+1)
+
+	debts := make([]types.Debt, 0, len(debtors))
+	for _, member := range debtors {
+		debts = append(debts, types.Debt{
+			Date:         req.Date,
+			Creditor:     req.User,
+			CreditorName: creditor.Name,
+			Debtor:       member.User,
+			DebtorName:   member.Name,
+			Amount:       share,
+			Currency:     currency,
+			Category:     req.Category,
+		})
+	}
+
+	return req.Amount - share*int64(len(debtors)), debts, nil
+}
+
+// addExpense adds the expense of the request and returns its ID, the expense made from a receipt is added
+// as an imported one, so that the receipt identifiers are kept along with it.
+func (c *controller) addExpense(ctx context.Context, req request.AddExpense, currency string, account int64) (int64, error) {
+	if req.Receipt == nil {
+		id, err := c.expenser.AddExpense(ctx, req.User, req.Date, req.Amount, currency, req.Category, account, req.Note, req.Tags)
+		return id, errors.Wrap(err, "Expenser.AddExpense")
+	}
+
+	added, err := c.expenser.ImportExpenses(ctx, req.User, []types.ImportedExpense{{
+		Expense: types.Expense{
+			ExpenseItem: types.ExpenseItem{
+				Date:     req.Date,
+				Amount:   req.Amount,
+				Currency: currency,
+				Account:  account,
+			},
+			Category: req.Category,
+		},
+		Hash: req.Receipt.Key(),
+	}})
+	if err != nil {
+		return 0, errors.Wrap(err, "Expenser.ImportExpenses")
+	}
+
+	if len(added) == 0 {
+		return 0, errDuplicateReceipt
+	}
+
+	return added[0].ID, nil
+}
+
+func (c *controller) ListExpenses(ctx context.Context, req request.ListExpenses) (resp response.ListExpenses) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListExpenses")
+	defer span.Finish()
+
+	list, err := c.expenser.ListLastExpenses(ctx, req.User, _lastExpensesCount)
+	if err != nil {
+		c.logger.Error("cannot list expenses", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	ids := make([]int64, 0, len(list))
+	for _, item := range list {
+		ids = append(ids, item.ID)
+	}
+
+	attached, err := c.attacher.ListAttached(ctx, req.User, ids)
+	if err != nil {
+		c.logger.Error("cannot list attached expenses", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Attached = attached
+	resp.Success = true
+	return
+}
+
+func (c *controller) FindExpenses(ctx context.Context, req request.FindExpenses) (resp response.FindExpenses) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.FindExpenses")
+	defer span.Finish()
+
+	list, more, err := c.expenser.FindExpenses(ctx, req.User, req.Filter, req.Page*_foundExpensesPageSize, _foundExpensesPageSize)
+	if err != nil {
+		c.logger.Error("cannot find expenses", zap.Error(err), zap.Object("request", req))
 		return
 	}
 
-	if err := c.expenser.AddExpense(ctx, req.User, req.Date, req.Amount, currency, req.Category); err != nil {
-		c.logger.Error("cannot add expense", zap.Error(err), zap.Object("request", req))
+	resp.List = list
+	resp.Page = req.Page
+	resp.More = more
+	resp.Success = true
+	return
+}
+
+func (c *controller) GetExpense(ctx context.Context, req request.GetExpense) (resp response.GetExpense) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.GetExpense")
+	defer span.Finish()
+
+	item, err := c.expenser.GetExpense(ctx, req.User, req.ID)
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot get expense", zap.Error(err), zap.Object("request", req))
+		}
+
 		return
 	}
 
+	resp.Item = item
 	resp.Success = true
+	return
+}
+
+func (c *controller) UpdateExpense(ctx context.Context, req request.UpdateExpense) (resp response.UpdateExpense) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.UpdateExpense")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	if req.Currency != "" && !c.currencyManager.IsAvailable(req.Currency) {
+		resp.UnknownCurrency = true
+		return
+	}
+
+	origin, err := c.expenser.GetExpense(ctx, req.User, req.ID)
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot get expense", zap.Error(err), zap.Object("request", req))
+		}
+
+		return
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = origin.Currency
+	}
+
+	if req.Category, err = c.categorizer.ResolveCategory(ctx, req.User, req.Category); err != nil {
+		c.logger.Error("cannot resolve category", zap.Error(err), zap.Object("request", req))
+		return
+	}
 
-	limit, err := c.limiter.Get(ctx, req.User, req.Category)
+	budget, _, err := c.budget(ctx, req.User)
 	if err != nil {
-		c.logger.Error("cannot get user limit", zap.Error(err), zap.Object("request", req))
+		c.logger.Error("cannot get user budget", zap.Error(err), zap.Object("request", req))
 		return
 	}
 
-	if limit.Total == 0 {
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) (err error) {
+		if err = c.expenser.UpdateExpense(ctx, req.User, req.ID, req.Date, req.Amount, currency, req.Category); err != nil {
+			return errors.Wrap(err, "Expenser.UpdateExpense")
+		}
+
+		if err = c.refundLimit(ctx, budget, origin.Amount, origin.Currency, origin.Date, origin.Category); err != nil {
+			return err
+		}
+
+		if err = c.chargeLimit(ctx, budget, req.Amount, currency, req.Date, req.Category); err != nil {
+			return err
+		}
+
+		if resp.LimitAlert, err = c.alertLimit(ctx, budget, req.Category); err != nil {
+			return err
+		}
+
+		return c.publishExpenseEvent(ctx, _eventExpenseUpdated, req.User, types.Expense{
+			ExpenseItem: types.ExpenseItem{
+				ID:       req.ID,
+				Date:     req.Date,
+				Amount:   req.Amount,
+				Currency: currency,
+			},
+			Category: req.Category,
+		})
+	})
+	if err != nil {
+		if resp.Invalid = asInputError(err); resp.Invalid == nil {
+			c.logger.Error("cannot update expense", zap.Error(err), zap.Object("request", req))
+		}
+		resp.LimitAlert = 0
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) DeleteExpense(ctx context.Context, req request.DeleteExpense) (resp response.DeleteExpense) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.DeleteExpense")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	origin, err := c.expenser.GetExpense(ctx, req.User, req.ID)
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot get expense", zap.Error(err), zap.Object("request", req))
+		}
+
 		return
 	}
 
-	limitRetention, err := c.rater.Exchange(ctx, req.Amount, currency, limit.Currency, utils.TruncateToDate(req.Date))
+	budget, _, err := c.budget(ctx, req.User)
 	if err != nil {
-		c.logger.Error("cannot exchange currency", zap.Error(err), zap.String("from", currency), zap.String("to", limit.Currency))
+		c.logger.Error("cannot get user budget", zap.Error(err), zap.Object("request", req))
 		return
 	}
 
-	reached, err := c.limiter.Decrease(ctx, req.User, limitRetention, req.Category)
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := c.expenser.DeleteExpense(ctx, req.User, req.ID); err != nil {
+			return errors.Wrap(err, "Expenser.DeleteExpense")
+		}
+
+		if err := c.refundLimit(ctx, budget, origin.Amount, origin.Currency, origin.Date, origin.Category); err != nil {
+			return err
+		}
+
+		return c.publishExpenseEvent(ctx, _eventExpenseDeleted, req.User, origin)
+	})
 	if err != nil {
-		c.logger.Error("cannot decrease limit", zap.Error(err), zap.String("currency", currency), zap.Object("limit", limit))
+		c.logger.Error("cannot delete expense", zap.Error(err), zap.Object("request", req))
 		return
 	}
 
-	resp.LimitReached = reached
+	resp.Success = true
 	return
 }
 
-func (c *controller) GetReport(ctx context.Context, req request.GetReport) (resp response.GetReport) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.GetReport")
+func (c *controller) AttachToExpense(ctx context.Context, req request.AttachToExpense) (resp response.AttachToExpense) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.AttachToExpense")
 	defer span.Finish()
 
-	resp.From = req.From
+	if err := c.attacher.Attach(ctx, req.User, req.ExpenseID, req.Data); err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		resp.TooLarge = errors.Is(err, ErrTooLarge)
+		if !resp.NotFound && !resp.TooLarge {
+			c.logger.Error("cannot attach file to expense", zap.Error(err), zap.Object("request", req))
+		}
 
-	currency, ok := c.resolveUserCurrency(ctx, req.User)
-	if !ok {
 		return
 	}
 
-	resp.Currency = currency
+	resp.Success = true
+	return
+}
 
-	data, err := c.reporter.GetReport(ctx, req.User, req.From, currency)
-	resp.Ready = !errors.Is(err, ErrNotReady)
+func (c *controller) GetAttachment(ctx context.Context, req request.GetAttachment) (resp response.GetAttachment) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.GetAttachment")
+	defer span.Finish()
 
+	data, err := c.attacher.GetAttachment(ctx, req.User, req.ExpenseID)
 	if err != nil {
-		if resp.Ready {
-			c.logger.Error("cannot get report", zap.Error(err), zap.Object("request", req))
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot get attachment", zap.Error(err), zap.Object("request", req))
 		}
 
 		return
@@ -215,6 +728,1016 @@ func (c *controller) GetReport(ctx context.Context, req request.GetReport) (resp
 	return
 }
 
+// AddIncome adds the income of the user, incomes are neither charged to limits nor published as expense events.
+func (c *controller) AddIncome(ctx context.Context, req request.AddIncome) (resp response.AddIncome) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.AddIncome")
+	defer span.Finish()
+
+	account, err := c.resolveAccount(ctx, req.User, req.Account)
+	if err != nil {
+		resp.UnknownAccount = errors.Is(err, ErrNotFound)
+		if !resp.UnknownAccount {
+			c.logger.Error("cannot get account", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	// the income put into an account is in the account currency unless another one is given
+	currency := req.Currency
+	switch {
+	case currency != "":
+		if !c.currencyManager.IsAvailable(currency) {
+			resp.UnknownCurrency = true
+			return
+		}
+	case account.ID != 0:
+		currency = account.Currency
+	default:
+		var ok bool
+		if currency, ok = c.resolveUserCurrency(ctx, req.User); !ok {
+			return
+		}
+	}
+
+	if err := c.incomer.AddIncome(ctx, req.User, req.Date, req.Amount, currency, req.Category, account.ID); err != nil {
+		if resp.Invalid = asInputError(err); resp.Invalid == nil {
+			c.logger.Error("cannot add income", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) ListIncomes(ctx context.Context, req request.ListIncomes) (resp response.ListIncomes) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListIncomes")
+	defer span.Finish()
+
+	list, err := c.incomer.ListLastIncomes(ctx, req.User, _lastExpensesCount)
+	if err != nil {
+		c.logger.Error("cannot list incomes", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+func (c *controller) AddAccount(ctx context.Context, req request.AddAccount) (resp response.AddAccount) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.AddAccount")
+	defer span.Finish()
+
+	currency := req.Currency
+	if currency == "" {
+		var ok bool
+		if currency, ok = c.resolveUserCurrency(ctx, req.User); !ok {
+			return
+		}
+	} else if !c.currencyManager.IsAvailable(currency) {
+		resp.UnknownCurrency = true
+		return
+	}
+
+	if err := c.accounter.AddAccount(ctx, req.User, req.Name, currency, req.Opening); err != nil {
+		resp.Duplicate = errors.Is(err, ErrAlreadyExists)
+		resp.Invalid = asInputError(err)
+		if !resp.Duplicate && resp.Invalid == nil {
+			c.logger.Error("cannot add account", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) ListAccounts(ctx context.Context, req request.ListAccounts) (resp response.ListAccounts) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListAccounts")
+	defer span.Finish()
+
+	list, err := c.accounter.ListAccounts(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot list accounts", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+// GetBalance returns balances of the user accounts converted into the user's current currency at today's rate.
+func (c *controller) GetBalance(ctx context.Context, req request.GetBalance) (resp response.GetBalance) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.GetBalance")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	currency, ok := c.resolveUserCurrency(ctx, req.User)
+	if !ok {
+		return
+	}
+
+	resp.Currency = currency
+
+	list, err := c.accounter.ListBalances(ctx, req.User, currency, utils.TruncateToDate(time.Now()))
+	if err != nil {
+		c.logger.Error("cannot list balances", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+// Transfer moves money between accounts of the user, the amount is exchanged into the currency
+// of the target account at the rate of the transfer date unless the received amount is given.
+func (c *controller) Transfer(ctx context.Context, req request.Transfer) (resp response.Transfer) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.Transfer")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	var err error
+	if resp.From, err = c.accounter.GetAccount(ctx, req.User, req.From); err == nil {
+		resp.To, err = c.accounter.GetAccount(ctx, req.User, req.To)
+	}
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot get account", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Received = req.Received
+	if resp.Received == 0 {
+		if resp.Received, err = c.rater.Exchange(ctx, req.Amount, resp.From.Currency, resp.To.Currency, req.Date); err != nil {
+			if resp.Invalid = asInputError(err); resp.Invalid == nil {
+				c.logger.Error("cannot exchange transfer amount", zap.Error(err), zap.Object("request", req))
+			}
+			return
+		}
+	}
+
+	err = c.accounter.Transfer(ctx, req.User, types.Transfer{
+		Date:     req.Date,
+		From:     resp.From.ID,
+		To:       resp.To.ID,
+		Amount:   req.Amount,
+		Received: resp.Received,
+	})
+	if err != nil {
+		if resp.Invalid = asInputError(err); resp.Invalid == nil {
+			c.logger.Error("cannot transfer", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) AddGoal(ctx context.Context, req request.AddGoal) (resp response.AddGoal) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.AddGoal")
+	defer span.Finish()
+
+	currency := req.Currency
+	if currency == "" {
+		var ok bool
+		if currency, ok = c.resolveUserCurrency(ctx, req.User); !ok {
+			return
+		}
+	} else if !c.currencyManager.IsAvailable(currency) {
+		resp.UnknownCurrency = true
+		return
+	}
+
+	err := c.saver.AddGoal(ctx, req.User, types.Goal{
+		Name:     req.Name,
+		Target:   req.Target,
+		Currency: currency,
+		Deadline: req.Deadline,
+	})
+	if err != nil {
+		resp.Duplicate = errors.Is(err, ErrAlreadyExists)
+		resp.Invalid = asInputError(err)
+		if !resp.Duplicate && resp.Invalid == nil {
+			c.logger.Error("cannot add goal", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) Contribute(ctx context.Context, req request.Contribute) (resp response.Contribute) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.Contribute")
+	defer span.Finish()
+
+	if req.Currency != "" && !c.currencyManager.IsAvailable(req.Currency) {
+		resp.UnknownCurrency = true
+		return
+	}
+
+	goal, err := c.saver.Contribute(ctx, req.User, req.Name, req.Date, req.Amount, req.Currency)
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot contribute to goal", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Goal = goal
+	resp.Success = true
+	return
+}
+
+func (c *controller) DeleteGoal(ctx context.Context, req request.DeleteGoal) (resp response.DeleteGoal) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.DeleteGoal")
+	defer span.Finish()
+
+	if err := c.saver.DeleteGoal(ctx, req.User, req.Name); err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot delete goal", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+// ListGoals returns progress of the user goals converted into the user's current currency at today's rate.
+func (c *controller) ListGoals(ctx context.Context, req request.ListGoals) (resp response.ListGoals) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListGoals")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	currency, ok := c.resolveUserCurrency(ctx, req.User)
+	if !ok {
+		return
+	}
+
+	resp.Currency = currency
+
+	list, err := c.saver.ListGoals(ctx, req.User, currency, utils.TruncateToDate(time.Now()))
+	if err != nil {
+		c.logger.Error("cannot list goals", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+func (c *controller) CreateGroup(ctx context.Context, req request.CreateGroup) (resp response.CreateGroup) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.CreateGroup")
+	defer span.Finish()
+
+	group, err := c.grouper.CreateGroup(ctx, req.User, req.Name)
+	if err != nil {
+		resp.AlreadyMember = errors.Is(err, ErrAlreadyExists)
+		if !resp.AlreadyMember {
+			c.logger.Error("cannot create group", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Group = group
+	resp.Success = true
+	return
+}
+
+func (c *controller) JoinGroup(ctx context.Context, req request.JoinGroup) (resp response.JoinGroup) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.JoinGroup")
+	defer span.Finish()
+
+	group, err := c.grouper.JoinGroup(ctx, req.User, req.Code, req.Name)
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		resp.AlreadyMember = errors.Is(err, ErrAlreadyExists)
+		if !resp.NotFound && !resp.AlreadyMember {
+			c.logger.Error("cannot join group", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Group = group
+	resp.Success = true
+	return
+}
+
+func (c *controller) LeaveGroup(ctx context.Context, req request.LeaveGroup) (resp response.LeaveGroup) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.LeaveGroup")
+	defer span.Finish()
+
+	if err := c.grouper.LeaveGroup(ctx, req.User); err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot leave group", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) RemoveGroupMember(ctx context.Context, req request.RemoveGroupMember) (resp response.RemoveGroupMember) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.RemoveGroupMember")
+	defer span.Finish()
+
+	if err := c.grouper.RemoveMember(ctx, req.User, req.Name); err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		resp.Forbidden = errors.Is(err, ErrForbidden)
+		if !resp.NotFound && !resp.Forbidden {
+			c.logger.Error("cannot remove group member", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) GetGroup(ctx context.Context, req request.GetGroup) (resp response.GetGroup) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.GetGroup")
+	defer span.Finish()
+
+	group, err := c.grouper.GetGroup(ctx, req.User)
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot get group", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Group = group
+	resp.Success = true
+	return
+}
+
+func (c *controller) ListDebts(ctx context.Context, req request.ListDebts) (resp response.ListDebts) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListDebts")
+	defer span.Finish()
+
+	list, err := c.debtor.ListDebts(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot list debts", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+func (c *controller) SettleDebts(ctx context.Context, req request.SettleDebts) (resp response.SettleDebts) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.SettleDebts")
+	defer span.Finish()
+
+	err := c.unitOfWork.Do(ctx, func(ctx context.Context) (err error) {
+		resp.Settled, err = c.debtor.SettleDebts(ctx, req.User, req.Name, req.Currency)
+		return err
+	})
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot settle debts", zap.Error(err), zap.Object("request", req))
+		}
+		resp.Settled = nil
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) GetReport(ctx context.Context, req request.GetReport) (resp response.GetReport) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.GetReport")
+	defer span.Finish()
+
+	resp.From = req.From
+	resp.To = req.To
+	resp.Grouping = req.Grouping
+	resp.Compare = req.Compare
+
+	currency, ok := c.resolveUserCurrency(ctx, req.User)
+	if !ok {
+		return
+	}
+
+	resp.Currency = currency
+
+	data, err := c.reporter.GetReport(ctx, req.User, req.From, req.To, req.Grouping, req.Compare, currency)
+	resp.Ready = !errors.Is(err, ErrNotReady)
+
+	if err != nil {
+		if resp.Ready {
+			c.logger.Error("cannot get report", zap.Error(err), zap.Object("request", req))
+		}
+
+		return
+	}
+
+	if req.RollUp {
+		data.Data = types.RollUpCategories(data.Data)
+		data.Previous = types.RollUpCategories(data.Previous)
+		for i := range data.Buckets {
+			data.Buckets[i].Data = types.RollUpCategories(data.Buckets[i].Data)
+		}
+	}
+
+	resp.Data = data.Data
+	resp.Income = data.Income
+	resp.Buckets = data.Buckets
+	resp.Previous = data.Previous
+	resp.PreviousIncome = data.PreviousIncome
+	resp.Members = data.Members
+	resp.Success = true
+	return
+}
+
+func (c *controller) ExportExpenses(ctx context.Context, req request.ExportExpenses) (resp response.ExportExpenses) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ExportExpenses")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	currency, ok := c.resolveUserCurrency(ctx, req.User)
+	if !ok {
+		return
+	}
+
+	resp.Currency = currency
+
+	count, err := c.exporter.Export(ctx, req.User, req.From, req.To, req.Format, currency, req.Writer)
+	if err != nil {
+		c.logger.Error("cannot export expenses", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.Count = count
+	resp.Success = true
+	return
+}
+
+func (c *controller) ImportExpenses(ctx context.Context, req request.ImportExpenses) (resp response.ImportExpenses) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ImportExpenses")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	items, err := c.importer.Parse(ctx, req.User, req.Name, req.Bank, req.Data)
+	if err != nil {
+		resp.UnknownStatement = errors.Is(err, ErrUnknownStatement)
+		resp.InvalidStatement = errors.Is(err, ErrInvalidStatement)
+		if !resp.UnknownStatement {
+			c.logger.Error("cannot parse statement", zap.Error(err), zap.Object("request", req))
+		}
+
+		return
+	}
+
+	resp.Parsed = len(items)
+	if len(items) == 0 {
+		resp.Success = true
+		return
+	}
+
+	budget, _, err := c.budget(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot get user budget", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		added, err := c.expenser.ImportExpenses(ctx, req.User, items)
+		if err != nil {
+			return errors.Wrap(err, "Expenser.ImportExpenses")
+		}
+
+		resp.Imported = len(added)
+
+		if resp.LimitAlerts, err = c.chargeImported(ctx, budget, added); err != nil {
+			return err
+		}
+
+		for _, item := range added {
+			if err := c.publishExpenseEvent(ctx, _eventExpenseAdded, req.User, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("cannot import expenses", zap.Error(err), zap.Object("request", req))
+		resp.Imported, resp.LimitAlerts = 0, nil
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) ListMerchantRules(ctx context.Context, req request.ListMerchantRules) (resp response.ListMerchantRules) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListMerchantRules")
+	defer span.Finish()
+
+	list, err := c.importer.ListRules(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot list merchant rules", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+func (c *controller) SetMerchantRule(ctx context.Context, req request.SetMerchantRule) (resp response.SetMerchantRule) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.SetMerchantRule")
+	defer span.Finish()
+
+	var err error
+	if req.Category == "" {
+		err = c.importer.DeleteRule(ctx, req.User, req.Pattern)
+	} else {
+		err = c.importer.SetRule(ctx, req.User, req.Pattern, req.Category)
+	}
+
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot set merchant rule", zap.Error(err), zap.Object("request", req))
+		}
+
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) ListCategories(ctx context.Context, req request.ListCategories) (resp response.ListCategories) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListCategories")
+	defer span.Finish()
+
+	list, err := c.categorizer.ListCategories(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot list categories", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+func (c *controller) SetCategoryAlias(ctx context.Context, req request.SetCategoryAlias) (resp response.SetCategoryAlias) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.SetCategoryAlias")
+	defer span.Finish()
+
+	var err error
+	if req.Category == "" {
+		err = c.categorizer.DeleteAlias(ctx, req.User, req.Alias)
+	} else {
+		err = c.categorizer.SetAlias(ctx, req.User, req.Alias, req.Category)
+	}
+
+	if err != nil {
+		resp.Invalid = errors.Is(err, ErrInvalidCategory)
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.Invalid && !resp.NotFound {
+			c.logger.Error("cannot set category alias", zap.Error(err), zap.Object("request", req))
+		}
+
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+// RenameCategory renames the category through the whole history at once, so that expenses, limits,
+// recurring expenses, merchant rules and aliases never refer to different names.
+func (c *controller) RenameCategory(ctx context.Context, req request.RenameCategory) (resp response.RenameCategory) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.RenameCategory")
+	defer span.Finish()
+
+	err := c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		return c.categorizer.RenameCategory(ctx, req.User, req.From, req.To, req.Merge)
+	})
+	if err != nil {
+		resp.Invalid = errors.Is(err, ErrInvalidCategory)
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		resp.AlreadyExists = errors.Is(err, ErrAlreadyExists)
+		if !resp.Invalid && !resp.NotFound && !resp.AlreadyExists {
+			c.logger.Error("cannot rename category", zap.Error(err), zap.Object("request", req))
+		}
+
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+func (c *controller) AddRecurring(ctx context.Context, req request.AddRecurring) (resp response.AddRecurring) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.AddRecurring")
+	defer span.Finish()
+
+	currency := req.Currency
+	if currency == "" {
+		var ok bool
+		if currency, ok = c.resolveUserCurrency(ctx, req.User); !ok {
+			return
+		}
+	} else if !c.currencyManager.IsAvailable(currency) {
+		resp.UnknownCurrency = true
+		return
+	}
+
+	item, err := c.recurrer.Add(ctx, req.User, types.RecurringExpense{
+		Recurrence: req.Recurrence,
+		Amount:     req.Amount,
+		Currency:   currency,
+		Category:   req.Category,
+	})
+	if err != nil {
+		if resp.Invalid = asInputError(err); resp.Invalid == nil {
+			c.logger.Error("cannot add recurring expense", zap.Error(err), zap.Object("request", req))
+		}
+		return
+	}
+
+	resp.Item = item
+	resp.Success = true
+	return
+}
+
+func (c *controller) ListRecurring(ctx context.Context, req request.ListRecurring) (resp response.ListRecurring) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.ListRecurring")
+	defer span.Finish()
+
+	list, err := c.recurrer.List(ctx, req.User)
+	if err != nil {
+		c.logger.Error("cannot list recurring expenses", zap.Error(err), zap.Object("request", req))
+		return
+	}
+
+	resp.List = list
+	resp.Success = true
+	return
+}
+
+func (c *controller) SetRecurringPaused(ctx context.Context, req request.SetRecurringPaused) (resp response.SetRecurringPaused) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.SetRecurringPaused")
+	defer span.Finish()
+
+	item, err := c.recurrer.SetPaused(ctx, req.User, req.ID, req.Paused)
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		if !resp.NotFound {
+			c.logger.Error("cannot pause recurring expense", zap.Error(err), zap.Object("request", req))
+		}
+
+		return
+	}
+
+	resp.Item = item
+	resp.Success = true
+	return
+}
+
+// Undo reverts the last operation of the user made within the undo window, the operation leaves the journal
+// only once it's reverted. The operation named in the request is reverted only while it's the last one.
+func (c *controller) Undo(ctx context.Context, req request.Undo) (resp response.Undo) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "controller.Undo")
+	defer span.Finish()
+
+	resp.Ready = c.rater.TryAcquireExchange()
+	if !resp.Ready {
+		return
+	}
+	defer c.rater.ReleaseExchange()
+
+	err := c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		op, err := c.journaler.Pop(ctx, req.User, req.Operation)
+		if err != nil {
+			return errors.Wrap(err, "Journaler.Pop")
+		}
+
+		resp.Kind = op.Kind
+
+		return c.revert(ctx, req.User, op)
+	})
+	if err != nil {
+		resp.NotFound = errors.Is(err, ErrNotFound)
+		resp.Forbidden = errors.Is(err, ErrForbidden)
+		if !resp.NotFound && !resp.Forbidden {
+			c.logger.Error("cannot undo operation", zap.Error(err), zap.Object("request", req))
+		}
+
+		resp.Kind = ""
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+// revert applies the inverse of the operation: the added expense is deleted and its debts are written off
+// by the opposite ones, the limit and the currency are put back as they were.
+func (c *controller) revert(ctx context.Context, user *types.User, op types.Operation) error {
+	switch op.Kind {
+	case types.OperationAddExpense:
+		origin, err := c.expenser.GetExpense(ctx, user, op.ExpenseID)
+		if err != nil {
+			return errors.Wrap(err, "Expenser.GetExpense")
+		}
+
+		budget, _, err := c.budget(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		// the report cache is invalidated by the expenser along with the deletion
+		if err = c.expenser.DeleteExpense(ctx, user, op.ExpenseID); err != nil {
+			return errors.Wrap(err, "Expenser.DeleteExpense")
+		}
+
+		for _, debt := range op.Debts {
+			debt.Creditor, debt.Debtor = debt.Debtor, debt.Creditor
+			debt.CreditorName, debt.DebtorName = debt.DebtorName, debt.CreditorName
+
+			if err = c.debtor.AddDebt(ctx, debt); err != nil {
+				return errors.Wrap(err, "Debtor.AddDebt")
+			}
+		}
+
+		if err = c.refundLimit(ctx, budget, origin.Amount, origin.Currency, origin.Date, origin.Category); err != nil {
+			return err
+		}
+
+		return c.publishExpenseEvent(ctx, _eventExpenseDeleted, user, origin)
+
+	case types.OperationSetLimit:
+		budget, owner, err := c.budget(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		if !owner {
+			return ErrForbidden
+		}
+
+		return errors.Wrap(c.limiter.Restore(ctx, budget, op.Limit, op.Category), "limiter.Restore")
+
+	case types.OperationSetCurrency:
+		return errors.Wrap(c.currencyManager.Set(ctx, user, op.Currency), "currencyManager.Set")
+	}
+
+	return errors.Errorf("unknown operation %q", op.Kind)
+}
+
+// chargeImported decreases the limits by the imported expenses made within their current periods,
+// the older ones have been spent in the past periods. It returns the thresholds crossed by category.
+func (c *controller) chargeImported(ctx context.Context, user *types.User, items []types.Expense) (map[string]int, error) {
+	byCategory := make(map[string][]types.Expense)
+	for _, item := range items {
+		byCategory[item.Category] = append(byCategory[item.Category], item)
+	}
+
+	alerts := make(map[string]int)
+	for category, list := range byCategory {
+		limit, err := c.limiter.Get(ctx, user, category)
+		if err != nil {
+			return nil, errors.Wrap(err, "limiter.Get")
+		}
+
+		if limit.Total == 0 {
+			continue
+		}
+
+		var charged bool
+		for _, item := range list {
+			if item.Date.Before(limit.Anchor) {
+				continue
+			}
+
+			if err := c.chargeLimit(ctx, user, item.Amount, item.Currency, item.Date, category); err != nil {
+				return nil, err
+			}
+			charged = true
+		}
+
+		if !charged {
+			continue
+		}
+
+		threshold, err := c.alertLimit(ctx, user, category)
+		if err != nil {
+			return nil, err
+		}
+
+		if threshold > 0 {
+			alerts[category] = threshold
+		}
+	}
+
+	return alerts, nil
+}
+
+// chargeLimit decreases the limit matching the category by the amount exchanged into the limit currency,
+// expenses dated before the current period of the limit aren't charged.
+func (c *controller) chargeLimit(ctx context.Context, user *types.User, amount int64, currency string, date time.Time, category string) error {
+	limit, err := c.limiter.Get(ctx, user, category)
+	if err != nil {
+		return errors.Wrap(err, "limiter.Get")
+	}
+
+	if limit.Total == 0 || date.Before(limit.Anchor) {
+		return nil
+	}
+
+	limitRetention, err := c.rater.Exchange(ctx, amount, currency, limit.Currency, utils.TruncateToDate(date))
+	if err != nil {
+		return errors.Wrapf(err, "exchange %s to %s", currency, limit.Currency)
+	}
+
+	_, err = c.limiter.Decrease(ctx, user, limitRetention, category)
+
+	return errors.Wrap(err, "limiter.Decrease")
+}
+
+// alertLimit returns the highest usage threshold (percent) of the limit matching the category
+// which has been crossed and not alerted yet within the current period, or 0.
+func (c *controller) alertLimit(ctx context.Context, user *types.User, category string) (int, error) {
+	limit, err := c.limiter.Get(ctx, user, category)
+	if err != nil {
+		return 0, errors.Wrap(err, "limiter.Get")
+	}
+
+	budget := limit.Total + limit.Carried
+	if budget <= 0 {
+		return 0, nil
+	}
+
+	thresholds, err := c.limiter.GetAlerts(ctx, user)
+	if err != nil {
+		return 0, errors.Wrap(err, "limiter.GetAlerts")
+	}
+
+	usage := int((budget - limit.Remains) * 100 / budget)
+
+	crossed := 0
+	for _, threshold := range thresholds {
+		if threshold <= usage && threshold > limit.Notified && threshold > crossed {
+			crossed = threshold
+		}
+	}
+
+	if crossed == 0 {
+		return 0, nil
+	}
+
+	notified, err := c.limiter.Notify(ctx, user, crossed, category)
+	if err != nil {
+		return 0, errors.Wrap(err, "limiter.Notify")
+	}
+
+	if !notified {
+		return 0, nil
+	}
+
+	return crossed, nil
+}
+
+// refundLimit gives the amount of a removed (or changed) expense back to the limit matching the category.
+// Expenses of the past periods have been charged to those periods, so they aren't refunded.
+func (c *controller) refundLimit(ctx context.Context, user *types.User, amount int64, currency string, date time.Time, category string) error {
+	limit, err := c.limiter.Get(ctx, user, category)
+	if err != nil {
+		return errors.Wrap(err, "limiter.Get")
+	}
+
+	if limit.Total == 0 || date.Before(limit.Anchor) {
+		return nil
+	}
+
+	limitRetention, err := c.rater.Exchange(ctx, amount, currency, limit.Currency, utils.TruncateToDate(date))
+	if err != nil {
+		return errors.Wrapf(err, "exchange %s to %s", currency, limit.Currency)
+	}
+
+	return errors.Wrap(c.limiter.Increase(ctx, user, limitRetention, category), "limiter.Increase")
+}
+
+// publishExpenseEvent writes the expense change to the outbox.
+func (c *controller) publishExpenseEvent(ctx context.Context, kind string, user *types.User, expense types.Expense) error {
+	payload, err := json.Marshal(expenseEvent{
+		User:     int64(*user),
+		ID:       expense.ID,
+		Date:     expense.Date,
+		Amount:   expense.Amount,
+		Currency: expense.Currency,
+		Category: expense.Category,
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal expense event")
+	}
+
+	return errors.Wrap(c.outbox.Add(ctx, types.OutboxEvent{Kind: kind, Payload: payload}), "outbox.Add")
+}
+
+// asInputError returns the input error the operation failed with, nil if it failed for another reason.
+func asInputError(err error) error {
+	var e InputError
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return nil
+}
+
+// budget returns the user whose limits cover the user's expenses, that's the owner of the user's group
+// or the user themselves, and whether the user is the one managing the limits.
+func (c *controller) budget(ctx context.Context, user *types.User) (*types.User, bool, error) {
+	group, err := c.grouper.GetGroup(ctx, user)
+	if errors.Is(err, ErrNotFound) {
+		return user, true, nil
+	} else if err != nil {
+		return nil, false, errors.Wrap(err, "Grouper.GetGroup")
+	}
+
+	owner := group.Owner()
+
+	return owner, *owner == *user, nil
+}
+
+// resolveAccount returns the account of the user by its name, an empty name stands for no account.
+func (c *controller) resolveAccount(ctx context.Context, user *types.User, name string) (types.Account, error) {
+	if name == "" {
+		return types.Account{}, nil
+	}
+
+	return c.accounter.GetAccount(ctx, user, name)
+}
+
+// pickAccount resolves the account named explicitly, or else the first of the tags naming an account,
+// that tag is dropped from the returned ones as tags and account selectors share the same # sign.
+func (c *controller) pickAccount(ctx context.Context, user *types.User, name string, tags []string) (types.Account, []string, error) {
+	if name != "" {
+		account, err := c.resolveAccount(ctx, user, name)
+		return account, tags, err
+	}
+
+	for i, tag := range tags {
+		account, err := c.accounter.GetAccount(ctx, user, tag)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return types.Account{}, nil, errors.Wrap(err, "Accounter.GetAccount")
+		}
+
+		return account, append(tags[:i:i], tags[i+1:]...), nil
+	}
+
+	return types.Account{}, tags, nil
+}
+
 func (c *controller) resolveUserCurrency(ctx context.Context, user *types.User) (string, bool) {
 	currency, err := c.currencyManager.Get(ctx, user)
 	if err != nil {
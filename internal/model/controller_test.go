@@ -4,9 +4,12 @@ package model
 
 import (
 	"context"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/response"
@@ -18,10 +21,23 @@ import (
 
 type controllerMocksInitializer struct {
 	expenser        func(m *mocks.MockExpenser)
+	categorizer     func(m *mocks.MockCategorizer)
+	attacher        func(m *mocks.MockAttacher)
+	incomer         func(m *mocks.MockIncomer)
+	accounter       func(m *mocks.MockAccounter)
+	saver           func(m *mocks.MockSaver)
+	grouper         func(m *mocks.MockGrouper)
+	debtor          func(m *mocks.MockDebtor)
 	reporter        func(m *mocks.MockReporter)
+	exporter        func(m *mocks.MockExporter)
+	importer        func(m *mocks.MockImporter)
+	recurrer        func(m *mocks.MockRecurrer)
+	journaler       func(m *mocks.MockJournaler)
 	limiter         func(m *mocks.Mocklimiter)
 	currencyManager func(m *mocks.MockcurrencyManager)
 	rater           func(m *mocks.MockRater)
+	unitOfWork      func(m *mocks.MockunitOfWork)
+	outbox          func(m *mocks.Mockoutbox)
 }
 
 func setupController(t *testing.T, i controllerMocksInitializer) *controller {
@@ -32,11 +48,74 @@ func setupController(t *testing.T, i controllerMocksInitializer) *controller {
 		i.expenser(expenserMock)
 	}
 
+	categorizerMock := mocks.NewMockCategorizer(ctrl)
+	if i.categorizer != nil {
+		i.categorizer(categorizerMock)
+	} else {
+		categorizerMock.EXPECT().ResolveCategory(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ *types.User, name string) (string, error) {
+			return name, nil
+		}).AnyTimes()
+	}
+
+	attacherMock := mocks.NewMockAttacher(ctrl)
+	if i.attacher != nil {
+		i.attacher(attacherMock)
+	}
+
+	incomerMock := mocks.NewMockIncomer(ctrl)
+	if i.incomer != nil {
+		i.incomer(incomerMock)
+	}
+
+	accounterMock := mocks.NewMockAccounter(ctrl)
+	if i.accounter != nil {
+		i.accounter(accounterMock)
+	}
+
+	saverMock := mocks.NewMockSaver(ctrl)
+	if i.saver != nil {
+		i.saver(saverMock)
+	}
+
+	grouperMock := mocks.NewMockGrouper(ctrl)
+	if i.grouper != nil {
+		i.grouper(grouperMock)
+	} else {
+		grouperMock.EXPECT().GetGroup(gomock.Any(), gomock.Any()).Return(types.Group{}, ErrNotFound).AnyTimes()
+	}
+
+	debtorMock := mocks.NewMockDebtor(ctrl)
+	if i.debtor != nil {
+		i.debtor(debtorMock)
+	}
+
 	reporterMock := mocks.NewMockReporter(ctrl)
 	if i.reporter != nil {
 		i.reporter(reporterMock)
 	}
 
+	exporterMock := mocks.NewMockExporter(ctrl)
+	if i.exporter != nil {
+		i.exporter(exporterMock)
+	}
+
+	importerMock := mocks.NewMockImporter(ctrl)
+	if i.importer != nil {
+		i.importer(importerMock)
+	}
+
+	recurrerMock := mocks.NewMockRecurrer(ctrl)
+	if i.recurrer != nil {
+		i.recurrer(recurrerMock)
+	}
+
+	journalerMock := mocks.NewMockJournaler(ctrl)
+	if i.journaler != nil {
+		i.journaler(journalerMock)
+	} else {
+		journalerMock.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+	}
+
 	limiterMock := mocks.NewMocklimiter(ctrl)
 	if i.limiter != nil {
 		i.limiter(limiterMock)
@@ -52,7 +131,21 @@ func setupController(t *testing.T, i controllerMocksInitializer) *controller {
 		i.rater(raterMock)
 	}
 
-	return NewController(expenserMock, reporterMock, limiterMock, currencyManagerMock, raterMock, zap.NewNop())
+	unitOfWorkMock := mocks.NewMockunitOfWork(ctrl)
+	if i.unitOfWork != nil {
+		i.unitOfWork(unitOfWorkMock)
+	} else {
+		unitOfWorkMock.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	}
+
+	outboxMock := mocks.NewMockoutbox(ctrl)
+	if i.outbox != nil {
+		i.outbox(outboxMock)
+	}
+
+	return NewController(expenserMock, categorizerMock, attacherMock, incomerMock, accounterMock, saverMock, grouperMock, debtorMock, reporterMock, exporterMock, importerMock, recurrerMock, journalerMock, limiterMock, currencyManagerMock, raterMock, unitOfWorkMock, outboxMock, zap.NewNop())
 }
 
 func Test_controller_ListCurrencies(t *testing.T) {
@@ -106,6 +199,7 @@ func Test_controller_SetCurrency(t *testing.T) {
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
 				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "RUB").Return(test.SimpleError)
 			},
 		})
@@ -117,7 +211,7 @@ func Test_controller_SetCurrency(t *testing.T) {
 		})
 
 		// ASSERT
-		assert.Equal(t, response.SetCurrency(false), resp)
+		assert.Equal(t, response.SetCurrency{}, resp)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -126,8 +220,15 @@ func Test_controller_SetCurrency(t *testing.T) {
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
 				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "EUR").Return(nil)
 			},
+			journaler: func(m *mocks.MockJournaler) {
+				m.EXPECT().Record(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.Operation{
+					Kind:     types.OperationSetCurrency,
+					Currency: "USD",
+				}).Return(int64(5), nil)
+			},
 		})
 
 		// ACT
@@ -137,7 +238,10 @@ func Test_controller_SetCurrency(t *testing.T) {
 		})
 
 		// ASSERT
-		assert.Equal(t, response.SetCurrency(true), resp)
+		assert.Equal(t, response.SetCurrency{
+			Operation: 5,
+			Success:   true,
+		}, resp)
 	})
 }
 
@@ -306,6 +410,7 @@ func Test_controller_ListLimits(t *testing.T) {
 						Currency: "RUB",
 					},
 				}, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{50, 100}, nil)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
 				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
@@ -349,6 +454,7 @@ func Test_controller_ListLimits(t *testing.T) {
 					},
 				},
 			},
+			Alerts:  []int{50, 100},
 			Success: true,
 		}, resp)
 	})
@@ -373,7 +479,7 @@ func Test_controller_SetLimit(t *testing.T) {
 		})
 
 		// ASSERT
-		assert.Equal(t, response.SetLimit(false), resp)
+		assert.Equal(t, response.SetLimit{}, resp)
 	})
 
 	t.Run("cannot set", func(t *testing.T) {
@@ -382,7 +488,8 @@ func Test_controller_SetLimit(t *testing.T) {
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			limiter: func(m *mocks.Mocklimiter) {
-				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1000000), "USD", "taxi").Return(test.SimpleError)
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, nil)
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1000000), "USD", types.LimitPeriod{Kind: types.PeriodMonth}, "taxi").Return(test.SimpleError)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
 				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
@@ -393,11 +500,12 @@ func Test_controller_SetLimit(t *testing.T) {
 		resp := controller.SetLimit(context.Background(), request.SetLimit{
 			User:     test.User,
 			Value:    1000000,
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
 			Category: "taxi",
 		})
 
 		// ASSERT
-		assert.Equal(t, response.SetLimit(false), resp)
+		assert.Equal(t, response.SetLimit{}, resp)
 	})
 
 	t.Run("successful set", func(t *testing.T) {
@@ -406,7 +514,8 @@ func Test_controller_SetLimit(t *testing.T) {
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			limiter: func(m *mocks.Mocklimiter) {
-				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2000000), "USD", "taxi").Return(nil)
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, nil)
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2000000), "USD", types.LimitPeriod{Kind: types.PeriodDay, CarryOver: true}, "taxi").Return(nil)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
 				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
@@ -417,11 +526,12 @@ func Test_controller_SetLimit(t *testing.T) {
 		resp := controller.SetLimit(context.Background(), request.SetLimit{
 			User:     test.User,
 			Value:    2000000,
+			Period:   types.LimitPeriod{Kind: types.PeriodDay, CarryOver: true},
 			Category: "taxi",
 		})
 
 		// ASSERT
-		assert.Equal(t, response.SetLimit(true), resp)
+		assert.Equal(t, response.SetLimit{Success: true}, resp)
 	})
 
 	t.Run("cannot unset", func(t *testing.T) {
@@ -430,6 +540,7 @@ func Test_controller_SetLimit(t *testing.T) {
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, nil)
 				m.EXPECT().Unset(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(test.SimpleError)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
@@ -445,7 +556,7 @@ func Test_controller_SetLimit(t *testing.T) {
 		})
 
 		// ASSERT
-		assert.Equal(t, response.SetLimit(false), resp)
+		assert.Equal(t, response.SetLimit{}, resp)
 	})
 
 	t.Run("successful unset", func(t *testing.T) {
@@ -454,6 +565,7 @@ func Test_controller_SetLimit(t *testing.T) {
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, nil)
 				m.EXPECT().Unset(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(nil)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
@@ -469,7 +581,116 @@ func Test_controller_SetLimit(t *testing.T) {
 		})
 
 		// ASSERT
-		assert.Equal(t, response.SetLimit(true), resp)
+		assert.Equal(t, response.SetLimit{Success: true}, resp)
+	})
+	t.Run("group member", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		owner := types.User(101)
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().GetGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 1,
+					Members: []types.GroupMember{
+						{User: &owner, Role: types.RoleOwner},
+						{User: test.User, Role: types.RoleMember},
+					},
+				}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.SetLimit(context.Background(), request.SetLimit{
+			User:     test.User,
+			Value:    1000000,
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Category: "taxi",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetLimit{Forbidden: true}, resp)
+	})
+
+	t.Run("group owner", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		member := types.User(102)
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().GetGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 1,
+					Members: []types.GroupMember{
+						{User: test.User, Role: types.RoleOwner},
+						{User: &member, Role: types.RoleMember},
+					},
+				}, nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, nil)
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1000000), "USD", types.LimitPeriod{Kind: types.PeriodMonth}, "taxi").Return(nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.SetLimit(context.Background(), request.SetLimit{
+			User:     test.User,
+			Value:    1000000,
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Category: "taxi",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetLimit{Success: true}, resp)
+	})
+}
+
+func Test_controller_SetLimitAlerts(t *testing.T) {
+	t.Run("cannot set", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().SetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User, []int{0}).Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.SetLimitAlerts(context.Background(), request.SetLimitAlerts{
+			User:       test.User,
+			Thresholds: []int{0},
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetLimitAlerts{}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().SetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User, []int{75, 100}).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.SetLimitAlerts(context.Background(), request.SetLimitAlerts{
+			User:       test.User,
+			Thresholds: []int{75, 100},
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetLimitAlerts{Success: true}, resp)
 	})
 }
 
@@ -524,16 +745,13 @@ func Test_controller_AddExpense(t *testing.T) {
 		}, resp)
 	})
 
-	t.Run("cannot add", func(t *testing.T) {
+	t.Run("unknown currency", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
-			expenser: func(m *mocks.MockExpenser) {
-				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(20000), "USD", "coffee").Return(test.SimpleError)
-			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+				m.EXPECT().IsAvailable("GBP").Return(false)
 			},
 			rater: func(m *mocks.MockRater) {
 				m.EXPECT().TryAcquireExchange().Return(true)
@@ -545,29 +763,25 @@ func Test_controller_AddExpense(t *testing.T) {
 		resp := controller.AddExpense(context.Background(), request.AddExpense{
 			User:     test.User,
 			Date:     test.Today,
-			Amount:   20000,
+			Amount:   15000,
+			Currency: "GBP",
 			Category: "coffee",
 		})
 
 		// ASSERT
 		assert.Equal(t, response.AddExpense{
-			Ready: true,
+			Ready:           true,
+			UnknownCurrency: true,
 		}, resp)
 	})
 
-	t.Run("cannot get limits", func(t *testing.T) {
+	t.Run("unknown account", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
-			expenser: func(m *mocks.MockExpenser) {
-				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(25000), "USD", "coffee").Return(nil)
-			},
-			limiter: func(m *mocks.Mocklimiter) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{}, test.SimpleError)
-			},
-			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "wallet").Return(types.Account{}, ErrNotFound)
 			},
 			rater: func(m *mocks.MockRater) {
 				m.EXPECT().TryAcquireExchange().Return(true)
@@ -579,37 +793,39 @@ func Test_controller_AddExpense(t *testing.T) {
 		resp := controller.AddExpense(context.Background(), request.AddExpense{
 			User:     test.User,
 			Date:     test.Today,
-			Amount:   25000,
+			Amount:   15000,
 			Category: "coffee",
+			Account:  "wallet",
 		})
 
 		// ASSERT
 		assert.Equal(t, response.AddExpense{
-			Ready:   true,
-			Success: true,
+			Ready:          true,
+			UnknownAccount: true,
 		}, resp)
 	})
 
-	t.Run("no limit", func(t *testing.T) {
+	t.Run("account currency", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "savings").Return(types.Account{ID: 7, Name: "savings", Currency: "USD"}, nil)
+			},
 			expenser: func(m *mocks.MockExpenser) {
-				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(30000), "USD", "coffee").Return(nil)
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(30000), "USD", "coffee", int64(7), "", []string(nil)).Return(int64(0), nil)
 			},
 			limiter: func(m *mocks.Mocklimiter) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
-					Total: 0,
-				}, nil)
-			},
-			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{}, nil).Times(2)
 			},
 			rater: func(m *mocks.MockRater) {
 				m.EXPECT().TryAcquireExchange().Return(true)
 				m.EXPECT().ReleaseExchange()
 			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
 		})
 
 		// ACT
@@ -618,6 +834,7 @@ func Test_controller_AddExpense(t *testing.T) {
 			Date:     test.Today,
 			Amount:   30000,
 			Category: "coffee",
+			Account:  "savings",
 		})
 
 		// ASSERT
@@ -627,28 +844,30 @@ func Test_controller_AddExpense(t *testing.T) {
 		}, resp)
 	})
 
-	t.Run("cannot exchange for limit", func(t *testing.T) {
+	t.Run("account tag and category alias", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
+			categorizer: func(m *mocks.MockCategorizer) {
+				m.EXPECT().ResolveCategory(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "кофе").Return("food/coffee", nil)
+			},
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "work").Return(types.Account{}, ErrNotFound)
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "savings").Return(types.Account{ID: 7, Name: "savings", Currency: "USD"}, nil)
+			},
 			expenser: func(m *mocks.MockExpenser) {
-				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(35000), "EUR", "coffee").Return(nil)
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(30000), "USD", "food/coffee", int64(7), "with client", []string{"work", "team"}).Return(int64(42), nil)
 			},
 			limiter: func(m *mocks.Mocklimiter) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
-					Total:    60000,
-					Remains:  30000,
-					Currency: "USD",
-				}, nil)
-			},
-			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("EUR", nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "food/coffee").Return(types.LimitItem{}, nil).Times(2)
 			},
 			rater: func(m *mocks.MockRater) {
 				m.EXPECT().TryAcquireExchange().Return(true)
 				m.EXPECT().ReleaseExchange()
-				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(35000), "EUR", "USD", test.Today).Return(int64(0), test.SimpleError)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
 			},
 		})
 
@@ -656,40 +875,47 @@ func Test_controller_AddExpense(t *testing.T) {
 		resp := controller.AddExpense(context.Background(), request.AddExpense{
 			User:     test.User,
 			Date:     test.Today,
-			Amount:   35000,
-			Category: "coffee",
+			Amount:   30000,
+			Category: "кофе",
+			Note:     "with client",
+			Tags:     []string{"work", "savings", "team"},
 		})
 
 		// ASSERT
 		assert.Equal(t, response.AddExpense{
+			ID:      42,
 			Ready:   true,
 			Success: true,
 		}, resp)
 	})
 
-	t.Run("cannot decrease limit", func(t *testing.T) {
+	t.Run("explicit currency", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			expenser: func(m *mocks.MockExpenser) {
-				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(2000000), "RUB", "coffee").Return(nil)
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(125000), "EUR", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
 			},
 			limiter: func(m *mocks.Mocklimiter) {
 				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
-					Total:    60000,
-					Remains:  30000,
-					Currency: "USD",
-				}, nil)
-				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(40000), "coffee").Return(false, test.SimpleError)
+					Total:    1000000,
+					Remains:  1000000,
+					Currency: "RUB",
+				}, nil).Times(2)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(750000), "coffee").Return(false, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{50, 80, 100}, nil)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+				m.EXPECT().IsAvailable("EUR").Return(true)
 			},
 			rater: func(m *mocks.MockRater) {
 				m.EXPECT().TryAcquireExchange().Return(true)
 				m.EXPECT().ReleaseExchange()
-				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(2000000), "RUB", "USD", test.Today).Return(int64(40000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(125000), "EUR", "RUB", test.Today).Return(int64(750000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
 			},
 		})
 
@@ -697,7 +923,8 @@ func Test_controller_AddExpense(t *testing.T) {
 		resp := controller.AddExpense(context.Background(), request.AddExpense{
 			User:     test.User,
 			Date:     test.Today,
-			Amount:   2000000,
+			Amount:   125000,
+			Currency: "EUR",
 			Category: "coffee",
 		})
 
@@ -708,29 +935,20 @@ func Test_controller_AddExpense(t *testing.T) {
 		}, resp)
 	})
 
-	t.Run("limit has been reached", func(t *testing.T) {
+	t.Run("cannot add", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			expenser: func(m *mocks.MockExpenser) {
-				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(2000000), "RUB", "coffee").Return(nil)
-			},
-			limiter: func(m *mocks.Mocklimiter) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
-					Total:    60000,
-					Remains:  30000,
-					Currency: "USD",
-				}, nil)
-				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(40000), "coffee").Return(true, nil)
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(20000), "USD", "coffee", int64(0), "", []string(nil)).Return(int64(0), test.SimpleError)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
 			},
 			rater: func(m *mocks.MockRater) {
 				m.EXPECT().TryAcquireExchange().Return(true)
 				m.EXPECT().ReleaseExchange()
-				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(2000000), "RUB", "USD", test.Today).Return(int64(40000), nil)
 			},
 		})
 
@@ -738,41 +956,33 @@ func Test_controller_AddExpense(t *testing.T) {
 		resp := controller.AddExpense(context.Background(), request.AddExpense{
 			User:     test.User,
 			Date:     test.Today,
-			Amount:   2000000,
+			Amount:   20000,
 			Category: "coffee",
 		})
 
 		// ASSERT
 		assert.Equal(t, response.AddExpense{
-			Ready:        true,
-			LimitReached: true,
-			Success:      true,
+			Ready: true,
 		}, resp)
 	})
 
-	t.Run("limit has not been reached", func(t *testing.T) {
+	t.Run("cannot get limits", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
 			expenser: func(m *mocks.MockExpenser) {
-				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(1000000), "RUB", "coffee").Return(nil)
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(25000), "USD", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
 			},
 			limiter: func(m *mocks.Mocklimiter) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
-					Total:    60000,
-					Remains:  30000,
-					Currency: "USD",
-				}, nil)
-				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(20000), "coffee").Return(false, nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{}, test.SimpleError)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
 			},
 			rater: func(m *mocks.MockRater) {
 				m.EXPECT().TryAcquireExchange().Return(true)
 				m.EXPECT().ReleaseExchange()
-				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(1000000), "RUB", "USD", test.Today).Return(int64(20000), nil)
 			},
 		})
 
@@ -780,131 +990,2804 @@ func Test_controller_AddExpense(t *testing.T) {
 		resp := controller.AddExpense(context.Background(), request.AddExpense{
 			User:     test.User,
 			Date:     test.Today,
-			Amount:   1000000,
+			Amount:   25000,
 			Category: "coffee",
 		})
 
 		// ASSERT
 		assert.Equal(t, response.AddExpense{
-			Ready:        true,
-			LimitReached: false,
-			Success:      true,
+			Ready: true,
 		}, resp)
 	})
-}
 
-func Test_controller_GetReport(t *testing.T) {
-	t.Run("no currency", func(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(30000), "USD", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total: 0,
+				}, nil).Times(2)
+			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("", test.SimpleError)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
 			},
 		})
 
 		// ACT
-		resp := controller.GetReport(context.Background(), request.GetReport{
-			User: test.User,
-			From: test.Today,
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   30000,
+			Category: "coffee",
 		})
 
 		// ASSERT
-		assert.Equal(t, response.GetReport{
-			From:  test.Today,
-			Ready: false,
+		assert.Equal(t, response.AddExpense{
+			Ready:   true,
+			Success: true,
 		}, resp)
 	})
 
-	t.Run("not ready", func(t *testing.T) {
+	t.Run("cannot exchange for limit", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
-			reporter: func(m *mocks.MockReporter) {
-				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, "RUB").Return(nil, ErrNotReady)
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(35000), "EUR", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  30000,
+					Currency: "USD",
+				}, nil)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("EUR", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(35000), "EUR", "USD", test.Today).Return(int64(0), test.SimpleError)
 			},
 		})
 
 		// ACT
-		resp := controller.GetReport(context.Background(), request.GetReport{
-			User: test.User,
-			From: test.Today,
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   35000,
+			Category: "coffee",
 		})
 
 		// ASSERT
-		assert.Equal(t, response.GetReport{
-			From:     test.Today,
-			Currency: "RUB",
-			Ready:    false,
+		assert.Equal(t, response.AddExpense{
+			Ready: true,
 		}, resp)
 	})
 
-	t.Run("ready error", func(t *testing.T) {
+	t.Run("cannot decrease limit", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(2000000), "RUB", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  30000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(40000), "coffee").Return(false, test.SimpleError)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(2000000), "RUB", "USD", test.Today).Return(int64(40000), nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   2000000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready: true,
+		}, resp)
+	})
+
+	t.Run("limit has been reached", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(2000000), "RUB", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  30000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(40000), "coffee").Return(true, nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  0,
+					Currency: "USD",
+					Notified: 50,
+				}, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{50, 80, 100}, nil)
+				m.EXPECT().Notify(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 100, "coffee").Return(true, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(2000000), "RUB", "USD", test.Today).Return(int64(40000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   2000000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready:      true,
+			LimitAlert: 100,
+			Success:    true,
+		}, resp)
+	})
+
+	t.Run("group limit", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		owner := types.User(101)
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(2000000), "RUB", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().GetGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 1,
+					Members: []types.GroupMember{
+						{User: &owner, Role: types.RoleOwner},
+						{User: test.User, Role: types.RoleMember},
+					},
+				}, nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), &owner, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  30000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), &owner, int64(40000), "coffee").Return(true, nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), &owner, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  0,
+					Currency: "USD",
+					Notified: 50,
+				}, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), &owner).Return([]int{50, 80, 100}, nil)
+				m.EXPECT().Notify(gomock.AssignableToTypeOf(test.CtxInterface), &owner, 100, "coffee").Return(true, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(2000000), "RUB", "USD", test.Today).Return(int64(40000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   2000000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready:      true,
+			LimitAlert: 100,
+			Success:    true,
+		}, resp)
+	})
+
+	t.Run("split", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		alice, bob := types.User(101), types.User(102)
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(33334), "USD", "restaurant", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().GetGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 1,
+					Members: []types.GroupMember{
+						{User: test.User, Name: "@tester", Role: types.RoleOwner},
+						{User: &alice, Name: "@alice", Role: types.RoleMember},
+						{User: &bob, Name: "Bob", Role: types.RoleMember},
+					},
+				}, nil).Times(2)
+			},
+			debtor: func(m *mocks.MockDebtor) {
+				m.EXPECT().AddDebt(gomock.AssignableToTypeOf(test.CtxInterface), types.Debt{
+					Date: test.Today, Creditor: test.User, CreditorName: "@tester", Debtor: &alice, DebtorName: "@alice",
+					Amount: 33333, Currency: "USD", Category: "restaurant",
+				}).Return(nil)
+				m.EXPECT().AddDebt(gomock.AssignableToTypeOf(test.CtxInterface), types.Debt{
+					Date: test.Today, Creditor: test.User, CreditorName: "@tester", Debtor: &bob, DebtorName: "Bob",
+					Amount: 33333, Currency: "USD", Category: "restaurant",
+				}).Return(nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "restaurant").Return(types.LimitItem{
+					Total:    1000000,
+					Remains:  1000000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(33334), "restaurant").Return(false, nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "restaurant").Return(types.LimitItem{
+					Total:    1000000,
+					Remains:  966666,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{50}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(33334), "USD", "USD", test.Today).Return(int64(33334), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   100000,
+			Category: "restaurant",
+			Split:    []string{"@alice", "bob", "@Alice", "@tester"},
+		})
+
+		// ASSERT
+		assert.True(t, resp.Success)
+		assert.Len(t, resp.Debts, 2)
+	})
+
+	t.Run("split with unknown member", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().GetGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID:      1,
+					Members: []types.GroupMember{{User: test.User, Name: "@tester", Role: types.RoleOwner}},
+				}, nil).Times(2)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   100000,
+			Category: "restaurant",
+			Split:    []string{"@carol"},
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready:         true,
+			UnknownMember: true,
+		}, resp)
+	})
+
+	t.Run("threshold has been alerted already", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(1000000), "RUB", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  30000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(20000), "coffee").Return(false, nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  10000,
+					Currency: "USD",
+					Notified: 80,
+				}, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{50, 80, 100}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(1000000), "RUB", "USD", test.Today).Return(int64(20000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   1000000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready:   true,
+			Success: true,
+		}, resp)
+	})
+	t.Run("outbox event carries expense id", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		var event types.OutboxEvent
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(30000), "USD", "coffee", int64(0), "", []string(nil)).Return(int64(42), nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{}, nil).Times(2)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).DoAndReturn(
+					func(_ context.Context, e types.OutboxEvent) error {
+						event = e
+						return nil
+					},
+				)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   30000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.True(t, resp.Success)
+		assert.Equal(t, _eventExpenseAdded, event.Kind)
+		assert.Contains(t, string(event.Payload), `"id":42`)
+	})
+
+	t.Run("cannot write outbox event", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(2000000), "RUB", "coffee", int64(0), "", []string(nil)).Return(int64(0), nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  30000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(40000), "coffee").Return(true, nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  20000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{50, 80, 100}, nil)
+				m.EXPECT().Notify(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 50, "coffee").Return(true, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(2000000), "RUB", "USD", test.Today).Return(int64(40000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   2000000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready: true,
+		}, resp)
+	})
+
+	t.Run("transaction failed", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			unitOfWork: func(m *mocks.MockunitOfWork) {
+				m.EXPECT().Do(gomock.AssignableToTypeOf(test.CtxInterface), gomock.Any()).Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   2000000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready: true,
+		}, resp)
+	})
+
+	t.Run("receipt", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		receipt := &types.Receipt{Drive: "9960440300000001", Document: "12345", Sign: "1234567890"}
+
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().ImportExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, []types.ImportedExpense{{
+					Expense: types.Expense{
+						ExpenseItem: types.ExpenseItem{Date: test.Yesterday, Amount: 12345000, Currency: "RUB"},
+						Category:    "groceries",
+					},
+					Hash: "fns:9960440300000001:12345:1234567890",
+				}}).Return([]types.Expense{{
+					ExpenseItem: types.ExpenseItem{ID: 1, Date: test.Yesterday, Amount: 12345000, Currency: "RUB"},
+					Category:    "groceries",
+				}}, nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "groceries").Return(types.LimitItem{}, nil).Times(2)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("RUB").Return(true)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Yesterday,
+			Amount:   12345000,
+			Currency: "RUB",
+			Category: "groceries",
+			Receipt:  receipt,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			ID:      1,
+			Ready:   true,
+			Success: true,
+		}, resp)
+	})
+
+	t.Run("duplicate receipt", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().ImportExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.Len(1)).Return([]types.Expense{}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("RUB").Return(true)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.AddExpense(context.Background(), request.AddExpense{
+			User:     test.User,
+			Date:     test.Yesterday,
+			Amount:   12345000,
+			Currency: "RUB",
+			Category: "groceries",
+			Receipt:  &types.Receipt{Drive: "1", Document: "2", Sign: "3"},
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddExpense{
+			Ready:     true,
+			Duplicate: true,
+		}, resp)
+	})
+}
+
+func Test_controller_AddIncome(t *testing.T) {
+	t.Run("unknown currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("GBP").Return(false)
+			},
+		})
+
+		// ACT
+		resp := controller.AddIncome(context.Background(), request.AddIncome{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   1500000000,
+			Currency: "GBP",
+			Category: "salary",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddIncome{
+			UnknownCurrency: true,
+		}, resp)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			incomer: func(m *mocks.MockIncomer) {
+				m.EXPECT().AddIncome(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(1500000000), "RUB", "salary", int64(0)).Return(test.SimpleError)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddIncome(context.Background(), request.AddIncome{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   1500000000,
+			Category: "salary",
+		})
+
+		// ASSERT
+		assert.Empty(t, resp)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			incomer: func(m *mocks.MockIncomer) {
+				m.EXPECT().AddIncome(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Tomorrow, int64(1500000000), "RUB", "salary", int64(0)).Return(errors.Wrap(ErrFutureDate, "validateIncome"))
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddIncome(context.Background(), request.AddIncome{
+			User:     test.User,
+			Date:     test.Tomorrow,
+			Amount:   1500000000,
+			Category: "salary",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddIncome{
+			Invalid: ErrFutureDate,
+		}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			incomer: func(m *mocks.MockIncomer) {
+				m.EXPECT().AddIncome(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, int64(5000000), "USD", "freelance", int64(0)).Return(nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("USD").Return(true)
+			},
+		})
+
+		// ACT
+		resp := controller.AddIncome(context.Background(), request.AddIncome{
+			User:     test.User,
+			Date:     test.Today,
+			Amount:   5000000,
+			Currency: "USD",
+			Category: "freelance",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddIncome{
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_ListIncomes(t *testing.T) {
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			incomer: func(m *mocks.MockIncomer) {
+				m.EXPECT().ListLastIncomes(gomock.AssignableToTypeOf(test.CtxInterface), test.User, _lastExpensesCount).Return(nil, test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.ListIncomes(context.Background(), request.ListIncomes{
+			User: test.User,
+		})
+
+		// ASSERT
+		assert.Empty(t, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		list := []types.Income{
+			{
+				ExpenseItem: types.ExpenseItem{ID: 1, Date: test.Today, Amount: 1500000000, Currency: "RUB"},
+				Category:    "salary",
+			},
+		}
+		controller := setupController(t, controllerMocksInitializer{
+			incomer: func(m *mocks.MockIncomer) {
+				m.EXPECT().ListLastIncomes(gomock.AssignableToTypeOf(test.CtxInterface), test.User, _lastExpensesCount).Return(list, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.ListIncomes(context.Background(), request.ListIncomes{
+			User: test.User,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ListIncomes{
+			List:    list,
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_ListExpenses(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().ListLastExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, _lastExpensesCount).Return(nil, test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.ListExpenses(context.Background(), request.ListExpenses{
+			User: test.User,
+		})
+
+		// ASSERT
+		assert.Empty(t, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		list := []types.Expense{
+			{
+				ExpenseItem: types.ExpenseItem{ID: 7, Date: test.Today, Amount: 10000, Currency: "RUB"},
+				Category:    "coffee",
+			},
+		}
+
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().ListLastExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, _lastExpensesCount).Return(list, nil)
+			},
+			attacher: func(m *mocks.MockAttacher) {
+				m.EXPECT().ListAttached(gomock.AssignableToTypeOf(test.CtxInterface), test.User, []int64{7}).Return(map[int64]bool{7: true}, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.ListExpenses(context.Background(), request.ListExpenses{
+			User: test.User,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ListExpenses{
+			List:     list,
+			Attached: map[int64]bool{7: true},
+			Success:  true,
+		}, resp)
+	})
+}
+
+func Test_controller_AttachToExpense(t *testing.T) {
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			attacher: func(m *mocks.MockAttacher) {
+				m.EXPECT().Attach(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(7), []byte("photo")).Return(ErrTooLarge)
+			},
+		})
+
+		// ACT
+		resp := controller.AttachToExpense(context.Background(), request.AttachToExpense{
+			User:      test.User,
+			ExpenseID: 7,
+			Data:      []byte("photo"),
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AttachToExpense{
+			TooLarge: true,
+		}, resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			attacher: func(m *mocks.MockAttacher) {
+				m.EXPECT().Attach(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(7), []byte("photo")).Return(ErrNotFound)
+			},
+		})
+
+		// ACT
+		resp := controller.AttachToExpense(context.Background(), request.AttachToExpense{
+			User:      test.User,
+			ExpenseID: 7,
+			Data:      []byte("photo"),
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AttachToExpense{
+			NotFound: true,
+		}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			attacher: func(m *mocks.MockAttacher) {
+				m.EXPECT().Attach(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(7), []byte("photo")).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AttachToExpense(context.Background(), request.AttachToExpense{
+			User:      test.User,
+			ExpenseID: 7,
+			Data:      []byte("photo"),
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AttachToExpense{
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_GetAttachment(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			attacher: func(m *mocks.MockAttacher) {
+				m.EXPECT().GetAttachment(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(7)).Return(nil, test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.GetAttachment(context.Background(), request.GetAttachment{
+			User:      test.User,
+			ExpenseID: 7,
+		})
+
+		// ASSERT
+		assert.Empty(t, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			attacher: func(m *mocks.MockAttacher) {
+				m.EXPECT().GetAttachment(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(7)).Return([]byte("photo"), nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetAttachment(context.Background(), request.GetAttachment{
+			User:      test.User,
+			ExpenseID: 7,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetAttachment{
+			Data:    []byte("photo"),
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_GetExpense(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1)).Return(types.Expense{}, ErrNotFound)
+			},
+		})
+
+		// ACT
+		resp := controller.GetExpense(context.Background(), request.GetExpense{
+			User: test.User,
+			ID:   1,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetExpense{
+			NotFound: true,
+		}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		item := types.Expense{
+			ExpenseItem: types.ExpenseItem{ID: 2, Date: test.Today, Amount: 10000, Currency: "RUB"},
+			Category:    "coffee",
+		}
+
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2)).Return(item, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetExpense(context.Background(), request.GetExpense{
+			User: test.User,
+			ID:   2,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetExpense{
+			Item:    item,
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_UpdateExpense(t *testing.T) {
+	t.Run("not ready", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(false)
+			},
+		})
+
+		// ACT
+		resp := controller.UpdateExpense(context.Background(), request.UpdateExpense{
+			User:     test.User,
+			ID:       1,
+			Date:     test.Today,
+			Amount:   10000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Empty(t, resp)
+	})
+
+	t.Run("unknown currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("GBP").Return(false)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.UpdateExpense(context.Background(), request.UpdateExpense{
+			User:     test.User,
+			ID:       1,
+			Date:     test.Today,
+			Amount:   10000,
+			Currency: "GBP",
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.UpdateExpense{
+			Ready:           true,
+			UnknownCurrency: true,
+		}, resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1)).Return(types.Expense{}, ErrNotFound)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.UpdateExpense(context.Background(), request.UpdateExpense{
+			User:     test.User,
+			ID:       1,
+			Date:     test.Today,
+			Amount:   10000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.UpdateExpense{
+			Ready:    true,
+			NotFound: true,
+		}, resp)
+	})
+
+	t.Run("cannot update", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2)).Return(types.Expense{
+					ExpenseItem: types.ExpenseItem{ID: 2, Date: test.Yesterday, Amount: 20000, Currency: "EUR"},
+					Category:    "taxi",
+				}, nil)
+				m.EXPECT().UpdateExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2), test.Today, int64(10000), "EUR", "coffee").Return(test.SimpleError)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.UpdateExpense(context.Background(), request.UpdateExpense{
+			User:     test.User,
+			ID:       2,
+			Date:     test.Today,
+			Amount:   10000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.UpdateExpense{
+			Ready: true,
+		}, resp)
+	})
+
+	t.Run("limit recharged", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(types.Expense{
+					ExpenseItem: types.ExpenseItem{ID: 3, Date: test.Yesterday, Amount: 20000, Currency: "EUR"},
+					Category:    "taxi",
+				}, nil)
+				m.EXPECT().UpdateExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3), test.Today, int64(30000), "EUR", "coffee").Return(nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  10000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Increase(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(22000), "taxi").Return(nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  30000,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(33000), "coffee").Return(true, nil)
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(types.LimitItem{
+					Total:    60000,
+					Remains:  0,
+					Currency: "USD",
+				}, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{80}, nil)
+				m.EXPECT().Notify(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 80, "coffee").Return(true, nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(20000), "EUR", "USD", test.Yesterday).Return(int64(22000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(30000), "EUR", "USD", test.Today).Return(int64(33000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.UpdateExpense(context.Background(), request.UpdateExpense{
+			User:     test.User,
+			ID:       3,
+			Date:     test.Today,
+			Amount:   30000,
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.UpdateExpense{
+			Ready:      true,
+			LimitAlert: 80,
+			Success:    true,
+		}, resp)
+	})
+}
+
+func Test_controller_DeleteExpense(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1)).Return(types.Expense{}, ErrNotFound)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.DeleteExpense(context.Background(), request.DeleteExpense{
+			User: test.User,
+			ID:   1,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.DeleteExpense{
+			Ready:    true,
+			NotFound: true,
+		}, resp)
+	})
+
+	t.Run("without limit", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2)).Return(types.Expense{
+					ExpenseItem: types.ExpenseItem{ID: 2, Date: test.Yesterday, Amount: 20000, Currency: "RUB"},
+					Category:    "taxi",
+				}, nil)
+				m.EXPECT().DeleteExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2)).Return(nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(types.LimitItem{}, nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.DeleteExpense(context.Background(), request.DeleteExpense{
+			User: test.User,
+			ID:   2,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.DeleteExpense{
+			Ready:   true,
+			Success: true,
+		}, resp)
+	})
+
+	t.Run("limit of the past period not refunded", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(types.Expense{
+					ExpenseItem: types.ExpenseItem{ID: 3, Date: test.Yesterday, Amount: 20000, Currency: "RUB"},
+					Category:    "taxi",
+				}, nil)
+				m.EXPECT().DeleteExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(types.LimitItem{
+					Total:    50000,
+					Remains:  50000,
+					Currency: "RUB",
+					Anchor:   test.Today,
+				}, nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.DeleteExpense(context.Background(), request.DeleteExpense{
+			User: test.User,
+			ID:   3,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.DeleteExpense{
+			Ready:   true,
+			Success: true,
+		}, resp)
+	})
+
+	t.Run("limit refunded", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(types.Expense{
+					ExpenseItem: types.ExpenseItem{ID: 3, Date: test.Yesterday, Amount: 20000, Currency: "RUB"},
+					Category:    "taxi",
+				}, nil)
+				m.EXPECT().DeleteExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(types.LimitItem{
+					Total:    50000,
+					Remains:  0,
+					Currency: "RUB",
+				}, nil)
+				m.EXPECT().Increase(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(20000), "taxi").Return(nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(20000), "RUB", "RUB", test.Yesterday).Return(int64(20000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.DeleteExpense(context.Background(), request.DeleteExpense{
+			User: test.User,
+			ID:   3,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.DeleteExpense{
+			Ready:   true,
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_AddAccount(t *testing.T) {
+	t.Run("duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().AddAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "card", "RUB", int64(0)).Return(ErrAlreadyExists)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddAccount(context.Background(), request.AddAccount{
+			User: test.User,
+			Name: "card",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddAccount{
+			Duplicate: true,
+		}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().AddAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "savings", "USD", int64(10000000)).Return(nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("USD").Return(true)
+			},
+		})
+
+		// ACT
+		resp := controller.AddAccount(context.Background(), request.AddAccount{
+			User:     test.User,
+			Name:     "savings",
+			Currency: "USD",
+			Opening:  10000000,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddAccount{
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_GetBalance(t *testing.T) {
+	t.Run("not ready", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(false)
+			},
+		})
+
+		// ACT
+		resp := controller.GetBalance(context.Background(), request.GetBalance{
+			User: test.User,
+		})
+
+		// ASSERT
+		assert.Empty(t, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		list := []types.AccountBalance{
+			{Account: types.Account{ID: 2, Name: "savings", Currency: "USD"}, Balance: 1000000, Converted: 60000000},
+		}
+		controller := setupController(t, controllerMocksInitializer{
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().ListBalances(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "RUB", test.Today).Return(list, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.GetBalance(context.Background(), request.GetBalance{
+			User: test.User,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetBalance{
+			Currency: "RUB",
+			Ready:    true,
+			List:     list,
+			Success:  true,
+		}, resp)
+	})
+}
+
+func Test_controller_Transfer(t *testing.T) {
+	card := types.Account{ID: 1, Name: "card", Currency: "RUB"}
+	savings := types.Account{ID: 2, Name: "savings", Currency: "USD"}
+
+	t.Run("unknown account", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "card").Return(card, nil)
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "wallet").Return(types.Account{}, ErrNotFound)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.Transfer(context.Background(), request.Transfer{
+			User:   test.User,
+			Date:   test.Today,
+			From:   "card",
+			To:     "wallet",
+			Amount: 6000000,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.Transfer{
+			Ready:    true,
+			NotFound: true,
+			From:     card,
+		}, resp)
+	})
+
+	t.Run("exchanged", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "card").Return(card, nil)
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "savings").Return(savings, nil)
+				m.EXPECT().Transfer(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.Transfer{
+					Date:     test.Today,
+					From:     1,
+					To:       2,
+					Amount:   6000000,
+					Received: 10000,
+				}).Return(nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(6000000), "RUB", "USD", test.Today).Return(int64(10000), nil)
+			},
+		})
+
+		// ACT
+		resp := controller.Transfer(context.Background(), request.Transfer{
+			User:   test.User,
+			Date:   test.Today,
+			From:   "card",
+			To:     "savings",
+			Amount: 6000000,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.Transfer{
+			Ready:    true,
+			From:     card,
+			To:       savings,
+			Received: 10000,
+			Success:  true,
+		}, resp)
+	})
+
+	t.Run("explicit received amount", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			accounter: func(m *mocks.MockAccounter) {
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "card").Return(card, nil)
+				m.EXPECT().GetAccount(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "savings").Return(savings, nil)
+				m.EXPECT().Transfer(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.Transfer{
+					Date:     test.Today,
+					From:     1,
+					To:       2,
+					Amount:   6150000,
+					Received: 10000,
+				}).Return(nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.Transfer(context.Background(), request.Transfer{
+			User:     test.User,
+			Date:     test.Today,
+			From:     "card",
+			To:       "savings",
+			Amount:   6150000,
+			Received: 10000,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.Transfer{
+			Ready:    true,
+			From:     card,
+			To:       savings,
+			Received: 10000,
+			Success:  true,
+		}, resp)
+	})
+}
+
+func Test_controller_GetReport(t *testing.T) {
+	t.Run("no currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("", test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.GetReport(context.Background(), request.GetReport{
+			User: test.User,
+			From: test.Today,
+			To:   test.Tomorrow,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetReport{
+			From:  test.Today,
+			To:    test.Tomorrow,
+			Ready: false,
+		}, resp)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			reporter: func(m *mocks.MockReporter) {
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "RUB").Return(types.ReportData{}, ErrNotReady)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetReport(context.Background(), request.GetReport{
+			User: test.User,
+			From: test.Today,
+			To:   test.Tomorrow,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetReport{
+			From:     test.Today,
+			To:       test.Tomorrow,
+			Currency: "RUB",
+			Ready:    false,
+		}, resp)
+	})
+
+	t.Run("ready error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			reporter: func(m *mocks.MockReporter) {
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "RUB").Return(types.ReportData{}, test.SimpleError)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetReport(context.Background(), request.GetReport{
+			User: test.User,
+			From: test.Today,
+			To:   test.Tomorrow,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetReport{
+			From:     test.Today,
+			To:       test.Tomorrow,
+			Currency: "RUB",
+			Ready:    true,
+		}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		expectedResp := response.GetReport{
+			From:     test.Today,
+			To:       test.Tomorrow,
+			Currency: "USD",
+			Ready:    true,
+			Data: map[string]int64{
+				"coffee": 20000,
+				"taxi":   130000,
+			},
+			Success: true,
+		}
+
+		controller := setupController(t, controllerMocksInitializer{
+			reporter: func(m *mocks.MockReporter) {
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "USD").Return(types.ReportData{
+					Data: map[string]int64{
+						"coffee": 20000,
+						"taxi":   130000,
+					},
+				}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetReport(context.Background(), request.GetReport{
+			User: test.User,
+			From: test.Today,
+			To:   test.Tomorrow,
+		})
+
+		// ASSERT
+		assert.Equal(t, expectedResp, resp)
+	})
+
+	t.Run("success grouped", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		buckets := []types.ReportBucket{
+			{Start: test.Yesterday, Data: map[string]int64{"taxi": 130000}},
+			{Start: test.Today, Data: map[string]int64{"coffee": 20000}},
+		}
+
+		controller := setupController(t, controllerMocksInitializer{
+			reporter: func(m *mocks.MockReporter) {
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, test.Tomorrow, types.GroupingDay, types.DateRange{}, "USD").Return(types.ReportData{
+					Data: map[string]int64{
+						"coffee": 20000,
+						"taxi":   130000,
+					},
+					Buckets: buckets,
+				}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetReport(context.Background(), request.GetReport{
+			User:     test.User,
+			From:     test.Yesterday,
+			To:       test.Tomorrow,
+			Grouping: types.GroupingDay,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetReport{
+			From:     test.Yesterday,
+			To:       test.Tomorrow,
+			Grouping: types.GroupingDay,
+			Currency: "USD",
+			Ready:    true,
+			Data: map[string]int64{
+				"coffee": 20000,
+				"taxi":   130000,
+			},
+			Buckets: buckets,
+			Success: true,
+		}, resp)
+	})
+
+	t.Run("success compared", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		compare := types.DateRange{From: test.Yesterday, To: test.Today}
+
+		controller := setupController(t, controllerMocksInitializer{
+			reporter: func(m *mocks.MockReporter) {
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, test.Tomorrow, types.GroupingNone, compare, "USD").Return(types.ReportData{
+					Data:     map[string]int64{"coffee": 20000},
+					Previous: map[string]int64{"taxi": 130000},
+				}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetReport(context.Background(), request.GetReport{
+			User:    test.User,
+			From:    test.Today,
+			To:      test.Tomorrow,
+			Compare: compare,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetReport{
+			From:     test.Today,
+			To:       test.Tomorrow,
+			Compare:  compare,
+			Currency: "USD",
+			Ready:    true,
+			Data:     map[string]int64{"coffee": 20000},
+			Previous: map[string]int64{"taxi": 130000},
+			Success:  true,
+		}, resp)
+	})
+
+	t.Run("success rolled up", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			reporter: func(m *mocks.MockReporter) {
+				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "USD").Return(types.ReportData{
+					Data: map[string]int64{
+						"food/coffee":     20000,
+						"food/restaurant": 50000,
+						"taxi":            130000,
+					},
+				}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.GetReport(context.Background(), request.GetReport{
+			User:   test.User,
+			From:   test.Today,
+			To:     test.Tomorrow,
+			RollUp: true,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.GetReport{
+			From:     test.Today,
+			To:       test.Tomorrow,
+			Currency: "USD",
+			Ready:    true,
+			Data: map[string]int64{
+				"food": 70000,
+				"taxi": 130000,
+			},
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_ExportExpenses(t *testing.T) {
+	t.Run("not ready", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(false)
+			},
+		})
+
+		// ACT
+		resp := controller.ExportExpenses(context.Background(), request.ExportExpenses{
+			User:   test.User,
+			From:   test.Yesterday,
+			Format: types.ExportCSV,
+			Writer: io.Discard,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ExportExpenses{}, resp)
+	})
+
+	t.Run("export error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			exporter: func(m *mocks.MockExporter) {
+				m.EXPECT().Export(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, time.Time{}, types.ExportCSV, "RUB", io.Discard).Return(0, test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.ExportExpenses(context.Background(), request.ExportExpenses{
+			User:   test.User,
+			From:   test.Yesterday,
+			Format: types.ExportCSV,
+			Writer: io.Discard,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ExportExpenses{
+			Currency: "RUB",
+			Ready:    true,
+		}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			},
+			exporter: func(m *mocks.MockExporter) {
+				m.EXPECT().Export(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, test.Tomorrow, types.ExportXLSX, "USD", io.Discard).Return(3, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.ExportExpenses(context.Background(), request.ExportExpenses{
+			User:   test.User,
+			From:   test.Yesterday,
+			To:     test.Tomorrow,
+			Format: types.ExportXLSX,
+			Writer: io.Discard,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ExportExpenses{
+			Currency: "USD",
+			Ready:    true,
+			Count:    3,
+			Success:  true,
+		}, resp)
+	})
+}
+
+func Test_controller_ImportExpenses(t *testing.T) {
+	statement := []byte("date,amount,merchant\n")
+
+	imported := func(date time.Time, amount int64, category, hash string) types.ImportedExpense {
+		return types.ImportedExpense{
+			Expense: types.Expense{
+				ExpenseItem: types.ExpenseItem{Date: date, Amount: amount, Currency: "RUB"},
+				Category:    category,
+			},
+			Hash: hash,
+		}
+	}
+
+	t.Run("unknown statement", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			importer: func(m *mocks.MockImporter) {
+				m.EXPECT().Parse(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "statement.pdf", "", statement).Return(nil, ErrUnknownStatement)
+			},
+		})
+
+		// ACT
+		resp := controller.ImportExpenses(context.Background(), request.ImportExpenses{
+			User: test.User,
+			Name: "statement.pdf",
+			Data: statement,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ImportExpenses{
+			Ready:            true,
+			UnknownStatement: true,
+		}, resp)
+	})
+
+	t.Run("import error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		items := []types.ImportedExpense{imported(test.Today, 100000, "coffee", "h1")}
+
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+			importer: func(m *mocks.MockImporter) {
+				m.EXPECT().Parse(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "statement.csv", "tinkoff", statement).Return(items, nil)
+			},
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().ImportExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, items).Return(nil, test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.ImportExpenses(context.Background(), request.ImportExpenses{
+			User: test.User,
+			Name: "statement.csv",
+			Bank: "tinkoff",
+			Data: statement,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ImportExpenses{
+			Ready:  true,
+			Parsed: 1,
+		}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		items := []types.ImportedExpense{
+			imported(test.Yesterday, 100000, "coffee", "h1"),
+			imported(test.Today, 150000, "coffee", "h2"),
+			imported(test.Today, 3000000, "taxi", "h3"),
+			imported(test.Today, 500000, "cinema", "h4"),
+		}
+
+		controller := setupController(t, controllerMocksInitializer{
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(150000), "RUB", "RUB", test.Today).Return(int64(150000), nil)
+			},
+			importer: func(m *mocks.MockImporter) {
+				m.EXPECT().Parse(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "statement.csv", "", statement).Return(items, nil)
+			},
+			expenser: func(m *mocks.MockExpenser) {
+				// the taxi expense has been imported before
+				m.EXPECT().ImportExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, items).Return([]types.Expense{
+					{ExpenseItem: types.ExpenseItem{ID: 1, Date: test.Yesterday, Amount: 100000, Currency: "RUB"}, Category: "coffee"},
+					{ExpenseItem: types.ExpenseItem{ID: 2, Date: test.Today, Amount: 150000, Currency: "RUB"}, Category: "coffee"},
+					{ExpenseItem: types.ExpenseItem{ID: 3, Date: test.Today, Amount: 500000, Currency: "RUB"}, Category: "cinema"},
+				}, nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				// the expense of yesterday has been spent in the previous period
+				coffee := types.LimitItem{Total: 200000, Remains: 200000, Currency: "RUB", Anchor: test.Today}
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(coffee, nil).Times(2)
+				m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(150000), "coffee").Return(false, nil)
+				coffee.Remains = 50000
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "coffee").Return(coffee, nil)
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{50, 80, 100}, nil)
+				m.EXPECT().Notify(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 50, "coffee").Return(true, nil)
+
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "cinema").Return(types.LimitItem{}, nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil).Times(3)
+			},
+		})
+
+		// ACT
+		resp := controller.ImportExpenses(context.Background(), request.ImportExpenses{
+			User: test.User,
+			Name: "statement.csv",
+			Data: statement,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.ImportExpenses{
+			Ready:       true,
+			Parsed:      4,
+			Imported:    3,
+			LimitAlerts: map[string]int{"coffee": 50},
+			Success:     true,
+		}, resp)
+	})
+}
+
+func Test_controller_SetMerchantRule(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			importer: func(m *mocks.MockImporter) {
+				m.EXPECT().SetRule(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "starbucks", "coffee").Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.SetMerchantRule(context.Background(), request.SetMerchantRule{
+			User:     test.User,
+			Pattern:  "starbucks",
+			Category: "coffee",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetMerchantRule{Success: true}, resp)
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			importer: func(m *mocks.MockImporter) {
+				m.EXPECT().DeleteRule(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "starbucks").Return(ErrNotFound)
+			},
+		})
+
+		// ACT
+		resp := controller.SetMerchantRule(context.Background(), request.SetMerchantRule{
+			User:    test.User,
+			Pattern: "starbucks",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetMerchantRule{NotFound: true}, resp)
+	})
+}
+
+func Test_controller_AddRecurring(t *testing.T) {
+	recurrence := types.Recurrence{
+		Frequency: types.FrequencyMonthly,
+		Interval:  1,
+		Start:     time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("unknown currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("XXX").Return(false)
+			},
+		})
+
+		// ACT
+		resp := controller.AddRecurring(context.Background(), request.AddRecurring{
+			User:       test.User,
+			Recurrence: recurrence,
+			Amount:     300000000,
+			Currency:   "XXX",
+			Category:   "rent",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddRecurring{UnknownCurrency: true}, resp)
+	})
+
+	t.Run("user currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		item := types.RecurringExpense{
+			Recurrence: recurrence,
+			Amount:     300000000,
+			Currency:   "RUB",
+			Category:   "rent",
+		}
+
+		added := item
+		added.ID = 1
+		added.Next = recurrence.Start
+
+		controller := setupController(t, controllerMocksInitializer{
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+			recurrer: func(m *mocks.MockRecurrer) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.User, item).Return(added, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddRecurring(context.Background(), request.AddRecurring{
+			User:       test.User,
+			Recurrence: recurrence,
+			Amount:     300000000,
+			Category:   "rent",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddRecurring{Item: added, Success: true}, resp)
+	})
+}
+
+func Test_controller_SetRecurringPaused(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			recurrer: func(m *mocks.MockRecurrer) {
+				m.EXPECT().SetPaused(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1), true).Return(types.RecurringExpense{}, ErrNotFound)
+			},
+		})
+
+		// ACT
+		resp := controller.SetRecurringPaused(context.Background(), request.SetRecurringPaused{
+			User:   test.User,
+			ID:     1,
+			Paused: true,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetRecurringPaused{NotFound: true}, resp)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			recurrer: func(m *mocks.MockRecurrer) {
+				m.EXPECT().SetPaused(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1), false).Return(types.RecurringExpense{}, test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.SetRecurringPaused(context.Background(), request.SetRecurringPaused{
+			User: test.User,
+			ID:   1,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetRecurringPaused{}, resp)
+	})
+}
+
+func Test_controller_JoinGroup(t *testing.T) {
+	t.Run("unknown code", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().JoinGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "ABCD2345", "@bob").Return(types.Group{}, ErrNotFound)
+			},
+		})
+
+		// ACT
+		resp := controller.JoinGroup(context.Background(), request.JoinGroup{
+			User: test.User,
+			Code: "ABCD2345",
+			Name: "@bob",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.JoinGroup{NotFound: true}, resp)
+	})
+
+	t.Run("already member", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().JoinGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "ABCD2345", "@bob").Return(types.Group{}, ErrAlreadyExists)
+			},
+		})
+
+		// ACT
+		resp := controller.JoinGroup(context.Background(), request.JoinGroup{
+			User: test.User,
+			Code: "ABCD2345",
+			Name: "@bob",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.JoinGroup{AlreadyMember: true}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		group := types.Group{ID: 1, Code: "ABCD2345"}
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().JoinGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "ABCD2345", "@bob").Return(group, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.JoinGroup(context.Background(), request.JoinGroup{
+			User: test.User,
+			Code: "ABCD2345",
+			Name: "@bob",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.JoinGroup{Group: group, Success: true}, resp)
+	})
+}
+
+func Test_controller_RemoveGroupMember(t *testing.T) {
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().RemoveMember(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "@alice").Return(ErrForbidden)
+			},
+		})
+
+		// ACT
+		resp := controller.RemoveGroupMember(context.Background(), request.RemoveGroupMember{
+			User: test.User,
+			Name: "@alice",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.RemoveGroupMember{Forbidden: true}, resp)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().RemoveMember(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "@bob").Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.RemoveGroupMember(context.Background(), request.RemoveGroupMember{
+			User: test.User,
+			Name: "@bob",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.RemoveGroupMember{}, resp)
+	})
+}
+
+func Test_controller_SettleDebts(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			debtor: func(m *mocks.MockDebtor) {
+				m.EXPECT().SettleDebts(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "@alice", "").Return(nil, ErrNotFound)
+			},
+		})
+
+		// ACT
+		resp := controller.SettleDebts(context.Background(), request.SettleDebts{
+			User: test.User,
+			Name: "@alice",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SettleDebts{NotFound: true}, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		alice := types.User(101)
+		settled := []types.DebtBalance{{Counterparty: &alice, Name: "@alice", Currency: "RUB", Amount: 10000000}}
+		controller := setupController(t, controllerMocksInitializer{
+			debtor: func(m *mocks.MockDebtor) {
+				m.EXPECT().SettleDebts(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "@alice", "RUB").Return(settled, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.SettleDebts(context.Background(), request.SettleDebts{
+			User:     test.User,
+			Name:     "@alice",
+			Currency: "RUB",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SettleDebts{Settled: settled, Success: true}, resp)
+	})
+}
+
+func Test_controller_AddGoal(t *testing.T) {
+	t.Run("unknown currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().IsAvailable("XYZ").Return(false)
+			},
+		})
+
+		// ACT
+		resp := controller.AddGoal(context.Background(), request.AddGoal{
+			User:     test.User,
+			Name:     "vacation",
+			Target:   2000000000,
+			Currency: "XYZ",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddGoal{UnknownCurrency: true}, resp)
+	})
+
+	t.Run("user currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		deadline := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+		controller := setupController(t, controllerMocksInitializer{
+			saver: func(m *mocks.MockSaver) {
+				m.EXPECT().AddGoal(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.Goal{
+					Name:     "vacation",
+					Target:   2000000000,
+					Currency: "RUB",
+					Deadline: deadline,
+				}).Return(nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
+			},
+		})
+
+		// ACT
+		resp := controller.AddGoal(context.Background(), request.AddGoal{
+			User:     test.User,
+			Name:     "vacation",
+			Target:   2000000000,
+			Deadline: deadline,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.AddGoal{Success: true}, resp)
+	})
+}
+
+func Test_controller_ListGoals(t *testing.T) {
+	t.Run("not ready", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
 		controller := setupController(t, controllerMocksInitializer{
-			reporter: func(m *mocks.MockReporter) {
-				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, "RUB").Return(nil, test.SimpleError)
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(false)
+			},
+		})
+
+		// ACT
+		resp := controller.ListGoals(context.Background(), request.ListGoals{
+			User: test.User,
+		})
+
+		// ASSERT
+		assert.Empty(t, resp)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		list := []types.GoalProgress{{Goal: types.Goal{ID: 3, Name: "vacation", Target: 2000000000, Currency: "USD"}, Saved: 10000, Converted: 600000}}
+		controller := setupController(t, controllerMocksInitializer{
+			saver: func(m *mocks.MockSaver) {
+				m.EXPECT().ListGoals(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "RUB", gomock.AssignableToTypeOf(time.Time{})).Return(list, nil)
 			},
 			currencyManager: func(m *mocks.MockcurrencyManager) {
 				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("RUB", nil)
 			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
 		})
 
 		// ACT
-		resp := controller.GetReport(context.Background(), request.GetReport{
+		resp := controller.ListGoals(context.Background(), request.ListGoals{
 			User: test.User,
-			From: test.Today,
 		})
 
 		// ASSERT
-		assert.Equal(t, response.GetReport{
-			From:     test.Today,
+		assert.Equal(t, response.ListGoals{
 			Currency: "RUB",
 			Ready:    true,
+			List:     list,
+			Success:  true,
 		}, resp)
 	})
+}
 
-	t.Run("success", func(t *testing.T) {
+func Test_controller_FindExpenses(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
 		t.Parallel()
 
 		// ARRANGE
-		expectedResp := response.GetReport{
-			From:     test.Today,
-			Currency: "USD",
+		filter := types.ExpenseFilter{Query: "coffee"}
+
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().FindExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, filter, 0, 5).Return(nil, false, test.SimpleError)
+			},
+		})
+
+		// ACT
+		resp := controller.FindExpenses(context.Background(), request.FindExpenses{
+			User:   test.User,
+			Filter: filter,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.FindExpenses{}, resp)
+	})
+
+	t.Run("second page", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		filter := types.ExpenseFilter{Query: "coffee"}
+		list := []types.Expense{{ExpenseItem: types.ExpenseItem{ID: 6}, Category: "coffee"}}
+
+		controller := setupController(t, controllerMocksInitializer{
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().FindExpenses(gomock.AssignableToTypeOf(test.CtxInterface), test.User, filter, 5, 5).Return(list, true, nil)
+			},
+		})
+
+		// ACT
+		resp := controller.FindExpenses(context.Background(), request.FindExpenses{
+			User:   test.User,
+			Filter: filter,
+			Page:   1,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.FindExpenses{
+			List:    list,
+			Page:    1,
+			More:    true,
+			Success: true,
+		}, resp)
+	})
+}
+
+func Test_controller_SetCategoryAlias(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			categorizer: func(m *mocks.MockCategorizer) {
+				m.EXPECT().SetAlias(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi", "taxi").Return(ErrInvalidCategory)
+			},
+		})
+
+		// ACT
+		resp := controller.SetCategoryAlias(context.Background(), request.SetCategoryAlias{
+			User:     test.User,
+			Alias:    "taxi",
+			Category: "taxi",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetCategoryAlias{Invalid: true}, resp)
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			categorizer: func(m *mocks.MockCategorizer) {
+				m.EXPECT().DeleteAlias(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "такси").Return(ErrNotFound)
+			},
+		})
+
+		// ACT
+		resp := controller.SetCategoryAlias(context.Background(), request.SetCategoryAlias{
+			User:  test.User,
+			Alias: "такси",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.SetCategoryAlias{NotFound: true}, resp)
+	})
+}
+
+func Test_controller_RenameCategory(t *testing.T) {
+	t.Run("already exists", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			categorizer: func(m *mocks.MockCategorizer) {
+				m.EXPECT().RenameCategory(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi", "transport/taxi", false).Return(ErrAlreadyExists)
+			},
+		})
+
+		// ACT
+		resp := controller.RenameCategory(context.Background(), request.RenameCategory{
+			User: test.User,
+			From: "taxi",
+			To:   "transport/taxi",
+		})
+
+		// ASSERT
+		assert.Equal(t, response.RenameCategory{AlreadyExists: true}, resp)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			categorizer: func(m *mocks.MockCategorizer) {
+				m.EXPECT().RenameCategory(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "такси", "transport/taxi", true).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.RenameCategory(context.Background(), request.RenameCategory{
+			User:  test.User,
+			From:  "такси",
+			To:    "transport/taxi",
+			Merge: true,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.RenameCategory{Success: true}, resp)
+	})
+}
+
+func Test_controller_Undo(t *testing.T) {
+	t.Run("nothing to undo", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			journaler: func(m *mocks.MockJournaler) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0)).Return(types.Operation{}, ErrNotFound)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.Undo(context.Background(), request.Undo{User: test.User})
+
+		// ASSERT
+		assert.Equal(t, response.Undo{
 			Ready:    true,
-			Data: map[string]int64{
-				"coffee": 20000,
-				"taxi":   130000,
+			NotFound: true,
+		}, resp)
+	})
+
+	t.Run("operation is not the last one", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			journaler: func(m *mocks.MockJournaler) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(9)).Return(types.Operation{}, ErrNotFound)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
+		})
+
+		// ACT
+		resp := controller.Undo(context.Background(), request.Undo{
+			User:      test.User,
+			Operation: 9,
+		})
+
+		// ASSERT
+		assert.Equal(t, response.Undo{
+			Ready:    true,
+			NotFound: true,
+		}, resp)
+	})
+
+	t.Run("added expense", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		creditor, debtor := types.User(101), types.User(102)
+		controller := setupController(t, controllerMocksInitializer{
+			journaler: func(m *mocks.MockJournaler) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0)).Return(types.Operation{
+					Kind:      types.OperationAddExpense,
+					ExpenseID: 3,
+					Debts: []types.Debt{
+						{Creditor: &creditor, Debtor: &debtor, Amount: 10000, Currency: "RUB"},
+					},
+				}, nil)
+			},
+			expenser: func(m *mocks.MockExpenser) {
+				m.EXPECT().GetExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(types.Expense{
+					ExpenseItem: types.ExpenseItem{ID: 3, Date: test.Yesterday, Amount: 20000, Currency: "RUB"},
+					Category:    "taxi",
+				}, nil)
+				m.EXPECT().DeleteExpense(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(nil)
+			},
+			debtor: func(m *mocks.MockDebtor) {
+				m.EXPECT().AddDebt(gomock.AssignableToTypeOf(test.CtxInterface), types.Debt{
+					Creditor: &debtor,
+					Debtor:   &creditor,
+					Amount:   10000,
+					Currency: "RUB",
+				}).Return(nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(types.LimitItem{
+					Total:    50000,
+					Remains:  0,
+					Currency: "RUB",
+				}, nil)
+				m.EXPECT().Increase(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(20000), "taxi").Return(nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(20000), "RUB", "RUB", test.Yesterday).Return(int64(20000), nil)
+			},
+			outbox: func(m *mocks.Mockoutbox) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(types.OutboxEvent{})).Return(nil)
+			},
+		})
+
+		// ACT
+		resp := controller.Undo(context.Background(), request.Undo{User: test.User})
+
+		// ASSERT
+		assert.Equal(t, response.Undo{
+			Ready:   true,
+			Success: true,
+			Kind:    types.OperationAddExpense,
+		}, resp)
+	})
+
+	t.Run("set limit", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		previous := types.LimitItem{Total: 50000, Remains: 12000, Currency: "RUB"}
+		controller := setupController(t, controllerMocksInitializer{
+			journaler: func(m *mocks.MockJournaler) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0)).Return(types.Operation{
+					Kind:     types.OperationSetLimit,
+					Category: "taxi",
+					Limit:    previous,
+				}, nil)
+			},
+			limiter: func(m *mocks.Mocklimiter) {
+				m.EXPECT().Restore(gomock.AssignableToTypeOf(test.CtxInterface), test.User, previous, "taxi").Return(nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
 			},
+		})
+
+		// ACT
+		resp := controller.Undo(context.Background(), request.Undo{User: test.User})
+
+		// ASSERT
+		assert.Equal(t, response.Undo{
+			Ready:   true,
 			Success: true,
-		}
+			Kind:    types.OperationSetLimit,
+		}, resp)
+	})
+
+	t.Run("set limit by group member", func(t *testing.T) {
+		t.Parallel()
 
+		// ARRANGE
+		owner := types.User(101)
 		controller := setupController(t, controllerMocksInitializer{
-			reporter: func(m *mocks.MockReporter) {
-				m.EXPECT().GetReport(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, "USD").Return(map[string]int64{
-					"coffee": 20000,
-					"taxi":   130000,
+			journaler: func(m *mocks.MockJournaler) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0)).Return(types.Operation{
+					Kind:     types.OperationSetLimit,
+					Category: "taxi",
 				}, nil)
 			},
-			currencyManager: func(m *mocks.MockcurrencyManager) {
-				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return("USD", nil)
+			grouper: func(m *mocks.MockGrouper) {
+				m.EXPECT().GetGroup(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 1,
+					Members: []types.GroupMember{
+						{User: &owner, Role: types.RoleOwner},
+						{User: test.User, Role: types.RoleMember},
+					},
+				}, nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
 			},
 		})
 
 		// ACT
-		resp := controller.GetReport(context.Background(), request.GetReport{
-			User: test.User,
-			From: test.Today,
+		resp := controller.Undo(context.Background(), request.Undo{User: test.User})
+
+		// ASSERT
+		assert.Equal(t, response.Undo{
+			Ready:     true,
+			Forbidden: true,
+		}, resp)
+	})
+
+	t.Run("set currency", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		controller := setupController(t, controllerMocksInitializer{
+			journaler: func(m *mocks.MockJournaler) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0)).Return(types.Operation{
+					Kind:     types.OperationSetCurrency,
+					Currency: "USD",
+				}, nil)
+			},
+			currencyManager: func(m *mocks.MockcurrencyManager) {
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "USD").Return(nil)
+			},
+			rater: func(m *mocks.MockRater) {
+				m.EXPECT().TryAcquireExchange().Return(true)
+				m.EXPECT().ReleaseExchange()
+			},
 		})
 
+		// ACT
+		resp := controller.Undo(context.Background(), request.Undo{User: test.User})
+
 		// ASSERT
-		assert.Equal(t, expectedResp, resp)
+		assert.Equal(t, response.Undo{
+			Ready:   true,
+			Success: true,
+			Kind:    types.OperationSetCurrency,
+		}, resp)
 	})
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 )
@@ -59,15 +60,21 @@ func (m *currencyManager) Set(ctx context.Context, user *types.User, curr string
 	})
 	defer span.Finish()
 
+	if m.IsAvailable(curr) {
+		return m.storage.Set(ctx, user, curr)
+	}
+
+	return model.ErrUnknownCurrency
+}
+
+func (m *currencyManager) IsAvailable(curr string) bool {
 	for _, c := range m.currencies {
-		if c.code != curr {
-			continue
+		if c.code == curr {
+			return true
 		}
-
-		return m.storage.Set(ctx, user, curr)
 	}
 
-	return errors.New("указана неизвестная валюта")
+	return false
 }
 
 func (m *currencyManager) ListCurrenciesCodesWithFlags() []string {
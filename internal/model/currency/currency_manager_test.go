@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
 	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
 )
 
@@ -87,7 +88,7 @@ func Test_manager_Set(t *testing.T) {
 		err := m.Set(context.Background(), test.User, "RUB")
 
 		// ASSERT
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, model.ErrUnknownCurrency)
 	})
 
 	t.Run("error", func(t *testing.T) {
@@ -138,3 +139,27 @@ func Test_manager_ListCurrenciesCodesWithFlags(t *testing.T) {
 		}, list)
 	})
 }
+
+func Test_manager_IsAvailable(t *testing.T) {
+	t.Run("available", func(t *testing.T) {
+		// ARRANGE
+		m := setupCurrencyManager(t, test.DefaultCurrencyCfg, currencyManagerMocksInitializer{})
+
+		// ACT
+		available := m.IsAvailable("EUR")
+
+		// ASSERT
+		assert.True(t, available)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		// ARRANGE
+		m := setupCurrencyManager(t, test.DefaultCurrencyCfg, currencyManagerMocksInitializer{})
+
+		// ACT
+		available := m.IsAvailable("GBP")
+
+		// ASSERT
+		assert.False(t, available)
+	})
+}
@@ -8,14 +8,11 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
 	"go.uber.org/zap"
 )
 
-var (
-	errCannotExchange = errors.New("не удалось выполнить конвертацию валюты")
-)
-
 type gateway interface {
 	FetchRates(ctx context.Context) (map[string]int64, time.Time, error)
 }
@@ -120,7 +117,7 @@ func (r *rater) getRate(ctx context.Context, from string, date time.Time) (int64
 	if err != nil {
 		return 0, errors.Wrap(err, "CurrencyRatesStorage.Get")
 	} else if !ok {
-		return 0, errCannotExchange
+		return 0, model.ErrNoExchangeRate
 	}
 
 	return rate, nil
@@ -12,6 +12,7 @@ import (
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
 	cmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model/currency"
 	smocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
 	"go.uber.org/zap"
 )
@@ -163,7 +164,7 @@ func Test_rater_Exchange(t *testing.T) {
 		)
 
 		// ASSERT
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, model.ErrNoExchangeRate)
 		assert.Equal(t, int64(0), value)
 	})
 
@@ -208,7 +209,7 @@ func Test_rater_Exchange(t *testing.T) {
 		)
 
 		// ASSERT
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, model.ErrNoExchangeRate)
 		assert.Equal(t, int64(0), value)
 	})
 
@@ -0,0 +1,163 @@
+package expense
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type accounter struct {
+	storage storage.AccountStorage
+	rater   model.Rater
+}
+
+func NewAccounter(s storage.AccountStorage, r model.Rater) *accounter {
+	return &accounter{
+		storage: s,
+		rater:   r,
+	}
+}
+
+// AddAccount adds the account, names are case-insensitive and unique per user.
+func (a *accounter) AddAccount(ctx context.Context, user *types.User, name, currency string, opening int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "accounter.AddAccount", opentracing.Tags{
+		"user":     *user,
+		"name":     name,
+		"currency": currency,
+		"opening":  opening,
+	})
+	defer span.Finish()
+
+	name = normalizeAccountName(name)
+	if name == "" {
+		return model.ErrEmptyName
+	}
+
+	added, err := a.storage.Add(ctx, user, types.Account{
+		Name:     name,
+		Currency: currency,
+		Opening:  opening,
+	})
+	if err != nil {
+		return errors.Wrap(err, "AccountStorage.Add")
+	}
+
+	if !added {
+		return model.ErrAlreadyExists
+	}
+
+	return nil
+}
+
+func (a *accounter) ListAccounts(ctx context.Context, user *types.User) ([]types.Account, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "accounter.ListAccounts", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	list, err := a.storage.List(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "AccountStorage.List")
+	}
+
+	return list, nil
+}
+
+func (a *accounter) GetAccount(ctx context.Context, user *types.User, name string) (types.Account, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "accounter.GetAccount", opentracing.Tags{
+		"user": *user,
+		"name": name,
+	})
+	defer span.Finish()
+
+	account, ok, err := a.storage.GetByName(ctx, user, normalizeAccountName(name))
+	if err != nil {
+		return types.Account{}, errors.Wrap(err, "AccountStorage.GetByName")
+	}
+
+	if !ok {
+		return types.Account{}, model.ErrNotFound
+	}
+
+	return account, nil
+}
+
+func (a *accounter) Transfer(ctx context.Context, user *types.User, transfer types.Transfer) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "accounter.Transfer", opentracing.Tags{
+		"user":     *user,
+		"from":     transfer.From,
+		"to":       transfer.To,
+		"amount":   transfer.Amount,
+		"received": transfer.Received,
+	})
+	defer span.Finish()
+
+	switch {
+	case transfer.From == transfer.To:
+		return model.ErrSameAccount
+	case transfer.Amount <= 0 || transfer.Received <= 0:
+		return model.ErrNonPositiveAmount
+	}
+
+	return errors.Wrap(a.storage.AddTransfer(ctx, user, transfer), "AccountStorage.AddTransfer")
+}
+
+// ListBalances returns balances of the user accounts in their currencies, the movements are exchanged
+// at the rates of their dates. The balances are converted into the currency at the rate of the date.
+func (a *accounter) ListBalances(ctx context.Context, user *types.User, currency string, date time.Time) ([]types.AccountBalance, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "accounter.ListBalances", opentracing.Tags{
+		"user":     *user,
+		"currency": currency,
+		"date":     date,
+	})
+	defer span.Finish()
+
+	accounts, err := a.storage.List(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "AccountStorage.List")
+	}
+
+	totals, err := a.storage.Turnover(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "AccountStorage.Turnover")
+	}
+
+	balances := make([]types.AccountBalance, 0, len(accounts))
+	indexes := make(map[int64]int, len(accounts))
+	for i, account := range accounts {
+		balances = append(balances, types.AccountBalance{Account: account, Balance: account.Opening})
+		indexes[account.ID] = i
+	}
+
+	for _, total := range totals {
+		i, ok := indexes[total.Account]
+		if !ok {
+			continue
+		}
+
+		amount, err := a.rater.Exchange(ctx, total.Amount, total.Currency, balances[i].Currency, total.Date)
+		if err != nil {
+			return nil, errors.Wrapf(err, "exchange %s to %s", total.Currency, balances[i].Currency)
+		}
+
+		balances[i].Balance += amount
+	}
+
+	for i := range balances {
+		if balances[i].Converted, err = a.rater.Exchange(ctx, balances[i].Balance, balances[i].Currency, currency, date); err != nil {
+			return nil, errors.Wrapf(err, "exchange %s to %s", balances[i].Currency, currency)
+		}
+	}
+
+	return balances, nil
+}
+
+func normalizeAccountName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+}
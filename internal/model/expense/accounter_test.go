@@ -0,0 +1,182 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type accounterMocksInitializer struct {
+	storage func(m *mocks.MockAccountStorage)
+	rater   func(m *mmocks.MockRater)
+}
+
+func setupAccounter(t *testing.T, i accounterMocksInitializer) *accounter {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockAccountStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	raterMock := mmocks.NewMockRater(ctrl)
+	if i.rater != nil {
+		i.rater(raterMock)
+	}
+
+	return NewAccounter(storageMock, raterMock)
+}
+
+func Test_accounter_AddAccount(t *testing.T) {
+	t.Run("duplicate", func(t *testing.T) {
+		// ARRANGE
+		a := setupAccounter(t, accounterMocksInitializer{
+			storage: func(m *mocks.MockAccountStorage) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.Account{
+					Name:     "card",
+					Currency: "RUB",
+				}).Return(false, nil)
+			},
+		})
+
+		// ACT
+		err := a.AddAccount(context.Background(), test.User, "Card", "RUB", 0)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		a := setupAccounter(t, accounterMocksInitializer{
+			storage: func(m *mocks.MockAccountStorage) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.Account{
+					Name:     "savings",
+					Currency: "USD",
+					Opening:  10000000,
+				}).Return(true, nil)
+			},
+		})
+
+		// ACT
+		err := a.AddAccount(context.Background(), test.User, " #savings", "USD", 10000000)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
+func Test_accounter_GetAccount(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		// ARRANGE
+		a := setupAccounter(t, accounterMocksInitializer{
+			storage: func(m *mocks.MockAccountStorage) {
+				m.EXPECT().GetByName(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "cash").Return(types.Account{}, false, nil)
+			},
+		})
+
+		// ACT
+		_, err := a.GetAccount(context.Background(), test.User, "Cash")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+}
+
+func Test_accounter_Transfer(t *testing.T) {
+	t.Run("same account", func(t *testing.T) {
+		// ARRANGE
+		a := setupAccounter(t, accounterMocksInitializer{})
+
+		// ACT
+		err := a.Transfer(context.Background(), test.User, types.Transfer{Date: test.Today, From: 1, To: 1, Amount: 10000, Received: 10000})
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrSameAccount)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		transfer := types.Transfer{Date: test.Today, From: 1, To: 2, Amount: 6150000, Received: 1000000}
+		a := setupAccounter(t, accounterMocksInitializer{
+			storage: func(m *mocks.MockAccountStorage) {
+				m.EXPECT().AddTransfer(gomock.AssignableToTypeOf(test.CtxInterface), test.User, transfer).Return(nil)
+			},
+		})
+
+		// ACT
+		err := a.Transfer(context.Background(), test.User, transfer)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
+func Test_accounter_ListBalances(t *testing.T) {
+	accounts := []types.Account{
+		{ID: 1, Name: "card", Currency: "RUB", Opening: 500000000},
+		{ID: 2, Name: "savings", Currency: "USD"},
+	}
+
+	t.Run("exchange error", func(t *testing.T) {
+		// ARRANGE
+		a := setupAccounter(t, accounterMocksInitializer{
+			storage: func(m *mocks.MockAccountStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(accounts, nil)
+				m.EXPECT().Turnover(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]types.AccountTotal{
+					{Account: 2, Date: test.Yesterday, Currency: "EUR", Amount: 10000},
+				}, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(10000), "EUR", "USD", test.Yesterday).Return(int64(0), test.SimpleError)
+			},
+		})
+
+		// ACT
+		list, err := a.ListBalances(context.Background(), test.User, "RUB", test.Today)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		a := setupAccounter(t, accounterMocksInitializer{
+			storage: func(m *mocks.MockAccountStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(accounts, nil)
+				m.EXPECT().Turnover(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]types.AccountTotal{
+					{Account: 1, Date: test.Yesterday, Currency: "RUB", Amount: -6150000},
+					{Account: 1, Date: test.Today, Currency: "USD", Amount: -10000},
+					{Account: 2, Date: test.Yesterday, Currency: "USD", Amount: 1000000},
+				}, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(-6150000), "RUB", "RUB", test.Yesterday).Return(int64(-6150000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(-10000), "USD", "RUB", test.Today).Return(int64(-600000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(1000000), "USD", "USD", test.Yesterday).Return(int64(1000000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(493250000), "RUB", "RUB", test.Today).Return(int64(493250000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(1000000), "USD", "RUB", test.Today).Return(int64(60000000), nil)
+			},
+		})
+
+		// ACT
+		list, err := a.ListBalances(context.Background(), test.User, "RUB", test.Today)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.AccountBalance{
+			{Account: accounts[0], Balance: 493250000, Converted: 493250000},
+			{Account: accounts[1], Balance: 1000000, Converted: 60000000},
+		}, list)
+	})
+}
@@ -0,0 +1,201 @@
+package expense
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	_defaultMaxAttachmentSize         = 5 << 20 // 5 MiB
+	_defaultAttachmentCleanupInterval = time.Hour
+)
+
+type attacher struct {
+	maxFileSize int
+	retention   time.Duration
+	interval    time.Duration
+	storage     storage.AttachmentStorage
+	blobs       storage.BlobStorage
+	logger      *zap.Logger
+}
+
+func NewAttacher(cfg config.AttachmentsConfig, s storage.AttachmentStorage, b storage.BlobStorage, l *zap.Logger) *attacher {
+	maxFileSize := cfg.MaxFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = _defaultMaxAttachmentSize
+	}
+
+	interval := cfg.CleanupInterval
+	if interval == 0 {
+		interval = _defaultAttachmentCleanupInterval
+	}
+
+	return &attacher{
+		maxFileSize: maxFileSize,
+		retention:   cfg.Retention,
+		interval:    interval,
+		storage:     s,
+		blobs:       b,
+		logger:      l,
+	}
+}
+
+// Attach stores the file under a new key and attaches it to the expense, the file of the previous attachment
+// is deleted once it's replaced. Files are never overwritten, so that a failure leaves the previous attachment intact.
+func (a *attacher) Attach(ctx context.Context, user *types.User, expenseID int64, data []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "attacher.Attach", opentracing.Tags{
+		"user":       *user,
+		"expense_id": expenseID,
+		"size":       len(data),
+	})
+	defer span.Finish()
+
+	if len(data) > a.maxFileSize {
+		return model.ErrTooLarge
+	}
+
+	previous, found, err := a.storage.Get(ctx, user, expenseID)
+	if err != nil {
+		return errors.Wrap(err, "AttachmentStorage.Get")
+	}
+
+	key, err := newAttachmentKey(user, expenseID)
+	if err != nil {
+		return errors.Wrap(err, "generate attachment key")
+	}
+
+	if err = a.blobs.Put(ctx, key, data); err != nil {
+		return errors.Wrap(err, "BlobStorage.Put")
+	}
+
+	attached, err := a.storage.Set(ctx, user, types.Attachment{
+		ExpenseID: expenseID,
+		Key:       key,
+		Size:      int64(len(data)),
+		Created:   time.Now(),
+	})
+	if err != nil || !attached {
+		a.deleteBlob(ctx, key)
+
+		if err != nil {
+			return errors.Wrap(err, "AttachmentStorage.Set")
+		}
+
+		return model.ErrNotFound
+	}
+
+	if found {
+		a.deleteBlob(ctx, previous.Key)
+	}
+
+	return nil
+}
+
+func (a *attacher) GetAttachment(ctx context.Context, user *types.User, expenseID int64) ([]byte, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "attacher.GetAttachment", opentracing.Tags{
+		"user":       *user,
+		"expense_id": expenseID,
+	})
+	defer span.Finish()
+
+	item, found, err := a.storage.Get(ctx, user, expenseID)
+	if err != nil {
+		return nil, errors.Wrap(err, "AttachmentStorage.Get")
+	}
+
+	if !found {
+		return nil, model.ErrNotFound
+	}
+
+	data, err := a.blobs.Get(ctx, item.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "BlobStorage.Get")
+	}
+
+	return data, nil
+}
+
+func (a *attacher) ListAttached(ctx context.Context, user *types.User, ids []int64) (map[int64]bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "attacher.ListAttached", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	attached, err := a.storage.ListAttached(ctx, user, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "AttachmentStorage.ListAttached")
+	}
+
+	result := make(map[int64]bool, len(attached))
+	for _, id := range attached {
+		result[id] = true
+	}
+
+	return result, nil
+}
+
+// Run removes expired attachments along with the ones left by deleted expenses every interval.
+func (a *attacher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		a.cleanup(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup deletes the attachments before their files, so that an attachment never refers to a missing file;
+// the files failed to be deleted are left behind.
+func (a *attacher) cleanup(ctx context.Context, now time.Time) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "attacher.cleanup")
+	defer span.Finish()
+
+	var before time.Time
+	if a.retention > 0 {
+		before = now.Add(-a.retention)
+	}
+
+	keys, err := a.storage.DeleteExpired(ctx, before)
+	if err != nil {
+		a.logger.Error("cannot delete expired attachments", zap.Error(err))
+		return
+	}
+
+	for _, key := range keys {
+		a.deleteBlob(ctx, key)
+	}
+}
+
+func (a *attacher) deleteBlob(ctx context.Context, key string) {
+	if err := a.blobs.Delete(ctx, key); err != nil {
+		a.logger.Error("cannot delete attachment file", zap.Error(err), zap.String("key", key))
+	}
+}
+
+// newAttachmentKey generates a unique key of the file grouping the files by users.
+func newAttachmentKey(user *types.User, expenseID int64) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d/%d-%s.jpg", int64(*user), expenseID, hex.EncodeToString(suffix)), nil
+}
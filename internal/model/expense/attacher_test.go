@@ -0,0 +1,163 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+type attacherMocksInitializer struct {
+	storage func(m *mocks.MockAttachmentStorage)
+	blobs   func(m *mocks.MockBlobStorage)
+}
+
+func setupAttacher(t *testing.T, cfg config.AttachmentsConfig, i attacherMocksInitializer) *attacher {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockAttachmentStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	blobsMock := mocks.NewMockBlobStorage(ctrl)
+	if i.blobs != nil {
+		i.blobs(blobsMock)
+	}
+
+	return NewAttacher(cfg, storageMock, blobsMock, zap.NewNop())
+}
+
+func Test_attacher_Attach(t *testing.T) {
+	t.Run("too large", func(t *testing.T) {
+		// ARRANGE
+		a := setupAttacher(t, config.AttachmentsConfig{MaxFileSize: 4}, attacherMocksInitializer{})
+
+		// ACT
+		err := a.Attach(context.Background(), test.User, 7, []byte("photo"))
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrTooLarge)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// ARRANGE
+		var key string
+
+		a := setupAttacher(t, config.AttachmentsConfig{}, attacherMocksInitializer{
+			storage: func(m *mocks.MockAttachmentStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(7)).Return(types.Attachment{}, false, nil)
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.Any()).Return(false, nil)
+			},
+			blobs: func(m *mocks.MockBlobStorage) {
+				m.EXPECT().Put(gomock.AssignableToTypeOf(test.CtxInterface), gomock.Any(), []byte("photo")).DoAndReturn(func(_ context.Context, k string, _ []byte) error {
+					key = k
+					return nil
+				})
+				m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), gomock.Any()).DoAndReturn(func(_ context.Context, k string) error {
+					assert.Equal(t, key, k)
+					return nil
+				})
+			},
+		})
+
+		// ACT
+		err := a.Attach(context.Background(), test.User, 7, []byte("photo"))
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		// ARRANGE
+		var key string
+
+		a := setupAttacher(t, config.AttachmentsConfig{}, attacherMocksInitializer{
+			storage: func(m *mocks.MockAttachmentStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(7)).Return(types.Attachment{
+					ExpenseID: 7,
+					Key:       "123/7-old.jpg",
+				}, true, nil)
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.Any()).DoAndReturn(func(_ context.Context, _ *types.User, item types.Attachment) (bool, error) {
+					assert.Equal(t, int64(7), item.ExpenseID)
+					assert.Equal(t, key, item.Key)
+					assert.Equal(t, int64(5), item.Size)
+					return true, nil
+				})
+			},
+			blobs: func(m *mocks.MockBlobStorage) {
+				m.EXPECT().Put(gomock.AssignableToTypeOf(test.CtxInterface), gomock.Any(), []byte("photo")).DoAndReturn(func(_ context.Context, k string, _ []byte) error {
+					key = k
+					return nil
+				})
+				m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), "123/7-old.jpg").Return(nil)
+			},
+		})
+
+		// ACT
+		err := a.Attach(context.Background(), test.User, 7, []byte("photo"))
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key, "123/7-"))
+	})
+}
+
+func Test_attacher_ListAttached(t *testing.T) {
+	// ARRANGE
+	a := setupAttacher(t, config.AttachmentsConfig{}, attacherMocksInitializer{
+		storage: func(m *mocks.MockAttachmentStorage) {
+			m.EXPECT().ListAttached(gomock.AssignableToTypeOf(test.CtxInterface), test.User, []int64{7, 8}).Return([]int64{8}, nil)
+		},
+	})
+
+	// ACT
+	attached, err := a.ListAttached(context.Background(), test.User, []int64{7, 8})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{8: true}, attached)
+}
+
+func Test_attacher_cleanup(t *testing.T) {
+	t.Run("retention", func(t *testing.T) {
+		// ARRANGE
+		now := time.Date(2022, 11, 18, 12, 0, 0, 0, time.UTC)
+
+		a := setupAttacher(t, config.AttachmentsConfig{Retention: 24 * time.Hour}, attacherMocksInitializer{
+			storage: func(m *mocks.MockAttachmentStorage) {
+				m.EXPECT().DeleteExpired(gomock.AssignableToTypeOf(test.CtxInterface), now.Add(-24*time.Hour)).Return([]string{"123/7-a.jpg", "123/8-b.jpg"}, nil)
+			},
+			blobs: func(m *mocks.MockBlobStorage) {
+				m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), "123/7-a.jpg").Return(test.SimpleError)
+				m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), "123/8-b.jpg").Return(nil)
+			},
+		})
+
+		// ACT
+		a.cleanup(context.Background(), now)
+	})
+
+	t.Run("forever", func(t *testing.T) {
+		// ARRANGE
+		a := setupAttacher(t, config.AttachmentsConfig{}, attacherMocksInitializer{
+			storage: func(m *mocks.MockAttachmentStorage) {
+				m.EXPECT().DeleteExpired(gomock.AssignableToTypeOf(test.CtxInterface), time.Time{}).Return(nil, nil)
+			},
+		})
+
+		// ACT
+		a.cleanup(context.Background(), time.Now())
+	})
+}
@@ -0,0 +1,140 @@
+package expense
+
+import (
+	"context"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type categorizer struct {
+	storage storage.CategoryStorage
+}
+
+func NewCategorizer(s storage.CategoryStorage) *categorizer {
+	return &categorizer{
+		storage: s,
+	}
+}
+
+// ResolveCategory normalizes the name and replaces an alias with the category it stands for.
+func (c *categorizer) ResolveCategory(ctx context.Context, user *types.User, name string) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "categorizer.ResolveCategory", opentracing.Tags{
+		"user": *user,
+		"name": name,
+	})
+	defer span.Finish()
+
+	name = types.NormalizeCategory(name)
+
+	category, found, err := c.storage.Resolve(ctx, user, name)
+	if err != nil {
+		return "", errors.Wrap(err, "CategoryStorage.Resolve")
+	}
+
+	if !found {
+		return name, nil
+	}
+
+	return category, nil
+}
+
+func (c *categorizer) ListCategories(ctx context.Context, user *types.User) ([]types.Category, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "categorizer.ListCategories", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	list, err := c.storage.List(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "CategoryStorage.List")
+	}
+
+	return list, nil
+}
+
+// SetAlias makes the alias stand for the category, an alias can't stand for itself.
+func (c *categorizer) SetAlias(ctx context.Context, user *types.User, alias, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "categorizer.SetAlias", opentracing.Tags{
+		"user":     *user,
+		"alias":    alias,
+		"category": category,
+	})
+	defer span.Finish()
+
+	alias, category = types.NormalizeCategory(alias), types.NormalizeCategory(category)
+	if alias == "" || category == "" || alias == category {
+		return model.ErrInvalidCategory
+	}
+
+	return errors.Wrap(c.storage.SetAlias(ctx, user, alias, category), "CategoryStorage.SetAlias")
+}
+
+func (c *categorizer) DeleteAlias(ctx context.Context, user *types.User, alias string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "categorizer.DeleteAlias", opentracing.Tags{
+		"user":  *user,
+		"alias": alias,
+	})
+	defer span.Finish()
+
+	deleted, err := c.storage.DeleteAlias(ctx, user, types.NormalizeCategory(alias))
+	if err != nil {
+		return errors.Wrap(err, "CategoryStorage.DeleteAlias")
+	}
+
+	if !deleted {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// RenameCategory moves the category along with its subcategories under the new name through
+// the whole history. Renaming fails if the new category is in use, while merging joins the categories
+// and keeps the old name as an alias of the new one.
+func (c *categorizer) RenameCategory(ctx context.Context, user *types.User, from, to string, merge bool) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "categorizer.RenameCategory", opentracing.Tags{
+		"user":  *user,
+		"from":  from,
+		"to":    to,
+		"merge": merge,
+	})
+	defer span.Finish()
+
+	from, to = types.NormalizeCategory(from), types.NormalizeCategory(to)
+	if from == "" || to == "" || from == to || strings.HasPrefix(to, from+"/") {
+		return model.ErrInvalidCategory
+	}
+
+	if !merge {
+		list, err := c.storage.List(ctx, user)
+		if err != nil {
+			return errors.Wrap(err, "CategoryStorage.List")
+		}
+
+		for _, category := range list {
+			if category.Name == to || strings.HasPrefix(category.Name, to+"/") {
+				return model.ErrAlreadyExists
+			}
+		}
+	}
+
+	renamed, err := c.storage.Rename(ctx, user, from, to)
+	if err != nil {
+		return errors.Wrap(err, "CategoryStorage.Rename")
+	}
+
+	if !renamed {
+		return model.ErrNotFound
+	}
+
+	if merge {
+		return errors.Wrap(c.storage.SetAlias(ctx, user, from, to), "CategoryStorage.SetAlias")
+	}
+
+	return nil
+}
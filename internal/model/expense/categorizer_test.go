@@ -0,0 +1,172 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type categorizerMocksInitializer struct {
+	storage func(m *mocks.MockCategoryStorage)
+}
+
+func setupCategorizer(t *testing.T, i categorizerMocksInitializer) *categorizer {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockCategoryStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	return NewCategorizer(storageMock)
+}
+
+func Test_categorizer_ResolveCategory(t *testing.T) {
+	t.Run("alias", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{
+			storage: func(m *mocks.MockCategoryStorage) {
+				m.EXPECT().Resolve(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "такси").Return("transport/taxi", true, nil)
+			},
+		})
+
+		// ACT
+		category, err := c.ResolveCategory(context.Background(), test.User, " Такси ")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, "transport/taxi", category)
+	})
+
+	t.Run("no alias", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{
+			storage: func(m *mocks.MockCategoryStorage) {
+				m.EXPECT().Resolve(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "transport/bus").Return("", false, nil)
+			},
+		})
+
+		// ACT
+		category, err := c.ResolveCategory(context.Background(), test.User, "Transport / Bus")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, "transport/bus", category)
+	})
+}
+
+func Test_categorizer_SetAlias(t *testing.T) {
+	t.Run("alias of itself", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{})
+
+		// ACT
+		err := c.SetAlias(context.Background(), test.User, "Taxi", "taxi")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrInvalidCategory)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{
+			storage: func(m *mocks.MockCategoryStorage) {
+				m.EXPECT().SetAlias(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "такси", "transport/taxi").Return(nil)
+			},
+		})
+
+		// ACT
+		err := c.SetAlias(context.Background(), test.User, "Такси", "transport/ taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
+func Test_categorizer_DeleteAlias(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{
+			storage: func(m *mocks.MockCategoryStorage) {
+				m.EXPECT().DeleteAlias(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "такси").Return(false, nil)
+			},
+		})
+
+		// ACT
+		err := c.DeleteAlias(context.Background(), test.User, "такси")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+}
+
+func Test_categorizer_RenameCategory(t *testing.T) {
+	t.Run("into itself", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{})
+
+		// ACT
+		err := c.RenameCategory(context.Background(), test.User, "transport", "transport/taxi", false)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrInvalidCategory)
+	})
+
+	t.Run("target exists", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{
+			storage: func(m *mocks.MockCategoryStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]types.Category{
+					{Name: "taxi"},
+					{Name: "transport/taxi/night"},
+				}, nil)
+			},
+		})
+
+		// ACT
+		err := c.RenameCategory(context.Background(), test.User, "taxi", "transport/taxi", false)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{
+			storage: func(m *mocks.MockCategoryStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, nil)
+				m.EXPECT().Rename(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi", "transport/taxi").Return(false, nil)
+			},
+		})
+
+		// ACT
+		err := c.RenameCategory(context.Background(), test.User, "taxi", "transport/taxi", false)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("merge keeps alias", func(t *testing.T) {
+		// ARRANGE
+		c := setupCategorizer(t, categorizerMocksInitializer{
+			storage: func(m *mocks.MockCategoryStorage) {
+				m.EXPECT().Rename(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "такси", "transport/taxi").Return(true, nil)
+				m.EXPECT().SetAlias(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "такси", "transport/taxi").Return(nil)
+			},
+		})
+
+		// ACT
+		err := c.RenameCategory(context.Background(), test.User, "Такси", "transport/taxi", true)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,94 @@
+package expense
+
+import (
+	"context"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type debtor struct {
+	storage storage.DebtStorage
+}
+
+func NewDebtor(s storage.DebtStorage) *debtor {
+	return &debtor{
+		storage: s,
+	}
+}
+
+func (d *debtor) AddDebt(ctx context.Context, debt types.Debt) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "debtor.AddDebt", opentracing.Tags{
+		"creditor": *debt.Creditor,
+		"debtor":   *debt.Debtor,
+		"amount":   debt.Amount,
+		"currency": debt.Currency,
+	})
+	defer span.Finish()
+
+	return errors.Wrap(d.storage.Add(ctx, debt), "DebtStorage.Add")
+}
+
+// ListDebts returns the net amounts of open debts between the user and other users by currencies,
+// the settled ones are left out.
+func (d *debtor) ListDebts(ctx context.Context, user *types.User) ([]types.DebtBalance, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "debtor.ListDebts", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	balances, err := d.storage.Balances(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "DebtStorage.Balances")
+	}
+
+	list := make([]types.DebtBalance, 0, len(balances))
+	for _, balance := range balances {
+		if balance.Amount != 0 {
+			list = append(list, balance)
+		}
+	}
+
+	return list, nil
+}
+
+// SettleDebts closes open debts between the user and the counterparty with the name in the currency
+// (in all currencies when it's empty) and returns the settled balances.
+func (d *debtor) SettleDebts(ctx context.Context, user *types.User, name, currency string) ([]types.DebtBalance, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "debtor.SettleDebts", opentracing.Tags{
+		"user":     *user,
+		"name":     name,
+		"currency": currency,
+	})
+	defer span.Finish()
+
+	balances, err := d.storage.Balances(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "DebtStorage.Balances")
+	}
+
+	name = strings.TrimPrefix(name, "@")
+
+	var settled []types.DebtBalance
+	for _, balance := range balances {
+		if !strings.EqualFold(strings.TrimPrefix(balance.Name, "@"), name) || (currency != "" && balance.Currency != currency) {
+			continue
+		}
+
+		if err := d.storage.Settle(ctx, user, balance.Counterparty, balance.Currency); err != nil {
+			return nil, errors.Wrap(err, "DebtStorage.Settle")
+		}
+
+		settled = append(settled, balance)
+	}
+
+	if len(settled) == 0 {
+		return nil, model.ErrNotFound
+	}
+
+	return settled, nil
+}
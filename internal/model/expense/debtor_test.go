@@ -0,0 +1,113 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type debtorMocksInitializer struct {
+	storage func(m *mocks.MockDebtStorage)
+}
+
+func setupDebtor(t *testing.T, i debtorMocksInitializer) *debtor {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockDebtStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	return NewDebtor(storageMock)
+}
+
+func Test_debtor_ListDebts(t *testing.T) {
+	// ARRANGE
+	alice, bob := types.User(101), types.User(102)
+	d := setupDebtor(t, debtorMocksInitializer{
+		storage: func(m *mocks.MockDebtStorage) {
+			m.EXPECT().Balances(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]types.DebtBalance{
+				{Counterparty: &alice, Name: "@alice", Currency: "RUB", Amount: 10000000},
+				{Counterparty: &alice, Name: "@alice", Currency: "USD", Amount: 0},
+				{Counterparty: &bob, Name: "Bob", Currency: "RUB", Amount: -5000000},
+			}, nil)
+		},
+	})
+
+	// ACT
+	list, err := d.ListDebts(context.Background(), test.User)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []types.DebtBalance{
+		{Counterparty: &alice, Name: "@alice", Currency: "RUB", Amount: 10000000},
+		{Counterparty: &bob, Name: "Bob", Currency: "RUB", Amount: -5000000},
+	}, list)
+}
+
+func Test_debtor_SettleDebts(t *testing.T) {
+	alice, bob := types.User(101), types.User(102)
+	balances := []types.DebtBalance{
+		{Counterparty: &alice, Name: "@alice", Currency: "RUB", Amount: 10000000},
+		{Counterparty: &alice, Name: "@alice", Currency: "USD", Amount: 0},
+		{Counterparty: &bob, Name: "Bob", Currency: "RUB", Amount: -5000000},
+	}
+
+	t.Run("unknown counterparty", func(t *testing.T) {
+		// ARRANGE
+		d := setupDebtor(t, debtorMocksInitializer{
+			storage: func(m *mocks.MockDebtStorage) {
+				m.EXPECT().Balances(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(balances, nil)
+			},
+		})
+
+		// ACT
+		_, err := d.SettleDebts(context.Background(), test.User, "@carol", "")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("all currencies", func(t *testing.T) {
+		// ARRANGE
+		d := setupDebtor(t, debtorMocksInitializer{
+			storage: func(m *mocks.MockDebtStorage) {
+				m.EXPECT().Balances(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(balances, nil)
+				m.EXPECT().Settle(gomock.AssignableToTypeOf(test.CtxInterface), test.User, &alice, "RUB").Return(nil)
+				m.EXPECT().Settle(gomock.AssignableToTypeOf(test.CtxInterface), test.User, &alice, "USD").Return(nil)
+			},
+		})
+
+		// ACT
+		settled, err := d.SettleDebts(context.Background(), test.User, "ALICE", "")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, balances[:2], settled)
+	})
+
+	t.Run("one currency", func(t *testing.T) {
+		// ARRANGE
+		d := setupDebtor(t, debtorMocksInitializer{
+			storage: func(m *mocks.MockDebtStorage) {
+				m.EXPECT().Balances(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(balances, nil)
+				m.EXPECT().Settle(gomock.AssignableToTypeOf(test.CtxInterface), test.User, &bob, "RUB").Return(nil)
+			},
+		})
+
+		// ACT
+		settled, err := d.SettleDebts(context.Background(), test.User, "bob", "RUB")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, balances[2:], settled)
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
@@ -21,28 +22,113 @@ func NewExpenser(s storage.ExpenseStorage) *expenser {
 	}
 }
 
-func (e *expenser) AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string) error {
+func (e *expenser) AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64, note string, tags []string) (int64, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "expenser.Add", opentracing.Tags{
 		"user":     *user,
 		"date":     date,
 		"amount":   amount,
 		"currency": currency,
 		"category": category,
+		"account":  account,
+		"tags":     tags,
 	})
 	defer span.Finish()
 
-	if amount < 0 {
-		return errors.New("сумма трат должна быть положительным числом")
+	if err := validateExpense(date, amount); err != nil {
+		return 0, err
 	}
 
-	if date.After(utils.TruncateToDate(time.Now())) {
-		return errors.New("траты из будущего не поддерживаются")
+	return e.storage.Add(
+		ctx,
+		user,
+		types.ExpenseItem{
+			Date:     date,
+			Amount:   amount,
+			Currency: currency,
+			Account:  account,
+			Note:     note,
+			Tags:     tags,
+		},
+		category,
+	)
+}
+
+func (e *expenser) ListLastExpenses(ctx context.Context, user *types.User, count int) ([]types.Expense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "expenser.ListLast", opentracing.Tags{
+		"user":  *user,
+		"count": count,
+	})
+	defer span.Finish()
+
+	list, err := e.storage.ListLast(ctx, user, count)
+	if err != nil {
+		return nil, errors.Wrap(err, "ExpenseStorage.ListLast")
 	}
 
-	return e.storage.Add(
+	return list, nil
+}
+
+// FindExpenses returns a page of the expenses matching the filter, the latest first,
+// and reports whether there are more of them.
+func (e *expenser) FindExpenses(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "expenser.Find", opentracing.Tags{
+		"user":   *user,
+		"query":  filter.Query,
+		"offset": offset,
+	})
+	defer span.Finish()
+
+	list, err := e.storage.Find(ctx, user, filter, offset, limit+1)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "ExpenseStorage.Find")
+	}
+
+	if len(list) > limit {
+		return list[:limit], true, nil
+	}
+
+	return list, false, nil
+}
+
+func (e *expenser) GetExpense(ctx context.Context, user *types.User, id int64) (types.Expense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "expenser.Get", opentracing.Tags{
+		"user": *user,
+		"id":   id,
+	})
+	defer span.Finish()
+
+	item, found, err := e.storage.Get(ctx, user, id)
+	if err != nil {
+		return types.Expense{}, errors.Wrap(err, "ExpenseStorage.Get")
+	}
+
+	if !found {
+		return types.Expense{}, model.ErrNotFound
+	}
+
+	return item, nil
+}
+
+func (e *expenser) UpdateExpense(ctx context.Context, user *types.User, id int64, date time.Time, amount int64, currency, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "expenser.Update", opentracing.Tags{
+		"user":     *user,
+		"id":       id,
+		"date":     date,
+		"amount":   amount,
+		"currency": currency,
+		"category": category,
+	})
+	defer span.Finish()
+
+	if err := validateExpense(date, amount); err != nil {
+		return err
+	}
+
+	return e.storage.Update(
 		ctx,
 		user,
 		types.ExpenseItem{
+			ID:       id,
 			Date:     date,
 			Amount:   amount,
 			Currency: currency,
@@ -50,3 +136,47 @@ func (e *expenser) AddExpense(ctx context.Context, user *types.User, date time.T
 		category,
 	)
 }
+
+func (e *expenser) DeleteExpense(ctx context.Context, user *types.User, id int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "expenser.Delete", opentracing.Tags{
+		"user": *user,
+		"id":   id,
+	})
+	defer span.Finish()
+
+	return e.storage.Delete(ctx, user, id)
+}
+
+// ImportExpenses adds the imported expenses skipping the ones imported before, it returns the added expenses.
+func (e *expenser) ImportExpenses(ctx context.Context, user *types.User, items []types.ImportedExpense) ([]types.Expense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "expenser.Import", opentracing.Tags{
+		"user":  *user,
+		"count": len(items),
+	})
+	defer span.Finish()
+
+	for _, item := range items {
+		if err := validateExpense(item.Date, item.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	list, err := e.storage.AddImported(ctx, user, items)
+	if err != nil {
+		return nil, errors.Wrap(err, "ExpenseStorage.AddImported")
+	}
+
+	return list, nil
+}
+
+func validateExpense(date time.Time, amount int64) error {
+	if amount < 0 {
+		return model.ErrNonPositiveAmount
+	}
+
+	if date.After(utils.TruncateToDate(time.Now())) {
+		return model.ErrFutureDate
+	}
+
+	return nil
+}
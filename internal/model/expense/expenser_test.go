@@ -9,6 +9,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 )
@@ -34,17 +35,20 @@ func Test_expenser_AddExpenser(t *testing.T) {
 		e := setupExpenser(t, expenserMocksInitializer{})
 
 		// ACT
-		err := e.AddExpense(
+		_, err := e.AddExpense(
 			context.Background(),
 			test.User,
 			test.Today,    // date
 			int64(-10000), // amount
 			"RUB",         // currency
 			"",            // category
+			int64(0),      // account
+			"",            // note
+			nil,           // tags
 		)
 
 		// ASSERT
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, model.ErrNonPositiveAmount)
 	})
 
 	t.Run("feature expense", func(t *testing.T) {
@@ -52,17 +56,20 @@ func Test_expenser_AddExpenser(t *testing.T) {
 		e := setupExpenser(t, expenserMocksInitializer{})
 
 		// ACT
-		err := e.AddExpense(
+		_, err := e.AddExpense(
 			context.Background(),
 			test.User,
 			test.Tomorrow, // date
 			int64(10000),  // amount
 			"RUB",         // currency
 			"",            // category
+			int64(0),      // account
+			"",            // note
+			nil,           // tags
 		)
 
 		// ASSERT
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, model.ErrFutureDate)
 	})
 
 	t.Run("error", func(t *testing.T) {
@@ -73,18 +80,21 @@ func Test_expenser_AddExpenser(t *testing.T) {
 					Date:     test.Today,
 					Amount:   20000,
 					Currency: "RUB",
-				}, "taxi").Return(test.SimpleError)
+				}, "taxi").Return(int64(0), test.SimpleError)
 			},
 		})
 
 		// ACT
-		err := e.AddExpense(
+		_, err := e.AddExpense(
 			context.Background(),
 			test.User,
 			test.Today,   // date
 			int64(20000), // amount
 			"RUB",        // currency
 			"taxi",       // category
+			int64(0),     // account
+			"",           // note
+			nil,          // tags
 		)
 
 		// ASSERT
@@ -99,21 +109,250 @@ func Test_expenser_AddExpenser(t *testing.T) {
 					Date:     test.Today,
 					Amount:   150000,
 					Currency: "RUB",
-				}, "coffee").Return(nil)
+					Account:  3,
+					Note:     "with client",
+					Tags:     []string{"work"},
+				}, "coffee").Return(int64(7), nil)
 			},
 		})
 
 		// ACT
-		err := e.AddExpense(
+		id, err := e.AddExpense(
 			context.Background(),
 			test.User,
 			test.Today,    // date
 			int64(150000), // amount
 			"RUB",         // currency
 			"coffee",      // category
+			int64(3),      // account
+			"with client", // note
+			[]string{"work"},
 		)
 
 		// ASSERT
 		assert.NoError(t, err)
+		assert.Equal(t, int64(7), id)
+	})
+}
+
+func Test_expenser_ListLastExpenses(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().ListLast(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 10).Return(nil, test.SimpleError)
+			},
+		})
+
+		// ACT
+		list, err := e.ListLastExpenses(context.Background(), test.User, 10)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().ListLast(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 5).Return([]types.Expense{
+					{
+						ExpenseItem: types.ExpenseItem{ID: 2, Date: test.Today, Amount: 10000, Currency: "RUB"},
+						Category:    "coffee",
+					},
+				}, nil)
+			},
+		})
+
+		// ACT
+		list, err := e.ListLastExpenses(context.Background(), test.User, 5)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Expense{
+			{
+				ExpenseItem: types.ExpenseItem{ID: 2, Date: test.Today, Amount: 10000, Currency: "RUB"},
+				Category:    "coffee",
+			},
+		}, list)
+	})
+}
+
+func Test_expenser_GetExpense(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1)).Return(types.Expense{}, false, test.SimpleError)
+			},
+		})
+
+		// ACT
+		item, err := e.GetExpense(context.Background(), test.User, 1)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
+		assert.Empty(t, item)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2)).Return(types.Expense{}, false, nil)
+			},
+		})
+
+		// ACT
+		item, err := e.GetExpense(context.Background(), test.User, 2)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.Empty(t, item)
+	})
+
+	t.Run("found", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3)).Return(types.Expense{
+					ExpenseItem: types.ExpenseItem{ID: 3, Date: test.Yesterday, Amount: 20000, Currency: "USD"},
+					Category:    "taxi",
+				}, true, nil)
+			},
+		})
+
+		// ACT
+		item, err := e.GetExpense(context.Background(), test.User, 3)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, types.Expense{
+			ExpenseItem: types.ExpenseItem{ID: 3, Date: test.Yesterday, Amount: 20000, Currency: "USD"},
+			Category:    "taxi",
+		}, item)
+	})
+}
+
+func Test_expenser_UpdateExpense(t *testing.T) {
+	t.Run("feature expense", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{})
+
+		// ACT
+		err := e.UpdateExpense(
+			context.Background(),
+			test.User,
+			int64(1),      // id
+			test.Tomorrow, // date
+			int64(10000),  // amount
+			"RUB",         // currency
+			"coffee",      // category
+		)
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Update(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.ExpenseItem{
+					ID:       1,
+					Date:     test.Today,
+					Amount:   10000,
+					Currency: "RUB",
+				}, "tea").Return(nil)
+			},
+		})
+
+		// ACT
+		err := e.UpdateExpense(
+			context.Background(),
+			test.User,
+			int64(1),     // id
+			test.Today,   // date
+			int64(10000), // amount
+			"RUB",        // currency
+			"tea",        // category
+		)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
+func Test_expenser_DeleteExpense(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(5)).Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		err := e.DeleteExpense(context.Background(), test.User, 5)
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(6)).Return(nil)
+			},
+		})
+
+		// ACT
+		err := e.DeleteExpense(context.Background(), test.User, 6)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
+func Test_expenser_ImportExpenses(t *testing.T) {
+	items := []types.ImportedExpense{
+		{Expense: types.Expense{ExpenseItem: types.ExpenseItem{Date: test.Yesterday, Amount: 20000, Currency: "RUB"}, Category: "taxi"}, Hash: "h1"},
+		{Expense: types.Expense{ExpenseItem: types.ExpenseItem{Date: test.Today, Amount: 10000, Currency: "RUB"}, Category: "coffee"}, Hash: "h2"},
+	}
+
+	t.Run("future expense", func(t *testing.T) {
+		// ARRANGE
+		e := setupExpenser(t, expenserMocksInitializer{})
+
+		future := append([]types.ImportedExpense{}, items...)
+		future[1].Date = test.Tomorrow
+
+		// ACT
+		list, err := e.ImportExpenses(context.Background(), test.User, future)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Nil(t, list)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		added := []types.Expense{{ExpenseItem: types.ExpenseItem{ID: 7, Date: test.Today, Amount: 10000, Currency: "RUB"}, Category: "coffee"}}
+
+		e := setupExpenser(t, expenserMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().AddImported(gomock.AssignableToTypeOf(test.CtxInterface), test.User, items).Return(added, nil)
+			},
+		})
+
+		// ACT
+		list, err := e.ImportExpenses(context.Background(), test.User, items)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, added, list)
 	})
 }
@@ -0,0 +1,67 @@
+package expense
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/export"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type exporter struct {
+	storage storage.ExpenseStorage
+	rater   model.Rater
+}
+
+func NewExporter(s storage.ExpenseStorage, r model.Rater) *exporter {
+	return &exporter{
+		storage: s,
+		rater:   r,
+	}
+}
+
+// Export streams expenses dated within [from, to) into w, converting their amounts into
+// the currency at the rates of the expense dates. It returns the number of exported expenses.
+func (e *exporter) Export(ctx context.Context, user *types.User, from, to time.Time, format types.ExportFormat, currency string, w io.Writer) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "exporter.Export", opentracing.Tags{
+		"user":     *user,
+		"from":     from,
+		"to":       to,
+		"format":   format,
+		"currency": currency,
+	})
+	defer span.Finish()
+
+	writer, err := export.NewWriter(w, format, currency)
+	if err != nil {
+		return 0, errors.Wrap(err, "export.NewWriter")
+	}
+
+	var count int
+	err = e.storage.Walk(ctx, user, from, to, func(item types.Expense) error {
+		converted, err := e.rater.Exchange(ctx, item.Amount, item.Currency, currency, item.Date)
+		if err != nil {
+			return errors.Wrapf(err, "exchange %s to %s", item.Currency, currency)
+		}
+
+		count++
+
+		return writer.Write(export.Row{
+			Date:      item.Date,
+			Amount:    item.Amount,
+			Currency:  item.Currency,
+			Converted: converted,
+			Category:  item.Category,
+		})
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "ExpenseStorage.Walk")
+	}
+
+	return count, writer.Close()
+}
@@ -0,0 +1,114 @@
+//go:build unit
+
+package expense
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type exporterMocksInitializer struct {
+	storage func(m *mocks.MockExpenseStorage)
+	rater   func(m *mmocks.MockRater)
+}
+
+func setupExporter(t *testing.T, i exporterMocksInitializer) *exporter {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockExpenseStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	raterMock := mmocks.NewMockRater(ctrl)
+	if i.rater != nil {
+		i.rater(raterMock)
+	}
+
+	return NewExporter(storageMock, raterMock)
+}
+
+func walkExpenses(list ...types.Expense) func(context.Context, *types.User, time.Time, time.Time, func(types.Expense) error) error {
+	return func(_ context.Context, _ *types.User, _, _ time.Time, fn func(types.Expense) error) error {
+		for _, item := range list {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func Test_exporter_Export(t *testing.T) {
+	expenses := []types.Expense{
+		{ExpenseItem: types.ExpenseItem{ID: 1, Date: test.Yesterday, Amount: 1575000, Currency: "RUB"}, Category: "coffee"},
+		{ExpenseItem: types.ExpenseItem{ID: 2, Date: test.Today, Amount: 30000, Currency: "USD"}, Category: "taxi"},
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		// ARRANGE
+		e := setupExporter(t, exporterMocksInitializer{})
+
+		// ACT
+		count, err := e.Export(context.Background(), test.User, test.Yesterday, time.Time{}, "pdf", "RUB", io.Discard)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("exchange error", func(t *testing.T) {
+		// ARRANGE
+		e := setupExporter(t, exporterMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Walk(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, time.Time{}, gomock.Any()).DoAndReturn(walkExpenses(expenses...))
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(1575000), "RUB", "RUB", test.Yesterday).Return(int64(1575000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(30000), "USD", "RUB", test.Today).Return(int64(0), test.SimpleError)
+			},
+		})
+
+		// ACT
+		count, err := e.Export(context.Background(), test.User, test.Yesterday, time.Time{}, types.ExportCSV, "RUB", io.Discard)
+
+		// ASSERT
+		assert.ErrorIs(t, err, test.SimpleError)
+		assert.Zero(t, count)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		e := setupExporter(t, exporterMocksInitializer{
+			storage: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().Walk(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, test.Tomorrow, gomock.Any()).DoAndReturn(walkExpenses(expenses...))
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(1575000), "RUB", "RUB", test.Yesterday).Return(int64(1575000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(30000), "USD", "RUB", test.Today).Return(int64(1845000), nil)
+			},
+		})
+		buf := new(bytes.Buffer)
+
+		// ACT
+		count, err := e.Export(context.Background(), test.User, test.Yesterday, test.Tomorrow, types.ExportCSV, "RUB", buf)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "date,amount,currency,amount_RUB,category\n"+
+			test.Yesterday.Format("2006-01-02")+",157.50,RUB,157.50,coffee\n"+
+			test.Today.Format("2006-01-02")+",3.00,USD,184.50,taxi\n", buf.String())
+	})
+}
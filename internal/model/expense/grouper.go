@@ -0,0 +1,180 @@
+package expense
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const (
+	// the alphabet lacks look-alike characters, so that the code is easily retyped
+	_groupCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	_groupCodeLength   = 8
+)
+
+type grouper struct {
+	storage storage.GroupStorage
+}
+
+func NewGrouper(s storage.GroupStorage) *grouper {
+	return &grouper{
+		storage: s,
+	}
+}
+
+// CreateGroup creates a group owned by the user, a user is a member of one group at most.
+func (g *grouper) CreateGroup(ctx context.Context, user *types.User, name string) (types.Group, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grouper.CreateGroup", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	if _, found, err := g.storage.GetByUser(ctx, user); err != nil {
+		return types.Group{}, errors.Wrap(err, "GroupStorage.GetByUser")
+	} else if found {
+		return types.Group{}, model.ErrAlreadyExists
+	}
+
+	code, err := newGroupCode()
+	if err != nil {
+		return types.Group{}, errors.Wrap(err, "newGroupCode")
+	}
+
+	owner := types.GroupMember{
+		User: user,
+		Name: name,
+		Role: types.RoleOwner,
+	}
+
+	id, err := g.storage.Add(ctx, code, owner)
+	if err != nil {
+		return types.Group{}, errors.Wrap(err, "GroupStorage.Add")
+	}
+
+	return types.Group{
+		ID:      id,
+		Code:    code,
+		Members: []types.GroupMember{owner},
+	}, nil
+}
+
+// JoinGroup adds the user to the group with the code as a member.
+func (g *grouper) JoinGroup(ctx context.Context, user *types.User, code, name string) (types.Group, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grouper.JoinGroup", opentracing.Tags{
+		"user": *user,
+		"code": code,
+	})
+	defer span.Finish()
+
+	group, found, err := g.storage.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return types.Group{}, errors.Wrap(err, "GroupStorage.GetByCode")
+	}
+
+	if !found {
+		return types.Group{}, model.ErrNotFound
+	}
+
+	member := types.GroupMember{
+		User: user,
+		Name: name,
+		Role: types.RoleMember,
+	}
+
+	added, err := g.storage.AddMember(ctx, group.ID, member)
+	if err != nil {
+		return types.Group{}, errors.Wrap(err, "GroupStorage.AddMember")
+	}
+
+	if !added {
+		return types.Group{}, model.ErrAlreadyExists
+	}
+
+	group.Members = append(group.Members, member)
+
+	return group, nil
+}
+
+// LeaveGroup removes the user from their group, the group is dissolved when its owner leaves.
+func (g *grouper) LeaveGroup(ctx context.Context, user *types.User) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grouper.LeaveGroup", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	group, err := g.GetGroup(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if *group.Owner() == *user {
+		return errors.Wrap(g.storage.Delete(ctx, group.ID), "GroupStorage.Delete")
+	}
+
+	return errors.Wrap(g.storage.RemoveMember(ctx, user), "GroupStorage.RemoveMember")
+}
+
+// RemoveMember removes the member with the name from the group of the owner.
+func (g *grouper) RemoveMember(ctx context.Context, owner *types.User, name string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grouper.RemoveMember", opentracing.Tags{
+		"user": *owner,
+		"name": name,
+	})
+	defer span.Finish()
+
+	group, err := g.GetGroup(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	if *group.Owner() != *owner {
+		return model.ErrForbidden
+	}
+
+	member, found := group.MemberByName(name)
+	if !found || member.Role == types.RoleOwner {
+		return model.ErrNotFound
+	}
+
+	return errors.Wrap(g.storage.RemoveMember(ctx, member.User), "GroupStorage.RemoveMember")
+}
+
+// GetGroup returns the group the user is a member of.
+func (g *grouper) GetGroup(ctx context.Context, user *types.User) (types.Group, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grouper.GetGroup", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	group, found, err := g.storage.GetByUser(ctx, user)
+	if err != nil {
+		return types.Group{}, errors.Wrap(err, "GroupStorage.GetByUser")
+	}
+
+	if !found {
+		return types.Group{}, model.ErrNotFound
+	}
+
+	return group, nil
+}
+
+func newGroupCode() (string, error) {
+	code := make([]byte, _groupCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(_groupCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+
+		code[i] = _groupCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
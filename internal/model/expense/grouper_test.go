@@ -0,0 +1,251 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type grouperMocksInitializer struct {
+	storage func(m *mocks.MockGroupStorage)
+}
+
+func setupGrouper(t *testing.T, i grouperMocksInitializer) *grouper {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockGroupStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	return NewGrouper(storageMock)
+}
+
+func Test_grouper_CreateGroup(t *testing.T) {
+	t.Run("already member", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{ID: 1}, true, nil)
+			},
+		})
+
+		// ACT
+		_, err := g.CreateGroup(context.Background(), test.User, "@alice")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		owner := types.GroupMember{User: test.User, Name: "@alice", Role: types.RoleOwner}
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{}, false, nil)
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), gomock.AssignableToTypeOf(""), owner).Return(int64(7), nil)
+			},
+		})
+
+		// ACT
+		group, err := g.CreateGroup(context.Background(), test.User, "@alice")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), group.ID)
+		assert.Len(t, group.Code, _groupCodeLength)
+		assert.Empty(t, strings.Trim(group.Code, _groupCodeAlphabet))
+		assert.Equal(t, []types.GroupMember{owner}, group.Members)
+	})
+}
+
+func Test_grouper_JoinGroup(t *testing.T) {
+	owner := types.User(101)
+	group := types.Group{
+		ID:      7,
+		Code:    "ABCD2345",
+		Members: []types.GroupMember{{User: &owner, Name: "@alice", Role: types.RoleOwner}},
+	}
+
+	t.Run("unknown code", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByCode(gomock.AssignableToTypeOf(test.CtxInterface), "ABCD2345").Return(types.Group{}, false, nil)
+			},
+		})
+
+		// ACT
+		_, err := g.JoinGroup(context.Background(), test.User, "abcd2345", "@bob")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("already member", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByCode(gomock.AssignableToTypeOf(test.CtxInterface), "ABCD2345").Return(group, true, nil)
+				m.EXPECT().AddMember(gomock.AssignableToTypeOf(test.CtxInterface), int64(7), types.GroupMember{
+					User: test.User,
+					Name: "@bob",
+					Role: types.RoleMember,
+				}).Return(false, nil)
+			},
+		})
+
+		// ACT
+		_, err := g.JoinGroup(context.Background(), test.User, "ABCD2345", "@bob")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		member := types.GroupMember{User: test.User, Name: "@bob", Role: types.RoleMember}
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByCode(gomock.AssignableToTypeOf(test.CtxInterface), "ABCD2345").Return(group, true, nil)
+				m.EXPECT().AddMember(gomock.AssignableToTypeOf(test.CtxInterface), int64(7), member).Return(true, nil)
+			},
+		})
+
+		// ACT
+		joined, err := g.JoinGroup(context.Background(), test.User, " ABCD2345 ", "@bob")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, append(group.Members, member), joined.Members)
+	})
+}
+
+func Test_grouper_LeaveGroup(t *testing.T) {
+	other := types.User(101)
+
+	t.Run("not in group", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{}, false, nil)
+			},
+		})
+
+		// ACT
+		err := g.LeaveGroup(context.Background(), test.User)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("owner dissolves", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 7,
+					Members: []types.GroupMember{
+						{User: test.User, Role: types.RoleOwner},
+						{User: &other, Role: types.RoleMember},
+					},
+				}, true, nil)
+				m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), int64(7)).Return(nil)
+			},
+		})
+
+		// ACT
+		err := g.LeaveGroup(context.Background(), test.User)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("member leaves", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 7,
+					Members: []types.GroupMember{
+						{User: &other, Role: types.RoleOwner},
+						{User: test.User, Role: types.RoleMember},
+					},
+				}, true, nil)
+				m.EXPECT().RemoveMember(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil)
+			},
+		})
+
+		// ACT
+		err := g.LeaveGroup(context.Background(), test.User)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
+func Test_grouper_RemoveMember(t *testing.T) {
+	other := types.User(101)
+	group := types.Group{
+		ID: 7,
+		Members: []types.GroupMember{
+			{User: test.User, Name: "@alice", Role: types.RoleOwner},
+			{User: &other, Name: "@bob", Role: types.RoleMember},
+		},
+	}
+
+	t.Run("not owner", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), &other).Return(group, true, nil)
+			},
+		})
+
+		// ACT
+		err := g.RemoveMember(context.Background(), &other, "@alice")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrForbidden)
+	})
+
+	t.Run("unknown member", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(group, true, nil)
+			},
+		})
+
+		// ACT
+		err := g.RemoveMember(context.Background(), test.User, "@carol")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		g := setupGrouper(t, grouperMocksInitializer{
+			storage: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(group, true, nil)
+				m.EXPECT().RemoveMember(gomock.AssignableToTypeOf(test.CtxInterface), &other).Return(nil)
+			},
+		})
+
+		// ACT
+		err := g.RemoveMember(context.Background(), test.User, "Bob")
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
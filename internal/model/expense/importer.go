@@ -0,0 +1,172 @@
+package expense
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/statement"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const _defaultImportCategory = "прочее"
+
+type importer struct {
+	parser          *statement.Parser
+	rules           storage.MerchantRuleStorage
+	defaultCategory string
+}
+
+func NewImporter(cfg config.ImportConfig, rules storage.MerchantRuleStorage) *importer {
+	defaultCategory := cfg.DefaultCategory
+	if defaultCategory == "" {
+		defaultCategory = _defaultImportCategory
+	}
+
+	return &importer{
+		parser:          statement.NewParser(cfg.Banks),
+		rules:           rules,
+		defaultCategory: defaultCategory,
+	}
+}
+
+// Parse turns expenses of the statement into imported expenses categorized by the user's merchant rules,
+// incomes are skipped. The bank names the layout of CSV statements, it is detected when empty.
+func (i *importer) Parse(ctx context.Context, user *types.User, name, bank string, data []byte) ([]types.ImportedExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "importer.Parse", opentracing.Tags{
+		"user": *user,
+		"name": name,
+		"bank": bank,
+		"size": len(data),
+	})
+	defer span.Finish()
+
+	transactions, err := i.parser.Parse(name, bank, data)
+	if err != nil {
+		if errors.Is(err, statement.ErrUnknownFormat) || errors.Is(err, statement.ErrUnknownBank) {
+			return nil, model.ErrUnknownStatement
+		}
+
+		return nil, errors.Wrap(model.ErrInvalidStatement, err.Error())
+	}
+
+	rules, err := i.rules.List(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "MerchantRuleStorage.List")
+	}
+
+	var (
+		list        []types.ImportedExpense
+		occurrences = make(map[string]int)
+	)
+
+	for _, t := range transactions {
+		if t.Amount >= 0 {
+			continue
+		}
+
+		hash := transactionHash(t, occurrences)
+
+		list = append(list, types.ImportedExpense{
+			Expense: types.Expense{
+				ExpenseItem: types.ExpenseItem{
+					Date:     t.Date,
+					Amount:   -t.Amount,
+					Currency: t.Currency,
+				},
+				Category: i.categorize(rules, t.Description),
+			},
+			Hash: hash,
+		})
+	}
+
+	return list, nil
+}
+
+func (i *importer) ListRules(ctx context.Context, user *types.User) ([]types.MerchantRule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "importer.ListRules", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	list, err := i.rules.List(ctx, user)
+
+	return list, errors.Wrap(err, "MerchantRuleStorage.List")
+}
+
+// SetRule makes expenses with descriptions containing the pattern (case-insensitively) fall into the category.
+func (i *importer) SetRule(ctx context.Context, user *types.User, pattern, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "importer.SetRule", opentracing.Tags{
+		"user":     *user,
+		"pattern":  pattern,
+		"category": category,
+	})
+	defer span.Finish()
+
+	rule := types.MerchantRule{
+		Pattern:  strings.ToLower(strings.TrimSpace(pattern)),
+		Category: strings.TrimSpace(category),
+	}
+
+	if rule.Pattern == "" || rule.Category == "" {
+		return errors.New("pattern and category must not be empty")
+	}
+
+	return errors.Wrap(i.rules.Set(ctx, user, rule), "MerchantRuleStorage.Set")
+}
+
+func (i *importer) DeleteRule(ctx context.Context, user *types.User, pattern string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "importer.DeleteRule", opentracing.Tags{
+		"user":    *user,
+		"pattern": pattern,
+	})
+	defer span.Finish()
+
+	deleted, err := i.rules.Delete(ctx, user, strings.ToLower(strings.TrimSpace(pattern)))
+	if err != nil {
+		return errors.Wrap(err, "MerchantRuleStorage.Delete")
+	}
+
+	if !deleted {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// categorize picks the category of the longest pattern found in the description.
+func (i *importer) categorize(rules []types.MerchantRule, description string) string {
+	description = strings.ToLower(description)
+
+	category, matched := i.defaultCategory, 0
+	for _, rule := range rules {
+		if len(rule.Pattern) > matched && strings.Contains(description, rule.Pattern) {
+			category, matched = rule.Category, len(rule.Pattern)
+		}
+	}
+
+	return category
+}
+
+// transactionHash identifies the transaction by the bank ID, or by its contents when the statement has no IDs.
+// Equal transactions of a statement are told apart by their occurrence numbers, so that importing the same
+// statement again adds nothing while all of them are imported the first time.
+func transactionHash(t statement.Transaction, occurrences map[string]int) string {
+	key := "id:" + t.ID
+	if t.ID == "" {
+		key = fmt.Sprintf("%s|%d|%s|%s", t.Date.Format("2006-01-02"), t.Amount, t.Currency, strings.ToLower(t.Description))
+		occurrences[key]++
+		key = fmt.Sprintf("%s|%d", key, occurrences[key])
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
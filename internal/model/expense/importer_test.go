@@ -0,0 +1,127 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+var _importConfig = config.ImportConfig{
+	Banks: []config.BankConfig{
+		{
+			Name:              "plain",
+			DateColumn:        "date",
+			AmountColumn:      "amount",
+			Currency:          "RUB",
+			DescriptionColumn: "merchant",
+			ExpensesNegative:  true,
+		},
+	},
+}
+
+func setupImporter(t *testing.T, rules func(m *mocks.MockMerchantRuleStorage)) *importer {
+	ctrl := gomock.NewController(t)
+
+	rulesMock := mocks.NewMockMerchantRuleStorage(ctrl)
+	if rules != nil {
+		rules(rulesMock)
+	}
+
+	return NewImporter(_importConfig, rulesMock)
+}
+
+func Test_importer_Parse(t *testing.T) {
+	t.Run("unknown statement", func(t *testing.T) {
+		// ARRANGE
+		i := setupImporter(t, nil)
+
+		// ACT
+		_, err := i.Parse(context.Background(), test.User, "statement.csv", "", []byte("when,how much\n"))
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrUnknownStatement)
+	})
+
+	t.Run("invalid statement", func(t *testing.T) {
+		// ARRANGE
+		i := setupImporter(t, nil)
+
+		// ACT
+		_, err := i.Parse(context.Background(), test.User, "statement.csv", "plain", []byte("date,amount,merchant\nyesterday,-1,Taxi\n"))
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrInvalidStatement)
+	})
+
+	t.Run("categorized by rules", func(t *testing.T) {
+		// ARRANGE
+		i := setupImporter(t, func(m *mocks.MockMerchantRuleStorage) {
+			m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]types.MerchantRule{
+				{Pattern: "yandex", Category: "services"},
+				{Pattern: "yandex taxi", Category: "taxi"},
+				{Pattern: "starbucks", Category: "coffee"},
+			}, nil)
+		})
+
+		// ACT
+		list, err := i.Parse(context.Background(), test.User, "statement.csv", "", []byte("date,amount,merchant\n"+
+			"2022-10-15,-100,STARBUCKS Moscow\n"+
+			"2022-10-15,-100,STARBUCKS Moscow\n"+
+			"2022-10-16,5000,Salary\n"+
+			"2022-10-16,-350.50,Yandex Taxi\n"+
+			"2022-10-17,-200,Yandex Music\n"+
+			"2022-10-17,-999,Unknown shop\n"))
+
+		// ASSERT
+		assert.NoError(t, err)
+
+		categories := make([]string, 0, len(list))
+		hashes := make(map[string]struct{})
+		for _, item := range list {
+			categories = append(categories, item.Category)
+			hashes[item.Hash] = struct{}{}
+		}
+
+		assert.Equal(t, []string{"coffee", "coffee", "taxi", "services", _defaultImportCategory}, categories)
+		assert.Len(t, hashes, 5, "equal transactions must have different hashes")
+		assert.Equal(t, int64(3505000), list[2].Amount)
+		assert.Equal(t, "RUB", list[2].Currency)
+	})
+}
+
+func Test_importer_SetRule(t *testing.T) {
+	t.Run("normalized", func(t *testing.T) {
+		// ARRANGE
+		i := setupImporter(t, func(m *mocks.MockMerchantRuleStorage) {
+			m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.MerchantRule{Pattern: "starbucks", Category: "coffee"}).Return(nil)
+		})
+
+		// ACT
+		err := i.SetRule(context.Background(), test.User, " StarBucks ", "coffee ")
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		// ARRANGE
+		i := setupImporter(t, func(m *mocks.MockMerchantRuleStorage) {
+			m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "starbucks").Return(false, nil)
+		})
+
+		// ACT
+		err := i.DeleteRule(context.Background(), test.User, "Starbucks")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+}
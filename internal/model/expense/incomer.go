@@ -0,0 +1,78 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+)
+
+type incomer struct {
+	storage storage.IncomeStorage
+}
+
+func NewIncomer(s storage.IncomeStorage) *incomer {
+	return &incomer{
+		storage: s,
+	}
+}
+
+func (i *incomer) AddIncome(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "incomer.Add", opentracing.Tags{
+		"user":     *user,
+		"date":     date,
+		"amount":   amount,
+		"currency": currency,
+		"category": category,
+		"account":  account,
+	})
+	defer span.Finish()
+
+	if err := validateIncome(date, amount); err != nil {
+		return err
+	}
+
+	return i.storage.Add(
+		ctx,
+		user,
+		types.ExpenseItem{
+			Date:     date,
+			Amount:   amount,
+			Currency: currency,
+			Account:  account,
+		},
+		category,
+	)
+}
+
+func (i *incomer) ListLastIncomes(ctx context.Context, user *types.User, count int) ([]types.Income, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "incomer.ListLast", opentracing.Tags{
+		"user":  *user,
+		"count": count,
+	})
+	defer span.Finish()
+
+	list, err := i.storage.ListLast(ctx, user, count)
+	if err != nil {
+		return nil, errors.Wrap(err, "IncomeStorage.ListLast")
+	}
+
+	return list, nil
+}
+
+func validateIncome(date time.Time, amount int64) error {
+	if amount <= 0 {
+		return model.ErrNonPositiveAmount
+	}
+
+	if date.After(utils.TruncateToDate(time.Now())) {
+		return model.ErrFutureDate
+	}
+
+	return nil
+}
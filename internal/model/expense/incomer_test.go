@@ -0,0 +1,113 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type incomerMocksInitializer struct {
+	storage func(m *mocks.MockIncomeStorage)
+}
+
+func setupIncomer(t *testing.T, i incomerMocksInitializer) *incomer {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockIncomeStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	return NewIncomer(storageMock)
+}
+
+func Test_incomer_AddIncome(t *testing.T) {
+	t.Run("zero amount", func(t *testing.T) {
+		// ARRANGE
+		i := setupIncomer(t, incomerMocksInitializer{})
+
+		// ACT
+		err := i.AddIncome(context.Background(), test.User, test.Today, 0, "RUB", "salary", int64(0))
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNonPositiveAmount)
+	})
+
+	t.Run("future income", func(t *testing.T) {
+		// ARRANGE
+		i := setupIncomer(t, incomerMocksInitializer{})
+
+		// ACT
+		err := i.AddIncome(context.Background(), test.User, test.Tomorrow, 1500000000, "RUB", "salary", int64(0))
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrFutureDate)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		i := setupIncomer(t, incomerMocksInitializer{
+			storage: func(m *mocks.MockIncomeStorage) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.ExpenseItem{
+					Date:     test.Today,
+					Amount:   1500000000,
+					Currency: "RUB",
+				}, "salary").Return(nil)
+			},
+		})
+
+		// ACT
+		err := i.AddIncome(context.Background(), test.User, test.Today, 1500000000, "RUB", "salary", int64(0))
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
+func Test_incomer_ListLastIncomes(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		i := setupIncomer(t, incomerMocksInitializer{
+			storage: func(m *mocks.MockIncomeStorage) {
+				m.EXPECT().ListLast(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 10).Return(nil, test.SimpleError)
+			},
+		})
+
+		// ACT
+		list, err := i.ListLastIncomes(context.Background(), test.User, 10)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		expected := []types.Income{
+			{
+				ExpenseItem: types.ExpenseItem{ID: 1, Date: test.Today, Amount: 1500000000, Currency: "RUB"},
+				Category:    "salary",
+			},
+		}
+		i := setupIncomer(t, incomerMocksInitializer{
+			storage: func(m *mocks.MockIncomeStorage) {
+				m.EXPECT().ListLast(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 10).Return(expected, nil)
+			},
+		})
+
+		// ACT
+		list, err := i.ListLastIncomes(context.Background(), test.User, 10)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, expected, list)
+	})
+}
@@ -0,0 +1,73 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const _defaultUndoWindow = 10 * time.Minute
+
+// journaler keeps the operations of users within the undo window, the older ones are dropped as new ones come.
+type journaler struct {
+	window  time.Duration
+	storage storage.JournalStorage
+}
+
+func NewJournaler(cfg config.UndoConfig, s storage.JournalStorage) *journaler {
+	window := cfg.Window
+	if window <= 0 {
+		window = _defaultUndoWindow
+	}
+
+	return &journaler{
+		window:  window,
+		storage: s,
+	}
+}
+
+func (j *journaler) Record(ctx context.Context, user *types.User, op types.Operation) (int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "journaler.Record", opentracing.Tags{
+		"user": *user,
+		"kind": op.Kind,
+	})
+	defer span.Finish()
+
+	op.Created = time.Now()
+
+	if err := j.storage.DeleteBefore(ctx, user, op.Created.Add(-j.window)); err != nil {
+		return 0, errors.Wrap(err, "JournalStorage.DeleteBefore")
+	}
+
+	id, err := j.storage.Add(ctx, user, op)
+	if err != nil {
+		return 0, errors.Wrap(err, "JournalStorage.Add")
+	}
+
+	return id, nil
+}
+
+func (j *journaler) Pop(ctx context.Context, user *types.User, id int64) (types.Operation, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "journaler.Pop", opentracing.Tags{
+		"user": *user,
+		"id":   id,
+	})
+	defer span.Finish()
+
+	op, found, err := j.storage.Pop(ctx, user, id, time.Now().Add(-j.window))
+	if err != nil {
+		return types.Operation{}, errors.Wrap(err, "JournalStorage.Pop")
+	}
+
+	if !found {
+		return types.Operation{}, model.ErrNotFound
+	}
+
+	return op, nil
+}
@@ -0,0 +1,118 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type journalerMocksInitializer struct {
+	storage func(m *mocks.MockJournalStorage)
+}
+
+func setupJournaler(t *testing.T, cfg config.UndoConfig, i journalerMocksInitializer) *journaler {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockJournalStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	return NewJournaler(cfg, storageMock)
+}
+
+func Test_journaler_Record(t *testing.T) {
+	// ARRANGE
+	var before time.Time
+
+	j := setupJournaler(t, config.UndoConfig{Window: time.Minute}, journalerMocksInitializer{
+		storage: func(m *mocks.MockJournalStorage) {
+			m.EXPECT().DeleteBefore(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.Any()).DoAndReturn(func(_ context.Context, _ *types.User, t time.Time) error {
+				before = t
+				return nil
+			})
+			m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.Any()).DoAndReturn(func(_ context.Context, _ *types.User, op types.Operation) (int64, error) {
+				assert.Equal(t, types.OperationSetCurrency, op.Kind)
+				assert.Equal(t, "USD", op.Currency)
+				assert.Equal(t, time.Minute, op.Created.Sub(before))
+				return 5, nil
+			})
+		},
+	})
+
+	// ACT
+	id, err := j.Record(context.Background(), test.User, types.Operation{
+		Kind:     types.OperationSetCurrency,
+		Currency: "USD",
+	})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+}
+
+func Test_journaler_Pop(t *testing.T) {
+	t.Run("nothing to undo", func(t *testing.T) {
+		// ARRANGE
+		j := setupJournaler(t, config.UndoConfig{}, journalerMocksInitializer{
+			storage: func(m *mocks.MockJournalStorage) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0), gomock.Any()).DoAndReturn(func(_ context.Context, _ *types.User, _ int64, since time.Time) (types.Operation, bool, error) {
+					assert.WithinDuration(t, time.Now().Add(-_defaultUndoWindow), since, time.Second)
+					return types.Operation{}, false, nil
+				})
+			},
+		})
+
+		// ACT
+		_, err := j.Pop(context.Background(), test.User, 0)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		j := setupJournaler(t, config.UndoConfig{}, journalerMocksInitializer{
+			storage: func(m *mocks.MockJournalStorage) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0), gomock.Any()).Return(types.Operation{}, false, test.SimpleError)
+			},
+		})
+
+		// ACT
+		_, err := j.Pop(context.Background(), test.User, 0)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("last operation", func(t *testing.T) {
+		// ARRANGE
+		j := setupJournaler(t, config.UndoConfig{}, journalerMocksInitializer{
+			storage: func(m *mocks.MockJournalStorage) {
+				m.EXPECT().Pop(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(3), gomock.Any()).Return(types.Operation{
+					ID:        3,
+					Kind:      types.OperationAddExpense,
+					ExpenseID: 7,
+				}, true, nil)
+			},
+		})
+
+		// ACT
+		op, err := j.Pop(context.Background(), test.User, 3)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), op.ExpenseID)
+	})
+}
@@ -0,0 +1,227 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+)
+
+// ledgerLimiter derives limit remains from the expenses recorded within the current
+// limit period, so remains kept in the limit storage are only a cache. The limits of a group
+// are kept by its owner and cover the expenses of all the members.
+type ledgerLimiter struct {
+	*limiter
+	expenses storage.ExpenseStorage
+	groups   storage.GroupStorage
+	rater    model.Rater
+}
+
+func NewLedgerLimiter(ls storage.ExpenseLimitStorage, es storage.ExpenseStorage, gs storage.GroupStorage, rater model.Rater) *ledgerLimiter {
+	return &ledgerLimiter{
+		limiter:  NewLimiter(ls),
+		expenses: es,
+		groups:   gs,
+		rater:    rater,
+	}
+}
+
+func (l *ledgerLimiter) Get(ctx context.Context, user *types.User, category string) (types.LimitItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ledgerLimiter.Get", opentracing.Tags{
+		"user":     *user,
+		"category": category,
+	})
+	defer span.Finish()
+
+	limits, found, err := l.storage.List(ctx, user)
+	if err != nil {
+		return types.LimitItem{}, errors.Wrap(err, "ExpenseLimitStorage.List")
+	}
+
+	if !found {
+		return types.LimitItem{}, nil
+	}
+
+	if _, ok := limits[category]; !ok {
+		category = ""
+	}
+
+	if _, ok := limits[category]; !ok {
+		return types.LimitItem{}, nil
+	}
+
+	return l.recalculate(ctx, user, limits, category)
+}
+
+// Decrease ignores the value: the expense has already been recorded, so it is
+// taken into account when the remains are recalculated.
+func (l *ledgerLimiter) Decrease(ctx context.Context, user *types.User, value int64, category string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ledgerLimiter.Decrease", opentracing.Tags{
+		"user":     *user,
+		"value":    value,
+		"category": category,
+	})
+	defer span.Finish()
+
+	limit, err := l.Get(ctx, user, category)
+	if err != nil {
+		return false, err
+	}
+
+	return limit.Total > 0 && limit.Remains == 0, nil
+}
+
+func (l *ledgerLimiter) Increase(ctx context.Context, user *types.User, value int64, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ledgerLimiter.Increase", opentracing.Tags{
+		"user":     *user,
+		"value":    value,
+		"category": category,
+	})
+	defer span.Finish()
+
+	_, err := l.Get(ctx, user, category)
+
+	return err
+}
+
+func (l *ledgerLimiter) List(ctx context.Context, user *types.User) (map[string]types.LimitItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ledgerLimiter.List", opentracing.Tags{"user": *user})
+	defer span.Finish()
+
+	limits, found, err := l.storage.List(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "ExpenseLimitStorage.List")
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	list := make(map[string]types.LimitItem, len(limits))
+	for category := range limits {
+		if list[category], err = l.recalculate(ctx, user, limits, category); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// recalculate computes remains of the limit for the current period from the spent
+// amounts, renewing the limit first when one or more periods have passed, and
+// caches the result in the limit storage.
+func (l *ledgerLimiter) recalculate(ctx context.Context, user *types.User, limits map[string]types.LimitItem, category string) (types.LimitItem, error) {
+	limit := limits[category]
+	today := utils.TruncateToDate(time.Now())
+
+	if limit.Anchor.IsZero() {
+		limit.Anchor = periodStart(limit.Period, today)
+	}
+
+	expenses, err := l.listExpenses(ctx, user, limit.Anchor)
+	if err != nil {
+		return types.LimitItem{}, err
+	}
+
+	renewed := false
+	for {
+		end := periodEnd(limit.Period, limit.Anchor)
+
+		spent, err := l.spent(ctx, expenses, limits, category, limit.Currency, limit.Anchor, end)
+		if err != nil {
+			return types.LimitItem{}, err
+		}
+
+		limit.Remains = limit.Total + limit.Carried - spent
+		if limit.Remains < 0 {
+			limit.Remains = 0
+		}
+
+		if today.Before(end) {
+			break
+		}
+
+		if limit.Period.CarryOver {
+			limit.Carried = limit.Remains
+		} else {
+			limit.Carried = 0
+		}
+
+		limit.Anchor, renewed = end, true
+	}
+
+	if renewed {
+		err = l.storage.Renew(ctx, user, limit, category)
+	} else {
+		err = l.storage.Refresh(ctx, user, limit.Remains, category)
+	}
+	if err != nil {
+		return types.LimitItem{}, errors.Wrap(err, "ExpenseLimitStorage cache")
+	}
+
+	return limit, nil
+}
+
+// listExpenses returns the expenses dated since the date grouped by category, the expenses of all the members
+// are merged when the user is in a group.
+func (l *ledgerLimiter) listExpenses(ctx context.Context, user *types.User, from time.Time) (map[string][]types.ExpenseItem, error) {
+	group, found, err := l.groups.GetByUser(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "GroupStorage.GetByUser")
+	}
+
+	if !found {
+		expenses, err := l.expenses.List(ctx, user, from, time.Time{})
+		return expenses, errors.Wrap(err, "ExpenseStorage.List")
+	}
+
+	merged := make(map[string][]types.ExpenseItem)
+	for _, member := range group.Members {
+		expenses, err := l.expenses.List(ctx, member.User, from, time.Time{})
+		if err != nil {
+			return nil, errors.Wrap(err, "ExpenseStorage.List")
+		}
+
+		for category, items := range expenses {
+			merged[category] = append(merged[category], items...)
+		}
+	}
+
+	return merged, nil
+}
+
+// spent sums the expenses of [from, to) covered by the limit of the category in the
+// limit currency. The common limit covers categories that have no limits of their own.
+func (l *ledgerLimiter) spent(ctx context.Context, expenses map[string][]types.ExpenseItem, limits map[string]types.LimitItem, category, currency string, from, to time.Time) (int64, error) {
+	var total int64
+
+	for expenseCategory, items := range expenses {
+		if category != "" && expenseCategory != category {
+			continue
+		}
+
+		if _, ok := limits[expenseCategory]; category == "" && expenseCategory != "" && ok {
+			continue
+		}
+
+		for _, item := range items {
+			if item.Date.Before(from) || !item.Date.Before(to) {
+				continue
+			}
+
+			amount, err := l.rater.Exchange(ctx, item.Amount, item.Currency, currency, utils.TruncateToDate(item.Date))
+			if err != nil {
+				return 0, errors.Wrap(err, "Rater.Exchange")
+			}
+
+			total += amount
+		}
+	}
+
+	return total, nil
+}
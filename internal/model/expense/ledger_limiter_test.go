@@ -0,0 +1,395 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+)
+
+type ledgerLimiterMocksInitializer struct {
+	limits   func(m *mocks.MockExpenseLimitStorage)
+	expenses func(m *mocks.MockExpenseStorage)
+	groups   func(m *mocks.MockGroupStorage)
+	rater    func(m *mmocks.MockRater)
+}
+
+func setupLedgerLimiter(t *testing.T, i ledgerLimiterMocksInitializer) *ledgerLimiter {
+	ctrl := gomock.NewController(t)
+
+	limitsMock := mocks.NewMockExpenseLimitStorage(ctrl)
+	if i.limits != nil {
+		i.limits(limitsMock)
+	}
+
+	expensesMock := mocks.NewMockExpenseStorage(ctrl)
+	if i.expenses != nil {
+		i.expenses(expensesMock)
+	}
+
+	groupsMock := mocks.NewMockGroupStorage(ctrl)
+	if i.groups != nil {
+		i.groups(groupsMock)
+	} else {
+		groupsMock.EXPECT().GetByUser(gomock.Any(), gomock.Any()).Return(types.Group{}, false, nil).AnyTimes()
+	}
+
+	raterMock := mmocks.NewMockRater(ctrl)
+	if i.rater != nil {
+		i.rater(raterMock)
+	}
+
+	return NewLedgerLimiter(limitsMock, expensesMock, groupsMock, raterMock)
+}
+
+func Test_ledgerLimiter_Get(t *testing.T) {
+	today := utils.TruncateToDate(time.Now())
+	month := periodStart(types.LimitPeriod{Kind: types.PeriodMonth}, today)
+
+	limits := map[string]types.LimitItem{
+		"": {
+			Total:    1000000,
+			Remains:  1000000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:   month,
+		},
+		"taxi": {
+			Total:    100000,
+			Remains:  100000,
+			Currency: "USD",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:   month,
+		},
+	}
+
+	expenses := map[string][]types.ExpenseItem{
+		"taxi": {
+			{ID: 1, Date: month.AddDate(0, 0, -1), Amount: 50000, Currency: "USD"},
+			{ID: 2, Date: month, Amount: 30000, Currency: "USD"},
+			{ID: 3, Date: today, Amount: 600000, Currency: "RUB"},
+		},
+		"coffee": {
+			{ID: 4, Date: today, Amount: 150000, Currency: "RUB"},
+		},
+	}
+
+	t.Run("limits error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, false, test.SimpleError)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "taxi")
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, item)
+	})
+
+	t.Run("limit not found", func(t *testing.T) {
+		// ARRANGE
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, false, nil)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Empty(t, item)
+	})
+
+	t.Run("expenses error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(limits, true, nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, month, time.Time{}).Return(nil, test.SimpleError)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "taxi")
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, item)
+	})
+
+	t.Run("category limit", func(t *testing.T) {
+		// ARRANGE
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(limits, true, nil)
+				m.EXPECT().Refresh(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(60000), "taxi").Return(nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, month, time.Time{}).Return(expenses, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(30000), "USD", "USD", month).Return(int64(30000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(600000), "RUB", "USD", today).Return(int64(10000), nil)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, types.LimitItem{
+			Total:    100000,
+			Remains:  60000,
+			Currency: "USD",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:   month,
+		}, item)
+	})
+
+	t.Run("common limit", func(t *testing.T) {
+		// ARRANGE
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(limits, true, nil)
+				m.EXPECT().Refresh(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(850000), "").Return(nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, month, time.Time{}).Return(expenses, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(150000), "RUB", "RUB", today).Return(int64(150000), nil)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "coffee")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, types.LimitItem{
+			Total:    1000000,
+			Remains:  850000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:   month,
+		}, item)
+	})
+
+	t.Run("group expenses", func(t *testing.T) {
+		// ARRANGE
+		member := types.User(102)
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(limits, true, nil)
+				m.EXPECT().Refresh(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(800000), "").Return(nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, month, time.Time{}).Return(expenses, nil)
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), &member, month, time.Time{}).Return(map[string][]types.ExpenseItem{
+					"coffee": {{ID: 5, Date: today, Amount: 50000, Currency: "RUB"}},
+				}, nil)
+			},
+			groups: func(m *mocks.MockGroupStorage) {
+				m.EXPECT().GetByUser(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(types.Group{
+					ID: 1,
+					Members: []types.GroupMember{
+						{User: test.User, Name: "alice", Role: types.RoleOwner},
+						{User: &member, Name: "bob", Role: types.RoleMember},
+					},
+				}, true, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(150000), "RUB", "RUB", today).Return(int64(150000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(50000), "RUB", "RUB", today).Return(int64(50000), nil)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "coffee")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, int64(800000), item.Remains)
+	})
+
+	t.Run("exchange error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(limits, true, nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, month, time.Time{}).Return(expenses, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(150000), "RUB", "RUB", today).Return(int64(0), test.SimpleError)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "coffee")
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, item)
+	})
+
+	t.Run("renew with carry over", func(t *testing.T) {
+		// ARRANGE
+		anchor := today.AddDate(0, 0, -1)
+		renewed := types.LimitItem{
+			Total:    100000,
+			Remains:  100000 + 70000 - 20000,
+			Carried:  70000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodDay, CarryOver: true},
+			Anchor:   today,
+		}
+
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(map[string]types.LimitItem{
+					"": {
+						Total:    100000,
+						Remains:  100000,
+						Currency: "RUB",
+						Period:   types.LimitPeriod{Kind: types.PeriodDay, CarryOver: true},
+						Anchor:   anchor,
+					},
+				}, true, nil)
+				m.EXPECT().Renew(gomock.AssignableToTypeOf(test.CtxInterface), test.User, renewed, "").Return(nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, anchor, time.Time{}).Return(map[string][]types.ExpenseItem{
+					"coffee": {
+						{ID: 1, Date: anchor, Amount: 30000, Currency: "RUB"},
+						{ID: 2, Date: today, Amount: 20000, Currency: "RUB"},
+					},
+				}, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(30000), "RUB", "RUB", anchor).Return(int64(30000), nil)
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(20000), "RUB", "RUB", today).Return(int64(20000), nil)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "coffee")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, renewed, item)
+	})
+}
+
+func Test_ledgerLimiter_Decrease(t *testing.T) {
+	t.Run("limit reached", func(t *testing.T) {
+		// ARRANGE
+		today := utils.TruncateToDate(time.Now())
+
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(map[string]types.LimitItem{
+					"taxi": {
+						Total:    100000,
+						Remains:  100000,
+						Currency: "RUB",
+						Period:   types.LimitPeriod{Kind: types.PeriodDay},
+						Anchor:   today,
+					},
+				}, true, nil)
+				m.EXPECT().Refresh(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0), "taxi").Return(nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, today, time.Time{}).Return(map[string][]types.ExpenseItem{
+					"taxi": {{ID: 1, Date: today, Amount: 120000, Currency: "RUB"}},
+				}, nil)
+			},
+			rater: func(m *mmocks.MockRater) {
+				m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(120000), "RUB", "RUB", today).Return(int64(120000), nil)
+			},
+		})
+
+		// ACT
+		reached, err := l.Decrease(context.Background(), test.User, 120000, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, reached)
+	})
+}
+
+func Test_ledgerLimiter_List(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, false, test.SimpleError)
+			},
+		})
+
+		// ACT
+		list, err := l.List(context.Background(), test.User)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		today := utils.TruncateToDate(time.Now())
+		week := periodStart(types.LimitPeriod{Kind: types.PeriodWeek}, today)
+
+		l := setupLedgerLimiter(t, ledgerLimiterMocksInitializer{
+			limits: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(map[string]types.LimitItem{
+					"taxi": {
+						Total:    100000,
+						Remains:  0,
+						Currency: "RUB",
+						Period:   types.LimitPeriod{Kind: types.PeriodWeek},
+						Anchor:   week,
+					},
+				}, true, nil)
+				m.EXPECT().Refresh(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(100000), "taxi").Return(nil)
+			},
+			expenses: func(m *mocks.MockExpenseStorage) {
+				m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User, week, time.Time{}).Return(nil, nil)
+			},
+		})
+
+		// ACT
+		list, err := l.List(context.Background(), test.User)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]types.LimitItem{
+			"taxi": {
+				Total:    100000,
+				Remains:  100000,
+				Currency: "RUB",
+				Period:   types.LimitPeriod{Kind: types.PeriodWeek},
+				Anchor:   week,
+			},
+		}, list)
+	})
+}
@@ -2,14 +2,19 @@ package expense
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
-	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
-
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
 )
 
+// _defaultAlerts are the limit usage thresholds (percent) alerted when the user has not set their own.
+var _defaultAlerts = []int{50, 80, 100}
+
 type limiter struct {
 	storage storage.ExpenseLimitStorage
 }
@@ -32,17 +37,18 @@ func (l *limiter) Get(ctx context.Context, user *types.User, category string) (t
 		return types.LimitItem{}, errors.Wrap(err, "ExpenseLimitStorage.Get")
 	}
 
-	if found {
-		return limit, nil
+	if !found {
+		return types.LimitItem{}, nil
 	}
 
-	return types.LimitItem{}, nil
+	return l.renew(ctx, user, limit, category)
 }
 
-func (l *limiter) Set(ctx context.Context, user *types.User, limit int64, currency, category string) error {
+func (l *limiter) Set(ctx context.Context, user *types.User, limit int64, currency string, period types.LimitPeriod, category string) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.Set", opentracing.Tags{
 		"user":     *user,
 		"limit":    limit,
+		"period":   period.Kind,
 		"category": category,
 	})
 	defer span.Finish()
@@ -51,7 +57,24 @@ func (l *limiter) Set(ctx context.Context, user *types.User, limit int64, curren
 		return errors.New("negative limit")
 	}
 
-	return l.storage.Set(ctx, user, limit, currency, category)
+	switch period.Kind {
+	case types.PeriodDay, types.PeriodWeek, types.PeriodMonth:
+		period.Days = 0
+	case types.PeriodCustom:
+		if period.Days <= 0 {
+			return errors.New("non-positive custom period")
+		}
+	default:
+		return errors.New("unknown limit period")
+	}
+
+	return l.storage.Set(ctx, user, types.LimitItem{
+		Total:    limit,
+		Remains:  limit,
+		Currency: currency,
+		Period:   period,
+		Anchor:   periodStart(period, utils.TruncateToDate(time.Now())),
+	}, category)
 }
 
 func (l *limiter) Decrease(ctx context.Context, user *types.User, value int64, category string) (bool, error) {
@@ -65,6 +88,17 @@ func (l *limiter) Decrease(ctx context.Context, user *types.User, value int64, c
 	return l.storage.Decrease(ctx, user, value, category)
 }
 
+func (l *limiter) Increase(ctx context.Context, user *types.User, value int64, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.Increase", opentracing.Tags{
+		"user":     *user,
+		"value":    value,
+		"category": category,
+	})
+	defer span.Finish()
+
+	return l.storage.Increase(ctx, user, value, category)
+}
+
 func (l *limiter) Unset(ctx context.Context, user *types.User, category string) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.Unset", opentracing.Tags{"user": *user})
 	defer span.Finish()
@@ -72,6 +106,45 @@ func (l *limiter) Unset(ctx context.Context, user *types.User, category string)
 	return l.storage.Unset(ctx, user, category)
 }
 
+// Restore puts the limit back as it was including its remains, e.g. when the change of the limit is undone.
+// The zero limit stands for no limit.
+func (l *limiter) Restore(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.Restore", opentracing.Tags{
+		"user":     *user,
+		"limit":    item.Total,
+		"category": category,
+	})
+	defer span.Finish()
+
+	if item.Total == 0 {
+		return errors.Wrap(l.storage.Unset(ctx, user, category), "ExpenseLimitStorage.Unset")
+	}
+
+	// setting the limit starts its period over, the remains and the alerts are put back afterwards
+	if err := l.storage.Set(ctx, user, item, category); err != nil {
+		return errors.Wrap(err, "ExpenseLimitStorage.Set")
+	}
+
+	if err := l.storage.Refresh(ctx, user, item.Remains, category); err != nil {
+		return errors.Wrap(err, "ExpenseLimitStorage.Refresh")
+	}
+
+	// the limit is overspent only when nothing remains, so charging the overspent part puts it back
+	if item.Overspent > 0 {
+		if _, err := l.storage.Decrease(ctx, user, item.Overspent, category); err != nil {
+			return errors.Wrap(err, "ExpenseLimitStorage.Decrease")
+		}
+	}
+
+	if item.Notified > 0 {
+		if _, err := l.storage.Notify(ctx, user, item.Notified, category); err != nil {
+			return errors.Wrap(err, "ExpenseLimitStorage.Notify")
+		}
+	}
+
+	return nil
+}
+
 func (l *limiter) List(ctx context.Context, user *types.User) (map[string]types.LimitItem, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.List", opentracing.Tags{"user": *user})
 	defer span.Finish()
@@ -81,9 +154,139 @@ func (l *limiter) List(ctx context.Context, user *types.User) (map[string]types.
 		return nil, errors.Wrap(err, "ExpenseLimitStorage.List")
 	}
 
-	if found {
-		return list, nil
+	if !found {
+		return nil, nil
+	}
+
+	for category := range list {
+		if list[category], err = l.renew(ctx, user, list[category], category); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (l *limiter) Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.Notify", opentracing.Tags{
+		"user":      *user,
+		"threshold": threshold,
+		"category":  category,
+	})
+	defer span.Finish()
+
+	return l.storage.Notify(ctx, user, threshold, category)
+}
+
+func (l *limiter) GetAlerts(ctx context.Context, user *types.User) ([]int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.GetAlerts", opentracing.Tags{"user": *user})
+	defer span.Finish()
+
+	thresholds, found, err := l.storage.GetAlerts(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "ExpenseLimitStorage.GetAlerts")
+	}
+
+	if !found {
+		return _defaultAlerts, nil
+	}
+
+	return thresholds, nil
+}
+
+func (l *limiter) SetAlerts(ctx context.Context, user *types.User, thresholds []int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "limiter.SetAlerts", opentracing.Tags{
+		"user":       *user,
+		"thresholds": thresholds,
+	})
+	defer span.Finish()
+
+	for _, threshold := range thresholds {
+		if threshold <= 0 || threshold > 100 {
+			return errors.New("threshold out of range")
+		}
+	}
+
+	thresholds = append([]int(nil), thresholds...)
+	sort.Ints(thresholds)
+
+	unique := thresholds[:0]
+	for i, threshold := range thresholds {
+		if i == 0 || threshold != thresholds[i-1] {
+			unique = append(unique, threshold)
+		}
+	}
+
+	return l.storage.SetAlerts(ctx, user, unique)
+}
+
+// renew moves the limit to the period containing today, resetting remains
+// (or adding the total to them when unspent money is carried over).
+func (l *limiter) renew(ctx context.Context, user *types.User, limit types.LimitItem, category string) (types.LimitItem, error) {
+	today := utils.TruncateToDate(time.Now())
+
+	renewed, ok := renewLimit(limit, today)
+	if !ok {
+		return limit, nil
+	}
+
+	if err := l.storage.Renew(ctx, user, renewed, category); err != nil {
+		return types.LimitItem{}, errors.Wrap(err, "ExpenseLimitStorage.Renew")
+	}
+
+	return renewed, nil
+}
+
+func renewLimit(limit types.LimitItem, today time.Time) (types.LimitItem, bool) {
+	if limit.Anchor.IsZero() {
+		return limit, false
+	}
+
+	end := periodEnd(limit.Period, limit.Anchor)
+	if today.Before(end) {
+		return limit, false
+	}
+
+	for !today.Before(end) {
+		if limit.Period.CarryOver {
+			limit.Carried = limit.Remains
+		} else {
+			limit.Carried = 0
+		}
+
+		limit.Remains = limit.Total + limit.Carried
+		limit.Overspent = 0
+
+		limit.Anchor, end = end, periodEnd(limit.Period, end)
+	}
+
+	return limit, true
+}
+
+// periodStart returns the first day of the period containing the date.
+func periodStart(period types.LimitPeriod, date time.Time) time.Time {
+	switch period.Kind {
+	case types.PeriodWeek:
+		return date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	case types.PeriodMonth:
+		return date.AddDate(0, 0, 1-date.Day())
+	}
+
+	return date
+}
+
+// periodEnd returns the first day of the period following the one started at anchor.
+func periodEnd(period types.LimitPeriod, anchor time.Time) time.Time {
+	switch period.Kind {
+	case types.PeriodDay:
+		return anchor.AddDate(0, 0, 1)
+	case types.PeriodWeek:
+		return anchor.AddDate(0, 0, 7)
+	case types.PeriodCustom:
+		if period.Days > 0 {
+			return anchor.AddDate(0, 0, period.Days)
+		}
 	}
 
-	return nil, nil
+	return anchor.AddDate(0, 1, 0)
 }
@@ -5,12 +5,14 @@ package expense
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
 )
 
 type limiterMocksInitializer struct {
@@ -86,6 +88,65 @@ func Test_limiter_Get(t *testing.T) {
 	})
 }
 
+func Test_limiter_Get_renew(t *testing.T) {
+	today := utils.TruncateToDate(time.Now())
+	anchor := periodStart(types.LimitPeriod{Kind: types.PeriodDay}, today).AddDate(0, 0, -3)
+
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(types.LimitItem{
+					Total:    100000,
+					Remains:  0,
+					Currency: "RUB",
+					Period:   types.LimitPeriod{Kind: types.PeriodDay},
+					Anchor:   anchor,
+				}, true, nil)
+				m.EXPECT().Renew(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.AssignableToTypeOf(types.LimitItem{}), "taxi").Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "taxi")
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, item)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		renewed := types.LimitItem{
+			Total:    100000,
+			Remains:  100000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodDay},
+			Anchor:   today,
+		}
+
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(types.LimitItem{
+					Total:    100000,
+					Remains:  0,
+					Currency: "RUB",
+					Period:   types.LimitPeriod{Kind: types.PeriodDay},
+					Anchor:   anchor,
+				}, true, nil)
+				m.EXPECT().Renew(gomock.AssignableToTypeOf(test.CtxInterface), test.User, renewed, "taxi").Return(nil)
+			},
+		})
+
+		// ACT
+		item, err := l.Get(context.Background(), test.User, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, renewed, item)
+	})
+}
+
 func Test_limiter_Set(t *testing.T) {
 	t.Run("negative limit", func(t *testing.T) {
 		// ARRANGE
@@ -97,7 +158,44 @@ func Test_limiter_Set(t *testing.T) {
 			test.User,
 			int64(-10000), // limit
 			"RUB",         // currency
-			"",            // category
+			types.LimitPeriod{Kind: types.PeriodMonth},
+			"", // category
+		)
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown period", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{})
+
+		// ACT
+		err := l.Set(
+			context.Background(),
+			test.User,
+			int64(10000), // limit
+			"RUB",        // currency
+			types.LimitPeriod{Kind: "year"},
+			"", // category
+		)
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("empty custom period", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{})
+
+		// ACT
+		err := l.Set(
+			context.Background(),
+			test.User,
+			int64(10000), // limit
+			"RUB",        // currency
+			types.LimitPeriod{Kind: types.PeriodCustom},
+			"", // category
 		)
 
 		// ASSERT
@@ -108,7 +206,7 @@ func Test_limiter_Set(t *testing.T) {
 		// ARRANGE
 		l := setupLimiter(t, limiterMocksInitializer{
 			storage: func(m *mocks.MockExpenseLimitStorage) {
-				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1000000), "RUB", "coffee").Return(test.SimpleError)
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.AssignableToTypeOf(types.LimitItem{}), "coffee").Return(test.SimpleError)
 			},
 		})
 
@@ -118,7 +216,8 @@ func Test_limiter_Set(t *testing.T) {
 			test.User,
 			int64(1000000), // limit
 			"RUB",          // currency
-			"coffee",       // category
+			types.LimitPeriod{Kind: types.PeriodMonth},
+			"coffee", // category
 		)
 
 		// ASSERT
@@ -129,7 +228,13 @@ func Test_limiter_Set(t *testing.T) {
 		// ARRANGE
 		l := setupLimiter(t, limiterMocksInitializer{
 			storage: func(m *mocks.MockExpenseLimitStorage) {
-				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1200000), "RUB", "coffee").Return(nil)
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.LimitItem{
+					Total:    1200000,
+					Remains:  1200000,
+					Currency: "RUB",
+					Period:   types.LimitPeriod{Kind: types.PeriodWeek, CarryOver: true},
+					Anchor:   periodStart(types.LimitPeriod{Kind: types.PeriodWeek}, utils.TruncateToDate(time.Now())),
+				}, "coffee").Return(nil)
 			},
 		})
 
@@ -139,7 +244,8 @@ func Test_limiter_Set(t *testing.T) {
 			test.User,
 			int64(1200000), // limit
 			"RUB",          // currency
-			"coffee",       // category
+			types.LimitPeriod{Kind: types.PeriodWeek, CarryOver: true},
+			"coffee", // category
 		)
 
 		// ASSERT
@@ -191,6 +297,48 @@ func Test_limiter_Decrease(t *testing.T) {
 	})
 }
 
+func Test_limiter_Increase(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().Increase(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(100000), "taxi").Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		err := l.Increase(
+			context.Background(),
+			test.User,
+			int64(100000), // value
+			"taxi",        // category
+		)
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().Increase(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(200000), "coffee").Return(nil)
+			},
+		})
+
+		// ACT
+		err := l.Increase(
+			context.Background(),
+			test.User,
+			int64(200000), // value
+			"coffee",      // category
+		)
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
+
 func Test_limiter_Unset(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		// ARRANGE
@@ -231,6 +379,93 @@ func Test_limiter_Unset(t *testing.T) {
 	})
 }
 
+func Test_limiter_Restore(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().Unset(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "taxi").Return(nil)
+			},
+		})
+
+		// ACT
+		err := l.Restore(context.Background(), test.User, types.LimitItem{}, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("remains and alerts", func(t *testing.T) {
+		// ARRANGE
+		item := types.LimitItem{
+			Total:    10000000,
+			Remains:  2500000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:   time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+			Notified: 50,
+		}
+
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				gomock.InOrder(
+					m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, item, "taxi").Return(nil),
+					m.EXPECT().Refresh(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(2500000), "taxi").Return(nil),
+					m.EXPECT().Notify(gomock.AssignableToTypeOf(test.CtxInterface), test.User, 50, "taxi").Return(true, nil),
+				)
+			},
+		})
+
+		// ACT
+		err := l.Restore(context.Background(), test.User, item, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("overspent", func(t *testing.T) {
+		// ARRANGE
+		item := types.LimitItem{
+			Total:     10000000,
+			Currency:  "RUB",
+			Period:    types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:    time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+			Overspent: 400000,
+		}
+
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				gomock.InOrder(
+					m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, item, "taxi").Return(nil),
+					m.EXPECT().Refresh(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(0), "taxi").Return(nil),
+					m.EXPECT().Decrease(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(400000), "taxi").Return(true, nil),
+				)
+			},
+		})
+
+		// ACT
+		err := l.Restore(context.Background(), test.User, item, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().Set(gomock.AssignableToTypeOf(test.CtxInterface), test.User, gomock.Any(), "taxi").Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		err := l.Restore(context.Background(), test.User, types.LimitItem{Total: 10000000, Remains: 2500000}, "taxi")
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+}
+
 func Test_limiter_List(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		// ARRANGE
@@ -302,3 +537,156 @@ func Test_limiter_List(t *testing.T) {
 		}, list)
 	})
 }
+
+func Test_renewLimit(t *testing.T) {
+	date := func(day, month, year int) time.Time {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		limit   types.LimitItem
+		today   time.Time
+		want    types.LimitItem
+		renewed bool
+	}{
+		{
+			name:  "without anchor",
+			limit: types.LimitItem{Total: 100, Remains: 10, Period: types.LimitPeriod{Kind: types.PeriodMonth}},
+			today: date(15, 10, 2022),
+			want:  types.LimitItem{Total: 100, Remains: 10, Period: types.LimitPeriod{Kind: types.PeriodMonth}},
+		},
+		{
+			name:  "same period",
+			limit: types.LimitItem{Total: 100, Remains: 10, Period: types.LimitPeriod{Kind: types.PeriodMonth}, Anchor: date(1, 10, 2022)},
+			today: date(31, 10, 2022),
+			want:  types.LimitItem{Total: 100, Remains: 10, Period: types.LimitPeriod{Kind: types.PeriodMonth}, Anchor: date(1, 10, 2022)},
+		},
+		{
+			name:    "next month",
+			limit:   types.LimitItem{Total: 100, Remains: 10, Period: types.LimitPeriod{Kind: types.PeriodMonth}, Anchor: date(1, 10, 2022)},
+			today:   date(1, 11, 2022),
+			want:    types.LimitItem{Total: 100, Remains: 100, Period: types.LimitPeriod{Kind: types.PeriodMonth}, Anchor: date(1, 11, 2022)},
+			renewed: true,
+		},
+		{
+			name:    "overspent next month",
+			limit:   types.LimitItem{Total: 100, Period: types.LimitPeriod{Kind: types.PeriodMonth}, Anchor: date(1, 10, 2022), Overspent: 40},
+			today:   date(1, 11, 2022),
+			want:    types.LimitItem{Total: 100, Remains: 100, Period: types.LimitPeriod{Kind: types.PeriodMonth}, Anchor: date(1, 11, 2022)},
+			renewed: true,
+		},
+		{
+			name:    "several weeks with carry over",
+			limit:   types.LimitItem{Total: 100, Remains: 10, Period: types.LimitPeriod{Kind: types.PeriodWeek, CarryOver: true}, Anchor: date(3, 10, 2022)},
+			today:   date(19, 10, 2022),
+			want:    types.LimitItem{Total: 100, Remains: 210, Carried: 110, Period: types.LimitPeriod{Kind: types.PeriodWeek, CarryOver: true}, Anchor: date(17, 10, 2022)},
+			renewed: true,
+		},
+		{
+			name:    "custom period",
+			limit:   types.LimitItem{Total: 100, Remains: 0, Period: types.LimitPeriod{Kind: types.PeriodCustom, Days: 10}, Anchor: date(1, 10, 2022)},
+			today:   date(25, 10, 2022),
+			want:    types.LimitItem{Total: 100, Remains: 100, Period: types.LimitPeriod{Kind: types.PeriodCustom, Days: 10}, Anchor: date(21, 10, 2022)},
+			renewed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got, renewed := renewLimit(tt.limit, tt.today)
+
+			// ASSERT
+			assert.Equal(t, tt.renewed, renewed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_periodStart(t *testing.T) {
+	date := time.Date(2022, 10, 19, 0, 0, 0, 0, time.UTC) // Wednesday
+
+	assert.Equal(t, date, periodStart(types.LimitPeriod{Kind: types.PeriodDay}, date))
+	assert.Equal(t, time.Date(2022, 10, 17, 0, 0, 0, 0, time.UTC), periodStart(types.LimitPeriod{Kind: types.PeriodWeek}, date))
+	assert.Equal(t, time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), periodStart(types.LimitPeriod{Kind: types.PeriodMonth}, date))
+	assert.Equal(t, date, periodStart(types.LimitPeriod{Kind: types.PeriodCustom, Days: 10}, date))
+}
+
+func Test_limiter_GetAlerts(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, false, test.SimpleError)
+			},
+		})
+
+		// ACT
+		thresholds, err := l.GetAlerts(context.Background(), test.User)
+
+		// ASSERT
+		assert.Error(t, err)
+		assert.Empty(t, thresholds)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return(nil, false, nil)
+			},
+		})
+
+		// ACT
+		thresholds, err := l.GetAlerts(context.Background(), test.User)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []int{50, 80, 100}, thresholds)
+	})
+
+	t.Run("found", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().GetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]int{90}, true, nil)
+			},
+		})
+
+		// ACT
+		thresholds, err := l.GetAlerts(context.Background(), test.User)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []int{90}, thresholds)
+	})
+}
+
+func Test_limiter_SetAlerts(t *testing.T) {
+	t.Run("out of range", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{})
+
+		// ACT
+		err := l.SetAlerts(context.Background(), test.User, []int{50, 150})
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		l := setupLimiter(t, limiterMocksInitializer{
+			storage: func(m *mocks.MockExpenseLimitStorage) {
+				m.EXPECT().SetAlerts(gomock.AssignableToTypeOf(test.CtxInterface), test.User, []int{50, 80, 100}).Return(nil)
+			},
+		})
+
+		// ACT
+		err := l.SetAlerts(context.Background(), test.User, []int{100, 50, 80, 50})
+
+		// ASSERT
+		assert.NoError(t, err)
+	})
+}
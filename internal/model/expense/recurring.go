@@ -0,0 +1,212 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/response"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+	"go.uber.org/zap"
+)
+
+const _defaultRecurringInterval = time.Minute
+
+var errOccurrenceNotReady = errors.New("occurrence cannot be added yet")
+
+// adder adds expenses along with charging the limits, it's implemented by the controller.
+type adder interface {
+	AddExpense(ctx context.Context, req request.AddExpense) response.AddExpense
+}
+
+type recurrer struct {
+	interval   time.Duration
+	storage    storage.RecurringExpenseStorage
+	unitOfWork storage.UnitOfWork
+	logger     *zap.Logger
+}
+
+// NewRecurrer creates the recurrer, its unit of work must be shared with the adder passed to Run,
+// so that an occurrence is added in the same transaction which moves the schedule forward.
+func NewRecurrer(cfg config.RecurringConfig, s storage.RecurringExpenseStorage, uow storage.UnitOfWork, l *zap.Logger) *recurrer {
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = _defaultRecurringInterval
+	}
+
+	return &recurrer{
+		interval:   interval,
+		storage:    s,
+		unitOfWork: uow,
+		logger:     l,
+	}
+}
+
+func (r *recurrer) Add(ctx context.Context, user *types.User, item types.RecurringExpense) (types.RecurringExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "recurrer.Add", opentracing.Tags{
+		"user":      *user,
+		"frequency": item.Recurrence.Frequency,
+		"interval":  item.Recurrence.Interval,
+		"start":     item.Recurrence.Start,
+	})
+	defer span.Finish()
+
+	if !item.Recurrence.Valid() {
+		return types.RecurringExpense{}, model.ErrWrongRecurrence
+	}
+
+	item.Recurrence.Start = utils.TruncateToDate(item.Recurrence.Start)
+	item.Count = 0
+	item.Next = item.Recurrence.Occurrence(0)
+	item.Paused = false
+
+	id, err := r.storage.Add(ctx, user, item)
+	if err != nil {
+		return types.RecurringExpense{}, errors.Wrap(err, "RecurringExpenseStorage.Add")
+	}
+
+	item.ID = id
+
+	return item, nil
+}
+
+func (r *recurrer) List(ctx context.Context, user *types.User) ([]types.RecurringExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "recurrer.List", opentracing.Tags{
+		"user": *user,
+	})
+	defer span.Finish()
+
+	list, err := r.storage.List(ctx, user)
+
+	return list, errors.Wrap(err, "RecurringExpenseStorage.List")
+}
+
+// SetPaused pauses or resumes the recurring expense. The occurrences passed while it has been paused
+// are skipped on resume.
+func (r *recurrer) SetPaused(ctx context.Context, user *types.User, id int64, paused bool) (item types.RecurringExpense, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "recurrer.SetPaused", opentracing.Tags{
+		"user":   *user,
+		"id":     id,
+		"paused": paused,
+	})
+	defer span.Finish()
+
+	err = r.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		var found bool
+		if item, found, err = r.storage.Get(ctx, user, id); err != nil {
+			return errors.Wrap(err, "RecurringExpenseStorage.Get")
+		} else if !found {
+			return model.ErrNotFound
+		} else if !item.Recurrence.Valid() {
+			return model.ErrWrongRecurrence
+		}
+
+		if item.Paused && !paused {
+			today := utils.TruncateToDate(time.Now())
+			for item.Next.Before(today) {
+				item.Count++
+				item.Next = item.Recurrence.Occurrence(item.Count)
+			}
+		}
+
+		item.Paused = paused
+
+		return errors.Wrap(r.storage.Update(ctx, user, item), "RecurringExpenseStorage.Update")
+	})
+
+	return item, err
+}
+
+// Run adds the due occurrences of recurring expenses through the adder until the context is done.
+func (r *recurrer) Run(ctx context.Context, a adder) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.addDue(ctx, a, utils.TruncateToDate(time.Now()))
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// addDue adds the occurrences due by today one recurring expense per transaction. The recurring expense
+// is locked while its occurrences are being added, and its schedule moves forward in the same transaction,
+// so each occurrence is added exactly once even by several concurrent instances. The recurring expenses
+// failed to be added are left till the next run.
+func (r *recurrer) addDue(ctx context.Context, a adder, today time.Time) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "recurrer.addDue", opentracing.Tags{
+		"date": today,
+	})
+	defer span.Finish()
+
+	var failed []int64
+	for ctx.Err() == nil {
+		var (
+			id    int64
+			found bool
+		)
+
+		err := r.unitOfWork.Do(ctx, func(ctx context.Context) error {
+			user, item, ok, err := r.storage.LockDue(ctx, today, failed)
+			if err != nil {
+				return errors.Wrap(err, "RecurringExpenseStorage.LockDue")
+			} else if !ok {
+				return nil
+			}
+
+			id, found = item.ID, true
+			if !item.Recurrence.Valid() {
+				return model.ErrWrongRecurrence
+			}
+
+			for !item.Next.After(today) {
+				resp := a.AddExpense(ctx, request.AddExpense{
+					User:      user,
+					Date:      item.Next,
+					Amount:    item.Amount,
+					Currency:  item.Currency,
+					Category:  item.Category,
+					Scheduled: true,
+				})
+
+				if !resp.Ready {
+					return errOccurrenceNotReady
+				} else if !resp.Success {
+					return errors.Errorf("cannot add occurrence %d of recurring expense %d", item.Count, item.ID)
+				}
+
+				item.Count++
+				item.Next = item.Recurrence.Occurrence(item.Count)
+			}
+
+			return errors.Wrap(r.storage.Update(ctx, user, item), "RecurringExpenseStorage.Update")
+		})
+
+		switch {
+		case errors.Is(err, errOccurrenceNotReady):
+			return
+
+		case err != nil:
+			r.logger.Error("cannot add recurring expense", zap.Error(err), zap.Int64("id", id))
+			if !found {
+				return
+			}
+
+			failed = append(failed, id)
+
+		case !found:
+			return
+		}
+	}
+}
@@ -0,0 +1,266 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/response"
+	mmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+	"go.uber.org/zap"
+)
+
+type recurrerMocksInitializer struct {
+	storage func(m *mocks.MockRecurringExpenseStorage)
+	adder   func(m *mmocks.MockController)
+}
+
+func setupRecurrer(t *testing.T, i recurrerMocksInitializer) (*recurrer, adder) {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockRecurringExpenseStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	adderMock := mmocks.NewMockController(ctrl)
+	if i.adder != nil {
+		i.adder(adderMock)
+	}
+
+	unitOfWorkMock := mocks.NewMockUnitOfWork(ctrl)
+	unitOfWorkMock.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return NewRecurrer(config.RecurringConfig{}, storageMock, unitOfWorkMock, zap.NewNop()), adderMock
+}
+
+func Test_recurrer_Add(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// ARRANGE
+		start := time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)
+		item := types.RecurringExpense{
+			Recurrence: types.Recurrence{Frequency: types.FrequencyMonthly, Interval: 1, Start: start},
+			Amount:     1500000,
+			Currency:   "RUB",
+			Category:   "rent",
+			Next:       start,
+		}
+
+		r, _ := setupRecurrer(t, recurrerMocksInitializer{
+			storage: func(m *mocks.MockRecurringExpenseStorage) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.User, item).Return(int64(1), nil)
+			},
+		})
+
+		// ACT
+		got, err := r.Add(context.Background(), test.User, item)
+
+		// ASSERT
+		item.ID = 1
+		assert.NoError(t, err)
+		assert.Equal(t, item, got)
+	})
+
+	t.Run("zero interval", func(t *testing.T) {
+		// ARRANGE
+		r, _ := setupRecurrer(t, recurrerMocksInitializer{})
+
+		// ACT
+		_, err := r.Add(context.Background(), test.User, types.RecurringExpense{
+			Recurrence: types.Recurrence{Frequency: types.FrequencyDaily, Start: time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)},
+			Amount:     1500000,
+			Currency:   "RUB",
+			Category:   "lunch",
+		})
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrWrongRecurrence)
+	})
+
+	t.Run("unknown frequency", func(t *testing.T) {
+		// ARRANGE
+		r, _ := setupRecurrer(t, recurrerMocksInitializer{})
+
+		// ACT
+		_, err := r.Add(context.Background(), test.User, types.RecurringExpense{
+			Recurrence: types.Recurrence{Frequency: "hourly", Interval: 1, Start: time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)},
+			Amount:     1500000,
+			Currency:   "RUB",
+			Category:   "lunch",
+		})
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrWrongRecurrence)
+	})
+}
+
+func Test_recurrer_SetPaused(t *testing.T) {
+	today := utils.TruncateToDate(time.Now())
+
+	t.Run("not found", func(t *testing.T) {
+		// ARRANGE
+		r, _ := setupRecurrer(t, recurrerMocksInitializer{
+			storage: func(m *mocks.MockRecurringExpenseStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1)).Return(types.RecurringExpense{}, false, nil)
+			},
+		})
+
+		// ACT
+		_, err := r.SetPaused(context.Background(), test.User, 1, true)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("zero interval", func(t *testing.T) {
+		// ARRANGE
+		r, _ := setupRecurrer(t, recurrerMocksInitializer{
+			storage: func(m *mocks.MockRecurringExpenseStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1)).Return(types.RecurringExpense{
+					ID:         1,
+					Recurrence: types.Recurrence{Frequency: types.FrequencyDaily, Start: today.AddDate(0, 0, -10)},
+					Next:       today.AddDate(0, 0, -10),
+					Paused:     true,
+				}, true, nil)
+			},
+		})
+
+		// ACT
+		_, err := r.SetPaused(context.Background(), test.User, 1, false)
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrWrongRecurrence)
+	})
+
+	t.Run("resume skips missed occurrences", func(t *testing.T) {
+		// ARRANGE
+		item := types.RecurringExpense{
+			ID:         1,
+			Recurrence: types.Recurrence{Frequency: types.FrequencyDaily, Interval: 1, Start: today.AddDate(0, 0, -10)},
+			Amount:     1500000,
+			Currency:   "RUB",
+			Category:   "lunch",
+			Count:      3,
+			Next:       today.AddDate(0, 0, -7),
+			Paused:     true,
+		}
+
+		resumed := item
+		resumed.Count = 10
+		resumed.Next = today
+		resumed.Paused = false
+
+		r, _ := setupRecurrer(t, recurrerMocksInitializer{
+			storage: func(m *mocks.MockRecurringExpenseStorage) {
+				m.EXPECT().Get(gomock.AssignableToTypeOf(test.CtxInterface), test.User, int64(1)).Return(item, true, nil)
+				m.EXPECT().Update(gomock.AssignableToTypeOf(test.CtxInterface), test.User, resumed).Return(nil)
+			},
+		})
+
+		// ACT
+		got, err := r.SetPaused(context.Background(), test.User, 1, false)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, resumed, got)
+	})
+}
+
+func Test_recurrer_addDue(t *testing.T) {
+	today := time.Date(2022, 11, 10, 0, 0, 0, 0, time.UTC)
+	item := types.RecurringExpense{
+		ID:         1,
+		Recurrence: types.Recurrence{Frequency: types.FrequencyMonthly, Interval: 1, Start: time.Date(2022, 9, 5, 0, 0, 0, 0, time.UTC)},
+		Amount:     300000000,
+		Currency:   "RUB",
+		Category:   "rent",
+		Count:      1,
+		Next:       time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	addRequest := func(date time.Time) request.AddExpense {
+		return request.AddExpense{
+			User:      test.User,
+			Date:      date,
+			Amount:    300000000,
+			Currency:  "RUB",
+			Category:  "rent",
+			Scheduled: true,
+		}
+	}
+
+	t.Run("adds all due occurrences", func(t *testing.T) {
+		// ARRANGE
+		updated := item
+		updated.Count = 3
+		updated.Next = time.Date(2022, 12, 5, 0, 0, 0, 0, time.UTC)
+
+		r, a := setupRecurrer(t, recurrerMocksInitializer{
+			storage: func(m *mocks.MockRecurringExpenseStorage) {
+				gomock.InOrder(
+					m.EXPECT().LockDue(gomock.AssignableToTypeOf(test.CtxInterface), today, nil).Return(test.User, item, true, nil),
+					m.EXPECT().Update(gomock.AssignableToTypeOf(test.CtxInterface), test.User, updated).Return(nil),
+					m.EXPECT().LockDue(gomock.AssignableToTypeOf(test.CtxInterface), today, nil).Return(nil, types.RecurringExpense{}, false, nil),
+				)
+			},
+			adder: func(m *mmocks.MockController) {
+				gomock.InOrder(
+					m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), addRequest(time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC))).
+						Return(response.AddExpense{Ready: true, Success: true}),
+					m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), addRequest(time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC))).
+						Return(response.AddExpense{Ready: true, Success: true}),
+				)
+			},
+		})
+
+		// ACT
+		r.addDue(context.Background(), a, today)
+	})
+
+	t.Run("failed occurrence is skipped till the next run", func(t *testing.T) {
+		// ARRANGE
+		r, a := setupRecurrer(t, recurrerMocksInitializer{
+			storage: func(m *mocks.MockRecurringExpenseStorage) {
+				gomock.InOrder(
+					m.EXPECT().LockDue(gomock.AssignableToTypeOf(test.CtxInterface), today, nil).Return(test.User, item, true, nil),
+					m.EXPECT().LockDue(gomock.AssignableToTypeOf(test.CtxInterface), today, []int64{1}).Return(nil, types.RecurringExpense{}, false, nil),
+				)
+			},
+			adder: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), addRequest(item.Next)).
+					Return(response.AddExpense{Ready: true, UnknownCurrency: true})
+			},
+		})
+
+		// ACT
+		r.addDue(context.Background(), a, today)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		// ARRANGE
+		r, a := setupRecurrer(t, recurrerMocksInitializer{
+			storage: func(m *mocks.MockRecurringExpenseStorage) {
+				m.EXPECT().LockDue(gomock.AssignableToTypeOf(test.CtxInterface), today, nil).Return(test.User, item, true, nil)
+			},
+			adder: func(m *mmocks.MockController) {
+				m.EXPECT().AddExpense(gomock.AssignableToTypeOf(test.CtxInterface), addRequest(item.Next)).Return(response.AddExpense{})
+			},
+		})
+
+		// ACT
+		r.addDue(context.Background(), a, today)
+	})
+}
@@ -0,0 +1,123 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	_defaultRelayInterval  = 5 * time.Second
+	_defaultRelayBatchSize = 100
+)
+
+type publisher interface {
+	Publish(ctx context.Context, event types.OutboxEvent) error
+}
+
+type relay struct {
+	interval   time.Duration
+	batchSize  int
+	storage    storage.OutboxStorage
+	unitOfWork storage.UnitOfWork
+	publisher  publisher
+	logger     *zap.Logger
+}
+
+// NewRelay creates the relay publishing the events written to the outbox along with the changes they describe.
+func NewRelay(cfg config.OutboxConfig, s storage.OutboxStorage, uow storage.UnitOfWork, p publisher, l *zap.Logger) *relay {
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = _defaultRelayInterval
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = _defaultRelayBatchSize
+	}
+
+	return &relay{
+		interval:   interval,
+		batchSize:  batchSize,
+		storage:    s,
+		unitOfWork: uow,
+		publisher:  p,
+		logger:     l,
+	}
+}
+
+// Run publishes the outbox events until the context is done.
+func (r *relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.publishPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishPending publishes the events batch by batch in their order and removes the published ones
+// from the outbox in the same transaction. An event is published at least once: the batch is published
+// again when the transaction fails after sending it. Publishing stops at the first failed event, it's
+// retried on the next run.
+func (r *relay) publishPending(ctx context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "relay.publishPending")
+	defer span.Finish()
+
+	for ctx.Err() == nil {
+		var (
+			locked       int
+			publishErr   error
+			publishedIDs []int64
+		)
+
+		err := r.unitOfWork.Do(ctx, func(ctx context.Context) error {
+			events, err := r.storage.LockPending(ctx, r.batchSize)
+			if err != nil {
+				return errors.Wrap(err, "OutboxStorage.LockPending")
+			}
+
+			locked, publishedIDs = len(events), make([]int64, 0, len(events))
+			for _, event := range events {
+				if publishErr = r.publisher.Publish(ctx, event); publishErr != nil {
+					publishErr = errors.Wrapf(publishErr, "publish outbox event %d", event.ID)
+					break
+				}
+
+				publishedIDs = append(publishedIDs, event.ID)
+			}
+
+			if len(publishedIDs) == 0 {
+				return nil
+			}
+
+			return errors.Wrap(r.storage.Delete(ctx, publishedIDs), "OutboxStorage.Delete")
+		})
+
+		switch {
+		case err != nil:
+			r.logger.Error("cannot relay outbox events", zap.Error(err))
+			return
+
+		case publishErr != nil:
+			r.logger.Error("cannot publish outbox event", zap.Error(publishErr))
+			return
+
+		case locked < r.batchSize:
+			return
+		}
+	}
+}
@@ -0,0 +1,120 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	emocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model/expense"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"go.uber.org/zap"
+)
+
+type relayMocksInitializer struct {
+	storage   func(m *mocks.MockOutboxStorage)
+	publisher func(m *emocks.Mockpublisher)
+}
+
+func setupRelay(t *testing.T, batchSize int, i relayMocksInitializer) *relay {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockOutboxStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	publisherMock := emocks.NewMockpublisher(ctrl)
+	if i.publisher != nil {
+		i.publisher(publisherMock)
+	}
+
+	unitOfWorkMock := mocks.NewMockUnitOfWork(ctrl)
+	unitOfWorkMock.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return NewRelay(config.OutboxConfig{BatchSize: batchSize}, storageMock, unitOfWorkMock, publisherMock, zap.NewNop())
+}
+
+func Test_relay_publishPending(t *testing.T) {
+	first := types.OutboxEvent{ID: 1, Kind: "expense.added", Payload: []byte(`{"id":1}`)}
+	second := types.OutboxEvent{ID: 2, Kind: "expense.deleted", Payload: []byte(`{"id":1}`)}
+	third := types.OutboxEvent{ID: 3, Kind: "expense.added", Payload: []byte(`{"id":2}`)}
+
+	t.Run("batches published and pruned", func(t *testing.T) {
+		// ARRANGE
+		r := setupRelay(t, 2, relayMocksInitializer{
+			storage: func(m *mocks.MockOutboxStorage) {
+				gomock.InOrder(
+					m.EXPECT().LockPending(gomock.AssignableToTypeOf(test.CtxInterface), 2).Return([]types.OutboxEvent{first, second}, nil),
+					m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), []int64{1, 2}).Return(nil),
+					m.EXPECT().LockPending(gomock.AssignableToTypeOf(test.CtxInterface), 2).Return([]types.OutboxEvent{third}, nil),
+					m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), []int64{3}).Return(nil),
+				)
+			},
+			publisher: func(m *emocks.Mockpublisher) {
+				gomock.InOrder(
+					m.EXPECT().Publish(gomock.AssignableToTypeOf(test.CtxInterface), first).Return(nil),
+					m.EXPECT().Publish(gomock.AssignableToTypeOf(test.CtxInterface), second).Return(nil),
+					m.EXPECT().Publish(gomock.AssignableToTypeOf(test.CtxInterface), third).Return(nil),
+				)
+			},
+		})
+
+		// ACT
+		r.publishPending(context.Background())
+	})
+
+	t.Run("nothing pending", func(t *testing.T) {
+		// ARRANGE
+		r := setupRelay(t, 2, relayMocksInitializer{
+			storage: func(m *mocks.MockOutboxStorage) {
+				m.EXPECT().LockPending(gomock.AssignableToTypeOf(test.CtxInterface), 2).Return(nil, nil)
+			},
+		})
+
+		// ACT
+		r.publishPending(context.Background())
+	})
+
+	t.Run("published part pruned on failure", func(t *testing.T) {
+		// ARRANGE
+		r := setupRelay(t, 2, relayMocksInitializer{
+			storage: func(m *mocks.MockOutboxStorage) {
+				gomock.InOrder(
+					m.EXPECT().LockPending(gomock.AssignableToTypeOf(test.CtxInterface), 2).Return([]types.OutboxEvent{first, second}, nil),
+					m.EXPECT().Delete(gomock.AssignableToTypeOf(test.CtxInterface), []int64{1}).Return(nil),
+				)
+			},
+			publisher: func(m *emocks.Mockpublisher) {
+				gomock.InOrder(
+					m.EXPECT().Publish(gomock.AssignableToTypeOf(test.CtxInterface), first).Return(nil),
+					m.EXPECT().Publish(gomock.AssignableToTypeOf(test.CtxInterface), second).Return(test.SimpleError),
+				)
+			},
+		})
+
+		// ACT
+		r.publishPending(context.Background())
+	})
+
+	t.Run("first event failed", func(t *testing.T) {
+		// ARRANGE
+		r := setupRelay(t, 2, relayMocksInitializer{
+			storage: func(m *mocks.MockOutboxStorage) {
+				m.EXPECT().LockPending(gomock.AssignableToTypeOf(test.CtxInterface), 2).Return([]types.OutboxEvent{first, second}, nil)
+			},
+			publisher: func(m *emocks.Mockpublisher) {
+				m.EXPECT().Publish(gomock.AssignableToTypeOf(test.CtxInterface), first).Return(test.SimpleError)
+			},
+		})
+
+		// ACT
+		r.publishPending(context.Background())
+	})
+}
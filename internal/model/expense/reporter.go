@@ -13,7 +13,7 @@ import (
 
 type (
 	producer interface {
-		Send(ctx context.Context, user *types.User, from time.Time, currency string) error
+		Send(ctx context.Context, user *types.User, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange, currency string) error
 	}
 
 	listener interface {
@@ -38,10 +38,13 @@ func NewReporter(timeout time.Duration, p producer, ls listener, l *zap.Logger)
 	}
 }
 
-func (r *reporter) GetReport(ctx context.Context, user *types.User, from time.Time, currency string) (map[string]int64, error) {
+func (r *reporter) GetReport(ctx context.Context, user *types.User, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange, currency string) (types.ReportData, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "reporter.GetReport", opentracing.Tags{
 		"user":     *user,
 		"from":     from,
+		"to":       to,
+		"grouping": grouping,
+		"compare":  compare,
 		"currency": currency,
 	})
 	defer span.Finish()
@@ -52,22 +55,22 @@ func (r *reporter) GetReport(ctx context.Context, user *types.User, from time.Ti
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	if err := r.producer.Send(ctx, user, from, currency); err != nil {
-		return nil, err
+	if err := r.producer.Send(ctx, user, from, to, grouping, compare, currency); err != nil {
+		return types.ReportData{}, err
 	}
 
 	select {
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return types.ReportData{}, ctx.Err()
 	case report := <-reportCh:
 		if report.Success {
-			return report.Data, nil
+			return report.ReportData, nil
 		}
 
 		if report.Error == model.ErrNotReady.Error() {
-			return nil, model.ErrNotReady
+			return types.ReportData{}, model.ErrNotReady
 		}
 
-		return nil, errors.New(report.Error)
+		return types.ReportData{}, errors.New(report.Error)
 	}
 }
@@ -42,7 +42,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		// ARRANGE
 		r := setupReporter(t, 0, reporterMocksInitializer{
 			producer: func(m *mocks.Mockproducer) {
-				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, "RUB").Return(test.SimpleError)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, test.Tomorrow, types.GroupingNone, types.DateRange{}, "RUB").Return(test.SimpleError)
 			},
 			listener: func(m *mocks.Mocklistener) {
 				m.EXPECT().Subscribe(test.User).Return(make(<-chan types.Report))
@@ -51,7 +51,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		})
 
 		// ACT
-		data, err := r.GetReport(context.Background(), test.User, test.Yesterday, "RUB")
+		data, err := r.GetReport(context.Background(), test.User, test.Yesterday, test.Tomorrow, types.GroupingNone, types.DateRange{}, "RUB")
 
 		// ASSERT
 		assert.Error(t, err)
@@ -62,7 +62,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		// ARRANGE
 		r := setupReporter(t, time.Millisecond, reporterMocksInitializer{
 			producer: func(m *mocks.Mockproducer) {
-				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, "USD").Return(nil)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, test.Tomorrow, types.GroupingNone, types.DateRange{}, "USD").Return(nil)
 			},
 			listener: func(m *mocks.Mocklistener) {
 				m.EXPECT().Subscribe(test.User).Return(make(<-chan types.Report))
@@ -71,7 +71,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		})
 
 		// ACT
-		data, err := r.GetReport(context.Background(), test.User, test.Yesterday, "USD")
+		data, err := r.GetReport(context.Background(), test.User, test.Yesterday, test.Tomorrow, types.GroupingNone, types.DateRange{}, "USD")
 
 		// ASSERT
 		assert.Error(t, err)
@@ -83,7 +83,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		// ARRANGE
 		r := setupReporter(t, time.Second, reporterMocksInitializer{
 			producer: func(m *mocks.Mockproducer) {
-				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, "EUR").Return(nil)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "EUR").Return(nil)
 			},
 			listener: func(m *mocks.Mocklistener) {
 				reportCh := make(chan types.Report)
@@ -99,7 +99,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		})
 
 		// ACT
-		data, err := r.GetReport(context.Background(), test.User, test.Today, "EUR")
+		data, err := r.GetReport(context.Background(), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "EUR")
 
 		// ASSERT
 		assert.Equal(t, model.ErrNotReady, err)
@@ -110,7 +110,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		// ARRANGE
 		r := setupReporter(t, time.Second, reporterMocksInitializer{
 			producer: func(m *mocks.Mockproducer) {
-				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, "RUB").Return(nil)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "RUB").Return(nil)
 			},
 			listener: func(m *mocks.Mocklistener) {
 				reportCh := make(chan types.Report)
@@ -126,7 +126,7 @@ func Test_reporter_GetReport(t *testing.T) {
 		})
 
 		// ACT
-		data, err := r.GetReport(context.Background(), test.User, test.Today, "RUB")
+		data, err := r.GetReport(context.Background(), test.User, test.Today, test.Tomorrow, types.GroupingNone, types.DateRange{}, "RUB")
 
 		// ASSERT
 		assert.Error(t, err)
@@ -137,15 +137,26 @@ func Test_reporter_GetReport(t *testing.T) {
 		// ARRANGE
 		r := setupReporter(t, time.Second, reporterMocksInitializer{
 			producer: func(m *mocks.Mockproducer) {
-				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, "USD").Return(nil)
+				m.EXPECT().Send(gomock.AssignableToTypeOf(test.CtxInterface), test.User, test.Yesterday, test.Tomorrow, types.GroupingMonth, types.DateRange{}, "USD").Return(nil)
 			},
 			listener: func(m *mocks.Mocklistener) {
 				reportCh := make(chan types.Report)
 				go func() {
 					reportCh <- types.Report{
-						Data: map[string]int64{
-							"taxi":   220000,
-							"coffee": 250000,
+						ReportData: types.ReportData{
+							Data: map[string]int64{
+								"taxi":   220000,
+								"coffee": 250000,
+							},
+							Buckets: []types.ReportBucket{
+								{
+									Start: test.Yesterday,
+									Data: map[string]int64{
+										"taxi":   220000,
+										"coffee": 250000,
+									},
+								},
+							},
 						},
 						Success: true,
 					}
@@ -156,13 +167,24 @@ func Test_reporter_GetReport(t *testing.T) {
 		})
 
 		// ACT
-		data, err := r.GetReport(context.Background(), test.User, test.Yesterday, "USD")
+		data, err := r.GetReport(context.Background(), test.User, test.Yesterday, test.Tomorrow, types.GroupingMonth, types.DateRange{}, "USD")
 
 		// ASSERT
 		assert.NoError(t, err)
-		assert.Equal(t, map[string]int64{
-			"taxi":   220000,
-			"coffee": 250000,
+		assert.Equal(t, types.ReportData{
+			Data: map[string]int64{
+				"taxi":   220000,
+				"coffee": 250000,
+			},
+			Buckets: []types.ReportBucket{
+				{
+					Start: test.Yesterday,
+					Data: map[string]int64{
+						"taxi":   220000,
+						"coffee": 250000,
+					},
+				},
+			},
 		}, data)
 	})
 }
@@ -115,6 +115,80 @@ func (m *Report) validate(all bool) error {
 
 	}
 
+	for idx, item := range m.GetBuckets() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ReportValidationError{
+						field:  fmt.Sprintf("Buckets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ReportValidationError{
+						field:  fmt.Sprintf("Buckets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ReportValidationError{
+					field:  fmt.Sprintf("Buckets[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Previous
+
+	// no validation rules for Income
+
+	// no validation rules for PreviousIncome
+
+	for idx, item := range m.GetMembers() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ReportValidationError{
+						field:  fmt.Sprintf("Members[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ReportValidationError{
+						field:  fmt.Sprintf("Members[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ReportValidationError{
+					field:  fmt.Sprintf("Members[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return ReportMultiError(errors)
 	}
@@ -192,6 +266,222 @@ var _ interface {
 	ErrorName() string
 } = ReportValidationError{}
 
+// Validate checks the field values on MemberTotal with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *MemberTotal) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MemberTotal with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in MemberTotalMultiError, or
+// nil if none found.
+func (m *MemberTotal) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MemberTotal) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Name
+
+	// no validation rules for Amount
+
+	if len(errors) > 0 {
+		return MemberTotalMultiError(errors)
+	}
+
+	return nil
+}
+
+// MemberTotalMultiError is an error wrapping multiple validation errors
+// returned by MemberTotal.ValidateAll() if the designated constraints aren't met.
+type MemberTotalMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MemberTotalMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MemberTotalMultiError) AllErrors() []error { return m }
+
+// MemberTotalValidationError is the validation error returned by
+// MemberTotal.Validate if the designated constraints aren't met.
+type MemberTotalValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MemberTotalValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MemberTotalValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MemberTotalValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MemberTotalValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MemberTotalValidationError) ErrorName() string { return "MemberTotalValidationError" }
+
+// Error satisfies the builtin error interface
+func (e MemberTotalValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMemberTotal.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MemberTotalValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MemberTotalValidationError{}
+
+// Validate checks the field values on Bucket with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Bucket) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Bucket with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in BucketMultiError, or nil if none found.
+func (m *Bucket) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Bucket) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetStart() == nil {
+		err := BucketValidationError{
+			field:  "Start",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Data
+
+	// no validation rules for Income
+
+	if len(errors) > 0 {
+		return BucketMultiError(errors)
+	}
+
+	return nil
+}
+
+// BucketMultiError is an error wrapping multiple validation errors returned by
+// Bucket.ValidateAll() if the designated constraints aren't met.
+type BucketMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BucketMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BucketMultiError) AllErrors() []error { return m }
+
+// BucketValidationError is the validation error returned by Bucket.Validate if
+// the designated constraints aren't met.
+type BucketValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BucketValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BucketValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BucketValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BucketValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BucketValidationError) ErrorName() string { return "BucketValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BucketValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBucket.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BucketValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BucketValidationError{}
+
 // Validate checks the field values on User with the rules defined in the proto
 // definition for this message. If any rules are violated, the first error
 // encountered is returned, or nil if there are no violations.
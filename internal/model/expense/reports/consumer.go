@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/opentracing/opentracing-go"
@@ -19,10 +20,12 @@ import (
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model/expense/reports/api"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type consumer struct {
@@ -36,12 +39,14 @@ type consumer struct {
 	grpcClient     api.ReporterClient
 
 	storage storage.ExpenseStorage
+	incomes storage.IncomeStorage
+	groups  storage.GroupStorage
 	rater   model.Rater
 
 	logger *zap.Logger
 }
 
-func NewConsumer(kafkaCfg config.ReportsKafkaConfig, grpcCfg config.ReportsGrpcConfig, s storage.ExpenseStorage, r model.Rater, l *zap.Logger) (*consumer, error) {
+func NewConsumer(kafkaCfg config.ReportsKafkaConfig, grpcCfg config.ReportsGrpcConfig, s storage.ExpenseStorage, i storage.IncomeStorage, g storage.GroupStorage, r model.Rater, l *zap.Logger) (*consumer, error) {
 	saramaCfg := sarama.NewConfig()
 	saramaCfg.Version = sarama.V2_5_0_0
 	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
@@ -67,6 +72,8 @@ func NewConsumer(kafkaCfg config.ReportsKafkaConfig, grpcCfg config.ReportsGrpcC
 		grpcAddress: grpcCfg.ClientAddress,
 
 		storage: s,
+		incomes: i,
+		groups:  g,
 		rater:   r,
 
 		logger: l,
@@ -159,31 +166,224 @@ func (c *consumer) handleMessage(ctx context.Context, saramaMessage *sarama.Cons
 		return
 	}
 
+	current := types.DateRange{From: reportMessage.From, To: reportMessage.To}
+	compared := !reportMessage.Compare.IsZero()
+
+	// both ranges are fetched at once, so every expense is exchanged at its own date only once
+	queried := current
+	if compared {
+		queried = unionRange(current, reportMessage.Compare)
+	}
+
+	// the report of a group member covers the whole group
 	user := types.User(userID)
-	expenses, err := c.storage.List(ctx, &user, reportMessage.From)
+	group, grouped, err := c.groups.GetByUser(ctx, &user)
 	if err != nil {
-		logger.Error("ExpenseStorage.List", zap.Error(err))
+		logger.Error("GroupStorage.GetByUser", zap.Error(err))
 		return
 	}
 
-	data := make(map[string]int64)
+	members := []types.GroupMember{{User: &user}}
+	if grouped {
+		members = group.Members
+	}
 
-	for category := range expenses {
-		for _, item := range expenses[category] {
-			amount, err := c.rater.Exchange(ctx, item.Amount, item.Currency, reportMessage.Currency, item.Date)
-			if err != nil {
-				logger.Error("cannot exchange currency", zap.Error(err), zap.String("from", item.Currency), zap.String("to", reportMessage.Currency))
-				return
+	expenses, incomes := newAggregation(), newAggregation()
+	for _, member := range members {
+		totals, err := c.storage.SumByDate(ctx, member.User, queried.From, queried.To)
+		if err != nil {
+			logger.Error("ExpenseStorage.SumByDate", zap.Error(err))
+			return
+		}
+
+		memberExpenses, err := c.aggregate(ctx, reportMessage, totals)
+		if err != nil {
+			logger.Error("cannot aggregate expenses", zap.Error(err))
+			return
+		}
+
+		incomeTotals, err := c.incomes.SumByDate(ctx, member.User, queried.From, queried.To)
+		if err != nil {
+			logger.Error("IncomeStorage.SumByDate", zap.Error(err))
+			return
+		}
+
+		memberIncomes, err := c.aggregate(ctx, reportMessage, incomeTotals)
+		if err != nil {
+			logger.Error("cannot aggregate incomes", zap.Error(err))
+			return
+		}
+
+		expenses.merge(memberExpenses)
+		incomes.merge(memberIncomes)
+
+		if grouped {
+			var spent int64
+			for _, amount := range memberExpenses.data {
+				spent += amount
 			}
 
-			data[category] += amount
+			report.Members = append(report.Members, &api.MemberTotal{
+				Name:   member.Name,
+				Amount: spent,
+			})
 		}
 	}
 
-	report.Data = data
+	report.Data = expenses.data
+	report.Income = incomes.data
+	if compared {
+		report.Previous = expenses.previous
+		report.PreviousIncome = incomes.previous
+	}
+	if reportMessage.Grouping != types.GroupingNone {
+		report.Buckets = bucketsSequence(reportMessage, expenses.buckets, incomes.buckets)
+	}
 	report.Success = true
 }
 
+// aggregation holds the totals by category of the report range, the compared range and the buckets.
+type aggregation struct {
+	data     map[string]int64
+	previous map[string]int64
+	buckets  map[time.Time]map[string]int64
+}
+
+func newAggregation() aggregation {
+	return aggregation{
+		data:     make(map[string]int64),
+		previous: make(map[string]int64),
+		buckets:  make(map[time.Time]map[string]int64),
+	}
+}
+
+// merge adds up the totals of the other aggregation.
+func (a aggregation) merge(other aggregation) {
+	for category, amount := range other.data {
+		a.data[category] += amount
+	}
+
+	for category, amount := range other.previous {
+		a.previous[category] += amount
+	}
+
+	for start, data := range other.buckets {
+		if a.buckets[start] == nil {
+			a.buckets[start] = make(map[string]int64)
+		}
+		for category, amount := range data {
+			a.buckets[start][category] += amount
+		}
+	}
+}
+
+// aggregate exchanges the totals into the report currency at their own dates and sums them up by category.
+func (c *consumer) aggregate(ctx context.Context, reportMessage message.Report, totals []types.ExpenseTotal) (aggregation, error) {
+	current := types.DateRange{From: reportMessage.From, To: reportMessage.To}
+	compared := !reportMessage.Compare.IsZero()
+
+	result := newAggregation()
+
+	for _, item := range totals {
+		inCurrent := current.Contains(item.Date)
+		inPrevious := compared && reportMessage.Compare.Contains(item.Date)
+		if !inCurrent && !inPrevious {
+			continue
+		}
+
+		amount, err := c.rater.Exchange(ctx, item.Amount, item.Currency, reportMessage.Currency, item.Date)
+		if err != nil {
+			return aggregation{}, errors.Wrapf(err, "exchange %s to %s", item.Currency, reportMessage.Currency)
+		}
+
+		if inPrevious {
+			result.previous[item.Category] += amount
+		}
+
+		if !inCurrent {
+			continue
+		}
+
+		result.data[item.Category] += amount
+
+		if reportMessage.Grouping != types.GroupingNone {
+			start := bucketStart(reportMessage.Grouping, item.Date)
+			if result.buckets[start] == nil {
+				result.buckets[start] = make(map[string]int64)
+			}
+			result.buckets[start][item.Category] += amount
+		}
+	}
+
+	return result, nil
+}
+
+// bucketsSequence returns buckets covering the whole report range in chronological order,
+// including the ones without expenses and incomes.
+func bucketsSequence(reportMessage message.Report, buckets, incomeBuckets map[time.Time]map[string]int64) []*api.Bucket {
+	to := reportMessage.To
+	if to.IsZero() {
+		for _, m := range []map[time.Time]map[string]int64{buckets, incomeBuckets} {
+			for start := range m {
+				if end := bucketEnd(reportMessage.Grouping, start); end.After(to) {
+					to = end
+				}
+			}
+		}
+	}
+
+	sequence := make([]*api.Bucket, 0, len(buckets))
+	for start := bucketStart(reportMessage.Grouping, reportMessage.From); start.Before(to); start = bucketEnd(reportMessage.Grouping, start) {
+		sequence = append(sequence, &api.Bucket{
+			Start:  timestamppb.New(start),
+			Data:   buckets[start],
+			Income: incomeBuckets[start],
+		})
+	}
+
+	return sequence
+}
+
+// unionRange returns the smallest range covering both of the ranges.
+func unionRange(a, b types.DateRange) types.DateRange {
+	union := a
+	if b.From.Before(union.From) {
+		union.From = b.From
+	}
+
+	if union.To.IsZero() || b.To.IsZero() {
+		union.To = time.Time{}
+	} else if b.To.After(union.To) {
+		union.To = b.To
+	}
+
+	return union
+}
+
+func bucketStart(grouping types.ReportGrouping, date time.Time) time.Time {
+	date = utils.TruncateToDate(date)
+
+	switch grouping {
+	case types.GroupingWeek:
+		return date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	case types.GroupingMonth:
+		return date.AddDate(0, 0, 1-date.Day())
+	}
+
+	return date
+}
+
+func bucketEnd(grouping types.ReportGrouping, start time.Time) time.Time {
+	switch grouping {
+	case types.GroupingWeek:
+		return start.AddDate(0, 0, 7)
+	case types.GroupingMonth:
+		return start.AddDate(0, 1, 0)
+	}
+
+	return start.AddDate(0, 0, 1)
+}
+
 func (c *consumer) sendReport(ctx context.Context, report *api.Report) {
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		traceBuffer := new(bytes.Buffer)
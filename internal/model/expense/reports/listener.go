@@ -88,8 +88,32 @@ func (l *listener) SendReport(ctx context.Context, in *api.Report) (*emptypb.Emp
 
 	user := types.User(in.User.Id)
 	if subscriber, ok := l.subscribers[user]; ok {
+		buckets := make([]types.ReportBucket, 0, len(in.Buckets))
+		for _, bucket := range in.Buckets {
+			buckets = append(buckets, types.ReportBucket{
+				Start:  bucket.Start.AsTime(),
+				Data:   bucket.Data,
+				Income: bucket.Income,
+			})
+		}
+
+		var members []types.MemberTotal
+		for _, member := range in.Members {
+			members = append(members, types.MemberTotal{
+				Name:   member.Name,
+				Amount: member.Amount,
+			})
+		}
+
 		subscriber <- types.Report{
-			Data:    in.Data,
+			ReportData: types.ReportData{
+				Data:           in.Data,
+				Income:         in.Income,
+				Buckets:        buckets,
+				Previous:       in.Previous,
+				PreviousIncome: in.PreviousIncome,
+				Members:        members,
+			},
 			Success: in.Success,
 			Error:   in.Error,
 		}
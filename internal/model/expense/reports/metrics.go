@@ -50,7 +50,9 @@ var (
 
 func metricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	start := time.Now()
-	defer _consumeTime.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	defer func() {
+		_consumeTime.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	}()
 
 	return handler(ctx, req)
 }
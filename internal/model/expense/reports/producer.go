@@ -15,8 +15,11 @@ import (
 	"go.uber.org/zap"
 )
 
+const _defaultEventsTopic = "events"
+
 type producer struct {
 	topic          string
+	eventsTopic    string
 	saramaProducer sarama.SyncProducer
 	logger         *zap.Logger
 }
@@ -40,16 +43,25 @@ func NewProducer(kafkaCfg config.ReportsKafkaConfig, l *zap.Logger) (*producer,
 		return nil, errors.Wrap(err, "starting sarama sync producer")
 	}
 
+	eventsTopic := kafkaCfg.EventsTopic
+	if eventsTopic == "" {
+		eventsTopic = _defaultEventsTopic
+	}
+
 	return &producer{
 		topic:          kafkaCfg.Topic,
+		eventsTopic:    eventsTopic,
 		saramaProducer: saramaProducer,
 		logger:         l,
 	}, nil
 }
 
-func (p *producer) Send(ctx context.Context, user *types.User, from time.Time, currency string) error {
+func (p *producer) Send(ctx context.Context, user *types.User, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange, currency string) error {
 	value, err := json.Marshal(message.Report{
 		From:     from,
+		To:       to,
+		Grouping: grouping,
+		Compare:  compare,
 		Currency: currency,
 	})
 	if err != nil {
@@ -62,17 +74,7 @@ func (p *producer) Send(ctx context.Context, user *types.User, from time.Time, c
 		Value: sarama.ByteEncoder(value),
 	}
 
-	if span := opentracing.SpanFromContext(ctx); span != nil {
-		buf := new(bytes.Buffer)
-		if err := span.Tracer().Inject(span.Context(), opentracing.Binary, buf); err != nil {
-			p.logger.Error("failed to inject span ID", zap.Error(err))
-		} else {
-			msg.Headers = []sarama.RecordHeader{{
-				Key:   []byte("x-trace"),
-				Value: buf.Bytes(),
-			}}
-		}
-	}
+	p.injectSpan(ctx, msg)
 
 	_, _, err = p.saramaProducer.SendMessage(msg)
 	if err != nil {
@@ -84,6 +86,42 @@ func (p *producer) Send(ctx context.Context, user *types.User, from time.Time, c
 	return nil
 }
 
+// Publish sends the outbox event to the events topic, the events of the same kind share the partition
+// to keep their order.
+func (p *producer) Publish(ctx context.Context, event types.OutboxEvent) error {
+	msg := &sarama.ProducerMessage{
+		Topic: p.eventsTopic,
+		Key:   sarama.StringEncoder(event.Kind),
+		Value: sarama.ByteEncoder(event.Payload),
+	}
+
+	p.injectSpan(ctx, msg)
+
+	if _, _, err := p.saramaProducer.SendMessage(msg); err != nil {
+		return errors.Wrap(err, "failed to send event message to kafka")
+	}
+
+	return nil
+}
+
+func (p *producer) injectSpan(ctx context.Context, msg *sarama.ProducerMessage) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := span.Tracer().Inject(span.Context(), opentracing.Binary, buf); err != nil {
+		p.logger.Error("failed to inject span ID", zap.Error(err))
+		return
+	}
+
+	msg.Headers = []sarama.RecordHeader{{
+		Key:   []byte("x-trace"),
+		Value: buf.Bytes(),
+	}}
+}
+
 func (p *producer) Close() {
 	if err := p.saramaProducer.Close(); err != nil {
 		p.logger.Error("failed to close sarama producer", zap.Error(err))
@@ -0,0 +1,193 @@
+package expense
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type saver struct {
+	storage storage.GoalStorage
+	rater   model.Rater
+}
+
+func NewSaver(s storage.GoalStorage, r model.Rater) *saver {
+	return &saver{
+		storage: s,
+		rater:   r,
+	}
+}
+
+// AddGoal adds the goal, names are case-insensitive and unique per user.
+func (s *saver) AddGoal(ctx context.Context, user *types.User, goal types.Goal) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "saver.AddGoal", opentracing.Tags{
+		"user":     *user,
+		"name":     goal.Name,
+		"target":   goal.Target,
+		"currency": goal.Currency,
+		"deadline": goal.Deadline,
+	})
+	defer span.Finish()
+
+	goal.Name = normalizeGoalName(goal.Name)
+	switch {
+	case goal.Name == "":
+		return model.ErrEmptyName
+	case goal.Target <= 0:
+		return model.ErrNonPositiveAmount
+	}
+
+	added, err := s.storage.Add(ctx, user, goal)
+	if err != nil {
+		return errors.Wrap(err, "GoalStorage.Add")
+	}
+
+	if !added {
+		return model.ErrAlreadyExists
+	}
+
+	return nil
+}
+
+// Contribute puts the amount aside for the goal with the name, the contribution is in the goal currency
+// unless another one is given. The negative amount takes the money back.
+func (s *saver) Contribute(ctx context.Context, user *types.User, name string, date time.Time, amount int64, currency string) (types.Goal, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "saver.Contribute", opentracing.Tags{
+		"user":     *user,
+		"name":     name,
+		"date":     date,
+		"amount":   amount,
+		"currency": currency,
+	})
+	defer span.Finish()
+
+	goal, err := s.getGoal(ctx, user, name)
+	if err != nil {
+		return types.Goal{}, err
+	}
+
+	if currency == "" {
+		currency = goal.Currency
+	}
+
+	err = s.storage.AddContribution(ctx, user, types.GoalContribution{
+		Goal:     goal.ID,
+		Date:     date,
+		Amount:   amount,
+		Currency: currency,
+	})
+	if err != nil {
+		return types.Goal{}, errors.Wrap(err, "GoalStorage.AddContribution")
+	}
+
+	return goal, nil
+}
+
+func (s *saver) DeleteGoal(ctx context.Context, user *types.User, name string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "saver.DeleteGoal", opentracing.Tags{
+		"user": *user,
+		"name": name,
+	})
+	defer span.Finish()
+
+	goal, err := s.getGoal(ctx, user, name)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(s.storage.Delete(ctx, user, goal.ID), "GoalStorage.Delete")
+}
+
+// ListGoals returns progress of the user goals, the contributions are exchanged into the goal currency
+// and the saved amount is converted into the currency at the rate of the date.
+func (s *saver) ListGoals(ctx context.Context, user *types.User, currency string, date time.Time) ([]types.GoalProgress, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "saver.ListGoals", opentracing.Tags{
+		"user":     *user,
+		"currency": currency,
+		"date":     date,
+	})
+	defer span.Finish()
+
+	goals, err := s.storage.List(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "GoalStorage.List")
+	}
+
+	totals, err := s.storage.Totals(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "GoalStorage.Totals")
+	}
+
+	list := make([]types.GoalProgress, 0, len(goals))
+	indexes := make(map[int64]int, len(goals))
+	for i, goal := range goals {
+		list = append(list, types.GoalProgress{Goal: goal})
+		indexes[goal.ID] = i
+	}
+
+	for _, total := range totals {
+		i, ok := indexes[total.Goal]
+		if !ok {
+			continue
+		}
+
+		amount, err := s.rater.Exchange(ctx, total.Amount, total.Currency, list[i].Currency, date)
+		if err != nil {
+			return nil, errors.Wrapf(err, "exchange %s to %s", total.Currency, list[i].Currency)
+		}
+
+		list[i].Saved += amount
+	}
+
+	for i := range list {
+		if list[i].Converted, err = s.rater.Exchange(ctx, list[i].Saved, list[i].Currency, currency, date); err != nil {
+			return nil, errors.Wrapf(err, "exchange %s to %s", list[i].Currency, currency)
+		}
+
+		list[i].Monthly = monthlySaving(list[i].Target-list[i].Saved, date, list[i].Deadline)
+	}
+
+	return list, nil
+}
+
+func (s *saver) getGoal(ctx context.Context, user *types.User, name string) (types.Goal, error) {
+	goal, ok, err := s.storage.GetByName(ctx, user, normalizeGoalName(name))
+	if err != nil {
+		return types.Goal{}, errors.Wrap(err, "GoalStorage.GetByName")
+	}
+
+	if !ok {
+		return types.Goal{}, model.ErrNotFound
+	}
+
+	return goal, nil
+}
+
+// monthlySaving spreads the remaining amount over the months left till the deadline, a started month
+// counts as a whole one. It's zero when the goal is reached, and the whole amount when the deadline has passed.
+func monthlySaving(remains int64, date, deadline time.Time) int64 {
+	if remains <= 0 || deadline.IsZero() {
+		return 0
+	}
+
+	months := int64(deadline.Year()-date.Year())*12 + int64(deadline.Month()-date.Month())
+	if deadline.Day() > date.Day() {
+		months++
+	}
+
+	if months <= 0 {
+		return remains
+	}
+
+	return (remains + months - 1) / months
+}
+
+func normalizeGoalName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,181 @@
+//go:build unit
+
+package expense
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mmocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/model"
+	mocks "gitlab.ozon.dev/almenschhikov/go-course-4/internal/mocks/storage"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/model"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/test"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type saverMocksInitializer struct {
+	storage func(m *mocks.MockGoalStorage)
+	rater   func(m *mmocks.MockRater)
+}
+
+func setupSaver(t *testing.T, i saverMocksInitializer) *saver {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockGoalStorage(ctrl)
+	if i.storage != nil {
+		i.storage(storageMock)
+	}
+
+	raterMock := mmocks.NewMockRater(ctrl)
+	if i.rater != nil {
+		i.rater(raterMock)
+	}
+
+	return NewSaver(storageMock, raterMock)
+}
+
+func Test_saver_AddGoal(t *testing.T) {
+	t.Run("duplicate", func(t *testing.T) {
+		// ARRANGE
+		s := setupSaver(t, saverMocksInitializer{
+			storage: func(m *mocks.MockGoalStorage) {
+				m.EXPECT().Add(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.Goal{
+					Name:     "vacation",
+					Target:   2000000000,
+					Currency: "RUB",
+				}).Return(false, nil)
+			},
+		})
+
+		// ACT
+		err := s.AddGoal(context.Background(), test.User, types.Goal{
+			Name:     " Vacation ",
+			Target:   2000000000,
+			Currency: "RUB",
+		})
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	})
+
+	t.Run("no target", func(t *testing.T) {
+		// ARRANGE
+		s := setupSaver(t, saverMocksInitializer{})
+
+		// ACT
+		err := s.AddGoal(context.Background(), test.User, types.Goal{
+			Name:     "vacation",
+			Currency: "RUB",
+		})
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNonPositiveAmount)
+	})
+}
+
+func Test_saver_Contribute(t *testing.T) {
+	t.Run("unknown goal", func(t *testing.T) {
+		// ARRANGE
+		s := setupSaver(t, saverMocksInitializer{
+			storage: func(m *mocks.MockGoalStorage) {
+				m.EXPECT().GetByName(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "car").Return(types.Goal{}, false, nil)
+			},
+		})
+
+		// ACT
+		_, err := s.Contribute(context.Background(), test.User, "Car", test.Today, 10000, "")
+
+		// ASSERT
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("goal currency", func(t *testing.T) {
+		// ARRANGE
+		goal := types.Goal{ID: 3, Name: "vacation", Target: 2000000000, Currency: "RUB"}
+		s := setupSaver(t, saverMocksInitializer{
+			storage: func(m *mocks.MockGoalStorage) {
+				m.EXPECT().GetByName(gomock.AssignableToTypeOf(test.CtxInterface), test.User, "vacation").Return(goal, true, nil)
+				m.EXPECT().AddContribution(gomock.AssignableToTypeOf(test.CtxInterface), test.User, types.GoalContribution{
+					Goal:     3,
+					Date:     test.Today,
+					Amount:   50000000,
+					Currency: "RUB",
+				}).Return(nil)
+			},
+		})
+
+		// ACT
+		got, err := s.Contribute(context.Background(), test.User, "vacation", test.Today, 50000000, "")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, goal, got)
+	})
+}
+
+func Test_saver_ListGoals(t *testing.T) {
+	// ARRANGE
+	today := time.Date(2022, 11, 16, 0, 0, 0, 0, time.UTC)
+	vacation := types.Goal{ID: 3, Name: "vacation", Target: 2000000000, Currency: "RUB", Deadline: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}
+	laptop := types.Goal{ID: 4, Name: "laptop", Target: 15000000, Currency: "USD"}
+
+	s := setupSaver(t, saverMocksInitializer{
+		storage: func(m *mocks.MockGoalStorage) {
+			m.EXPECT().List(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]types.Goal{vacation, laptop}, nil)
+			m.EXPECT().Totals(gomock.AssignableToTypeOf(test.CtxInterface), test.User).Return([]types.GoalTotal{
+				{Goal: 3, Currency: "RUB", Amount: 500000000},
+				{Goal: 3, Currency: "USD", Amount: 1000000},
+				{Goal: 4, Currency: "USD", Amount: 5000000},
+			}, nil)
+		},
+		rater: func(m *mmocks.MockRater) {
+			m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(500000000), "RUB", "RUB", today).Return(int64(500000000), nil)
+			m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(1000000), "USD", "RUB", today).Return(int64(60000000), nil)
+			m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(5000000), "USD", "USD", today).Return(int64(5000000), nil)
+			m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(560000000), "RUB", "RUB", today).Return(int64(560000000), nil)
+			m.EXPECT().Exchange(gomock.AssignableToTypeOf(test.CtxInterface), int64(5000000), "USD", "RUB", today).Return(int64(300000000), nil)
+		},
+	})
+
+	// ACT
+	list, err := s.ListGoals(context.Background(), test.User, "RUB", today)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []types.GoalProgress{
+		// 1440000 RUB left for 7 months till June
+		{Goal: vacation, Saved: 560000000, Converted: 560000000, Monthly: 205714286},
+		{Goal: laptop, Saved: 5000000, Converted: 300000000},
+	}, list)
+}
+
+func Test_monthlySaving(t *testing.T) {
+	today := time.Date(2022, 11, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		remains  int64
+		deadline time.Time
+		want     int64
+	}{
+		{name: "no deadline", remains: 1200, want: 0},
+		{name: "reached", remains: -100, deadline: time.Date(2023, 11, 16, 0, 0, 0, 0, time.UTC), want: 0},
+		{name: "whole months", remains: 1200, deadline: time.Date(2023, 11, 16, 0, 0, 0, 0, time.UTC), want: 100},
+		{name: "started month", remains: 1300, deadline: time.Date(2023, 11, 17, 0, 0, 0, 0, time.UTC), want: 100},
+		{name: "this month", remains: 1200, deadline: time.Date(2022, 11, 30, 0, 0, 0, 0, time.UTC), want: 1200},
+		{name: "passed", remains: 1200, deadline: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), want: 1200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got := monthlySaving(tt.remains, today, tt.deadline)
+
+			// ASSERT
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/dto/request"
@@ -13,21 +14,157 @@ type (
 	Controller interface {
 		ListCurrencies(ctx context.Context, req request.ListCurrencies) response.ListCurrencies
 		SetCurrency(ctx context.Context, req request.SetCurrency) response.SetCurrency
+		IsCurrencyAvailable(code string) bool
 
 		ListLimits(ctx context.Context, req request.ListLimits) response.ListLimits
 		SetLimit(ctx context.Context, req request.SetLimit) response.SetLimit
+		SetLimitAlerts(ctx context.Context, req request.SetLimitAlerts) response.SetLimitAlerts
 
 		AddExpense(ctx context.Context, req request.AddExpense) response.AddExpense
+		ListExpenses(ctx context.Context, req request.ListExpenses) response.ListExpenses
+		FindExpenses(ctx context.Context, req request.FindExpenses) response.FindExpenses
+		GetExpense(ctx context.Context, req request.GetExpense) response.GetExpense
+		UpdateExpense(ctx context.Context, req request.UpdateExpense) response.UpdateExpense
+		DeleteExpense(ctx context.Context, req request.DeleteExpense) response.DeleteExpense
+		AttachToExpense(ctx context.Context, req request.AttachToExpense) response.AttachToExpense
+		GetAttachment(ctx context.Context, req request.GetAttachment) response.GetAttachment
+
+		AddIncome(ctx context.Context, req request.AddIncome) response.AddIncome
+		ListIncomes(ctx context.Context, req request.ListIncomes) response.ListIncomes
+
+		AddAccount(ctx context.Context, req request.AddAccount) response.AddAccount
+		ListAccounts(ctx context.Context, req request.ListAccounts) response.ListAccounts
+		GetBalance(ctx context.Context, req request.GetBalance) response.GetBalance
+		Transfer(ctx context.Context, req request.Transfer) response.Transfer
+
+		AddGoal(ctx context.Context, req request.AddGoal) response.AddGoal
+		Contribute(ctx context.Context, req request.Contribute) response.Contribute
+		DeleteGoal(ctx context.Context, req request.DeleteGoal) response.DeleteGoal
+		ListGoals(ctx context.Context, req request.ListGoals) response.ListGoals
+
+		CreateGroup(ctx context.Context, req request.CreateGroup) response.CreateGroup
+		JoinGroup(ctx context.Context, req request.JoinGroup) response.JoinGroup
+		LeaveGroup(ctx context.Context, req request.LeaveGroup) response.LeaveGroup
+		RemoveGroupMember(ctx context.Context, req request.RemoveGroupMember) response.RemoveGroupMember
+		GetGroup(ctx context.Context, req request.GetGroup) response.GetGroup
+
+		ListDebts(ctx context.Context, req request.ListDebts) response.ListDebts
+		SettleDebts(ctx context.Context, req request.SettleDebts) response.SettleDebts
 
 		GetReport(ctx context.Context, req request.GetReport) response.GetReport
+		ExportExpenses(ctx context.Context, req request.ExportExpenses) response.ExportExpenses
+
+		ImportExpenses(ctx context.Context, req request.ImportExpenses) response.ImportExpenses
+		ListMerchantRules(ctx context.Context, req request.ListMerchantRules) response.ListMerchantRules
+		SetMerchantRule(ctx context.Context, req request.SetMerchantRule) response.SetMerchantRule
+
+		ListCategories(ctx context.Context, req request.ListCategories) response.ListCategories
+		SetCategoryAlias(ctx context.Context, req request.SetCategoryAlias) response.SetCategoryAlias
+		RenameCategory(ctx context.Context, req request.RenameCategory) response.RenameCategory
+
+		AddRecurring(ctx context.Context, req request.AddRecurring) response.AddRecurring
+		ListRecurring(ctx context.Context, req request.ListRecurring) response.ListRecurring
+		SetRecurringPaused(ctx context.Context, req request.SetRecurringPaused) response.SetRecurringPaused
+
+		Undo(ctx context.Context, req request.Undo) response.Undo
 	}
 
 	Expenser interface {
-		AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string) error
+		// AddExpense adds the expense and returns its ID.
+		AddExpense(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64, note string, tags []string) (int64, error)
+		ListLastExpenses(ctx context.Context, user *types.User, count int) ([]types.Expense, error)
+		FindExpenses(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, bool, error)
+		GetExpense(ctx context.Context, user *types.User, id int64) (types.Expense, error)
+		UpdateExpense(ctx context.Context, user *types.User, id int64, date time.Time, amount int64, currency, category string) error
+		DeleteExpense(ctx context.Context, user *types.User, id int64) error
+		ImportExpenses(ctx context.Context, user *types.User, items []types.ImportedExpense) ([]types.Expense, error)
+	}
+
+	// Categorizer keeps the catalogue of the user categories: aliases standing for categories
+	// and renames of categories applied to the whole history.
+	Categorizer interface {
+		ResolveCategory(ctx context.Context, user *types.User, name string) (string, error)
+		ListCategories(ctx context.Context, user *types.User) ([]types.Category, error)
+		SetAlias(ctx context.Context, user *types.User, alias, category string) error
+		DeleteAlias(ctx context.Context, user *types.User, alias string) error
+		RenameCategory(ctx context.Context, user *types.User, from, to string, merge bool) error
+	}
+
+	// Attacher keeps files attached to expenses, e.g. photos of receipts.
+	Attacher interface {
+		// Attach stores the file and attaches it to the expense replacing the previous one.
+		Attach(ctx context.Context, user *types.User, expenseID int64, data []byte) error
+		GetAttachment(ctx context.Context, user *types.User, expenseID int64) ([]byte, error)
+		// ListAttached tells which of the expenses have attachments.
+		ListAttached(ctx context.Context, user *types.User, ids []int64) (map[int64]bool, error)
+	}
+
+	// Incomer keeps incomes apart from expenses, they are never charged to limits.
+	Incomer interface {
+		AddIncome(ctx context.Context, user *types.User, date time.Time, amount int64, currency, category string, account int64) error
+		ListLastIncomes(ctx context.Context, user *types.User, count int) ([]types.Income, error)
+	}
+
+	Accounter interface {
+		AddAccount(ctx context.Context, user *types.User, name, currency string, opening int64) error
+		ListAccounts(ctx context.Context, user *types.User) ([]types.Account, error)
+		GetAccount(ctx context.Context, user *types.User, name string) (types.Account, error)
+		Transfer(ctx context.Context, user *types.User, transfer types.Transfer) error
+		ListBalances(ctx context.Context, user *types.User, currency string, date time.Time) ([]types.AccountBalance, error)
+	}
+
+	// Saver keeps savings goals along with the money put aside for them.
+	Saver interface {
+		AddGoal(ctx context.Context, user *types.User, goal types.Goal) error
+		Contribute(ctx context.Context, user *types.User, name string, date time.Time, amount int64, currency string) (types.Goal, error)
+		DeleteGoal(ctx context.Context, user *types.User, name string) error
+		ListGoals(ctx context.Context, user *types.User, currency string, date time.Time) ([]types.GoalProgress, error)
+	}
+
+	// Grouper manages households sharing limits and reports, the limits of a group are kept by its owner.
+	Grouper interface {
+		CreateGroup(ctx context.Context, user *types.User, name string) (types.Group, error)
+		JoinGroup(ctx context.Context, user *types.User, code, name string) (types.Group, error)
+		LeaveGroup(ctx context.Context, user *types.User) error
+		RemoveMember(ctx context.Context, owner *types.User, name string) error
+		GetGroup(ctx context.Context, user *types.User) (types.Group, error)
+	}
+
+	// Debtor keeps debts between group members arising from split expenses.
+	Debtor interface {
+		AddDebt(ctx context.Context, debt types.Debt) error
+		ListDebts(ctx context.Context, user *types.User) ([]types.DebtBalance, error)
+		SettleDebts(ctx context.Context, user *types.User, name, currency string) ([]types.DebtBalance, error)
 	}
 
 	Reporter interface {
-		GetReport(ctx context.Context, user *types.User, from time.Time, currency string) (map[string]int64, error)
+		GetReport(ctx context.Context, user *types.User, from, to time.Time, grouping types.ReportGrouping, compare types.DateRange, currency string) (types.ReportData, error)
+	}
+
+	Exporter interface {
+		Export(ctx context.Context, user *types.User, from, to time.Time, format types.ExportFormat, currency string, w io.Writer) (int, error)
+	}
+
+	Importer interface {
+		Parse(ctx context.Context, user *types.User, name, bank string, data []byte) ([]types.ImportedExpense, error)
+		ListRules(ctx context.Context, user *types.User) ([]types.MerchantRule, error)
+		SetRule(ctx context.Context, user *types.User, pattern, category string) error
+		DeleteRule(ctx context.Context, user *types.User, pattern string) error
+	}
+
+	Recurrer interface {
+		Add(ctx context.Context, user *types.User, item types.RecurringExpense) (types.RecurringExpense, error)
+		List(ctx context.Context, user *types.User) ([]types.RecurringExpense, error)
+		SetPaused(ctx context.Context, user *types.User, id int64, paused bool) (types.RecurringExpense, error)
+	}
+
+	// Journaler keeps the recent operations of users, so that the last one can be undone within the window.
+	Journaler interface {
+		// Record adds the operation to the journal and returns its ID.
+		Record(ctx context.Context, user *types.User, op types.Operation) (int64, error)
+		// Pop removes the last operation of the user made within the window and returns it, ErrNotFound when
+		// there's none or the last one doesn't have the ID. The ID 0 stands for any operation.
+		Pop(ctx context.Context, user *types.User, id int64) (types.Operation, error)
 	}
 
 	Rater interface {
@@ -39,15 +176,29 @@ type (
 
 	limiter interface {
 		Get(ctx context.Context, user *types.User, category string) (types.LimitItem, error)
-		Set(ctx context.Context, user *types.User, limit int64, currency, category string) error
+		Set(ctx context.Context, user *types.User, limit int64, currency string, period types.LimitPeriod, category string) error
 		Decrease(ctx context.Context, user *types.User, value int64, category string) (bool, error)
+		Increase(ctx context.Context, user *types.User, value int64, category string) error
 		Unset(ctx context.Context, user *types.User, category string) error
+		Restore(ctx context.Context, user *types.User, item types.LimitItem, category string) error
 		List(ctx context.Context, user *types.User) (map[string]types.LimitItem, error)
+		Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error)
+		GetAlerts(ctx context.Context, user *types.User) ([]int, error)
+		SetAlerts(ctx context.Context, user *types.User, thresholds []int) error
 	}
 
 	currencyManager interface {
 		Get(ctx context.Context, user *types.User) (string, error)
 		Set(ctx context.Context, user *types.User, currency string) error
 		ListCurrenciesCodesWithFlags() []string
+		IsAvailable(currency string) bool
+	}
+
+	unitOfWork interface {
+		Do(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	outbox interface {
+		Add(ctx context.Context, event types.OutboxEvent) error
 	}
 )
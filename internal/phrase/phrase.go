@@ -0,0 +1,401 @@
+// Package phrase parses expenses written in free form, e.g. "вчера такси 350" or "coffee 4.5$ yesterday",
+// so that every frontend understands the same phrases.
+package phrase
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+)
+
+var (
+	ErrNoAmount    = errors.New("no amount")
+	ErrWrongAmount = errors.New("invalid amount")
+	ErrWrongDate   = errors.New("invalid date")
+	ErrNoCategory  = errors.New("no category")
+)
+
+var (
+	_amountRx  = regexp.MustCompile(`^([€$₽£]?)(\d+(?:[.,]\d+)?)(\D*)$`)
+	_numberRx  = regexp.MustCompile(`^\d+$`)
+	_dateRx    = regexp.MustCompile(`^\d{2}\.\d{2}\.\d{4}$`)
+	_daysAgoRx = regexp.MustCompile(`^-(\d+)d$`)
+	_codeRx    = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+	_currencySymbols = map[string]string{
+		"€": "EUR",
+		"$": "USD",
+		"₽": "RUB",
+		"£": "GBP",
+	}
+
+	// _currencyWords are lowercase names of currencies, the trailing dots of abbreviations are trimmed
+	_currencyWords = map[string]string{
+		"р":        "RUB",
+		"руб":      "RUB",
+		"рубль":    "RUB",
+		"рубля":    "RUB",
+		"рублей":   "RUB",
+		"долл":     "USD",
+		"доллар":   "USD",
+		"доллара":  "USD",
+		"долларов": "USD",
+		"евро":     "EUR",
+		"фунт":     "GBP",
+		"фунта":    "GBP",
+		"фунтов":   "GBP",
+	}
+
+	// _relativeDays are the days back from today named by words
+	_relativeDays = map[string]int{
+		"@":         0,
+		"сегодня":   0,
+		"today":     0,
+		"вчера":     1,
+		"yesterday": 1,
+		"позавчера": 2,
+	}
+
+	_dayWords = map[string]bool{"день": true, "дня": true, "дней": true, "day": true, "days": true}
+	_agoWords = map[string]bool{"назад": true, "ago": true}
+
+	_months = map[string]time.Month{
+		"янв": time.January, "января": time.January, "январь": time.January, "jan": time.January, "january": time.January,
+		"фев": time.February, "февраля": time.February, "февраль": time.February, "feb": time.February, "february": time.February,
+		"мар": time.March, "марта": time.March, "март": time.March, "mar": time.March, "march": time.March,
+		"апр": time.April, "апреля": time.April, "апрель": time.April, "apr": time.April, "april": time.April,
+		"мая": time.May, "май": time.May, "may": time.May,
+		"июн": time.June, "июня": time.June, "июнь": time.June, "jun": time.June, "june": time.June,
+		"июл": time.July, "июля": time.July, "июль": time.July, "jul": time.July, "july": time.July,
+		"авг": time.August, "августа": time.August, "август": time.August, "aug": time.August, "august": time.August,
+		"сен": time.September, "сент": time.September, "сентября": time.September, "сентябрь": time.September,
+		"sep": time.September, "sept": time.September, "september": time.September,
+		"окт": time.October, "октября": time.October, "октябрь": time.October, "oct": time.October, "october": time.October,
+		"ноя": time.November, "нояб": time.November, "ноября": time.November, "ноябрь": time.November,
+		"nov": time.November, "november": time.November,
+		"дек": time.December, "декабря": time.December, "декабрь": time.December, "dec": time.December, "december": time.December,
+	}
+)
+
+// Expense is the expense described by the phrase.
+type Expense struct {
+	Date     time.Time // today when the phrase has no date
+	Amount   int64
+	Currency string // empty when the phrase has no currency
+	Category string // normalized
+	// Guessed reports whether the phrase is ambiguous, e.g. has several amounts, and the expense should be confirmed
+	Guessed bool
+}
+
+// amount is a number of the phrase which may be the amount of the expense.
+type amount struct {
+	value    int64
+	currency string
+	text     string
+}
+
+// part is either a word of the category or one of the amounts.
+type part struct {
+	word   string
+	amount int // index of the amount, -1 for words
+}
+
+// Parse parses the phrase consisting of the amount, the category and optionally the date and the currency in any order.
+// The date is either absolute (dd.mm.yyyy, "12 окт", "oct 12") or relative to today ("вчера", "2 days ago", "-2d"),
+// dates without the year are the latest ones not after today. The currency goes with the amount as a symbol (4.5$, $4.5),
+// a code (12.50 EUR, 12.50 eur) or a name (350 руб). Codes are taken for currencies only when available reports so,
+// otherwise they are words of the category.
+func Parse(phrase string, today time.Time, available func(code string) bool) (Expense, error) {
+	today = utils.TruncateToDate(today)
+
+	var (
+		e       Expense
+		dated   bool
+		amounts []amount
+		parts   []part
+	)
+
+	words := strings.Fields(phrase)
+	for i := 0; i < len(words); {
+		date, n, err := parseDate(words[i:], today)
+		if err != nil {
+			return Expense{}, err
+		}
+
+		if n > 0 {
+			if dated {
+				// the first date wins, the user sees which one in the confirmation
+				e.Guessed = true
+			} else {
+				e.Date, dated = date, true
+			}
+
+			i += n
+			continue
+		}
+
+		a, n, err := parseAmount(words[i:], available)
+		if err != nil {
+			return Expense{}, err
+		}
+
+		if n > 0 {
+			parts = append(parts, part{amount: len(amounts)})
+			amounts = append(amounts, a)
+
+			i += n
+			continue
+		}
+
+		parts = append(parts, part{word: trimWord(words[i]), amount: -1})
+		i++
+	}
+
+	if len(amounts) == 0 {
+		return Expense{}, ErrNoAmount
+	}
+
+	// amounts with the currency are more likely to be the ones than plain numbers
+	chosen := 0
+	if len(amounts) > 1 {
+		e.Guessed = true
+
+		for j, a := range amounts {
+			if a.currency != "" {
+				chosen = j
+				break
+			}
+		}
+	}
+
+	category := make([]string, 0, len(parts))
+	for _, p := range parts {
+		switch {
+		case p.amount < 0:
+			category = append(category, p.word)
+		case p.amount != chosen:
+			category = append(category, amounts[p.amount].text)
+		}
+	}
+
+	if e.Category = types.NormalizeCategory(strings.Join(category, " ")); e.Category == "" {
+		return Expense{}, ErrNoCategory
+	}
+
+	if !dated {
+		e.Date = today
+	}
+
+	e.Amount, e.Currency = amounts[chosen].value, amounts[chosen].currency
+
+	return e, nil
+}
+
+// ParseAmount parses the text consisting of the amount alone, with or without the currency, e.g. when a dialog
+// asks for it. The currency is empty when not given, codes are checked the same way as by Parse.
+func ParseAmount(text string, available func(code string) bool) (int64, string, error) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return 0, "", ErrNoAmount
+	}
+
+	a, n, err := parseAmount(words, available)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if n != len(words) {
+		return 0, "", ErrWrongAmount
+	}
+
+	return a.value, a.currency, nil
+}
+
+// ParseDate parses the text consisting of the date alone, written the same way as in phrases.
+func ParseDate(text string, today time.Time) (time.Time, error) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return time.Time{}, ErrWrongDate
+	}
+
+	date, n, err := parseDate(words, utils.TruncateToDate(today))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if n != len(words) {
+		return time.Time{}, ErrWrongDate
+	}
+
+	return date, nil
+}
+
+// parseDate parses the date at the beginning of the words and returns the number of words it takes, 0 if there's no date.
+func parseDate(words []string, today time.Time) (time.Time, int, error) {
+	word := strings.ToLower(trimWord(words[0]))
+
+	if days, ok := _relativeDays[word]; ok {
+		return today.AddDate(0, 0, -days), 1, nil
+	}
+
+	if m := _daysAgoRx.FindStringSubmatch(word); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, 0, ErrWrongDate
+		}
+
+		return today.AddDate(0, 0, -days), 1, nil
+	}
+
+	if _dateRx.MatchString(word) {
+		date, err := time.Parse("02.01.2006", word)
+		if err != nil {
+			return time.Time{}, 0, ErrWrongDate
+		}
+
+		return date, 1, nil
+	}
+
+	if len(words) < 2 {
+		return time.Time{}, 0, nil
+	}
+
+	next := strings.ToLower(trimWord(words[1]))
+
+	if _numberRx.MatchString(word) && len(words) >= 3 && _dayWords[next] && _agoWords[strings.ToLower(trimWord(words[2]))] {
+		days, err := strconv.Atoi(word)
+		if err != nil {
+			return time.Time{}, 0, ErrWrongDate
+		}
+
+		return today.AddDate(0, 0, -days), 3, nil
+	}
+
+	// the day goes either before or after the month: "12 окт", "oct 12"
+	day, month := word, next
+	if _, ok := _months[strings.TrimSuffix(day, ".")]; ok {
+		day, month = next, word
+	}
+
+	m, ok := _months[strings.TrimSuffix(month, ".")]
+	if !ok || !_numberRx.MatchString(day) {
+		return time.Time{}, 0, nil
+	}
+
+	d, err := strconv.Atoi(day)
+	if err != nil || d < 1 || d > 31 {
+		// not a day, e.g. the amount after the month
+		return time.Time{}, 0, nil
+	}
+
+	date, err := latestDate(today, m, d)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return date, 2, nil
+}
+
+// latestDate returns the latest date of the month and the day not after today.
+func latestDate(today time.Time, month time.Month, day int) (time.Time, error) {
+	for year := today.Year(); year >= today.Year()-4; year-- {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		if date.Day() != day {
+			// there's no such day in the month this year, e.g. 29 February
+			if month != time.February || day != 29 {
+				return time.Time{}, ErrWrongDate
+			}
+			continue
+		}
+
+		if !date.After(today) {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, ErrWrongDate
+}
+
+// parseAmount parses the amount at the beginning of the words and returns the number of words it takes,
+// 0 if there's no amount.
+func parseAmount(words []string, available func(code string) bool) (amount, int, error) {
+	word := trimWord(words[0])
+
+	// the currency symbol is separated from the amount: "$ 4.5"
+	if currency, ok := _currencySymbols[word]; ok && len(words) > 1 {
+		a, n, err := parseAmount(words[1:2], available)
+		if err != nil || n == 0 || a.currency != "" {
+			return amount{}, 0, nil
+		}
+
+		a.currency, a.text = currency, word+" "+a.text
+
+		return a, 2, nil
+	}
+
+	m := _amountRx.FindStringSubmatch(word)
+	if m == nil {
+		return amount{}, 0, nil
+	}
+
+	a := amount{text: word}
+
+	switch {
+	case m[1] != "" && m[3] != "":
+		return amount{}, 0, ErrWrongAmount
+
+	case m[1] != "":
+		a.currency = _currencySymbols[m[1]]
+
+	case m[3] != "":
+		// a number glued with anything but the currency is a typo rather than a word of the category
+		currency, ok := lookupCurrency(m[3], available)
+		if !ok {
+			return amount{}, 0, ErrWrongAmount
+		}
+
+		a.currency = currency
+	}
+
+	value, err := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", "."), 64)
+	if err != nil {
+		return amount{}, 0, ErrWrongAmount
+	}
+
+	a.value = int64(math.Round(value * 10000))
+
+	if a.currency == "" && len(words) > 1 {
+		if currency, ok := lookupCurrency(trimWord(words[1]), available); ok {
+			a.currency, a.text = currency, a.text+" "+words[1]
+			return a, 2, nil
+		}
+	}
+
+	return a, 1, nil
+}
+
+// lookupCurrency returns the code of the currency given by the symbol, the available code in any case or the name.
+func lookupCurrency(word string, available func(code string) bool) (string, bool) {
+	if currency, ok := _currencySymbols[word]; ok {
+		return currency, true
+	}
+
+	if code := strings.ToUpper(word); _codeRx.MatchString(word) && available(code) {
+		return code, true
+	}
+
+	currency, ok := _currencyWords[strings.TrimSuffix(strings.ToLower(word), ".")]
+
+	return currency, ok
+}
+
+// trimWord trims punctuation separating the words, e.g. "такси, 350".
+func trimWord(word string) string {
+	return strings.TrimRight(word, ",;")
+}
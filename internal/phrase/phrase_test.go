@@ -0,0 +1,283 @@
+//go:build unit
+
+package phrase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func available(code string) bool {
+	return code == "RUB" || code == "USD" || code == "EUR"
+}
+
+func Test_Parse(t *testing.T) {
+	today := time.Date(2022, 10, 21, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		phrase  string
+		want    Expense
+		wantErr error
+	}{
+		{
+			name:   "amount and category",
+			phrase: "2,02 coffee",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 20200, Category: "coffee"},
+		},
+		{
+			name:   "strict order",
+			phrase: "20.10.2022 10 Transport / Taxi",
+			want:   Expense{Date: date(2022, 10, 20), Amount: 100000, Category: "transport/taxi"},
+		},
+		{
+			name:   "days back",
+			phrase: "-2d 2.5 coffee",
+			want:   Expense{Date: date(2022, 10, 19), Amount: 25000, Category: "coffee"},
+		},
+		{
+			name:   "today sign",
+			phrase: "@ 2.5 coffee",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 25000, Category: "coffee"},
+		},
+		{
+			name:   "yesterday in russian",
+			phrase: "вчера такси 350",
+			want:   Expense{Date: date(2022, 10, 20), Amount: 3500000, Category: "такси"},
+		},
+		{
+			name:   "day before yesterday",
+			phrase: "кофе 350 руб. позавчера",
+			want:   Expense{Date: date(2022, 10, 19), Amount: 3500000, Currency: "RUB", Category: "кофе"},
+		},
+		{
+			name:   "yesterday in english",
+			phrase: "coffee 4.5$ yesterday",
+			want:   Expense{Date: date(2022, 10, 20), Amount: 45000, Currency: "USD", Category: "coffee"},
+		},
+		{
+			name:   "days ago in russian",
+			phrase: "3 дня назад 100 такси",
+			want:   Expense{Date: date(2022, 10, 18), Amount: 1000000, Category: "такси"},
+		},
+		{
+			name:   "days ago in english",
+			phrase: "lunch 12 usd 2 days ago",
+			want:   Expense{Date: date(2022, 10, 19), Amount: 120000, Currency: "USD", Category: "lunch"},
+		},
+		{
+			name:   "day and month",
+			phrase: "350р кофе 12 окт",
+			want:   Expense{Date: date(2022, 10, 12), Amount: 3500000, Currency: "RUB", Category: "кофе"},
+		},
+		{
+			name:   "month and day of the last year",
+			phrase: "Nov. 25 dinner 30",
+			want:   Expense{Date: date(2021, 11, 25), Amount: 300000, Category: "dinner"},
+		},
+		{
+			name:   "currency code",
+			phrase: "12.50 EUR coffee",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 125000, Currency: "EUR", Category: "coffee"},
+		},
+		{
+			name:   "lowercase currency code",
+			phrase: "12.50 eur coffee",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 125000, Currency: "EUR", Category: "coffee"},
+		},
+		{
+			name:   "unknown currency code",
+			phrase: "300 CAR wash",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 3000000, Category: "car wash"},
+		},
+		{
+			name:   "unavailable currency code",
+			phrase: "5 gbp tea",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 50000, Category: "gbp tea"},
+		},
+		{
+			name:   "currency symbol before amount",
+			phrase: "$ 4.5 coffee",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 45000, Currency: "USD", Category: "coffee"},
+		},
+		{
+			name:   "glued currency symbol",
+			phrase: "12,5€ coffee",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 125000, Currency: "EUR", Category: "coffee"},
+		},
+		{
+			name:   "category of several words",
+			phrase: "кофе с собой, 200",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 2000000, Category: "кофе с собой"},
+		},
+		{
+			name:   "amount with currency among numbers",
+			phrase: "кофе 2 350р",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 3500000, Currency: "RUB", Category: "кофе 2", Guessed: true},
+		},
+		{
+			name:   "several numbers",
+			phrase: "такси 200 300",
+			want:   Expense{Date: date(2022, 10, 21), Amount: 2000000, Category: "такси 300", Guessed: true},
+		},
+		{
+			name:   "several dates",
+			phrase: "вчера 100 такси сегодня",
+			want:   Expense{Date: date(2022, 10, 20), Amount: 1000000, Category: "такси", Guessed: true},
+		},
+		{
+			name:    "no amount",
+			phrase:  "такси",
+			wantErr: ErrNoAmount,
+		},
+		{
+			name:    "no category",
+			phrase:  "вчера 350р",
+			wantErr: ErrNoCategory,
+		},
+		{
+			name:    "number glued with text",
+			phrase:  "2d 10 taxi",
+			wantErr: ErrWrongAmount,
+		},
+		{
+			name:    "invalid date",
+			phrase:  "99.99.9999 10 taxi",
+			wantErr: ErrWrongDate,
+		},
+		{
+			name:    "no such day",
+			phrase:  "31 ноя 100 такси",
+			wantErr: ErrWrongDate,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got, err := Parse(tt.phrase, today, available)
+
+			// ASSERT
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func Test_ParseAmount(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantAmount   int64
+		wantCurrency string
+		wantErr      error
+	}{
+		{
+			name:       "number",
+			text:       "350",
+			wantAmount: 3500000,
+		},
+		{
+			name:         "currency symbol",
+			text:         "4,5$",
+			wantAmount:   45000,
+			wantCurrency: "USD",
+		},
+		{
+			name:         "currency name",
+			text:         "350 рублей",
+			wantAmount:   3500000,
+			wantCurrency: "RUB",
+		},
+		{
+			name:         "lowercase currency code",
+			text:         "350 rub",
+			wantAmount:   3500000,
+			wantCurrency: "RUB",
+		},
+		{
+			name:    "empty",
+			text:    " ",
+			wantErr: ErrNoAmount,
+		},
+		{
+			name:    "not a number",
+			text:    "такси",
+			wantErr: ErrWrongAmount,
+		},
+		{
+			name:    "amount with category",
+			text:    "350 такси",
+			wantErr: ErrWrongAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			amount, currency, err := ParseAmount(tt.text, available)
+
+			// ASSERT
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantAmount, amount)
+			assert.Equal(t, tt.wantCurrency, currency)
+		})
+	}
+}
+
+func Test_ParseDate(t *testing.T) {
+	today := time.Date(2022, 10, 21, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		text    string
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name: "relative",
+			text: "Позавчера",
+			want: date(2022, 10, 19),
+		},
+		{
+			name: "day and month",
+			text: "12 окт",
+			want: date(2022, 10, 12),
+		},
+		{
+			name: "full date",
+			text: "01.02.2022",
+			want: date(2022, 2, 1),
+		},
+		{
+			name:    "not a date",
+			text:    "такси",
+			wantErr: ErrWrongDate,
+		},
+		{
+			name:    "date with category",
+			text:    "вчера такси",
+			wantErr: ErrWrongDate,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got, err := ParseDate(tt.text, today)
+
+			// ASSERT
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package receipt
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg" // photos are sent as JPEG
+	_ "image/png"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/pkg/errors"
+)
+
+var ErrNoQRCode = errors.New("no QR code found")
+
+// DecodeQR finds a QR code on the image and returns its payload.
+func DecodeQR(data []byte) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", errors.Wrap(err, "decode image")
+	}
+
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", errors.Wrap(err, "binarize image")
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+
+	result, err := qrcode.NewQRCodeReader().Decode(bmp, hints)
+	if err != nil {
+		return "", errors.Wrap(ErrNoQRCode, err.Error())
+	}
+
+	return result.GetText(), nil
+}
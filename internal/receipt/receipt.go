@@ -0,0 +1,80 @@
+// Package receipt reads the payload of QR codes printed on Russian fiscal receipts.
+package receipt
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+// _purchase is the operation type (n) of receipts issued for purchases, the others are refunds and receipts of sellers.
+const _purchase = "1"
+
+var (
+	ErrNotReceipt = errors.New("not a fiscal receipt")
+	ErrNotExpense = errors.New("receipt is not a purchase")
+)
+
+// Receipt is a purchase described by the QR code of a fiscal receipt.
+type Receipt struct {
+	ID     types.Receipt
+	Date   time.Time
+	Amount int64
+}
+
+// IsReceipt reports whether the text looks like the payload of a receipt QR code.
+func IsReceipt(text string) bool {
+	return strings.HasPrefix(text, "t=") && strings.Contains(text, "&s=") && strings.Contains(text, "&fn=")
+}
+
+// Parse parses the payload of the form t=20221021T1530&s=1234.50&fn=...&i=...&fp=...&n=1.
+func Parse(payload string) (Receipt, error) {
+	if !IsReceipt(payload) {
+		return Receipt{}, ErrNotReceipt
+	}
+
+	values, err := url.ParseQuery(strings.TrimSpace(payload))
+	if err != nil {
+		return Receipt{}, errors.Wrap(ErrNotReceipt, err.Error())
+	}
+
+	r := Receipt{
+		ID: types.Receipt{
+			Drive:    values.Get("fn"),
+			Document: values.Get("i"),
+			Sign:     values.Get("fp"),
+		},
+	}
+
+	if r.ID.Drive == "" || r.ID.Document == "" || r.ID.Sign == "" {
+		return Receipt{}, errors.Wrap(ErrNotReceipt, "no fiscal identifiers")
+	}
+
+	if n := values.Get("n"); n != "" && n != _purchase {
+		return Receipt{}, ErrNotExpense
+	}
+
+	// the time is local to the seller and written with or without seconds, only the date matters
+	t := values.Get("t")
+	if len(t) < 8 {
+		return Receipt{}, errors.Wrapf(ErrNotReceipt, "invalid time %q", t)
+	}
+
+	if r.Date, err = time.Parse("20060102", t[:8]); err != nil {
+		return Receipt{}, errors.Wrapf(ErrNotReceipt, "invalid time %q", t)
+	}
+
+	sum, err := strconv.ParseFloat(values.Get("s"), 64)
+	if err != nil || sum <= 0 {
+		return Receipt{}, errors.Wrapf(ErrNotReceipt, "invalid sum %q", values.Get("s"))
+	}
+
+	r.Amount = int64(math.Round(sum * 10000))
+
+	return r, nil
+}
@@ -0,0 +1,107 @@
+//go:build unit
+
+package receipt
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const _payload = "t=20221021T1530&s=1234.50&fn=9960440300000001&i=12345&fp=1234567890&n=1"
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    Receipt
+		wantErr error
+	}{
+		{
+			name:    "purchase",
+			payload: _payload,
+			want: Receipt{
+				ID:     types.Receipt{Drive: "9960440300000001", Document: "12345", Sign: "1234567890"},
+				Date:   time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+				Amount: 12345000,
+			},
+		},
+		{
+			name:    "time with seconds",
+			payload: "t=20221021T153012&s=99&fn=1&i=2&fp=3&n=1",
+			want: Receipt{
+				ID:     types.Receipt{Drive: "1", Document: "2", Sign: "3"},
+				Date:   time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+				Amount: 990000,
+			},
+		},
+		{
+			name:    "refund",
+			payload: "t=20221021T1530&s=1234.50&fn=1&i=2&fp=3&n=2",
+			wantErr: ErrNotExpense,
+		},
+		{
+			name:    "no fiscal sign",
+			payload: "t=20221021T1530&s=1234.50&fn=1&i=2&n=1",
+			wantErr: ErrNotReceipt,
+		},
+		{
+			name:    "invalid sum",
+			payload: "t=20221021T1530&s=-5&fn=1&i=2&fp=3&n=1",
+			wantErr: ErrNotReceipt,
+		},
+		{
+			name:    "not a receipt",
+			payload: "450 coffee",
+			wantErr: ErrNotReceipt,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got, err := Parse(tt.payload)
+
+			// ASSERT
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_DecodeQR(t *testing.T) {
+	t.Run("qr code", func(t *testing.T) {
+		// ARRANGE
+		matrix, err := qrcode.NewQRCodeWriter().Encode(_payload, gozxing.BarcodeFormat_QR_CODE, 300, 300, nil)
+		if err != nil {
+			t.Fatalf("cannot encode qr code: %s", err.Error())
+		}
+
+		buf := new(bytes.Buffer)
+		if err := png.Encode(buf, matrix); err != nil {
+			t.Fatalf("cannot encode image: %s", err.Error())
+		}
+
+		// ACT
+		payload, err := DecodeQR(buf.Bytes())
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, _payload, payload)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		// ACT
+		_, err := DecodeQR([]byte("t=20221021T1530"))
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+}
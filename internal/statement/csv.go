@@ -0,0 +1,151 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/utils"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const _defaultDateFormat = "2006-01-02"
+
+func parseCSV(data []byte, layout config.BankConfig) ([]Transaction, error) {
+	r, err := newCSVReader(data, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "read header")
+	}
+
+	columns := columnIndexes(header)
+	if err := checkColumns(columns, layout); err != nil {
+		return nil, err
+	}
+
+	dateFormat := layout.DateFormat
+	if dateFormat == "" {
+		dateFormat = _defaultDateFormat
+	}
+
+	var list []Transaction
+	for row := 2; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "read row %d", row)
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		if value(layout.DateColumn) == "" && value(layout.AmountColumn) == "" {
+			continue
+		}
+
+		date, err := time.Parse(dateFormat, value(layout.DateColumn))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid date in row %d", row)
+		}
+
+		amount, err := parseAmount(value(layout.AmountColumn))
+		if err != nil {
+			return nil, errors.Wrapf(err, "row %d", row)
+		}
+
+		if !layout.ExpensesNegative {
+			amount = -amount
+		}
+
+		currency := value(layout.CurrencyColumn)
+		if currency == "" {
+			currency = layout.Currency
+		}
+
+		list = append(list, Transaction{
+			ID:          value(layout.IDColumn),
+			Date:        utils.TruncateToDate(date),
+			Amount:      amount,
+			Currency:    strings.ToUpper(currency),
+			Description: value(layout.DescriptionColumn),
+		})
+	}
+
+	return list, nil
+}
+
+// matchesHeader reports whether the statement header has all the columns of the layout.
+func matchesHeader(data []byte, layout config.BankConfig) bool {
+	r, err := newCSVReader(data, layout)
+	if err != nil {
+		return false
+	}
+
+	header, err := r.Read()
+	if err != nil {
+		return false
+	}
+
+	return checkColumns(columnIndexes(header), layout) == nil
+}
+
+func newCSVReader(data []byte, layout config.BankConfig) (*csv.Reader, error) {
+	switch strings.ToLower(layout.Encoding) {
+	case "", "utf-8", "utf8":
+
+	case "windows-1251", "cp1251":
+		decoded, err := charmap.Windows1251.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode windows-1251")
+		}
+		data = decoded
+
+	default:
+		return nil, errors.Errorf("unsupported encoding %q", layout.Encoding)
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if layout.Delimiter != "" {
+		r.Comma, _ = utf8.DecodeRuneInString(layout.Delimiter)
+	}
+
+	return r, nil
+}
+
+func columnIndexes(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, title := range header {
+		columns[strings.TrimSpace(title)] = i
+	}
+
+	return columns
+}
+
+// checkColumns makes sure the header has all the columns referred to by the layout.
+func checkColumns(columns map[string]int, layout config.BankConfig) error {
+	for _, column := range []string{layout.DateColumn, layout.AmountColumn, layout.DescriptionColumn, layout.CurrencyColumn, layout.IDColumn} {
+		if _, ok := columns[column]; column != "" && !ok {
+			return errors.Errorf("no column %q in the header", column)
+		}
+	}
+
+	return nil
+}
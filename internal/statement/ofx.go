@@ -0,0 +1,116 @@
+package statement
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// _ofxTagRx matches both the SGML (OFX 1.x) elements, which have no closing tags,
+// and the XML (OFX 2.x) ones along with the text following them.
+var _ofxTagRx = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+func isOFX(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+
+	return bytes.Contains(head, []byte("OFXHEADER")) || bytes.Contains(bytes.ToUpper(head), []byte("<OFX>"))
+}
+
+func parseOFX(data []byte) ([]Transaction, error) {
+	if bytes.Contains(data, []byte("CHARSET:1251")) {
+		decoded, err := charmap.Windows1251.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode windows-1251")
+		}
+		data = decoded
+	}
+
+	var (
+		list     []Transaction
+		currency string
+		current  *Transaction
+		memo     string
+	)
+
+	for _, match := range _ofxTagRx.FindAllSubmatch(data, -1) {
+		closing := len(match[1]) > 0
+		tag := strings.ToUpper(string(match[2]))
+		value := html.UnescapeString(strings.TrimSpace(string(match[3])))
+
+		if tag == "STMTTRN" {
+			if !closing {
+				current, memo = &Transaction{Currency: currency}, ""
+				continue
+			}
+
+			if current == nil {
+				continue
+			}
+
+			if current.Description == "" {
+				current.Description = memo
+			}
+
+			if current.Date.IsZero() {
+				return nil, errors.Errorf("no posting date of transaction %q", current.ID)
+			}
+
+			list = append(list, *current)
+			current = nil
+			continue
+		}
+
+		if closing || value == "" {
+			continue
+		}
+
+		if tag == "CURDEF" {
+			currency = strings.ToUpper(value)
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		switch tag {
+		case "FITID":
+			current.ID = value
+
+		case "DTPOSTED":
+			// dates are written as YYYYMMDD optionally followed by the time and the time zone
+			if len(value) < 8 {
+				return nil, errors.Errorf("invalid posting date %q", value)
+			}
+
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid posting date %q", value)
+			}
+			current.Date = date
+
+		case "TRNAMT":
+			amount, err := parseAmount(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "transaction %q", current.ID)
+			}
+			current.Amount = amount
+
+		case "NAME", "PAYEE":
+			current.Description = value
+
+		case "MEMO":
+			memo = value
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,115 @@
+// Package statement parses bank statements exported as CSV or OFX files into transactions.
+package statement
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown statement format")
+	ErrUnknownBank   = errors.New("unknown bank statement layout")
+)
+
+// Transaction is a single operation of a statement, expenses have negative amounts.
+type Transaction struct {
+	ID          string // bank transaction ID, empty when the statement has none
+	Date        time.Time
+	Amount      int64
+	Currency    string
+	Description string
+}
+
+type Parser struct {
+	banks []config.BankConfig
+}
+
+func NewParser(banks []config.BankConfig) *Parser {
+	return &Parser{
+		banks: banks,
+	}
+}
+
+// Parse detects the format of the statement by the file name and its content. CSV statements
+// are parsed with the layout of the bank, it is detected by the header when the bank is empty.
+func (p *Parser) Parse(name, bank string, data []byte) ([]Transaction, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	switch ext := strings.ToLower(filepath.Ext(name)); {
+	case ext == ".ofx" || ext == ".qfx" || isOFX(data):
+		return parseOFX(data)
+
+	case ext == ".csv" || ext == ".txt":
+		layout, err := p.layout(bank, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseCSV(data, layout)
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// Banks returns names of the banks with known CSV layouts.
+func (p *Parser) Banks() []string {
+	names := make([]string, 0, len(p.banks))
+	for _, bank := range p.banks {
+		names = append(names, bank.Name)
+	}
+
+	return names
+}
+
+func (p *Parser) layout(bank string, data []byte) (config.BankConfig, error) {
+	if bank != "" {
+		for _, layout := range p.banks {
+			if strings.EqualFold(layout.Name, bank) {
+				return layout, nil
+			}
+		}
+
+		return config.BankConfig{}, ErrUnknownBank
+	}
+
+	for _, layout := range p.banks {
+		if matchesHeader(data, layout) {
+			return layout, nil
+		}
+	}
+
+	return config.BankConfig{}, ErrUnknownBank
+}
+
+// parseAmount parses amounts written with either a dot or a comma as the decimal separator
+// and spaces (or commas along with the dot) separating thousands.
+func parseAmount(value string) (int64, error) {
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+
+		return r
+	}, value)
+
+	if strings.Contains(value, ".") {
+		value = strings.ReplaceAll(value, ",", "")
+	} else {
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid amount %q", value)
+	}
+
+	return int64(math.Round(amount * 10000)), nil
+}
@@ -0,0 +1,140 @@
+//go:build unit
+
+package statement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/config"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var _banks = []config.BankConfig{
+	{
+		Name:              "tinkoff",
+		Delimiter:         ";",
+		Encoding:          "windows-1251",
+		DateColumn:        "Дата платежа",
+		DateFormat:        "02.01.2006",
+		AmountColumn:      "Сумма платежа",
+		CurrencyColumn:    "Валюта платежа",
+		DescriptionColumn: "Описание",
+		ExpensesNegative:  true,
+	},
+	{
+		Name:              "plain",
+		DateColumn:        "date",
+		AmountColumn:      "amount",
+		Currency:          "rub",
+		DescriptionColumn: "merchant",
+		IDColumn:          "id",
+	},
+}
+
+func date(day int) time.Time {
+	return time.Date(2022, 10, day, 0, 0, 0, 0, time.UTC)
+}
+
+func Test_Parser_Parse(t *testing.T) {
+	tinkoff, _ := charmap.Windows1251.NewEncoder().String("Дата платежа;Сумма платежа;Валюта платежа;Описание\n" +
+		"15.10.2022;-1 234,50;RUB;Пятёрочка\n" +
+		"16.10.2022;5000;RUB;Пополнение\n" +
+		";;;\n")
+
+	tests := []struct {
+		name     string
+		fileName string
+		bank     string
+		data     string
+		want     []Transaction
+		wantErr  error
+	}{
+		{
+			name:     "csv of the bank",
+			fileName: "statement.csv",
+			bank:     "Tinkoff",
+			data:     tinkoff,
+			want: []Transaction{
+				{Date: date(15), Amount: -12345000, Currency: "RUB", Description: "Пятёрочка"},
+				{Date: date(16), Amount: 50000000, Currency: "RUB", Description: "Пополнение"},
+			},
+		},
+		{
+			name:     "csv detected by the header",
+			fileName: "statement.csv",
+			data: "\xef\xbb\xbfid,date,amount,merchant\n" +
+				"a1,2022-10-15,\"1,099.90\",Starbucks\n" +
+				"a2,2022-10-16,-20.5,Refund\n",
+			want: []Transaction{
+				{ID: "a1", Date: date(15), Amount: -10999000, Currency: "RUB", Description: "Starbucks"},
+				{ID: "a2", Date: date(16), Amount: 205000, Currency: "RUB", Description: "Refund"},
+			},
+		},
+		{
+			name:     "unknown bank",
+			fileName: "statement.csv",
+			bank:     "sber",
+			data:     tinkoff,
+			wantErr:  ErrUnknownBank,
+		},
+		{
+			name:     "unknown csv layout",
+			fileName: "statement.csv",
+			data:     "when,how much\n2022-10-15,100\n",
+			wantErr:  ErrUnknownBank,
+		},
+		{
+			name:     "ofx sgml",
+			fileName: "statement.ofx",
+			data: "OFXHEADER:100\nDATA:OFXSGML\nCHARSET:1252\n\n<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS>\n" +
+				"<CURDEF>usd\n<BANKTRANLIST>\n" +
+				"<STMTTRN>\n<TRNTYPE>DEBIT\n<DTPOSTED>20221015120000[-5:EST]\n<TRNAMT>-4.75\n<FITID>2022101501\n<NAME>STARBUCKS &amp; CO\n</STMTTRN>\n" +
+				"<STMTTRN>\n<TRNTYPE>DEBIT\n<DTPOSTED>20221016\n<TRNAMT>-12.00\n<FITID>2022101601\n<MEMO>Cinema\n</STMTTRN>\n" +
+				"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>\n",
+			want: []Transaction{
+				{ID: "2022101501", Date: date(15), Amount: -47500, Currency: "USD", Description: "STARBUCKS & CO"},
+				{ID: "2022101601", Date: date(16), Amount: -120000, Currency: "USD", Description: "Cinema"},
+			},
+		},
+		{
+			name:     "ofx xml detected by the content",
+			fileName: "statement",
+			data: `<?xml version="1.0"?><OFX><CURDEF>RUB</CURDEF><BANKTRANLIST>` +
+				`<STMTTRN><DTPOSTED>20221015</DTPOSTED><TRNAMT>-300</TRNAMT><FITID>x1</FITID><NAME>Taxi</NAME></STMTTRN>` +
+				`</BANKTRANLIST></OFX>`,
+			want: []Transaction{
+				{ID: "x1", Date: date(15), Amount: -3000000, Currency: "RUB", Description: "Taxi"},
+			},
+		},
+		{
+			name:     "unknown format",
+			fileName: "statement.pdf",
+			data:     "%PDF-1.4",
+			wantErr:  ErrUnknownFormat,
+		},
+	}
+
+	parser := NewParser(_banks)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			list, err := parser.Parse(tt.fileName, tt.bank, []byte(tt.data))
+
+			// ASSERT
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, list)
+		})
+	}
+
+	t.Run("invalid amount", func(t *testing.T) {
+		// ACT
+		_, err := parser.Parse("statement.csv", "plain", []byte("id,date,amount,merchant\na1,2022-10-15,ten,Starbucks\n"))
+
+		// ASSERT
+		assert.EqualError(t, err, `row 2: invalid amount "ten": strconv.ParseFloat: parsing "ten": invalid syntax`)
+	})
+}
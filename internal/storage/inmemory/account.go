@@ -0,0 +1,149 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+// inMemoryAccountStorage reads expenses and incomes of the shared storages to sum up the account movements.
+type inMemoryAccountStorage struct {
+	data      map[*types.User][]types.Account
+	transfers map[*types.User][]types.Transfer
+	lastID    int64
+
+	expenses *inMemoryExpenseStorage
+	incomes  *inMemoryIncomeStorage
+}
+
+func (s *inMemoryAccountStorage) Add(ctx context.Context, user *types.User, account types.Account) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAccountStorage.Add")
+	defer span.Finish()
+
+	for _, item := range s.data[user] {
+		if item.Name == account.Name {
+			return false, nil
+		}
+	}
+
+	s.lastID++
+	account.ID = s.lastID
+
+	s.data[user] = append(s.data[user], account)
+
+	return true, nil
+}
+
+func (s *inMemoryAccountStorage) List(ctx context.Context, user *types.User) ([]types.Account, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAccountStorage.List")
+	defer span.Finish()
+
+	list := append([]types.Account{}, s.data[user]...)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryAccountStorage) GetByName(ctx context.Context, user *types.User, name string) (types.Account, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAccountStorage.GetByName")
+	defer span.Finish()
+
+	for _, item := range s.data[user] {
+		if item.Name == name {
+			return item, true, nil
+		}
+	}
+
+	return types.Account{}, false, nil
+}
+
+func (s *inMemoryAccountStorage) AddTransfer(ctx context.Context, user *types.User, transfer types.Transfer) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAccountStorage.AddTransfer")
+	defer span.Finish()
+
+	s.transfers[user] = append(s.transfers[user], transfer)
+
+	return nil
+}
+
+func (s *inMemoryAccountStorage) Turnover(ctx context.Context, user *types.User) ([]types.AccountTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAccountStorage.Turnover")
+	defer span.Finish()
+
+	type totalKey struct {
+		account  int64
+		date     time.Time
+		currency string
+	}
+
+	totals := make(map[totalKey]int64)
+	for _, group := range s.expenses.data[user] {
+		for _, item := range group.expenses {
+			if item.Account != 0 {
+				totals[totalKey{item.Account, item.Date, item.Currency}] -= item.Amount
+			}
+		}
+	}
+
+	for _, item := range s.incomes.data[user] {
+		if item.Account != 0 {
+			totals[totalKey{item.Account, item.Date, item.Currency}] += item.Amount
+		}
+	}
+
+	currencies := make(map[int64]string, len(s.data[user]))
+	for _, account := range s.data[user] {
+		currencies[account.ID] = account.Currency
+	}
+
+	for _, transfer := range s.transfers[user] {
+		totals[totalKey{transfer.From, transfer.Date, currencies[transfer.From]}] -= transfer.Amount
+		totals[totalKey{transfer.To, transfer.Date, currencies[transfer.To]}] += transfer.Received
+	}
+
+	list := make([]types.AccountTotal, 0, len(totals))
+	for key, amount := range totals {
+		list = append(list, types.AccountTotal{
+			Account:  key.account,
+			Date:     key.date,
+			Currency: key.currency,
+			Amount:   amount,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		switch {
+		case list[i].Account != list[j].Account:
+			return list[i].Account < list[j].Account
+		case !list[i].Date.Equal(list[j].Date):
+			return list[i].Date.Before(list[j].Date)
+		}
+
+		return list[i].Currency < list[j].Currency
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryAccountStorage) snapshot() func() {
+	data := make(map[*types.User][]types.Account, len(s.data))
+	for user, list := range s.data {
+		data[user] = list[:len(list):len(list)]
+	}
+
+	transfers := make(map[*types.User][]types.Transfer, len(s.transfers))
+	for user, list := range s.transfers {
+		transfers[user] = list[:len(list):len(list)]
+	}
+
+	lastID := s.lastID
+
+	return func() {
+		s.data, s.transfers, s.lastID = data, transfers, lastID
+	}
+}
@@ -0,0 +1,88 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+// inMemoryAttachmentStorage keeps the attachments by users and expenses, the attachments of deleted
+// expenses are left until they're deleted as expired ones.
+type inMemoryAttachmentStorage struct {
+	data     map[*types.User]map[int64]types.Attachment
+	expenses *inMemoryExpenseStorage
+}
+
+func (s *inMemoryAttachmentStorage) Set(ctx context.Context, user *types.User, item types.Attachment) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAttachmentStorage.Set")
+	defer span.Finish()
+
+	if group, _ := s.expenses.find(user, item.ExpenseID); group == nil {
+		return false, nil
+	}
+
+	if _, ok := s.data[user]; !ok {
+		s.data[user] = make(map[int64]types.Attachment)
+	}
+
+	s.data[user][item.ExpenseID] = item
+
+	return true, nil
+}
+
+func (s *inMemoryAttachmentStorage) Get(ctx context.Context, user *types.User, expenseID int64) (types.Attachment, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAttachmentStorage.Get")
+	defer span.Finish()
+
+	if group, _ := s.expenses.find(user, expenseID); group == nil {
+		return types.Attachment{}, false, nil
+	}
+
+	item, ok := s.data[user][expenseID]
+
+	return item, ok, nil
+}
+
+func (s *inMemoryAttachmentStorage) ListAttached(ctx context.Context, user *types.User, ids []int64) ([]int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAttachmentStorage.ListAttached")
+	defer span.Finish()
+
+	list := make([]int64, 0)
+	for _, id := range ids {
+		if _, ok := s.data[user][id]; !ok {
+			continue
+		}
+
+		if group, _ := s.expenses.find(user, id); group != nil {
+			list = append(list, id)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryAttachmentStorage) DeleteExpired(ctx context.Context, before time.Time) ([]string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryAttachmentStorage.DeleteExpired")
+	defer span.Finish()
+
+	keys := make([]string, 0)
+	for user, items := range s.data {
+		for id, item := range items {
+			if group, _ := s.expenses.find(user, id); group != nil && !item.Created.Before(before) {
+				continue
+			}
+
+			keys = append(keys, item.Key)
+			delete(items, id)
+		}
+	}
+
+	return keys, nil
+}
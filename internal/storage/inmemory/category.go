@@ -0,0 +1,138 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+// inMemoryCategoryStorage keeps the aliases, the categories themselves are the ones
+// of the expenses and are renamed in the storages sharing them.
+type inMemoryCategoryStorage struct {
+	aliases   map[*types.User]map[string]string
+	expenses  *inMemoryExpenseStorage
+	recurring *inMemoryRecurringExpenseStorage
+	limits    *inMemoryExpenseLimitStorage
+	rules     *inMemoryMerchantRuleStorage
+}
+
+func (s *inMemoryCategoryStorage) List(ctx context.Context, user *types.User) ([]types.Category, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryCategoryStorage.List")
+	defer span.Finish()
+
+	aliases := make(map[string][]string)
+	for _, group := range s.expenses.data[user] {
+		if len(group.expenses) != 0 {
+			aliases[group.category] = nil
+		}
+	}
+
+	for alias, category := range s.aliases[user] {
+		aliases[category] = append(aliases[category], alias)
+	}
+
+	list := make([]types.Category, 0, len(aliases))
+	for category, names := range aliases {
+		sort.Strings(names)
+		list = append(list, types.Category{
+			Name:    category,
+			Aliases: append([]string{}, names...),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryCategoryStorage) Resolve(ctx context.Context, user *types.User, alias string) (string, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryCategoryStorage.Resolve")
+	defer span.Finish()
+
+	category, found := s.aliases[user][alias]
+
+	return category, found, nil
+}
+
+func (s *inMemoryCategoryStorage) SetAlias(ctx context.Context, user *types.User, alias, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryCategoryStorage.SetAlias")
+	defer span.Finish()
+
+	if _, ok := s.aliases[user]; !ok {
+		s.aliases[user] = make(map[string]string)
+	}
+
+	s.aliases[user][alias] = category
+
+	return nil
+}
+
+func (s *inMemoryCategoryStorage) DeleteAlias(ctx context.Context, user *types.User, alias string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryCategoryStorage.DeleteAlias")
+	defer span.Finish()
+
+	if _, ok := s.aliases[user][alias]; !ok {
+		return false, nil
+	}
+
+	delete(s.aliases[user], alias)
+
+	return true, nil
+}
+
+func (s *inMemoryCategoryStorage) Rename(ctx context.Context, user *types.User, from, to string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryCategoryStorage.Rename")
+	defer span.Finish()
+
+	renamed := s.expenses.renameCategory(user, from, to)
+	renamed = s.recurring.renameCategory(user, from, to) || renamed
+	renamed = s.limits.renameCategory(user, from, to) || renamed
+	renamed = s.rules.renameCategory(user, from, to) || renamed
+
+	for alias, category := range s.aliases[user] {
+		if category, ok := renameCategory(category, from, to); ok {
+			s.aliases[user][alias], renamed = category, true
+		}
+	}
+
+	// an alias of the renamed category may have become the name of the category itself
+	for alias, category := range s.aliases[user] {
+		if alias == category {
+			delete(s.aliases[user], alias)
+		}
+	}
+
+	return renamed, nil
+}
+
+func (s *inMemoryCategoryStorage) snapshot() func() {
+	aliases := make(map[*types.User]map[string]string, len(s.aliases))
+	for user, userAliases := range s.aliases {
+		aliases[user] = make(map[string]string, len(userAliases))
+		for alias, category := range userAliases {
+			aliases[user][alias] = category
+		}
+	}
+
+	return func() {
+		s.aliases = aliases
+	}
+}
+
+// renameCategory returns the new name of the category if it is either the renamed one or its subcategory.
+func renameCategory(category, from, to string) (string, bool) {
+	if category == from {
+		return to, true
+	}
+
+	if strings.HasPrefix(category, from+"/") {
+		return to + strings.TrimPrefix(category, from), true
+	}
+
+	return "", false
+}
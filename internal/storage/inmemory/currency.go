@@ -28,3 +28,14 @@ func (s *inMemoryCurrencyStorage) Set(ctx context.Context, user *types.User, val
 
 	return nil
 }
+
+func (s *inMemoryCurrencyStorage) snapshot() func() {
+	data := make(map[*types.User]string, len(s.data))
+	for user, currency := range s.data {
+		data[user] = currency
+	}
+
+	return func() {
+		s.data = data
+	}
+}
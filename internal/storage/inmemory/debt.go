@@ -0,0 +1,96 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type debtRecord struct {
+	types.Debt
+	settled bool
+}
+
+type inMemoryDebtStorage struct {
+	data []debtRecord
+}
+
+func (s *inMemoryDebtStorage) Add(ctx context.Context, debt types.Debt) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryDebtStorage.Add")
+	defer span.Finish()
+
+	s.data = append(s.data, debtRecord{Debt: debt})
+
+	return nil
+}
+
+func (s *inMemoryDebtStorage) Balances(ctx context.Context, user *types.User) ([]types.DebtBalance, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryDebtStorage.Balances")
+	defer span.Finish()
+
+	type balanceKey struct {
+		counterparty *types.User
+		currency     string
+	}
+
+	balances := make(map[balanceKey]*types.DebtBalance)
+	add := func(counterparty *types.User, name, currency string, amount int64) {
+		key := balanceKey{counterparty, currency}
+		if balances[key] == nil {
+			balances[key] = &types.DebtBalance{Counterparty: counterparty, Currency: currency}
+		}
+
+		// the latest name of the counterparty wins
+		balances[key].Name = name
+		balances[key].Amount += amount
+	}
+
+	for _, record := range s.data {
+		switch {
+		case record.settled:
+		case record.Creditor == user:
+			add(record.Debtor, record.DebtorName, record.Currency, record.Amount)
+		case record.Debtor == user:
+			add(record.Creditor, record.CreditorName, record.Currency, -record.Amount)
+		}
+	}
+
+	list := make([]types.DebtBalance, 0, len(balances))
+	for _, balance := range balances {
+		list = append(list, *balance)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if *list[i].Counterparty != *list[j].Counterparty {
+			return *list[i].Counterparty < *list[j].Counterparty
+		}
+
+		return list[i].Currency < list[j].Currency
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryDebtStorage) Settle(ctx context.Context, user, counterparty *types.User, currency string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryDebtStorage.Settle")
+	defer span.Finish()
+
+	for i, record := range s.data {
+		if record.Currency == currency &&
+			(record.Creditor == user && record.Debtor == counterparty || record.Creditor == counterparty && record.Debtor == user) {
+			s.data[i].settled = true
+		}
+	}
+
+	return nil
+}
+
+func (s *inMemoryDebtStorage) snapshot() func() {
+	data := append([]debtRecord(nil), s.data...)
+
+	return func() {
+		s.data = data
+	}
+}
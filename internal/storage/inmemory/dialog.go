@@ -0,0 +1,82 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+// dialogState is the value kept until the deadline, zero deadline never comes.
+type dialogState struct {
+	value    []byte
+	deadline time.Time
+}
+
+// inMemoryDialogStorage is shared by concurrent handlers of the messages unlike the other storages,
+// hence the mutex. Expired states are deleted once they're requested.
+type inMemoryDialogStorage struct {
+	mu   sync.Mutex
+	data map[*types.User]map[string]dialogState
+}
+
+func NewDialogStorage() *inMemoryDialogStorage {
+	return &inMemoryDialogStorage{
+		data: make(map[*types.User]map[string]dialogState),
+	}
+}
+
+func (s *inMemoryDialogStorage) Get(ctx context.Context, user *types.User, key string) ([]byte, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryDialogStorage.Get")
+	defer span.Finish()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.data[user][key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if !state.deadline.IsZero() && !time.Now().Before(state.deadline) {
+		delete(s.data[user], key)
+		return nil, false, nil
+	}
+
+	return state.value, true, nil
+}
+
+func (s *inMemoryDialogStorage) Set(ctx context.Context, user *types.User, key string, value []byte, ttl time.Duration) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryDialogStorage.Set")
+	defer span.Finish()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.data[user]; !ok {
+		s.data[user] = make(map[string]dialogState)
+	}
+
+	state := dialogState{value: value}
+	if ttl > 0 {
+		state.deadline = time.Now().Add(ttl)
+	}
+
+	s.data[user][key] = state
+
+	return nil
+}
+
+func (s *inMemoryDialogStorage) Delete(ctx context.Context, user *types.User, key string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryDialogStorage.Delete")
+	defer span.Finish()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data[user], key)
+
+	return nil
+}
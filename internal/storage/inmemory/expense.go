@@ -2,14 +2,19 @@ package inmemory
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 )
 
 type inMemoryExpenseStorage struct {
-	data map[*types.User][]*expensesGroup
+	data   map[*types.User][]*expensesGroup
+	hashes map[*types.User]map[string]struct{}
+	lastID int64
 }
 
 type expensesGroup struct {
@@ -17,22 +22,31 @@ type expensesGroup struct {
 	expenses []types.ExpenseItem
 }
 
-func (s *inMemoryExpenseStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+func (s *inMemoryExpenseStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) (int64, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.Add")
 	defer span.Finish()
 
+	s.lastID++
+	item.ID = s.lastID
+
+	s.add(user, item, category)
+
+	return item.ID, nil
+}
+
+func (s *inMemoryExpenseStorage) add(user *types.User, item types.ExpenseItem, category string) {
 	if _, ok := s.data[user]; !ok {
 		s.data[user] = []*expensesGroup{{
 			category: category,
 			expenses: []types.ExpenseItem{item},
 		}}
-		return nil
+		return
 	}
 
 	for _, group := range s.data[user] {
 		if group.category == category {
 			group.expenses = append(group.expenses, item)
-			return nil
+			return
 		}
 	}
 
@@ -40,11 +54,9 @@ func (s *inMemoryExpenseStorage) Add(ctx context.Context, user *types.User, item
 		category: category,
 		expenses: []types.ExpenseItem{item},
 	})
-
-	return nil
 }
 
-func (s *inMemoryExpenseStorage) List(ctx context.Context, user *types.User, from time.Time) (map[string][]types.ExpenseItem, error) {
+func (s *inMemoryExpenseStorage) List(ctx context.Context, user *types.User, from, to time.Time) (map[string][]types.ExpenseItem, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.List")
 	defer span.Finish()
 
@@ -57,7 +69,7 @@ func (s *inMemoryExpenseStorage) List(ctx context.Context, user *types.User, fro
 	for _, group := range s.data[user] {
 		result[group.category] = make([]types.ExpenseItem, 0)
 		for _, item := range group.expenses {
-			if item.Date.After(from) {
+			if !item.Date.Before(from) && (to.IsZero() || item.Date.Before(to)) {
 				result[group.category] = append(result[group.category], item)
 			}
 		}
@@ -65,3 +77,325 @@ func (s *inMemoryExpenseStorage) List(ctx context.Context, user *types.User, fro
 
 	return result, nil
 }
+
+func (s *inMemoryExpenseStorage) SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.SumByDate")
+	defer span.Finish()
+
+	type totalKey struct {
+		date     time.Time
+		category string
+		currency string
+	}
+
+	totals := make(map[totalKey]int64)
+	for _, group := range s.data[user] {
+		for _, item := range group.expenses {
+			if item.Date.Before(from) || (!to.IsZero() && !item.Date.Before(to)) {
+				continue
+			}
+
+			totals[totalKey{item.Date, group.category, item.Currency}] += item.Amount
+		}
+	}
+
+	list := make([]types.ExpenseTotal, 0, len(totals))
+	for key, amount := range totals {
+		list = append(list, types.ExpenseTotal{
+			Date:     key.date,
+			Category: key.category,
+			Currency: key.currency,
+			Amount:   amount,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		switch {
+		case !list[i].Date.Equal(list[j].Date):
+			return list[i].Date.Before(list[j].Date)
+		case list[i].Category != list[j].Category:
+			return list[i].Category < list[j].Category
+		}
+
+		return list[i].Currency < list[j].Currency
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryExpenseStorage) Walk(ctx context.Context, user *types.User, from, to time.Time, fn func(types.Expense) error) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.Walk")
+	defer span.Finish()
+
+	list := make([]types.Expense, 0)
+	for _, group := range s.data[user] {
+		for _, item := range group.expenses {
+			if !item.Date.Before(from) && (to.IsZero() || item.Date.Before(to)) {
+				list = append(list, types.Expense{
+					ExpenseItem: item,
+					Category:    group.category,
+				})
+			}
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Date.Equal(list[j].Date) {
+			return list[i].ID < list[j].ID
+		}
+
+		return list[i].Date.Before(list[j].Date)
+	})
+
+	for _, item := range list {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *inMemoryExpenseStorage) ListLast(ctx context.Context, user *types.User, count int) ([]types.Expense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.ListLast")
+	defer span.Finish()
+
+	list := make([]types.Expense, 0)
+	for _, group := range s.data[user] {
+		for _, item := range group.expenses {
+			list = append(list, types.Expense{
+				ExpenseItem: item,
+				Category:    group.category,
+			})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Date.Equal(list[j].Date) {
+			return list[i].ID > list[j].ID
+		}
+
+		return list[i].Date.After(list[j].Date)
+	})
+
+	if len(list) > count {
+		list = list[:count]
+	}
+
+	return list, nil
+}
+
+func (s *inMemoryExpenseStorage) AddImported(ctx context.Context, user *types.User, items []types.ImportedExpense) ([]types.Expense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.AddImported")
+	defer span.Finish()
+
+	if _, ok := s.hashes[user]; !ok {
+		s.hashes[user] = make(map[string]struct{})
+	}
+
+	list := make([]types.Expense, 0, len(items))
+	for _, item := range items {
+		if _, ok := s.hashes[user][item.Hash]; ok {
+			continue
+		}
+		s.hashes[user][item.Hash] = struct{}{}
+
+		s.lastID++
+		item.ID = s.lastID
+
+		s.add(user, item.ExpenseItem, item.Category)
+		list = append(list, item.Expense)
+	}
+
+	return list, nil
+}
+
+func (s *inMemoryExpenseStorage) Get(ctx context.Context, user *types.User, id int64) (types.Expense, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.Get")
+	defer span.Finish()
+
+	group, i := s.find(user, id)
+	if group == nil {
+		return types.Expense{}, false, nil
+	}
+
+	return types.Expense{
+		ExpenseItem: group.expenses[i],
+		Category:    group.category,
+	}, true, nil
+}
+
+func (s *inMemoryExpenseStorage) Update(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.Update")
+	defer span.Finish()
+
+	group, i := s.find(user, item.ID)
+	if group == nil {
+		return errors.New("expense not found")
+	}
+
+	// the account, the note and the tags aren't changed by updates
+	item.Account = group.expenses[i].Account
+	item.Note, item.Tags = group.expenses[i].Note, group.expenses[i].Tags
+
+	if group.category == category {
+		group.expenses[i] = item
+		return nil
+	}
+
+	group.expenses = append(group.expenses[:i], group.expenses[i+1:]...)
+	s.add(user, item, category)
+
+	return nil
+}
+
+func (s *inMemoryExpenseStorage) Delete(ctx context.Context, user *types.User, id int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.Delete")
+	defer span.Finish()
+
+	group, i := s.find(user, id)
+	if group == nil {
+		return errors.New("expense not found")
+	}
+
+	group.expenses = append(group.expenses[:i], group.expenses[i+1:]...)
+
+	return nil
+}
+
+func (s *inMemoryExpenseStorage) Find(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseStorage.Find")
+	defer span.Finish()
+
+	words := strings.Fields(strings.ToLower(filter.Query))
+
+	list := make([]types.Expense, 0)
+	for _, group := range s.data[user] {
+		for _, item := range group.expenses {
+			switch {
+			case !filter.From.IsZero() && item.Date.Before(filter.From),
+				!filter.To.IsZero() && !item.Date.Before(filter.To),
+				filter.Min != 0 && item.Amount < filter.Min,
+				filter.Max != 0 && item.Amount > filter.Max,
+				!matchExpense(group.category, item, words):
+				continue
+			}
+
+			list = append(list, types.Expense{
+				ExpenseItem: item,
+				Category:    group.category,
+			})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Date.Equal(list[j].Date) {
+			return list[i].ID > list[j].ID
+		}
+
+		return list[i].Date.After(list[j].Date)
+	})
+
+	if offset >= len(list) {
+		return []types.Expense{}, nil
+	}
+
+	list = list[offset:]
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	return list, nil
+}
+
+// matchExpense reports whether every word is found either in the category or in the note of the expense,
+// or is one of its tags.
+func matchExpense(category string, item types.ExpenseItem, words []string) bool {
+	text := category + " " + strings.ToLower(item.Note)
+
+	for _, word := range words {
+		if !strings.Contains(text, word) && !hasTag(item.Tags, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renameCategory moves the expenses of the category and its subcategories under the new name.
+func (s *inMemoryExpenseStorage) renameCategory(user *types.User, from, to string) bool {
+	if _, ok := s.data[user]; !ok {
+		return false
+	}
+
+	var moved []types.Expense
+
+	groups := s.data[user][:0]
+	for _, group := range s.data[user] {
+		category, ok := renameCategory(group.category, from, to)
+		if !ok {
+			groups = append(groups, group)
+			continue
+		}
+
+		for _, item := range group.expenses {
+			moved = append(moved, types.Expense{ExpenseItem: item, Category: category})
+		}
+	}
+	s.data[user] = groups
+
+	for _, item := range moved {
+		s.add(user, item.ExpenseItem, item.Category)
+	}
+
+	return len(moved) != 0
+}
+
+func (s *inMemoryExpenseStorage) find(user *types.User, id int64) (*expensesGroup, int) {
+	for _, group := range s.data[user] {
+		for i, item := range group.expenses {
+			if item.ID == id {
+				return group, i
+			}
+		}
+	}
+
+	return nil, 0
+}
+
+func (s *inMemoryExpenseStorage) snapshot() func() {
+	data := make(map[*types.User][]*expensesGroup, len(s.data))
+	for user, groups := range s.data {
+		data[user] = make([]*expensesGroup, 0, len(groups))
+		for _, group := range groups {
+			data[user] = append(data[user], &expensesGroup{
+				category: group.category,
+				expenses: append([]types.ExpenseItem(nil), group.expenses...),
+			})
+		}
+	}
+
+	hashes := make(map[*types.User]map[string]struct{}, len(s.hashes))
+	for user, userHashes := range s.hashes {
+		hashes[user] = make(map[string]struct{}, len(userHashes))
+		for hash := range userHashes {
+			hashes[user][hash] = struct{}{}
+		}
+	}
+
+	lastID := s.lastID
+
+	return func() {
+		s.data, s.hashes, s.lastID = data, hashes, lastID
+	}
+}
@@ -8,7 +8,8 @@ import (
 )
 
 type inMemoryExpenseLimitStorage struct {
-	data map[*types.User]map[string]types.LimitItem
+	data   map[*types.User]map[string]types.LimitItem
+	alerts map[*types.User][]int
 }
 
 func (s *inMemoryExpenseLimitStorage) Get(ctx context.Context, user *types.User, category string) (types.LimitItem, bool, error) {
@@ -28,15 +29,13 @@ func (s *inMemoryExpenseLimitStorage) Get(ctx context.Context, user *types.User,
 	return types.LimitItem{}, false, nil
 }
 
-func (s *inMemoryExpenseLimitStorage) Set(ctx context.Context, user *types.User, total int64, currency, category string) error {
+func (s *inMemoryExpenseLimitStorage) Set(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
 	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.Set")
 	defer span.Finish()
 
-	item := types.LimitItem{
-		Total:    total,
-		Remains:  total,
-		Currency: currency,
-	}
+	item.Remains = item.Total + item.Carried
+	item.Notified = 0
+	item.Overspent = 0
 
 	if _, ok := s.data[user]; ok {
 		s.data[user][category] = item
@@ -47,6 +46,58 @@ func (s *inMemoryExpenseLimitStorage) Set(ctx context.Context, user *types.User,
 	return nil
 }
 
+func (s *inMemoryExpenseLimitStorage) Renew(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.Renew")
+	defer span.Finish()
+
+	if _, ok := s.data[user]; !ok {
+		return nil
+	}
+
+	current, ok := s.data[user][category]
+	if !ok {
+		category = ""
+		current, ok = s.data[user][category]
+	}
+	if !ok || !current.Anchor.Before(item.Anchor) {
+		return nil
+	}
+
+	current.Remains = item.Remains
+	current.Carried = item.Carried
+	current.Anchor = item.Anchor
+	current.Notified = 0
+	current.Overspent = 0
+
+	s.data[user][category] = current
+
+	return nil
+}
+
+func (s *inMemoryExpenseLimitStorage) Refresh(ctx context.Context, user *types.User, remains int64, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.Refresh")
+	defer span.Finish()
+
+	if _, ok := s.data[user]; !ok {
+		return nil
+	}
+
+	item, ok := s.data[user][category]
+	if !ok {
+		category = ""
+		item, ok = s.data[user][category]
+	}
+	if !ok {
+		return nil
+	}
+
+	item.Remains = remains
+
+	s.data[user][category] = item
+
+	return nil
+}
+
 func (s *inMemoryExpenseLimitStorage) Decrease(ctx context.Context, user *types.User, value int64, category string) (bool, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.Decrease")
 	defer span.Finish()
@@ -66,6 +117,7 @@ func (s *inMemoryExpenseLimitStorage) Decrease(ctx context.Context, user *types.
 
 	item.Remains -= value
 	if item.Remains < 0 {
+		item.Overspent -= item.Remains
 		item.Remains = 0
 	}
 
@@ -74,6 +126,37 @@ func (s *inMemoryExpenseLimitStorage) Decrease(ctx context.Context, user *types.
 	return item.Remains == 0, nil
 }
 
+func (s *inMemoryExpenseLimitStorage) Increase(ctx context.Context, user *types.User, value int64, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.Increase")
+	defer span.Finish()
+
+	if _, ok := s.data[user]; !ok {
+		return nil
+	}
+
+	item, ok := s.data[user][category]
+	if !ok {
+		category = ""
+		item, ok = s.data[user][category]
+	}
+	if !ok {
+		return nil
+	}
+
+	item.Overspent -= value
+	if item.Overspent < 0 {
+		item.Remains -= item.Overspent
+		item.Overspent = 0
+	}
+	if item.Remains > item.Total+item.Carried {
+		item.Remains = item.Total + item.Carried
+	}
+
+	s.data[user][category] = item
+
+	return nil
+}
+
 func (s *inMemoryExpenseLimitStorage) Unset(ctx context.Context, user *types.User, category string) error {
 	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.Unset")
 	defer span.Finish()
@@ -93,3 +176,81 @@ func (s *inMemoryExpenseLimitStorage) List(ctx context.Context, user *types.User
 
 	return limits, found, nil
 }
+
+func (s *inMemoryExpenseLimitStorage) Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.Notify")
+	defer span.Finish()
+
+	if _, ok := s.data[user]; !ok {
+		return false, nil
+	}
+
+	item, ok := s.data[user][category]
+	if !ok {
+		category = ""
+		item, ok = s.data[user][category]
+	}
+	if !ok || item.Notified >= threshold {
+		return false, nil
+	}
+
+	item.Notified = threshold
+
+	s.data[user][category] = item
+
+	return true, nil
+}
+
+func (s *inMemoryExpenseLimitStorage) GetAlerts(ctx context.Context, user *types.User) ([]int, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.GetAlerts")
+	defer span.Finish()
+
+	thresholds, found := s.alerts[user]
+
+	return thresholds, found, nil
+}
+
+func (s *inMemoryExpenseLimitStorage) SetAlerts(ctx context.Context, user *types.User, thresholds []int) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryExpenseLimitStorage.SetAlerts")
+	defer span.Finish()
+
+	s.alerts[user] = thresholds
+
+	return nil
+}
+
+// renameCategory moves the limits of the category and its subcategories under the new name,
+// a limit is dropped if the new category already has its own one.
+func (s *inMemoryExpenseLimitStorage) renameCategory(user *types.User, from, to string) bool {
+	limits := s.data[user]
+
+	renamed := make(map[string]types.LimitItem)
+	for category, limit := range limits {
+		if newCategory, ok := renameCategory(category, from, to); ok {
+			delete(limits, category)
+			renamed[newCategory] = limit
+		}
+	}
+
+	for category, limit := range renamed {
+		if _, found := limits[category]; !found {
+			limits[category] = limit
+		}
+	}
+
+	return len(renamed) != 0
+}
+
+func (s *inMemoryExpenseLimitStorage) snapshot() func() {
+	data := make(map[*types.User]map[string]types.LimitItem, len(s.data))
+	for user, limits := range s.data {
+		data[user] = make(map[string]types.LimitItem, len(limits))
+		for category, limit := range limits {
+			data[user][category] = limit
+		}
+	}
+
+	return func() {
+		s.data = data
+	}
+}
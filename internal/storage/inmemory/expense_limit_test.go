@@ -0,0 +1,50 @@
+//go:build unit
+
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_inMemoryExpenseLimitStorage_Increase(t *testing.T) {
+	t.Run("overspent refunded", func(t *testing.T) {
+		// ARRANGE
+		user := types.User(1)
+		s := NewFactory().CreateExpenseLimitStorage()
+		_ = s.Set(context.Background(), &user, types.LimitItem{Total: 1000000, Currency: "RUB"}, "taxi")
+		_, _ = s.Decrease(context.Background(), &user, 900000, "taxi")
+
+		// ACT
+		reached, decreaseErr := s.Decrease(context.Background(), &user, 500000, "taxi")
+		overspent, _, _ := s.Get(context.Background(), &user, "taxi")
+		increaseErr := s.Increase(context.Background(), &user, 500000, "taxi")
+		refunded, _, _ := s.Get(context.Background(), &user, "taxi")
+
+		// ASSERT
+		assert.NoError(t, decreaseErr)
+		assert.NoError(t, increaseErr)
+		assert.True(t, reached)
+		assert.Equal(t, types.LimitItem{Total: 1000000, Currency: "RUB", Overspent: 400000}, overspent)
+		assert.Equal(t, types.LimitItem{Total: 1000000, Remains: 100000, Currency: "RUB"}, refunded)
+	})
+
+	t.Run("partially overspent refunded", func(t *testing.T) {
+		// ARRANGE
+		user := types.User(1)
+		s := NewFactory().CreateExpenseLimitStorage()
+		_ = s.Set(context.Background(), &user, types.LimitItem{Total: 1000000, Currency: "RUB"}, "taxi")
+		_, _ = s.Decrease(context.Background(), &user, 1200000, "taxi")
+
+		// ACT
+		err := s.Increase(context.Background(), &user, 100000, "taxi")
+		limit, _, _ := s.Get(context.Background(), &user, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, types.LimitItem{Total: 1000000, Currency: "RUB", Overspent: 100000}, limit)
+	})
+}
@@ -5,34 +5,130 @@ import (
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
 )
 
-type factory struct{}
+// factory shares the storages between their consumers, so that
+// the unit of work is able to roll back all of them at once.
+type factory struct {
+	expenses    *inMemoryExpenseStorage
+	categories  *inMemoryCategoryStorage
+	attachments *inMemoryAttachmentStorage
+	incomes     *inMemoryIncomeStorage
+	accounts    *inMemoryAccountStorage
+	goals       *inMemoryGoalStorage
+	groups      *inMemoryGroupStorage
+	debts       *inMemoryDebtStorage
+	limits      *inMemoryExpenseLimitStorage
+	currencies  *inMemoryCurrencyStorage
+	rules       *inMemoryMerchantRuleStorage
+	outbox      *inMemoryOutboxStorage
+	recurring   *inMemoryRecurringExpenseStorage
+	journal     *inMemoryJournalStorage
+}
 
 func NewFactory() *factory {
-	return &factory{}
+	expenses := &inMemoryExpenseStorage{
+		data:   make(map[*types.User][]*expensesGroup),
+		hashes: make(map[*types.User]map[string]struct{}),
+	}
+	incomes := &inMemoryIncomeStorage{
+		data: make(map[*types.User][]types.Income),
+	}
+	limits := &inMemoryExpenseLimitStorage{
+		data:   make(map[*types.User]map[string]types.LimitItem),
+		alerts: make(map[*types.User][]int),
+	}
+	rules := &inMemoryMerchantRuleStorage{
+		data: make(map[*types.User]map[string]string),
+	}
+	recurring := &inMemoryRecurringExpenseStorage{
+		data: make(map[*types.User][]types.RecurringExpense),
+	}
+
+	return &factory{
+		expenses: expenses,
+		categories: &inMemoryCategoryStorage{
+			aliases:   make(map[*types.User]map[string]string),
+			expenses:  expenses,
+			recurring: recurring,
+			limits:    limits,
+			rules:     rules,
+		},
+		attachments: &inMemoryAttachmentStorage{
+			data:     make(map[*types.User]map[int64]types.Attachment),
+			expenses: expenses,
+		},
+		incomes: incomes,
+		accounts: &inMemoryAccountStorage{
+			data:      make(map[*types.User][]types.Account),
+			transfers: make(map[*types.User][]types.Transfer),
+			expenses:  expenses,
+			incomes:   incomes,
+		},
+		goals: &inMemoryGoalStorage{
+			data:          make(map[*types.User][]types.Goal),
+			contributions: make(map[*types.User][]types.GoalContribution),
+		},
+		groups: &inMemoryGroupStorage{
+			data: make(map[int64]types.Group),
+		},
+		debts:  &inMemoryDebtStorage{},
+		limits: limits,
+		currencies: &inMemoryCurrencyStorage{
+			data: make(map[*types.User]string),
+		},
+		rules:     rules,
+		outbox:    &inMemoryOutboxStorage{},
+		recurring: recurring,
+		journal: &inMemoryJournalStorage{
+			data: make(map[*types.User][]types.Operation),
+		},
+	}
 }
 
 func (f *factory) CreateTelegramUserStorage() storage.TelegramUserStorage {
 	return &inMemoryTelegramUserStorage{
-		data: make(map[int64]*types.User),
+		data:      make(map[int64]*types.User),
+		languages: make(map[int64]string),
 	}
 }
 
 func (f *factory) CreateExpenseStorage() storage.ExpenseStorage {
-	return &inMemoryExpenseStorage{
-		data: make(map[*types.User][]*expensesGroup),
-	}
+	return f.expenses
+}
+
+func (f *factory) CreateCategoryStorage() storage.CategoryStorage {
+	return f.categories
+}
+
+func (f *factory) CreateAttachmentStorage() storage.AttachmentStorage {
+	return f.attachments
+}
+
+func (f *factory) CreateIncomeStorage() storage.IncomeStorage {
+	return f.incomes
+}
+
+func (f *factory) CreateAccountStorage() storage.AccountStorage {
+	return f.accounts
+}
+
+func (f *factory) CreateGoalStorage() storage.GoalStorage {
+	return f.goals
+}
+
+func (f *factory) CreateGroupStorage() storage.GroupStorage {
+	return f.groups
+}
+
+func (f *factory) CreateDebtStorage() storage.DebtStorage {
+	return f.debts
 }
 
 func (f *factory) CreateExpenseLimitStorage() storage.ExpenseLimitStorage {
-	return &inMemoryExpenseLimitStorage{
-		data: make(map[*types.User]map[string]types.LimitItem),
-	}
+	return f.limits
 }
 
 func (f *factory) CreateCurrencyStorage() storage.CurrencyStorage {
-	return &inMemoryCurrencyStorage{
-		data: make(map[*types.User]string),
-	}
+	return f.currencies
 }
 
 func (f *factory) CreateCurrencyRatesStorage() storage.CurrencyRatesStorage {
@@ -40,3 +136,25 @@ func (f *factory) CreateCurrencyRatesStorage() storage.CurrencyRatesStorage {
 		data: make(map[string]int64),
 	}
 }
+
+func (f *factory) CreateMerchantRuleStorage() storage.MerchantRuleStorage {
+	return f.rules
+}
+
+func (f *factory) CreateRecurringExpenseStorage() storage.RecurringExpenseStorage {
+	return f.recurring
+}
+
+func (f *factory) CreateOutboxStorage() storage.OutboxStorage {
+	return f.outbox
+}
+
+func (f *factory) CreateJournalStorage() storage.JournalStorage {
+	return f.journal
+}
+
+func (f *factory) CreateUnitOfWork() storage.UnitOfWork {
+	return &inMemoryUnitOfWork{
+		storages: []snapshotter{f.expenses, f.categories, f.incomes, f.accounts, f.debts, f.limits, f.currencies, f.outbox, f.recurring, f.journal},
+	}
+}
@@ -0,0 +1,132 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type inMemoryGoalStorage struct {
+	data          map[*types.User][]types.Goal
+	contributions map[*types.User][]types.GoalContribution
+	lastID        int64
+}
+
+func (s *inMemoryGoalStorage) Add(ctx context.Context, user *types.User, goal types.Goal) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGoalStorage.Add")
+	defer span.Finish()
+
+	for _, item := range s.data[user] {
+		if item.Name == goal.Name {
+			return false, nil
+		}
+	}
+
+	s.lastID++
+	goal.ID = s.lastID
+
+	s.data[user] = append(s.data[user], goal)
+
+	return true, nil
+}
+
+func (s *inMemoryGoalStorage) List(ctx context.Context, user *types.User) ([]types.Goal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGoalStorage.List")
+	defer span.Finish()
+
+	// goals without deadlines go last
+	list := append([]types.Goal{}, s.data[user]...)
+	sort.Slice(list, func(i, j int) bool {
+		switch {
+		case list[i].Deadline.Equal(list[j].Deadline):
+			return list[i].Name < list[j].Name
+		case list[i].Deadline.IsZero() || list[j].Deadline.IsZero():
+			return list[j].Deadline.IsZero()
+		}
+
+		return list[i].Deadline.Before(list[j].Deadline)
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryGoalStorage) GetByName(ctx context.Context, user *types.User, name string) (types.Goal, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGoalStorage.GetByName")
+	defer span.Finish()
+
+	for _, item := range s.data[user] {
+		if item.Name == name {
+			return item, true, nil
+		}
+	}
+
+	return types.Goal{}, false, nil
+}
+
+func (s *inMemoryGoalStorage) Delete(ctx context.Context, user *types.User, id int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGoalStorage.Delete")
+	defer span.Finish()
+
+	goals := s.data[user][:0]
+	for _, item := range s.data[user] {
+		if item.ID != id {
+			goals = append(goals, item)
+		}
+	}
+	s.data[user] = goals
+
+	contributions := s.contributions[user][:0]
+	for _, item := range s.contributions[user] {
+		if item.Goal != id {
+			contributions = append(contributions, item)
+		}
+	}
+	s.contributions[user] = contributions
+
+	return nil
+}
+
+func (s *inMemoryGoalStorage) AddContribution(ctx context.Context, user *types.User, contribution types.GoalContribution) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGoalStorage.AddContribution")
+	defer span.Finish()
+
+	s.contributions[user] = append(s.contributions[user], contribution)
+
+	return nil
+}
+
+func (s *inMemoryGoalStorage) Totals(ctx context.Context, user *types.User) ([]types.GoalTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGoalStorage.Totals")
+	defer span.Finish()
+
+	type totalKey struct {
+		goal     int64
+		currency string
+	}
+
+	totals := make(map[totalKey]int64)
+	for _, item := range s.contributions[user] {
+		totals[totalKey{item.Goal, item.Currency}] += item.Amount
+	}
+
+	list := make([]types.GoalTotal, 0, len(totals))
+	for key, amount := range totals {
+		list = append(list, types.GoalTotal{
+			Goal:     key.goal,
+			Currency: key.currency,
+			Amount:   amount,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Goal != list[j].Goal {
+			return list[i].Goal < list[j].Goal
+		}
+
+		return list[i].Currency < list[j].Currency
+	})
+
+	return list, nil
+}
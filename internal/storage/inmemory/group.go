@@ -0,0 +1,140 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type inMemoryGroupStorage struct {
+	data   map[int64]types.Group
+	lastID int64
+}
+
+func (s *inMemoryGroupStorage) Add(ctx context.Context, code string, owner types.GroupMember) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGroupStorage.Add")
+	defer span.Finish()
+
+	for _, group := range s.data {
+		if group.Code == code {
+			return 0, errors.New("duplicate group code")
+		}
+	}
+
+	if _, found := s.find(owner.User); found {
+		return 0, errors.New("owner is a member of another group")
+	}
+
+	s.lastID++
+	s.data[s.lastID] = types.Group{
+		ID:      s.lastID,
+		Code:    code,
+		Members: []types.GroupMember{owner},
+	}
+
+	return s.lastID, nil
+}
+
+func (s *inMemoryGroupStorage) GetByCode(ctx context.Context, code string) (types.Group, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGroupStorage.GetByCode")
+	defer span.Finish()
+
+	for _, group := range s.data {
+		if group.Code == code {
+			return s.copy(group), true, nil
+		}
+	}
+
+	return types.Group{}, false, nil
+}
+
+func (s *inMemoryGroupStorage) GetByUser(ctx context.Context, user *types.User) (types.Group, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGroupStorage.GetByUser")
+	defer span.Finish()
+
+	group, found := s.find(user)
+	if !found {
+		return types.Group{}, false, nil
+	}
+
+	return s.copy(group), true, nil
+}
+
+func (s *inMemoryGroupStorage) AddMember(ctx context.Context, group int64, member types.GroupMember) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGroupStorage.AddMember")
+	defer span.Finish()
+
+	if _, found := s.find(member.User); found {
+		return false, nil
+	}
+
+	item, ok := s.data[group]
+	if !ok {
+		return false, errors.New("unknown group")
+	}
+
+	item.Members = append(item.Members, member)
+	s.data[group] = item
+
+	return true, nil
+}
+
+func (s *inMemoryGroupStorage) RemoveMember(ctx context.Context, user *types.User) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGroupStorage.RemoveMember")
+	defer span.Finish()
+
+	group, found := s.find(user)
+	if !found {
+		return nil
+	}
+
+	members := make([]types.GroupMember, 0, len(group.Members))
+	for _, member := range group.Members {
+		if member.User != user {
+			members = append(members, member)
+		}
+	}
+
+	group.Members = members
+	s.data[group.ID] = group
+
+	return nil
+}
+
+func (s *inMemoryGroupStorage) Delete(ctx context.Context, group int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryGroupStorage.Delete")
+	defer span.Finish()
+
+	delete(s.data, group)
+
+	return nil
+}
+
+func (s *inMemoryGroupStorage) find(user *types.User) (types.Group, bool) {
+	for _, group := range s.data {
+		for _, member := range group.Members {
+			if member.User == user {
+				return group, true
+			}
+		}
+	}
+
+	return types.Group{}, false
+}
+
+// copy returns the group with its own copy of the members, the owner goes first.
+func (s *inMemoryGroupStorage) copy(group types.Group) types.Group {
+	group.Members = append([]types.GroupMember{}, group.Members...)
+	sort.SliceStable(group.Members, func(i, j int) bool {
+		if group.Members[i].Role != group.Members[j].Role {
+			return group.Members[i].Role == types.RoleOwner
+		}
+
+		return group.Members[i].Name < group.Members[j].Name
+	})
+
+	return group
+}
@@ -0,0 +1,103 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type inMemoryIncomeStorage struct {
+	data   map[*types.User][]types.Income
+	lastID int64
+}
+
+func (s *inMemoryIncomeStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryIncomeStorage.Add")
+	defer span.Finish()
+
+	s.lastID++
+	item.ID = s.lastID
+
+	s.data[user] = append(s.data[user], types.Income{ExpenseItem: item, Category: category})
+
+	return nil
+}
+
+func (s *inMemoryIncomeStorage) SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryIncomeStorage.SumByDate")
+	defer span.Finish()
+
+	type totalKey struct {
+		date     time.Time
+		category string
+		currency string
+	}
+
+	totals := make(map[totalKey]int64)
+	for _, item := range s.data[user] {
+		if item.Date.Before(from) || (!to.IsZero() && !item.Date.Before(to)) {
+			continue
+		}
+
+		totals[totalKey{item.Date, item.Category, item.Currency}] += item.Amount
+	}
+
+	list := make([]types.ExpenseTotal, 0, len(totals))
+	for key, amount := range totals {
+		list = append(list, types.ExpenseTotal{
+			Date:     key.date,
+			Category: key.category,
+			Currency: key.currency,
+			Amount:   amount,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		switch {
+		case !list[i].Date.Equal(list[j].Date):
+			return list[i].Date.Before(list[j].Date)
+		case list[i].Category != list[j].Category:
+			return list[i].Category < list[j].Category
+		}
+
+		return list[i].Currency < list[j].Currency
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryIncomeStorage) ListLast(ctx context.Context, user *types.User, count int) ([]types.Income, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryIncomeStorage.ListLast")
+	defer span.Finish()
+
+	list := append([]types.Income(nil), s.data[user]...)
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].Date.Equal(list[j].Date) {
+			return list[i].Date.After(list[j].Date)
+		}
+
+		return list[i].ID > list[j].ID
+	})
+
+	if len(list) > count {
+		list = list[:count]
+	}
+
+	return list, nil
+}
+
+func (s *inMemoryIncomeStorage) snapshot() func() {
+	data := make(map[*types.User][]types.Income, len(s.data))
+	for user, list := range s.data {
+		data[user] = list[:len(list):len(list)]
+	}
+
+	lastID := s.lastID
+
+	return func() {
+		s.data, s.lastID = data, lastID
+	}
+}
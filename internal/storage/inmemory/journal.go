@@ -0,0 +1,74 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type inMemoryJournalStorage struct {
+	data   map[*types.User][]types.Operation
+	lastID int64
+}
+
+func (s *inMemoryJournalStorage) Add(ctx context.Context, user *types.User, op types.Operation) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryJournalStorage.Add")
+	defer span.Finish()
+
+	s.lastID++
+	op.ID = s.lastID
+
+	s.data[user] = append(s.data[user], op)
+
+	return op.ID, nil
+}
+
+func (s *inMemoryJournalStorage) Pop(ctx context.Context, user *types.User, id int64, since time.Time) (types.Operation, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryJournalStorage.Pop")
+	defer span.Finish()
+
+	list := s.data[user]
+	if len(list) == 0 || list[len(list)-1].Created.Before(since) {
+		return types.Operation{}, false, nil
+	}
+
+	op := list[len(list)-1]
+	if id != 0 && op.ID != id {
+		return types.Operation{}, false, nil
+	}
+
+	s.data[user] = list[: len(list)-1 : len(list)-1]
+
+	return op, true, nil
+}
+
+func (s *inMemoryJournalStorage) DeleteBefore(ctx context.Context, user *types.User, before time.Time) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryJournalStorage.DeleteBefore")
+	defer span.Finish()
+
+	list := s.data[user]
+
+	kept := make([]types.Operation, 0, len(list))
+	for _, op := range list {
+		if !op.Created.Before(before) {
+			kept = append(kept, op)
+		}
+	}
+
+	s.data[user] = kept
+
+	return nil
+}
+
+func (s *inMemoryJournalStorage) snapshot() func() {
+	data := make(map[*types.User][]types.Operation, len(s.data))
+	for user, list := range s.data {
+		data[user] = append([]types.Operation(nil), list...)
+	}
+
+	return func() {
+		s.data = data
+	}
+}
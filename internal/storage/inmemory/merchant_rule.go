@@ -0,0 +1,67 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type inMemoryMerchantRuleStorage struct {
+	data map[*types.User]map[string]string
+}
+
+func (s *inMemoryMerchantRuleStorage) List(ctx context.Context, user *types.User) ([]types.MerchantRule, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryMerchantRuleStorage.List")
+	defer span.Finish()
+
+	list := make([]types.MerchantRule, 0, len(s.data[user]))
+	for pattern, category := range s.data[user] {
+		list = append(list, types.MerchantRule{Pattern: pattern, Category: category})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Pattern < list[j].Pattern
+	})
+
+	return list, nil
+}
+
+func (s *inMemoryMerchantRuleStorage) Set(ctx context.Context, user *types.User, rule types.MerchantRule) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryMerchantRuleStorage.Set")
+	defer span.Finish()
+
+	if _, ok := s.data[user]; !ok {
+		s.data[user] = make(map[string]string)
+	}
+
+	s.data[user][rule.Pattern] = rule.Category
+
+	return nil
+}
+
+func (s *inMemoryMerchantRuleStorage) Delete(ctx context.Context, user *types.User, pattern string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryMerchantRuleStorage.Delete")
+	defer span.Finish()
+
+	if _, ok := s.data[user][pattern]; !ok {
+		return false, nil
+	}
+
+	delete(s.data[user], pattern)
+
+	return true, nil
+}
+
+// renameCategory moves the rules of the category and its subcategories under the new name.
+func (s *inMemoryMerchantRuleStorage) renameCategory(user *types.User, from, to string) bool {
+	var renamed bool
+	for pattern, category := range s.data[user] {
+		if category, ok := renameCategory(category, from, to); ok {
+			s.data[user][pattern], renamed = category, true
+		}
+	}
+
+	return renamed
+}
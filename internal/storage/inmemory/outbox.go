@@ -0,0 +1,66 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type inMemoryOutboxStorage struct {
+	data   []types.OutboxEvent
+	lastID int64
+}
+
+func (s *inMemoryOutboxStorage) Add(ctx context.Context, event types.OutboxEvent) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryOutboxStorage.Add")
+	defer span.Finish()
+
+	s.lastID++
+	event.ID = s.lastID
+
+	s.data = append(s.data, event)
+
+	return nil
+}
+
+// LockPending returns the oldest events, the lock of the unit of work keeps them from concurrent relays.
+func (s *inMemoryOutboxStorage) LockPending(ctx context.Context, limit int) ([]types.OutboxEvent, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryOutboxStorage.LockPending")
+	defer span.Finish()
+
+	if limit > len(s.data) {
+		limit = len(s.data)
+	}
+
+	return append([]types.OutboxEvent(nil), s.data[:limit]...), nil
+}
+
+func (s *inMemoryOutboxStorage) Delete(ctx context.Context, ids []int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryOutboxStorage.Delete")
+	defer span.Finish()
+
+	deleted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	data := make([]types.OutboxEvent, 0, len(s.data))
+	for _, event := range s.data {
+		if !deleted[event.ID] {
+			data = append(data, event)
+		}
+	}
+
+	s.data = data
+
+	return nil
+}
+
+func (s *inMemoryOutboxStorage) snapshot() func() {
+	data, lastID := s.data[:len(s.data):len(s.data)], s.lastID
+
+	return func() {
+		s.data, s.lastID = data, lastID
+	}
+}
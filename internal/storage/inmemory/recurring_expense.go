@@ -0,0 +1,122 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type inMemoryRecurringExpenseStorage struct {
+	data   map[*types.User][]types.RecurringExpense
+	lastID int64
+}
+
+func (s *inMemoryRecurringExpenseStorage) Add(ctx context.Context, user *types.User, item types.RecurringExpense) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryRecurringExpenseStorage.Add")
+	defer span.Finish()
+
+	s.lastID++
+	item.ID = s.lastID
+
+	s.data[user] = append(s.data[user], item)
+
+	return item.ID, nil
+}
+
+func (s *inMemoryRecurringExpenseStorage) List(ctx context.Context, user *types.User) ([]types.RecurringExpense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryRecurringExpenseStorage.List")
+	defer span.Finish()
+
+	list := make([]types.RecurringExpense, len(s.data[user]))
+	copy(list, s.data[user])
+
+	return list, nil
+}
+
+func (s *inMemoryRecurringExpenseStorage) Get(ctx context.Context, user *types.User, id int64) (types.RecurringExpense, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryRecurringExpenseStorage.Get")
+	defer span.Finish()
+
+	for _, item := range s.data[user] {
+		if item.ID == id {
+			return item, true, nil
+		}
+	}
+
+	return types.RecurringExpense{}, false, nil
+}
+
+func (s *inMemoryRecurringExpenseStorage) Update(ctx context.Context, user *types.User, item types.RecurringExpense) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryRecurringExpenseStorage.Update")
+	defer span.Finish()
+
+	for i, current := range s.data[user] {
+		if current.ID == item.ID {
+			current.Count = item.Count
+			current.Next = item.Next
+			current.Paused = item.Paused
+
+			s.data[user][i] = current
+		}
+	}
+
+	return nil
+}
+
+func (s *inMemoryRecurringExpenseStorage) LockDue(ctx context.Context, date time.Time, exclude []int64) (*types.User, types.RecurringExpense, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryRecurringExpenseStorage.LockDue")
+	defer span.Finish()
+
+	var (
+		due   types.RecurringExpense
+		owner *types.User
+	)
+
+	for user, list := range s.data {
+	next:
+		for _, item := range list {
+			if item.Paused || item.Next.After(date) {
+				continue
+			}
+
+			for _, id := range exclude {
+				if item.ID == id {
+					continue next
+				}
+			}
+
+			if owner == nil || item.Next.Before(due.Next) || item.Next.Equal(due.Next) && item.ID < due.ID {
+				due, owner = item, user
+			}
+		}
+	}
+
+	return owner, due, owner != nil, nil
+}
+
+// renameCategory moves the recurring expenses of the category and its subcategories under the new name.
+func (s *inMemoryRecurringExpenseStorage) renameCategory(user *types.User, from, to string) bool {
+	var renamed bool
+	for i, item := range s.data[user] {
+		if category, ok := renameCategory(item.Category, from, to); ok {
+			s.data[user][i].Category, renamed = category, true
+		}
+	}
+
+	return renamed
+}
+
+func (s *inMemoryRecurringExpenseStorage) snapshot() func() {
+	data := make(map[*types.User][]types.RecurringExpense, len(s.data))
+	for user, list := range s.data {
+		data[user] = append([]types.RecurringExpense(nil), list...)
+	}
+
+	lastID := s.lastID
+
+	return func() {
+		s.data, s.lastID = data, lastID
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 type inMemoryTelegramUserStorage struct {
-	data map[int64]*types.User
+	data      map[int64]*types.User
+	languages map[int64]string
 }
 
 func (s *inMemoryTelegramUserStorage) Add(ctx context.Context, tgUserID int64) (*types.User, error) {
@@ -36,3 +37,36 @@ func (s *inMemoryTelegramUserStorage) FetchByID(ctx context.Context, tgUserID in
 
 	return nil, errors.New("user not found")
 }
+
+func (s *inMemoryTelegramUserStorage) FetchTelegramID(ctx context.Context, user *types.User) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryTelegramUserStorage.FetchTelegramID")
+	defer span.Finish()
+
+	for tgUserID, u := range s.data {
+		if u == user {
+			return tgUserID, nil
+		}
+	}
+
+	return 0, errors.New("user not found")
+}
+
+func (s *inMemoryTelegramUserStorage) FetchLanguage(ctx context.Context, tgUserID int64) (string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryTelegramUserStorage.FetchLanguage")
+	defer span.Finish()
+
+	return s.languages[tgUserID], nil
+}
+
+func (s *inMemoryTelegramUserStorage) SetLanguage(ctx context.Context, tgUserID int64, lang string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "inMemoryTelegramUserStorage.SetLanguage")
+	defer span.Finish()
+
+	if _, ok := s.data[tgUserID]; !ok {
+		return errors.New("user not found")
+	}
+
+	s.languages[tgUserID] = lang
+
+	return nil
+}
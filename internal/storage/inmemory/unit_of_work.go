@@ -0,0 +1,72 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/ctxkey"
+)
+
+// snapshotter copies the storage data and returns the function restoring it.
+type snapshotter interface {
+	snapshot() (restore func())
+}
+
+type inMemoryUnitOfWork struct {
+	mu       sync.Mutex
+	storages []snapshotter
+}
+
+func (u *inMemoryUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(ctxkey.Transaction) == u {
+		return fn(ctx)
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inMemoryUnitOfWork.Do")
+	defer span.Finish()
+
+	hooks, err := u.do(ctx, fn)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+
+	return nil
+}
+
+// do runs fn under the lock and returns the hooks to run after the changes are kept.
+func (u *inMemoryUnitOfWork) do(ctx context.Context, fn func(ctx context.Context) error) ([]func(ctx context.Context), error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	restore := make([]func(), 0, len(u.storages))
+	for _, s := range u.storages {
+		restore = append(restore, s.snapshot())
+	}
+
+	var hooks []func(ctx context.Context)
+
+	txCtx := context.WithValue(context.WithValue(ctx, ctxkey.Transaction, u), ctxkey.CommitHooks, &hooks)
+	if err := fn(txCtx); err != nil {
+		for _, r := range restore {
+			r()
+		}
+
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+func (u *inMemoryUnitOfWork) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(ctxkey.CommitHooks).(*[]func(ctx context.Context)); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+
+	fn(ctx)
+}
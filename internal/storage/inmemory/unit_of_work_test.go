@@ -0,0 +1,38 @@
+//go:build unit
+
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_inMemoryUnitOfWork_Do(t *testing.T) {
+	t.Run("currency rolled back", func(t *testing.T) {
+		// ARRANGE
+		user := types.User(1)
+		f := NewFactory()
+		uow := f.CreateUnitOfWork()
+		_ = f.CreateCurrencyStorage().Set(context.Background(), &user, "RUB")
+
+		// ACT
+		doErr := uow.Do(context.Background(), func(ctx context.Context) error {
+			if err := f.CreateCurrencyStorage().Set(ctx, &user, "USD"); err != nil {
+				return err
+			}
+
+			return errors.New("failure")
+		})
+		currency, found, getErr := f.CreateCurrencyStorage().Get(context.Background(), &user)
+
+		// ASSERT
+		assert.Error(t, doErr)
+		assert.NoError(t, getErr)
+		assert.True(t, found)
+		assert.Equal(t, "RUB", currency)
+	})
+}
@@ -0,0 +1,101 @@
+package localfs
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// localBlobStorage keeps the files in the directory, the keys are slash-separated paths relative to it.
+type localBlobStorage struct {
+	dir string
+}
+
+// NewBlobStorage creates the storage keeping the files in the directory, the directory is created if missing.
+func NewBlobStorage(dir string) (*localBlobStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errors.Wrap(err, "create directory")
+	}
+
+	return &localBlobStorage{
+		dir: dir,
+	}, nil
+}
+
+// Put writes the file to a temporary one first, so that a failed write never leaves a truncated file under the key.
+func (s *localBlobStorage) Put(ctx context.Context, key string, data []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "localBlobStorage.Put")
+	defer span.Finish()
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.Wrap(err, "create directory")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "create temporary file")
+	}
+	defer os.Remove(tmp.Name()) // fails once the file is renamed
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "write temporary file")
+	}
+
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "close temporary file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), path), "rename temporary file")
+}
+
+func (s *localBlobStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "localBlobStorage.Get")
+	defer span.Finish()
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read file")
+	}
+
+	return data, nil
+}
+
+func (s *localBlobStorage) Delete(ctx context.Context, key string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "localBlobStorage.Delete")
+	defer span.Finish()
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Wrap(err, "remove file")
+	}
+
+	return nil
+}
+
+// path resolves the key within the directory, keys pointing outside of it are rejected.
+func (s *localBlobStorage) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", errors.Errorf("invalid key %q", key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,51 @@
+//go:build unit
+
+package localfs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_localBlobStorage(t *testing.T) {
+	// ARRANGE
+	ctx := context.Background()
+
+	s, err := NewBlobStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("cannot create storage: %s", err.Error())
+	}
+
+	t.Run("put and get", func(t *testing.T) {
+		// ACT
+		putErr := s.Put(ctx, "123/7-a.jpg", []byte("photo"))
+		data, err := s.Get(ctx, "123/7-a.jpg")
+
+		// ASSERT
+		assert.NoError(t, putErr)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("photo"), data)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// ACT
+		err1 := s.Delete(ctx, "123/7-a.jpg")
+		err2 := s.Delete(ctx, "123/7-a.jpg")
+		_, getErr := s.Get(ctx, "123/7-a.jpg")
+
+		// ASSERT
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Error(t, getErr)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		// ACT
+		err := s.Put(ctx, "../7-a.jpg", []byte("photo"))
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,164 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgAccountStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgAccountStorage) Add(ctx context.Context, user *types.User, account types.Account) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAccountStorage.Add")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into accounts (user_id, name, currency_code, opening)
+         values ($1, $2, $3, $4)
+         on conflict (user_id, name) do nothing`,
+		user,             // $1
+		account.Name,     // $2
+		account.Currency, // $3
+		account.Opening,  // $4
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "insert account")
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (s *pgAccountStorage) List(ctx context.Context, user *types.User) ([]types.Account, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAccountStorage.List")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select id, name, currency_code, opening
+         from accounts
+         where user_id = $1
+         order by name`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select accounts")
+	}
+	defer rows.Close()
+
+	list := make([]types.Account, 0)
+
+	var item types.Account
+	for rows.Next() {
+		if err := rows.Scan(&item.ID, &item.Name, &item.Currency, &item.Opening); err != nil {
+			return nil, errors.Wrap(err, "scan selected accounts")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected accounts")
+}
+
+func (s *pgAccountStorage) GetByName(ctx context.Context, user *types.User, name string) (types.Account, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAccountStorage.GetByName")
+	defer span.Finish()
+
+	var item types.Account
+
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select id, name, currency_code, opening
+         from accounts
+         where user_id = $1
+           and name = $2`,
+		user, // $1
+		name, // $2
+	).Scan(&item.ID, &item.Name, &item.Currency, &item.Opening)
+	if err == pgx.ErrNoRows {
+		return types.Account{}, false, nil
+	} else if err != nil {
+		return types.Account{}, false, errors.Wrap(err, "select account")
+	}
+
+	return item, true, nil
+}
+
+func (s *pgAccountStorage) AddTransfer(ctx context.Context, user *types.User, transfer types.Transfer) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAccountStorage.AddTransfer")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into transfers (user_id, date, from_account_id, to_account_id, amount, received)
+         values ($1, $2, $3, $4, $5, $6)`,
+		user,              // $1
+		transfer.Date,     // $2
+		transfer.From,     // $3
+		transfer.To,       // $4
+		transfer.Amount,   // $5
+		transfer.Received, // $6
+	)
+	if err != nil {
+		return errors.Wrap(err, "insert transfer")
+	}
+
+	return nil
+}
+
+func (s *pgAccountStorage) Turnover(ctx context.Context, user *types.User) ([]types.AccountTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAccountStorage.Turnover")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select account_id, date, currency_code, sum(amount)::bigint
+         from (
+           select account_id, date, currency_code, amount
+           from incomes
+           where user_id = $1
+             and account_id is not null
+           union all
+           select account_id, date, currency_code, -amount
+           from expenses
+           where user_id = $1
+             and account_id is not null
+           union all
+           select t.from_account_id, t.date, a.currency_code, -t.amount
+           from transfers t
+             join accounts a on a.id = t.from_account_id
+           where t.user_id = $1
+           union all
+           select t.to_account_id, t.date, a.currency_code, t.received
+           from transfers t
+             join accounts a on a.id = t.to_account_id
+           where t.user_id = $1
+         ) movements
+         group by account_id, date, currency_code
+         order by account_id, date, currency_code`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select account turnover")
+	}
+	defer rows.Close()
+
+	list := make([]types.AccountTotal, 0)
+
+	var item types.AccountTotal
+	for rows.Next() {
+		if err := rows.Scan(&item.Account, &item.Date, &item.Currency, &item.Amount); err != nil {
+			return nil, errors.Wrap(err, "scan selected account turnover")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected account turnover")
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgAccountStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateAccountStorage()
+	expenses := _testFactory.CreateExpenseStorage()
+	incomes := _testFactory.CreateIncomeStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where user_id in ($1, $2)`, int64(*_testUser101), int64(*_testUser102))
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from incomes where user_id in ($1, $2)`, int64(*_testUser101), int64(*_testUser102))
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from accounts where user_id in ($1, $2)`, int64(*_testUser101), int64(*_testUser102))
+	})
+
+	var card, savings types.Account
+
+	t.Run("add", func(t *testing.T) {
+		// ACT
+		cardAdded, cardErr := s.Add(_ctx, _testUser101, types.Account{Name: "card", Currency: "RUB", Opening: 10000000})
+		savingsAdded, savingsErr := s.Add(_ctx, _testUser101, types.Account{Name: "savings", Currency: "USD"})
+		duplicateAdded, duplicateErr := s.Add(_ctx, _testUser101, types.Account{Name: "card", Currency: "EUR"})
+		otherAdded, otherErr := s.Add(_ctx, _testUser102, types.Account{Name: "card", Currency: "RUB"})
+
+		// ASSERT
+		assert.NoError(t, cardErr)
+		assert.True(t, cardAdded)
+		assert.NoError(t, savingsErr)
+		assert.True(t, savingsAdded)
+		assert.NoError(t, duplicateErr)
+		assert.False(t, duplicateAdded)
+		assert.NoError(t, otherErr)
+		assert.True(t, otherAdded)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		// ACT
+		list, err := s.List(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			card, savings = list[0], list[1]
+			assert.NotZero(t, card.ID)
+			assert.Equal(t, types.Account{ID: card.ID, Name: "card", Currency: "RUB", Opening: 10000000}, card)
+			assert.Equal(t, types.Account{ID: savings.ID, Name: "savings", Currency: "USD"}, savings)
+		}
+	})
+
+	t.Run("get by name", func(t *testing.T) {
+		// ACT
+		account, found, err := s.GetByName(_ctx, _testUser101, "savings")
+		_, missingFound, missingErr := s.GetByName(_ctx, _testUser101, "wallet")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, savings, account)
+		assert.NoError(t, missingErr)
+		assert.False(t, missingFound)
+	})
+
+	t.Run("turnover", func(t *testing.T) {
+		// ARRANGE
+		date := time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC)
+		_, coffeeErr := expenses.Add(_ctx, _testUser101, types.ExpenseItem{Date: date, Amount: 4500000, Currency: "RUB", Account: card.ID}, "coffee")
+		assert.NoError(t, coffeeErr)
+		_, taxiErr := expenses.Add(_ctx, _testUser101, types.ExpenseItem{Date: date, Amount: 1000000, Currency: "RUB"}, "taxi")
+		assert.NoError(t, taxiErr)
+		assert.NoError(t, incomes.Add(_ctx, _testUser101, types.ExpenseItem{Date: date, Amount: 1500000000, Currency: "RUB", Account: card.ID}, "salary"))
+		assert.NoError(t, s.AddTransfer(_ctx, _testUser101, types.Transfer{
+			Date:     date.AddDate(0, 0, 1),
+			From:     card.ID,
+			To:       savings.ID,
+			Amount:   61500000,
+			Received: 1000000,
+		}))
+
+		// ACT
+		list, err := s.Turnover(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.AccountTotal{
+			{Account: card.ID, Date: date, Currency: "RUB", Amount: 1495500000},
+			{Account: card.ID, Date: date.AddDate(0, 0, 1), Currency: "RUB", Amount: -61500000},
+			{Account: savings.ID, Date: date.AddDate(0, 0, 1), Currency: "USD", Amount: 1000000},
+		}, list)
+	})
+}
@@ -0,0 +1,138 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgAttachmentStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgAttachmentStorage) Set(ctx context.Context, user *types.User, item types.Attachment) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAttachmentStorage.Set")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into expense_attachments (user_id, expense_id, blob_key, size, created_at)
+         select user_id, id, $3, $4, $5
+         from expenses
+         where user_id = $1
+           and id = $2
+         on conflict (expense_id) do update
+           set blob_key   = excluded.blob_key,
+               size       = excluded.size,
+               created_at = excluded.created_at`,
+		user,           // $1
+		item.ExpenseID, // $2
+		item.Key,       // $3
+		item.Size,      // $4
+		item.Created,   // $5
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "upsert attachment")
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (s *pgAttachmentStorage) Get(ctx context.Context, user *types.User, expenseID int64) (types.Attachment, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAttachmentStorage.Get")
+	defer span.Finish()
+
+	item := types.Attachment{ExpenseID: expenseID}
+
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select blob_key, size, created_at
+         from expense_attachments
+         where user_id = $1
+           and expense_id = $2`,
+		user,      // $1
+		expenseID, // $2
+	).Scan(&item.Key, &item.Size, &item.Created)
+	if err == pgx.ErrNoRows {
+		return types.Attachment{}, false, nil
+	} else if err != nil {
+		return types.Attachment{}, false, errors.Wrap(err, "select attachment")
+	}
+
+	return item, true, nil
+}
+
+func (s *pgAttachmentStorage) ListAttached(ctx context.Context, user *types.User, ids []int64) ([]int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAttachmentStorage.ListAttached")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select expense_id
+         from expense_attachments
+         where user_id = $1
+           and expense_id = any ($2)
+         order by expense_id`,
+		user, // $1
+		ids,  // $2
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select attached expenses")
+	}
+	defer rows.Close()
+
+	list := make([]int64, 0)
+
+	var id int64
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "scan selected attached expenses")
+		}
+
+		list = append(list, id)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected attached expenses")
+}
+
+func (s *pgAttachmentStorage) DeleteExpired(ctx context.Context, before time.Time) ([]string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgAttachmentStorage.DeleteExpired")
+	defer span.Finish()
+
+	var beforeBound *time.Time
+	if !before.IsZero() {
+		beforeBound = &before
+	}
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`delete
+         from expense_attachments
+         where expense_id is null
+            or created_at < $1
+           returning blob_key`,
+		beforeBound, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "delete expired attachments")
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+
+	var key string
+	for rows.Next() {
+		if err := rows.Scan(&key); err != nil {
+			return nil, errors.Wrap(err, "scan deleted attachments")
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, errors.Wrap(rows.Err(), "iterate deleted attachments")
+}
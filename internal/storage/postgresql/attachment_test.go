@@ -0,0 +1,78 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgAttachmentStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateAttachmentStorage()
+	e := _testFactory.CreateExpenseStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expense_attachments where user_id = $1`, int64(*_testUser102))
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where user_id = $1 and category = 'attachments'`, int64(*_testUser102))
+	})
+
+	expenseID, err := e.Add(_ctx, _testUser102, types.ExpenseItem{
+		Date:     time.Date(2022, 11, 18, 0, 0, 0, 0, time.UTC),
+		Amount:   1000000,
+		Currency: "RUB",
+	}, "attachments")
+	if err != nil {
+		t.Fatalf("cannot add test expense: %s", err.Error())
+	}
+
+	created := time.Date(2022, 11, 18, 12, 0, 0, 0, time.UTC)
+
+	t.Run("set to missing expense", func(t *testing.T) {
+		// ACT
+		attached, err := s.Set(_ctx, _testUser101, types.Attachment{ExpenseID: expenseID, Key: "a.jpg", Size: 1, Created: created})
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.False(t, attached)
+	})
+
+	t.Run("set and replace", func(t *testing.T) {
+		// ACT
+		attached1, err1 := s.Set(_ctx, _testUser102, types.Attachment{ExpenseID: expenseID, Key: "a.jpg", Size: 1, Created: created})
+		attached2, err2 := s.Set(_ctx, _testUser102, types.Attachment{ExpenseID: expenseID, Key: "b.jpg", Size: 2, Created: created})
+		item, found, getErr := s.Get(_ctx, _testUser102, expenseID)
+		list, listErr := s.ListAttached(_ctx, _testUser102, []int64{expenseID, expenseID + 1})
+
+		// ASSERT
+		assert.NoError(t, err1)
+		assert.True(t, attached1)
+		assert.NoError(t, err2)
+		assert.True(t, attached2)
+		assert.NoError(t, getErr)
+		assert.True(t, found)
+		assert.Equal(t, "b.jpg", item.Key)
+		assert.Equal(t, int64(2), item.Size)
+		assert.True(t, created.Equal(item.Created))
+		assert.NoError(t, listErr)
+		assert.Equal(t, []int64{expenseID}, list)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		// ACT
+		keysBefore, errBefore := s.DeleteExpired(_ctx, created)
+		keysAfter, errAfter := s.DeleteExpired(_ctx, created.Add(time.Second))
+		_, found, getErr := s.Get(_ctx, _testUser102, expenseID)
+
+		// ASSERT
+		assert.NoError(t, errBefore)
+		assert.NotContains(t, keysBefore, "b.jpg")
+		assert.NoError(t, errAfter)
+		assert.Contains(t, keysAfter, "b.jpg")
+		assert.NoError(t, getErr)
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,199 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgCategoryStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgCategoryStorage) List(ctx context.Context, user *types.User) ([]types.Category, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgCategoryStorage.List")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select c.category, coalesce(array_agg(a.alias order by a.alias) filter (where a.alias is not null), '{}')
+         from (select category from expenses where user_id = $1
+               union
+               select category from category_aliases where user_id = $1) c
+           left join category_aliases a on a.user_id = $1 and a.category = c.category
+         group by c.category
+         order by c.category`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select categories")
+	}
+	defer rows.Close()
+
+	list := make([]types.Category, 0)
+	for rows.Next() {
+		var item types.Category
+		if err := rows.Scan(&item.Name, &item.Aliases); err != nil {
+			return nil, errors.Wrap(err, "scan selected categories")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected categories")
+}
+
+func (s *pgCategoryStorage) Resolve(ctx context.Context, user *types.User, alias string) (string, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgCategoryStorage.Resolve")
+	defer span.Finish()
+
+	var category string
+
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select category
+         from category_aliases
+         where user_id = $1
+           and alias = $2`,
+		user,  // $1
+		alias, // $2
+	).Scan(&category)
+	if err == pgx.ErrNoRows {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, errors.Wrap(err, "select category alias")
+	}
+
+	return category, true, nil
+}
+
+func (s *pgCategoryStorage) SetAlias(ctx context.Context, user *types.User, alias, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgCategoryStorage.SetAlias")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into category_aliases (user_id, alias, category)
+         values ($1, $2, $3)
+         on conflict (user_id, alias) do update
+           set category = excluded.category`,
+		user,     // $1
+		alias,    // $2
+		category, // $3
+	)
+	if err != nil {
+		return errors.Wrap(err, "upsert category alias")
+	}
+
+	return nil
+}
+
+func (s *pgCategoryStorage) DeleteAlias(ctx context.Context, user *types.User, alias string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgCategoryStorage.DeleteAlias")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from category_aliases
+         where user_id = $1
+           and alias = $2`,
+		user,  // $1
+		alias, // $2
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "delete category alias")
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (s *pgCategoryStorage) Rename(ctx context.Context, user *types.User, from, to string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgCategoryStorage.Rename")
+	defer span.Finish()
+
+	// every query moves the category ($2) and its subcategories under the new name ($3)
+	queries := []struct {
+		name  string
+		query string
+	}{
+		{
+			name: "rename expenses category",
+			query: `update expenses
+                    set category = $3 || substr(category, length($2) + 1)
+                    where user_id = $1
+                      and (category = $2 or starts_with(category, $2 || '/'))`,
+		},
+		{
+			name: "rename recurring expenses category",
+			query: `update recurring_expenses
+                    set category = $3 || substr(category, length($2) + 1)
+                    where user_id = $1
+                      and (category = $2 or starts_with(category, $2 || '/'))`,
+		},
+		{
+			name: "delete merged limits",
+			query: `delete from limits l
+                    where l.user_id = $1
+                      and (l.category = $2 or starts_with(l.category, $2 || '/'))
+                      and exists(select
+                                 from limits t
+                                 where t.user_id = $1
+                                   and t.category = $3 || substr(l.category, length($2) + 1))`,
+		},
+		{
+			name: "rename limits category",
+			query: `update limits
+                    set category = $3 || substr(category, length($2) + 1)
+                    where user_id = $1
+                      and (category = $2 or starts_with(category, $2 || '/'))`,
+		},
+		{
+			name: "rename merchant rules category",
+			query: `update merchant_rules
+                    set category = $3 || substr(category, length($2) + 1)
+                    where user_id = $1
+                      and (category = $2 or starts_with(category, $2 || '/'))`,
+		},
+		{
+			name: "rename category aliases",
+			query: `update category_aliases
+                    set category = $3 || substr(category, length($2) + 1)
+                    where user_id = $1
+                      and (category = $2 or starts_with(category, $2 || '/'))`,
+		},
+	}
+
+	var renamed bool
+	for _, q := range queries {
+		tag, err := conn(ctx, s.pool).Exec(
+			ctx,
+			q.query,
+			user, // $1
+			from, // $2
+			to,   // $3
+		)
+		if err != nil {
+			return false, errors.Wrap(err, q.name)
+		}
+
+		renamed = renamed || tag.RowsAffected() != 0
+	}
+
+	// an alias of the renamed category may have become the name of the category itself
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from category_aliases
+         where user_id = $1
+           and alias = category`,
+		user, // $1
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "delete self aliases")
+	}
+
+	return renamed, nil
+}
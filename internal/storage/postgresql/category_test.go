@@ -0,0 +1,97 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgCategoryStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateCategoryStorage()
+	e := _testFactory.CreateExpenseStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from category_aliases where user_id = $1`, int64(*_testUser102))
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where user_id = $1 and category like 'rename/%'`, int64(*_testUser102))
+	})
+
+	_, err := e.Add(_ctx, _testUser102, types.ExpenseItem{
+		Date:     time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+		Amount:   1000000,
+		Currency: "RUB",
+	}, "rename/taxi/night")
+	if err != nil {
+		t.Fatalf("cannot add test expense: %s", err.Error())
+	}
+
+	t.Run("set alias", func(t *testing.T) {
+		// ACT
+		err1 := s.SetAlias(_ctx, _testUser102, "такси", "rename/bus")
+		err2 := s.SetAlias(_ctx, _testUser102, "такси", "rename/taxi")
+
+		// ASSERT
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		// ACT
+		category, found, err := s.Resolve(_ctx, _testUser102, "такси")
+		_, missing, missingErr := s.Resolve(_ctx, _testUser102, "автобус")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "rename/taxi", category)
+		assert.NoError(t, missingErr)
+		assert.False(t, missing)
+	})
+
+	t.Run("rename subtree", func(t *testing.T) {
+		// ACT
+		renamed, err := s.Rename(_ctx, _testUser102, "rename/taxi", "rename/transport/taxi")
+		category, _, resolveErr := s.Resolve(_ctx, _testUser102, "такси")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, renamed)
+		assert.NoError(t, resolveErr)
+		assert.Equal(t, "rename/transport/taxi", category)
+	})
+
+	t.Run("rename missing", func(t *testing.T) {
+		// ACT
+		renamed, err := s.Rename(_ctx, _testUser102, "rename/taxi", "rename/cab")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.False(t, renamed)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		// ACT
+		list, err := s.List(_ctx, _testUser102)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Contains(t, list, types.Category{Name: "rename/transport/taxi", Aliases: []string{"такси"}})
+		assert.Contains(t, list, types.Category{Name: "rename/transport/taxi/night", Aliases: []string{}})
+	})
+
+	t.Run("delete alias", func(t *testing.T) {
+		// ACT
+		ok, err := s.DeleteAlias(_ctx, _testUser102, "такси")
+		missing, missingErr := s.DeleteAlias(_ctx, _testUser102, "такси")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.NoError(t, missingErr)
+		assert.False(t, missing)
+	})
+}
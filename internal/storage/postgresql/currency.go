@@ -20,7 +20,7 @@ func (s *pgCurrencyStorage) Get(ctx context.Context, user *types.User) (string,
 
 	var value string
 
-	err := s.pool.QueryRow(
+	err := conn(ctx, s.pool).QueryRow(
 		ctx,
 		`select code
          from currencies
@@ -40,7 +40,7 @@ func (s *pgCurrencyStorage) Set(ctx context.Context, user *types.User, value str
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgCurrencyStorage.Set")
 	defer span.Finish()
 
-	_, err := s.pool.Exec(
+	_, err := conn(ctx, s.pool).Exec(
 		ctx,
 		`insert into currencies (user_id, code)
          values ($1, $2)
@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgDebtStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgDebtStorage) Add(ctx context.Context, debt types.Debt) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgDebtStorage.Add")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into debts (date, creditor_id, creditor_name, debtor_id, debtor_name, amount, currency_code, category)
+         values ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		debt.Date,         // $1
+		debt.Creditor,     // $2
+		debt.CreditorName, // $3
+		debt.Debtor,       // $4
+		debt.DebtorName,   // $5
+		debt.Amount,       // $6
+		debt.Currency,     // $7
+		debt.Category,     // $8
+	)
+	if err != nil {
+		return errors.Wrap(err, "insert debt")
+	}
+
+	return nil
+}
+
+func (s *pgDebtStorage) Balances(ctx context.Context, user *types.User) ([]types.DebtBalance, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgDebtStorage.Balances")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select counterparty_id, (array_agg(name order by id desc))[1], currency_code, sum(amount)::bigint
+         from (
+           select id, debtor_id as counterparty_id, debtor_name as name, currency_code, amount
+           from debts
+           where creditor_id = $1
+             and settled_at is null
+           union all
+           select id, creditor_id, creditor_name, currency_code, -amount
+           from debts
+           where debtor_id = $1
+             and settled_at is null
+         ) d
+         group by counterparty_id, currency_code
+         order by counterparty_id, currency_code`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select debt balances")
+	}
+	defer rows.Close()
+
+	var list []types.DebtBalance
+	for rows.Next() {
+		var (
+			counterparty int64
+			balance      types.DebtBalance
+		)
+		if err := rows.Scan(&counterparty, &balance.Name, &balance.Currency, &balance.Amount); err != nil {
+			return nil, errors.Wrap(err, "scan selected debt balances")
+		}
+
+		balance.Counterparty = (*types.User)(&counterparty)
+		list = append(list, balance)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected debt balances")
+}
+
+func (s *pgDebtStorage) Settle(ctx context.Context, user, counterparty *types.User, currency string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgDebtStorage.Settle")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`update debts
+         set settled_at = now()
+         where settled_at is null
+           and currency_code = $3
+           and ((creditor_id = $1 and debtor_id = $2) or (creditor_id = $2 and debtor_id = $1))`,
+		user,         // $1
+		counterparty, // $2
+		currency,     // $3
+	)
+	if err != nil {
+		return errors.Wrap(err, "settle debts")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgDebtStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateDebtStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from debts where creditor_id = $1 or debtor_id = $1`, _testUser101)
+	})
+
+	date := time.Date(2022, 11, 15, 0, 0, 0, 0, time.UTC)
+	debts := []types.Debt{
+		{Date: date, Creditor: _testUser101, CreditorName: "@alice", Debtor: _testUser102, DebtorName: "@bob", Amount: 15000000, Currency: "RUB", Category: "restaurant"},
+		{Date: date, Creditor: _testUser102, CreditorName: "@bob", Debtor: _testUser101, DebtorName: "@alice", Amount: 5000000, Currency: "RUB", Category: "taxi"},
+		{Date: date, Creditor: _testUser102, CreditorName: "@bobby", Debtor: _testUser101, DebtorName: "@alice", Amount: 200000, Currency: "USD", Category: "coffee"},
+	}
+
+	t.Run("add", func(t *testing.T) {
+		for _, debt := range debts {
+			// ACT
+			err := s.Add(_ctx, debt)
+
+			// ASSERT
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("balances", func(t *testing.T) {
+		// ACT
+		list, err := s.Balances(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.DebtBalance{
+			{Counterparty: _testUser102, Name: "@bob", Currency: "RUB", Amount: 10000000},
+			{Counterparty: _testUser102, Name: "@bobby", Currency: "USD", Amount: -200000},
+		}, list)
+	})
+
+	t.Run("settle", func(t *testing.T) {
+		// ACT
+		err := s.Settle(_ctx, _testUser102, _testUser101, "RUB")
+		list, listErr := s.Balances(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, listErr)
+		assert.Equal(t, []types.DebtBalance{
+			{Counterparty: _testUser102, Name: "@bobby", Currency: "USD", Amount: -200000},
+		}, list)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -14,39 +15,52 @@ type pgExpenseStorage struct {
 	pool *pgxpool.Pool
 }
 
-func (s *pgExpenseStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+func (s *pgExpenseStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) (int64, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.Add")
 	defer span.Finish()
 
-	_, err := s.pool.Exec(
+	var id int64
+
+	err := conn(ctx, s.pool).QueryRow(
 		ctx,
-		`insert into expenses (user_id, date, amount, currency_code, category)
-         values ($1, $2, $3, $4, $5)`,
+		`insert into expenses (user_id, date, amount, currency_code, category, account_id, note, tags)
+         values ($1, $2, $3, $4, $5, nullif($6, 0), $7, coalesce($8, '{}'))
+           returning id`,
 		user,          // $1
 		item.Date,     // $2
 		item.Amount,   // $3
 		item.Currency, // $4
 		category,      // $5
-	)
+		item.Account,  // $6
+		item.Note,     // $7
+		item.Tags,     // $8
+	).Scan(&id)
 	if err != nil {
-		return errors.Wrap(err, "insert expense")
+		return 0, errors.Wrap(err, "insert expense")
 	}
 
-	return nil
+	return id, nil
 }
 
-func (s *pgExpenseStorage) List(ctx context.Context, user *types.User, from time.Time) (map[string][]types.ExpenseItem, error) {
+func (s *pgExpenseStorage) List(ctx context.Context, user *types.User, from, to time.Time) (map[string][]types.ExpenseItem, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.List")
 	defer span.Finish()
 
-	rows, err := s.pool.Query(
+	var toBound *time.Time
+	if !to.IsZero() {
+		toBound = &to
+	}
+
+	rows, err := conn(ctx, s.pool).Query(
 		ctx,
-		`select category, date, amount, currency_code
+		`select id, category, date, amount, currency_code
          from expenses
          where user_id = $1
-           and date >= $2`,
-		user, // $1
-		from, // $2
+           and date >= $2
+           and ($3::date is null or date < $3)`,
+		user,    // $1
+		from,    // $2
+		toBound, // $3
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "select expenses")
@@ -55,17 +69,19 @@ func (s *pgExpenseStorage) List(ctx context.Context, user *types.User, from time
 	list := make(map[string][]types.ExpenseItem)
 
 	var (
+		id       int64
 		category string
 		date     time.Time
 		amount   int64
 		currency string
 	)
 	for rows.Next() {
-		if err := rows.Scan(&category, &date, &amount, &currency); err != nil {
+		if err := rows.Scan(&id, &category, &date, &amount, &currency); err != nil {
 			return nil, errors.Wrap(err, "scan selected expenses")
 		}
 
 		list[category] = append(list[category], types.ExpenseItem{
+			ID:       id,
 			Date:     date,
 			Amount:   amount,
 			Currency: currency,
@@ -74,3 +90,302 @@ func (s *pgExpenseStorage) List(ctx context.Context, user *types.User, from time
 
 	return list, nil
 }
+
+func (s *pgExpenseStorage) SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.SumByDate")
+	defer span.Finish()
+
+	var toBound *time.Time
+	if !to.IsZero() {
+		toBound = &to
+	}
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select date, category, currency_code, sum(amount)::bigint
+         from expenses
+         where user_id = $1
+           and date >= $2
+           and ($3::date is null or date < $3)
+         group by date, category, currency_code
+         order by date, category, currency_code`,
+		user,    // $1
+		from,    // $2
+		toBound, // $3
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select expense totals")
+	}
+
+	list := make([]types.ExpenseTotal, 0)
+	for rows.Next() {
+		var item types.ExpenseTotal
+		if err := rows.Scan(&item.Date, &item.Category, &item.Currency, &item.Amount); err != nil {
+			return nil, errors.Wrap(err, "scan selected expense totals")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, nil
+}
+
+func (s *pgExpenseStorage) Walk(ctx context.Context, user *types.User, from, to time.Time, fn func(types.Expense) error) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.Walk")
+	defer span.Finish()
+
+	var toBound *time.Time
+	if !to.IsZero() {
+		toBound = &to
+	}
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select id, category, date, amount, currency_code
+         from expenses
+         where user_id = $1
+           and date >= $2
+           and ($3::date is null or date < $3)
+         order by date, id`,
+		user,    // $1
+		from,    // $2
+		toBound, // $3
+	)
+	if err != nil {
+		return errors.Wrap(err, "select expenses")
+	}
+	defer rows.Close()
+
+	var item types.Expense
+	for rows.Next() {
+		if err := rows.Scan(&item.ID, &item.Category, &item.Date, &item.Amount, &item.Currency); err != nil {
+			return errors.Wrap(err, "scan selected expenses")
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(rows.Err(), "iterate selected expenses")
+}
+
+func (s *pgExpenseStorage) ListLast(ctx context.Context, user *types.User, count int) ([]types.Expense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.ListLast")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select id, category, date, amount, currency_code, note, nullif(tags, '{}')
+         from expenses
+         where user_id = $1
+         order by date desc, id desc
+         limit $2`,
+		user,  // $1
+		count, // $2
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select last expenses")
+	}
+
+	list := make([]types.Expense, 0, count)
+	for rows.Next() {
+		var item types.Expense
+		if err := rows.Scan(&item.ID, &item.Category, &item.Date, &item.Amount, &item.Currency, &item.Note, &item.Tags); err != nil {
+			return nil, errors.Wrap(err, "scan selected expenses")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, nil
+}
+
+func (s *pgExpenseStorage) AddImported(ctx context.Context, user *types.User, items []types.ImportedExpense) ([]types.Expense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.AddImported")
+	defer span.Finish()
+
+	var (
+		dates      = make([]time.Time, 0, len(items))
+		amounts    = make([]int64, 0, len(items))
+		currencies = make([]string, 0, len(items))
+		categories = make([]string, 0, len(items))
+		hashes     = make([]string, 0, len(items))
+		accounts   = make([]int64, 0, len(items))
+	)
+	for _, item := range items {
+		dates = append(dates, item.Date)
+		amounts = append(amounts, item.Amount)
+		currencies = append(currencies, item.Currency)
+		categories = append(categories, item.Category)
+		hashes = append(hashes, item.Hash)
+		accounts = append(accounts, item.Account)
+	}
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`insert into expenses (user_id, date, amount, currency_code, category, import_hash, account_id)
+         select $1, date, amount, currency_code, category, import_hash, nullif(account_id, 0)
+         from unnest($2::date[], $3::bigint[], $4::text[], $5::text[], $6::text[], $7::bigint[])
+           as imported (date, amount, currency_code, category, import_hash, account_id)
+         on conflict (user_id, import_hash) do nothing
+         returning id, category, date, amount, currency_code, coalesce(account_id, 0)`,
+		user,       // $1
+		dates,      // $2
+		amounts,    // $3
+		currencies, // $4
+		categories, // $5
+		hashes,     // $6
+		accounts,   // $7
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert imported expenses")
+	}
+	defer rows.Close()
+
+	list := make([]types.Expense, 0, len(items))
+
+	var item types.Expense
+	for rows.Next() {
+		if err := rows.Scan(&item.ID, &item.Category, &item.Date, &item.Amount, &item.Currency, &item.Account); err != nil {
+			return nil, errors.Wrap(err, "scan inserted expenses")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate inserted expenses")
+}
+
+func (s *pgExpenseStorage) Get(ctx context.Context, user *types.User, id int64) (types.Expense, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.Get")
+	defer span.Finish()
+
+	var item types.Expense
+
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select id, category, date, amount, currency_code, note, nullif(tags, '{}')
+         from expenses
+         where user_id = $1
+           and id = $2`,
+		user, // $1
+		id,   // $2
+	).Scan(&item.ID, &item.Category, &item.Date, &item.Amount, &item.Currency, &item.Note, &item.Tags)
+	if err == pgx.ErrNoRows {
+		return types.Expense{}, false, nil
+	} else if err != nil {
+		return types.Expense{}, false, errors.Wrap(err, "select expense")
+	}
+
+	return item, true, nil
+}
+
+func (s *pgExpenseStorage) Update(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.Update")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`update expenses
+         set date = $3,
+             amount = $4,
+             currency_code = $5,
+             category = $6
+         where user_id = $1
+           and id = $2`,
+		user,          // $1
+		item.ID,       // $2
+		item.Date,     // $3
+		item.Amount,   // $4
+		item.Currency, // $5
+		category,      // $6
+	)
+	if err != nil {
+		return errors.Wrap(err, "update expense")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("expense not found")
+	}
+
+	return nil
+}
+
+func (s *pgExpenseStorage) Delete(ctx context.Context, user *types.User, id int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.Delete")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from expenses
+         where user_id = $1
+           and id = $2`,
+		user, // $1
+		id,   // $2
+	)
+	if err != nil {
+		return errors.Wrap(err, "delete expense")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("expense not found")
+	}
+
+	return nil
+}
+
+func (s *pgExpenseStorage) Find(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseStorage.Find")
+	defer span.Finish()
+
+	var fromBound, toBound *time.Time
+	if !filter.From.IsZero() {
+		fromBound = &filter.From
+	}
+	if !filter.To.IsZero() {
+		toBound = &filter.To
+	}
+
+	// the expression matches the one of idx_expenses_search, subcategories are searched as separate words
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select id, category, date, amount, currency_code, note, nullif(tags, '{}')
+         from expenses
+         where user_id = $1
+           and ($2 = ''
+             or to_tsvector('simple', replace(category, '/', ' ') || ' ' || note) @@ plainto_tsquery('simple', $2)
+             or tags @> regexp_split_to_array(lower(trim($2)), '\s+'))
+           and ($3::date is null or date >= $3)
+           and ($4::date is null or date < $4)
+           and ($5 = 0 or amount >= $5)
+           and ($6 = 0 or amount <= $6)
+         order by date desc, id desc
+         offset $7
+         limit $8`,
+		user,         // $1
+		filter.Query, // $2
+		fromBound,    // $3
+		toBound,      // $4
+		filter.Min,   // $5
+		filter.Max,   // $6
+		offset,       // $7
+		limit,        // $8
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select found expenses")
+	}
+	defer rows.Close()
+
+	list := make([]types.Expense, 0, limit)
+	for rows.Next() {
+		var item types.Expense
+		if err := rows.Scan(&item.ID, &item.Category, &item.Date, &item.Amount, &item.Currency, &item.Note, &item.Tags); err != nil {
+			return nil, errors.Wrap(err, "scan found expenses")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate found expenses")
+}
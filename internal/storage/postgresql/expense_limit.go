@@ -18,17 +18,20 @@ func (s *pgExpenseLimitStorage) Get(ctx context.Context, user *types.User, categ
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Get")
 	defer span.Finish()
 
-	var (
-		total    int64
-		remains  int64
-		currency string
-	)
+	var item types.LimitItem
 
-	err := s.pool.QueryRow(
+	err := conn(ctx, s.pool).QueryRow(
 		ctx,
 		`select total,
                 remains,
-                currency_code
+                carried,
+                currency_code,
+                period,
+                period_days,
+                carry_over,
+                anchor,
+                notified,
+                overspent
          from limits
          where user_id = $1
            and category in ($2, '')
@@ -36,36 +39,44 @@ func (s *pgExpenseLimitStorage) Get(ctx context.Context, user *types.User, categ
          limit 1`,
 		user,     // $1
 		category, // $2
-	).Scan(&total, &remains, &currency)
+	).Scan(&item.Total, &item.Remains, &item.Carried, &item.Currency, &item.Period.Kind, &item.Period.Days, &item.Period.CarryOver, &item.Anchor, &item.Notified, &item.Overspent)
 	if err == pgx.ErrNoRows {
 		return types.LimitItem{}, false, nil
 	} else if err != nil {
 		return types.LimitItem{}, false, errors.Wrap(err, "select limit")
 	}
 
-	return types.LimitItem{
-		Total:    total,
-		Remains:  remains,
-		Currency: currency,
-	}, true, nil
+	return item, true, nil
 }
 
-func (s *pgExpenseLimitStorage) Set(ctx context.Context, user *types.User, total int64, currency, category string) error {
+func (s *pgExpenseLimitStorage) Set(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Set")
 	defer span.Finish()
 
-	_, err := s.pool.Exec(
+	_, err := conn(ctx, s.pool).Exec(
 		ctx,
-		`insert into limits (user_id, category, total, remains, currency_code)
-		 values ($1, $4, $2, $2, $3)
+		`insert into limits (user_id, category, total, remains, carried, currency_code, period, period_days, carry_over, anchor)
+		 values ($1, $4, $2, $2 + $9, $9, $3, $5, $6, $7, $8)
 		   on conflict (user_id, category)
 		     do update set total = excluded.total,
 		                   remains = excluded.remains,
-		                   currency_code = excluded.currency_code`,
-		user,     // $1
-		total,    // $2
-		currency, // $3
-		category, // $4
+		                   carried = excluded.carried,
+		                   currency_code = excluded.currency_code,
+		                   period = excluded.period,
+		                   period_days = excluded.period_days,
+		                   carry_over = excluded.carry_over,
+		                   anchor = excluded.anchor,
+		                   notified = 0,
+		                   overspent = 0`,
+		user,                  // $1
+		item.Total,            // $2
+		item.Currency,         // $3
+		category,              // $4
+		item.Period.Kind,      // $5
+		item.Period.Days,      // $6
+		item.Period.CarryOver, // $7
+		item.Anchor,           // $8
+		item.Carried,          // $9
 	)
 	if err != nil {
 		return errors.Wrap(err, "upsert limit")
@@ -74,13 +85,78 @@ func (s *pgExpenseLimitStorage) Set(ctx context.Context, user *types.User, total
 	return nil
 }
 
+func (s *pgExpenseLimitStorage) Renew(ctx context.Context, user *types.User, item types.LimitItem, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Renew")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`with "limit" as (
+           select user_id, category
+           from limits
+           where user_id = $1
+             and category in ($4, '')
+           order by category desc
+           limit 1
+         )
+         update limits set remains = $2,
+                           anchor = $3,
+                           carried = $5,
+                           notified = 0,
+                           overspent = 0
+           from "limit"
+           where limits.user_id = "limit".user_id
+             and limits.category = "limit".category
+             and limits.anchor < $3`,
+		user,         // $1
+		item.Remains, // $2
+		item.Anchor,  // $3
+		category,     // $4
+		item.Carried, // $5
+	)
+	if err != nil {
+		return errors.Wrap(err, "renew limit")
+	}
+
+	return nil
+}
+
+func (s *pgExpenseLimitStorage) Refresh(ctx context.Context, user *types.User, remains int64, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Refresh")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`with "limit" as (
+           select user_id, category
+           from limits
+           where user_id = $1
+             and category in ($3, '')
+           order by category desc
+           limit 1
+         )
+         update limits set remains = $2
+           from "limit"
+           where limits.user_id = "limit".user_id
+             and limits.category = "limit".category`,
+		user,     // $1
+		remains,  // $2
+		category, // $3
+	)
+	if err != nil {
+		return errors.Wrap(err, "refresh limit")
+	}
+
+	return nil
+}
+
 func (s *pgExpenseLimitStorage) Decrease(ctx context.Context, user *types.User, value int64, category string) (bool, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Decrease")
 	defer span.Finish()
 
 	var limitReached bool
 
-	err := s.pool.QueryRow(
+	err := conn(ctx, s.pool).QueryRow(
 		ctx,
 		`with "limit" as (
            select user_id, category
@@ -90,7 +166,8 @@ func (s *pgExpenseLimitStorage) Decrease(ctx context.Context, user *types.User,
            order by category desc
            limit 1
          )
-         update limits set remains = case when remains - $2 > 0 then remains - $2 else 0 end
+         update limits set remains = greatest(remains - $2, 0),
+                           overspent = overspent + greatest($2 - remains, 0)
            from "limit"
            where limits.user_id = "limit".user_id
              and limits.category = "limit".category
@@ -106,11 +183,41 @@ func (s *pgExpenseLimitStorage) Decrease(ctx context.Context, user *types.User,
 	return limitReached, nil
 }
 
+func (s *pgExpenseLimitStorage) Increase(ctx context.Context, user *types.User, value int64, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Increase")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`with "limit" as (
+           select user_id, category
+           from limits
+           where user_id = $1
+             and category in ($3, '')
+           order by category desc
+           limit 1
+         )
+         update limits set remains = least(remains + greatest($2 - overspent, 0), total + carried),
+                           overspent = greatest(overspent - $2, 0)
+           from "limit"
+           where limits.user_id = "limit".user_id
+             and limits.category = "limit".category`,
+		user,     // $1
+		value,    // $2
+		category, // $3
+	)
+	if err != nil {
+		return errors.Wrap(err, "increase limit")
+	}
+
+	return nil
+}
+
 func (s *pgExpenseLimitStorage) Unset(ctx context.Context, user *types.User, category string) error {
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Unset")
 	defer span.Finish()
 
-	_, err := s.pool.Exec(
+	_, err := conn(ctx, s.pool).Exec(
 		ctx,
 		`delete from limits
          where user_id = $1
@@ -129,12 +236,19 @@ func (s *pgExpenseLimitStorage) List(ctx context.Context, user *types.User) (map
 	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.List")
 	defer span.Finish()
 
-	rows, err := s.pool.Query(
+	rows, err := conn(ctx, s.pool).Query(
 		ctx,
 		`select category,
                 total,
                 remains,
-                currency_code
+                carried,
+                currency_code,
+                period,
+                period_days,
+                carry_over,
+                anchor,
+                notified,
+                overspent
          from limits
          where user_id = $1
          order by 1`,
@@ -148,24 +262,90 @@ func (s *pgExpenseLimitStorage) List(ctx context.Context, user *types.User) (map
 	found := false
 
 	var (
-		category     string
-		total        int64
-		remains      int64
-		currencyCode string
+		category string
+		item     types.LimitItem
 	)
 	for rows.Next() {
 		found = true
 
-		if err := rows.Scan(&category, &total, &remains, &currencyCode); err != nil {
+		if err := rows.Scan(&category, &item.Total, &item.Remains, &item.Carried, &item.Currency, &item.Period.Kind, &item.Period.Days, &item.Period.CarryOver, &item.Anchor, &item.Notified, &item.Overspent); err != nil {
 			return nil, true, errors.Wrap(err, "scan selected limits")
 		}
 
-		list[category] = types.LimitItem{
-			Total:    total,
-			Remains:  remains,
-			Currency: currencyCode,
-		}
+		list[category] = item
 	}
 
 	return list, found, nil
 }
+
+func (s *pgExpenseLimitStorage) Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.Notify")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`with "limit" as (
+           select user_id, category
+           from limits
+           where user_id = $1
+             and category in ($3, '')
+           order by category desc
+           limit 1
+         )
+         update limits set notified = $2
+           from "limit"
+           where limits.user_id = "limit".user_id
+             and limits.category = "limit".category
+             and limits.notified < $2`,
+		user,      // $1
+		threshold, // $2
+		category,  // $3
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "update limit notified threshold")
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *pgExpenseLimitStorage) GetAlerts(ctx context.Context, user *types.User) ([]int, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.GetAlerts")
+	defer span.Finish()
+
+	var thresholds []int
+
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select thresholds
+         from limit_alerts
+         where user_id = $1`,
+		user, // $1
+	).Scan(&thresholds)
+	if err == pgx.ErrNoRows {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, errors.Wrap(err, "select limit alerts")
+	}
+
+	return thresholds, true, nil
+}
+
+func (s *pgExpenseLimitStorage) SetAlerts(ctx context.Context, user *types.User, thresholds []int) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgExpenseLimitStorage.SetAlerts")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into limit_alerts (user_id, thresholds)
+         values ($1, $2)
+           on conflict (user_id)
+             do update set thresholds = excluded.thresholds`,
+		user,       // $1
+		thresholds, // $2
+	)
+	if err != nil {
+		return errors.Wrap(err, "upsert limit alerts")
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ package postgresql
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
@@ -34,7 +35,8 @@ func Test_pgExpenseLimitStorage_Get(t *testing.T) {
 			Total:    25000000,
 			Remains:  20000000,
 			Currency: "RUB",
-		}, limit)
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
 	})
 
 	t.Run("category limit", func(t *testing.T) {
@@ -48,7 +50,8 @@ func Test_pgExpenseLimitStorage_Get(t *testing.T) {
 			Total:    1000000,
 			Remains:  700000,
 			Currency: "USD",
-		}, limit)
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
 	})
 }
 
@@ -58,7 +61,13 @@ func Test_pgExpenseLimitStorage_Set(t *testing.T) {
 
 	t.Run("insert", func(t *testing.T) {
 		// ACT
-		setErr := s.Set(_ctx, _testUser102, 100000, "EUR", "tea")
+		setErr := s.Set(_ctx, _testUser102, types.LimitItem{
+			Total:    100000,
+			Remains:  100000,
+			Currency: "EUR",
+			Period:   types.LimitPeriod{Kind: types.PeriodCustom, Days: 10, CarryOver: true},
+			Anchor:   time.Date(2022, 10, 15, 0, 0, 0, 0, time.UTC),
+		}, "tea")
 		limit, ok, getErr := s.Get(_ctx, _testUser102, "tea")
 
 		// ASSERT
@@ -69,6 +78,8 @@ func Test_pgExpenseLimitStorage_Set(t *testing.T) {
 			Total:    100000,
 			Remains:  100000,
 			Currency: "EUR",
+			Period:   types.LimitPeriod{Kind: types.PeriodCustom, Days: 10, CarryOver: true},
+			Anchor:   time.Date(2022, 10, 15, 0, 0, 0, 0, time.UTC),
 		}, limit)
 
 		// CLEANUP
@@ -85,7 +96,13 @@ func Test_pgExpenseLimitStorage_Set(t *testing.T) {
 
 	t.Run("update", func(t *testing.T) {
 		// ACT
-		setErr := s.Set(_ctx, _testUser101, 12000000, "USD", "taxi")
+		setErr := s.Set(_ctx, _testUser101, types.LimitItem{
+			Total:    12000000,
+			Remains:  12000000,
+			Currency: "USD",
+			Period:   types.LimitPeriod{Kind: types.PeriodWeek},
+			Anchor:   time.Date(2022, 10, 17, 0, 0, 0, 0, time.UTC),
+		}, "taxi")
 		limit, ok, getErr := s.Get(_ctx, _testUser101, "taxi")
 
 		// ASSERT
@@ -96,6 +113,8 @@ func Test_pgExpenseLimitStorage_Set(t *testing.T) {
 			Total:    12000000,
 			Remains:  12000000,
 			Currency: "USD",
+			Period:   types.LimitPeriod{Kind: types.PeriodWeek},
+			Anchor:   time.Date(2022, 10, 17, 0, 0, 0, 0, time.UTC),
 		}, limit)
 
 		// CLEANUP
@@ -104,7 +123,9 @@ func Test_pgExpenseLimitStorage_Set(t *testing.T) {
 				_ctx,
 				`update limits
                  set total = 1000000,
-                     remains = 700000
+                     remains = 700000,
+                     period = 'month',
+                     anchor = date_trunc('month', current_date)
                  where user_id = $1
                    and category = 'taxi'`,
 				int64(*_testUser101),
@@ -131,7 +152,8 @@ func Test_pgExpenseLimitStorage_Decrease(t *testing.T) {
 			Total:    1000000,
 			Remains:  50000,
 			Currency: "USD",
-		}, limit)
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
 
 		// CLEANUP
 		t.Cleanup(func() {
@@ -139,7 +161,9 @@ func Test_pgExpenseLimitStorage_Decrease(t *testing.T) {
 				_ctx,
 				`update limits
                  set total = 1000000,
-                     remains = 700000
+                     remains = 700000,
+                     period = 'month',
+                     anchor = date_trunc('month', current_date)
                  where user_id = $1
                    and category = 'taxi'`,
 				int64(*_testUser101),
@@ -157,11 +181,85 @@ func Test_pgExpenseLimitStorage_Decrease(t *testing.T) {
 		assert.NoError(t, getErr)
 		assert.True(t, reached)
 		assert.True(t, ok)
+		assert.Equal(t, types.LimitItem{
+			Total:     1000000,
+			Remains:   0,
+			Currency:  "USD",
+			Period:    types.LimitPeriod{Kind: types.PeriodMonth},
+			Overspent: 50000,
+		}, withoutAnchor(limit))
+
+		// CLEANUP
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(
+				_ctx,
+				`update limits
+                 set total = 1000000,
+                     remains = 700000,
+                     overspent = 0,
+                     period = 'month',
+                     anchor = date_trunc('month', current_date)
+                 where user_id = $1
+                   and category = 'taxi'`,
+				int64(*_testUser101),
+			)
+		})
+	})
+}
+
+func Test_pgExpenseLimitStorage_Increase(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseLimitStorage()
+
+	t.Run("partially", func(t *testing.T) {
+		// ACT
+		increaseErr := s.Increase(_ctx, _testUser101, 200000, "taxi")
+		limit, ok, getErr := s.Get(_ctx, _testUser101, "taxi")
+
+		// ASSERT
+		assert.NoError(t, increaseErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
 		assert.Equal(t, types.LimitItem{
 			Total:    1000000,
-			Remains:  0,
+			Remains:  900000,
 			Currency: "USD",
-		}, limit)
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
+
+		// CLEANUP
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(
+				_ctx,
+				`update limits
+                 set total = 1000000,
+                     remains = 700000,
+                     period = 'month',
+                     anchor = date_trunc('month', current_date)
+                 where user_id = $1
+                   and category = 'taxi'`,
+				int64(*_testUser101),
+			)
+		})
+	})
+
+	t.Run("overspent first", func(t *testing.T) {
+		// ACT
+		_, decreaseErr := s.Decrease(_ctx, _testUser101, 1200000, "taxi")
+		increaseErr := s.Increase(_ctx, _testUser101, 1200000, "taxi")
+		limit, ok, getErr := s.Get(_ctx, _testUser101, "taxi")
+
+		// ASSERT
+		assert.NoError(t, decreaseErr)
+		assert.NoError(t, increaseErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
+		assert.Equal(t, types.LimitItem{
+			Total:    1000000,
+			Remains:  700000,
+			Currency: "USD",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
 
 		// CLEANUP
 		t.Cleanup(func() {
@@ -169,13 +267,46 @@ func Test_pgExpenseLimitStorage_Decrease(t *testing.T) {
 				_ctx,
 				`update limits
                  set total = 1000000,
-                     remains = 700000
+                     remains = 700000,
+                     overspent = 0,
+                     period = 'month',
+                     anchor = date_trunc('month', current_date)
                  where user_id = $1
                    and category = 'taxi'`,
 				int64(*_testUser101),
 			)
 		})
 	})
+
+	t.Run("up to total", func(t *testing.T) {
+		// ACT
+		increaseErr := s.Increase(_ctx, _testUser101, 50000000, "tea")
+		limit, ok, getErr := s.Get(_ctx, _testUser101, "tea")
+
+		// ASSERT
+		assert.NoError(t, increaseErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
+		assert.Equal(t, types.LimitItem{
+			Total:    25000000,
+			Remains:  25000000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
+
+		// CLEANUP
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(
+				_ctx,
+				`update limits
+                 set total = 25000000,
+                     remains = 20000000
+                 where user_id = $1
+                   and category = ''`,
+				int64(*_testUser101),
+			)
+		})
+	})
 }
 
 func Test_pgExpenseLimitStorage_Unset(t *testing.T) {
@@ -195,7 +326,8 @@ func Test_pgExpenseLimitStorage_Unset(t *testing.T) {
 			Total:    25000000,
 			Remains:  20000000,
 			Currency: "RUB",
-		}, limit)
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
 
 		// CLEANUP
 		t.Cleanup(func() {
@@ -235,12 +367,191 @@ func Test_pgExpenseLimitStorage_List(t *testing.T) {
 				Total:    25000000,
 				Remains:  20000000,
 				Currency: "RUB",
+				Period:   types.LimitPeriod{Kind: types.PeriodMonth},
 			},
 			"taxi": {
 				Total:    1000000,
 				Remains:  700000,
 				Currency: "USD",
+				Period:   types.LimitPeriod{Kind: types.PeriodMonth},
 			},
-		}, list)
+		}, withoutAnchors(list))
 	})
 }
+
+func Test_pgExpenseLimitStorage_Renew(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseLimitStorage()
+
+	limit, _, _ := s.Get(_ctx, _testUser101, "taxi")
+	renewed := types.LimitItem{
+		Total:    1000000,
+		Remains:  1000000,
+		Currency: "USD",
+		Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		Anchor:   limit.Anchor.AddDate(0, 1, 0),
+	}
+
+	t.Run("new period", func(t *testing.T) {
+		// ACT
+		renewErr := s.Renew(_ctx, _testUser101, renewed, "taxi")
+		limit, ok, getErr := s.Get(_ctx, _testUser101, "taxi")
+
+		// ASSERT
+		assert.NoError(t, renewErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
+		assert.Equal(t, renewed, limit)
+	})
+
+	t.Run("already renewed", func(t *testing.T) {
+		// ACT
+		renewErr := s.Renew(_ctx, _testUser101, types.LimitItem{
+			Total:    1000000,
+			Remains:  500000,
+			Currency: "USD",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:   renewed.Anchor,
+		}, "taxi")
+		limit, ok, getErr := s.Get(_ctx, _testUser101, "taxi")
+
+		// ASSERT
+		assert.NoError(t, renewErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
+		assert.Equal(t, renewed, limit)
+	})
+
+	// CLEANUP
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(
+			_ctx,
+			`update limits
+             set remains = 700000,
+                 anchor = date_trunc('month', current_date)
+             where user_id = $1
+               and category = 'taxi'`,
+			int64(*_testUser101),
+		)
+	})
+}
+
+func Test_pgExpenseLimitStorage_Refresh(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseLimitStorage()
+
+	t.Run("common limit", func(t *testing.T) {
+		// ACT
+		refreshErr := s.Refresh(_ctx, _testUser101, 1500000, "coffee")
+		limit, ok, getErr := s.Get(_ctx, _testUser101, "coffee")
+
+		// ASSERT
+		assert.NoError(t, refreshErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
+		assert.Equal(t, types.LimitItem{
+			Total:    25000000,
+			Remains:  1500000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+		}, withoutAnchor(limit))
+
+		// CLEANUP
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(
+				_ctx,
+				`update limits
+                 set remains = 20000000
+                 where user_id = $1
+                   and category = ''`,
+				int64(*_testUser101),
+			)
+		})
+	})
+}
+
+func Test_pgExpenseLimitStorage_Notify(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseLimitStorage()
+
+	t.Run("first time", func(t *testing.T) {
+		// ACT
+		notified, err := s.Notify(_ctx, _testUser101, 80, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, notified)
+	})
+
+	t.Run("lower threshold", func(t *testing.T) {
+		// ACT
+		notified, err := s.Notify(_ctx, _testUser101, 50, "taxi")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.False(t, notified)
+	})
+
+	// CLEANUP
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(
+			_ctx,
+			`update limits
+             set notified = 0
+             where user_id = $1`,
+			int64(*_testUser101),
+		)
+	})
+}
+
+func Test_pgExpenseLimitStorage_GetAlerts_SetAlerts(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseLimitStorage()
+
+	t.Run("not found", func(t *testing.T) {
+		// ACT
+		thresholds, ok, err := s.GetAlerts(_ctx, _testUser102)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, thresholds)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		// ACT
+		setErr := s.SetAlerts(_ctx, _testUser102, []int{30, 90})
+		thresholds, ok, getErr := s.GetAlerts(_ctx, _testUser102)
+
+		// ASSERT
+		assert.NoError(t, setErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
+		assert.Equal(t, []int{30, 90}, thresholds)
+	})
+
+	// CLEANUP
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(
+			_ctx,
+			`delete
+             from limit_alerts
+             where user_id = $1`,
+			int64(*_testUser102),
+		)
+	})
+}
+
+func withoutAnchor(limit types.LimitItem) types.LimitItem {
+	limit.Anchor = time.Time{}
+
+	return limit
+}
+
+func withoutAnchors(list map[string]types.LimitItem) map[string]types.LimitItem {
+	for category := range list {
+		list[category] = withoutAnchor(list[category])
+	}
+
+	return list
+}
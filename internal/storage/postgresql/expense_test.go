@@ -16,7 +16,7 @@ func Test_pgExpenseStorage_Add_List(t *testing.T) {
 
 	t.Run("empty list", func(t *testing.T) {
 		// ACT
-		list, err := s.List(_ctx, _testUser101, time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC))
+		list, err := s.List(_ctx, _testUser101, time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC), time.Time{})
 
 		// ASSERT
 		assert.NoError(t, err)
@@ -25,19 +25,21 @@ func Test_pgExpenseStorage_Add_List(t *testing.T) {
 
 	t.Run("add with check", func(t *testing.T) {
 		// ACT
-		listBefore, listBeforeErr := s.List(_ctx, _testUser101, time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC))
-		addErr := s.Add(_ctx, _testUser101, types.ExpenseItem{
+		listBefore, listBeforeErr := s.List(_ctx, _testUser101, time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC), time.Time{})
+		id, addErr := s.Add(_ctx, _testUser101, types.ExpenseItem{
 			Date:     time.Date(2022, 10, 23, 0, 0, 0, 0, time.UTC),
 			Amount:   1200000,
 			Currency: "RUB",
 		}, "coffee")
-		listAfter, listAfterErr := s.List(_ctx, _testUser101, time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC))
+		listAfter, listAfterErr := s.List(_ctx, _testUser101, time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC), time.Time{})
 
 		// ASSERT
 		assert.NoError(t, listBeforeErr)
 		assert.NoError(t, addErr)
+		assert.NotZero(t, id)
 		assert.NoError(t, listAfterErr)
 
+		listBefore, listAfter = withoutIDs(listBefore), withoutIDs(listAfter)
 		assert.Equal(t, map[string][]types.ExpenseItem{
 			"taxi": {
 				{
@@ -91,3 +93,399 @@ func Test_pgExpenseStorage_Add_List(t *testing.T) {
 		})
 	})
 }
+
+func Test_pgExpenseStorage_List_Range(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseStorage()
+
+	t.Run("bounded", func(t *testing.T) {
+		// ACT
+		list, err := s.List(_ctx, _testUser102, time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC))
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]types.ExpenseItem{
+			"coffee": {
+				{
+					Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+					Amount:   1575000,
+					Currency: "RUB",
+				},
+			},
+		}, withoutIDs(list))
+	})
+}
+
+func Test_pgExpenseStorage_SumByDate(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseStorage()
+
+	_, err := _testFactory.pool.Exec(
+		_ctx,
+		`insert into expenses (user_id, date, amount, currency_code, category)
+         values ($1, '2022-10-20', 25000, 'RUB', 'coffee')`,
+		int64(*_testUser102),
+	)
+	if err != nil {
+		t.Fatalf("cannot insert test expense: %s", err.Error())
+	}
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where user_id = $1 and amount = 25000`, int64(*_testUser102))
+	})
+
+	// ACT
+	list, err := s.SumByDate(_ctx, _testUser102, time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), time.Time{})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ExpenseTotal{
+		{
+			Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+			Category: "coffee",
+			Currency: "RUB",
+			Amount:   1600000,
+		},
+		{
+			Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+			Category: "coffee",
+			Currency: "USD",
+			Amount:   30000,
+		},
+	}, list)
+}
+
+func Test_pgExpenseStorage_Walk(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseStorage()
+
+	t.Run("chronological", func(t *testing.T) {
+		// ARRANGE
+		var list []types.Expense
+
+		// ACT
+		err := s.Walk(_ctx, _testUser102, time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), time.Time{}, func(item types.Expense) error {
+			item.ID = 0
+			list = append(list, item)
+			return nil
+		})
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Expense{
+			{
+				ExpenseItem: types.ExpenseItem{Date: time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), Amount: 1575000, Currency: "RUB"},
+				Category:    "coffee",
+			},
+			{
+				ExpenseItem: types.ExpenseItem{Date: time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC), Amount: 30000, Currency: "USD"},
+				Category:    "coffee",
+			},
+		}, list)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		// ARRANGE
+		var count int
+
+		// ACT
+		err := s.Walk(_ctx, _testUser102, time.Time{}, time.Time{}, func(item types.Expense) error {
+			count++
+			return assert.AnError
+		})
+
+		// ASSERT
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func Test_pgExpenseStorage_AddImported(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseStorage()
+
+	imported := func(day int, amount int64, hash string) types.ImportedExpense {
+		return types.ImportedExpense{
+			Expense: types.Expense{
+				ExpenseItem: types.ExpenseItem{Date: time.Date(2022, 10, day, 0, 0, 0, 0, time.UTC), Amount: amount, Currency: "RUB"},
+				Category:    "imported",
+			},
+			Hash: hash,
+		}
+	}
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where user_id = $1 and category = 'imported'`, int64(*_testUser101))
+	})
+
+	t.Run("new", func(t *testing.T) {
+		// ACT
+		list, err := s.AddImported(_ctx, _testUser101, []types.ImportedExpense{
+			imported(10, 10000, "test-hash-1"),
+			imported(11, 20000, "test-hash-2"),
+		})
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+	})
+
+	t.Run("duplicates skipped", func(t *testing.T) {
+		// ACT
+		list, err := s.AddImported(_ctx, _testUser101, []types.ImportedExpense{
+			imported(11, 20000, "test-hash-2"),
+			imported(12, 30000, "test-hash-3"),
+		})
+
+		// ASSERT
+		assert.NoError(t, err)
+		if assert.Len(t, list, 1) {
+			assert.NotZero(t, list[0].ID)
+			list[0].ID = 0
+			assert.Equal(t, imported(12, 30000, "").Expense, list[0])
+		}
+	})
+
+	t.Run("another user", func(t *testing.T) {
+		// ARRANGE
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where user_id = $1 and category = 'imported'`, int64(*_testUser102))
+		})
+
+		// ACT
+		list, err := s.AddImported(_ctx, _testUser102, []types.ImportedExpense{
+			imported(11, 20000, "test-hash-2"),
+		})
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("with account", func(t *testing.T) {
+		// ARRANGE
+		accounts := _testFactory.CreateAccountStorage()
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(_ctx, `delete from accounts where user_id = $1 and name = 'imported'`, int64(*_testUser101))
+		})
+
+		_, err := accounts.Add(_ctx, _testUser101, types.Account{Name: "imported", Currency: "RUB"})
+		assert.NoError(t, err)
+		account, _, err := accounts.GetByName(_ctx, _testUser101, "imported")
+		assert.NoError(t, err)
+
+		item := imported(13, 40000, "test-hash-4")
+		item.Account = account.ID
+
+		// ACT
+		list, err := s.AddImported(_ctx, _testUser101, []types.ImportedExpense{item})
+
+		// ASSERT
+		assert.NoError(t, err)
+		if assert.Len(t, list, 1) {
+			assert.Equal(t, account.ID, list[0].Account)
+
+			var stored int64
+			err = _testFactory.pool.QueryRow(_ctx, `select account_id from expenses where id = $1`, list[0].ID).Scan(&stored)
+			assert.NoError(t, err)
+			assert.Equal(t, account.ID, stored)
+		}
+	})
+}
+
+func Test_pgExpenseStorage_ListLast(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseStorage()
+
+	t.Run("last two", func(t *testing.T) {
+		// ACT
+		list, err := s.ListLast(_ctx, _testUser102, 2)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+		assert.Equal(t, time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC), list[0].Date)
+		assert.Equal(t, int64(30000), list[0].Amount)
+		assert.Equal(t, "USD", list[0].Currency)
+		assert.Equal(t, "coffee", list[0].Category)
+		assert.Equal(t, time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC), list[1].Date)
+	})
+}
+
+func Test_pgExpenseStorage_Get_Update_Delete(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseStorage()
+
+	var id int64
+	err := _testFactory.pool.QueryRow(
+		_ctx,
+		`insert into expenses (user_id, date, amount, currency_code, category)
+         values ($1, '2022-10-22', 500000, 'RUB', 'books')
+           returning id`,
+		int64(*_testUser101),
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("cannot insert test expense: %s", err.Error())
+	}
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where id = $1`, id)
+	})
+
+	t.Run("get foreign", func(t *testing.T) {
+		// ACT
+		item, ok, err := s.Get(_ctx, _testUser102, id)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, item)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		// ACT
+		item, ok, err := s.Get(_ctx, _testUser101, id)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, types.Expense{
+			ExpenseItem: types.ExpenseItem{
+				ID:       id,
+				Date:     time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC),
+				Amount:   500000,
+				Currency: "RUB",
+			},
+			Category: "books",
+		}, item)
+	})
+
+	t.Run("update foreign", func(t *testing.T) {
+		// ACT
+		err := s.Update(_ctx, _testUser102, types.ExpenseItem{
+			ID:       id,
+			Date:     time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC),
+			Amount:   1,
+			Currency: "RUB",
+		}, "books")
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		// ACT
+		updateErr := s.Update(_ctx, _testUser101, types.ExpenseItem{
+			ID:       id,
+			Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+			Amount:   700000,
+			Currency: "RUB",
+		}, "magazines")
+		item, ok, getErr := s.Get(_ctx, _testUser101, id)
+
+		// ASSERT
+		assert.NoError(t, updateErr)
+		assert.NoError(t, getErr)
+		assert.True(t, ok)
+		assert.Equal(t, types.Expense{
+			ExpenseItem: types.ExpenseItem{
+				ID:       id,
+				Date:     time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+				Amount:   700000,
+				Currency: "RUB",
+			},
+			Category: "magazines",
+		}, item)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// ACT
+		deleteErr := s.Delete(_ctx, _testUser101, id)
+		_, ok, getErr := s.Get(_ctx, _testUser101, id)
+		secondDeleteErr := s.Delete(_ctx, _testUser101, id)
+
+		// ASSERT
+		assert.NoError(t, deleteErr)
+		assert.NoError(t, getErr)
+		assert.False(t, ok)
+		assert.Error(t, secondDeleteErr)
+	})
+}
+
+func withoutIDs(list map[string][]types.ExpenseItem) map[string][]types.ExpenseItem {
+	for category := range list {
+		for i := range list[category] {
+			list[category][i].ID = 0
+		}
+	}
+
+	return list
+}
+
+func Test_pgExpenseStorage_Find(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateExpenseStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from expenses where user_id = $1 and category like 'search/%'`, int64(*_testUser101))
+	})
+
+	for _, item := range []struct {
+		date     time.Time
+		amount   int64
+		category string
+		note     string
+		tags     []string
+	}{
+		{time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), 4500000, "search/coffee", "with client", []string{"work"}},
+		{time.Date(2022, 11, 2, 0, 0, 0, 0, time.UTC), 3000000, "search/coffee", "", nil},
+		{time.Date(2022, 11, 3, 0, 0, 0, 0, time.UTC), 9000000, "search/restaurant", "birthday", []string{"work", "party"}},
+	} {
+		_, err := s.Add(_ctx, _testUser101, types.ExpenseItem{
+			Date:     item.date,
+			Amount:   item.amount,
+			Currency: "RUB",
+			Note:     item.note,
+			Tags:     item.tags,
+		}, item.category)
+		if err != nil {
+			t.Fatalf("cannot add test expense: %s", err.Error())
+		}
+	}
+
+	t.Run("by category", func(t *testing.T) {
+		// ACT
+		list, err := s.Find(_ctx, _testUser101, types.ExpenseFilter{Query: "coffee"}, 0, 10)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+		assert.Equal(t, time.Date(2022, 11, 2, 0, 0, 0, 0, time.UTC), list[0].Date)
+		assert.Equal(t, "with client", list[1].Note)
+		assert.Equal(t, []string{"work"}, list[1].Tags)
+	})
+
+	t.Run("by tag and amount", func(t *testing.T) {
+		// ACT
+		list, err := s.Find(_ctx, _testUser101, types.ExpenseFilter{Query: "work", Min: 5000000}, 0, 10)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, "search/restaurant", list[0].Category)
+	})
+
+	t.Run("by note within dates paged", func(t *testing.T) {
+		// ACT
+		list, err := s.Find(_ctx, _testUser101, types.ExpenseFilter{
+			Query: "client",
+			From:  time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+			To:    time.Date(2022, 11, 2, 0, 0, 0, 0, time.UTC),
+		}, 0, 1)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, int64(4500000), list[0].Amount)
+	})
+}
@@ -90,6 +90,36 @@ func (f *factory) CreateExpenseStorage() storage.ExpenseStorage {
 	}
 }
 
+func (f *factory) CreateIncomeStorage() storage.IncomeStorage {
+	return &pgIncomeStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateAccountStorage() storage.AccountStorage {
+	return &pgAccountStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateGoalStorage() storage.GoalStorage {
+	return &pgGoalStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateGroupStorage() storage.GroupStorage {
+	return &pgGroupStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateDebtStorage() storage.DebtStorage {
+	return &pgDebtStorage{
+		pool: f.pool,
+	}
+}
+
 func (f *factory) CreateCurrencyStorage() storage.CurrencyStorage {
 	return &pgCurrencyStorage{
 		pool: f.pool,
@@ -107,3 +137,45 @@ func (f *factory) CreateExpenseLimitStorage() storage.ExpenseLimitStorage {
 		pool: f.pool,
 	}
 }
+
+func (f *factory) CreateCategoryStorage() storage.CategoryStorage {
+	return &pgCategoryStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateAttachmentStorage() storage.AttachmentStorage {
+	return &pgAttachmentStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateMerchantRuleStorage() storage.MerchantRuleStorage {
+	return &pgMerchantRuleStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateRecurringExpenseStorage() storage.RecurringExpenseStorage {
+	return &pgRecurringExpenseStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateJournalStorage() storage.JournalStorage {
+	return &pgJournalStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateOutboxStorage() storage.OutboxStorage {
+	return &pgOutboxStorage{
+		pool: f.pool,
+	}
+}
+
+func (f *factory) CreateUnitOfWork() storage.UnitOfWork {
+	return &pgUnitOfWork{
+		pool: f.pool,
+	}
+}
@@ -0,0 +1,186 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgGoalStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgGoalStorage) Add(ctx context.Context, user *types.User, goal types.Goal) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGoalStorage.Add")
+	defer span.Finish()
+
+	var deadline *time.Time
+	if !goal.Deadline.IsZero() {
+		deadline = &goal.Deadline
+	}
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into goals (user_id, name, target, currency_code, deadline)
+         values ($1, $2, $3, $4, $5)
+         on conflict (user_id, name) do nothing`,
+		user,          // $1
+		goal.Name,     // $2
+		goal.Target,   // $3
+		goal.Currency, // $4
+		deadline,      // $5
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "insert goal")
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (s *pgGoalStorage) List(ctx context.Context, user *types.User) ([]types.Goal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGoalStorage.List")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select id, name, target, currency_code, deadline
+         from goals
+         where user_id = $1
+         order by deadline nulls last, name`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select goals")
+	}
+	defer rows.Close()
+
+	list := make([]types.Goal, 0)
+	for rows.Next() {
+		var (
+			item     types.Goal
+			deadline *time.Time
+		)
+		if err := rows.Scan(&item.ID, &item.Name, &item.Target, &item.Currency, &deadline); err != nil {
+			return nil, errors.Wrap(err, "scan selected goals")
+		}
+
+		if deadline != nil {
+			item.Deadline = *deadline
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected goals")
+}
+
+func (s *pgGoalStorage) GetByName(ctx context.Context, user *types.User, name string) (types.Goal, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGoalStorage.GetByName")
+	defer span.Finish()
+
+	var (
+		item     types.Goal
+		deadline *time.Time
+	)
+
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select id, name, target, currency_code, deadline
+         from goals
+         where user_id = $1
+           and name = $2`,
+		user, // $1
+		name, // $2
+	).Scan(&item.ID, &item.Name, &item.Target, &item.Currency, &deadline)
+	if err == pgx.ErrNoRows {
+		return types.Goal{}, false, nil
+	} else if err != nil {
+		return types.Goal{}, false, errors.Wrap(err, "select goal")
+	}
+
+	if deadline != nil {
+		item.Deadline = *deadline
+	}
+
+	return item, true, nil
+}
+
+func (s *pgGoalStorage) Delete(ctx context.Context, user *types.User, id int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGoalStorage.Delete")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from goals
+         where user_id = $1
+           and id = $2`,
+		user, // $1
+		id,   // $2
+	)
+	if err != nil {
+		return errors.Wrap(err, "delete goal")
+	}
+
+	return nil
+}
+
+func (s *pgGoalStorage) AddContribution(ctx context.Context, user *types.User, contribution types.GoalContribution) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGoalStorage.AddContribution")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into goal_contributions (goal_id, date, amount, currency_code)
+         select id, $3, $4, $5
+         from goals
+         where user_id = $1
+           and id = $2`,
+		user,                  // $1
+		contribution.Goal,     // $2
+		contribution.Date,     // $3
+		contribution.Amount,   // $4
+		contribution.Currency, // $5
+	)
+	if err != nil {
+		return errors.Wrap(err, "insert goal contribution")
+	}
+
+	return nil
+}
+
+func (s *pgGoalStorage) Totals(ctx context.Context, user *types.User) ([]types.GoalTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGoalStorage.Totals")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select c.goal_id, c.currency_code, sum(c.amount)::bigint
+         from goal_contributions c
+           join goals g on g.id = c.goal_id
+         where g.user_id = $1
+         group by c.goal_id, c.currency_code
+         order by c.goal_id, c.currency_code`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select goal totals")
+	}
+	defer rows.Close()
+
+	var list []types.GoalTotal
+	for rows.Next() {
+		var item types.GoalTotal
+		if err := rows.Scan(&item.Goal, &item.Currency, &item.Amount); err != nil {
+			return nil, errors.Wrap(err, "scan selected goal totals")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected goal totals")
+}
@@ -0,0 +1,83 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgGoalStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateGoalStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from goals where user_id = $1`, _testUser101)
+	})
+
+	vacation := types.Goal{Name: "vacation", Target: 2000000000, Currency: "RUB", Deadline: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}
+	laptop := types.Goal{Name: "laptop", Target: 15000000, Currency: "USD"}
+
+	t.Run("add", func(t *testing.T) {
+		// ACT
+		added, err := s.Add(_ctx, _testUser101, vacation)
+		_, laptopErr := s.Add(_ctx, _testUser101, laptop)
+		duplicate, duplicateErr := s.Add(_ctx, _testUser101, vacation)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, added)
+		assert.NoError(t, laptopErr)
+		assert.NoError(t, duplicateErr)
+		assert.False(t, duplicate)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		// ACT
+		list, err := s.List(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			vacation.ID, laptop.ID = list[0].ID, list[1].ID
+			assert.Equal(t, []types.Goal{vacation, laptop}, list)
+		}
+	})
+
+	t.Run("totals", func(t *testing.T) {
+		// ARRANGE
+		for _, contribution := range []types.GoalContribution{
+			{Goal: vacation.ID, Date: vacation.Deadline, Amount: 500000000, Currency: "RUB"},
+			{Goal: vacation.ID, Date: vacation.Deadline, Amount: -100000000, Currency: "RUB"},
+			{Goal: vacation.ID, Date: vacation.Deadline, Amount: 1000000, Currency: "USD"},
+			{Goal: laptop.ID, Date: vacation.Deadline, Amount: 5000000, Currency: "USD"},
+		} {
+			assert.NoError(t, s.AddContribution(_ctx, _testUser101, contribution))
+		}
+
+		// ACT
+		totals, err := s.Totals(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []types.GoalTotal{
+			{Goal: vacation.ID, Currency: "RUB", Amount: 400000000},
+			{Goal: vacation.ID, Currency: "USD", Amount: 1000000},
+			{Goal: laptop.ID, Currency: "USD", Amount: 5000000},
+		}, totals)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// ACT
+		err := s.Delete(_ctx, _testUser101, laptop.ID)
+		_, found, getErr := s.GetByName(_ctx, _testUser101, "laptop")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, getErr)
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,183 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgGroupStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgGroupStorage) Add(ctx context.Context, code string, owner types.GroupMember) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGroupStorage.Add")
+	defer span.Finish()
+
+	var id int64
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`with g as (
+           insert into groups (code)
+           values ($1)
+             returning id
+         )
+         insert into group_members (user_id, group_id, name, role)
+         select $2, id, $3, $4
+         from g
+           returning group_id`,
+		code,       // $1
+		owner.User, // $2
+		owner.Name, // $3
+		owner.Role, // $4
+	).Scan(&id)
+	if err != nil {
+		return 0, errors.Wrap(err, "insert group")
+	}
+
+	return id, nil
+}
+
+func (s *pgGroupStorage) GetByCode(ctx context.Context, code string) (types.Group, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGroupStorage.GetByCode")
+	defer span.Finish()
+
+	group := types.Group{Code: code}
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select id
+         from groups
+         where code = $1`,
+		code, // $1
+	).Scan(&group.ID)
+	if err == pgx.ErrNoRows {
+		return types.Group{}, false, nil
+	} else if err != nil {
+		return types.Group{}, false, errors.Wrap(err, "select group")
+	}
+
+	if group.Members, err = s.listMembers(ctx, group.ID); err != nil {
+		return types.Group{}, false, err
+	}
+
+	return group, true, nil
+}
+
+func (s *pgGroupStorage) GetByUser(ctx context.Context, user *types.User) (types.Group, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGroupStorage.GetByUser")
+	defer span.Finish()
+
+	var group types.Group
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select g.id, g.code
+         from groups g
+           join group_members m on m.group_id = g.id
+         where m.user_id = $1`,
+		user, // $1
+	).Scan(&group.ID, &group.Code)
+	if err == pgx.ErrNoRows {
+		return types.Group{}, false, nil
+	} else if err != nil {
+		return types.Group{}, false, errors.Wrap(err, "select user group")
+	}
+
+	if group.Members, err = s.listMembers(ctx, group.ID); err != nil {
+		return types.Group{}, false, err
+	}
+
+	return group, true, nil
+}
+
+func (s *pgGroupStorage) AddMember(ctx context.Context, group int64, member types.GroupMember) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGroupStorage.AddMember")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into group_members (user_id, group_id, name, role)
+         values ($1, $2, $3, $4)
+         on conflict (user_id) do nothing`,
+		member.User, // $1
+		group,       // $2
+		member.Name, // $3
+		member.Role, // $4
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "insert group member")
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (s *pgGroupStorage) RemoveMember(ctx context.Context, user *types.User) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGroupStorage.RemoveMember")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from group_members
+         where user_id = $1`,
+		user, // $1
+	)
+	if err != nil {
+		return errors.Wrap(err, "delete group member")
+	}
+
+	return nil
+}
+
+func (s *pgGroupStorage) Delete(ctx context.Context, group int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgGroupStorage.Delete")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from groups
+         where id = $1`,
+		group, // $1
+	)
+	if err != nil {
+		return errors.Wrap(err, "delete group")
+	}
+
+	return nil
+}
+
+func (s *pgGroupStorage) listMembers(ctx context.Context, group int64) ([]types.GroupMember, error) {
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select user_id, name, role
+         from group_members
+         where group_id = $1
+         order by role <> 'owner', name`,
+		group, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select group members")
+	}
+	defer rows.Close()
+
+	list := make([]types.GroupMember, 0)
+
+	for rows.Next() {
+		var (
+			userID int64
+			member types.GroupMember
+		)
+		if err := rows.Scan(&userID, &member.Name, &member.Role); err != nil {
+			return nil, errors.Wrap(err, "scan selected group members")
+		}
+
+		user := types.User(userID)
+		member.User = &user
+
+		list = append(list, member)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected group members")
+}
@@ -0,0 +1,82 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgGroupStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateGroupStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from groups where code = $1`, "TEST2345")
+	})
+
+	owner := types.GroupMember{User: _testUser101, Name: "@alice", Role: types.RoleOwner}
+	member := types.GroupMember{User: _testUser102, Name: "@bob", Role: types.RoleMember}
+
+	var id int64
+
+	t.Run("add", func(t *testing.T) {
+		// ACT
+		var err error
+		id, err = s.Add(_ctx, "TEST2345", owner)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NotZero(t, id)
+	})
+
+	t.Run("add member", func(t *testing.T) {
+		// ACT
+		added, err := s.AddMember(_ctx, id, member)
+		duplicate, duplicateErr := s.AddMember(_ctx, id, member)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, added)
+		assert.NoError(t, duplicateErr)
+		assert.False(t, duplicate)
+	})
+
+	t.Run("get by code", func(t *testing.T) {
+		// ACT
+		group, found, err := s.GetByCode(_ctx, "TEST2345")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, types.Group{
+			ID:      id,
+			Code:    "TEST2345",
+			Members: []types.GroupMember{owner, member},
+		}, group)
+	})
+
+	t.Run("remove member", func(t *testing.T) {
+		// ACT
+		err := s.RemoveMember(_ctx, _testUser102)
+		_, found, getErr := s.GetByUser(_ctx, _testUser102)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, getErr)
+		assert.False(t, found)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// ACT
+		err := s.Delete(_ctx, id)
+		_, found, getErr := s.GetByUser(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, getErr)
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgIncomeStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgIncomeStorage) Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgIncomeStorage.Add")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into incomes (user_id, date, amount, currency_code, category, account_id)
+         values ($1, $2, $3, $4, $5, nullif($6, 0))`,
+		user,          // $1
+		item.Date,     // $2
+		item.Amount,   // $3
+		item.Currency, // $4
+		category,      // $5
+		item.Account,  // $6
+	)
+	if err != nil {
+		return errors.Wrap(err, "insert income")
+	}
+
+	return nil
+}
+
+func (s *pgIncomeStorage) SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgIncomeStorage.SumByDate")
+	defer span.Finish()
+
+	var toBound *time.Time
+	if !to.IsZero() {
+		toBound = &to
+	}
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select date, category, currency_code, sum(amount)::bigint
+         from incomes
+         where user_id = $1
+           and date >= $2
+           and ($3::date is null or date < $3)
+         group by date, category, currency_code
+         order by date, category, currency_code`,
+		user,    // $1
+		from,    // $2
+		toBound, // $3
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select income totals")
+	}
+	defer rows.Close()
+
+	list := make([]types.ExpenseTotal, 0)
+	for rows.Next() {
+		var item types.ExpenseTotal
+		if err := rows.Scan(&item.Date, &item.Category, &item.Currency, &item.Amount); err != nil {
+			return nil, errors.Wrap(err, "scan selected income totals")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected income totals")
+}
+
+func (s *pgIncomeStorage) ListLast(ctx context.Context, user *types.User, count int) ([]types.Income, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgIncomeStorage.ListLast")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select id, category, date, amount, currency_code
+         from incomes
+         where user_id = $1
+         order by date desc, id desc
+         limit $2`,
+		user,  // $1
+		count, // $2
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select last incomes")
+	}
+	defer rows.Close()
+
+	list := make([]types.Income, 0, count)
+
+	var item types.Income
+	for rows.Next() {
+		if err := rows.Scan(&item.ID, &item.Category, &item.Date, &item.Amount, &item.Currency); err != nil {
+			return nil, errors.Wrap(err, "scan selected incomes")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected incomes")
+}
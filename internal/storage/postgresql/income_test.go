@@ -0,0 +1,74 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgIncomeStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateIncomeStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from incomes where user_id in ($1, $2)`, int64(*_testUser101), int64(*_testUser102))
+	})
+
+	t.Run("add", func(t *testing.T) {
+		// ACT
+		salaryErr := s.Add(_ctx, _testUser101, types.ExpenseItem{
+			Date:     time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC),
+			Amount:   1500000000,
+			Currency: "RUB",
+		}, "salary")
+		freelanceErr := s.Add(_ctx, _testUser101, types.ExpenseItem{
+			Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+			Amount:   5000000,
+			Currency: "USD",
+		}, "freelance")
+		otherErr := s.Add(_ctx, _testUser102, types.ExpenseItem{
+			Date:     time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC),
+			Amount:   1000000000,
+			Currency: "RUB",
+		}, "salary")
+
+		// ASSERT
+		assert.NoError(t, salaryErr)
+		assert.NoError(t, freelanceErr)
+		assert.NoError(t, otherErr)
+	})
+
+	t.Run("sum by date", func(t *testing.T) {
+		// ACT
+		totals, err := s.SumByDate(_ctx, _testUser101, time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC))
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.ExpenseTotal{
+			{
+				Date:     time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC),
+				Category: "salary",
+				Currency: "RUB",
+				Amount:   1500000000,
+			},
+		}, totals)
+	})
+
+	t.Run("list last", func(t *testing.T) {
+		// ACT
+		list, err := s.ListLast(_ctx, _testUser101, 1)
+
+		// ASSERT
+		assert.NoError(t, err)
+		if assert.Len(t, list, 1) {
+			assert.NotZero(t, list[0].ID)
+			assert.Equal(t, "freelance", list[0].Category)
+			assert.Equal(t, int64(5000000), list[0].Amount)
+			assert.Equal(t, "USD", list[0].Currency)
+		}
+	})
+}
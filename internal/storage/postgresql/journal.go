@@ -0,0 +1,122 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgJournalStorage struct {
+	pool *pgxpool.Pool
+}
+
+// operationPayload is the state of the operation needed to revert it.
+type operationPayload struct {
+	ExpenseID int64           `json:"expense_id,omitempty"`
+	Debts     []types.Debt    `json:"debts,omitempty"`
+	Category  string          `json:"category,omitempty"`
+	Limit     types.LimitItem `json:"limit"`
+	Currency  string          `json:"currency,omitempty"`
+}
+
+func (s *pgJournalStorage) Add(ctx context.Context, user *types.User, op types.Operation) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgJournalStorage.Add")
+	defer span.Finish()
+
+	payload, err := json.Marshal(operationPayload{
+		ExpenseID: op.ExpenseID,
+		Debts:     op.Debts,
+		Category:  op.Category,
+		Limit:     op.Limit,
+		Currency:  op.Currency,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "marshal operation")
+	}
+
+	var id int64
+	err = conn(ctx, s.pool).QueryRow(
+		ctx,
+		`insert into journal (user_id, kind, payload, created_at)
+         values ($1, $2, $3, $4)
+           returning id`,
+		user,       // $1
+		op.Kind,    // $2
+		payload,    // $3
+		op.Created, // $4
+	).Scan(&id)
+	if err != nil {
+		return 0, errors.Wrap(err, "insert operation")
+	}
+
+	return id, nil
+}
+
+func (s *pgJournalStorage) Pop(ctx context.Context, user *types.User, id int64, since time.Time) (types.Operation, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgJournalStorage.Pop")
+	defer span.Finish()
+
+	var (
+		op      types.Operation
+		payload []byte
+	)
+
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`delete
+         from journal
+         where id = (
+           select id
+           from journal
+           where user_id = $1
+             and created_at >= $2
+           order by created_at desc, id desc
+           limit 1
+         )
+           and (id = $3 or $3 = 0)
+           returning id, kind, payload, created_at`,
+		user,  // $1
+		since, // $2
+		id,    // $3
+	).Scan(&op.ID, &op.Kind, &payload, &op.Created)
+	if err == pgx.ErrNoRows {
+		return types.Operation{}, false, nil
+	} else if err != nil {
+		return types.Operation{}, false, errors.Wrap(err, "delete last operation")
+	}
+
+	var p operationPayload
+	if err = json.Unmarshal(payload, &p); err != nil {
+		return types.Operation{}, false, errors.Wrap(err, "unmarshal operation")
+	}
+
+	op.ExpenseID, op.Debts, op.Category, op.Limit, op.Currency = p.ExpenseID, p.Debts, p.Category, p.Limit, p.Currency
+
+	return op, true, nil
+}
+
+func (s *pgJournalStorage) DeleteBefore(ctx context.Context, user *types.User, before time.Time) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgJournalStorage.DeleteBefore")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete
+         from journal
+         where user_id = $1
+           and created_at < $2`,
+		user,   // $1
+		before, // $2
+	)
+	if err != nil {
+		return errors.Wrap(err, "delete old operations")
+	}
+
+	return nil
+}
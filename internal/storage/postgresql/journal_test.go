@@ -0,0 +1,99 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgJournalStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateJournalStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from journal where user_id = $1`, int64(*_testUser102))
+	})
+
+	created := time.Date(2022, 11, 19, 12, 0, 0, 0, time.UTC)
+
+	limit := types.Operation{
+		Kind:     types.OperationSetLimit,
+		Created:  created,
+		Category: "taxi",
+		Limit: types.LimitItem{
+			Total:    10000000,
+			Remains:  2500000,
+			Currency: "RUB",
+			Period:   types.LimitPeriod{Kind: types.PeriodMonth},
+			Anchor:   time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+			Notified: 50,
+		},
+	}
+	currency := types.Operation{
+		Kind:     types.OperationSetCurrency,
+		Created:  created.Add(time.Minute),
+		Currency: "USD",
+	}
+
+	var limitID, currencyID int64
+
+	t.Run("add", func(t *testing.T) {
+		// ACT
+		_, err1 := s.Add(_ctx, _testUser102, types.Operation{Kind: types.OperationAddExpense, Created: created.Add(-time.Hour), ExpenseID: 7})
+		id2, err2 := s.Add(_ctx, _testUser102, limit)
+		id3, err3 := s.Add(_ctx, _testUser102, currency)
+
+		// ASSERT
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.NoError(t, err3)
+		assert.NotZero(t, id2)
+		assert.Greater(t, id3, id2)
+
+		limitID, currencyID = id2, id3
+	})
+
+	t.Run("pop the last ones", func(t *testing.T) {
+		// ACT
+		_, foundNotLast, errNotLast := s.Pop(_ctx, _testUser102, limitID, created)
+		op1, found1, err1 := s.Pop(_ctx, _testUser102, currencyID, created)
+		op2, found2, err2 := s.Pop(_ctx, _testUser102, 0, created)
+		_, found3, err3 := s.Pop(_ctx, _testUser102, 0, created)
+		_, foundOther, errOther := s.Pop(_ctx, _testUser101, 0, time.Time{})
+
+		// ASSERT
+		assert.NoError(t, errNotLast)
+		assert.False(t, foundNotLast)
+		assert.NoError(t, err1)
+		assert.True(t, found1)
+		assert.Equal(t, currencyID, op1.ID)
+		assert.Equal(t, currency.Currency, op1.Currency)
+		assert.True(t, currency.Created.Equal(op1.Created))
+		assert.NoError(t, err2)
+		assert.True(t, found2)
+		assert.Equal(t, types.OperationSetLimit, op2.Kind)
+		assert.Equal(t, "taxi", op2.Category)
+		assert.Equal(t, limit.Limit.Remains, op2.Limit.Remains)
+		assert.Equal(t, limit.Limit.Notified, op2.Limit.Notified)
+		assert.True(t, limit.Limit.Anchor.Equal(op2.Limit.Anchor))
+		assert.NoError(t, err3)
+		assert.False(t, found3)
+		assert.NoError(t, errOther)
+		assert.False(t, foundOther)
+	})
+
+	t.Run("delete before", func(t *testing.T) {
+		// ACT
+		err := s.DeleteBefore(_ctx, _testUser102, created)
+		_, found, popErr := s.Pop(_ctx, _testUser102, 0, time.Time{})
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, popErr)
+		assert.False(t, found)
+	})
+}
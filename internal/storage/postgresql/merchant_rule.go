@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgMerchantRuleStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgMerchantRuleStorage) List(ctx context.Context, user *types.User) ([]types.MerchantRule, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgMerchantRuleStorage.List")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select pattern, category
+         from merchant_rules
+         where user_id = $1
+         order by pattern`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select merchant rules")
+	}
+	defer rows.Close()
+
+	list := make([]types.MerchantRule, 0)
+
+	var rule types.MerchantRule
+	for rows.Next() {
+		if err := rows.Scan(&rule.Pattern, &rule.Category); err != nil {
+			return nil, errors.Wrap(err, "scan selected merchant rules")
+		}
+
+		list = append(list, rule)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected merchant rules")
+}
+
+func (s *pgMerchantRuleStorage) Set(ctx context.Context, user *types.User, rule types.MerchantRule) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgMerchantRuleStorage.Set")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into merchant_rules (user_id, pattern, category)
+         values ($1, $2, $3)
+         on conflict (user_id, pattern) do update
+           set category = excluded.category`,
+		user,          // $1
+		rule.Pattern,  // $2
+		rule.Category, // $3
+	)
+	if err != nil {
+		return errors.Wrap(err, "upsert merchant rule")
+	}
+
+	return nil
+}
+
+func (s *pgMerchantRuleStorage) Delete(ctx context.Context, user *types.User, pattern string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgMerchantRuleStorage.Delete")
+	defer span.Finish()
+
+	tag, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from merchant_rules
+         where user_id = $1
+           and pattern = $2`,
+		user,    // $1
+		pattern, // $2
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "delete merchant rule")
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
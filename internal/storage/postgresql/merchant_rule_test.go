@@ -0,0 +1,55 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgMerchantRuleStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateMerchantRuleStorage()
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from merchant_rules where user_id = $1`, int64(*_testUser101))
+	})
+
+	t.Run("set", func(t *testing.T) {
+		// ACT
+		err1 := s.Set(_ctx, _testUser101, types.MerchantRule{Pattern: "yandex taxi", Category: "car"})
+		err2 := s.Set(_ctx, _testUser101, types.MerchantRule{Pattern: "yandex taxi", Category: "taxi"})
+		err3 := s.Set(_ctx, _testUser101, types.MerchantRule{Pattern: "starbucks", Category: "coffee"})
+
+		// ASSERT
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.NoError(t, err3)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		// ACT
+		list, err := s.List(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.MerchantRule{
+			{Pattern: "starbucks", Category: "coffee"},
+			{Pattern: "yandex taxi", Category: "taxi"},
+		}, list)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// ACT
+		ok, err := s.Delete(_ctx, _testUser101, "starbucks")
+		missing, missingErr := s.Delete(_ctx, _testUser101, "starbucks")
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.NoError(t, missingErr)
+		assert.False(t, missing)
+	})
+}
@@ -0,0 +1,80 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+type pgOutboxStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgOutboxStorage) Add(ctx context.Context, event types.OutboxEvent) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgOutboxStorage.Add")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`insert into outbox (kind, payload)
+         values ($1, $2)`,
+		event.Kind,    // $1
+		event.Payload, // $2
+	)
+	if err != nil {
+		return errors.Wrap(err, "insert outbox event")
+	}
+
+	return nil
+}
+
+func (s *pgOutboxStorage) LockPending(ctx context.Context, limit int) ([]types.OutboxEvent, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgOutboxStorage.LockPending")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select id, kind, payload
+         from outbox
+         order by id
+         limit $1
+           for update skip locked`,
+		limit, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select outbox events")
+	}
+	defer rows.Close()
+
+	var events []types.OutboxEvent
+	for rows.Next() {
+		var event types.OutboxEvent
+		if err := rows.Scan(&event.ID, &event.Kind, &event.Payload); err != nil {
+			return nil, errors.Wrap(err, "scan selected outbox event")
+		}
+
+		events = append(events, event)
+	}
+
+	return events, errors.Wrap(rows.Err(), "iterate selected outbox events")
+}
+
+func (s *pgOutboxStorage) Delete(ctx context.Context, ids []int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgOutboxStorage.Delete")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`delete from outbox
+         where id = any ($1)`,
+		ids, // $1
+	)
+	if err != nil {
+		return errors.Wrap(err, "delete outbox events")
+	}
+
+	return nil
+}
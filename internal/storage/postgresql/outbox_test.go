@@ -0,0 +1,69 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgOutboxStorage_LockPending_Delete(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateOutboxStorage()
+	uow := _testFactory.CreateUnitOfWork()
+
+	_ = s.Add(_ctx, types.OutboxEvent{Kind: "expense.added", Payload: []byte(`{"id": 1}`)})
+	_ = s.Add(_ctx, types.OutboxEvent{Kind: "expense.deleted", Payload: []byte(`{"id": 1}`)})
+	_ = s.Add(_ctx, types.OutboxEvent{Kind: "expense.added", Payload: []byte(`{"id": 2}`)})
+
+	t.Run("locked events skipped", func(t *testing.T) {
+		// ACT
+		var locked, concurrent []types.OutboxEvent
+		var lockErr, concurrentErr error
+		doErr := uow.Do(_ctx, func(ctx context.Context) error {
+			locked, lockErr = s.LockPending(ctx, 2)
+
+			return uow.Do(context.Background(), func(ctx context.Context) error {
+				concurrent, concurrentErr = s.LockPending(ctx, 10)
+				return nil
+			})
+		})
+
+		// ASSERT
+		assert.NoError(t, doErr)
+		assert.NoError(t, lockErr)
+		assert.NoError(t, concurrentErr)
+		if assert.Len(t, locked, 2) {
+			assert.Equal(t, "expense.added", locked[0].Kind)
+			assert.JSONEq(t, `{"id": 1}`, string(locked[0].Payload))
+			assert.Equal(t, "expense.deleted", locked[1].Kind)
+		}
+		if assert.Len(t, concurrent, 1) {
+			assert.JSONEq(t, `{"id": 2}`, string(concurrent[0].Payload))
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// ARRANGE
+		pending, _ := s.LockPending(_ctx, 10)
+
+		// ACT
+		deleteErr := s.Delete(_ctx, []int64{pending[0].ID, pending[1].ID})
+		left, lockErr := s.LockPending(_ctx, 10)
+
+		// ASSERT
+		assert.NoError(t, deleteErr)
+		assert.NoError(t, lockErr)
+		if assert.Len(t, left, 1) {
+			assert.Equal(t, pending[2], left[0])
+		}
+	})
+
+	// CLEANUP
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from outbox`)
+	})
+}
@@ -0,0 +1,183 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+const _recurringExpenseColumns = `id, amount, currency_code, category, frequency, step, start_date, occurrences, next_date, paused`
+
+type pgRecurringExpenseStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *pgRecurringExpenseStorage) Add(ctx context.Context, user *types.User, item types.RecurringExpense) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgRecurringExpenseStorage.Add")
+	defer span.Finish()
+
+	var id int64
+	err := conn(ctx, s.pool).QueryRow(
+		ctx,
+		`insert into recurring_expenses (user_id, amount, currency_code, category, frequency, step, start_date, occurrences, next_date, paused)
+         values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+           returning id`,
+		user,                      // $1
+		item.Amount,               // $2
+		item.Currency,             // $3
+		item.Category,             // $4
+		item.Recurrence.Frequency, // $5
+		item.Recurrence.Interval,  // $6
+		item.Recurrence.Start,     // $7
+		item.Count,                // $8
+		item.Next,                 // $9
+		item.Paused,               // $10
+	).Scan(&id)
+	if err != nil {
+		return 0, errors.Wrap(err, "insert recurring expense")
+	}
+
+	return id, nil
+}
+
+func (s *pgRecurringExpenseStorage) List(ctx context.Context, user *types.User) ([]types.RecurringExpense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgRecurringExpenseStorage.List")
+	defer span.Finish()
+
+	rows, err := conn(ctx, s.pool).Query(
+		ctx,
+		`select `+_recurringExpenseColumns+`
+         from recurring_expenses
+         where user_id = $1
+         order by id`,
+		user, // $1
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "select recurring expenses")
+	}
+	defer rows.Close()
+
+	list := make([]types.RecurringExpense, 0)
+	for rows.Next() {
+		item, err := scanRecurringExpense(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan selected recurring expenses")
+		}
+
+		list = append(list, item)
+	}
+
+	return list, errors.Wrap(rows.Err(), "iterate selected recurring expenses")
+}
+
+// Get locks the recurring expense till the end of the transaction, so that the scheduler doesn't
+// overwrite its pause and vice versa.
+func (s *pgRecurringExpenseStorage) Get(ctx context.Context, user *types.User, id int64) (types.RecurringExpense, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgRecurringExpenseStorage.Get")
+	defer span.Finish()
+
+	item, err := scanRecurringExpense(conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select `+_recurringExpenseColumns+`
+         from recurring_expenses
+         where user_id = $1
+           and id = $2
+           for update`,
+		user, // $1
+		id,   // $2
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return types.RecurringExpense{}, false, nil
+	}
+	if err != nil {
+		return types.RecurringExpense{}, false, errors.Wrap(err, "select recurring expense")
+	}
+
+	return item, true, nil
+}
+
+func (s *pgRecurringExpenseStorage) Update(ctx context.Context, user *types.User, item types.RecurringExpense) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgRecurringExpenseStorage.Update")
+	defer span.Finish()
+
+	_, err := conn(ctx, s.pool).Exec(
+		ctx,
+		`update recurring_expenses
+         set occurrences = $3,
+             next_date   = $4,
+             paused      = $5
+         where user_id = $1
+           and id = $2`,
+		user,        // $1
+		item.ID,     // $2
+		item.Count,  // $3
+		item.Next,   // $4
+		item.Paused, // $5
+	)
+	if err != nil {
+		return errors.Wrap(err, "update recurring expense")
+	}
+
+	return nil
+}
+
+func (s *pgRecurringExpenseStorage) LockDue(ctx context.Context, date time.Time, exclude []int64) (*types.User, types.RecurringExpense, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgRecurringExpenseStorage.LockDue")
+	defer span.Finish()
+
+	if exclude == nil {
+		exclude = []int64{}
+	}
+
+	var userID int64
+	item, err := scanRecurringExpense(conn(ctx, s.pool).QueryRow(
+		ctx,
+		`select user_id, `+_recurringExpenseColumns+`
+         from recurring_expenses
+         where not paused
+           and next_date <= $1
+           and id <> all ($2)
+         order by next_date, id
+         limit 1
+           for update skip locked`,
+		date,    // $1
+		exclude, // $2
+	), &userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, types.RecurringExpense{}, false, nil
+	}
+	if err != nil {
+		return nil, types.RecurringExpense{}, false, errors.Wrap(err, "select due recurring expense")
+	}
+
+	user := types.User(userID)
+	return &user, item, true, nil
+}
+
+// scanRecurringExpense scans the prefix destinations followed by the recurring expense columns.
+func scanRecurringExpense(row pgx.Row, prefix ...any) (types.RecurringExpense, error) {
+	var item types.RecurringExpense
+
+	dest := append(prefix,
+		&item.ID,
+		&item.Amount,
+		&item.Currency,
+		&item.Category,
+		&item.Recurrence.Frequency,
+		&item.Recurrence.Interval,
+		&item.Recurrence.Start,
+		&item.Count,
+		&item.Next,
+		&item.Paused,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return types.RecurringExpense{}, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,107 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgRecurringExpenseStorage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateRecurringExpenseStorage()
+	uow := _testFactory.CreateUnitOfWork()
+
+	start := time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)
+	item := types.RecurringExpense{
+		Recurrence: types.Recurrence{Frequency: types.FrequencyMonthly, Interval: 1, Start: start},
+		Amount:     300000000,
+		Currency:   "RUB",
+		Category:   "rent",
+		Next:       start,
+	}
+
+	t.Cleanup(func() {
+		_, _ = _testFactory.pool.Exec(_ctx, `delete from recurring_expenses where user_id in ($1, $2)`, int64(*_testUser101), int64(*_testUser102))
+	})
+
+	t.Run("add", func(t *testing.T) {
+		// ACT
+		id, err := s.Add(_ctx, _testUser101, item)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NotZero(t, id)
+
+		item.ID = id
+	})
+
+	t.Run("lock due", func(t *testing.T) {
+		// ACT
+		var (
+			user    *types.User
+			due     types.RecurringExpense
+			found   bool
+			skipped bool
+		)
+
+		err := uow.Do(_ctx, func(ctx context.Context) (err error) {
+			if user, due, found, err = s.LockDue(ctx, start, nil); err != nil {
+				return err
+			}
+
+			// a concurrent transaction skips the locked recurring expense
+			_, _, locked, err := s.LockDue(_ctx, start, nil)
+			skipped = !locked
+
+			return err
+		})
+
+		_, _, excluded, excludedErr := s.LockDue(_ctx, start, []int64{item.ID})
+		_, _, early, earlyErr := s.LockDue(_ctx, start.AddDate(0, 0, -1), nil)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, *_testUser101, *user)
+		assert.Equal(t, item, due)
+		assert.True(t, skipped)
+		assert.NoError(t, excludedErr)
+		assert.False(t, excluded)
+		assert.NoError(t, earlyErr)
+		assert.False(t, early)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		// ARRANGE
+		item.Count = 1
+		item.Next = item.Recurrence.Occurrence(1)
+		item.Paused = true
+
+		// ACT
+		err := s.Update(_ctx, _testUser101, item)
+		got, found, getErr := s.Get(_ctx, _testUser101, item.ID)
+		_, foreign, foreignErr := s.Get(_ctx, _testUser102, item.ID)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, getErr)
+		assert.True(t, found)
+		assert.Equal(t, item, got)
+		assert.NoError(t, foreignErr)
+		assert.False(t, foreign)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		// ACT
+		list, err := s.List(_ctx, _testUser101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Equal(t, []types.RecurringExpense{item}, list)
+	})
+}
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -74,3 +75,68 @@ func (s *pgTelegramUserStorage) FetchByID(ctx context.Context, tgUserID int64) (
 	user := types.User(userId)
 	return &user, nil
 }
+
+func (s *pgTelegramUserStorage) FetchTelegramID(ctx context.Context, user *types.User) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgTelegramUserStorage.FetchTelegramID")
+	defer span.Finish()
+
+	var tgUserID int64
+
+	err := s.pool.QueryRow(
+		ctx,
+		`select id
+         from tg_users
+         where user_id = $1`,
+		user, // $1
+	).Scan(&tgUserID)
+	if err != nil {
+		return 0, errors.Wrap(err, "select telegram user id")
+	}
+
+	return tgUserID, nil
+}
+
+func (s *pgTelegramUserStorage) FetchLanguage(ctx context.Context, tgUserID int64) (string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgTelegramUserStorage.FetchLanguage")
+	defer span.Finish()
+
+	var lang *string
+
+	err := s.pool.QueryRow(
+		ctx,
+		`select lang
+         from tg_users
+         where id = $1`,
+		tgUserID, // $1
+	).Scan(&lang)
+	if err == pgx.ErrNoRows || err == nil && lang == nil {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Wrap(err, "select telegram user language")
+	}
+
+	return *lang, nil
+}
+
+func (s *pgTelegramUserStorage) SetLanguage(ctx context.Context, tgUserID int64, lang string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pgTelegramUserStorage.SetLanguage")
+	defer span.Finish()
+
+	tag, err := s.pool.Exec(
+		ctx,
+		`update tg_users
+         set lang = $2
+         where id = $1`,
+		tgUserID, // $1
+		lang,     // $2
+	)
+	if err != nil {
+		return errors.Wrap(err, "update telegram user language")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("telegram user not found")
+	}
+
+	return nil
+}
@@ -65,3 +65,46 @@ func Test_pgTelegramUserStorage_FetchByID(t *testing.T) {
 		assert.IsType(t, &([]types.User{types.User(0)}[0]), user)
 	})
 }
+
+func Test_pgTelegramUserStorage_SetLanguage(t *testing.T) {
+	// ARRANGE
+	s := _testFactory.CreateTelegramUserStorage()
+
+	t.Run("no user", func(t *testing.T) {
+		// ACT
+		err := s.SetLanguage(_ctx, 99999, "en")
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+
+	t.Run("not chosen", func(t *testing.T) {
+		// ACT
+		lang, err := s.FetchLanguage(_ctx, 102)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.Empty(t, lang)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// ACT
+		err := s.SetLanguage(_ctx, 101, "en")
+		lang, fetchErr := s.FetchLanguage(_ctx, 101)
+
+		// ASSERT
+		assert.NoError(t, err)
+		assert.NoError(t, fetchErr)
+		assert.Equal(t, "en", lang)
+
+		// CLEANUP
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(
+				_ctx,
+				`update tg_users
+                 set lang = null
+                 where id = 101`,
+			)
+		})
+	})
+}
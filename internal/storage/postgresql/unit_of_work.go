@@ -0,0 +1,79 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/ctxkey"
+)
+
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// conn returns the transaction started by the unit of work for the context or the pool otherwise.
+func conn(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(ctxkey.Transaction).(pgx.Tx); ok {
+		return tx
+	}
+
+	return pool
+}
+
+type pgUnitOfWork struct {
+	pool *pgxpool.Pool
+}
+
+func (u *pgUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(ctxkey.Transaction).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "pgUnitOfWork.Do")
+	defer span.Finish()
+
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "begin transaction")
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	var hooks []func(ctx context.Context)
+
+	txCtx := context.WithValue(context.WithValue(ctx, ctxkey.Transaction, tx), ctxkey.CommitHooks, &hooks)
+	if err := fn(txCtx); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit transaction")
+	}
+
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+
+	return nil
+}
+
+func (u *pgUnitOfWork) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(ctxkey.CommitHooks).(*[]func(ctx context.Context)); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+
+	fn(ctx)
+}
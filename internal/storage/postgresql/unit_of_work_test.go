@@ -0,0 +1,106 @@
+//go:build integration
+
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+func Test_pgUnitOfWork_Do(t *testing.T) {
+	// ARRANGE
+	uow := _testFactory.CreateUnitOfWork()
+	expenses := _testFactory.CreateExpenseStorage()
+	limits := _testFactory.CreateExpenseLimitStorage()
+	outbox := _testFactory.CreateOutboxStorage()
+
+	from := time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC)
+	change := func(ctx context.Context) error {
+		if _, err := expenses.Add(ctx, _testUser102, types.ExpenseItem{
+			Date:     from,
+			Amount:   300000,
+			Currency: "USD",
+		}, "tea"); err != nil {
+			return err
+		}
+
+		if _, err := limits.Decrease(ctx, _testUser101, 100000, "taxi"); err != nil {
+			return err
+		}
+
+		return outbox.Add(ctx, types.OutboxEvent{Kind: "expense.added", Payload: []byte(`{}`)})
+	}
+
+	t.Run("rollback", func(t *testing.T) {
+		// ACT
+		var committed bool
+		doErr := uow.Do(_ctx, func(ctx context.Context) error {
+			if err := change(ctx); err != nil {
+				return err
+			}
+
+			uow.AfterCommit(ctx, func(context.Context) { committed = true })
+
+			return errors.New("failure")
+		})
+		list, listErr := expenses.List(_ctx, _testUser102, from, time.Time{})
+		limit, _, getErr := limits.Get(_ctx, _testUser101, "taxi")
+
+		// ASSERT
+		assert.Error(t, doErr)
+		assert.NoError(t, listErr)
+		assert.NoError(t, getErr)
+		assert.Empty(t, list)
+		assert.Equal(t, int64(700000), limit.Remains)
+		assert.False(t, committed)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		// ACT
+		var listedAfterCommit map[string][]types.ExpenseItem
+		doErr := uow.Do(_ctx, func(ctx context.Context) error {
+			uow.AfterCommit(ctx, func(ctx context.Context) {
+				// the hook sees the committed changes outside of the transaction
+				listedAfterCommit, _ = expenses.List(ctx, _testUser102, from, time.Time{})
+			})
+
+			return change(ctx)
+		})
+		list, listErr := expenses.List(_ctx, _testUser102, from, time.Time{})
+		limit, _, getErr := limits.Get(_ctx, _testUser101, "taxi")
+
+		// ASSERT
+		assert.NoError(t, doErr)
+		assert.NoError(t, listErr)
+		assert.NoError(t, getErr)
+		assert.Len(t, list["tea"], 1)
+		assert.Equal(t, int64(600000), limit.Remains)
+		assert.Len(t, listedAfterCommit["tea"], 1)
+
+		// CLEANUP
+		t.Cleanup(func() {
+			_, _ = _testFactory.pool.Exec(
+				_ctx,
+				`delete
+                 from expenses
+                 where user_id = $1
+                   and category = 'tea'`,
+				int64(*_testUser102),
+			)
+			_, _ = _testFactory.pool.Exec(
+				_ctx,
+				`update limits
+                 set remains = 700000
+                 where user_id = $1
+                   and category = 'taxi'`,
+				int64(*_testUser101),
+			)
+			_, _ = _testFactory.pool.Exec(_ctx, `delete from outbox`)
+		})
+	})
+}
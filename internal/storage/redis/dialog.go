@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/almenschhikov/go-course-4/internal/types"
+)
+
+// redisDialogStorage keeps the state in keys expiring along with it, so that dialogs survive restarts of the bot.
+type redisDialogStorage struct {
+	keyPrefix string
+	rdb       redis.Cmdable
+}
+
+func NewDialogStorage(dsn string) (*redisDialogStorage, error) {
+	opts, err := redis.ParseURL(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redisDialogStorage{
+		keyPrefix: "dialog",
+		rdb:       redis.NewClient(opts),
+	}, nil
+}
+
+func (s *redisDialogStorage) Get(ctx context.Context, user *types.User, key string) ([]byte, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisDialogStorage.Get")
+	defer span.Finish()
+
+	value, err := s.rdb.Get(ctx, s.stateKey(user, key)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, errors.Wrap(err, "get state")
+	}
+
+	return value, true, nil
+}
+
+func (s *redisDialogStorage) Set(ctx context.Context, user *types.User, key string, value []byte, ttl time.Duration) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisDialogStorage.Set")
+	defer span.Finish()
+
+	return errors.Wrap(s.rdb.Set(ctx, s.stateKey(user, key), value, ttl).Err(), "set state")
+}
+
+func (s *redisDialogStorage) Delete(ctx context.Context, user *types.User, key string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "redisDialogStorage.Delete")
+	defer span.Finish()
+
+	return errors.Wrap(s.rdb.Del(ctx, s.stateKey(user, key)).Err(), "delete state")
+}
+
+func (s *redisDialogStorage) stateKey(user *types.User, key string) string {
+	return fmt.Sprintf("%s_%d_%s", s.keyPrefix, int64(*user), key)
+}
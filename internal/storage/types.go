@@ -11,19 +11,187 @@ type (
 	TelegramUserStorage interface {
 		Add(ctx context.Context, tgUserID int64) (*types.User, error)
 		FetchByID(ctx context.Context, tgUserID int64) (*types.User, error)
+		// FetchTelegramID returns the Telegram ID of the user to send notifications to.
+		FetchTelegramID(ctx context.Context, user *types.User) (int64, error)
+		// FetchLanguage returns the language chosen by the user, empty if the user hasn't chosen any.
+		FetchLanguage(ctx context.Context, tgUserID int64) (string, error)
+		SetLanguage(ctx context.Context, tgUserID int64, lang string) error
 	}
 
 	ExpenseStorage interface {
+		// Add adds the expense and returns its ID.
+		Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) (int64, error)
+		// List returns expenses dated within [from, to) grouped by category; zero to means no upper bound.
+		List(ctx context.Context, user *types.User, from, to time.Time) (map[string][]types.ExpenseItem, error)
+		// SumByDate returns amounts of expenses dated within [from, to) summed up by date, category and currency.
+		SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error)
+		// Walk calls fn for every expense dated within [from, to) in chronological order and stops
+		// on the first error returned by fn; zero to means no upper bound.
+		Walk(ctx context.Context, user *types.User, from, to time.Time, fn func(types.Expense) error) error
+		ListLast(ctx context.Context, user *types.User, count int) ([]types.Expense, error)
+		// AddImported adds the expenses at once skipping the ones whose hashes have already been
+		// imported, and returns the added expenses.
+		AddImported(ctx context.Context, user *types.User, items []types.ImportedExpense) ([]types.Expense, error)
+		Get(ctx context.Context, user *types.User, id int64) (types.Expense, bool, error)
+		Update(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error
+		Delete(ctx context.Context, user *types.User, id int64) error
+		// Find returns at most limit expenses matching the filter skipping the first offset ones,
+		// the latest expenses go first.
+		Find(ctx context.Context, user *types.User, filter types.ExpenseFilter, offset, limit int) ([]types.Expense, error)
+	}
+
+	CategoryStorage interface {
+		// List returns the categories of the user expenses and the ones having aliases, sorted by names.
+		List(ctx context.Context, user *types.User) ([]types.Category, error)
+		// Resolve returns the category the alias stands for.
+		Resolve(ctx context.Context, user *types.User, alias string) (string, bool, error)
+		SetAlias(ctx context.Context, user *types.User, alias, category string) error
+		DeleteAlias(ctx context.Context, user *types.User, alias string) (bool, error)
+		// Rename moves the category along with its subcategories under the new name in the expenses,
+		// recurring expenses, limits, merchant rules and aliases, and reports whether there was anything
+		// to move. The limit of the category is dropped if the new one already has its own limit.
+		Rename(ctx context.Context, user *types.User, from, to string) (bool, error)
+	}
+
+	// AttachmentStorage links the files kept in the blob storage to the expenses, an expense has one attachment at most.
+	AttachmentStorage interface {
+		// Set attaches the file to the expense replacing the previous attachment, it reports false
+		// when the user has no such expense.
+		Set(ctx context.Context, user *types.User, item types.Attachment) (bool, error)
+		Get(ctx context.Context, user *types.User, expenseID int64) (types.Attachment, bool, error)
+		// ListAttached returns the IDs of the given expenses which have attachments.
+		ListAttached(ctx context.Context, user *types.User, ids []int64) ([]int64, error)
+		// DeleteExpired deletes the attachments created before the time along with the ones left by deleted
+		// expenses and returns their keys; the attachments of existing expenses are kept when the time is zero.
+		DeleteExpired(ctx context.Context, before time.Time) ([]string, error)
+	}
+
+	// JournalStorage keeps the operations of users in the order they're made.
+	JournalStorage interface {
+		// Add adds the operation and returns its ID.
+		Add(ctx context.Context, user *types.User, op types.Operation) (int64, error)
+		// Pop deletes the last operation of the user made since the time and returns it, provided that it has the ID
+		// unless the ID is 0. It reports false when there's no such operation.
+		Pop(ctx context.Context, user *types.User, id int64, since time.Time) (types.Operation, bool, error)
+		// DeleteBefore deletes the operations of the user made before the time.
+		DeleteBefore(ctx context.Context, user *types.User, before time.Time) error
+	}
+
+	// BlobStorage keeps files by their keys.
+	BlobStorage interface {
+		Put(ctx context.Context, key string, data []byte) error
+		Get(ctx context.Context, key string) ([]byte, error)
+		// Delete deletes the file, a missing file is not an error.
+		Delete(ctx context.Context, key string) error
+	}
+
+	// DialogStorage keeps the state of conversations with users under the keys, e.g. the step of a dialog
+	// or the receipt waiting for the category. The state expires after the TTL, zero TTL keeps it forever.
+	DialogStorage interface {
+		Get(ctx context.Context, user *types.User, key string) ([]byte, bool, error)
+		Set(ctx context.Context, user *types.User, key string, value []byte, ttl time.Duration) error
+		// Delete deletes the state, a missing state is not an error.
+		Delete(ctx context.Context, user *types.User, key string) error
+	}
+
+	AccountStorage interface {
+		// Add adds the account unless the user has another one with the same name.
+		Add(ctx context.Context, user *types.User, account types.Account) (bool, error)
+		List(ctx context.Context, user *types.User) ([]types.Account, error)
+		GetByName(ctx context.Context, user *types.User, name string) (types.Account, bool, error)
+		AddTransfer(ctx context.Context, user *types.User, transfer types.Transfer) error
+		// Turnover returns movements of the user accounts summed up by accounts, dates and currencies.
+		Turnover(ctx context.Context, user *types.User) ([]types.AccountTotal, error)
+	}
+
+	IncomeStorage interface {
 		Add(ctx context.Context, user *types.User, item types.ExpenseItem, category string) error
-		List(ctx context.Context, user *types.User, from time.Time) (map[string][]types.ExpenseItem, error)
+		// SumByDate returns amounts of incomes dated within [from, to) summed up by date, category and currency.
+		SumByDate(ctx context.Context, user *types.User, from, to time.Time) ([]types.ExpenseTotal, error)
+		ListLast(ctx context.Context, user *types.User, count int) ([]types.Income, error)
+	}
+
+	GoalStorage interface {
+		// Add adds the goal unless the user has another one with the same name.
+		Add(ctx context.Context, user *types.User, goal types.Goal) (bool, error)
+		List(ctx context.Context, user *types.User) ([]types.Goal, error)
+		GetByName(ctx context.Context, user *types.User, name string) (types.Goal, bool, error)
+		Delete(ctx context.Context, user *types.User, id int64) error
+		AddContribution(ctx context.Context, user *types.User, contribution types.GoalContribution) error
+		// Totals returns contributions to the user goals summed up by goals and currencies.
+		Totals(ctx context.Context, user *types.User) ([]types.GoalTotal, error)
+	}
+
+	GroupStorage interface {
+		// Add creates a group with the owner as its only member and returns the group ID.
+		Add(ctx context.Context, code string, owner types.GroupMember) (int64, error)
+		// GetByCode returns the group along with its members, the owner goes first.
+		GetByCode(ctx context.Context, code string) (types.Group, bool, error)
+		// GetByUser returns the group the user is a member of along with its members, the owner goes first.
+		GetByUser(ctx context.Context, user *types.User) (types.Group, bool, error)
+		// AddMember adds the member to the group unless they are a member of some group already.
+		AddMember(ctx context.Context, group int64, member types.GroupMember) (bool, error)
+		RemoveMember(ctx context.Context, user *types.User) error
+		Delete(ctx context.Context, group int64) error
+	}
+
+	DebtStorage interface {
+		Add(ctx context.Context, debt types.Debt) error
+		// Balances returns open debts between the user and other users netted by counterparties and currencies,
+		// the balances which net to zero are kept so that they could be settled as well.
+		Balances(ctx context.Context, user *types.User) ([]types.DebtBalance, error)
+		// Settle closes open debts between the user and the counterparty in the currency.
+		Settle(ctx context.Context, user, counterparty *types.User, currency string) error
 	}
 
 	ExpenseLimitStorage interface {
 		Get(ctx context.Context, user *types.User, category string) (types.LimitItem, bool, error)
-		Set(ctx context.Context, user *types.User, total int64, currency, category string) error
+		Set(ctx context.Context, user *types.User, item types.LimitItem, category string) error
+		Renew(ctx context.Context, user *types.User, item types.LimitItem, category string) error
+		Refresh(ctx context.Context, user *types.User, remains int64, category string) error
 		Decrease(ctx context.Context, user *types.User, value int64, category string) (bool, error)
+		Increase(ctx context.Context, user *types.User, value int64, category string) error
 		Unset(ctx context.Context, user *types.User, category string) error
 		List(ctx context.Context, user *types.User) (map[string]types.LimitItem, bool, error)
+		Notify(ctx context.Context, user *types.User, threshold int, category string) (bool, error)
+		GetAlerts(ctx context.Context, user *types.User) ([]int, bool, error)
+		SetAlerts(ctx context.Context, user *types.User, thresholds []int) error
+	}
+
+	MerchantRuleStorage interface {
+		List(ctx context.Context, user *types.User) ([]types.MerchantRule, error)
+		Set(ctx context.Context, user *types.User, rule types.MerchantRule) error
+		Delete(ctx context.Context, user *types.User, pattern string) (bool, error)
+	}
+
+	RecurringExpenseStorage interface {
+		Add(ctx context.Context, user *types.User, item types.RecurringExpense) (int64, error)
+		List(ctx context.Context, user *types.User) ([]types.RecurringExpense, error)
+		Get(ctx context.Context, user *types.User, id int64) (types.RecurringExpense, bool, error)
+		// Update stores the progress of the schedule and the pause of the recurring expense.
+		Update(ctx context.Context, user *types.User, item types.RecurringExpense) error
+		// LockDue returns a recurring expense (along with its owner) which is not paused and which next occurrence
+		// is due by the date, skipping the excluded ones. It stays locked till the end of the transaction, so that
+		// concurrent transactions skip it.
+		LockDue(ctx context.Context, date time.Time, exclude []int64) (*types.User, types.RecurringExpense, bool, error)
+	}
+
+	OutboxStorage interface {
+		Add(ctx context.Context, event types.OutboxEvent) error
+		// LockPending returns up to limit oldest events. They stay locked till the end of the transaction,
+		// so that concurrent transactions skip them.
+		LockPending(ctx context.Context, limit int) ([]types.OutboxEvent, error)
+		// Delete removes the events once they're published.
+		Delete(ctx context.Context, ids []int64) error
+	}
+
+	// UnitOfWork runs fn so that all the changes made through the storages with the
+	// context passed to fn are either committed together or rolled back on error.
+	UnitOfWork interface {
+		Do(ctx context.Context, fn func(ctx context.Context) error) error
+		// AfterCommit runs fn once the transaction of the context is committed, it's never run when
+		// the transaction is rolled back. Outside of transactions fn is run right away.
+		AfterCommit(ctx context.Context, fn func(ctx context.Context))
 	}
 
 	CurrencyStorage interface {
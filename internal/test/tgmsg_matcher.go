@@ -15,6 +15,10 @@ type MessageKeyboardContainsMatcher struct {
 	s string
 }
 
+type MessageKeyboardDataMatcher struct {
+	s string
+}
+
 func MessageTextContains(s string) MessageTextContainsMatcher {
 	return MessageTextContainsMatcher{s}
 }
@@ -23,6 +27,11 @@ func MessageKeyboardContains(s string) MessageKeyboardContainsMatcher {
 	return MessageKeyboardContainsMatcher{s}
 }
 
+// MessageKeyboardData matches messages having the button which sends the callback data.
+func MessageKeyboardData(s string) MessageKeyboardDataMatcher {
+	return MessageKeyboardDataMatcher{s}
+}
+
 func (m MessageTextContainsMatcher) Matches(x interface{}) bool {
 	msg, ok := x.(tgbotapi.MessageConfig)
 	if !ok {
@@ -55,6 +64,28 @@ func (m MessageKeyboardContainsMatcher) Matches(x interface{}) bool {
 	return false
 }
 
+func (m MessageKeyboardDataMatcher) Matches(x interface{}) bool {
+	msg, ok := x.(tgbotapi.MessageConfig)
+	if !ok {
+		return false
+	}
+
+	keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if !ok {
+		return false
+	}
+
+	for _, bb := range keyboard.InlineKeyboard {
+		for _, b := range bb {
+			if b.CallbackData != nil && *b.CallbackData == m.s {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (m MessageTextContainsMatcher) String() string {
 	return fmt.Sprintf("contains %v (%T)", m.s, m.s)
 }
@@ -62,3 +93,7 @@ func (m MessageTextContainsMatcher) String() string {
 func (m MessageKeyboardContainsMatcher) String() string {
 	return fmt.Sprintf("contains %v (%T)", m.s, m.s)
 }
+
+func (m MessageKeyboardDataMatcher) String() string {
+	return fmt.Sprintf("has button with data %v (%T)", m.s, m.s)
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap/zapcore"
@@ -14,27 +15,477 @@ func (u *User) String() string {
 }
 
 type ExpenseItem struct {
+	ID       int64
 	Date     time.Time
 	Amount   int64
 	Currency string
+	// Account is the account the money has been taken from or put into, 0 if none.
+	Account int64
+	// Note and Tags annotate expenses, the tags are kept lowercased without the leading #.
+	Note string
+	Tags []string
 }
 
-type Report struct {
+type Expense struct {
+	ExpenseItem
+	Category string
+}
+
+// ExpenseFilter selects expenses matching all of its non-zero conditions.
+type ExpenseFilter struct {
+	// Query words are looked for in categories, notes and tags.
+	Query string
+	From  time.Time
+	// To is the exclusive upper bound of the dates.
+	To time.Time
+	// Min and Max bound the amount in the expense currency.
+	Min int64
+	Max int64
+}
+
+// Attachment is a file attached to the expense, e.g. a photo of the receipt; the file itself
+// is kept in the blob storage under the key.
+type Attachment struct {
+	ExpenseID int64
+	Key       string
+	Size      int64
+	Created   time.Time
+}
+
+// Category is a category of the user expenses along with the aliases standing for it.
+type Category struct {
+	Name    string
+	Aliases []string
+}
+
+// NormalizeCategory lowercases the category and squeezes spaces within the segments of its path,
+// so that "Transport / Taxi " becomes "transport/taxi"; empty segments are dropped.
+func NormalizeCategory(category string) string {
+	segments := strings.Split(strings.ToLower(category), "/")
+
+	path := segments[:0]
+	for _, segment := range segments {
+		if segment = strings.Join(strings.Fields(segment), " "); segment != "" {
+			path = append(path, segment)
+		}
+	}
+
+	return strings.Join(path, "/")
+}
+
+// TopCategory returns the top-level category of the path, e.g. "transport" of "transport/taxi".
+func TopCategory(category string) string {
+	top, _, _ := strings.Cut(category, "/")
+	return top
+}
+
+// RollUpCategories sums up the amounts of subcategories under their top-level categories.
+func RollUpCategories(data map[string]int64) map[string]int64 {
+	if data == nil {
+		return nil
+	}
+
+	result := make(map[string]int64, len(data))
+	for category, amount := range data {
+		result[TopCategory(category)] += amount
+	}
+
+	return result
+}
+
+// Income is money received, it's kept apart from expenses and doesn't affect limits.
+type Income struct {
+	ExpenseItem
+	Category string
+}
+
+// Account is a place the money is kept in, e.g. a card or cash, in its own currency.
+type Account struct {
+	ID       int64
+	Name     string
+	Currency string
+	// Opening is the balance of the account at the moment it has been added.
+	Opening int64
+}
+
+// Transfer moves money between accounts of the user, Received is the amount credited
+// in the currency of the target account.
+type Transfer struct {
+	Date     time.Time
+	From     int64
+	To       int64
+	Amount   int64
+	Received int64
+}
+
+// AccountTotal is a sum of the account movements of a day in a currency, expenses and outgoing
+// transfers are negative.
+type AccountTotal struct {
+	Account  int64
+	Date     time.Time
+	Currency string
+	Amount   int64
+}
+
+// AccountBalance is the balance of the account in its currency and converted into another one.
+type AccountBalance struct {
+	Account
+	Balance   int64
+	Converted int64
+}
+
+// Goal is the amount the user saves up for, the deadline is optional.
+type Goal struct {
+	ID       int64
+	Name     string
+	Target   int64
+	Currency string
+	Deadline time.Time
+}
+
+// GoalContribution is money put aside for the goal (or taken back when negative).
+type GoalContribution struct {
+	Goal     int64
+	Date     time.Time
+	Amount   int64
+	Currency string
+}
+
+// GoalTotal is a sum of the contributions to the goal in a currency.
+type GoalTotal struct {
+	Goal     int64
+	Currency string
+	Amount   int64
+}
+
+// GoalProgress is the amount saved up for the goal in its currency and converted into another one,
+// Monthly is the saving (in the goal currency) required every month to reach the goal by the deadline.
+type GoalProgress struct {
+	Goal
+	Saved     int64
+	Converted int64
+	Monthly   int64
+}
+
+type GroupRole string
+
+const (
+	RoleOwner  GroupRole = "owner"
+	RoleMember GroupRole = "member"
+)
+
+// Group is a household sharing limits and reports, every member still adds expenses on their own.
+type Group struct {
+	ID int64
+	// Code is the invitation code to join the group.
+	Code    string
+	Members []GroupMember
+}
+
+type GroupMember struct {
+	User *User
+	// Name is the member's Telegram username (or first name) shown in reports.
+	Name string
+	Role GroupRole
+}
+
+// Owner returns the owner of the group, whose limits are shared by the members.
+func (g Group) Owner() *User {
+	for _, member := range g.Members {
+		if member.Role == RoleOwner {
+			return member.User
+		}
+	}
+
+	return nil
+}
+
+// Users returns the users who are the members of the group.
+func (g Group) Users() []*User {
+	users := make([]*User, 0, len(g.Members))
+	for _, member := range g.Members {
+		users = append(users, member.User)
+	}
+
+	return users
+}
+
+// Member returns the member of the group who is the user.
+func (g Group) Member(user *User) (GroupMember, bool) {
+	for _, member := range g.Members {
+		if *member.User == *user {
+			return member, true
+		}
+	}
+
+	return GroupMember{}, false
+}
+
+// MemberByName returns the member of the group with the name, the case and the leading @ are ignored.
+func (g Group) MemberByName(name string) (GroupMember, bool) {
+	name = strings.TrimPrefix(name, "@")
+	for _, member := range g.Members {
+		if strings.EqualFold(strings.TrimPrefix(member.Name, "@"), name) {
+			return member, true
+		}
+	}
+
+	return GroupMember{}, false
+}
+
+// Debt is the share of a split expense which the debtor owes the creditor who paid for it.
+type Debt struct {
+	Date time.Time
+	// Creditor and Debtor are group members, their names are kept as they were at the time of the split.
+	Creditor     *User
+	CreditorName string
+	Debtor       *User
+	DebtorName   string
+	Amount       int64
+	Currency     string
+	Category     string
+}
+
+// DebtBalance is the net amount of open debts between the user and the counterparty in the currency,
+// it's positive when the counterparty owes the user and negative otherwise.
+type DebtBalance struct {
+	Counterparty *User
+	Name         string
+	Currency     string
+	Amount       int64
+}
+
+// ImportedExpense is an expense imported from a bank statement, Hash identifies the bank
+// transaction so that it is imported only once.
+type ImportedExpense struct {
+	Expense
+	Hash string
+}
+
+// Receipt identifies a fiscal receipt by the number of the fiscal drive, the number of the fiscal document and the fiscal sign.
+type Receipt struct {
+	Drive    string
+	Document string
+	Sign     string
+}
+
+// Key is the import hash of the expense made from the receipt.
+func (r Receipt) Key() string {
+	return "fns:" + r.Drive + ":" + r.Document + ":" + r.Sign
+}
+
+// MerchantRule assigns the category to imported expenses with descriptions containing the pattern.
+type MerchantRule struct {
+	Pattern  string
+	Category string
+}
+
+// ExpenseTotal is a sum of the expenses (or incomes) made on the date in the category and currency.
+type ExpenseTotal struct {
+	Date     time.Time
+	Category string
+	Currency string
+	Amount   int64
+}
+
+// DateRange is a range of dates [From, To), it is empty when both bounds are zero.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (r DateRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains reports whether the date is within the range, the zero To bound means the range is unbounded.
+func (r DateRange) Contains(date time.Time) bool {
+	return !date.Before(r.From) && (r.To.IsZero() || date.Before(r.To))
+}
+
+type ReportGrouping string
+
+const (
+	GroupingNone  ReportGrouping = ""
+	GroupingDay   ReportGrouping = "day"
+	GroupingWeek  ReportGrouping = "week"
+	GroupingMonth ReportGrouping = "month"
+)
+
+// ReportBucket holds amounts per category spent and received within the time bucket beginning at Start.
+type ReportBucket struct {
+	Start  time.Time
+	Data   map[string]int64
+	Income map[string]int64
+}
+
+type ReportData struct {
 	Data    map[string]int64
+	Income  map[string]int64
+	Buckets []ReportBucket
+	// Previous holds amounts per category spent within the range the report is compared with.
+	Previous map[string]int64
+	// PreviousIncome holds amounts per category received within the range the report is compared with.
+	PreviousIncome map[string]int64
+	// Members holds amounts spent by each member of the user's group, empty outside of groups.
+	Members []MemberTotal
+}
+
+type MemberTotal struct {
+	Name   string
+	Amount int64
+}
+
+type Report struct {
+	ReportData
 	Success bool
 	Error   string
 }
 
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+type LimitPeriodKind string
+
+const (
+	PeriodDay    LimitPeriodKind = "day"
+	PeriodWeek   LimitPeriodKind = "week"
+	PeriodMonth  LimitPeriodKind = "month"
+	PeriodCustom LimitPeriodKind = "custom"
+)
+
+type LimitPeriod struct {
+	Kind      LimitPeriodKind
+	Days      int
+	CarryOver bool
+}
+
 type LimitItem struct {
 	Total    int64
 	Remains  int64
+	Carried  int64 // unspent amount carried over into the current period
 	Currency string
+	Period   LimitPeriod
+	Anchor   time.Time
+	Notified int // the highest usage threshold (percent) alerted in the current period
+	// Overspent is the part of the charged expenses not covered by the remains, it is paid back
+	// first when the expenses are refunded.
+	Overspent int64
 }
 
 func (l LimitItem) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddInt64("total", l.Total)
 	enc.AddInt64("remains", l.Remains)
+	enc.AddInt64("carried", l.Carried)
 	enc.AddString("currency", l.Currency)
+	enc.AddString("period", string(l.Period.Kind))
+	enc.AddInt("period_days", l.Period.Days)
+	enc.AddBool("carry_over", l.Period.CarryOver)
+	enc.AddTime("anchor", l.Anchor)
+	enc.AddInt("notified", l.Notified)
+	enc.AddInt64("overspent", l.Overspent)
 
 	return nil
 }
+
+type RecurrenceFrequency string
+
+const (
+	FrequencyDaily   RecurrenceFrequency = "daily"
+	FrequencyWeekly  RecurrenceFrequency = "weekly"
+	FrequencyMonthly RecurrenceFrequency = "monthly"
+	FrequencyYearly  RecurrenceFrequency = "yearly"
+)
+
+// Recurrence is a schedule of occurrences every Interval days, weeks, months or years starting at Start,
+// like the RRULE of iCalendar with the FREQ and INTERVAL parts.
+type Recurrence struct {
+	Frequency RecurrenceFrequency
+	Interval  int
+	Start     time.Time
+}
+
+// Valid reports whether the recurrence has a known frequency and a positive interval,
+// otherwise its occurrences never move forward.
+func (r Recurrence) Valid() bool {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+		return r.Interval > 0
+	}
+
+	return false
+}
+
+// Occurrence returns the date of the n-th occurrence counting from 0. Monthly and yearly occurrences
+// falling on days missing in the month are moved to its last day.
+func (r Recurrence) Occurrence(n int) time.Time {
+	step := n * r.Interval
+
+	var years, months int
+	switch r.Frequency {
+	case FrequencyDaily:
+		return r.Start.AddDate(0, 0, step)
+
+	case FrequencyWeekly:
+		return r.Start.AddDate(0, 0, 7*step)
+
+	case FrequencyMonthly:
+		months = step
+
+	case FrequencyYearly:
+		years = step
+	}
+
+	first := time.Date(r.Start.Year()+years, r.Start.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1); r.Start.Day() > last.Day() {
+		return last
+	}
+
+	return first.AddDate(0, 0, r.Start.Day()-1)
+}
+
+// RecurringExpense is an expense added on every occurrence of the recurrence.
+type RecurringExpense struct {
+	ID         int64
+	Recurrence Recurrence
+	Amount     int64
+	Currency   string
+	Category   string
+	Count      int       // occurrences passed, either added or skipped while paused
+	Next       time.Time // the date of the next occurrence
+	Paused     bool
+}
+
+// OutboxEvent is a domain event stored along with the changes it describes.
+type OutboxEvent struct {
+	ID      int64
+	Kind    string
+	Payload []byte
+}
+
+type OperationKind string
+
+const (
+	OperationAddExpense  OperationKind = "add_expense"
+	OperationSetLimit    OperationKind = "set_limit"
+	OperationSetCurrency OperationKind = "set_currency"
+)
+
+// Operation is the change made by the user along with the state needed to revert it.
+type Operation struct {
+	ID      int64
+	Kind    OperationKind
+	Created time.Time
+	// ExpenseID is the added expense and Debts are the debts of the members it's split with.
+	ExpenseID int64
+	Debts     []Debt
+	// Limit is the limit of the category before the change, the zero one stands for no limit.
+	Category string
+	Limit    LimitItem
+	// Currency is the user currency before the change.
+	Currency string
+}
@@ -0,0 +1,179 @@
+//go:build unit
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Recurrence_Occurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		n          int
+		want       time.Time
+	}{
+		{
+			name:       "first",
+			recurrence: Recurrence{Frequency: FrequencyMonthly, Interval: 1, Start: time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)},
+			n:          0,
+			want:       time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "every 3 days",
+			recurrence: Recurrence{Frequency: FrequencyDaily, Interval: 3, Start: time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)},
+			n:          10,
+			want:       time.Date(2022, 12, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "every 2 weeks",
+			recurrence: Recurrence{Frequency: FrequencyWeekly, Interval: 2, Start: time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)},
+			n:          2,
+			want:       time.Date(2022, 12, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly across the year",
+			recurrence: Recurrence{Frequency: FrequencyMonthly, Interval: 1, Start: time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)},
+			n:          3,
+			want:       time.Date(2023, 2, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly on the missing day",
+			recurrence: Recurrence{Frequency: FrequencyMonthly, Interval: 1, Start: time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)},
+			n:          1,
+			want:       time.Date(2022, 11, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly after the missing day",
+			recurrence: Recurrence{Frequency: FrequencyMonthly, Interval: 1, Start: time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)},
+			n:          2,
+			want:       time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "yearly on the leap day",
+			recurrence: Recurrence{Frequency: FrequencyYearly, Interval: 1, Start: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+			n:          1,
+			want:       time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got := tt.recurrence.Occurrence(tt.n)
+
+			// ASSERT
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Recurrence_Valid(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		want       bool
+	}{
+		{
+			name:       "valid",
+			recurrence: Recurrence{Frequency: FrequencyWeekly, Interval: 2},
+			want:       true,
+		},
+		{
+			name:       "zero interval",
+			recurrence: Recurrence{Frequency: FrequencyDaily},
+		},
+		{
+			name:       "negative interval",
+			recurrence: Recurrence{Frequency: FrequencyMonthly, Interval: -1},
+		},
+		{
+			name:       "unknown frequency",
+			recurrence: Recurrence{Frequency: "hourly", Interval: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got := tt.recurrence.Valid()
+
+			// ASSERT
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Group_MemberByName(t *testing.T) {
+	alice, bob := User(1), User(2)
+	group := Group{Members: []GroupMember{
+		{User: &alice, Name: "@Alice", Role: RoleOwner},
+		{User: &bob, Name: "Bob", Role: RoleMember},
+	}}
+
+	tests := []struct {
+		name  string
+		input string
+		want  *User
+	}{
+		{name: "username", input: "@alice", want: &alice},
+		{name: "username without @", input: "ALICE", want: &alice},
+		{name: "first name", input: "bob", want: &bob},
+		{name: "first name with @", input: "@Bob", want: &bob},
+		{name: "unknown", input: "@carol"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			member, found := group.MemberByName(tt.input)
+
+			// ASSERT
+			assert.Equal(t, tt.want != nil, found)
+			assert.Equal(t, tt.want, member.User)
+		})
+	}
+}
+
+func Test_NormalizeCategory(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "case", input: "Taxi", want: "taxi"},
+		{name: "spaces", input: "  fast   food ", want: "fast food"},
+		{name: "path", input: "Transport / Taxi ", want: "transport/taxi"},
+		{name: "empty segments", input: "/transport//taxi/", want: "transport/taxi"},
+		{name: "cyrillic", input: "Такси", want: "такси"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			got := NormalizeCategory(tt.input)
+
+			// ASSERT
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_RollUpCategories(t *testing.T) {
+	// ACT
+	got := RollUpCategories(map[string]int64{
+		"transport":         10000,
+		"transport/taxi":    20000,
+		"transport/bus/day": 30000,
+		"coffee":            40000,
+	})
+
+	// ASSERT
+	assert.Equal(t, map[string]int64{
+		"transport": 60000,
+		"coffee":    40000,
+	}, got)
+}
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table limits
+  add column period      text    not null default 'month',
+  add column period_days int     not null default 0,
+  add column carry_over  boolean not null default false,
+  add column anchor      date    not null default date_trunc('month', current_date),
+  add column carried     bigint  not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table limits
+  drop column carried,
+  drop column anchor,
+  drop column carry_over,
+  drop column period_days,
+  drop column period;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+create table outbox
+(
+  id         bigserial primary key,
+  kind       text        not null,
+  payload    jsonb       not null,
+  created_at timestamptz not null default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table outbox;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table limits
+  add column notified int not null default 0;
+
+create table limit_alerts
+(
+  user_id    int primary key,
+  thresholds int[] not null,
+
+  foreign key (user_id) references users
+    on delete cascade
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table limit_alerts;
+
+alter table limits
+  drop column notified;
+-- +goose StatementEnd
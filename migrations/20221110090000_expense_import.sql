@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table expenses
+  add column import_hash text;
+
+create unique index if not exists idx_expenses_user_import_hash on expenses (user_id, import_hash);
+
+create table merchant_rules
+(
+  user_id  int,
+  pattern  text not null,
+  category text not null,
+
+  primary key (user_id, pattern),
+  foreign key (user_id) references users
+    on delete cascade
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table merchant_rules;
+
+drop index idx_expenses_user_import_hash;
+
+alter table expenses
+  drop column import_hash;
+-- +goose StatementEnd
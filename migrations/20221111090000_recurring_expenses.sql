@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+create table recurring_expenses
+(
+  id            bigserial,
+  user_id       int        not null,
+  amount        bigint     not null,
+  currency_code varchar(3) not null,
+  category      text       not null,
+  frequency     text       not null,
+  step          int        not null,
+  start_date    date       not null,
+  occurrences   int        not null default 0,
+  next_date     date       not null,
+  paused        boolean    not null default false,
+
+  primary key (id),
+  foreign key (user_id) references users
+    on delete cascade
+);
+
+create index if not exists idx_recurring_expenses_next_date on recurring_expenses (next_date) where not paused;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table recurring_expenses;
+-- +goose StatementEnd
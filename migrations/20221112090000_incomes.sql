@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+create table incomes
+(
+  id            serial,
+  user_id       int        not null,
+  date          date       not null,
+  amount        bigint     not null,
+  currency_code varchar(3) not null,
+  category      text       not null,
+
+  primary key (id),
+  foreign key (user_id) references users
+    on delete cascade
+);
+
+create index if not exists idx_incomes_date_user on incomes (date, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table incomes;
+-- +goose StatementEnd
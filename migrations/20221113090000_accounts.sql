@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+create table accounts
+(
+  id            bigserial,
+  user_id       int        not null,
+  name          text       not null,
+  currency_code varchar(3) not null,
+  opening       bigint     not null default 0,
+
+  primary key (id),
+  unique (user_id, name),
+  foreign key (user_id) references users
+    on delete cascade
+);
+
+alter table expenses
+  add column account_id bigint references accounts on delete set null;
+
+alter table incomes
+  add column account_id bigint references accounts on delete set null;
+
+create index if not exists idx_expenses_account on expenses (account_id) where account_id is not null;
+create index if not exists idx_incomes_account on incomes (account_id) where account_id is not null;
+
+create table transfers
+(
+  id              bigserial,
+  user_id         int    not null,
+  date            date   not null,
+  from_account_id bigint not null,
+  to_account_id   bigint not null,
+  amount          bigint not null,
+  received        bigint not null,
+
+  primary key (id),
+  foreign key (user_id) references users
+    on delete cascade,
+  foreign key (from_account_id) references accounts
+    on delete cascade,
+  foreign key (to_account_id) references accounts
+    on delete cascade
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table transfers;
+
+alter table incomes
+  drop column account_id;
+
+alter table expenses
+  drop column account_id;
+
+drop table accounts;
+-- +goose StatementEnd
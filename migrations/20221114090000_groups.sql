@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+create table groups
+(
+  id   bigserial,
+  code text not null,
+
+  primary key (id),
+  unique (code)
+);
+
+create table group_members
+(
+  user_id  int    not null,
+  group_id bigint not null,
+  name     text   not null,
+  role     text   not null,
+
+  primary key (user_id),
+  foreign key (user_id) references users
+    on delete cascade,
+  foreign key (group_id) references groups
+    on delete cascade
+);
+
+create index if not exists idx_group_members_group on group_members (group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table group_members;
+drop table groups;
+-- +goose StatementEnd
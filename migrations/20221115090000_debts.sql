@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+create table debts
+(
+  id            bigserial,
+  date          date       not null,
+  creditor_id   int        not null,
+  creditor_name text       not null,
+  debtor_id     int        not null,
+  debtor_name   text       not null,
+  amount        bigint     not null,
+  currency_code varchar(3) not null,
+  category      text       not null,
+  settled_at    timestamptz,
+
+  primary key (id),
+  foreign key (creditor_id) references users
+    on delete cascade,
+  foreign key (debtor_id) references users
+    on delete cascade
+);
+
+create index if not exists idx_debts_creditor on debts (creditor_id) where settled_at is null;
+create index if not exists idx_debts_debtor on debts (debtor_id) where settled_at is null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table debts;
+-- +goose StatementEnd
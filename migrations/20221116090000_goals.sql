@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+create table goals
+(
+  id            bigserial,
+  user_id       int        not null,
+  name          text       not null,
+  target        bigint     not null,
+  currency_code varchar(3) not null,
+  deadline      date,
+
+  primary key (id),
+  unique (user_id, name),
+  foreign key (user_id) references users
+    on delete cascade
+);
+
+create table goal_contributions
+(
+  id            bigserial,
+  goal_id       bigint     not null,
+  date          date       not null,
+  amount        bigint     not null,
+  currency_code varchar(3) not null,
+
+  primary key (id),
+  foreign key (goal_id) references goals
+    on delete cascade
+);
+
+create index if not exists idx_goal_contributions_goal on goal_contributions (goal_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table goal_contributions;
+drop table goals;
+-- +goose StatementEnd
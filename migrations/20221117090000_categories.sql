@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+create table category_aliases
+(
+  user_id  int,
+  alias    text not null,
+  category text not null,
+
+  primary key (user_id, alias),
+  foreign key (user_id) references users
+    on delete cascade
+);
+
+-- categories used to be free text, so "Taxi" and "taxi " were different ones;
+-- the function mirrors types.NormalizeCategory: lowercase, squeeze spaces within
+-- the segments of the path and drop the empty ones, so "Food // Coffee" is "food/coffee"
+create function normalize_category(category text) returns text as
+$$
+select coalesce(string_agg(segment, '/' order by position), '')
+from (select btrim(regexp_replace(part, '\s+', ' ', 'g')) as segment, position
+      from unnest(string_to_array(lower(category), '/')) with ordinality as parts(part, position)) as segments
+where segment <> ''
+$$ language sql immutable;
+
+update expenses
+set category = normalize_category(category);
+
+update recurring_expenses
+set category = normalize_category(category);
+
+update merchant_rules
+set category = normalize_category(category);
+
+update debts
+set category = normalize_category(category);
+
+-- limits are keyed by the category, so of the limits that collapse into one name
+-- only the one already named that way (or any one of them) is kept, like /categories merge does
+delete from limits l
+where l.category is not null
+  and exists(select
+             from limits t
+             where t.user_id = l.user_id
+               and t.category is not null
+               and normalize_category(t.category) = normalize_category(l.category)
+               and (t.category = normalize_category(t.category), t.ctid) >
+                   (l.category = normalize_category(l.category), l.ctid));
+
+update limits
+set category = normalize_category(category)
+where category is not null;
+
+-- category_aliases is created empty above and incomes have their own free-text sources
+
+drop function normalize_category(text);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- the original spelling of the categories isn't kept, so they stay normalized
+drop table category_aliases;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table expenses
+  add column note text   not null default '',
+  add column tags text[] not null default '{}';
+
+create index if not exists idx_expenses_search on expenses
+  using gin (to_tsvector('simple', replace(category, '/', ' ') || ' ' || note));
+
+create index if not exists idx_expenses_tags on expenses using gin (tags);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index idx_expenses_tags;
+drop index idx_expenses_search;
+
+alter table expenses
+  drop column tags,
+  drop column note;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the attachments of deleted expenses are unlinked rather than deleted,
+-- so that their files are removed from the blob storage by the cleanup
+create table expense_attachments
+(
+  id         bigserial,
+  user_id    int         not null,
+  expense_id int,
+  blob_key   text        not null,
+  size       bigint      not null,
+  created_at timestamptz not null default now(),
+
+  primary key (id),
+  unique (expense_id),
+  foreign key (user_id) references users
+    on delete cascade,
+  foreign key (expense_id) references expenses
+    on delete set null
+);
+
+create index if not exists idx_expense_attachments_created on expense_attachments (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table expense_attachments;
+-- +goose StatementEnd
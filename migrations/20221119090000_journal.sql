@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the operations are kept for a short while to be undone, the state they revert to goes in the payload
+create table journal
+(
+  id         bigserial,
+  user_id    int         not null,
+  kind       text        not null,
+  payload    jsonb       not null,
+  created_at timestamptz not null default now(),
+
+  primary key (id),
+  foreign key (user_id) references users
+    on delete cascade
+);
+
+create index if not exists idx_journal_user_created on journal (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table journal;
+-- +goose StatementEnd
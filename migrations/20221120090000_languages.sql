@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- null language means the one of the Telegram client
+alter table tg_users
+  add column lang text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table tg_users
+  drop column lang;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table limits
+  add column overspent bigint not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table limits
+  drop column overspent;
+-- +goose StatementEnd